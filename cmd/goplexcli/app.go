@@ -0,0 +1,318 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/cache"
+	"github.com/joshkerr/goplexcli/internal/config"
+	"github.com/joshkerr/goplexcli/internal/download"
+	"github.com/joshkerr/goplexcli/internal/favorites"
+	"github.com/joshkerr/goplexcli/internal/logging"
+	"github.com/joshkerr/goplexcli/internal/player"
+	"github.com/joshkerr/goplexcli/internal/plex"
+	"github.com/joshkerr/goplexcli/internal/storage"
+	"github.com/joshkerr/goplexcli/internal/termimg"
+	"github.com/joshkerr/goplexcli/internal/ui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// needsAnnotation is the cobra annotation a command uses to declare what the
+// root's PersistentPreRunE must prepare before it runs. Set it with
+// requireConfig, requireLogin or requireCache rather than by hand.
+const needsAnnotation = "goplexcli/needs"
+
+// Levels for needsAnnotation, each including the ones before it.
+const (
+	needsConfig = "config" // config loaded (may be empty)
+	needsLogin  = "login"  // config loaded and valid
+	needsCache  = "cache"  // valid config and the media cache loaded
+)
+
+// App is the per-invocation state the root command prepares once and hands
+// to every command through its context, so run functions don't each repeat
+// the load/validate boilerplate.
+type App struct {
+	// Config is set for commands that declared needsConfig or higher.
+	Config *config.Config
+	// Cache is set for commands that declared needsCache. It may be empty;
+	// commands decide whether that is an error. Others that only sometimes
+	// need it load it through MediaCache.
+	Cache *cache.Cache
+	// Favorites is the one favorites store for the invocation, so its lock
+	// serializes every in-process reader and writer.
+	Favorites *favorites.Store
+
+	signalCtx  context.Context
+	stopSignal context.CancelFunc
+}
+
+type appKey struct{}
+
+// appFrom returns the App prepared for cmd. Commands that run without going
+// through the root's PersistentPreRunE (completion functions) get an empty
+// App.
+func appFrom(cmd *cobra.Command) *App {
+	if ctx := cmd.Context(); ctx != nil {
+		if a, ok := ctx.Value(appKey{}).(*App); ok {
+			return a
+		}
+	}
+	return &App{}
+}
+
+// requireConfig marks commands as needing the config loaded.
+func requireConfig(cmds ...*cobra.Command) { setNeeds(needsConfig, cmds) }
+
+// requireLogin marks commands as needing a valid config, i.e. a prior login.
+func requireLogin(cmds ...*cobra.Command) { setNeeds(needsLogin, cmds) }
+
+// requireCache marks commands as needing a valid config and the media cache.
+func requireCache(cmds ...*cobra.Command) { setNeeds(needsCache, cmds) }
+
+func setNeeds(level string, cmds []*cobra.Command) {
+	for _, cmd := range cmds {
+		if cmd.Annotations == nil {
+			cmd.Annotations = map[string]string{}
+		}
+		cmd.Annotations[needsAnnotation] = level
+	}
+}
+
+// initLogging sets up logging from --verbose and --log-file, and Plex
+// request tracing from --trace-http. With a log file, the Plex client's API
+// warnings and traced requests go there too instead of to stderr. Files are
+// appended to and stay open for the life of the process.
+func initLogging() error {
+	opts := []logging.Option{logging.WithVerbose(verbose)}
+	if logFile != "" {
+		f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		opts = append(opts, logging.WithOutput(f))
+	}
+	logging.Init(opts...)
+	if logFile != "" {
+		plex.SetAPILogger(slog.NewLogLogger(logging.Logger().Handler(), slog.LevelWarn))
+	}
+
+	if traceBodiesFile != "" && !traceHTTP {
+		return fmt.Errorf("--trace-http-bodies requires --trace-http")
+	}
+	if traceHTTP {
+		var bodies io.Writer
+		if traceBodiesFile != "" {
+			f, err := os.OpenFile(traceBodiesFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+			if err != nil {
+				return fmt.Errorf("failed to open trace file: %w", err)
+			}
+			bodies = f
+		}
+		plex.SetHTTPTrace(true, bodies)
+	}
+	return nil
+}
+
+// prepareApp is the root command's PersistentPreRunE. It initializes logging,
+// loads what the executing command declared it needs, and injects the App
+// into the command's context.
+func prepareApp(cmd *cobra.Command, args []string) error {
+	if err := initLogging(); err != nil {
+		return err
+	}
+	ui.ConfigureTerminal(noColor)
+	if err := config.SetProfile(profileName); err != nil {
+		return err
+	}
+
+	a := &App{Favorites: favorites.NewStore()}
+	switch level := cmd.Annotations[needsAnnotation]; level {
+	case "":
+	case needsConfig, needsLogin, needsCache:
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		a.Config = cfg
+		if err := cfg.ApplyTimezone(); err != nil {
+			return err
+		}
+		plex.ConfigureHTTP(plex.HTTPOptions{
+			Timeout: time.Duration(cfg.HTTPTimeout) * time.Second,
+			Retries: cfg.HTTPRetries,
+		})
+		clientID, err := config.ClientIdentifier()
+		if err != nil {
+			logging.Warn("failed to load client identifier", "error", err)
+		}
+		plex.SetDevice(plex.Device{ClientIdentifier: clientID, Name: cfg.DeviceName, Version: version})
+		plex.SetLibraryFilter(plex.LibraryFilter{Include: cfg.IncludeLibraries, Exclude: cfg.ExcludeLibraries})
+		player.SetCustomPlayers(customPlayers(cfg))
+		player.SetWindow(player.Window{Fullscreen: cfg.Fullscreen, OnTop: cfg.AlwaysOnTop, Volume: cfg.Volume, Profile: cfg.MPVProfile})
+		cache.SetFormat(cfg.CacheFormat)
+		state, err := cfg.StateDriver()
+		if err != nil {
+			return fmt.Errorf("failed to set up state storage: %w", err)
+		}
+		storage.SetBackend(state)
+		if err := ui.SetKeybindings(cfg.Keybindings); err != nil {
+			return fmt.Errorf("invalid keybindings: %w", err)
+		}
+		if err := ui.SetTheme(cfg.Theme, cfg.Themes); err != nil {
+			return fmt.Errorf("invalid theme: %w", err)
+		}
+		// The preview runs with our environment, so it draws posters
+		// exactly when this detects a way to.
+		images, _ := termimg.ParseProtocol(cfg.PreviewImages, os.Getenv)
+		ui.SetPreviewPosters(images != termimg.None)
+		applyStyles()
+
+		if level == needsConfig {
+			break
+		}
+		// First run: walk through setup instead of sending the user off to
+		// run login and cache reindex by hand.
+		if cfg.Validate() != nil && !config.Exists() && term.IsTerminal(int(os.Stdin.Fd())) {
+			fmt.Println(infoStyle.Render("No configuration found; starting setup."))
+			if err := setupWizard(cfg); err != nil {
+				return err
+			}
+		}
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("invalid config: %w. Please run 'goplexcli setup' first", err)
+		}
+		// Checked here rather than in Config.Validate, which can't import
+		// download (download imports config). Either way a typo fails before
+		// any download starts.
+		if err := download.ValidateTemplate(cfg.MovieTemplate); err != nil {
+			return fmt.Errorf("invalid config: movie_template: %w", err)
+		}
+		if err := download.ValidateTemplate(cfg.EpisodeTemplate); err != nil {
+			return fmt.Errorf("invalid config: episode_template: %w", err)
+		}
+
+		if level == needsLogin {
+			break
+		}
+		if _, err := a.MediaCache(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("command %q has unknown requirement %q", cmd.CommandPath(), level)
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cmd.SetContext(context.WithValue(ctx, appKey{}, a))
+	return nil
+}
+
+// MediaCache returns the App's media cache, loading it on first use.
+func (a *App) MediaCache() (*cache.Cache, error) {
+	if a.Cache == nil {
+		mediaCache, err := cache.Load()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load cache: %w", err)
+		}
+		a.Cache = mediaCache
+	}
+	return a.Cache, nil
+}
+
+// ReloadCache rereads the media cache from disk, for code about to change
+// it after a long wait (playback) during which another goplexcli may have
+// saved it.
+func (a *App) ReloadCache() (*cache.Cache, error) {
+	a.Cache = nil
+	return a.MediaCache()
+}
+
+// SignalContext returns a context cancelled on Ctrl-C or SIGTERM, for
+// long-running commands that want to stop cleanly. The handler is installed
+// on first use only: until then, Ctrl-C ends the process through
+// exitOnSignal, which is what interactive commands want.
+func (a *App) SignalContext() context.Context {
+	if a.signalCtx == nil {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		release := ownSignals()
+		a.signalCtx, a.stopSignal = ctx, func() {
+			stop()
+			release()
+		}
+	}
+	return a.signalCtx
+}
+
+// signalOwners counts the code currently handling Ctrl-C and SIGTERM itself
+// (see ownSignals). While it is zero, exitOnSignal ends the process.
+var signalOwners atomic.Int32
+
+// ownSignals tells exitOnSignal to leave Ctrl-C and SIGTERM to the caller,
+// which must have its own signal.Notify registered, until the returned
+// release func is called.
+func ownSignals() (release func()) {
+	signalOwners.Add(1)
+	var once sync.Once
+	return func() { once.Do(func() { signalOwners.Add(-1) }) }
+}
+
+// exitOnSignal replaces the default handling of Ctrl-C and SIGTERM, which
+// kills the process outright, with one that first removes the preview temp
+// files. Signals that arrive while something owns them (see ownSignals) are
+// left to it.
+func exitOnSignal() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		for sig := range ch {
+			if signalOwners.Load() > 0 {
+				continue
+			}
+			ui.RemovePreviewFiles()
+			os.Exit(signalExitCode(sig))
+		}
+	}()
+}
+
+// signalExitCode is the shell's exit status for a process killed by sig:
+// 130 for Ctrl-C, 143 for SIGTERM.
+func signalExitCode(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return 1
+}
+
+// Close releases the signal handler, if one was installed.
+func (a *App) Close() {
+	if a.stopSignal != nil {
+		a.stopSignal()
+	}
+}
+
+// customPlayers converts the config's players definitions for the player
+// package.
+func customPlayers(cfg *config.Config) []player.Custom {
+	players := make([]player.Custom, 0, len(cfg.Players))
+	for _, p := range cfg.Players {
+		players = append(players, player.Custom{
+			Name:         p.Name,
+			Path:         p.Path,
+			ArgsTemplate: p.ArgsTemplate,
+			SupportsIPC:  p.SupportsIPC,
+		})
+	}
+	return players
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestPrepareApp(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("APPDATA", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+
+	plain := &cobra.Command{Use: "plain"}
+	plain.SetContext(context.Background())
+	if err := prepareApp(plain, nil); err != nil {
+		t.Fatalf("prepareApp(plain) error = %v", err)
+	}
+	if app := appFrom(plain); app.Config != nil || app.Cache != nil {
+		t.Errorf("a command without requirements should get an empty App, got %+v", app)
+	}
+
+	withConfig := &cobra.Command{Use: "config"}
+	requireConfig(withConfig)
+	if err := prepareApp(withConfig, nil); err != nil {
+		t.Fatalf("prepareApp(config) error = %v", err)
+	}
+	if appFrom(withConfig).Config == nil {
+		t.Error("requireConfig should load the config")
+	}
+
+	// A fresh config has no server, so anything needing a login must fail
+	// before the command runs.
+	withLogin := &cobra.Command{Use: "login-only"}
+	requireLogin(withLogin)
+	if err := prepareApp(withLogin, nil); err == nil {
+		t.Error("requireLogin should reject an empty config")
+	}
+
+	// Naming templates are checked with the login, so a typo fails before
+	// any download starts.
+	t.Setenv("GOPLEXCLI_PLEX_TOKEN", "token")
+	t.Setenv("GOPLEXCLI_PLEX_URL", "http://plex:32400")
+	t.Setenv("GOPLEXCLI_MOVIE_TEMPLATE", "{titel}.{ext}")
+	if err := prepareApp(withLogin, nil); err == nil || !strings.Contains(err.Error(), "movie_template") {
+		t.Errorf("prepareApp with a bad movie_template error = %v, want a movie_template error", err)
+	}
+
+	bogus := &cobra.Command{Use: "bogus", Annotations: map[string]string{needsAnnotation: "everything"}}
+	if err := prepareApp(bogus, nil); err == nil {
+		t.Error("an unknown requirement should be reported")
+	}
+}
+
+func TestAppFromWithoutPrepare(t *testing.T) {
+	cmd := &cobra.Command{Use: "complete"}
+	cmd.SetContext(context.Background())
+	if app := appFrom(cmd); app == nil || app.Config != nil {
+		t.Errorf("appFrom without prepareApp = %+v, want an empty App", app)
+	}
+}
+
+func TestOwnSignals(t *testing.T) {
+	release := ownSignals()
+	app := &App{}
+	app.SignalContext()
+	if n := signalOwners.Load(); n != 2 {
+		t.Errorf("signalOwners = %d with two owners, want 2", n)
+	}
+	release()
+	release()
+	app.Close()
+	if n := signalOwners.Load(); n != 0 {
+		t.Errorf("signalOwners = %d after release, want 0", n)
+	}
+}
+
+func TestSignalExitCode(t *testing.T) {
+	if got := signalExitCode(os.Interrupt); got != 130 {
+		t.Errorf("signalExitCode(Interrupt) = %d, want 130", got)
+	}
+	if got := signalExitCode(syscall.SIGTERM); got != 143 {
+		t.Errorf("signalExitCode(SIGTERM) = %d, want 143", got)
+	}
+}
@@ -7,38 +7,65 @@ import _ "github.com/joshkerr/goplexcli/internal/termuxfix"
 
 import (
 	"bufio"
+	"cmp"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	_ "net/http/pprof" // registers /debug/pprof on http.DefaultServeMux for --pprof
 	"net/url"
 	"os"
 	"os/exec"
 	"os/signal"
+	"path/filepath"
 	"runtime"
+	"runtime/debug"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/joshkerr/goplexcli/internal/arr"
 	"github.com/joshkerr/goplexcli/internal/cache"
+	"github.com/joshkerr/goplexcli/internal/calendar"
 	"github.com/joshkerr/goplexcli/internal/config"
+	"github.com/joshkerr/goplexcli/internal/crash"
+	"github.com/joshkerr/goplexcli/internal/deleted"
+	"github.com/joshkerr/goplexcli/internal/discord"
 	"github.com/joshkerr/goplexcli/internal/download"
 	apperrors "github.com/joshkerr/goplexcli/internal/errors"
+	"github.com/joshkerr/goplexcli/internal/export"
 	"github.com/joshkerr/goplexcli/internal/favorites"
+	"github.com/joshkerr/goplexcli/internal/history"
 	"github.com/joshkerr/goplexcli/internal/lansync"
 	"github.com/joshkerr/goplexcli/internal/logging"
+	"github.com/joshkerr/goplexcli/internal/notify"
 	"github.com/joshkerr/goplexcli/internal/outplayer"
+	"github.com/joshkerr/goplexcli/internal/overseerr"
 	"github.com/joshkerr/goplexcli/internal/player"
 	"github.com/joshkerr/goplexcli/internal/plex"
+	"github.com/joshkerr/goplexcli/internal/posters"
 	"github.com/joshkerr/goplexcli/internal/preview"
 	"github.com/joshkerr/goplexcli/internal/progress"
+	"github.com/joshkerr/goplexcli/internal/qr"
 	"github.com/joshkerr/goplexcli/internal/queue"
 	"github.com/joshkerr/goplexcli/internal/stream"
+	"github.com/joshkerr/goplexcli/internal/termimg"
+	"github.com/joshkerr/goplexcli/internal/tmdb"
 	"github.com/joshkerr/goplexcli/internal/ui"
 	"github.com/joshkerr/goplexcli/internal/update"
+	"github.com/joshkerr/goplexcli/internal/usage"
 	"github.com/joshkerr/goplexcli/internal/webdav"
 	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/term"
 )
 
@@ -65,9 +92,184 @@ var (
 	syncPullPeer            string
 )
 
+// queueDownloadAt delays `queue download` until the next occurrence of that
+// time of day; queueDownloadWindow restricts it to a daily HH:MM-HH:MM range.
+var (
+	queueDownloadAt     string
+	queueDownloadWindow string
+)
+
+// pprofAddr, when set via the hidden --pprof flag, serves net/http/pprof on
+// that address for the life of a long-running command.
+var pprofAddr string
+
+// stats usage flags: turn local usage recording on or off, or wipe it.
+var (
+	statsEnable  bool
+	statsDisable bool
+	statsReset   bool
+)
+
+// exportOutput is the file `export m3u` writes (default: "<title>.m3u8"),
+// or the file `export` writes the catalog to (default: stdout).
+var exportOutput string
+
+// exportProxy makes `export m3u` write URLs through a stream server it runs
+// on exportPort, instead of tokenized Plex URLs.
+var (
+	exportProxy bool
+	exportPort  int
+)
+
+// exportFormat and exportType choose the catalog format and item type for
+// `export`.
+var (
+	exportFormat string
+	exportType   string
+)
+
+// playbackPreset names the playback preset (mpv audio options, e.g. "night")
+// applied when watching; set by --preset or the "Watch with Preset..." action.
+var playbackPreset string
+
+// watchChapter, when set, starts a single item at that chapter (1-based)
+// instead of offering to resume.
+var watchChapter int
+
+// audioOnly plays with mpv and no video, controlled from the terminal
+// (--audio-only).
+var audioOnly bool
+
+// screenshotDir overrides the config's screenshot_dir for this run
+// (--screenshot-dir).
+var screenshotDir string
+
+// receiveTimeout is how long `receive` searches for stream servers.
+var receiveTimeout time.Duration
+
+// servePort is the port `serve` listens on.
+var servePort int
+
+// serveQR prints a QR code for each stream `serve` publishes.
+var serveQR bool
+
+// streamToken is the access token `stream` sends to a protected stream
+// server, overriding stream_token from the config.
+var streamToken string
+
+// partyJoinHost and partyJoinFingerprint make `party join` join the host at
+// that address instead of discovering one.
+var (
+	partyJoinHost        string
+	partyJoinFingerprint string
+)
+
+// deleteAndFiles makes `delete` also remove each item's file through rclone,
+// for libraries whose files the Plex server can't delete itself.
+var deleteAndFiles bool
+
+// deletedFormat and deletedOutput control `deleted export`.
+var (
+	deletedFormat string
+	deletedOutput string
+)
+
+// watchLocal plays downloaded copies of the selected items instead of
+// streaming them; set by the "Play Local File" action.
+var watchLocal bool
+
+// downloadsCleanFiles makes `downloads clean` delete the downloaded files
+// themselves, not just forget the ones that are already gone.
+var downloadsCleanFiles bool
+
+// cacheDedupePolicy is the policy `cache dedupe` uses, overriding the
+// config's dedupe setting.
+var cacheDedupePolicy string
+
+// historySince and historyLimit filter `history` and `history replay`.
+var (
+	historySince string
+	historyLimit int
+)
+
+// sessionsInterval is how often `sessions` refreshes; sessionsStopReason is
+// shown to viewers whose stream is stopped from it.
+var (
+	sessionsInterval   time.Duration
+	sessionsStopReason string
+)
+
+// serverScanLibrary limits `server scan` to the library with this name.
+var serverScanLibrary string
+
+// infoJSON makes `info` print JSON instead of text.
+var infoJSON bool
+
+// calendarMonth ("YYYY-MM") and calendarAll control `calendar`.
+var (
+	calendarMonth string
+	calendarAll   bool
+)
+
 // searchDescriptions when true also matches against item summaries
 var searchDescriptions bool
 
+// Flags for 'download': what to download, and whether to queue it instead.
+var (
+	downloadShow     string
+	downloadSeason   int
+	downloadEpisodes string
+	downloadQueue    bool
+)
+
+// markShow and markSeason pick the episodes 'mark watched' scrobbles.
+var (
+	markShow   string
+	markSeason int
+)
+
+// browseResolution and browseHDR limit `browse` to items of that format.
+var (
+	browseResolution string
+	browseHDR        bool
+)
+
+// browseSource limits `browse` to servers the account owns ("mine") or
+// that friends share with it ("shared").
+var browseSource string
+
+// browseLibrary limits `browse` to one library section.
+var browseLibrary string
+
+// homePIN is the PIN for 'home switch' to a protected user.
+var homePIN string
+
+// remotePlayerName names the Plex player 'remote' commands control
+// (--player); remoteFromStart plays from the beginning instead of resuming.
+var (
+	remotePlayerName string
+	remoteFromStart  bool
+)
+
+// profileName selects a profile's config, cache and queue (--profile).
+var profileName string
+
+// noColor turns off colors and styling (--no-color).
+var noColor bool
+
+// verbose and logFile control diagnostic logging (--verbose, --log-file).
+var (
+	verbose bool
+	logFile string
+)
+
+// traceHTTP and traceBodiesFile trace Plex requests (--trace-http,
+// --trace-http-bodies).
+var (
+	traceHTTP       bool
+	traceBodiesFile string
+)
+
 // sort command flags
 var (
 	sortDesc        bool
@@ -77,28 +279,56 @@ var (
 	sortInteractive bool
 )
 
-var (
-	// Refined color palette for cohesive UI
-	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#C084FC")). // Purple accent
-			MarginBottom(1)
+// The CLI's message styles, drawn from the active theme by applyStyles.
+var titleStyle, successStyle, errorStyle, infoStyle, warningStyle lipgloss.Style
 
-	successStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#4ADE80")) // Green (matches logo)
+func init() { applyStyles() }
 
-	errorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F87171")). // Softer red
-			Bold(true)
+// applyStyles rebuilds the CLI's styles from the active theme (see
+// ui.SetTheme).
+func applyStyles() {
+	t := ui.CurrentTheme()
+	titleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.Accent).
+		MarginBottom(1)
+	successStyle = lipgloss.NewStyle().Foreground(t.Success)
+	errorStyle = lipgloss.NewStyle().Foreground(t.Error).Bold(true)
+	infoStyle = lipgloss.NewStyle().Foreground(t.Info)
+	warningStyle = lipgloss.NewStyle().Foreground(t.Warning)
+}
+
+// printProgress redraws the current line with a progress message. It prints
+// nothing without an interactive terminal, so logs don't fill up with
+// carriage returns and escape codes.
+func printProgress(format string, args ...any) {
+	if !ui.Interactive() {
+		return
+	}
+	fmt.Printf("\r\x1b[K"+format, args...)
+}
 
-	infoStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#60A5FA")) // Softer blue
+// progressBar renders done of total as a fixed-width text bar, e.g.
+// "[=====     ] 5/10".
+func progressBar(done, total, width int) string {
+	filled := width
+	if total > 0 {
+		filled = width * done / total
+	}
+	filled = min(max(filled, 0), width)
+	return fmt.Sprintf("[%s%s] %d/%d", strings.Repeat("=", filled), strings.Repeat(" ", width-filled), done, total)
+}
 
-	warningStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FBBF24")) // Amber
-)
+// endProgress ends a line drawn by printProgress.
+func endProgress() {
+	if ui.Interactive() {
+		fmt.Println()
+	}
+}
 
 func main() {
+	defer handlePanic()
+
 	rootCmd := &cobra.Command{
 		Use:   "goplexcli [search term]",
 		Short: "A CLI tool for browsing and streaming from your Plex server",
@@ -121,14 +351,134 @@ media-type picker offers "View Queue (N items)" → "Download All".`,
 	}
 	rootCmd.Flags().BoolVarP(&searchDescriptions, "descriptions", "d", false, "Also search item descriptions/summaries (default: title only)")
 	rootCmd.Flags().StringVar(&downloadDest, "dest", "", "Directory to download into (overrides download_dir in config; default: current directory)")
+	rootCmd.Flags().StringVar(&playbackPreset, "preset", "", "Playback preset to apply when watching (night, normalize, stereo, or one from config)")
+	rootCmd.Flags().IntVar(&watchChapter, "chapter", 0, "Start watching a single item at this chapter (see 'goplexcli chapters')")
+	rootCmd.Flags().BoolVar(&audioOnly, "audio-only", false, "Play sound only with mpv, with controls in the terminal (space, arrows, n, q)")
+	rootCmd.Flags().StringVar(&screenshotDir, "screenshot-dir", "", "Directory Ctrl+S in mpv saves stills to (overrides screenshot_dir in config)")
+	addPprofFlag(rootCmd)
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Use a separate config, cache and queue under profiles/<name>/ (default: $"+config.ProfileEnv+")")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Log debug details: HTTP requests, cache reads and writes, rclone runs and mpv IPC")
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "Append logs to this file instead of writing them to stderr")
+	rootCmd.PersistentFlags().BoolVar(&traceHTTP, "trace-http", false, "Log every Plex request: method, URL (token redacted), status, latency and size")
+	rootCmd.PersistentFlags().StringVar(&traceBodiesFile, "trace-http-bodies", "", "With --trace-http, also append response bodies (tokens redacted) to this file")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colors and styling (also set by NO_COLOR, TERM=dumb, or output that isn't a terminal)")
 
 	// Login command
 	loginCmd := &cobra.Command{
 		Use:   "login",
 		Short: "Login to your Plex account",
-		RunE:  runLogin,
+		Long: `Sign in to Plex with your username and password and pick a server.
+
+Accounts with two-factor authentication are then asked for the current code
+from their authenticator app.`,
+		RunE: runLogin,
+	}
+
+	// Setup command: guided first-run configuration.
+	setupCmd := &cobra.Command{
+		Use:   "setup",
+		Short: "Set up goplexcli step by step",
+		Long: `Walk through everything goplexcli needs in one go: sign in to Plex and
+pick a server, choose a player, map the server's folders to an rclone
+remote for downloads, check for fzf, and build the media cache.
+
+Setup starts by itself the first time you run a command that needs a
+login. Run it again at any time to change these choices.`,
+		RunE: runSetup,
 	}
 
+	// Play command: start playback of a title without browsing.
+	playCmd := &cobra.Command{
+		Use:   "play <title>",
+		Short: "Play a movie or show by title",
+		Long: `Play a movie, or the next unwatched episode of a show, straight from the
+cache without browsing. The title is matched loosely, ignoring case and
+punctuation; if it fits several titles you're asked to pick one.
+
+  goplexcli play "The Matrix"
+  goplexcli play severance`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeMediaTitles,
+		RunE:              runPlay,
+	}
+	playCmd.Flags().StringVar(&playbackPreset, "preset", "", "Playback preset to apply (night, normalize, stereo, or one from config)")
+	playCmd.Flags().IntVar(&watchChapter, "chapter", 0, "Start at this chapter (see 'goplexcli chapters')")
+	playCmd.Flags().BoolVar(&audioOnly, "audio-only", false, "Play sound only with mpv, with controls in the terminal (space, arrows, n, q)")
+	playCmd.Flags().StringVar(&screenshotDir, "screenshot-dir", "", "Directory Ctrl+S in mpv saves stills to (overrides screenshot_dir in config)")
+
+	// Download command: download a title without browsing.
+	downloadCmd := &cobra.Command{
+		Use:   "download [movie]",
+		Short: "Download a movie or episodes of a show by title",
+		Long: `Download a movie, or a whole show, season, or range of episodes, straight
+from the cache without browsing. Titles are matched as with 'play'.
+
+  goplexcli download "Heat"
+  goplexcli download --show "Severance"
+  goplexcli download --show "Severance" --season 2 --episodes 1-4,7
+
+With --queue the items are added to the download queue instead, for
+'goplexcli queue download' to fetch later.`,
+		Args:              cobra.ArbitraryArgs,
+		ValidArgsFunction: completeMediaTitles,
+		RunE:              runDownload,
+	}
+	downloadCmd.Flags().StringVar(&downloadShow, "show", "", "Show to download episodes of")
+	downloadCmd.Flags().IntVar(&downloadSeason, "season", 0, "Only download this season (0 for specials)")
+	downloadCmd.Flags().StringVar(&downloadEpisodes, "episodes", "", "Only download these episodes of --season, e.g. 1-4,7")
+	downloadCmd.Flags().BoolVar(&downloadQueue, "queue", false, "Add to the download queue instead of downloading now")
+	downloadCmd.Flags().StringVar(&downloadDest, "dest", "", "Directory to download into (overrides download_dir in config; default: current directory)")
+	downloadCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be downloaded without actually downloading")
+	_ = downloadCmd.RegisterFlagCompletionFunc("show", completeShowTitles)
+
+	// Mark command: set watched state on the server without playing.
+	markCmd := &cobra.Command{
+		Use:   "mark",
+		Short: "Mark media as watched",
+	}
+	markWatchedCmd := &cobra.Command{
+		Use:   "watched [movie]",
+		Short: "Mark a movie, show, or season as watched",
+		Long: `Mark a movie, or every episode of a show or season, as watched on its Plex
+server in one pass. Useful for syncing history after watching elsewhere.
+Titles are matched as with 'play'.
+
+  goplexcli mark watched "Heat"
+  goplexcli mark watched --show "The Wire"
+  goplexcli mark watched --show "The Wire" --season 3`,
+		Args:              cobra.ArbitraryArgs,
+		ValidArgsFunction: completeMediaTitles,
+		RunE:              runMarkWatched,
+	}
+	markWatchedCmd.Flags().StringVar(&markShow, "show", "", "Show to mark episodes of")
+	markWatchedCmd.Flags().IntVar(&markSeason, "season", 0, "Only mark this season (0 for specials)")
+	markWatchedCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be marked without changing anything")
+	_ = markWatchedCmd.RegisterFlagCompletionFunc("show", completeShowTitles)
+	markCmd.AddCommand(markWatchedCmd)
+
+	// Home command: switch between Plex Home users
+	homeCmd := &cobra.Command{
+		Use:   "home",
+		Short: "Switch between Plex Home users",
+		Long: `List the users of your Plex Home and switch to one of them. After a
+switch, goplexcli plays, tracks progress and marks things watched as that
+user, so a managed kids' account keeps its own watch history.`,
+	}
+	homeUsersCmd := &cobra.Command{
+		Use:   "users",
+		Short: "List Plex Home users",
+		Args:  cobra.NoArgs,
+		RunE:  runHomeUsers,
+	}
+	homeSwitchCmd := &cobra.Command{
+		Use:   "switch [user]",
+		Short: "Switch to a Plex Home user (pick one if not named)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runHomeSwitch,
+	}
+	homeSwitchCmd.Flags().StringVar(&homePIN, "pin", "", "PIN of a protected user (prompted for if needed)")
+	homeCmd.AddCommand(homeUsersCmd, homeSwitchCmd)
+
 	// Browse command
 	browseCmd := &cobra.Command{
 		Use:   "browse",
@@ -149,6 +499,15 @@ Downloading queued items:
 	}
 	browseCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be downloaded without actually downloading")
 	browseCmd.Flags().StringVar(&downloadDest, "dest", "", "Directory to download into (overrides download_dir in config; default: current directory)")
+	browseCmd.Flags().StringVar(&playbackPreset, "preset", "", "Playback preset to apply when watching (night, normalize, stereo, or one from config)")
+	browseCmd.Flags().IntVar(&watchChapter, "chapter", 0, "Start watching a single item at this chapter (see 'goplexcli chapters')")
+	browseCmd.Flags().BoolVar(&audioOnly, "audio-only", false, "Play sound only with mpv, with controls in the terminal (space, arrows, n, q)")
+	browseCmd.Flags().StringVar(&screenshotDir, "screenshot-dir", "", "Directory Ctrl+S in mpv saves stills to (overrides screenshot_dir in config)")
+	browseCmd.Flags().StringVar(&browseResolution, "resolution", "", "Only list items in this resolution (sd, 720, 1080, 4k)")
+	browseCmd.Flags().BoolVar(&browseHDR, "hdr", false, "Only list HDR items (HDR10, Dolby Vision, HLG)")
+	browseCmd.Flags().StringVar(&browseLibrary, "library", "", "Only list items from this library section, e.g. \"Documentaries\" (or \"Server/Documentaries\")")
+	browseCmd.Flags().StringVar(&browseSource, "source", "", "Only list items from your own servers (mine) or friends' shared servers (shared)")
+	addPprofFlag(browseCmd)
 
 	// Cache command
 	cacheCmd := &cobra.Command{
@@ -181,7 +540,43 @@ Downloading queued items:
 		RunE:  runCacheSearch,
 	}
 
-	cacheCmd.AddCommand(cacheUpdateCmd, cacheReindexCmd, cacheInfoCmd, cacheSearchCmd)
+	cachePostersCmd := &cobra.Command{
+		Use:   "posters",
+		Short: "Download posters for every cached item",
+		Long: `Download the poster of every movie and show in the cache into the
+poster cache, so the browser shows them instantly, even offline. Posters
+already cached are skipped; the cache keeps the most recently used posters
+within its size cap.`,
+		Args: cobra.NoArgs,
+		RunE: runCachePosters,
+	}
+
+	cacheDedupeCmd := &cobra.Command{
+		Use:   "dedupe",
+		Short: "Remove items that several servers share",
+		Long: `Remove items that more than one server has from the cache, keeping one
+copy of each. Copies are matched by Plex GUID. The policy decides which copy
+stays: "local" prefers a server on the local network, "quality" the best
+resolution, HDR and bitrate. The default is the config's dedupe setting, or
+"local".`,
+		Args: cobra.NoArgs,
+		RunE: runCacheDedupe,
+	}
+	cacheDedupeCmd.Flags().StringVar(&cacheDedupePolicy, "policy", "", "Which copy to keep: local or quality")
+	cacheDedupeCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be removed without changing the cache")
+
+	cacheSyncWatchedCmd := &cobra.Command{
+		Use:   "sync-watched",
+		Short: "Refresh watched state and resume positions from Plex",
+		Long: `Fetch the view count, resume position and last-viewed time of every cached
+item from its server, so browse filters, resume prompts and stats match
+what was watched elsewhere, without a full reindex. 'cache update' does this
+too.`,
+		Args: cobra.NoArgs,
+		RunE: runCacheSyncWatched,
+	}
+
+	cacheCmd.AddCommand(cacheUpdateCmd, cacheReindexCmd, cacheInfoCmd, cacheSearchCmd, cachePostersCmd, cacheDedupeCmd, cacheSyncWatchedCmd)
 
 	// Config command
 	configCmd := &cobra.Command{
@@ -196,6 +591,37 @@ Downloading queued items:
 		Short: "Discover and play streams from other devices",
 		RunE:  runStream,
 	}
+	streamCmd.Flags().StringVar(&streamToken, "token", "", "Access token for a protected stream server (default: stream_token from config)")
+
+	// Receive command: one picker over the streams of every server found.
+	receiveCmd := &cobra.Command{
+		Use:   "receive",
+		Short: "Pick a stream published anywhere on the LAN and play it",
+		Long: `Discover every goplexcli stream server on the local network, list all of
+their published streams in one picker, and play the chosen stream in the
+configured player. Handy for handing playback off between machines.`,
+		Args: cobra.NoArgs,
+		RunE: runReceive,
+	}
+	receiveCmd.Flags().StringVar(&streamToken, "token", "", "Access token for protected stream servers (default: stream_token from config)")
+	receiveCmd.Flags().DurationVar(&receiveTimeout, "timeout", 3*time.Second, "How long to search for servers")
+
+	// Serve command: publish picked items on the stream server.
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Publish media from the cache for other devices to play",
+		Long: `Pick items from the cache (TAB to select several) and publish them on
+the stream server, where other devices can play them: from the web UI or
+its M3U playlist, or with 'goplexcli stream'. Runs until Ctrl+C.
+
+The server uses the stream_* settings from the config (access token, TLS,
+proxying).`,
+		Args: cobra.NoArgs,
+		RunE: runServe,
+	}
+	serveCmd.Flags().IntVar(&servePort, "port", stream.DefaultPort, "Port to listen on")
+	serveCmd.Flags().BoolVar(&serveQR, "qr", false, "Also print a QR code for each published stream")
+	addPprofFlag(serveCmd)
 
 	// Server command
 	serverCmd := &cobra.Command{
@@ -233,7 +659,42 @@ Downloading queued items:
 		RunE:              runServerRemove,
 	}
 
-	serverCmd.AddCommand(serverListCmd, serverEnableCmd, serverDisableCmd, serverRemoveCmd)
+	serverStatusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show version, load, libraries, tasks and latency for each server",
+		Long: `Check every configured server and summarize its identity and version,
+round-trip latency, active streams and transcodes, item counts per library,
+and any scans or scheduled tasks running right now. Useful for working out
+why playback is slow.`,
+		Args: cobra.NoArgs,
+		RunE: runServerStatus,
+	}
+
+	serverScanCmd := &cobra.Command{
+		Use:   "scan",
+		Short: "Scan libraries for new files",
+		Long: `Ask every enabled server to scan its libraries for new, changed and
+removed files, as the "Scan Library Files" button does in Plex Web. With
+--library only libraries with that name are scanned. Scans run in the
+background on the server; run 'goplexcli cache update' once they finish.`,
+		Args: cobra.NoArgs,
+		RunE: runServerScan,
+	}
+	serverScanCmd.Flags().StringVar(&serverScanLibrary, "library", "", "Only scan the library with this name")
+
+	serverCmd.AddCommand(serverListCmd, serverStatusCmd, serverScanCmd, serverEnableCmd, serverDisableCmd, serverRemoveCmd)
+
+	// Refresh command: re-fetch an item's metadata.
+	refreshCmd := &cobra.Command{
+		Use:   "refresh <show|movie>",
+		Short: "Refresh a show's or movie's metadata on the server",
+		Long: `Ask the Plex server to refresh the metadata of a movie, or of a show with
+all its seasons and episodes, from its agents, as "Refresh Metadata" does in
+Plex Web. Useful after fixing a match or replacing artwork.`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeMediaTitles,
+		RunE:              runRefresh,
+	}
 
 	// WebDAV command: discover gowebdav transfer targets on the LAN and manage
 	// the shared credentials used to reach them.
@@ -395,7 +856,11 @@ installing it.`,
 		Hidden: true,
 		Args:   cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return preview.Run(os.Stdout, args[0], args[1])
+			images, err := termimg.ParseProtocol(appFrom(cmd).Config.PreviewImages, os.Getenv)
+			if err != nil {
+				images = termimg.None
+			}
+			return preview.Run(os.Stdout, args[0], args[1], os.Getenv(ui.PreviewTokenEnv), images, loadArrSnapshot(appFrom(cmd).Config))
 		},
 	}
 
@@ -422,6 +887,7 @@ it's open; use this on a headless or CLI-only machine.`,
 	}
 	syncServeCmd.Flags().IntVar(&syncServePort, "port", lansync.DefaultPort, "Port to serve on (0 for a random port)")
 	syncServeCmd.Flags().DurationVar(&syncServeUpdateInterval, "update-interval", time.Hour, "How often to refresh this cache from Plex so peers stay current (0 to disable)")
+	addPprofFlag(syncServeCmd)
 	syncPullCmd := &cobra.Command{
 		Use:   "pull",
 		Short: "Pull the newest cache from another computer on the LAN",
@@ -439,30 +905,612 @@ multicast), name it directly with --peer:
 	syncPullCmd.Flags().StringVar(&syncPullPeer, "peer", "", "Pull directly from this host[:port], bypassing mDNS discovery")
 	syncCmd.AddCommand(syncServeCmd, syncPullCmd)
 
-	rootCmd.AddCommand(loginCmd, browseCmd, cacheCmd, configCmd, streamCmd, serverCmd, webdavCmd, outplayerCmd, sortCmd, versionCmd, updateCmd, syncCmd, previewCmd)
+	// Queue command: drain the download queue non-interactively, optionally
+	// deferred to an off-peak window.
+	queueCmd := &cobra.Command{
+		Use:   "queue",
+		Short: "Manage the download queue",
+	}
+	queueDownloadCmd := &cobra.Command{
+		Use:   "download",
+		Short: "Download every queued item, optionally on a schedule",
+		Long: `Download every item in the queue, removing each from the queue as soon
+as it finishes. Set download_concurrency in config to run several transfers
+in parallel.
+
+Use --at to wait until a time of day before starting, or --window to only
+start downloads inside a daily off-peak range (the worker sleeps outside it
+and resumes automatically). Times are local and 24-hour:
+
+  goplexcli queue download --at 02:00
+  goplexcli queue download --window 01:00-07:00
+
+Ctrl-C or SIGTERM stops the worker; anything not yet downloaded stays in
+the queue for the next run, and a file that was cut off mid-transfer
+resumes from where it stopped.`,
+		RunE: runQueueDownload,
+	}
+	queueDownloadCmd.Flags().StringVar(&queueDownloadAt, "at", "", "Wait until this time of day (HH:MM) before starting")
+	queueDownloadCmd.Flags().StringVar(&queueDownloadWindow, "window", "", "Only start downloads within this daily range (HH:MM-HH:MM)")
+	queueDownloadCmd.Flags().StringVar(&downloadDest, "dest", "", "Directory to download into (overrides download_dir in config; default: current directory)")
+	queueDownloadCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be downloaded without actually downloading")
+	queueDownloadCmd.MarkFlagsMutuallyExclusive("at", "window")
+	addPprofFlag(queueDownloadCmd)
+	queueCmd.AddCommand(queueDownloadCmd)
+
+	// Stats command: opt-in, local-only usage counters.
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show local statistics",
+	}
+	statsUsageCmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Show how often each command is used and how long it takes",
+		Long: `Show how often each goplexcli command has been run and its median runtime.
+
+Recording is off by default. Turn it on with --enable (or set
+"usage_stats": true in config). Statistics are kept in a local file next
+to the media cache and are never sent anywhere.`,
+		Args: cobra.NoArgs,
+		RunE: runStatsUsage,
+	}
+	statsUsageCmd.Flags().BoolVar(&statsEnable, "enable", false, "Start recording usage statistics")
+	statsUsageCmd.Flags().BoolVar(&statsDisable, "disable", false, "Stop recording usage statistics")
+	statsUsageCmd.Flags().BoolVar(&statsReset, "reset", false, "Delete all recorded usage statistics")
+	statsUsageCmd.MarkFlagsMutuallyExclusive("enable", "disable")
+	statsCmd.AddCommand(statsUsageCmd)
+
+	// History command: the local log of playback sessions.
+	historyCmd := &cobra.Command{
+		Use:   "history [title]",
+		Short: "Show local playback history",
+		Long: `List recent playback sessions, newest first: when, what, how much was
+watched and how far into the item it ended. Filter by title or time:
+
+  goplexcli history office
+  goplexcli history --since 7d
+  goplexcli history --since 2024-06-01 --limit 50
+
+Use 'history replay' to pick one of them and play it again.`,
+		Args: cobra.ArbitraryArgs,
+		RunE: runHistory,
+	}
+	historyCmd.PersistentFlags().StringVar(&historySince, "since", "", "Only sessions since a time ago (12h, 7d, 2w) or a date (YYYY-MM-DD)")
+	historyCmd.PersistentFlags().IntVarP(&historyLimit, "limit", "n", 20, "Show at most this many sessions (0 for all)")
+	historyReplayCmd := &cobra.Command{
+		Use:   "replay [title]",
+		Short: "Pick something from your history and play it again",
+		Args:  cobra.ArbitraryArgs,
+		RunE:  runHistoryReplay,
+	}
+	historyItemCmd := &cobra.Command{
+		Use:   "item <title>",
+		Short: "Show every playback session of an item",
+		Long: `Show a timeline of every time an item was played: when, from which
+position to which, how much was watched, and in which player.
+
+The title is matched case-insensitively against played items, so
+"office s02e01" or just "heat" works. History is recorded locally for
+every playback and needs no server connection to read.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: runHistoryItem,
+	}
+	historyCmd.AddCommand(historyItemCmd, historyReplayCmd)
+
+	// Calendar command: episode air dates by month.
+	calendarCmd := &cobra.Command{
+		Use:   "calendar",
+		Short: "Show a month of episode air dates",
+		Long: `Show a calendar of the days episodes of your shows aired, with the
+episodes listed below it, followed by episodes the server already has
+announced for future dates.
+
+Your shows are your favorited shows, or, with none favorited, every show
+you have watched some of. Use --all for every show in the library.
+
+  goplexcli calendar
+  goplexcli calendar --month 2024-06`,
+		Args: cobra.NoArgs,
+		RunE: runCalendar,
+	}
+	calendarCmd.Flags().StringVar(&calendarMonth, "month", "", "Month to show as YYYY-MM (default: this month)")
+	calendarCmd.Flags().BoolVar(&calendarAll, "all", false, "Include every show, not just yours")
+
+	// Export command: write cached media out for other tools.
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export media for use in other apps",
+		Long: `Write the cached catalog (title, year, rating, duration, file path and
+size of every item) as CSV for spreadsheets, JSON for scripts, or a
+Markdown table, e.g. for a backup audit:
+
+  goplexcli export --format csv -o library.csv
+  goplexcli export --format md --type movie > movies.md
+
+Subcommands export other things, such as m3u playlists.`,
+		Args: cobra.NoArgs,
+		RunE: runExport,
+	}
+	exportCmd.Flags().StringVar(&exportFormat, "format", "csv", "Output format: "+strings.Join(export.CatalogFormats, ", "))
+	exportCmd.Flags().StringVar(&exportType, "type", "", "Only export this type: movie or show (default: everything)")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "File to write (default: stdout)")
+	exportM3UCmd := &cobra.Command{
+		Use:   "m3u <show|movie|playlist|album>",
+		Short: "Write an m3u8 playlist of stream URLs",
+		Long: `Write an m3u8 playlist of direct stream URLs that any external player
+(VLC, a car head unit, a smart TV app) can open.
+
+The title is matched against the cache: a show exports every episode in
+order and a movie exports itself. Otherwise a Plex playlist, then a music
+album, with that name is looked up on the server; music isn't cached, so
+its tracks are streamed straight from their files.
+
+The URLs embed your Plex token, so treat the file like a password. With
+--proxy, they point at a stream server this command runs instead (using
+the stream_* settings from the config): the file carries no Plex token,
+and its URLs work while the command runs, until Ctrl+C.`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeMediaTitles,
+		RunE:              runExportM3U,
+	}
+	exportM3UCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "File to write (default: <title>.m3u8)")
+	exportM3UCmd.Flags().BoolVar(&exportProxy, "proxy", false, "Point the URLs at a local stream server instead of Plex, and run it")
+	exportM3UCmd.Flags().IntVar(&exportPort, "port", stream.DefaultPort, "Port for the --proxy stream server")
+	exportCmd.AddCommand(exportM3UCmd)
+
+	// Chapters command: list a movie's chapters for use with --chapter.
+	chaptersCmd := &cobra.Command{
+		Use:   "chapters <movie>",
+		Short: "List a movie's chapters",
+		Long: `List the chapters Plex found in a movie's file, with their start times.
+Start watching at one with --chapter:
+
+  goplexcli chapters "Heat"
+  goplexcli "Heat" --chapter 12`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeMovieTitles,
+		RunE:              runChapters,
+	}
+
+	// Live TV command: channels, tuning and recordings from Plex DVR.
+	livetvCmd := &cobra.Command{
+		Use:   "livetv",
+		Short: "List live TV channels and what's on now",
+		Long: `List the live TV channels of your Plex DVR with what is airing on each,
+tune one and watch it in your player, or list scheduled recordings.
+
+  goplexcli livetv
+  goplexcli livetv watch 4.1
+  goplexcli livetv recordings
+
+Live TV needs a Plex server with a tuner and Plex DVR set up. The server
+transcodes the live stream, so playback starts a few seconds behind air.`,
+		Args: cobra.NoArgs,
+		RunE: runLiveTV,
+	}
+	livetvWatchCmd := &cobra.Command{
+		Use:   "watch [channel]",
+		Short: "Tune a channel by number, call sign or name and play it",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runLiveTVWatch,
+	}
+	livetvRecordingsCmd := &cobra.Command{
+		Use:   "recordings",
+		Short: "List scheduled DVR recordings",
+		Args:  cobra.NoArgs,
+		RunE:  runLiveTVRecordings,
+	}
+	livetvCmd.AddCommand(livetvWatchCmd, livetvRecordingsCmd)
+
+	// Sessions command: who's watching right now.
+	sessionsCmd := &cobra.Command{
+		Use:   "sessions",
+		Short: "Show who is watching on the Plex server, live",
+		Long: `Show the server's active streams in a table that refreshes itself: who is
+watching what, on which device, how far in, whether the server is
+transcoding or playing the file directly, and the bandwidth used.
+
+Select a stream and press x to stop it. Only the server owner sees other
+users' streams and can stop them.`,
+		Args: cobra.NoArgs,
+		RunE: runSessions,
+	}
+	sessionsCmd.Flags().DurationVar(&sessionsInterval, "interval", 2*time.Second, "How often to refresh")
+	sessionsCmd.Flags().StringVar(&sessionsStopReason, "reason", "The server owner stopped this stream.", "Message shown to a viewer whose stream is stopped")
+
+	// Info command: full metadata for one item.
+	infoCmd := &cobra.Command{
+		Use:   "info <title>",
+		Short: "Show full details of a movie or episode",
+		Long: `Show everything known about one item: summary, rating, credits, format
+(resolution, codecs, bitrate), audio and subtitle tracks, file size and
+path, watched state and when it was added. Titles are matched as with
+'play'; for a show you pick the episode.
+
+Format and tracks come from the Plex server; if it can't be reached, only
+the cached metadata is shown.
+
+  goplexcli info "Heat"
+  goplexcli info "Heat" --json`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeMediaTitles,
+		RunE:              runInfo,
+	}
+	infoCmd.Flags().BoolVar(&infoJSON, "json", false, "Print the details as JSON")
+
+	// Similar command: TMDB recommendations for a cached title.
+	similarCmd := &cobra.Command{
+		Use:   "similar <title>",
+		Short: "List titles similar to a movie or show",
+		Long: `List TMDB's recommendations for a movie or show in the cache, marking
+the ones already in your library. Titles are matched as with 'play'.
+
+Needs tmdb_api_key in the config. Recommendations looked up while
+updating the cache are reused; others are fetched on demand.
+
+With radarr_url and radarr_api_key set, movies already in Radarr are
+marked with their status, and you can request one of the rest: Radarr
+monitors it and starts searching.
+
+  goplexcli similar "Heat"
+  goplexcli similar "Breaking Bad"`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeMediaTitles,
+		RunE:              runSimilar,
+	}
+
+	// Delete command: remove items from the Plex server.
+	deleteCmd := &cobra.Command{
+		Use:   "delete <show|movie>",
+		Short: "Delete media from your Plex server",
+		Long: `Delete a movie, or every episode of a show, from your Plex library.
+
+Plex deletes the media files along with the library entry, and only lets
+the server owner do so with "Allow media deletion" enabled in the server
+settings. Nothing is deleted until you type "delete" to confirm.
+
+With --and-files the files are also deleted from their rclone remote, for
+setups where the server sees them through a mount it can't delete from.`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeMediaTitles,
+		RunE:              runDelete,
+	}
+	deleteCmd.Flags().BoolVar(&deleteAndFiles, "and-files", false, "Also delete the files from their rclone remote")
+	deleteCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be deleted without deleting anything")
 
-	if err := rootCmd.Execute(); err != nil {
+	// Deleted command: the local log of deletions.
+	deletedCmd := &cobra.Command{
+		Use:   "deleted",
+		Short: "Show media deleted through goplexcli",
+	}
+	deletedListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List deleted media, most recent first",
+		Args:  cobra.NoArgs,
+		RunE:  runDeletedList,
+	}
+	deletedExportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the deletion log for re-acquiring items",
+		Long: `Write every logged deletion as CSV or JSON, including each item's
+external IDs (IMDb, TMDB, TVDB) and file path, so anything removed by
+mistake can be found again or imported into another tool.`,
+		Args: cobra.NoArgs,
+		RunE: runDeletedExport,
+	}
+	deletedExportCmd.Flags().StringVar(&deletedFormat, "format", "csv", "Output format: csv or json")
+	deletedExportCmd.Flags().StringVarP(&deletedOutput, "output", "o", "", "File to write (default: stdout)")
+	deletedCmd.AddCommand(deletedListCmd, deletedExportCmd)
+
+	// Downloads command: the local index of downloaded files.
+	downloadsCmd := &cobra.Command{
+		Use:   "downloads",
+		Short: "Manage files downloaded through goplexcli",
+	}
+	downloadsListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List downloaded files, most recent first",
+		Args:  cobra.NoArgs,
+		RunE:  runDownloadsList,
+	}
+	downloadsCleanCmd := &cobra.Command{
+		Use:   "clean",
+		Short: "Forget downloads whose files are gone",
+		Long: `Remove downloads whose files have been moved, deleted or changed from
+the index. With --files, delete every downloaded file as well.`,
+		Args: cobra.NoArgs,
+		RunE: runDownloadsClean,
+	}
+	downloadsCleanCmd.Flags().BoolVar(&downloadsCleanFiles, "files", false, "Also delete the downloaded files from disk")
+	downloadsCmd.AddCommand(downloadsListCmd, downloadsCleanCmd)
+
+	// Party command: keep two players in sync over the stream server.
+	partyCmd := &cobra.Command{
+		Use:   "party",
+		Short: "Watch something in sync with another goplexcli",
+		Long: `Host or join a watch party. The host picks an item and controls playback;
+guests play the same stream and mirror the host's play, pause and seeks
+through their own mpv or IINA, staying within a second of the host.
+
+The host's stream server uses the stream_* settings from the config
+(access token, TLS, proxying).`,
+	}
+	partyHostCmd := &cobra.Command{
+		Use:   "host",
+		Short: "Pick an item from the cache and host a watch party for it",
+		Args:  cobra.NoArgs,
+		RunE:  runPartyHost,
+	}
+	partyJoinCmd := &cobra.Command{
+		Use:   "join",
+		Short: "Join a watch party hosted on the network",
+		Long: `Join a watch party. By default the local network is searched for hosts;
+pass --host to join one directly, e.g. across subnets or a VPN where
+discovery doesn't reach.`,
+		Args: cobra.NoArgs,
+		RunE: runPartyJoin,
+	}
+	partyJoinCmd.Flags().StringVar(&streamToken, "token", "", "Access token for a protected host (default: stream_token from config)")
+	partyJoinCmd.Flags().StringVar(&partyJoinHost, "host", "", "Join the party at host:port instead of discovering one")
+	partyJoinCmd.Flags().StringVar(&partyJoinFingerprint, "fingerprint", "", "SHA-256 certificate fingerprint of a --host that serves HTTPS")
+	partyCmd.AddCommand(partyHostCmd, partyJoinCmd)
+
+	// Remote command: control other Plex players on the account.
+	remoteCmd := &cobra.Command{
+		Use:   "remote",
+		Short: "Control Plex players on your account, like a TV",
+		Long: `List the Plex apps signed in to your account that can be remote
+controlled, and play, pause, seek or stop them, or have one play a title
+from the cache: fling a movie from the terminal to the living-room TV.
+
+Without --player you pick the player, unless there is only one. Players
+must be on and reachable; most need "Advertise as player" (or remote
+control) enabled in their settings.
+
+  goplexcli remote list
+  goplexcli remote play "Heat" --player "Living Room"
+  goplexcli remote seek 1:02:30
+  goplexcli remote pause`,
+	}
+	remoteCmd.PersistentFlags().StringVarP(&remotePlayerName, "player", "p", "", "Name of the player to control")
+	remoteListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List Plex players on your account",
+		Args:  cobra.NoArgs,
+		RunE:  runRemoteList,
+	}
+	remotePlayCmd := &cobra.Command{
+		Use:   "play <title>",
+		Short: "Play a movie or show on a player",
+		Long: `Play a movie, or the next unwatched episode of a show, on a player.
+Titles are matched as with 'play'. Items with progress resume where they
+were left unless --from-start is given.`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeMediaTitles,
+		RunE:              runRemotePlay,
+	}
+	remotePlayCmd.Flags().BoolVar(&remoteFromStart, "from-start", false, "Play from the beginning even if there is progress")
+	remotePauseCmd := &cobra.Command{
+		Use:   "pause",
+		Short: "Pause a player",
+		Args:  cobra.NoArgs,
+		RunE:  runRemoteCommand,
+	}
+	remoteResumeCmd := &cobra.Command{
+		Use:   "resume",
+		Short: "Resume a paused player",
+		Args:  cobra.NoArgs,
+		RunE:  runRemoteCommand,
+	}
+	remoteStopCmd := &cobra.Command{
+		Use:   "stop",
+		Short: "Stop a player",
+		Args:  cobra.NoArgs,
+		RunE:  runRemoteCommand,
+	}
+	remoteSeekCmd := &cobra.Command{
+		Use:   "seek <position>",
+		Short: "Seek a player to a position, e.g. 1:02:30 or 90 (seconds)",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runRemoteCommand,
+	}
+	remoteCmd.AddCommand(remoteListCmd, remotePlayCmd, remotePauseCmd, remoteResumeCmd, remoteStopCmd, remoteSeekCmd)
+
+	// Declare what each command needs loaded before it runs; prepareApp does
+	// the loading and hands the result over via appFrom.
+	requireConfig(loginCmd, setupCmd, configCmd, streamCmd, receiveCmd, partyJoinCmd, queueDownloadCmd, statsUsageCmd,
+		cacheInfoCmd, historyCmd, historyItemCmd, deletedListCmd, deletedExportCmd, downloadsListCmd, downloadsCleanCmd, previewCmd,
+		serverListCmd, serverStatusCmd, serverScanCmd, serverEnableCmd, serverDisableCmd, serverRemoveCmd,
+		webdavListCmd, webdavAddCmd, webdavRemoveCmd, webdavEnableCmd, webdavDisableCmd, webdavSetCredsCmd,
+		outplayerListCmd, outplayerAddCmd, outplayerRemoveCmd, outplayerEnableCmd, outplayerDisableCmd, syncServeCmd, syncPullCmd)
+	requireLogin(cacheUpdateCmd, cacheReindexCmd, homeUsersCmd, homeSwitchCmd, livetvCmd, livetvWatchCmd, livetvRecordingsCmd, sessionsCmd,
+		remoteListCmd, remotePauseCmd, remoteResumeCmd, remoteStopCmd, remoteSeekCmd)
+	requireCache(rootCmd, browseCmd, playCmd, downloadCmd, historyReplayCmd, exportCmd, cachePostersCmd, cacheDedupeCmd, exportM3UCmd, chaptersCmd, infoCmd, similarCmd, refreshCmd, deleteCmd, calendarCmd, serveCmd, partyHostCmd, markWatchedCmd, cacheSyncWatchedCmd, remotePlayCmd, cacheSearchCmd, sortCmd)
+	rootCmd.PersistentPreRunE = prepareApp
+	rootCmd.PersistentPostRun = func(cmd *cobra.Command, args []string) {
+		appFrom(cmd).Close()
+	}
+
+	rootCmd.AddCommand(loginCmd, setupCmd, homeCmd, playCmd, downloadCmd, browseCmd, cacheCmd, configCmd, streamCmd, serveCmd, receiveCmd, partyCmd, serverCmd, webdavCmd, outplayerCmd, sortCmd, versionCmd, updateCmd, syncCmd, queueCmd, previewCmd, statsCmd, historyCmd, calendarCmd, exportCmd, chaptersCmd, infoCmd, similarCmd, refreshCmd, livetvCmd, sessionsCmd, deleteCmd, deletedCmd, downloadsCmd, markCmd, remoteCmd)
+
+	exitOnSignal()
+	start := time.Now()
+	executed, err := rootCmd.ExecuteC()
+	recordUsage(executed, time.Since(start))
+	if err != nil {
 		fmt.Println(errorStyle.Render("Error: " + err.Error()))
+		if hint := apperrors.Hint(err); hint != "" {
+			fmt.Println(infoStyle.Render(hint))
+		}
 		os.Exit(1)
 	}
 }
 
+// recordUsage adds one run of cmd to the local usage statistics when the user
+// has opted in. Hidden helper commands (the fzf preview runs once per cursor
+// move) are skipped, and failures are ignored: stats must never break a
+// command.
+func recordUsage(cmd *cobra.Command, elapsed time.Duration) {
+	if cmd == nil || cmd.Hidden {
+		return
+	}
+	cfg, err := config.Load()
+	if err != nil || !cfg.UsageStats {
+		return
+	}
+	name := strings.TrimSpace(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()))
+	if name == "" {
+		name = "(root)"
+	}
+	if err := usage.Record(name, elapsed); err != nil {
+		logging.Debug("Failed to record usage stats", "error", err)
+	}
+}
+
+// handlePanic turns a panic on the main goroutine into a crash report saved
+// under the cache dir, so users can attach it to a bug report instead of
+// copying a scrolled-away stack trace. It must be deferred first in main.
+func handlePanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	stack := debug.Stack()
+
+	// Best effort: a broken config may be what caused the crash.
+	cfg, _ := config.Load()
+
+	path, err := crash.Write(crash.Report{
+		Panic:   r,
+		Stack:   stack,
+		Version: version,
+		Args:    os.Args[1:],
+		Config:  cfg,
+		Logs:    logging.RecentLines(),
+	})
+
+	fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("goplexcli crashed: %v", r)))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not save crash report (%v). Stack trace:\n%s\n", err, stack)
+	} else {
+		fmt.Fprintf(os.Stderr, "A crash report was saved to %s\nPlease attach it when reporting this bug at https://github.com/joshkerr/goplexcli/issues\n", path)
+	}
+	os.Exit(2)
+}
+
+// addPprofFlag gives a long-running command the hidden --pprof flag. It is
+// hidden because it's a diagnostic for bug reports, not a user feature.
+func addPprofFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&pprofAddr, "pprof", "", "Serve net/http/pprof on this address (e.g. localhost:6060)")
+	_ = cmd.Flags().MarkHidden("pprof")
+	// Chain any pre-run hook the command already has; cobra runs only
+	// PreRunE when both are set, so a plain PreRun is folded in too.
+	prev, prevNoErr := cmd.PreRunE, cmd.PreRun
+	cmd.PreRun = nil
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := startPprof(pprofAddr); err != nil {
+			return err
+		}
+		if prev != nil {
+			return prev(cmd, args)
+		}
+		if prevNoErr != nil {
+			prevNoErr(cmd, args)
+		}
+		return nil
+	}
+}
+
+// startPprof serves the pprof endpoints on addr in the background. Binding
+// happens up front so a bad or busy address fails the command immediately.
+func startPprof(addr string) error {
+	if addr == "" {
+		return nil
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to start pprof server: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "pprof listening on http://%s/debug/pprof/\n", ln.Addr())
+	go func() {
+		_ = http.Serve(ln, nil)
+	}()
+	return nil
+}
+
 // recentlyAddedLimit caps how many items the "Recently Added" hub shows.
 const recentlyAddedLimit = 50
 
-// buildContinueWatching returns items with resumable playback progress, most
-// recently watched first. Progress reflects cache freshness ('cache reindex'
-// refreshes it for older items).
+// buildContinueWatching returns the "Continue Watching" row of the Plex
+// home screen, most recently watched first: movies with resumable progress,
+// and for each show either its episode in progress or, when the last one
+// watched was finished, the episode after it. Each show appears once.
+// Progress reflects cache freshness ('cache sync-watched' refreshes it).
 func buildContinueWatching(media []plex.MediaItem) []plex.MediaItem {
-	var out []plex.MediaItem
+	type entry struct {
+		item     plex.MediaItem
+		viewedAt int64
+	}
+	var entries []entry
+
+	// A show's episodes in order, keyed by server and show.
+	shows := make(map[[2]string][]*plex.MediaItem)
 	for i := range media {
-		if ui.HasResumableProgress(&media[i]) {
-			out = append(out, media[i])
+		item := &media[i]
+		if item.Type == "episode" && item.ParentTitle != "" {
+			key := [2]string{item.ServerName, item.ParentTitle}
+			shows[key] = append(shows[key], item)
+			continue
+		}
+		if ui.HasResumableProgress(item) {
+			entries = append(entries, entry{*item, item.LastViewedAt})
 		}
 	}
-	sort.SliceStable(out, func(i, j int) bool {
-		return out[i].LastViewedAt > out[j].LastViewedAt
+
+	for _, episodes := range shows {
+		var last int
+		for i, ep := range episodes {
+			if ep.LastViewedAt > episodes[last].LastViewedAt {
+				last = i
+			}
+		}
+		watched := episodes[last]
+		if watched.LastViewedAt == 0 {
+			continue
+		}
+		if ui.HasResumableProgress(watched) {
+			entries = append(entries, entry{*watched, watched.LastViewedAt})
+			continue
+		}
+
+		sort.SliceStable(episodes, func(i, j int) bool {
+			if episodes[i].ParentIndex != episodes[j].ParentIndex {
+				return episodes[i].ParentIndex < episodes[j].ParentIndex
+			}
+			return episodes[i].Index < episodes[j].Index
+		})
+		// Up next: the first unwatched episode after it, skipping specials
+		// as Plex does.
+		passed := false
+		for _, ep := range episodes {
+			if ep == watched {
+				passed = true
+				continue
+			}
+			if passed && ep.ParentIndex > 0 && ep.ViewCount == 0 {
+				entries = append(entries, entry{*ep, watched.LastViewedAt})
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].viewedAt > entries[j].viewedAt
 	})
+	out := make([]plex.MediaItem, len(entries))
+	for i, e := range entries {
+		out[i] = e.item
+	}
 	return out
 }
 
@@ -517,7 +1565,50 @@ func completeServerNames(cmd *cobra.Command, args []string, toComplete string) (
 	return names, cobra.ShellCompDirectiveNoFileComp
 }
 
+// completeMediaTitles provides shell completion for commands that take a
+// show or movie title, from the cache.
+func completeMediaTitles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeTitles(args, toComplete, true, true)
+}
+
+// completeMovieTitles completes movie titles from the cache.
+func completeMovieTitles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeTitles(args, toComplete, false, true)
+}
+
+// completeShowTitles completes TV show names from the cache, for --show.
+func completeShowTitles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeTitles(nil, toComplete, true, false)
+}
+
+// completeTitles completes a single (quoted) title argument. Completion
+// runs without the usual app setup, so the profile and cache are loaded
+// here.
+func completeTitles(args []string, toComplete string, shows, movies bool) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	if err := config.SetProfile(profileName); err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	mediaCache, err := cache.Load()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return export.Titles(mediaCache.Media, shows, movies, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
 func runLogin(cmd *cobra.Command, args []string) error {
+	if err := login(appFrom(cmd).Config); err != nil {
+		return err
+	}
+	fmt.Println(infoStyle.Render("\nRun 'goplexcli cache reindex' to build your media cache"))
+	return nil
+}
+
+// login signs in with a username and password, lets the user pick one of
+// the account's servers, and saves it and the tokens to cfg.
+func login(cfg *config.Config) error {
 	fmt.Println(titleStyle.Render("Plex Login"))
 
 	// Get username
@@ -539,6 +1630,21 @@ func runLogin(cmd *cobra.Command, args []string) error {
 	fmt.Println(infoStyle.Render("\nAuthenticating..."))
 
 	token, servers, err := plex.Authenticate(username, password)
+	// Accounts with two-factor authentication need the authenticator app's
+	// code as well; allow a couple of retries for a mistyped or stale one.
+	for attempt := 0; attempt < 3 && errors.Is(err, plex.ErrVerificationCodeRequired); attempt++ {
+		if attempt == 0 {
+			fmt.Println(infoStyle.Render("This account uses two-factor authentication."))
+		} else {
+			fmt.Println(warningStyle.Render("That code didn't work; codes change every 30 seconds."))
+		}
+		fmt.Print("Verification code: ")
+		var code string
+		if _, err := fmt.Scanln(&code); err != nil {
+			return fmt.Errorf("failed to read verification code: %w", err)
+		}
+		token, servers, err = plex.AuthenticateWithCode(username, password, strings.TrimSpace(code))
+	}
 	if err != nil {
 		return fmt.Errorf("authentication failed: %w", err)
 	}
@@ -547,28 +1653,14 @@ func runLogin(cmd *cobra.Command, args []string) error {
 
 	// Select server
 	var selectedServer plex.Server
-	var selectedURL string
 
 	if len(servers) == 1 {
 		selectedServer = servers[0]
 		fmt.Println(infoStyle.Render(fmt.Sprintf("\nFound server: %s", selectedServer.Name)))
-
-		// If server has multiple connections, let user choose
-		if len(selectedServer.Connections) > 1 {
-			selectedURL, err = selectConnection(selectedServer)
-			if err != nil {
-				return err
-			}
-		} else {
-			selectedURL = selectedServer.URL
-		}
 	} else {
 		// Multiple servers - let user choose
 		fmt.Println(infoStyle.Render(fmt.Sprintf("\nFound %d servers", len(servers))))
 
-		// Load config to check for fzf
-		cfg, _ := config.Load()
-
 		// Format servers for selection
 		var serverNames []string
 		for i, server := range servers {
@@ -605,25 +1697,13 @@ func runLogin(cmd *cobra.Command, args []string) error {
 			}
 			selectedServer = servers[choice-1]
 		}
-
-		// Now select connection for the chosen server
-		if len(selectedServer.Connections) > 1 {
-			selectedURL, err = selectConnection(selectedServer)
-			if err != nil {
-				return err
-			}
-		} else {
-			selectedURL = selectedServer.URL
-		}
 	}
 
-	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Selected server: %s", selectedServer.Name)))
+	connections := rankConnections(selectedServer)
+	selectedURL := connections[0]
+	owned := selectedServer.Owned
 
-	// Load existing config to preserve custom settings
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
-	}
+	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Selected server: %s", selectedServer.Name)))
 
 	// Check if we want to add this as an additional server or replace
 	if len(cfg.Servers) > 0 {
@@ -640,6 +1720,8 @@ func runLogin(cmd *cobra.Command, args []string) error {
 				if s.URL == selectedURL {
 					cfg.Servers[i].Enabled = true
 					cfg.Servers[i].Token = selectedServer.AccessToken
+					cfg.Servers[i].Connections = connections
+					cfg.Servers[i].Owned = &owned
 					serverExists = true
 					fmt.Println(infoStyle.Render("Server already exists, enabled it"))
 					break
@@ -649,10 +1731,12 @@ func runLogin(cmd *cobra.Command, args []string) error {
 			if !serverExists {
 				// Add new server
 				cfg.Servers = append(cfg.Servers, config.PlexServer{
-					Name:    selectedServer.Name,
-					URL:     selectedURL,
-					Token:   selectedServer.AccessToken,
-					Enabled: true,
+					Name:        selectedServer.Name,
+					URL:         selectedURL,
+					Token:       selectedServer.AccessToken,
+					Enabled:     true,
+					Connections: connections,
+					Owned:       &owned,
 				})
 				fmt.Println(successStyle.Render(fmt.Sprintf("✓ Added server '%s'", selectedServer.Name)))
 			}
@@ -660,10 +1744,12 @@ func runLogin(cmd *cobra.Command, args []string) error {
 			// Replace with new single-server config
 			cfg.Servers = []config.PlexServer{
 				{
-					Name:    selectedServer.Name,
-					URL:     selectedURL,
-					Token:   selectedServer.AccessToken,
-					Enabled: true,
+					Name:        selectedServer.Name,
+					URL:         selectedURL,
+					Token:       selectedServer.AccessToken,
+					Enabled:     true,
+					Connections: connections,
+					Owned:       &owned,
 				},
 			}
 			fmt.Println(infoStyle.Render("Replaced existing server configuration"))
@@ -672,10 +1758,12 @@ func runLogin(cmd *cobra.Command, args []string) error {
 		// First server
 		cfg.Servers = []config.PlexServer{
 			{
-				Name:    selectedServer.Name,
-				URL:     selectedURL,
-				Token:   selectedServer.AccessToken,
-				Enabled: true,
+				Name:        selectedServer.Name,
+				URL:         selectedURL,
+				Token:       selectedServer.AccessToken,
+				Enabled:     true,
+				Connections: connections,
+				Owned:       &owned,
 			},
 		}
 	}
@@ -685,6 +1773,9 @@ func runLogin(cmd *cobra.Command, args []string) error {
 	cfg.PlexToken = token
 	cfg.PlexUsername = username
 
+	// A fresh login starts as the account itself, not a Home user
+	cfg.HomeUser, cfg.AdminToken = "", ""
+
 	if err := cfg.Save(); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
@@ -703,65 +1794,611 @@ func runLogin(cmd *cobra.Command, args []string) error {
 	} else {
 		fmt.Println(infoStyle.Render("\nServer URL: " + selectedURL))
 	}
-
-	fmt.Println(infoStyle.Render("\nRun 'goplexcli cache reindex' to build your media cache"))
-
 	return nil
 }
 
-func selectConnection(server plex.Server) (string, error) {
-	fmt.Println(infoStyle.Render(fmt.Sprintf("\nServer '%s' has %d available connections:", server.Name, len(server.Connections))))
+// setupSteps is how many steps the setup wizard walks through.
+const setupSteps = 5
 
-	// Load config to check for fzf
-	cfg, _ := config.Load()
+func runSetup(cmd *cobra.Command, args []string) error {
+	return setupWizard(appFrom(cmd).Config)
+}
 
-	// Format connections for selection
-	var connectionDescs []string
-	for i, conn := range server.Connections {
-		connType := "Remote"
-		if i == 0 && server.Local {
-			connType = "Local"
-		} else {
-			// Check if this connection looks local (private IP)
-			if strings.HasPrefix(conn, "http://192.168.") ||
-				strings.HasPrefix(conn, "http://10.") ||
-				strings.HasPrefix(conn, "http://172.") {
-				connType = "Local"
+// setupWizard walks through first-time setup in one go: signing in and
+// picking a server (login ranks its connections), choosing a player,
+// mapping Plex paths to rclone remotes for downloads, checking for fzf, and
+// building the cache. The config is saved before the cache is built, so
+// stopping there keeps the rest.
+func setupWizard(cfg *config.Config) error {
+	reader := bufio.NewReader(os.Stdin)
+	step := func(n int, title string) {
+		fmt.Println(titleStyle.Render(fmt.Sprintf("\nStep %d/%d: %s", n, setupSteps, title)))
+	}
+
+	step(1, "Plex account and server")
+	if cfg.Validate() == nil {
+		fmt.Println(infoStyle.Render(fmt.Sprintf("Already signed in, with %d server(s) configured.", len(cfg.Servers))))
+		if askYesNo(reader, "Sign in again?", false) {
+			if err := login(cfg); err != nil {
+				return err
 			}
 		}
-		connectionDescs = append(connectionDescs, fmt.Sprintf("%d. %s [%s]", i+1, conn, connType))
+	} else if err := login(cfg); err != nil {
+		return err
 	}
 
-	var selectedIdx int
+	step(2, "Player")
+	setupPlayer(reader, cfg)
 
-	// Check if fzf is available
+	step(3, "Downloads")
+	if err := setupRclone(reader, cfg); err != nil {
+		return err
+	}
+
+	step(4, "Pickers")
 	if ui.IsAvailable(cfg.FzfPath) {
-		_, idx, err := ui.SelectWithFzf(connectionDescs, "Select connection:", cfg.FzfPath)
-		if err != nil {
-			return "", fmt.Errorf("connection selection failed: %w", err)
-		}
-		selectedIdx = idx
+		fmt.Println(successStyle.Render("✓ fzf found: pickers have fuzzy search and a preview pane"))
 	} else {
-		// Fallback to manual selection
-		for _, desc := range connectionDescs {
-			fmt.Println("  " + desc)
-		}
-		fmt.Print("\nSelect connection number: ")
-		var choice int
-		if _, err := fmt.Scanln(&choice); err != nil {
-			return "", fmt.Errorf("failed to read selection: %w", err)
-		}
-		if choice < 1 || choice > len(server.Connections) {
-			return "", fmt.Errorf("invalid selection")
+		fmt.Println(infoStyle.Render("fzf isn't installed, so pickers are numbered menus. Install fzf (https://github.com/junegunn/fzf) for fuzzy search and previews."))
+		path := askLine(reader, "Path to fzf, if it's installed outside PATH (blank to skip): ")
+		if path != "" {
+			if ui.IsAvailable(path) {
+				cfg.FzfPath = path
+				fmt.Println(successStyle.Render("✓ Using fzf at " + path))
+			} else {
+				fmt.Println(warningStyle.Render("⚠ No fzf at " + path + "; skipped"))
+			}
 		}
-		selectedIdx = choice - 1
 	}
 
-	if selectedIdx < 0 || selectedIdx >= len(server.Connections) {
-		return "", fmt.Errorf("invalid connection selection")
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
 	}
+	fmt.Println(successStyle.Render("✓ Configuration saved"))
 
-	return server.Connections[selectedIdx], nil
+	step(5, "Media cache")
+	fmt.Println(infoStyle.Render("goplexcli browses a local cache of your libraries. Building it takes a few minutes for large libraries."))
+	if !askYesNo(reader, "Build the cache now?", true) {
+		fmt.Println(infoStyle.Render("Run 'goplexcli cache reindex' when you're ready."))
+		return nil
+	}
+	if err := updateCache(cfg, true); err != nil {
+		return err
+	}
+	fmt.Println(successStyle.Render("\n✓ Setup complete. Run 'goplexcli' to start browsing."))
+	return nil
+}
+
+// setupPlayer detects the installed players and sets cfg.Player, asking
+// which to use when there are several.
+func setupPlayer(reader *bufio.Reader, cfg *config.Config) {
+	var found []string
+	for _, name := range []string{"mpv", "vlc", "iina"} {
+		if name == "iina" && runtime.GOOS != "darwin" {
+			continue
+		}
+		c := *cfg
+		c.Player = name
+		if player.IsPlayerAvailable(name, c.PlayerPath()) {
+			found = append(found, name)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		fmt.Println(warningStyle.Render("⚠ No player found. Install mpv (https://mpv.io) to watch from goplexcli; downloads work without one."))
+		return
+	case 1:
+		cfg.Player = found[0]
+		fmt.Println(successStyle.Render("✓ Using " + found[0]))
+		return
+	}
+
+	current := slices.Index(found, cfg.PlayerName())
+	if current < 0 {
+		current = 0
+	}
+	fmt.Println(infoStyle.Render("Found several players:"))
+	for i, name := range found {
+		fmt.Printf("  %d. %s\n", i+1, name)
+	}
+	choice, err := strconv.Atoi(askLine(reader, fmt.Sprintf("Which should goplexcli use? (1-%d, Enter for %s): ", len(found), found[current])))
+	if err != nil || choice < 1 || choice > len(found) {
+		choice = current + 1
+	}
+	cfg.Player = found[choice-1]
+	fmt.Println(successStyle.Render("✓ Using " + cfg.Player))
+}
+
+// setupRclone offers to add a path mapping, which downloads need to turn a
+// file's path on the Plex server into an rclone remote path.
+func setupRclone(reader *bufio.Reader, cfg *config.Config) error {
+	if !download.IsAvailable(cfg.RclonePath) {
+		fmt.Println(warningStyle.Render("⚠ rclone isn't installed; downloads need it (https://rclone.org/install). Run 'goplexcli setup' again once it is."))
+		return nil
+	}
+	if len(cfg.PathMappings) > 0 {
+		fmt.Println(successStyle.Render(fmt.Sprintf("✓ %d path mapping(s) set; edit path_mappings in the config to change them", len(cfg.PathMappings))))
+		return nil
+	}
+
+	fmt.Println(infoStyle.Render("Downloads copy files with rclone. A path mapping tells goplexcli where the\nPlex server's folders are on an rclone remote, e.g. /mnt/media/ → gdrive:Media/."))
+	if !askYesNo(reader, "Add a path mapping now?", true) {
+		return nil
+	}
+	remotes, err := download.ListRemotes(context.Background(), cfg.RclonePath)
+	if err != nil {
+		return err
+	}
+	if len(remotes) == 0 {
+		fmt.Println(warningStyle.Render("⚠ rclone has no remotes yet. Run 'rclone config' to add one, then 'goplexcli setup' again."))
+		return nil
+	}
+
+	prefix := askLine(reader, "Media folder on the Plex server (e.g. /mnt/media/): ")
+	if prefix == "" {
+		return nil
+	}
+	remote := remotes[0]
+	if len(remotes) > 1 {
+		for i, r := range remotes {
+			fmt.Printf("  %d. %s\n", i+1, r)
+		}
+		choice, err := strconv.Atoi(askLine(reader, fmt.Sprintf("Remote holding those files (1-%d): ", len(remotes))))
+		if err != nil || choice < 1 || choice > len(remotes) {
+			fmt.Println(warningStyle.Render("⚠ Invalid choice; no mapping added"))
+			return nil
+		}
+		remote = remotes[choice-1]
+	}
+	dir := strings.Trim(askLine(reader, fmt.Sprintf("Folder on %s with the same files (blank for its root): ", remote)), "/")
+	if dir != "" {
+		dir += "/"
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	cfg.PathMappings = append(cfg.PathMappings, config.PathMapping{Prefix: prefix, Remote: remote + dir})
+	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Mapped %s → %s%s", prefix, remote, dir)))
+	return nil
+}
+
+// askLine prints prompt and returns the trimmed line typed in reply.
+func askLine(reader *bufio.Reader, prompt string) string {
+	fmt.Print(prompt)
+	line, _ := reader.ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+// askYesNo asks a yes/no question, returning def for an empty answer.
+func askYesNo(reader *bufio.Reader, question string, def bool) bool {
+	hint := "[y/N]"
+	if def {
+		hint = "[Y/n]"
+	}
+	switch strings.ToLower(askLine(reader, question+" "+hint+": ")) {
+	case "y", "yes":
+		return true
+	case "n", "no":
+		return false
+	}
+	return def
+}
+
+func runHomeUsers(cmd *cobra.Command, args []string) error {
+	cfg := appFrom(cmd).Config
+
+	users, err := plex.GetHomeUsers(cmd.Context(), homeAccountToken(cfg))
+	if err != nil {
+		return err
+	}
+	fmt.Println(titleStyle.Render("Plex Home Users"))
+	for _, u := range users {
+		line := "  " + homeUserLabel(u)
+		if u.Title == cfg.HomeUser || cfg.HomeUser == "" && u.Admin {
+			fmt.Println(successStyle.Render(line + " ← current"))
+		} else {
+			fmt.Println(line)
+		}
+	}
+	return nil
+}
+
+func runHomeSwitch(cmd *cobra.Command, args []string) error {
+	cfg := appFrom(cmd).Config
+	ctx := cmd.Context()
+	accountToken := homeAccountToken(cfg)
+
+	users, err := plex.GetHomeUsers(ctx, accountToken)
+	if err != nil {
+		return err
+	}
+
+	var user *plex.HomeUser
+	if len(args) == 1 {
+		for i := range users {
+			if strings.EqualFold(users[i].Title, args[0]) {
+				user = &users[i]
+			}
+		}
+		if user == nil {
+			return fmt.Errorf("no Plex Home user named %q (see 'goplexcli home users')", args[0])
+		}
+	} else {
+		labels := make([]string, len(users))
+		for i, u := range users {
+			labels[i] = homeUserLabel(u)
+		}
+		idx, err := chooseIndex(cfg, labels, "user")
+		if err != nil {
+			if errors.Is(err, apperrors.ErrCancelled) {
+				return nil
+			}
+			return err
+		}
+		user = &users[idx]
+	}
+
+	pin := homePIN
+	if user.Protected && pin == "" {
+		fmt.Printf("PIN for %s: ", user.Title)
+		pinBytes, err := term.ReadPassword(int(syscall.Stdin))
+		fmt.Println()
+		if err != nil {
+			return fmt.Errorf("failed to read PIN: %w", err)
+		}
+		pin = string(pinBytes)
+	}
+
+	userToken, err := plex.SwitchHomeUser(ctx, accountToken, user.ID, pin)
+	if err != nil {
+		return fmt.Errorf("failed to switch to %s: %w", user.Title, err)
+	}
+
+	// Servers issue each user their own access token.
+	servers, err := plex.ServersForToken(userToken)
+	if err != nil {
+		return fmt.Errorf("failed to get %s's servers: %w", user.Title, err)
+	}
+	for i := range cfg.Servers {
+		cfg.Servers[i].Token = ""
+		if srv, ok := matchPlexServer(cfg.Servers[i], servers); ok {
+			cfg.Servers[i].Token = srv.AccessToken
+		} else {
+			fmt.Println(warningStyle.Render(fmt.Sprintf("⚠ %s isn't shared with %s", cfg.Servers[i].Name, user.Title)))
+		}
+	}
+
+	if user.Admin {
+		cfg.PlexToken, cfg.HomeUser, cfg.AdminToken = accountToken, "", ""
+	} else {
+		cfg.PlexToken, cfg.HomeUser, cfg.AdminToken = userToken, user.Title, accountToken
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Println(successStyle.Render("✓ Switched to " + user.Title))
+	fmt.Println(infoStyle.Render("Run 'goplexcli cache reindex' to load " + user.Title + "'s watched status"))
+	return nil
+}
+
+// homeAccountToken returns the token that can list and switch Home users:
+// the admin's, kept aside while switched to another user.
+func homeAccountToken(cfg *config.Config) string {
+	if cfg.AdminToken != "" {
+		return cfg.AdminToken
+	}
+	return cfg.PlexToken
+}
+
+func homeUserLabel(u plex.HomeUser) string {
+	var tags []string
+	if u.Admin {
+		tags = append(tags, "admin")
+	}
+	if u.Restricted {
+		tags = append(tags, "managed")
+	}
+	if u.Protected {
+		tags = append(tags, "PIN")
+	}
+	if len(tags) == 0 {
+		return u.Title
+	}
+	return fmt.Sprintf("%s (%s)", u.Title, strings.Join(tags, ", "))
+}
+
+func runRemoteList(cmd *cobra.Command, args []string) error {
+	cfg := appFrom(cmd).Config
+
+	players, err := plex.GetRemotePlayers(cmd.Context(), homeAccountToken(cfg))
+	if err != nil {
+		return err
+	}
+	if len(players) == 0 {
+		fmt.Println(infoStyle.Render("No Plex players on your account."))
+		return nil
+	}
+	fmt.Println(titleStyle.Render("Plex Players"))
+	for _, p := range players {
+		fmt.Println("  " + remotePlayerLabel(p))
+	}
+	return nil
+}
+
+func runRemotePlay(cmd *cobra.Command, args []string) error {
+	app := appFrom(cmd)
+	cfg, mediaCache := app.Config, app.Cache
+	ctx := cmd.Context()
+
+	items, err := resolveTitleArg(cfg, mediaCache.Media, strings.Join(args, " "))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrCancelled) {
+			return nil
+		}
+		return err
+	}
+	item := items[0]
+	if item.Type == "episode" {
+		item = export.NextEpisode(items)
+	}
+
+	remote, name, err := chooseRemote(cmd, cfg)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrCancelled) {
+			return nil
+		}
+		return err
+	}
+
+	serverURL := item.ServerURL
+	if serverURL == "" {
+		serverURL = cfg.PlexURL
+	}
+	client, err := plex.NewWithName(serverURL, cfg.TokenForURL(serverURL), item.ServerName)
+	if err != nil {
+		return fmt.Errorf("failed to create plex client: %w", err)
+	}
+	client.SetFallbacks(cfg.ConnectionsForURL(serverURL))
+
+	offset := 0
+	if !remoteFromStart && ui.HasResumableProgress(item) {
+		offset = item.ViewOffset
+	}
+	if err := remote.PlayMedia(ctx, client, item.RatingKey(), offset); err != nil {
+		return fmt.Errorf("failed to play on %s: %w", name, err)
+	}
+	msg := fmt.Sprintf("✓ Playing %s on %s", item.FormatMediaTitle(), name)
+	if offset > 0 {
+		msg += " from " + progress.FormatDuration(offset)
+	}
+	fmt.Println(successStyle.Render(msg))
+	return nil
+}
+
+// runRemoteCommand runs the pause, resume, stop and seek subcommands.
+func runRemoteCommand(cmd *cobra.Command, args []string) error {
+	cfg := appFrom(cmd).Config
+	ctx := cmd.Context()
+
+	var seekMs int
+	if cmd.Name() == "seek" {
+		var err error
+		if seekMs, err = parseClock(args[0]); err != nil {
+			return err
+		}
+	}
+
+	remote, name, err := chooseRemote(cmd, cfg)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrCancelled) {
+			return nil
+		}
+		return err
+	}
+
+	var done string
+	switch cmd.Name() {
+	case "pause":
+		err, done = remote.Pause(ctx), "Paused "+name
+	case "resume":
+		err, done = remote.Play(ctx), "Resumed "+name
+	case "stop":
+		err, done = remote.Stop(ctx), "Stopped "+name
+	case "seek":
+		err, done = remote.SeekTo(ctx, seekMs), fmt.Sprintf("Moved %s to %s", name, progress.FormatDuration(seekMs))
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Println(successStyle.Render("✓ " + done))
+	return nil
+}
+
+// chooseRemote returns a Remote for the player named by --player, or else
+// the account's only player or one the user picks, along with its name.
+func chooseRemote(cmd *cobra.Command, cfg *config.Config) (*plex.Remote, string, error) {
+	token := homeAccountToken(cfg)
+	players, err := plex.GetRemotePlayers(cmd.Context(), token)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(players) == 0 {
+		return nil, "", fmt.Errorf("no Plex players on your account; is the TV on and signed in?")
+	}
+
+	var player *plex.RemotePlayer
+	switch {
+	case remotePlayerName != "":
+		for i := range players {
+			if strings.EqualFold(players[i].Name, remotePlayerName) {
+				player = &players[i]
+			}
+		}
+		if player == nil {
+			return nil, "", fmt.Errorf("no Plex player named %q (see 'goplexcli remote list')", remotePlayerName)
+		}
+	case len(players) == 1:
+		player = &players[0]
+	default:
+		labels := make([]string, len(players))
+		for i, p := range players {
+			labels[i] = remotePlayerLabel(p)
+		}
+		idx, err := chooseIndex(cfg, labels, "player")
+		if err != nil {
+			return nil, "", err
+		}
+		player = &players[idx]
+	}
+	return plex.NewRemote(*player, token), player.Name, nil
+}
+
+func remotePlayerLabel(p plex.RemotePlayer) string {
+	var tags []string
+	for _, t := range []string{p.Product, p.Platform} {
+		if t != "" {
+			tags = append(tags, t)
+		}
+	}
+	if len(tags) == 0 {
+		return p.Name
+	}
+	return fmt.Sprintf("%s (%s)", p.Name, strings.Join(tags, ", "))
+}
+
+// parseClock parses a position given as seconds ("90"), minutes and
+// seconds ("1:30") or hours, minutes and seconds ("1:02:30"), returning
+// milliseconds.
+func parseClock(s string) (int, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid position %q: use seconds, m:ss or h:mm:ss", s)
+	}
+	total := 0
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || i > 0 && n >= 60 {
+			return 0, fmt.Errorf("invalid position %q: use seconds, m:ss or h:mm:ss", s)
+		}
+		total = total*60 + n
+	}
+	return total * 1000, nil
+}
+
+// reachableServerURL returns the URL to reach server at: its configured URL
+// when that answers, otherwise the first of its other connections that does,
+// in the order ranked at login. With no fallbacks, or none answering, it is
+// the configured URL.
+func reachableServerURL(ctx context.Context, cfg *config.Config, server config.PlexServer) string {
+	var others []plex.Connection
+	for _, u := range server.Connections {
+		if strings.TrimRight(u, "/") != strings.TrimRight(server.URL, "/") {
+			others = append(others, plex.Connection{URI: u})
+		}
+	}
+	if len(others) == 0 {
+		return server.URL
+	}
+
+	token := cfg.TokenForServer(server)
+	if p := plex.ProbeConnections(ctx, []plex.Connection{{URI: server.URL}}, token)[0]; p.Err == nil {
+		return server.URL
+	}
+	for _, p := range plex.ProbeConnections(ctx, others, token) {
+		if p.Err == nil {
+			logging.Info("server unreachable at its configured URL; using a fallback connection", "server", server.Name, "url", p.URI)
+			fmt.Println(warningStyle.Render(fmt.Sprintf("%s isn't answering at %s; using %s", server.Name, server.URL, p.URI)))
+			return p.URI
+		}
+	}
+	return server.URL
+}
+
+// newStreamClient returns a client for serverURL that, if serverURL is down
+// when resolving a stream, fails over to the server's other stored
+// connections, relay included.
+func newStreamClient(cfg *config.Config, serverURL string) (*plex.Client, error) {
+	client, err := plex.New(serverURL, cfg.TokenForURL(serverURL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create plex client: %w", err)
+	}
+	client.SetFallbacks(cfg.ConnectionsForURL(serverURL))
+	return client, nil
+}
+
+// getStreamURL resolves key's stream URL through client, logging the
+// endpoint used when the client had to fail over to reach it.
+func getStreamURL(client *plex.Client, key string) (string, error) {
+	before := client.ServerURL()
+	streamURL, err := client.GetStreamURL(key)
+	if err == nil && client.ServerURL() != before {
+		logging.Info("server unreachable; streaming from a fallback connection", "configured", before, "url", client.ServerURL())
+		fmt.Println(warningStyle.Render(fmt.Sprintf("Server isn't answering at %s; using %s", before, client.ServerURL())))
+	}
+	return streamURL, err
+}
+
+// matchPlexServer finds a configured server among those plex.tv lists, by
+// connection URL, then by name.
+func matchPlexServer(s config.PlexServer, servers []plex.Server) (plex.Server, bool) {
+	for _, srv := range servers {
+		if s.HasURL(srv.URL) {
+			return srv, true
+		}
+		for _, conn := range srv.Connections {
+			if s.HasURL(conn.URI) {
+				return srv, true
+			}
+		}
+	}
+	for _, srv := range servers {
+		if srv.Name == s.Name {
+			return srv, true
+		}
+	}
+	return plex.Server{}, false
+}
+
+// rankConnections probes every address plex.tv advertises for server and
+// returns them best first (see plex.RankProbes), printing what it found. If
+// none answers, plex.tv's preferred URL is put first.
+func rankConnections(server plex.Server) []string {
+	if len(server.Connections) <= 1 {
+		return []string{server.URL}
+	}
+
+	fmt.Println(infoStyle.Render(fmt.Sprintf("\nProbing %d connections to %s...", len(server.Connections), server.Name)))
+	probes := plex.ProbeConnections(context.Background(), server.Connections, server.AccessToken)
+	plex.RankProbes(probes)
+
+	urls := make([]string, 0, len(probes))
+	for _, p := range probes {
+		var tags []string
+		if p.Local {
+			tags = append(tags, "local")
+		}
+		if p.Relay {
+			tags = append(tags, "relay")
+		}
+		label := p.URI
+		if len(tags) > 0 {
+			label += " [" + strings.Join(tags, ", ") + "]"
+		}
+		if p.Err != nil {
+			fmt.Println(warningStyle.Render("  ✗ " + label + " unreachable"))
+		} else {
+			fmt.Printf("  ✓ %s %s\n", label, infoStyle.Render(p.Latency.Round(time.Millisecond).String()))
+		}
+		urls = append(urls, p.URI)
+	}
+
+	if probes[0].Err != nil {
+		fmt.Println(warningStyle.Render("No connection answered; using the one plex.tv prefers."))
+		urls = append([]string{server.URL}, slices.DeleteFunc(urls, func(u string) bool { return u == server.URL })...)
+	}
+	return urls
 }
 
 func selectMediaManual(media []plex.MediaItem) (*plex.MediaItem, error) {
@@ -788,18 +2425,23 @@ func selectMediaManual(media []plex.MediaItem) (*plex.MediaItem, error) {
 }
 
 // selectMediaFlat handles flat media selection (for movies or "all" media type).
-// Returns selected media items, whether user cancelled, and any error.
-func selectMediaFlat(media []plex.MediaItem, cfg *config.Config, prompt string) ([]*plex.MediaItem, bool, error) {
+// Returns selected media items, the action picked with an inline fzf key
+// ("" if none; see ui.SelectMediaWithActions), whether user cancelled, and
+// any error.
+func selectMediaFlat(media []plex.MediaItem, cfg *config.Config, prompt string) ([]*plex.MediaItem, string, bool, error) {
 	var selectedMediaItems []*plex.MediaItem
+	var action string
 
 	if ui.IsAvailable(cfg.FzfPath) {
-		selectedIndices, err := ui.SelectMediaWithPreview(media, prompt, cfg.FzfPath, cfg.PlexURL, cfg.PlexToken)
+		refreshArrSnapshot(cfg)
+		selectedIndices, picked, err := ui.SelectMediaWithActions(media, prompt, cfg.FzfPath, cfg.PlexURL, cfg.PlexToken)
 		if err != nil {
 			if errors.Is(err, apperrors.ErrCancelled) {
-				return nil, true, nil
+				return nil, "", true, nil
 			}
-			return nil, false, fmt.Errorf("media selection failed: %w", err)
+			return nil, "", false, fmt.Errorf("media selection failed: %w", err)
 		}
+		action = picked
 
 		// Build list of selected media items
 		for _, index := range selectedIndices {
@@ -813,31 +2455,19 @@ func selectMediaFlat(media []plex.MediaItem, cfg *config.Config, prompt string)
 		// Fallback to manual selection (no fzf required)
 		selectedMedia, err := selectMediaManual(media)
 		if err != nil {
-			return nil, false, err
+			return nil, "", false, err
 		}
 		selectedMediaItems = []*plex.MediaItem{selectedMedia}
 	}
 
-	return selectedMediaItems, false, nil
+	return selectedMediaItems, action, false, nil
 }
 
 func runSearch(cmd *cobra.Command, args []string) error {
 	searchTerm := strings.ToLower(strings.Join(args, " "))
 
-	// Load config
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
-	}
-	if err := cfg.Validate(); err != nil {
-		return fmt.Errorf("invalid config: %w. Please run 'goplexcli login' first", err)
-	}
-
-	// Load cache
-	mediaCache, err := cache.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load cache: %w", err)
-	}
+	app := appFrom(cmd)
+	cfg, mediaCache := app.Config, app.Cache
 	if len(mediaCache.Media) == 0 {
 		fmt.Println(warningStyle.Render("Cache is empty. Run 'goplexcli cache reindex' first."))
 		return nil
@@ -952,6 +2582,9 @@ func runSearch(cmd *cobra.Command, args []string) error {
 	if len(results) == 0 {
 		fmt.Println(warningStyle.Render(fmt.Sprintf("No results found for \"%s\".", strings.Join(args, " "))))
 		fmt.Println(infoStyle.Render("Try 'goplexcli cache reindex' if your library has been updated recently."))
+		if cfg.OverseerrURL != "" && cfg.OverseerrAPIKey != "" && ui.Interactive() {
+			return offerOverseerrRequest(cmd.Context(), cfg, strings.Join(args, " "))
+		}
 		return nil
 	}
 
@@ -979,6 +2612,7 @@ func runSearch(cmd *cobra.Command, args []string) error {
 			for i, r := range results {
 				previewItems[i] = r.previewItem
 			}
+			refreshArrSnapshot(cfg)
 			idx, err = ui.SelectMediaWithCustomLabels(previewItems, labels, "Select:", cfg.FzfPath, cfg.PlexURL, cfg.PlexToken)
 		} else {
 			_, idx, err = ui.SelectWithFzf(labels, "Select:", cfg.FzfPath)
@@ -1011,7 +2645,7 @@ func runSearch(cmd *cobra.Command, args []string) error {
 	if selected.isMovie {
 		// Movie: go straight to action
 		selectedMediaItems := []*plex.MediaItem{selected.item}
-		err = handleMediaAction(cfg, q, selectedMediaItems)
+		err = handleMediaAction(app, q, selectedMediaItems, "")
 		if err != nil && !errors.Is(err, errAddedToQueue) {
 			return err
 		}
@@ -1034,7 +2668,7 @@ func runSearch(cmd *cobra.Command, args []string) error {
 
 	fmt.Println(infoStyle.Render(fmt.Sprintf("\n%s has %d seasons...\n", selected.showName, len(seasons))))
 
-	selectedSeason, err := ui.SelectSeason(seasons, selected.showName, cfg.FzfPath)
+	selectedSeason, err := ui.SelectSeasonWithPreview(seasons, allEpisodes, selected.showName, cfg.FzfPath)
 	if err != nil {
 		if errors.Is(err, apperrors.ErrCancelled) {
 			return nil
@@ -1054,7 +2688,7 @@ func runSearch(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Println(infoStyle.Render(fmt.Sprintf("\n%s has %d episodes...\n", seasonLabel, len(episodesInSeason))))
 
-	selectedMediaItems, cancelled, err := selectMediaFlat(episodesInSeason, cfg, "Select episode(s) (TAB for multi-select):")
+	selectedMediaItems, action, cancelled, err := selectMediaFlat(episodesInSeason, cfg, "Select episode(s) (TAB for multi-select):")
 	if err != nil {
 		return err
 	}
@@ -1066,41 +2700,410 @@ func runSearch(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	err = handleMediaAction(cfg, q, selectedMediaItems)
+	err = handleMediaAction(app, q, selectedMediaItems, action)
 	if err != nil && !errors.Is(err, errAddedToQueue) {
 		return err
 	}
 	return nil
 }
 
-func runBrowse(cmd *cobra.Command, args []string) error {
-	// Show logo for interactive browse command
-	ui.Logo(version)
-
-	// Load config
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
-	}
-
-	if err := cfg.Validate(); err != nil {
-		return fmt.Errorf("invalid config: %w. Please run 'goplexcli login' first", err)
-	}
-
-	// Load cache
-	mediaCache, err := cache.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load cache: %w", err)
-	}
-
+func runPlay(cmd *cobra.Command, args []string) error {
+	app := appFrom(cmd)
+	cfg, mediaCache := app.Config, app.Cache
 	if len(mediaCache.Media) == 0 {
 		fmt.Println(warningStyle.Render("Cache is empty. Run 'goplexcli cache reindex' first."))
 		return nil
 	}
 
-	fmt.Println(infoStyle.Render(fmt.Sprintf("Loaded %d media items from cache", len(mediaCache.Media))))
-	fmt.Println(infoStyle.Render(fmt.Sprintf("Last updated: %s", mediaCache.LastUpdated.Format(time.RFC822))))
-
+	items, err := resolveTitleArg(cfg, mediaCache.Media, strings.Join(args, " "))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrCancelled) {
+			return nil
+		}
+		return err
+	}
+	item := items[0]
+	if item.Type == "episode" {
+		item = export.NextEpisode(items)
+	}
+
+	fmt.Println(infoStyle.Render("Playing " + item.FormatMediaTitle()))
+	return handleWatchMultiple(app, []*plex.MediaItem{item})
+}
+
+func runDownload(cmd *cobra.Command, args []string) error {
+	app := appFrom(cmd)
+	cfg, mediaCache := app.Config, app.Cache
+	if len(mediaCache.Media) == 0 {
+		fmt.Println(warningStyle.Render("Cache is empty. Run 'goplexcli cache reindex' first."))
+		return nil
+	}
+
+	query := downloadShow
+	if query == "" {
+		query = strings.Join(args, " ")
+	}
+	if query == "" {
+		return fmt.Errorf("give a movie title or --show")
+	}
+	seasonSet := cmd.Flags().Changed("season")
+	if downloadEpisodes != "" && !seasonSet {
+		return fmt.Errorf("--episodes needs --season")
+	}
+	episodes, err := export.ParseEpisodes(downloadEpisodes)
+	if err != nil {
+		return err
+	}
+
+	items, err := resolveTitleArg(cfg, mediaCache.Media, query)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrCancelled) {
+			return nil
+		}
+		return err
+	}
+	if items[0].Type != "episode" && (downloadShow != "" || seasonSet) {
+		return fmt.Errorf("%s is a movie, not a show", items[0].FormatMediaTitle())
+	}
+	if seasonSet {
+		var filtered []*plex.MediaItem
+		for _, item := range items {
+			if item.ParentIndex == int64(downloadSeason) && (episodes == nil || episodes[item.Index]) {
+				filtered = append(filtered, item)
+			}
+		}
+		if len(filtered) == 0 {
+			return fmt.Errorf("no matching episodes of %s season %d in the cache", items[0].ParentTitle, downloadSeason)
+		}
+		items = filtered
+	}
+
+	if downloadQueue {
+		q, err := queue.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load queue: %w", err)
+		}
+		added := q.Add(items)
+		if err := q.Save(); err != nil {
+			return fmt.Errorf("failed to save queue: %w", err)
+		}
+		fmt.Println(successStyle.Render(fmt.Sprintf("Added %d item(s) to queue (%d already queued). Queue now has %s.", added, len(items)-added, ui.PluralizeItems(q.Len()))))
+		return nil
+	}
+	return handleDownloadMultiple(cfg, items)
+}
+
+func runMarkWatched(cmd *cobra.Command, args []string) error {
+	app := appFrom(cmd)
+	cfg, mediaCache := app.Config, app.Cache
+	if len(mediaCache.Media) == 0 {
+		fmt.Println(warningStyle.Render("Cache is empty. Run 'goplexcli cache reindex' first."))
+		return nil
+	}
+
+	query := markShow
+	if query == "" {
+		query = strings.Join(args, " ")
+	}
+	if query == "" {
+		return fmt.Errorf("give a movie title or --show")
+	}
+	seasonSet := cmd.Flags().Changed("season")
+
+	items, err := resolveTitleArg(cfg, mediaCache.Media, query)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrCancelled) {
+			return nil
+		}
+		return err
+	}
+	if items[0].Type != "episode" && (markShow != "" || seasonSet) {
+		return fmt.Errorf("%s is a movie, not a show", items[0].FormatMediaTitle())
+	}
+	if seasonSet {
+		var filtered []*plex.MediaItem
+		for _, item := range items {
+			if item.ParentIndex == int64(markSeason) {
+				filtered = append(filtered, item)
+			}
+		}
+		if len(filtered) == 0 {
+			return fmt.Errorf("no episodes of %s season %d in the cache", items[0].ParentTitle, markSeason)
+		}
+		items = filtered
+	}
+
+	if dryRun {
+		fmt.Println(titleStyle.Render(fmt.Sprintf("Mark %s as watched", ui.PluralizeItems(len(items)))))
+		for _, item := range items {
+			fmt.Println("  " + item.FormatMediaTitle())
+		}
+		fmt.Println(warningStyle.Render("\nDry run: nothing was marked."))
+		return nil
+	}
+
+	// Plex clients per server, since a title can span servers.
+	clients := make(map[string]*plex.Client)
+	ctx := cmd.Context()
+	var marked []*plex.MediaItem
+	var failures []string
+	for i, item := range items {
+		printProgress("%s %s", progressBar(i, len(items), 20), item.FormatMediaTitle())
+		serverURL := item.ServerURL
+		if serverURL == "" {
+			serverURL = cfg.PlexURL
+		}
+		client, ok := clients[serverURL]
+		if !ok {
+			c, err := plex.New(serverURL, cfg.TokenForURL(serverURL))
+			if err != nil {
+				endProgress()
+				return fmt.Errorf("failed to create plex client: %w", err)
+			}
+			client, clients[serverURL] = c, c
+		}
+		if err := client.MarkWatched(ctx, item.RatingKey()); err != nil {
+			failures = append(failures, fmt.Sprintf("✗ %s: %v", item.FormatMediaTitle(), err))
+			continue
+		}
+		marked = append(marked, item)
+	}
+	printProgress("%s", progressBar(len(items), len(items), 20))
+	endProgress()
+	for _, f := range failures {
+		fmt.Println(errorStyle.Render(f))
+	}
+
+	// Keep the cache in step so browse and continue-watching agree without
+	// a reindex.
+	if mediaCache.MarkWatched(marked) > 0 {
+		if err := mediaCache.Save(); err != nil {
+			logging.Warn("failed to save watched state to the cache", "error", err)
+		}
+	}
+
+	if len(marked) > 0 {
+		fmt.Println(successStyle.Render(fmt.Sprintf("✓ Marked %s as watched", ui.PluralizeItems(len(marked)))))
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%d of %d item(s) could not be marked", len(failures), len(items))
+	}
+	return nil
+}
+
+// resolveTitleArg resolves a title given on the command line to cached
+// items, as export.ResolveTitle does: a show's episodes in order, or a
+// single movie. The title is matched loosely; when it fits several titles,
+// or several movies share it, the user picks one.
+func resolveTitleArg(cfg *config.Config, media []plex.MediaItem, query string) ([]*plex.MediaItem, error) {
+	titles := export.MatchTitle(media, query)
+	if len(titles) == 0 {
+		return nil, fmt.Errorf("no movie or show matching %q in the cache", query)
+	}
+	title := titles[0]
+	if len(titles) > 1 {
+		idx, err := chooseIndex(cfg, titles, "title")
+		if err != nil {
+			return nil, err
+		}
+		title = titles[idx]
+	}
+
+	items := export.ResolveTitle(media, title)
+	if items[0].Type == "episode" || len(items) == 1 {
+		return items, nil
+	}
+
+	// The same movie title on several servers, or a remake
+	labels := make([]string, len(items))
+	for i, item := range items {
+		labels[i] = item.FormatMediaTitle()
+		if item.ServerName != "" {
+			labels[i] += "  [" + item.ServerName + "]"
+		}
+	}
+	idx, err := chooseIndex(cfg, labels, "movie")
+	if err != nil {
+		return nil, err
+	}
+	return items[idx : idx+1], nil
+}
+
+// filterByFormat keeps the items in resolution res (as returned by
+// plex.NormalizeResolution; "" for any) and, if hdr is set, only HDR ones.
+func filterByFormat(media []plex.MediaItem, res string, hdr bool) []plex.MediaItem {
+	var out []plex.MediaItem
+	for _, item := range media {
+		if (res == "" || item.MatchesResolution(res)) && (!hdr || item.HDR != "") {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// filterBySource keeps the items on shared servers if shared is set, or
+// else those on the account's own servers. Servers whose ownership isn't
+// recorded count as owned.
+func filterBySource(cfg *config.Config, media []plex.MediaItem, shared bool) []plex.MediaItem {
+	var out []plex.MediaItem
+	for _, item := range media {
+		if cfg.IsSharedURL(item.ServerURL) == shared {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// cachedLibrary is a library section items in the cache came from, merged
+// across servers by title.
+type cachedLibrary struct {
+	name     string
+	movies   int
+	episodes int
+}
+
+// cachedLibraries lists the library sections recorded on media, by name.
+// Caches indexed before sections were recorded have none.
+func cachedLibraries(media []plex.MediaItem) []cachedLibrary {
+	byName := map[string]*cachedLibrary{}
+	var libs []*cachedLibrary
+	for _, item := range media {
+		if item.Library == "" {
+			continue
+		}
+		key := strings.ToLower(item.Library)
+		lib, ok := byName[key]
+		if !ok {
+			lib = &cachedLibrary{name: item.Library}
+			byName[key] = lib
+			libs = append(libs, lib)
+		}
+		if item.Type == "episode" {
+			lib.episodes++
+		} else {
+			lib.movies++
+		}
+	}
+	out := make([]cachedLibrary, len(libs))
+	for i, lib := range libs {
+		out[i] = *lib
+	}
+	slices.SortFunc(out, func(a, b cachedLibrary) int { return cmp.Compare(strings.ToLower(a.name), strings.ToLower(b.name)) })
+	return out
+}
+
+// filterByLibrary keeps the items from the library section name, given by
+// title or as "Server/Title" as in include_libraries.
+func filterByLibrary(media []plex.MediaItem, name string) []plex.MediaItem {
+	filter := plex.LibraryFilter{Include: []string{name}}
+	var out []plex.MediaItem
+	for _, item := range media {
+		if item.Library != "" && filter.Allows(item.ServerName, item.Library) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// selectLibrary asks which of libs to browse and returns its name.
+func selectLibrary(cfg *config.Config, libs []cachedLibrary) (string, error) {
+	labels := make([]string, len(libs))
+	for i, lib := range libs {
+		var counts []string
+		if lib.movies > 0 {
+			counts = append(counts, fmt.Sprintf("%d movies", lib.movies))
+		}
+		if lib.episodes > 0 {
+			counts = append(counts, fmt.Sprintf("%d episodes", lib.episodes))
+		}
+		labels[i] = lib.name + "  ·  " + strings.Join(counts, ", ")
+	}
+
+	if ui.IsAvailable(cfg.FzfPath) {
+		_, idx, err := ui.SelectWithFzf(labels, "Select library:", cfg.FzfPath)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrCancelled) {
+				return "", err
+			}
+			return "", fmt.Errorf("library selection failed: %w", err)
+		}
+		return libs[idx].name, nil
+	}
+
+	fmt.Println(infoStyle.Render("\nSelect library:"))
+	for i, label := range labels {
+		fmt.Printf("  %d. %s\n", i+1, label)
+	}
+	fmt.Printf("\nChoice (1-%d): ", len(labels))
+	var choice int
+	if _, err := fmt.Scanln(&choice); err != nil {
+		return "", fmt.Errorf("failed to read selection: %w", err)
+	}
+	if choice < 1 || choice > len(labels) {
+		return "", fmt.Errorf("invalid selection")
+	}
+	return libs[choice-1].name, nil
+}
+
+func runBrowse(cmd *cobra.Command, args []string) error {
+	// Show logo for interactive browse command
+	ui.Logo(version)
+
+	app := appFrom(cmd)
+	cfg, mediaCache := app.Config, app.Cache
+	if len(mediaCache.Media) == 0 {
+		fmt.Println(warningStyle.Render("Cache is empty. Run 'goplexcli cache reindex' first."))
+		return nil
+	}
+
+	fmt.Println(infoStyle.Render(fmt.Sprintf("Loaded %d media items from cache", len(mediaCache.Media))))
+	fmt.Println(infoStyle.Render(fmt.Sprintf("Last updated: %s", mediaCache.LastUpdated.Local().Format(time.RFC822))))
+
+	media := mediaCache.Media
+	if browseResolution != "" || browseHDR {
+		res := plex.NormalizeResolution(browseResolution)
+		if browseResolution != "" && res == "" {
+			return fmt.Errorf("unknown resolution %q (want sd, 480, 720, 1080, 4k or 8k)", browseResolution)
+		}
+		media = filterByFormat(media, res, browseHDR)
+		if len(media) == 0 {
+			fmt.Println(warningStyle.Render("No cached items match that format. Run 'goplexcli cache reindex' if the cache predates format details."))
+			return nil
+		}
+		fmt.Println(infoStyle.Render(fmt.Sprintf("%d items match the format filter", len(media))))
+	}
+	if browseSource != "" {
+		if browseSource != "mine" && browseSource != "shared" {
+			return fmt.Errorf("unknown source %q (want mine or shared)", browseSource)
+		}
+		whose := "your own"
+		if browseSource == "shared" {
+			whose = "shared"
+		}
+		media = filterBySource(cfg, media, browseSource == "shared")
+		if len(media) == 0 {
+			fmt.Println(warningStyle.Render(fmt.Sprintf("No cached items are on %s servers. Run 'goplexcli login' again if server ownership isn't recorded yet.", whose)))
+			return nil
+		}
+		fmt.Println(infoStyle.Render(fmt.Sprintf("%d items are on %s servers", len(media), whose)))
+	}
+	if browseLibrary != "" {
+		all := cachedLibraries(media)
+		media = filterByLibrary(media, browseLibrary)
+		if len(media) == 0 {
+			if len(all) == 0 {
+				fmt.Println(warningStyle.Render("The cache doesn't record libraries yet. Run 'goplexcli cache reindex' first."))
+				return nil
+			}
+			names := make([]string, len(all))
+			for i, lib := range all {
+				names[i] = lib.name
+			}
+			return fmt.Errorf("no library %q in the cache (have: %s)", browseLibrary, strings.Join(names, ", "))
+		}
+		fmt.Println(infoStyle.Render(fmt.Sprintf("%d items in %s", len(media), browseLibrary)))
+	}
+
 	// Load persistent queue
 	q, err := queue.Load()
 	if err != nil {
@@ -1111,15 +3114,12 @@ func runBrowse(cmd *cobra.Command, args []string) error {
 		fmt.Println(infoStyle.Render(fmt.Sprintf("Queue has %s from previous session", ui.PluralizeItems(q.Len()))))
 	}
 
-	// Count items with resumable progress to decide whether to offer the
-	// "Continue Watching" hub. This reflects the cache's freshness; run
-	// 'cache reindex' to refresh progress on older items.
-	continueCount := 0
-	for i := range mediaCache.Media {
-		if ui.HasResumableProgress(&mediaCache.Media[i]) {
-			continueCount++
-		}
-	}
+	libraries := cachedLibraries(media)
+
+	// Count what "Continue Watching" would list to decide whether to offer
+	// it. This reflects the cache's freshness; run 'cache sync-watched' to
+	// pick up what was watched elsewhere.
+	continueCount := len(buildContinueWatching(media))
 
 browseLoop:
 	for {
@@ -1127,7 +3127,7 @@ browseLoop:
 		var mediaType string
 		if ui.IsAvailable(cfg.FzfPath) {
 			var err error
-			mediaType, err = ui.SelectMediaTypeWithQueue(cfg.FzfPath, q.Len(), continueCount)
+			mediaType, err = ui.SelectMediaTypeWithQueue(cfg.FzfPath, q.Len(), continueCount, len(libraries))
 			if err != nil {
 				if errors.Is(err, apperrors.ErrCancelled) {
 					return nil
@@ -1137,7 +3137,7 @@ browseLoop:
 		} else {
 			// Fallback to manual selection
 			var err error
-			mediaType, err = selectMediaTypeManualWithQueue(q.Len(), continueCount)
+			mediaType, err = selectMediaTypeManualWithQueue(q.Len(), continueCount, len(libraries))
 			if err != nil {
 				return err
 			}
@@ -1159,24 +3159,37 @@ browseLoop:
 		var filteredMedia []plex.MediaItem
 		switch mediaType {
 		case "movies":
-			for _, item := range mediaCache.Media {
+			for _, item := range media {
 				if item.Type == "movie" {
 					filteredMedia = append(filteredMedia, item)
 				}
 			}
 		case "tv shows":
-			for _, item := range mediaCache.Media {
+			for _, item := range media {
 				if item.Type == "episode" {
 					filteredMedia = append(filteredMedia, item)
 				}
 			}
 		case "all":
-			filteredMedia = mediaCache.Media
+			filteredMedia = media
+		case "libraries":
+			name, err := selectLibrary(cfg, libraries)
+			if err != nil {
+				if errors.Is(err, apperrors.ErrCancelled) {
+					continue browseLoop
+				}
+				return err
+			}
+			filteredMedia = filterByLibrary(media, name)
+			// Show libraries drill down like "TV Shows".
+			if len(filteredMedia) > 0 && filteredMedia[0].Type == "episode" {
+				mediaType = "tv shows"
+			}
 		case "continue watching":
-			filteredMedia = buildContinueWatching(mediaCache.Media)
+			filteredMedia = buildContinueWatching(media)
 		case "recently added movies":
 			var movies []plex.MediaItem
-			for _, item := range mediaCache.Media {
+			for _, item := range media {
 				if item.Type == "movie" {
 					movies = append(movies, item)
 				}
@@ -1186,13 +3199,13 @@ browseLoop:
 			// Keep every episode so the show -> season -> episode drill-down
 			// below can resolve seasons and episodes; the recency limit is
 			// applied to the show list itself, not the episode pool.
-			for _, item := range mediaCache.Media {
+			for _, item := range media {
 				if item.Type == "episode" {
 					filteredMedia = append(filteredMedia, item)
 				}
 			}
 		default:
-			filteredMedia = mediaCache.Media
+			filteredMedia = media
 		}
 
 		if len(filteredMedia) == 0 {
@@ -1202,6 +3215,7 @@ browseLoop:
 
 		// For TV shows, use hierarchical drill-down: Show -> Season -> Episode
 		var selectedMediaItems []*plex.MediaItem
+		var action string // picked with a key in the media list, if any
 		isTVDrillDown := mediaType == "tv shows" || mediaType == "recently added tv shows"
 		if isTVDrillDown && ui.IsAvailable(cfg.FzfPath) {
 			// Step 1: Select TV show. "Recently Added TV Shows" orders the top
@@ -1237,7 +3251,7 @@ browseLoop:
 
 			fmt.Println(infoStyle.Render(fmt.Sprintf("\n%s has %d seasons...\n", selectedShow, len(seasons))))
 
-			selectedSeason, err := ui.SelectSeason(seasons, selectedShow, cfg.FzfPath)
+			selectedSeason, err := ui.SelectSeasonWithPreview(seasons, filteredMedia, selectedShow, cfg.FzfPath)
 			if err != nil {
 				if errors.Is(err, apperrors.ErrCancelled) {
 					continue browseLoop
@@ -1259,7 +3273,7 @@ browseLoop:
 			fmt.Println(infoStyle.Render(fmt.Sprintf("\n%s has %d episodes...\n", seasonLabel, len(episodesInSeason))))
 
 			var cancelled bool
-			selectedMediaItems, cancelled, err = selectMediaFlat(episodesInSeason, cfg, "Select episode(s) (TAB for multi-select):")
+			selectedMediaItems, action, cancelled, err = selectMediaFlat(episodesInSeason, cfg, "Select episode(s) (TAB for multi-select):")
 			if err != nil {
 				return err
 			}
@@ -1272,7 +3286,7 @@ browseLoop:
 
 			var cancelled bool
 			var err error
-			selectedMediaItems, cancelled, err = selectMediaFlat(filteredMedia, cfg, "Select media (TAB for multi-select):")
+			selectedMediaItems, action, cancelled, err = selectMediaFlat(filteredMedia, cfg, "Select media (TAB for multi-select):")
 			if err != nil {
 				return err
 			}
@@ -1286,7 +3300,7 @@ browseLoop:
 		}
 
 		// Handle user action
-		err = handleMediaAction(cfg, q, selectedMediaItems)
+		err = handleMediaAction(app, q, selectedMediaItems, action)
 		if err != nil {
 			if errors.Is(err, errAddedToQueue) {
 				// Items were added to queue, continue browsing
@@ -1302,29 +3316,22 @@ browseLoop:
 // errAddedToQueue is a sentinel error to signal that items were added to the queue
 var errAddedToQueue = errors.New("items added to queue")
 
-// handleMediaAction prompts the user for an action and dispatches to the appropriate handler.
+// handleMediaAction prompts the user for an action, unless one was already
+// picked with an inline fzf key, and dispatches to the appropriate handler.
 // Returns errAddedToQueue if items were added to the queue (caller decides whether to continue or return).
 // Returns nil for actions that complete successfully.
 // Returns other errors for failures.
-func handleMediaAction(cfg *config.Config, q *queue.Queue, selectedMediaItems []*plex.MediaItem) error {
-	// Ask what to do. "Transfer to Outplayer" is only offered when at least one
-	// Outplayer target is enabled (disabling all targets hides the action).
-	outplayerCount := len(cfg.GetEnabledOutplayerTargets())
-	var action string
+func handleMediaAction(app *App, q *queue.Queue, selectedMediaItems []*plex.MediaItem, action string) error {
+	cfg := app.Config
 	var err error
-	if ui.IsAvailable(cfg.FzfPath) {
-		action, err = ui.PromptActionWithQueue(cfg.FzfPath, len(selectedMediaItems), q.Len(), outplayerCount)
+	if action == "" {
+		action, err = promptMediaAction(cfg, q, selectedMediaItems)
 		if err != nil {
 			if errors.Is(err, apperrors.ErrCancelled) {
 				return nil
 			}
 			return err
 		}
-	} else {
-		action, err = promptActionManualWithQueue(len(selectedMediaItems), q.Len(), outplayerCount)
-		if err != nil {
-			return err
-		}
 	}
 
 	// "More..." opens a submenu with the less-common playback/streaming options.
@@ -1347,7 +3354,25 @@ func handleMediaAction(cfg *config.Config, q *queue.Queue, selectedMediaItems []
 
 	switch action {
 	case "watch":
-		return handleWatchMultiple(cfg, selectedMediaItems)
+		return handleWatchMultiple(app, selectedMediaItems)
+	case "watch season":
+		return handleWatchSeason(app, selectedMediaItems)
+	case "watch local":
+		watchLocal = true
+		defer func() { watchLocal = false }()
+		return handleWatchMultiple(app, selectedMediaItems)
+	case "watch preset":
+		preset, err := selectPlaybackPreset(cfg)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrCancelled) {
+				return nil
+			}
+			return err
+		}
+		prev := playbackPreset
+		playbackPreset = preset
+		defer func() { playbackPreset = prev }()
+		return handleWatchMultiple(app, selectedMediaItems)
 	case "download":
 		return handleDownloadMultiple(cfg, selectedMediaItems)
 	case "transfer":
@@ -1382,32 +3407,145 @@ func handleMediaAction(cfg *config.Config, q *queue.Queue, selectedMediaItems []
 			fmt.Println(successStyle.Render(fmt.Sprintf("Added %d item(s) to queue. Queue now has %s.", added, ui.PluralizeItems(q.Len()))))
 		}
 		return errAddedToQueue
+	case "delete":
+		return handleDelete(app, selectedMediaItems)
 	case "stream":
 		if len(selectedMediaItems) > 1 {
 			fmt.Println(warningStyle.Render("Note: Stream only supports single selection, using first item"))
 		}
 		return handleStream(cfg, selectedMediaItems[0])
+	case "open imdb":
+		return handleOpenIMDb(selectedMediaItems)
 	default:
 		return nil
 	}
 }
 
-func handleWatchMultiple(cfg *config.Config, mediaItems []*plex.MediaItem) error {
+// promptMediaAction asks what to do with the selected items.
+// "Transfer to Outplayer" is only offered when at least one Outplayer
+// target is enabled (disabling all targets hides the action).
+func promptMediaAction(cfg *config.Config, q *queue.Queue, selectedMediaItems []*plex.MediaItem) (string, error) {
+	outplayerCount := len(cfg.GetEnabledOutplayerTargets())
+	localCount := countLocalCopies(selectedMediaItems)
+	seasonCount := export.CountSeasons(selectedMediaItems)
+	if ui.IsAvailable(cfg.FzfPath) {
+		return ui.PromptActionWithQueue(cfg.FzfPath, len(selectedMediaItems), q.Len(), outplayerCount, localCount, seasonCount)
+	}
+	return promptActionManualWithQueue(len(selectedMediaItems), q.Len(), outplayerCount, localCount, seasonCount)
+}
+
+// handleWatchSeason plays every episode of the seasons the selected
+// episodes belong to, in order, as one playlist, so the tracker reports
+// each episode as the player moves through them.
+func handleWatchSeason(app *App, selected []*plex.MediaItem) error {
+	mediaCache, err := app.MediaCache()
+	if err != nil {
+		return err
+	}
+	episodes := export.SeasonEpisodes(mediaCache.Media, selected)
+	if len(episodes) == 0 {
+		return fmt.Errorf("no episodes of the selected season(s) in the cache")
+	}
+	return handleWatchMultiple(app, episodes)
+}
+
+// handleOpenIMDb opens each item's IMDb page in the browser, printing the
+// link instead when no browser can be launched.
+func handleOpenIMDb(items []*plex.MediaItem) error {
+	for _, item := range items {
+		link := item.IMDbURL()
+		if link == "" {
+			fmt.Println(warningStyle.Render(fmt.Sprintf("No IMDb ID for %s (run 'goplexcli cache reindex' to fetch external IDs)", item.FormatMediaTitle())))
+			continue
+		}
+		if err := openURL(link); err != nil {
+			logging.Warn("failed to open browser", "url", link, "error", err)
+			fmt.Println(infoStyle.Render(fmt.Sprintf("%s: %s", item.FormatMediaTitle(), link)))
+			continue
+		}
+		fmt.Println(successStyle.Render(fmt.Sprintf("✓ Opened %s on IMDb", item.FormatMediaTitle())))
+	}
+	return nil
+}
+
+// openURL opens u with the platform's default handler.
+func openURL(u string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", u)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", u)
+	default:
+		cmd = exec.Command("xdg-open", u)
+	}
+	return cmd.Run()
+}
+
+// countLocalCopies returns how many of items have a downloaded copy on disk.
+func countLocalCopies(items []*plex.MediaItem) int {
+	index, err := download.LoadIndex()
+	if err != nil {
+		logging.Warn("failed to load downloads index", "error", err)
+		return 0
+	}
+	n := 0
+	for _, item := range items {
+		if _, ok := index.Local(item); ok {
+			n++
+		}
+	}
+	return n
+}
+
+func handleWatchMultiple(app *App, mediaItems []*plex.MediaItem) error {
+	cfg := app.Config
 	if len(mediaItems) == 0 {
 		return fmt.Errorf("no media items provided")
 	}
 
-	// Check if MPV is available
-	if !player.IsAvailable(cfg.MPVPath) {
-		return fmt.Errorf("mpv is not installed. Please install mpv to watch media")
+	// Check if the configured player is available. Audio-only playback is
+	// mpv's --no-video, whatever the configured player.
+	playerName, playerPath := cfg.PlayerName(), cfg.PlayerPath()
+	if audioOnly {
+		playerName, playerPath = "mpv", cfg.MPVPath
+	}
+	if !player.IsPlayerAvailable(playerName, playerPath) {
+		return fmt.Errorf("%s is not installed. Please install %s to watch media", playerName, playerName)
 	}
 
 	fmt.Println(infoStyle.Render(fmt.Sprintf("\nPreparing to play %d items...", len(mediaItems))))
 
 	// Create Plex client
-	client, err := plex.New(cfg.PlexURL, cfg.TokenForURL(cfg.PlexURL))
+	client, err := newStreamClient(cfg, cfg.PlexURL)
 	if err != nil {
-		return fmt.Errorf("failed to create plex client: %w", err)
+		return err
+	}
+
+	// --chapter starts a single item at a chapter instead of offering to
+	// resume. Plex's chapter list gives a start time that works with every
+	// player; for files Plex lists no chapters for, mpv can still jump to
+	// one over IPC once playing.
+	chapterStartSec, ipcChapter := -1, -1
+	if watchChapter > 0 {
+		if len(mediaItems) != 1 {
+			return fmt.Errorf("--chapter needs a single item, not %d", len(mediaItems))
+		}
+		chapters, err := client.GetChapters(context.Background(), mediaItems[0].RatingKey())
+		if err != nil {
+			return fmt.Errorf("failed to get chapters: %w", err)
+		}
+		for _, ch := range chapters {
+			if ch.Index == watchChapter {
+				chapterStartSec = ch.StartMs / 1000
+			}
+		}
+		if chapterStartSec < 0 {
+			if len(chapters) > 0 || !player.SupportsIPC(playerName) {
+				return fmt.Errorf("%s has no chapter %d (it has %d)", mediaItems[0].FormatMediaTitle(), watchChapter, len(chapters))
+			}
+			ipcChapter = watchChapter - 1
+		}
 	}
 
 	// Check for items with progress
@@ -1420,7 +3558,9 @@ func handleWatchMultiple(cfg *config.Config, mediaItems []*plex.MediaItem) error
 
 	// Determine start positions based on user choice
 	startPositions := make([]int, len(mediaItems))
-	if len(itemsWithProgress) > 0 {
+	if chapterStartSec >= 0 {
+		startPositions[0] = chapterStartSec
+	} else if len(itemsWithProgress) > 0 && ipcChapter < 0 {
 		if len(itemsWithProgress) == 1 && len(mediaItems) == 1 {
 			// Single item with progress - show simple resume prompt
 			choice, err := ui.PromptResume(ui.ResumePromptOptions{
@@ -1485,32 +3625,72 @@ func handleWatchMultiple(cfg *config.Config, mediaItems []*plex.MediaItem) error
 		}
 	}
 
-	// Get stream URLs for all items
-	var streamURLs []string
+	// With "Play Local File", items with a downloaded copy play from disk;
+	// progress is still reported to Plex.
+	var index *download.Index
+	if watchLocal {
+		if index, err = download.LoadIndex(); err != nil {
+			return fmt.Errorf("failed to load downloads index: %w", err)
+		}
+	}
+
+	// Get stream URLs for all items, with the titles and sidecar subtitles
+	// the player shows for them
+	var streamURLs, titles []string
+	var subFiles [][]string
 	for i, media := range mediaItems {
-		fmt.Printf("\r\x1b[K%s [%d/%d] %s",
+		printProgress("%s [%d/%d] %s",
 			infoStyle.Render("Getting stream URLs"),
 			i+1,
 			len(mediaItems),
 			media.FormatMediaTitle(),
 		)
 
-		streamURL, err := client.GetStreamURL(media.Key)
+		streamURL, err := playbackURL(client, media, index)
 		if err != nil {
-			fmt.Println()
+			endProgress()
 			return fmt.Errorf("failed to get stream URL for %s: %w", media.FormatMediaTitle(), err)
 		}
+		subs, err := client.SubtitleURLs(context.Background(), media.RatingKey())
+		if err != nil {
+			logging.Debug("no subtitle files", "title", media.FormatMediaTitle(), "error", err)
+		}
 		streamURLs = append(streamURLs, streamURL)
+		titles = append(titles, media.PlayerTitle())
+		subFiles = append(subFiles, subs)
+	}
+	endProgress()
+
+	// Set up progress tracking: mpv (and IINA, which embeds it, and custom
+	// players that declare supports_ipc) over a Unix socket (macOS/Linux)
+	// or named pipe (Windows), VLC over its HTTP interface on localhost.
+	// Other custom players play untracked.
+	opts := player.PlaybackOptions{Titles: titles, SubFiles: subFiles}
+	var playerClient progress.Connector
+	if playerName == "vlc" {
+		port, password, err := progress.GenerateVLCEndpoint()
+		if err != nil {
+			return err
+		}
+		opts.HTTPPort, opts.HTTPPassword = port, password
+		playerClient = progress.NewVLCClient(port, password)
+	} else if player.SupportsIPC(playerName) {
+		opts.SocketPath = progress.GenerateIPCPath()
+		playerClient = progress.NewMPVClient(opts.SocketPath)
+
+		// Clean up socket file when done (Unix only, no-op on Windows)
+		defer os.Remove(opts.SocketPath)
+	}
+	tracker := progress.NewTracker(mediaItems, playerClient, client)
+	// A play queue lets other Plex apps show the session's up-next items.
+	if err := tracker.CreatePlayQueue(context.Background()); err != nil {
+		logging.Debug("no play queue", "error", err)
+	}
+	if cfg.DiscordPresence {
+		if stop := startDiscordPresence(cfg, tracker); stop != nil {
+			defer stop()
+		}
 	}
-	fmt.Println()
-
-	// Set up progress tracking using Unix socket (macOS/Linux) or named pipe (Windows)
-	socketPath := progress.GenerateIPCPath()
-	mpvClient := progress.NewMPVClient(socketPath)
-	tracker := progress.NewTracker(mediaItems, mpvClient, client)
-
-	// Clean up socket file when done (Unix only, no-op on Windows)
-	defer os.Remove(socketPath)
 
 	// Prepare playback options
 	// Note: MPV's --start flag only applies to the first file in a playlist.
@@ -1534,48 +3714,142 @@ func handleWatchMultiple(cfg *config.Config, mediaItems []*plex.MediaItem) error
 		}
 	}
 
-	opts := player.PlaybackOptions{
-		SocketPath: socketPath,
-		StartPos:   startPos,
+	if playbackPreset != "" && playerName != "mpv" && playerName != "iina" {
+		return fmt.Errorf("playback presets are mpv options and can't be used with %s", playerName)
+	}
+	presetArgs, err := player.ResolvePreset(playbackPreset, cfg.PlaybackPresets)
+	if err != nil {
+		return err
+	}
+	if playbackPreset != "" {
+		fmt.Println(infoStyle.Render(fmt.Sprintf("Preset: %s", playbackPreset)))
+	}
+
+	stillsDir, err := cfg.ResolveScreenshotDir(screenshotDir)
+	if err != nil {
+		return err
+	}
+	if stillsDir != "" && !audioOnly {
+		if err := os.MkdirAll(stillsDir, 0o755); err != nil {
+			return fmt.Errorf("failed to create screenshot dir: %w", err)
+		}
+	}
+
+	opts.StartPos = startPos
+	opts.ExtraArgs = presetArgs
+	if audioOnly {
+		opts.ExtraArgs = append(opts.ExtraArgs, "--no-video")
 	}
 
 	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Starting playback of %d items...", len(mediaItems))))
-	fmt.Println(infoStyle.Render("Use 'n' in MPV to skip to next item"))
+	if !audioOnly {
+		fmt.Println(infoStyle.Render(fmt.Sprintf("Use 'n' in %s to skip to next item", strings.ToUpper(playerName))))
+	}
 
-	// Create context that cancels when MPV exits
+	// Create context that cancels when the player exits
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Start MPV in goroutine
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	defer ownSignals()()
+
+	// Start the player in goroutine
 	errCh := make(chan error, 1)
 	go func() {
-		_, err := player.PlayMultipleWithOptions(streamURLs, cfg.MPVPath, opts)
-		cancel() // Cancel context when MPV exits (stops Connect retries)
+		_, err := player.PlayMultipleWith(playerName, playerPath, streamURLs, opts)
+		cancel() // Cancel context when the player exits (stops Connect retries)
 		errCh <- err
 	}()
 
-	// Connect to MPV IPC and start tracking (with context for early cancellation)
-	tracking := false
-	if err := mpvClient.ConnectWithContext(ctx); err != nil {
-		// Only show warning if it wasn't due to MPV exiting
+	// Connect to the player and start tracking (with context for early cancellation)
+	tracking, autoplaying := false, false
+	// The now-playing line and --audio-only's terminal controls;
+	// stopTerminal hands the terminal back before anything else is printed.
+	stopTerminal := func() {}
+	if playerClient == nil {
+		fmt.Println(infoStyle.Render(fmt.Sprintf("Note: %s doesn't support progress tracking", playerName)))
+	} else if err := playerClient.ConnectWithContext(ctx); err != nil {
+		// Only show warning if it wasn't due to the player exiting
 		if ctx.Err() == nil {
 			fmt.Println(warningStyle.Render(fmt.Sprintf("Note: Progress tracking unavailable: %v", err)))
 		}
 	} else {
-		defer func() { _ = mpvClient.Close() }()
+		defer func() { _ = playerClient.Close() }()
+		// IINA handles keys itself, so only mpv gets the skip hint.
+		if cfg.SkipIntros || playerName == "mpv" {
+			tracker.EnableMarkers(progress.PlexMarkers(ctx, client), cfg.SkipIntros)
+		}
+		if mpv, ok := playerClient.(*progress.MPVClient); ok && cfg.AutoplayNext {
+			autoplaying = autoplayNext(app, client, tracker, mpv, index)
+		}
 		tracker.Start(ctx, 10*time.Second)
 		tracking = true
+
+		if mpv, ok := playerClient.(*progress.MPVClient); ok && ipcChapter >= 0 {
+			go jumpToChapter(ctx, mpv, ipcChapter)
+		}
+		if mpv, ok := playerClient.(*progress.MPVClient); ok && cfg.RemoteControl {
+			startRemoteControl(ctx, cfg, mpv)
+		}
+		if mpv, ok := playerClient.(*progress.MPVClient); ok && stillsDir != "" && !audioOnly {
+			if err := mpv.BindScreenshotKey(stillsDir); err != nil {
+				logging.Warn("failed to bind screenshot key", "error", err)
+			} else {
+				fmt.Println(infoStyle.Render(fmt.Sprintf("Press %s in %s to save a still to %s", progress.ScreenshotKey, playerName, stillsDir)))
+			}
+		}
+		var stops []func()
+		if mpv, ok := playerClient.(*progress.MPVClient); ok && audioOnly {
+			fmt.Println(infoStyle.Render(ui.TransportHelp))
+			stops = append(stops, goUntilStopped(ctx, func(ctx context.Context) { ui.TransportControls(ctx, mpv) }))
+		}
+		if ui.Interactive() {
+			stops = append(stops, goUntilStopped(ctx, func(ctx context.Context) { showNowPlaying(ctx, tracker, playerClient) }))
+		}
+		stopTerminal = func() {
+			for _, stop := range stops {
+				stop()
+			}
+		}
 	}
 
-	// Wait for playback to finish
-	playbackErr := <-errCh
+	// Wait for playback to finish, or for Ctrl-C or SIGTERM, which would
+	// otherwise kill goplexcli before it reports the stop and leave a stale
+	// session on the Plex dashboard.
+	var playbackErr error
+	var interrupt os.Signal
+	select {
+	case playbackErr = <-errCh:
+	case interrupt = <-sigCh:
+	}
+	stopTerminal()
 
 	// Stop tracking and flush the final position into the local cache so the
 	// just-watched item appears in "Continue Watching" immediately, without
 	// waiting for a 'cache reindex'.
 	if tracking {
 		tracker.Stop()
-		persistPlaybackProgress(tracker)
+		persistPlaybackProgress(app, tracker)
+	}
+	recordPlaybackHistory(tracker, playerName, startPos*1000)
+
+	if interrupt != nil {
+		// The tracker has sent its final "stopped"; take the player down
+		// with us and clean up what deferred calls would have.
+		if mpv, ok := playerClient.(*progress.MPVClient); ok {
+			_ = mpv.Quit()
+		}
+		if playerClient != nil {
+			_ = playerClient.Close()
+		}
+		if opts.SocketPath != "" {
+			os.Remove(opts.SocketPath)
+		}
+		ui.RemovePreviewFiles()
+		fmt.Println(warningStyle.Render("\nPlayback stopped"))
+		os.Exit(signalExitCode(interrupt))
 	}
 
 	if playbackErr != nil {
@@ -1583,701 +3857,829 @@ func handleWatchMultiple(cfg *config.Config, mediaItems []*plex.MediaItem) error
 	}
 
 	fmt.Println(successStyle.Render("✓ Playback finished"))
+	played := mediaItems[0].FormatMediaTitle()
+	if len(mediaItems) > 1 {
+		played = fmt.Sprintf("%s and %d more", played, len(mediaItems)-1)
+	}
+	sendWebhook(cfg, notify.Event{Name: notify.EventPlaybackFinished, Title: "Playback finished", Message: played, Time: time.Now()})
+
+	if tracking && !autoplaying {
+		if next := offerNextEpisode(app, tracker); next != nil {
+			// --chapter was for what was asked for, not what follows.
+			watchChapter = 0
+			return handleWatchMultiple(app, []*plex.MediaItem{next})
+		}
+	}
 	return nil
 }
 
-// persistPlaybackProgress writes the playback positions captured during this
-// session back into the local cache, keyed by media key. This makes
-// freshly-watched items appear in the "Continue Watching" hub immediately,
-// rather than only after a 'cache reindex'. Best-effort: cache write failures
-// are logged but do not fail playback.
-func persistPlaybackProgress(tracker *progress.Tracker) {
-	offsets := tracker.Progress()
-	if len(offsets) == 0 {
-		return
+// playbackURL returns what the player should open for media: its
+// downloaded copy when index has one, otherwise its Plex stream URL.
+func playbackURL(client *plex.Client, media *plex.MediaItem, index *download.Index) (string, error) {
+	if index != nil {
+		if local, ok := index.Local(media); ok {
+			return local.Path, nil
+		}
 	}
+	return getStreamURL(client, media.Key)
+}
 
-	mediaCache, err := cache.Load()
+// autoplayNext makes mpv play on through a show (see autoplay_next): when
+// the last item in its playlist has been watched, the show's next episode
+// is appended to the playlist and tracked like the rest. It reports
+// whether autoplay is on, which needs the cache.
+func autoplayNext(app *App, client *plex.Client, tracker *progress.Tracker, mpv *progress.MPVClient, index *download.Index) bool {
+	mediaCache, err := app.MediaCache()
 	if err != nil {
-		logging.Warn("failed to load cache to persist playback progress", "error", err)
-		return
+		logging.Warn("failed to load cache for autoplay", "error", err)
+		return false
 	}
+	tracker.OnFinished(func(i int, item *plex.MediaItem) {
+		if i != tracker.Len()-1 {
+			return
+		}
+		next := export.EpisodeAfter(mediaCache.Media, item)
+		if next == nil {
+			return
+		}
+		// Getting the stream URL takes a request; keep the tracker polling.
+		go func() {
+			streamURL, err := playbackURL(client, next, index)
+			if err != nil {
+				logging.Warn("failed to get the next episode for autoplay", "title", next.FormatMediaTitle(), "error", err)
+				return
+			}
+			tracker.AddItem(next)
+			if err := mpv.AppendFile(streamURL); err != nil {
+				logging.Warn("failed to queue the next episode in mpv", "title", next.FormatMediaTitle(), "error", err)
+				return
+			}
+			_ = mpv.ShowText("Up next: "+next.FormatMediaTitle(), 5*time.Second)
+		}()
+	})
+	return true
+}
 
-	if !mediaCache.ApplyOffsets(offsets) {
-		return
+// offerNextEpisode offers to play the episode after the one playback ended
+// on, if that was watched to the end, with a countdown (see
+// ui.PromptCountdown). It returns the episode to play, or nil.
+func offerNextEpisode(app *App, tracker *progress.Tracker) *plex.MediaItem {
+	last := tracker.CurrentMedia()
+	if last == nil || last.Type != "episode" || last.Duration <= 0 {
+		return nil
 	}
-
-	if err := mediaCache.Save(); err != nil {
-		logging.Warn("failed to persist playback progress to cache", "error", err)
+	if float64(tracker.Progress()[last.Key]) < 0.95*float64(last.Duration) {
+		return nil
 	}
-}
 
-func handleDownloadMultiple(cfg *config.Config, mediaItems []*plex.MediaItem) error {
-	if len(mediaItems) == 0 {
-		return fmt.Errorf("no media items provided")
+	// persistPlaybackProgress has brought the App's cache up to date, so
+	// the next episode's resume position is current.
+	mediaCache, err := app.MediaCache()
+	if err != nil {
+		logging.Warn("failed to load cache to find the next episode", "error", err)
+		return nil
 	}
-
-	// Check if rclone is available
-	if !download.IsAvailable(cfg.RclonePath) {
-		return fmt.Errorf("rclone is not installed. Please install rclone to download media")
+	next := export.EpisodeAfter(mediaCache.Media, last)
+	if next == nil {
+		return nil
 	}
+	prompt := fmt.Sprintf("Play next episode? (S%02dE%02d) [Enter]", next.ParentIndex, next.Index)
+	if !ui.PromptCountdown(infoStyle.Render(prompt), 10*time.Second) {
+		return nil
+	}
+	return next
+}
 
-	fmt.Println(infoStyle.Render(fmt.Sprintf("\nPreparing to download %d items...", len(mediaItems))))
+func runServe(cmd *cobra.Command, args []string) error {
+	app := appFrom(cmd)
+	cfg, mediaCache := app.Config, app.Cache
+	if len(mediaCache.Media) == 0 {
+		fmt.Println(warningStyle.Render("Cache is empty. Run 'goplexcli cache reindex' first."))
+		return nil
+	}
 
-	// Collect rclone paths and validate
-	var rclonePaths []string
-	for _, media := range mediaItems {
-		if media.RclonePath == "" {
-			fmt.Println(warningStyle.Render(fmt.Sprintf("⚠ Skipping %s (no rclone path)", media.FormatMediaTitle())))
-			continue
+	indices, err := ui.SelectMediaWithPreview(mediaCache.Media, "Publish (TAB to select several):", cfg.FzfPath, cfg.PlexURL, cfg.TokenForURL(cfg.PlexURL))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrCancelled) {
+			return nil
 		}
-		rclonePaths = append(rclonePaths, media.RclonePath)
-		fmt.Println(infoStyle.Render(fmt.Sprintf("  - %s", media.FormatMediaTitle())))
+		return fmt.Errorf("selection failed: %w", err)
 	}
-
-	if len(rclonePaths) == 0 {
-		return fmt.Errorf("no valid rclone paths available")
+	if len(indices) == 0 {
+		return nil
 	}
 
-	// Resolve destination directory (--dest flag > config download_dir > cwd)
-	destDir, err := cfg.ResolveDownloadDir(downloadDest)
+	server, err := stream.NewServer(servePort)
 	if err != nil {
-		return fmt.Errorf("failed to resolve download directory: %w", err)
+		return fmt.Errorf("failed to create stream server: %w", err)
+	}
+	hlsErr := server.EnableHLS(stream.HLSOptions{
+		FFmpegPath:     cfg.FFmpegPath,
+		TranscodeVideo: cfg.HLSTranscode,
+	})
+	accessToken, err := secureStreamServer(cfg, server)
+	if err != nil {
+		return err
+	}
+	if cfg.StreamProxy {
+		server.EnableProxy()
 	}
 
-	// Handle dry-run mode
-	if dryRun {
-		fmt.Println(warningStyle.Render("\n[DRY RUN] Would download the following files:"))
-		for _, path := range rclonePaths {
-			fmt.Println(infoStyle.Render(fmt.Sprintf("  - %s", path)))
+	// Plex clients per server, since a multi-server cache mixes items.
+	clients := make(map[string]*plex.Client)
+	var ids []string
+	for _, idx := range indices {
+		item := &mediaCache.Media[idx]
+		serverURL := item.ServerURL
+		if serverURL == "" {
+			serverURL = cfg.PlexURL
 		}
-		fmt.Println(warningStyle.Render(fmt.Sprintf("\n[DRY RUN] Total: %d files to %s", len(rclonePaths), destDir)))
-		return nil
+		client, ok := clients[serverURL]
+		if !ok {
+			if client, err = newStreamClient(cfg, serverURL); err != nil {
+				return err
+			}
+			clients[serverURL] = client
+		}
+		streamURL, err := getStreamURL(client, item.Key)
+		if err != nil {
+			return fmt.Errorf("failed to get stream URL for %s: %w", item.FormatMediaTitle(), err)
+		}
+		ids = append(ids, server.PublishStream(item, streamURL, client.ServerURL(), cfg.TokenForURL(serverURL)))
+		fmt.Println(successStyle.Render("✓ Published " + item.FormatMediaTitle()))
 	}
 
-	// Ensure the destination directory exists
-	if err := os.MkdirAll(destDir, 0755); err != nil {
-		return fmt.Errorf("failed to create download directory %q: %w", destDir, err)
+	webURL := fmt.Sprintf("%s://%s:%d", server.Scheme(), stream.GetLocalIP(), servePort)
+	if serveQR {
+		for _, id := range ids {
+			item, ok := server.GetStream(id)
+			if !ok {
+				continue
+			}
+			fmt.Println(infoStyle.Render("\n" + item.Title))
+			printQR(publishedURL(server, webURL, item))
+		}
 	}
 
-	fmt.Println(successStyle.Render(fmt.Sprintf("\n✓ Starting download of %d items to %s...", len(rclonePaths), destDir)))
-
-	// Download with rclone
-	ctx := context.Background()
-	if err := download.DownloadMultiple(ctx, rclonePaths, destDir, cfg.RclonePath); err != nil {
-		return fmt.Errorf("download failed: %w", err)
+	fmt.Println()
+	uiURL := webURL
+	if accessToken != "" {
+		uiURL += "/?" + stream.TokenParam + "=" + url.QueryEscape(accessToken)
+	}
+	printQR(uiURL)
+	fmt.Println(successStyle.Render("Web UI: ") + infoStyle.Render(uiURL))
+	if accessToken != "" {
+		fmt.Println(warningStyle.Render("Access token: ") + accessToken)
 	}
+	fmt.Println(successStyle.Render("Playlist: ") + infoStyle.Render(webURL+server.PlaylistPath()))
+	if hlsErr != nil {
+		fmt.Println(infoStyle.Render(fmt.Sprintf("(HLS for browsers/TVs unavailable: %v)", hlsErr)))
+	}
+	if fp := server.Fingerprint(); fp != "" && cfg.StreamCertFile == "" {
+		fmt.Println(infoStyle.Render("Self-signed certificate, SHA-256 fingerprint: " + fp))
+	}
+	fmt.Println(infoStyle.Render("\nOther devices can also run 'goplexcli stream'. Press Ctrl+C to stop.\n"))
 
-	fmt.Println(successStyle.Render("✓ All downloads complete"))
+	if err := server.Start(app.SignalContext()); err != nil {
+		return fmt.Errorf("stream server failed: %w", err)
+	}
+	fmt.Println(warningStyle.Render("Stream server stopped"))
 	return nil
 }
 
-// webdavDest is a unified WebDAV transfer destination: either an explicitly
-// configured target (its own credentials) or a gowebdav server discovered on
-// the LAN (shared WebDAVUser/WebDAVPass credentials).
-type webdavDest struct {
-	label   string // shown in the picker, e.g. "office-nas (http://192.168.1.50:5005)"
-	baseURL string
-	user    string
-	pass    string
-	dir     string
-	vendor  string // rclone WebDAV vendor; "" = "other"
-	shared  bool   // true when using the shared gowebdav credentials
-}
+// partySyncInterval is how often a watch-party host broadcasts its player's
+// state, and partyTolerance how far, in seconds, a guest may drift before
+// it seeks.
+const (
+	partySyncInterval = 500 * time.Millisecond
+	partyTolerance    = 1.0
+)
 
-// handleTransferToWebDAV lets the user pick a WebDAV destination — explicitly
-// configured targets (goplexcli webdav add) plus gowebdav servers discovered on
-// the LAN via mDNS — and pushes the selected media to it using rclone's WebDAV
-// backend. Configured targets carry their own credentials; discovered servers
-// use the shared ones stored in config (WebDAVUser/Pass).
-func handleTransferToWebDAV(cfg *config.Config, mediaItems []*plex.MediaItem) error {
-	if len(mediaItems) == 0 {
-		return fmt.Errorf("no media items provided")
+func runPartyHost(cmd *cobra.Command, args []string) error {
+	app := appFrom(cmd)
+	cfg, mediaCache := app.Config, app.Cache
+	if len(mediaCache.Media) == 0 {
+		fmt.Println(warningStyle.Render("Cache is empty. Run 'goplexcli cache reindex' first."))
+		return nil
 	}
-
-	// rclone is used for the actual transfer (same requirement as downloads).
-	if !download.IsAvailable(cfg.RclonePath) {
-		return fmt.Errorf("rclone is not installed. Please install rclone to transfer media")
+	if !player.SupportsIPC(cfg.PlayerName()) {
+		return fmt.Errorf("watch parties need mpv, IINA or a player with supports_ipc, not %s", cfg.PlayerName())
 	}
 
-	// Collect rclone source paths and validate (mirrors handleDownloadMultiple).
-	var rclonePaths []string
-	for _, media := range mediaItems {
-		if media.RclonePath == "" {
-			fmt.Println(warningStyle.Render(fmt.Sprintf("⚠ Skipping %s (no rclone path)", media.FormatMediaTitle())))
-			continue
+	indices, err := ui.SelectMediaWithPreview(mediaCache.Media, "Watch party:", cfg.FzfPath, cfg.PlexURL, cfg.TokenForURL(cfg.PlexURL))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrCancelled) {
+			return nil
 		}
-		rclonePaths = append(rclonePaths, media.RclonePath)
+		return fmt.Errorf("selection failed: %w", err)
 	}
-	if len(rclonePaths) == 0 {
-		return fmt.Errorf("no valid rclone paths available")
+	if len(indices) == 0 {
+		return nil
 	}
+	item := &mediaCache.Media[indices[0]]
 
-	// Configured targets are always available; discovered ones depend on what
-	// is currently on the LAN.
-	var dests []webdavDest
-	for _, t := range cfg.GetEnabledWebDAVTargets() {
-		dests = append(dests, webdavDest{
-			label:   fmt.Sprintf("%s (%s)", t.Name, t.URL),
-			baseURL: t.URL,
-			user:    t.User,
-			pass:    t.Pass,
-			dir:     t.Dir,
-			vendor:  t.Vendor,
-		})
+	serverURL := item.ServerURL
+	if serverURL == "" {
+		serverURL = cfg.PlexURL
+	}
+	client, err := newStreamClient(cfg, serverURL)
+	if err != nil {
+		return err
+	}
+	streamURL, err := getStreamURL(client, item.Key)
+	if err != nil {
+		return fmt.Errorf("failed to get stream URL: %w", err)
 	}
 
-	// Discover gowebdav servers advertised on the LAN.
-	fmt.Println(infoStyle.Render("\nSearching for gowebdav servers on the local network..."))
-	ctx := context.Background()
-	targets, err := webdav.Discover(ctx, 3*time.Second)
+	server, err := stream.NewServer(stream.DefaultPort)
 	if err != nil {
-		// Configured targets are still usable when mDNS discovery breaks
-		// (e.g. multicast blocked); only fail outright if there is nothing else.
-		if len(dests) == 0 {
-			return fmt.Errorf("discovery failed: %w", err)
-		}
-		fmt.Println(warningStyle.Render(fmt.Sprintf("⚠ Discovery failed: %v", err)))
+		return fmt.Errorf("failed to create stream server: %w", err)
 	}
-	for _, t := range targets {
-		baseURL := t.BaseURL()
-		if baseURL == "" {
-			continue
-		}
-		dests = append(dests, webdavDest{
-			label:   fmt.Sprintf("%s (%s, discovered)", t.Name, baseURL),
-			baseURL: baseURL,
-			user:    cfg.WebDAVUser,
-			pass:    cfg.WebDAVPass,
-			dir:     cfg.WebDAVDir,
-			shared:  true,
-		})
+	accessToken, err := secureStreamServer(cfg, server)
+	if err != nil {
+		return err
+	}
+	if cfg.StreamProxy {
+		server.EnableProxy()
 	}
+	server.EnableParty(server.PublishStream(item, streamURL, client.ServerURL(), cfg.TokenForURL(serverURL)))
 
-	if len(dests) == 0 {
-		fmt.Println(warningStyle.Render("No WebDAV destinations available"))
-		fmt.Println(infoStyle.Render("\nAdd a WebDAV server with:"))
-		fmt.Println(infoStyle.Render("  goplexcli webdav add"))
-		fmt.Println(infoStyle.Render("\nOr run a gowebdav server on another machine:"))
-		fmt.Println(infoStyle.Render("  gowebdav -name <label> -username <user> -password <pass>"))
-		fmt.Println(infoStyle.Render("(use the same credentials you set in goplexcli config)"))
-		return nil
+	ctx, cancel := context.WithCancel(app.SignalContext())
+	defer cancel()
+	serverErr := make(chan error, 1)
+	go func() { serverErr <- server.Start(ctx) }()
+
+	fmt.Println(successStyle.Render("✓ Hosting a watch party for " + item.FormatMediaTitle()))
+	fmt.Println(infoStyle.Render("Guests join with 'goplexcli party join'. Playback starts paused; press space when everyone is in."))
+	if accessToken != "" {
+		fmt.Println(warningStyle.Render("Access token: ") + accessToken)
 	}
 
-	fmt.Println(successStyle.Render(fmt.Sprintf("✓ %d destination(s) available\n", len(dests))))
+	err = playInParty(ctx, cfg, streamURL, item.PlayerTitle(), 0, func(ctx context.Context, mpv *progress.MPVClient) {
+		server.HostParty(ctx, mpv, partySyncInterval)
+	})
+	cancel()
+	if serr := <-serverErr; serr != nil && err == nil {
+		err = fmt.Errorf("stream server failed: %w", serr)
+	}
+	return err
+}
 
-	// Select a destination if more than one is available.
-	var selected webdavDest
-	if len(dests) == 1 {
-		selected = dests[0]
-		fmt.Println(infoStyle.Render("Transferring to: " + selected.label))
-	} else {
-		var names []string
-		for _, d := range dests {
-			names = append(names, d.label)
-		}
-		if ui.IsAvailable(cfg.FzfPath) {
-			_, idx, err := ui.SelectWithFzf(names, "Select WebDAV destination:", cfg.FzfPath)
-			if err != nil {
-				if errors.Is(err, apperrors.ErrCancelled) {
-					return nil
-				}
-				return fmt.Errorf("destination selection failed: %w", err)
-			}
-			selected = dests[idx]
-		} else {
-			fmt.Println(infoStyle.Render("Available destinations:"))
-			for i, name := range names {
-				fmt.Printf("  %d. %s\n", i+1, name)
-			}
-			fmt.Print("\nSelect destination number: ")
-			var choice int
-			if _, err := fmt.Scanln(&choice); err != nil {
-				return fmt.Errorf("failed to read selection: %w", err)
-			}
-			if choice < 1 || choice > len(dests) {
-				return fmt.Errorf("invalid selection")
-			}
-			selected = dests[choice-1]
-		}
+func runPartyJoin(cmd *cobra.Command, args []string) error {
+	app := appFrom(cmd)
+	cfg := app.Config
+	if !player.SupportsIPC(cfg.PlayerName()) {
+		return fmt.Errorf("watch parties need mpv, IINA or a player with supports_ipc, not %s", cfg.PlayerName())
 	}
 
-	if dryRun {
-		fmt.Println(warningStyle.Render(fmt.Sprintf("\n[DRY RUN] Would transfer %d file(s) to %s:", len(rclonePaths), selected.baseURL)))
-		for _, p := range rclonePaths {
-			fmt.Println(infoStyle.Render("  - " + p))
-		}
-		return nil
+	host, err := partyHost(app)
+	if err != nil || host == nil {
+		return err
 	}
 
-	if selected.shared && selected.user == "" && selected.pass == "" {
-		fmt.Println(warningStyle.Render("Note: no webdav_user/webdav_pass set in config; connecting anonymously."))
+	token, err := streamTokenFor(cfg, host)
+	if err != nil {
+		return err
 	}
-
-	fmt.Println(successStyle.Render(fmt.Sprintf("\n✓ Starting transfer of %d item(s) to %s...", len(rclonePaths), selected.baseURL)))
-	if err := download.UploadToWebDAV(ctx, rclonePaths, selected.baseURL, selected.user, selected.pass, selected.dir, selected.vendor, cfg.RclonePath); err != nil {
-		return fmt.Errorf("transfer failed: %w", err)
+	state, err := stream.FetchParty(host, token)
+	if err != nil {
+		return fmt.Errorf("failed to join party: %w", err)
 	}
-
-	fmt.Println(successStyle.Render("✓ All transfers complete"))
-	return nil
-}
-
-// runWebDAVDiscover scans the LAN for gowebdav servers and prints them.
-func runWebDAVDiscover(cmd *cobra.Command, args []string) error {
-	fmt.Println(titleStyle.Render("gowebdav Discovery"))
-	fmt.Println(infoStyle.Render("Searching for gowebdav servers on the local network...\n"))
-
-	ctx := context.Background()
-	targets, err := webdav.Discover(ctx, 3*time.Second)
+	streams, err := stream.FetchStreams(host, token)
 	if err != nil {
-		return fmt.Errorf("discovery failed: %w", err)
+		return fmt.Errorf("failed to fetch streams: %w", err)
 	}
-
-	if len(targets) == 0 {
-		fmt.Println(warningStyle.Render("No gowebdav servers found on the network"))
-		fmt.Println(infoStyle.Render("\nStart one on another machine with:"))
-		fmt.Println(infoStyle.Render("  gowebdav -name <label> -username <user> -password <pass>"))
-		return nil
+	var item *stream.StreamItem
+	for _, st := range streams {
+		if st.ID == state.StreamID {
+			item = st
+		}
 	}
-
-	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Found %d server(s):\n", len(targets))))
-	for _, t := range targets {
-		fmt.Printf("  %-20s %s\n", t.Name, t.BaseURL())
+	if item == nil {
+		return fmt.Errorf("the party's stream is no longer published on %s", host.Name)
 	}
-	return nil
+
+	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Joined %s's party: %s", host.Name, item.Title)))
+	fmt.Println(infoStyle.Render("The host controls playback."))
+
+	return playInParty(app.SignalContext(), cfg, item.StreamURL, item.Title, int(state.Position), func(ctx context.Context, mpv *progress.MPVClient) {
+		if err := stream.FollowParty(ctx, host, token, mpv, partyTolerance); err != nil {
+			fmt.Println(warningStyle.Render(fmt.Sprintf("Lost sync with the host: %v", err)))
+			return
+		}
+		if ctx.Err() == nil {
+			fmt.Println(infoStyle.Render("The host ended the party."))
+		}
+	})
 }
 
-// runWebDAVSetCreds prompts for and saves the shared gowebdav credentials.
-func runWebDAVSetCreds(cmd *cobra.Command, args []string) error {
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+// goUntilStopped runs fn in a goroutine with a context derived from ctx,
+// and returns a func that cancels that context and waits for fn to return.
+func goUntilStopped(ctx context.Context, fn func(context.Context)) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(ctx)
+	}()
+	return func() {
+		cancel()
+		<-done
 	}
+}
 
-	fmt.Println(titleStyle.Render("gowebdav Credentials"))
-	fmt.Println(infoStyle.Render("These are shared across every gowebdav server on your LAN.\n"))
+// showNowPlaying keeps a status line for the playing item — title,
+// position, pause state and a progress bar — on the terminal until ctx is
+// done, then clears it. It polls the player itself, every second, so the
+// line moves between the tracker's reports.
+func showNowPlaying(ctx context.Context, tracker *progress.Tracker, pc progress.PlayerClient) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	defer fmt.Print("\r\x1b[K")
 
-	fmt.Print("Username: ")
-	var username string
-	// An empty username (just Enter) is allowed for anonymous servers; ignore
-	// the EOF/empty-input error and treat it as blank.
-	_, _ = fmt.Scanln(&username)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
 
-	fmt.Print("Password: ")
-	passwordBytes, err := term.ReadPassword(int(syscall.Stdin))
-	if err != nil {
-		return fmt.Errorf("failed to read password: %w", err)
+		pos, err := pc.GetTimePos()
+		if err != nil {
+			continue
+		}
+		paused, _ := pc.GetPaused()
+		item := tracker.CurrentMedia()
+		if idx, err := pc.GetPlaylistPos(); err == nil && tracker.Item(idx) != nil {
+			item = tracker.Item(idx)
+		}
+		if item == nil {
+			continue
+		}
+		width := 80
+		if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+			width = w
+		}
+		// One column short of the edge, so the cursor never wraps.
+		fmt.Print("\r\x1b[K" + ui.NowPlayingLine(item.PlayerTitle(), int(pos*1000), item.Duration, paused, width-1))
 	}
-	fmt.Println()
+}
 
-	fmt.Print("Upload sub-directory (optional, blank = server root): ")
-	var dir string
-	_, _ = fmt.Scanln(&dir)
+// playInParty plays streamURL, shown as title, paused in the configured mpv
+// or IINA with IPC enabled, runs attach against it once connected, and
+// returns when the player exits.
+func playInParty(ctx context.Context, cfg *config.Config, streamURL, title string, startPos int, attach func(context.Context, *progress.MPVClient)) error {
+	opts := player.PlaybackOptions{
+		SocketPath: progress.GenerateIPCPath(),
+		StartPos:   startPos,
+		ExtraArgs:  []string{"--pause"},
+		Titles:     []string{title},
+	}
+	defer os.Remove(opts.SocketPath)
+	mpv := progress.NewMPVClient(opts.SocketPath)
 
-	cfg.WebDAVUser = strings.TrimSpace(username)
-	cfg.WebDAVPass = string(passwordBytes)
-	cfg.WebDAVDir = strings.TrimSpace(dir)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := player.PlayMultipleWith(cfg.PlayerName(), cfg.PlayerPath(), []string{streamURL}, opts)
+		cancel() // Stop syncing when the player exits
+		errCh <- err
+	}()
 
-	if err := cfg.Save(); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
+	if err := mpv.ConnectWithContext(ctx); err != nil {
+		if ctx.Err() == nil {
+			fmt.Println(warningStyle.Render(fmt.Sprintf("Note: Party sync unavailable: %v", err)))
+		}
+	} else {
+		defer func() { _ = mpv.Close() }()
+		go attach(ctx, mpv)
 	}
 
-	fmt.Println(successStyle.Render("✓ Saved gowebdav credentials"))
+	if err := <-errCh; err != nil {
+		return fmt.Errorf("playback failed: %w", err)
+	}
 	return nil
 }
 
-// findWebDAVTarget returns the index of the configured WebDAV target whose
-// name matches (case insensitively), or -1 if none match.
-func findWebDAVTarget(cfg *config.Config, name string) int {
-	for i, t := range cfg.WebDAVTargets {
-		if strings.EqualFold(t.Name, name) {
-			return i
-		}
+// publishedURL returns the URL a phone should open for a published stream:
+// its HLS playlist, which browsers play, when ffmpeg is available, else the
+// URL players get.
+func publishedURL(server *stream.Server, webURL string, item *stream.StreamItem) string {
+	if item.HLSPath != "" {
+		return webURL + item.HLSPath
 	}
-	return -1
+	if path := server.StreamPath(item.ID); path != "" {
+		return webURL + path
+	}
+	return item.StreamURL
 }
 
-// completeWebDAVTargetNames provides shell completion of configured WebDAV
-// target names.
-func completeWebDAVTargetNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	cfg, err := config.Load()
+// printQR prints text as a terminal QR code. Text too long to encode is
+// skipped; the URL printed next to it still works.
+func printQR(text string) {
+	code, err := qr.Encode(text)
 	if err != nil {
-		return nil, cobra.ShellCompDirectiveNoFileComp
+		logging.Warn("Could not draw QR code", "error", err)
+		return
 	}
-	already := make(map[string]struct{}, len(args))
-	for _, a := range args {
-		already[a] = struct{}{}
+	fmt.Print(code.String())
+}
+
+// secureStreamServer applies the configured TLS and access token to a stream
+// server, returning the token clients need ("" if none).
+func secureStreamServer(cfg *config.Config, server *stream.Server) (string, error) {
+	if cfg.StreamTLS || cfg.StreamCertFile != "" || cfg.StreamKeyFile != "" {
+		if err := server.EnableTLS(stream.TLSOptions{CertFile: cfg.StreamCertFile, KeyFile: cfg.StreamKeyFile}); err != nil {
+			return "", err
+		}
 	}
-	var names []string
-	for _, t := range cfg.WebDAVTargets {
-		if _, dup := already[t.Name]; dup {
-			continue
+
+	token := cfg.StreamToken
+	if cfg.StreamAuth && token == "" {
+		var err error
+		if token, err = stream.GenerateToken(); err != nil {
+			return "", err
 		}
-		names = append(names, t.Name)
 	}
-	return names, cobra.ShellCompDirectiveNoFileComp
+	if token != "" {
+		server.RequireToken(token)
+	}
+	return token, nil
 }
 
-// runWebDAVList prints all configured WebDAV targets and their status.
-func runWebDAVList(cmd *cobra.Command, args []string) error {
-	cfg, err := config.Load()
+// startRemoteControl serves the stream server's remote-control page and
+// endpoints, driving mpv, until ctx is cancelled. Failing to start only
+// costs the remote, not playback.
+func startRemoteControl(ctx context.Context, cfg *config.Config, mpv *progress.MPVClient) {
+	server, err := stream.NewServer(stream.DefaultPort)
+	if err != nil {
+		logging.Warn("failed to create remote control server", "error", err)
+		return
+	}
+	token, err := secureStreamServer(cfg, server)
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		fmt.Println(warningStyle.Render(fmt.Sprintf("Note: Remote control unavailable: %v", err)))
+		return
 	}
+	server.SetRemote(mpv)
 
-	fmt.Println(titleStyle.Render("WebDAV Targets"))
-	if len(cfg.WebDAVTargets) == 0 {
-		fmt.Println(warningStyle.Render("No WebDAV targets configured."))
-		fmt.Println(infoStyle.Render("Add one with: goplexcli webdav add"))
-		return nil
+	remoteURL := fmt.Sprintf("%s://%s:%d/remote", server.Scheme(), stream.GetLocalIP(), stream.DefaultPort)
+	if token != "" {
+		remoteURL += "?" + stream.TokenParam + "=" + url.QueryEscape(token)
 	}
+	fmt.Println(infoStyle.Render("Remote control: " + remoteURL))
 
-	for _, t := range cfg.WebDAVTargets {
-		dir := t.Dir
-		if dir == "" {
-			dir = "/"
+	go func() {
+		if err := server.Start(ctx); err != nil {
+			logging.Warn("remote control server failed", "error", err)
 		}
-		user := t.User
-		if user == "" {
-			user = "(anonymous)"
+	}()
+}
+
+// jumpToChapter asks mpv to go to chapter (0-based), retrying while the file
+// is still loading, until it succeeds, ctx is cancelled, or about ten seconds
+// pass.
+func jumpToChapter(ctx context.Context, mpv *progress.MPVClient, chapter int) {
+	var err error
+	for i := 0; i < 40; i++ {
+		if err = mpv.SetChapter(chapter); err == nil {
+			return
 		}
-		status := successStyle.Render("enabled")
-		if !t.Enabled {
-			status = warningStyle.Render("disabled")
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(250 * time.Millisecond):
 		}
-		fmt.Printf("  %-20s %-32s user=%-14s dir=%-12s %s\n", t.Name, t.URL, user, dir, status)
 	}
-	return nil
+	logging.Warn("failed to jump to chapter", "chapter", chapter+1, "error", err)
 }
 
-// runWebDAVAdd interactively adds a new WebDAV target to the config.
-func runWebDAVAdd(cmd *cobra.Command, args []string) error {
-	cfg, err := config.Load()
+// startDiscordPresence shows playback on the user's Discord profile as the
+// tracker reports it. It returns a function that clears the presence, or nil
+// if Discord can't be reached, which is reported but never stops playback.
+func startDiscordPresence(cfg *config.Config, tracker *progress.Tracker) func() {
+	if cfg.DiscordClientID == "" {
+		fmt.Println(warningStyle.Render("Discord presence needs discord_client_id to be set; see the README"))
+		return nil
+	}
+	presence, err := discord.Connect(cfg.DiscordClientID)
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		logging.Warn("Discord presence unavailable", "error", err)
+		return nil
+	}
+	tracker.OnReport(func(item *plex.MediaItem, posMs int, state string) {
+		if err := presence.SetActivity(discord.Watching(item, posMs, state)); err != nil {
+			logging.Debug("failed to update Discord presence", "error", err)
+		}
+	})
+	return func() {
+		_ = presence.SetActivity(nil)
+		_ = presence.Close()
 	}
+}
 
-	reader := bufio.NewReader(os.Stdin)
-	fmt.Println(titleStyle.Render("Add WebDAV Target"))
-	fmt.Println(infoStyle.Render("A WebDAV server with its own URL (including port) and credentials.\n"))
+// recordPlaybackHistory appends this session to the local playback history.
+// Best-effort, like persistPlaybackProgress.
+func recordPlaybackHistory(tracker *progress.Tracker, playerName string, startMs int) {
+	if err := history.Record(tracker.History(playerName, startMs)...); err != nil {
+		logging.Warn("failed to record playback history", "error", err)
+	}
+}
 
-	fmt.Print("Name (e.g. office-nas): ")
-	name, _ := reader.ReadString('\n')
-	name = strings.TrimSpace(name)
-	if name == "" {
-		return fmt.Errorf("name is required")
+// persistPlaybackProgress writes the playback positions captured during this
+// session back into the local cache, keyed by media key. This makes
+// freshly-watched items appear in the "Continue Watching" hub immediately,
+// rather than only after a 'cache reindex'. Best-effort: cache write failures
+// are logged but do not fail playback.
+func persistPlaybackProgress(app *App, tracker *progress.Tracker) {
+	offsets := tracker.Progress()
+	if len(offsets) == 0 {
+		return
 	}
-	if findWebDAVTarget(cfg, name) != -1 {
-		return fmt.Errorf("a target named %q already exists", name)
+
+	// Reloaded: playback can take hours, and another goplexcli may have
+	// saved the cache meanwhile.
+	mediaCache, err := app.ReloadCache()
+	if err != nil {
+		logging.Warn("failed to load cache to persist playback progress", "error", err)
+		return
 	}
 
-	fmt.Print("URL with port (e.g. http://192.168.1.50:5005): ")
-	rawURL, _ := reader.ReadString('\n')
-	rawURL = strings.TrimSpace(rawURL)
-	// Default to http:// when the user omits the scheme.
-	if rawURL != "" && !strings.Contains(rawURL, "://") {
-		rawURL = "http://" + rawURL
+	if !mediaCache.ApplyOffsets(offsets) {
+		return
 	}
-	rawURL = strings.TrimRight(rawURL, "/")
 
-	fmt.Print("Username (blank = anonymous): ")
-	var username string
-	_, _ = fmt.Scanln(&username)
-	username = strings.TrimSpace(username)
+	if err := mediaCache.Save(); err != nil {
+		logging.Warn("failed to persist playback progress to cache", "error", err)
+	}
+}
 
-	var password string
-	if username != "" {
-		fmt.Print("Password: ")
-		passwordBytes, err := term.ReadPassword(int(syscall.Stdin))
-		if err != nil {
-			return fmt.Errorf("failed to read password: %w", err)
+// confirmSharedDownload warns when items are on servers a friend shares
+// rather than ones the account owns, since downloading uses their upload
+// bandwidth, and asks whether to go ahead. Without a terminal to ask on, it
+// only warns.
+func confirmSharedDownload(cfg *config.Config, items []*plex.MediaItem) bool {
+	servers := map[string]bool{}
+	for _, item := range items {
+		if cfg.IsSharedURL(item.ServerURL) {
+			servers[item.ServerName] = true
 		}
-		fmt.Println()
-		password = string(passwordBytes)
 	}
-
-	fmt.Print("Upload sub-directory (optional, blank = server root): ")
-	dir, _ := reader.ReadString('\n')
-	dir = strings.TrimSpace(dir)
-
-	fmt.Print("Vendor [other/nextcloud/owncloud/sharepoint] (blank = other): ")
-	vendor, _ := reader.ReadString('\n')
-	vendor = strings.ToLower(strings.TrimSpace(vendor))
-
-	target := config.WebDAVTarget{
-		Name:    name,
-		URL:     rawURL,
-		User:    username,
-		Pass:    password,
-		Dir:     dir,
-		Vendor:  vendor,
-		Enabled: true,
+	if len(servers) == 0 {
+		return true
 	}
-	if err := target.Validate(); err != nil {
-		return fmt.Errorf("invalid target: %w", err)
+	names := slices.Sorted(maps.Keys(servers))
+	fmt.Println(warningStyle.Render(fmt.Sprintf("⚠ Some items are on servers you don't own (%s); downloading uses their owner's bandwidth.", strings.Join(names, ", "))))
+	if !ui.Interactive() {
+		return true
 	}
+	fmt.Print("Download anyway? [y/N]: ")
+	var confirm string
+	_, _ = fmt.Scanln(&confirm)
+	return confirm == "y" || confirm == "Y"
+}
 
-	// Best-effort connectivity/credential check; a target can still be saved
-	// while the server is offline.
-	fmt.Println(infoStyle.Render("\nChecking connectivity..."))
-	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
-	defer cancel()
-	if err := webdav.Check(ctx, target.URL, target.User, target.Pass); err != nil {
-		fmt.Println(warningStyle.Render(fmt.Sprintf("⚠ Could not verify target: %v", err)))
-		fmt.Println(infoStyle.Render("Saved anyway. Check the URL and credentials before transferring."))
-	} else {
-		fmt.Println(successStyle.Render("✓ Target is reachable and credentials are accepted"))
+func handleDownloadMultiple(cfg *config.Config, mediaItems []*plex.MediaItem) error {
+	if len(mediaItems) == 0 {
+		return fmt.Errorf("no media items provided")
 	}
 
-	cfg.WebDAVTargets = append(cfg.WebDAVTargets, target)
-	if err := cfg.Save(); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
+	// Check if rclone is available
+	if !download.IsAvailable(cfg.RclonePath) {
+		return fmt.Errorf("rclone is not installed. Please install rclone to download media")
 	}
-	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Added WebDAV target %q", name)))
-	return nil
-}
 
-// runWebDAVRemove deletes a configured WebDAV target by name.
-func runWebDAVRemove(cmd *cobra.Command, args []string) error {
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+	fmt.Println(infoStyle.Render(fmt.Sprintf("\nPreparing to download %d items...", len(mediaItems))))
+
+	// Collect rclone paths and validate
+	var downloadItems []*plex.MediaItem
+	for _, media := range mediaItems {
+		if media.RclonePath == "" {
+			fmt.Println(warningStyle.Render(fmt.Sprintf("⚠ Skipping %s (no rclone path)", media.FormatMediaTitle())))
+			continue
+		}
+		downloadItems = append(downloadItems, media)
+		fmt.Println(infoStyle.Render(fmt.Sprintf("  - %s", ui.ListLabel(media))))
 	}
-	name := args[0]
-	idx := findWebDAVTarget(cfg, name)
-	if idx == -1 {
-		return fmt.Errorf("no WebDAV target named %q", name)
+
+	if len(downloadItems) == 0 {
+		return fmt.Errorf("no valid rclone paths available")
 	}
-	removed := cfg.WebDAVTargets[idx].Name
-	cfg.WebDAVTargets = append(cfg.WebDAVTargets[:idx], cfg.WebDAVTargets[idx+1:]...)
-	if err := cfg.Save(); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
+	if !confirmSharedDownload(cfg, downloadItems) {
+		fmt.Println(warningStyle.Render("Download cancelled."))
+		return nil
 	}
-	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Removed WebDAV target %q", removed)))
-	return nil
-}
-
-// runWebDAVEnable enables a target so it appears in the transfer menu.
-func runWebDAVEnable(cmd *cobra.Command, args []string) error {
-	return setWebDAVTargetEnabled(args[0], true)
-}
-
-// runWebDAVDisable disables a target so it is hidden from the transfer menu.
-func runWebDAVDisable(cmd *cobra.Command, args []string) error {
-	return setWebDAVTargetEnabled(args[0], false)
-}
 
-// setWebDAVTargetEnabled toggles the Enabled flag of a target by name and saves.
-func setWebDAVTargetEnabled(name string, enabled bool) error {
-	cfg, err := config.Load()
+	// Resolve destination directory (--dest flag > config download_dir > cwd)
+	destDir, err := cfg.ResolveDownloadDir(downloadDest)
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
-	}
-	idx := findWebDAVTarget(cfg, name)
-	if idx == -1 {
-		return fmt.Errorf("no WebDAV target named %q", name)
-	}
-	cfg.WebDAVTargets[idx].Enabled = enabled
-	if err := cfg.Save(); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
+		return fmt.Errorf("failed to resolve download directory: %w", err)
 	}
-	state := "enabled"
-	if !enabled {
-		state = "disabled"
+
+	files, err := downloadFiles(cfg, destDir, downloadItems)
+	if err != nil {
+		return err
 	}
-	fmt.Println(successStyle.Render(fmt.Sprintf("✓ %s WebDAV target %q", state, cfg.WebDAVTargets[idx].Name)))
-	return nil
-}
 
-// findOutplayerTarget returns the index of the target whose name matches (case
-// insensitively), or -1 if none match.
-func findOutplayerTarget(cfg *config.Config, name string) int {
-	for i, t := range cfg.OutplayerTargets {
-		if strings.EqualFold(t.Name, name) {
-			return i
+	// Handle dry-run mode
+	if dryRun {
+		fmt.Println(warningStyle.Render("\n[DRY RUN] Would download the following files:"))
+		for _, f := range files {
+			fmt.Println(infoStyle.Render(fmt.Sprintf("  - %s -> %s", f.Source, f.Dest)))
 		}
+		fmt.Println(warningStyle.Render(fmt.Sprintf("\n[DRY RUN] Total: %d files to %s", len(files), destDir)))
+		// Report a shortfall rather than fail: a dry run is how the user
+		// finds out what a real run would do.
+		if err := checkDownloadSpace(destDir, downloadItems); err != nil {
+			fmt.Println(warningStyle.Render("[DRY RUN] Would fail: " + err.Error()))
+		}
+		return nil
 	}
-	return -1
-}
 
-// completeOutplayerNames provides shell completion of configured target names.
-func completeOutplayerNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	cfg, err := config.Load()
-	if err != nil {
-		return nil, cobra.ShellCompDirectiveNoFileComp
-	}
-	already := make(map[string]struct{}, len(args))
-	for _, a := range args {
-		already[a] = struct{}{}
+	// Fail before any transfer starts rather than part-way through the batch.
+	if err := checkDownloadSpace(destDir, downloadItems); err != nil {
+		return err
 	}
-	var names []string
-	for _, t := range cfg.OutplayerTargets {
-		if _, dup := already[t.Name]; dup {
-			continue
+
+	// Last chance to back out of a large batch; an empty answer (or no
+	// terminal) goes ahead so scripted downloads aren't blocked.
+	if len(downloadItems) > 1 {
+		prompt := fmt.Sprintf("Download %d items", len(downloadItems))
+		if total, _ := download.TotalSize(downloadItems); total > 0 {
+			prompt += " (" + plex.FormatSize(total) + ")"
+		}
+		fmt.Printf("%s to %s? [Y/n]: ", prompt, destDir)
+		var confirm string
+		_, _ = fmt.Scanln(&confirm)
+		if confirm == "n" || confirm == "N" {
+			fmt.Println(warningStyle.Render("Download cancelled."))
+			return nil
 		}
-		names = append(names, t.Name)
 	}
-	return names, cobra.ShellCompDirectiveNoFileComp
-}
 
-// runOutplayerList prints all configured Outplayer targets and their status.
-func runOutplayerList(cmd *cobra.Command, args []string) error {
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+	// Ensure the destination directory exists
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return fmt.Errorf("failed to create download directory %q: %w", destDir, err)
 	}
 
-	fmt.Println(titleStyle.Render("Outplayer Targets"))
-	if len(cfg.OutplayerTargets) == 0 {
-		fmt.Println(warningStyle.Render("No Outplayer targets configured."))
-		fmt.Println(infoStyle.Render("Add one with: goplexcli outplayer add"))
-		return nil
-	}
+	fmt.Println(successStyle.Render(fmt.Sprintf("\n✓ Starting download of %d items to %s...", len(files), destDir)))
 
-	for _, t := range cfg.OutplayerTargets {
-		dir := t.Dir
-		if dir == "" {
-			dir = "/"
-		}
-		status := successStyle.Render("enabled")
-		if !t.Enabled {
-			status = warningStyle.Render("disabled")
+	// Download with rclone
+	ctx := context.Background()
+	var completed []*plex.MediaItem
+	var completedPaths []string
+	err = download.DownloadMultipleConcurrent(ctx, files, cfg.RclonePath, cfg.GetDownloadConcurrency(), func(i int, err error) {
+		if err == nil {
+			completed = append(completed, downloadItems[i])
+			completedPaths = append(completedPaths, files[i].Dest)
 		}
-		fmt.Printf("  %-20s %-28s dir=%-12s %s\n", t.Name, t.URL, dir, status)
+	})
+	recordDownloads(completed, completedPaths)
+	runPostDownloadHooks(cfg, completed, completedPaths)
+	notifyDownloads(cfg, completed)
+	if err != nil {
+		return fmt.Errorf("download failed: %w", err)
 	}
+
+	fmt.Println(successStyle.Render("✓ All downloads complete"))
 	return nil
 }
 
-// runOutplayerAdd interactively adds a new Outplayer target to the config.
-func runOutplayerAdd(cmd *cobra.Command, args []string) error {
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+// checkDownloadSpace verifies destDir has room for every item with a known
+// size and prints the total. Items indexed before sizes were recorded are
+// counted as unknown and don't block the download.
+func checkDownloadSpace(destDir string, items []*plex.MediaItem) error {
+	total, unknown := download.TotalSize(items)
+	if unknown > 0 {
+		fmt.Println(warningStyle.Render(fmt.Sprintf("⚠ Size unknown for %d item(s); run 'goplexcli cache reindex' to record file sizes", unknown)))
 	}
-
-	reader := bufio.NewReader(os.Stdin)
-	fmt.Println(titleStyle.Render("Add Outplayer Target"))
-	fmt.Println(infoStyle.Render("In Outplayer, enable Wi-Fi transfer to see the address to use.\n"))
-
-	fmt.Print("Name (e.g. iPhone): ")
-	name, _ := reader.ReadString('\n')
-	name = strings.TrimSpace(name)
-	if name == "" {
-		return fmt.Errorf("name is required")
+	if total == 0 {
+		return nil
 	}
-	if findOutplayerTarget(cfg, name) != -1 {
-		return fmt.Errorf("a target named %q already exists", name)
+	if err := download.CheckFreeSpace(destDir, total); err != nil {
+		return fmt.Errorf("%w (%d item(s) totalling %s)", err, len(items)-unknown, plex.FormatSize(total))
 	}
+	fmt.Println(infoStyle.Render(fmt.Sprintf("Total size: %s", plex.FormatSize(total))))
+	return nil
+}
 
-	fmt.Print("URL (e.g. http://192.168.0.34): ")
-	rawURL, _ := reader.ReadString('\n')
-	rawURL = strings.TrimSpace(rawURL)
-	// Default to http:// when the user omits the scheme.
-	if rawURL != "" && !strings.Contains(rawURL, "://") {
-		rawURL = "http://" + rawURL
+// downloadFiles pairs each item's rclone source with its local destination
+// under destDir, laid out by the configured movie/episode naming template.
+// Two items rendering to the same destination is an error: their transfers
+// would share one .partial file and corrupt each other.
+func downloadFiles(cfg *config.Config, destDir string, items []*plex.MediaItem) ([]download.File, error) {
+	files := make([]download.File, len(items))
+	byDest := make(map[string]*plex.MediaItem, len(items))
+	for i, item := range items {
+		rel, err := download.RenderTemplate(cfg.NamingTemplate(item.Type), item)
+		if err != nil {
+			return nil, err
+		}
+		dest := filepath.Join(destDir, rel)
+		if other, ok := byDest[dest]; ok {
+			return nil, fmt.Errorf("%s and %s would both download to %s; make movie_template/episode_template tell them apart",
+				other.FormatMediaTitle(), item.FormatMediaTitle(), dest)
+		}
+		byDest[dest] = item
+		files[i] = download.File{
+			Source:    item.RclonePath,
+			Dest:      dest,
+			Size:      item.Size,
+			CheckHash: cfg.VerifyHash,
+		}
 	}
-	rawURL = strings.TrimRight(rawURL, "/")
-
-	fmt.Print("Upload folder (optional, blank = root): ")
-	dir, _ := reader.ReadString('\n')
-	dir = strings.TrimSpace(dir)
+	return files, nil
+}
 
-	target := config.OutplayerTarget{
-		Name:    name,
-		URL:     rawURL,
-		Dir:     dir,
-		Enabled: true,
+// recordDownloads adds finished downloads to the downloads index so they can
+// be played locally later. Best-effort: a failure is logged, not returned.
+func recordDownloads(items []*plex.MediaItem, paths []string) {
+	now := time.Now()
+	entries := make([]download.IndexEntry, len(items))
+	for i, item := range items {
+		entries[i] = download.NewIndexEntry(item, paths[i], now)
 	}
-	if err := target.Validate(); err != nil {
-		return fmt.Errorf("invalid target: %w", err)
+	if err := download.RecordDownloads(entries...); err != nil {
+		logging.Warn("failed to record downloads", "error", err)
 	}
+}
 
-	// Best-effort connectivity check; a target can still be saved while offline.
-	fmt.Println(infoStyle.Render("\nChecking connectivity..."))
-	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
-	defer cancel()
-	if err := outplayer.Reachable(ctx, target.URL); err != nil {
-		fmt.Println(warningStyle.Render(fmt.Sprintf("⚠ Could not reach target: %v", err)))
-		fmt.Println(infoStyle.Render("Saved anyway. Enable Wi-Fi transfer in Outplayer before uploading."))
-	} else {
-		fmt.Println(successStyle.Render("✓ Target is reachable"))
+// runPostDownloadHooks runs post_download_cmd once per finished item; paths
+// holds each item's local file. It runs after the transfer UI has exited so
+// hook output doesn't tear the progress view. Hook failures are reported but
+// never fail the download.
+func runPostDownloadHooks(cfg *config.Config, items []*plex.MediaItem, paths []string) {
+	if cfg.PostDownloadCmd == "" {
+		return
+	}
+	for i, item := range items {
+		if err := download.RunPostDownloadHook(cfg.PostDownloadCmd, item, paths[i]); err != nil {
+			fmt.Println(warningStyle.Render(fmt.Sprintf("⚠ %s: %v", item.FormatMediaTitle(), err)))
+		}
 	}
+}
 
-	cfg.OutplayerTargets = append(cfg.OutplayerTargets, target)
-	if err := cfg.Save(); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
+// notifyDownloads sends a notification for each finished download. Like the
+// hooks, it runs after the transfer UI has exited.
+func notifyDownloads(cfg *config.Config, items []*plex.MediaItem) {
+	for _, item := range items {
+		sendNotification(cfg, notify.EventDownloadComplete, "Download complete", item.FormatMediaTitle())
 	}
-	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Added Outplayer target %q", name)))
-	return nil
 }
 
-// runOutplayerRemove deletes a target from the config by name.
-func runOutplayerRemove(cmd *cobra.Command, args []string) error {
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+// sendNotification reports a finished operation: as a desktop notification
+// when notifications are enabled, and to the webhook when one is configured.
+// Failures, such as a missing notify-send, are logged and otherwise ignored.
+func sendNotification(cfg *config.Config, event, title, message string) {
+	if cfg.Notifications {
+		if err := notify.Send(title, message); err != nil {
+			logging.Warn("failed to send notification", "error", err)
+		}
 	}
-	name := args[0]
-	idx := findOutplayerTarget(cfg, name)
-	if idx == -1 {
-		return fmt.Errorf("no Outplayer target named %q", name)
+	sendWebhook(cfg, notify.Event{Name: event, Title: title, Message: message, Time: time.Now()})
+}
+
+// sendWebhook POSTs e to the configured webhook, if any. Failures are logged
+// and otherwise ignored.
+func sendWebhook(cfg *config.Config, e notify.Event) {
+	if cfg.WebhookURL == "" {
+		return
 	}
-	removed := cfg.OutplayerTargets[idx].Name
-	cfg.OutplayerTargets = append(cfg.OutplayerTargets[:idx], cfg.OutplayerTargets[idx+1:]...)
-	if err := cfg.Save(); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
+	if err := notify.PostWebhook(context.Background(), cfg.WebhookURL, cfg.WebhookTemplate, e); err != nil {
+		logging.Warn("failed to send webhook", "event", e.Name, "error", err)
 	}
-	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Removed Outplayer target %q", removed)))
-	return nil
 }
 
-// runOutplayerEnable enables a target so it appears in the transfer menu.
-func runOutplayerEnable(cmd *cobra.Command, args []string) error {
-	return setOutplayerEnabled(args[0], true)
+// webdavDest is a unified WebDAV transfer destination: either an explicitly
+// configured target (its own credentials) or a gowebdav server discovered on
+// the LAN (shared WebDAVUser/WebDAVPass credentials).
+type webdavDest struct {
+	label   string // shown in the picker, e.g. "office-nas (http://192.168.1.50:5005)"
+	baseURL string
+	user    string
+	pass    string
+	dir     string
+	vendor  string // rclone WebDAV vendor; "" = "other"
+	shared  bool   // true when using the shared gowebdav credentials
 }
 
-// runOutplayerDisable disables a target so it is hidden from the transfer menu.
-func runOutplayerDisable(cmd *cobra.Command, args []string) error {
-	return setOutplayerEnabled(args[0], false)
-}
-
-// setOutplayerEnabled toggles the Enabled flag of a target by name and saves.
-func setOutplayerEnabled(name string, enabled bool) error {
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
-	}
-	idx := findOutplayerTarget(cfg, name)
-	if idx == -1 {
-		return fmt.Errorf("no Outplayer target named %q", name)
-	}
-	cfg.OutplayerTargets[idx].Enabled = enabled
-	if err := cfg.Save(); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
-	}
-	state := "enabled"
-	if !enabled {
-		state = "disabled"
-	}
-	fmt.Println(successStyle.Render(fmt.Sprintf("✓ %s Outplayer target %q", state, cfg.OutplayerTargets[idx].Name)))
-	return nil
-}
-
-// handleTransferToOutplayer uploads the selected media to a user-configured
-// Outplayer Wi-Fi transfer target. The media source is streamed from its rclone
-// remote directly into Outplayer's HTTP uploader (see internal/outplayer).
-func handleTransferToOutplayer(cfg *config.Config, mediaItems []*plex.MediaItem) error {
+// handleTransferToWebDAV lets the user pick a WebDAV destination — explicitly
+// configured targets (goplexcli webdav add) plus gowebdav servers discovered on
+// the LAN via mDNS — and pushes the selected media to it using rclone's WebDAV
+// backend. Configured targets carry their own credentials; discovered servers
+// use the shared ones stored in config (WebDAVUser/Pass).
+func handleTransferToWebDAV(cfg *config.Config, mediaItems []*plex.MediaItem) error {
 	if len(mediaItems) == 0 {
 		return fmt.Errorf("no media items provided")
 	}
 
-	// rclone provides the source bytes (same requirement as downloads).
+	// rclone is used for the actual transfer (same requirement as downloads).
 	if !download.IsAvailable(cfg.RclonePath) {
 		return fmt.Errorf("rclone is not installed. Please install rclone to transfer media")
 	}
 
-	// Collect rclone source paths and validate (mirrors handleTransferToWebDAV).
+	// Collect rclone source paths and validate (mirrors handleDownloadMultiple).
 	var rclonePaths []string
 	for _, media := range mediaItems {
 		if media.RclonePath == "" {
@@ -2290,1164 +4692,4080 @@ func handleTransferToOutplayer(cfg *config.Config, mediaItems []*plex.MediaItem)
 		return fmt.Errorf("no valid rclone paths available")
 	}
 
-	targets := cfg.GetEnabledOutplayerTargets()
-	if len(targets) == 0 {
-		fmt.Println(warningStyle.Render("No enabled Outplayer targets."))
-		fmt.Println(infoStyle.Render("Add one with: goplexcli outplayer add"))
+	// Configured targets are always available; discovered ones depend on what
+	// is currently on the LAN.
+	var dests []webdavDest
+	for _, t := range cfg.GetEnabledWebDAVTargets() {
+		dests = append(dests, webdavDest{
+			label:   fmt.Sprintf("%s (%s)", t.Name, t.URL),
+			baseURL: t.URL,
+			user:    t.User,
+			pass:    t.Pass,
+			dir:     t.Dir,
+			vendor:  t.Vendor,
+		})
+	}
+
+	// Discover gowebdav servers advertised on the LAN.
+	fmt.Println(infoStyle.Render("\nSearching for gowebdav servers on the local network..."))
+	ctx := context.Background()
+	targets, err := webdav.Discover(ctx, 3*time.Second)
+	if err != nil {
+		// Configured targets are still usable when mDNS discovery breaks
+		// (e.g. multicast blocked); only fail outright if there is nothing else.
+		if len(dests) == 0 {
+			return fmt.Errorf("discovery failed: %w", err)
+		}
+		fmt.Println(warningStyle.Render(fmt.Sprintf("⚠ Discovery failed: %v", err)))
+	}
+	for _, t := range targets {
+		baseURL := t.BaseURL()
+		if baseURL == "" {
+			continue
+		}
+		dests = append(dests, webdavDest{
+			label:   fmt.Sprintf("%s (%s, discovered)", t.Name, baseURL),
+			baseURL: baseURL,
+			user:    cfg.WebDAVUser,
+			pass:    cfg.WebDAVPass,
+			dir:     cfg.WebDAVDir,
+			shared:  true,
+		})
+	}
+
+	if len(dests) == 0 {
+		fmt.Println(warningStyle.Render("No WebDAV destinations available"))
+		fmt.Println(infoStyle.Render("\nAdd a WebDAV server with:"))
+		fmt.Println(infoStyle.Render("  goplexcli webdav add"))
+		fmt.Println(infoStyle.Render("\nOr run a gowebdav server on another machine:"))
+		fmt.Println(infoStyle.Render("  gowebdav -name <label> -username <user> -password <pass>"))
+		fmt.Println(infoStyle.Render("(use the same credentials you set in goplexcli config)"))
 		return nil
 	}
 
-	// Select a target if more than one is enabled.
-	var selected config.OutplayerTarget
-	if len(targets) == 1 {
-		selected = targets[0]
-		fmt.Println(infoStyle.Render(fmt.Sprintf("Uploading to: %s (%s)", selected.Name, selected.URL)))
+	fmt.Println(successStyle.Render(fmt.Sprintf("✓ %d destination(s) available\n", len(dests))))
+
+	// Select a destination if more than one is available.
+	var selected webdavDest
+	if len(dests) == 1 {
+		selected = dests[0]
+		fmt.Println(infoStyle.Render("Transferring to: " + selected.label))
 	} else {
-		names := make([]string, len(targets))
-		for i, t := range targets {
-			names[i] = fmt.Sprintf("%s (%s)", t.Name, t.URL)
+		var names []string
+		for _, d := range dests {
+			names = append(names, d.label)
 		}
 		if ui.IsAvailable(cfg.FzfPath) {
-			_, idx, err := ui.SelectWithFzf(names, "Select Outplayer target:", cfg.FzfPath)
+			_, idx, err := ui.SelectWithFzf(names, "Select WebDAV destination:", cfg.FzfPath)
 			if err != nil {
 				if errors.Is(err, apperrors.ErrCancelled) {
 					return nil
 				}
-				return fmt.Errorf("target selection failed: %w", err)
-			}
-			selected = targets[idx]
-		} else {
-			fmt.Println(infoStyle.Render("Available targets:"))
-			for i, n := range names {
-				fmt.Printf("  %d. %s\n", i+1, n)
-			}
-			fmt.Print("\nSelect target number: ")
-			var choice int
-			if _, err := fmt.Scanln(&choice); err != nil {
-				return fmt.Errorf("failed to read selection: %w", err)
-			}
-			if choice < 1 || choice > len(targets) {
-				return fmt.Errorf("invalid selection")
+				return fmt.Errorf("destination selection failed: %w", err)
+			}
+			selected = dests[idx]
+		} else {
+			fmt.Println(infoStyle.Render("Available destinations:"))
+			for i, name := range names {
+				fmt.Printf("  %d. %s\n", i+1, name)
+			}
+			fmt.Print("\nSelect destination number: ")
+			var choice int
+			if _, err := fmt.Scanln(&choice); err != nil {
+				return fmt.Errorf("failed to read selection: %w", err)
+			}
+			if choice < 1 || choice > len(dests) {
+				return fmt.Errorf("invalid selection")
+			}
+			selected = dests[choice-1]
+		}
+	}
+
+	if dryRun {
+		fmt.Println(warningStyle.Render(fmt.Sprintf("\n[DRY RUN] Would transfer %d file(s) to %s:", len(rclonePaths), selected.baseURL)))
+		for _, p := range rclonePaths {
+			fmt.Println(infoStyle.Render("  - " + p))
+		}
+		return nil
+	}
+
+	if selected.shared && selected.user == "" && selected.pass == "" {
+		fmt.Println(warningStyle.Render("Note: no webdav_user/webdav_pass set in config; connecting anonymously."))
+	}
+
+	fmt.Println(successStyle.Render(fmt.Sprintf("\n✓ Starting transfer of %d item(s) to %s...", len(rclonePaths), selected.baseURL)))
+	if err := download.UploadToWebDAV(ctx, rclonePaths, selected.baseURL, selected.user, selected.pass, selected.dir, selected.vendor, cfg.RclonePath); err != nil {
+		return fmt.Errorf("transfer failed: %w", err)
+	}
+
+	fmt.Println(successStyle.Render("✓ All transfers complete"))
+	return nil
+}
+
+// runWebDAVDiscover scans the LAN for gowebdav servers and prints them.
+func runWebDAVDiscover(cmd *cobra.Command, args []string) error {
+	fmt.Println(titleStyle.Render("gowebdav Discovery"))
+	fmt.Println(infoStyle.Render("Searching for gowebdav servers on the local network...\n"))
+
+	ctx := context.Background()
+	targets, err := webdav.Discover(ctx, 3*time.Second)
+	if err != nil {
+		return fmt.Errorf("discovery failed: %w", err)
+	}
+
+	if len(targets) == 0 {
+		fmt.Println(warningStyle.Render("No gowebdav servers found on the network"))
+		fmt.Println(infoStyle.Render("\nStart one on another machine with:"))
+		fmt.Println(infoStyle.Render("  gowebdav -name <label> -username <user> -password <pass>"))
+		return nil
+	}
+
+	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Found %d server(s):\n", len(targets))))
+	for _, t := range targets {
+		fmt.Printf("  %-20s %s\n", t.Name, t.BaseURL())
+	}
+	return nil
+}
+
+// runWebDAVSetCreds prompts for and saves the shared gowebdav credentials.
+func runWebDAVSetCreds(cmd *cobra.Command, args []string) error {
+	cfg := appFrom(cmd).Config
+
+	fmt.Println(titleStyle.Render("gowebdav Credentials"))
+	fmt.Println(infoStyle.Render("These are shared across every gowebdav server on your LAN.\n"))
+
+	fmt.Print("Username: ")
+	var username string
+	// An empty username (just Enter) is allowed for anonymous servers; ignore
+	// the EOF/empty-input error and treat it as blank.
+	_, _ = fmt.Scanln(&username)
+
+	fmt.Print("Password: ")
+	passwordBytes, err := term.ReadPassword(int(syscall.Stdin))
+	if err != nil {
+		return fmt.Errorf("failed to read password: %w", err)
+	}
+	fmt.Println()
+
+	fmt.Print("Upload sub-directory (optional, blank = server root): ")
+	var dir string
+	_, _ = fmt.Scanln(&dir)
+
+	cfg.WebDAVUser = strings.TrimSpace(username)
+	cfg.WebDAVPass = string(passwordBytes)
+	cfg.WebDAVDir = strings.TrimSpace(dir)
+
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Println(successStyle.Render("✓ Saved gowebdav credentials"))
+	return nil
+}
+
+// findWebDAVTarget returns the index of the configured WebDAV target whose
+// name matches (case insensitively), or -1 if none match.
+func findWebDAVTarget(cfg *config.Config, name string) int {
+	for i, t := range cfg.WebDAVTargets {
+		if strings.EqualFold(t.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// completeWebDAVTargetNames provides shell completion of configured WebDAV
+// target names.
+func completeWebDAVTargetNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	already := make(map[string]struct{}, len(args))
+	for _, a := range args {
+		already[a] = struct{}{}
+	}
+	var names []string
+	for _, t := range cfg.WebDAVTargets {
+		if _, dup := already[t.Name]; dup {
+			continue
+		}
+		names = append(names, t.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// runWebDAVList prints all configured WebDAV targets and their status.
+func runWebDAVList(cmd *cobra.Command, args []string) error {
+	cfg := appFrom(cmd).Config
+
+	fmt.Println(titleStyle.Render("WebDAV Targets"))
+	if len(cfg.WebDAVTargets) == 0 {
+		fmt.Println(warningStyle.Render("No WebDAV targets configured."))
+		fmt.Println(infoStyle.Render("Add one with: goplexcli webdav add"))
+		return nil
+	}
+
+	for _, t := range cfg.WebDAVTargets {
+		dir := t.Dir
+		if dir == "" {
+			dir = "/"
+		}
+		user := t.User
+		if user == "" {
+			user = "(anonymous)"
+		}
+		status := successStyle.Render("enabled")
+		if !t.Enabled {
+			status = warningStyle.Render("disabled")
+		}
+		fmt.Printf("  %-20s %-32s user=%-14s dir=%-12s %s\n", t.Name, t.URL, user, dir, status)
+	}
+	return nil
+}
+
+// runWebDAVAdd interactively adds a new WebDAV target to the config.
+func runWebDAVAdd(cmd *cobra.Command, args []string) error {
+	cfg := appFrom(cmd).Config
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Println(titleStyle.Render("Add WebDAV Target"))
+	fmt.Println(infoStyle.Render("A WebDAV server with its own URL (including port) and credentials.\n"))
+
+	fmt.Print("Name (e.g. office-nas): ")
+	name, _ := reader.ReadString('\n')
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if findWebDAVTarget(cfg, name) != -1 {
+		return fmt.Errorf("a target named %q already exists", name)
+	}
+
+	fmt.Print("URL with port (e.g. http://192.168.1.50:5005): ")
+	rawURL, _ := reader.ReadString('\n')
+	rawURL = strings.TrimSpace(rawURL)
+	// Default to http:// when the user omits the scheme.
+	if rawURL != "" && !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+	rawURL = strings.TrimRight(rawURL, "/")
+
+	fmt.Print("Username (blank = anonymous): ")
+	var username string
+	_, _ = fmt.Scanln(&username)
+	username = strings.TrimSpace(username)
+
+	var password string
+	if username != "" {
+		fmt.Print("Password: ")
+		passwordBytes, err := term.ReadPassword(int(syscall.Stdin))
+		if err != nil {
+			return fmt.Errorf("failed to read password: %w", err)
+		}
+		fmt.Println()
+		password = string(passwordBytes)
+	}
+
+	fmt.Print("Upload sub-directory (optional, blank = server root): ")
+	dir, _ := reader.ReadString('\n')
+	dir = strings.TrimSpace(dir)
+
+	fmt.Print("Vendor [other/nextcloud/owncloud/sharepoint] (blank = other): ")
+	vendor, _ := reader.ReadString('\n')
+	vendor = strings.ToLower(strings.TrimSpace(vendor))
+
+	target := config.WebDAVTarget{
+		Name:    name,
+		URL:     rawURL,
+		User:    username,
+		Pass:    password,
+		Dir:     dir,
+		Vendor:  vendor,
+		Enabled: true,
+	}
+	if err := target.Validate(); err != nil {
+		return fmt.Errorf("invalid target: %w", err)
+	}
+
+	// Best-effort connectivity/credential check; a target can still be saved
+	// while the server is offline.
+	fmt.Println(infoStyle.Render("\nChecking connectivity..."))
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+	if err := webdav.Check(ctx, target.URL, target.User, target.Pass); err != nil {
+		fmt.Println(warningStyle.Render(fmt.Sprintf("⚠ Could not verify target: %v", err)))
+		fmt.Println(infoStyle.Render("Saved anyway. Check the URL and credentials before transferring."))
+	} else {
+		fmt.Println(successStyle.Render("✓ Target is reachable and credentials are accepted"))
+	}
+
+	cfg.WebDAVTargets = append(cfg.WebDAVTargets, target)
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Added WebDAV target %q", name)))
+	return nil
+}
+
+// runWebDAVRemove deletes a configured WebDAV target by name.
+func runWebDAVRemove(cmd *cobra.Command, args []string) error {
+	cfg := appFrom(cmd).Config
+
+	name := args[0]
+	idx := findWebDAVTarget(cfg, name)
+	if idx == -1 {
+		return fmt.Errorf("no WebDAV target named %q", name)
+	}
+	removed := cfg.WebDAVTargets[idx].Name
+	cfg.WebDAVTargets = append(cfg.WebDAVTargets[:idx], cfg.WebDAVTargets[idx+1:]...)
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Removed WebDAV target %q", removed)))
+	return nil
+}
+
+// runWebDAVEnable enables a target so it appears in the transfer menu.
+func runWebDAVEnable(cmd *cobra.Command, args []string) error {
+	return setWebDAVTargetEnabled(appFrom(cmd).Config, args[0], true)
+}
+
+// runWebDAVDisable disables a target so it is hidden from the transfer menu.
+func runWebDAVDisable(cmd *cobra.Command, args []string) error {
+	return setWebDAVTargetEnabled(appFrom(cmd).Config, args[0], false)
+}
+
+// setWebDAVTargetEnabled toggles the Enabled flag of a target by name and saves.
+func setWebDAVTargetEnabled(cfg *config.Config, name string, enabled bool) error {
+	idx := findWebDAVTarget(cfg, name)
+	if idx == -1 {
+		return fmt.Errorf("no WebDAV target named %q", name)
+	}
+	cfg.WebDAVTargets[idx].Enabled = enabled
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	state := "enabled"
+	if !enabled {
+		state = "disabled"
+	}
+	fmt.Println(successStyle.Render(fmt.Sprintf("✓ %s WebDAV target %q", state, cfg.WebDAVTargets[idx].Name)))
+	return nil
+}
+
+// findOutplayerTarget returns the index of the target whose name matches (case
+// insensitively), or -1 if none match.
+func findOutplayerTarget(cfg *config.Config, name string) int {
+	for i, t := range cfg.OutplayerTargets {
+		if strings.EqualFold(t.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// completeOutplayerNames provides shell completion of configured target names.
+func completeOutplayerNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	already := make(map[string]struct{}, len(args))
+	for _, a := range args {
+		already[a] = struct{}{}
+	}
+	var names []string
+	for _, t := range cfg.OutplayerTargets {
+		if _, dup := already[t.Name]; dup {
+			continue
+		}
+		names = append(names, t.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// runOutplayerList prints all configured Outplayer targets and their status.
+func runOutplayerList(cmd *cobra.Command, args []string) error {
+	cfg := appFrom(cmd).Config
+
+	fmt.Println(titleStyle.Render("Outplayer Targets"))
+	if len(cfg.OutplayerTargets) == 0 {
+		fmt.Println(warningStyle.Render("No Outplayer targets configured."))
+		fmt.Println(infoStyle.Render("Add one with: goplexcli outplayer add"))
+		return nil
+	}
+
+	for _, t := range cfg.OutplayerTargets {
+		dir := t.Dir
+		if dir == "" {
+			dir = "/"
+		}
+		status := successStyle.Render("enabled")
+		if !t.Enabled {
+			status = warningStyle.Render("disabled")
+		}
+		fmt.Printf("  %-20s %-28s dir=%-12s %s\n", t.Name, t.URL, dir, status)
+	}
+	return nil
+}
+
+// runOutplayerAdd interactively adds a new Outplayer target to the config.
+func runOutplayerAdd(cmd *cobra.Command, args []string) error {
+	cfg := appFrom(cmd).Config
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Println(titleStyle.Render("Add Outplayer Target"))
+	fmt.Println(infoStyle.Render("In Outplayer, enable Wi-Fi transfer to see the address to use.\n"))
+
+	fmt.Print("Name (e.g. iPhone): ")
+	name, _ := reader.ReadString('\n')
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if findOutplayerTarget(cfg, name) != -1 {
+		return fmt.Errorf("a target named %q already exists", name)
+	}
+
+	fmt.Print("URL (e.g. http://192.168.0.34): ")
+	rawURL, _ := reader.ReadString('\n')
+	rawURL = strings.TrimSpace(rawURL)
+	// Default to http:// when the user omits the scheme.
+	if rawURL != "" && !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+	rawURL = strings.TrimRight(rawURL, "/")
+
+	fmt.Print("Upload folder (optional, blank = root): ")
+	dir, _ := reader.ReadString('\n')
+	dir = strings.TrimSpace(dir)
+
+	target := config.OutplayerTarget{
+		Name:    name,
+		URL:     rawURL,
+		Dir:     dir,
+		Enabled: true,
+	}
+	if err := target.Validate(); err != nil {
+		return fmt.Errorf("invalid target: %w", err)
+	}
+
+	// Best-effort connectivity check; a target can still be saved while offline.
+	fmt.Println(infoStyle.Render("\nChecking connectivity..."))
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+	if err := outplayer.Reachable(ctx, target.URL); err != nil {
+		fmt.Println(warningStyle.Render(fmt.Sprintf("⚠ Could not reach target: %v", err)))
+		fmt.Println(infoStyle.Render("Saved anyway. Enable Wi-Fi transfer in Outplayer before uploading."))
+	} else {
+		fmt.Println(successStyle.Render("✓ Target is reachable"))
+	}
+
+	cfg.OutplayerTargets = append(cfg.OutplayerTargets, target)
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Added Outplayer target %q", name)))
+	return nil
+}
+
+// runOutplayerRemove deletes a target from the config by name.
+func runOutplayerRemove(cmd *cobra.Command, args []string) error {
+	cfg := appFrom(cmd).Config
+
+	name := args[0]
+	idx := findOutplayerTarget(cfg, name)
+	if idx == -1 {
+		return fmt.Errorf("no Outplayer target named %q", name)
+	}
+	removed := cfg.OutplayerTargets[idx].Name
+	cfg.OutplayerTargets = append(cfg.OutplayerTargets[:idx], cfg.OutplayerTargets[idx+1:]...)
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Removed Outplayer target %q", removed)))
+	return nil
+}
+
+// runOutplayerEnable enables a target so it appears in the transfer menu.
+func runOutplayerEnable(cmd *cobra.Command, args []string) error {
+	return setOutplayerEnabled(appFrom(cmd).Config, args[0], true)
+}
+
+// runOutplayerDisable disables a target so it is hidden from the transfer menu.
+func runOutplayerDisable(cmd *cobra.Command, args []string) error {
+	return setOutplayerEnabled(appFrom(cmd).Config, args[0], false)
+}
+
+// setOutplayerEnabled toggles the Enabled flag of a target by name and saves.
+func setOutplayerEnabled(cfg *config.Config, name string, enabled bool) error {
+	idx := findOutplayerTarget(cfg, name)
+	if idx == -1 {
+		return fmt.Errorf("no Outplayer target named %q", name)
+	}
+	cfg.OutplayerTargets[idx].Enabled = enabled
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	state := "enabled"
+	if !enabled {
+		state = "disabled"
+	}
+	fmt.Println(successStyle.Render(fmt.Sprintf("✓ %s Outplayer target %q", state, cfg.OutplayerTargets[idx].Name)))
+	return nil
+}
+
+// handleTransferToOutplayer uploads the selected media to a user-configured
+// Outplayer Wi-Fi transfer target. The media source is streamed from its rclone
+// remote directly into Outplayer's HTTP uploader (see internal/outplayer).
+func handleTransferToOutplayer(cfg *config.Config, mediaItems []*plex.MediaItem) error {
+	if len(mediaItems) == 0 {
+		return fmt.Errorf("no media items provided")
+	}
+
+	// rclone provides the source bytes (same requirement as downloads).
+	if !download.IsAvailable(cfg.RclonePath) {
+		return fmt.Errorf("rclone is not installed. Please install rclone to transfer media")
+	}
+
+	// Collect rclone source paths and validate (mirrors handleTransferToWebDAV).
+	var rclonePaths []string
+	for _, media := range mediaItems {
+		if media.RclonePath == "" {
+			fmt.Println(warningStyle.Render(fmt.Sprintf("⚠ Skipping %s (no rclone path)", media.FormatMediaTitle())))
+			continue
+		}
+		rclonePaths = append(rclonePaths, media.RclonePath)
+	}
+	if len(rclonePaths) == 0 {
+		return fmt.Errorf("no valid rclone paths available")
+	}
+
+	targets := cfg.GetEnabledOutplayerTargets()
+	if len(targets) == 0 {
+		fmt.Println(warningStyle.Render("No enabled Outplayer targets."))
+		fmt.Println(infoStyle.Render("Add one with: goplexcli outplayer add"))
+		return nil
+	}
+
+	// Select a target if more than one is enabled.
+	var selected config.OutplayerTarget
+	if len(targets) == 1 {
+		selected = targets[0]
+		fmt.Println(infoStyle.Render(fmt.Sprintf("Uploading to: %s (%s)", selected.Name, selected.URL)))
+	} else {
+		names := make([]string, len(targets))
+		for i, t := range targets {
+			names[i] = fmt.Sprintf("%s (%s)", t.Name, t.URL)
+		}
+		if ui.IsAvailable(cfg.FzfPath) {
+			_, idx, err := ui.SelectWithFzf(names, "Select Outplayer target:", cfg.FzfPath)
+			if err != nil {
+				if errors.Is(err, apperrors.ErrCancelled) {
+					return nil
+				}
+				return fmt.Errorf("target selection failed: %w", err)
+			}
+			selected = targets[idx]
+		} else {
+			fmt.Println(infoStyle.Render("Available targets:"))
+			for i, n := range names {
+				fmt.Printf("  %d. %s\n", i+1, n)
+			}
+			fmt.Print("\nSelect target number: ")
+			var choice int
+			if _, err := fmt.Scanln(&choice); err != nil {
+				return fmt.Errorf("failed to read selection: %w", err)
+			}
+			if choice < 1 || choice > len(targets) {
+				return fmt.Errorf("invalid selection")
+			}
+			selected = targets[choice-1]
+		}
+	}
+
+	if dryRun {
+		fmt.Println(warningStyle.Render(fmt.Sprintf("\n[DRY RUN] Would upload %d file(s) to %s (%s):", len(rclonePaths), selected.Name, selected.URL)))
+		for _, p := range rclonePaths {
+			fmt.Println(infoStyle.Render("  - " + p))
+		}
+		return nil
+	}
+
+	ctx := context.Background()
+
+	// Fail fast if the target is unreachable (e.g. Wi-Fi transfer is off).
+	checkCtx, cancel := context.WithTimeout(ctx, 8*time.Second)
+	err := outplayer.Reachable(checkCtx, selected.URL)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("cannot reach %s (%s): %w\nMake sure Outplayer's Wi-Fi transfer is enabled and on the same network", selected.Name, selected.URL, err)
+	}
+
+	fmt.Println(successStyle.Render(fmt.Sprintf("\n✓ Uploading %d item(s) to %s...", len(rclonePaths), selected.Name)))
+	if err := outplayer.Upload(ctx, rclonePaths, selected.URL, selected.Dir, cfg.RclonePath); err != nil {
+		return fmt.Errorf("upload failed: %w", err)
+	}
+
+	fmt.Println(successStyle.Render("✓ All uploads complete"))
+	return nil
+}
+
+func handleSenPlayer(cfg *config.Config, mediaItems []*plex.MediaItem, mode string) error {
+	if len(mediaItems) == 0 {
+		return fmt.Errorf("no media items provided")
+	}
+
+	// SenPlayer only supports one item at a time
+	if len(mediaItems) > 1 {
+		fmt.Println(warningStyle.Render("Note: SenPlayer only supports single selection, using first item"))
+	}
+
+	media := mediaItems[0]
+	actionText := "Playing"
+	if mode == "download" {
+		actionText = "Downloading"
+	}
+	fmt.Println(infoStyle.Render(fmt.Sprintf("\nPreparing for SenPlayer (%s): %s", actionText, media.FormatMediaTitle())))
+
+	// Create Plex client
+	client, err := newStreamClient(cfg, cfg.PlexURL)
+	if err != nil {
+		return err
+	}
+
+	// Get stream URL
+	streamURL, err := getStreamURL(client, media.Key)
+	if err != nil {
+		return fmt.Errorf("failed to get stream URL: %w", err)
+	}
+
+	// Build filename from media title
+	filename := media.Title
+	if media.Year > 0 {
+		filename = fmt.Sprintf("%s (%d)", media.Title, media.Year)
+	}
+	filename += ".mkv"
+
+	var senplayerURL string
+	if mode == "download" {
+		// Download format: SenPlayer://x-callback-url/download?url=<url>&name=<filename>
+		senplayerURL = fmt.Sprintf("SenPlayer://x-callback-url/download?url=%s&name=%s",
+			url.QueryEscape(streamURL),
+			url.QueryEscape(filename),
+		)
+	} else {
+		// Play format: SenPlayer://x-callback-url/play?url=<url>&name=<filename>&User-Agent=<ua>
+		senplayerURL = fmt.Sprintf("SenPlayer://x-callback-url/play?url=%s&name=%s&User-Agent=%s",
+			url.QueryEscape(streamURL),
+			url.QueryEscape(filename),
+			url.QueryEscape("GoplexCLI/1.0"),
+		)
+	}
+
+	// On macOS, open the URL directly
+	if runtime.GOOS == "darwin" {
+		fmt.Println(infoStyle.Render("Opening in SenPlayer..."))
+		cmd := exec.Command("open", senplayerURL)
+		if err := cmd.Run(); err != nil {
+			// If open fails, fall back to showing the URL
+			fmt.Println(warningStyle.Render("Could not open SenPlayer automatically"))
+			fmt.Println(infoStyle.Render("\nCopy this URL to open in SenPlayer:"))
+			fmt.Println(senplayerURL)
+		} else {
+			fmt.Println(successStyle.Render(fmt.Sprintf("✓ Sent to SenPlayer (%s)", mode)))
+		}
+	} else {
+		// On other platforms, show the URL for manual copying
+		fmt.Println(infoStyle.Render("\nCopy this URL to open in SenPlayer:"))
+		fmt.Println(senplayerURL)
+	}
+
+	return nil
+}
+
+func handleStream(cfg *config.Config, media *plex.MediaItem) error {
+	fmt.Println(infoStyle.Render("\nPublishing stream: " + media.FormatMediaTitle()))
+
+	// Create Plex client
+	client, err := newStreamClient(cfg, cfg.PlexURL)
+	if err != nil {
+		return err
+	}
+
+	// Get stream URL
+	streamURL, err := getStreamURL(client, media.Key)
+	if err != nil {
+		return fmt.Errorf("failed to get stream URL: %w", err)
+	}
+
+	// Create and start stream server
+	server, err := stream.NewServer(stream.DefaultPort)
+	if err != nil {
+		return fmt.Errorf("failed to create stream server: %w", err)
+	}
+
+	// HLS is optional: without ffmpeg the direct URL still works for players
+	// that handle MKV themselves.
+	hlsErr := server.EnableHLS(stream.HLSOptions{
+		FFmpegPath:     cfg.FFmpegPath,
+		TranscodeVideo: cfg.HLSTranscode,
+	})
+
+	// Protect the server before publishing, so the HLS path carries the token
+	accessToken, err := secureStreamServer(cfg, server)
+	if err != nil {
+		return err
+	}
+
+	if cfg.StreamProxy {
+		server.EnableProxy()
+	}
+
+	// Publish the stream
+	streamID := server.PublishStream(media, streamURL, cfg.PlexURL, cfg.TokenForURL(cfg.PlexURL))
+
+	localIP := stream.GetLocalIP()
+	webURL := fmt.Sprintf("%s://%s:%d", server.Scheme(), localIP, stream.DefaultPort)
+
+	// Players get the proxied URL when proxying, so the token stays here
+	playURL := streamURL
+	if path := server.StreamPath(streamID); path != "" {
+		playURL = webURL + path
+	}
+
+	// URL encode for deep links
+	encodedURL := url.QueryEscape(playURL)
+
+	fmt.Println(successStyle.Render("✓ Stream published"))
+	fmt.Println(infoStyle.Render(fmt.Sprintf("Stream ID: %s", streamID)))
+	fmt.Println(infoStyle.Render(fmt.Sprintf("Title: %s", media.FormatMediaTitle())))
+	fmt.Println(warningStyle.Render(fmt.Sprintf("\nStream server running on port %d", stream.DefaultPort)))
+
+	fmt.Println(successStyle.Render("\nClick to open in your player:"))
+	fmt.Println()
+
+	playerStyle := lipgloss.NewStyle().Foreground(ui.CurrentTheme().Accent).Bold(true).Width(12)
+	linkStyle := lipgloss.NewStyle().Foreground(ui.CurrentTheme().Info).Underline(true)
+
+	fmt.Printf("  %s %s\n\n", playerStyle.Render("Infuse"), linkStyle.Render(fmt.Sprintf("infuse://x-callback-url/play?url=%s", encodedURL)))
+	fmt.Printf("  %s %s\n\n", playerStyle.Render("OutPlayer"), linkStyle.Render(fmt.Sprintf("outplayer://x-callback-url/play?url=%s", encodedURL)))
+	fmt.Printf("  %s %s\n\n", playerStyle.Render("SenPlayer"), linkStyle.Render(fmt.Sprintf("senplayer://x-callback-url/play?url=%s", encodedURL)))
+	fmt.Printf("  %s %s\n\n", playerStyle.Render("VLC"), linkStyle.Render(fmt.Sprintf("vlc://%s", encodedURL)))
+	fmt.Printf("  %s %s\n", playerStyle.Render("VidHub"), linkStyle.Render(fmt.Sprintf("open-vidhub://x-callback-url/open?url=%s", encodedURL)))
+
+	if item, ok := server.GetStream(streamID); ok && hlsErr == nil {
+		fmt.Printf("\n  %s %s\n", playerStyle.Render("Browser/TV"), linkStyle.Render(webURL+item.HLSPath))
+	} else if hlsErr != nil {
+		fmt.Println(infoStyle.Render(fmt.Sprintf("\n  (HLS for browsers/TVs unavailable: %v)", hlsErr)))
+	}
+
+	fmt.Println()
+	uiURL := webURL
+	if accessToken != "" {
+		uiURL += "/?" + stream.TokenParam + "=" + url.QueryEscape(accessToken)
+	}
+	printQR(uiURL)
+	fmt.Println(successStyle.Render("Web UI: ") + linkStyle.Render(uiURL))
+	if accessToken != "" {
+		fmt.Println(warningStyle.Render("Access token: ") + accessToken)
+		fmt.Println(infoStyle.Render("Other devices need it: 'goplexcli stream --token <token>', or any user name with the token as password"))
+	}
+	fmt.Println(successStyle.Render("Playlist: ") + linkStyle.Render(webURL+server.PlaylistPath()))
+	if fp := server.Fingerprint(); fp != "" && cfg.StreamCertFile == "" {
+		fmt.Println(infoStyle.Render("Self-signed certificate, SHA-256 fingerprint: " + fp))
+	}
+	fmt.Println()
+	fmt.Println(infoStyle.Render("Press Ctrl+C or 'q' to stop the server\n"))
+
+	// Setup signal handling for graceful shutdown
+	ctx, cancel := context.WithCancel(context.Background())
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+	defer ownSignals()()
+
+	go func() {
+		<-sigChan
+		fmt.Println(warningStyle.Render("\n\nShutting down stream server..."))
+		cancel()
+	}()
+
+	// Setup keyboard input for 'q' to quit
+	go func() {
+		oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
+		if err != nil {
+			return
+		}
+		defer func() {
+			_ = term.Restore(int(os.Stdin.Fd()), oldState)
+		}()
+
+		b := make([]byte, 1)
+		for {
+			n, err := os.Stdin.Read(b)
+			if err != nil || n == 0 {
+				return
+			}
+			if b[0] == 'q' || b[0] == 'Q' {
+				fmt.Println(warningStyle.Render("\n\nShutting down stream server..."))
+				cancel()
+				return
+			}
+		}
+	}()
+
+	// Start server (blocks until context cancelled)
+	if err := server.Start(ctx); err != nil {
+		return fmt.Errorf("stream server failed: %w", err)
+	}
+
+	fmt.Println(successStyle.Render("✓ Stream server stopped"))
+	return nil
+}
+
+// handleQueueView displays queue and handles queue actions
+// Returns "done" (after download), "back" (continue browsing), or error
+func handleQueueView(cfg *config.Config, q *queue.Queue) (string, error) {
+	if q.IsEmpty() {
+		fmt.Println(warningStyle.Render("Queue is empty"))
+		return "back", nil
+	}
+
+	fmt.Println(titleStyle.Render("Download Queue"))
+	summary := fmt.Sprintf("%d item(s) in queue", q.Len())
+	if total, _ := download.TotalSize(q.Items); total > 0 {
+		summary += ", " + plex.FormatSize(total)
+	}
+	fmt.Println(infoStyle.Render(summary + ":\n"))
+
+	for i, item := range q.Items {
+		fmt.Printf("  %d. %s\n", i+1, ui.ListLabel(item))
+	}
+	fmt.Println()
+
+	// Prompt for queue action. "Transfer to Outplayer" is only offered when at
+	// least one Outplayer target is enabled (mirrors the browse action menu).
+	outplayerCount := len(cfg.GetEnabledOutplayerTargets())
+	var action string
+	var err error
+
+	if ui.IsAvailable(cfg.FzfPath) {
+		action, err = ui.PromptQueueAction(cfg.FzfPath, q.Len(), outplayerCount)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrCancelled) {
+				return "back", nil
+			}
+			return "", err
+		}
+	} else {
+		action, err = promptQueueActionManual(q.Len(), outplayerCount)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	switch action {
+	case "download":
+		// Capture keys of items being downloaded before starting
+		// This allows us to remove only these items after download,
+		// preserving any new items added by other instances during download
+		keysToRemove := make([]string, len(q.Items))
+		for i, item := range q.Items {
+			keysToRemove[i] = item.Key
+		}
+
+		err := handleDownloadMultiple(cfg, q.Items)
+		if err != nil {
+			return "", err
+		}
+
+		// Remove only the downloaded items (preserves items added during download)
+		if err := q.RemoveByKeys(keysToRemove); err != nil {
+			return "", fmt.Errorf("failed to update queue: %w", err)
+		}
+		return "done", nil
+
+	case "transfer":
+		// Transfers are non-destructive: the queue is left intact (the transfer
+		// handler returns nil on soft no-ops like cancelling target selection,
+		// so auto-removing here could silently clear the queue). Stay in the
+		// queue view so the user can also download or clear afterwards.
+		if err := handleTransferToWebDAV(cfg, q.Items); err != nil {
+			return "", err
+		}
+		return "back", nil
+
+	case "transfer-outplayer":
+		if err := handleTransferToOutplayer(cfg, q.Items); err != nil {
+			return "", err
+		}
+		return "back", nil
+
+	case "clear":
+		if err := q.Clear(); err != nil {
+			return "", fmt.Errorf("failed to clear queue: %w", err)
+		}
+		fmt.Println(successStyle.Render("Queue cleared"))
+		return "back", nil
+
+	case "remove":
+		if ui.IsAvailable(cfg.FzfPath) {
+			indices, err := ui.SelectQueueItemsForRemoval(q.Items, cfg.FzfPath)
+			if err != nil {
+				if errors.Is(err, apperrors.ErrCancelled) {
+					return "back", nil
+				}
+				return "", err
+			}
+			q.Remove(indices)
+			if err := q.Save(); err != nil {
+				return "", fmt.Errorf("failed to save queue: %w", err)
+			}
+			fmt.Println(successStyle.Render(fmt.Sprintf("Removed %d item(s) from queue", len(indices))))
+		} else {
+			err := removeFromQueueManual(q)
+			if err != nil {
+				return "", err
+			}
+		}
+		return "back", nil
+
+	case "back":
+		return "back", nil
+
+	default:
+		return "back", nil
+	}
+}
+
+// runQueueDownload drains the persisted queue, download_concurrency items at a
+// time, starting each only while --window is open. Each item is removed from the queue as soon as it finishes, so an
+// interrupted run (Ctrl-C, SIGTERM) leaves exactly the undownloaded items behind.
+func runQueueDownload(cmd *cobra.Command, args []string) error {
+	var window *queue.Window
+	var startAt time.Duration
+	if queueDownloadWindow != "" {
+		w, err := queue.ParseWindow(queueDownloadWindow)
+		if err != nil {
+			return err
+		}
+		window = &w
+	}
+	if queueDownloadAt != "" {
+		at, err := queue.ParseClock(queueDownloadAt)
+		if err != nil {
+			return err
+		}
+		startAt = at
+	}
+
+	cfg := appFrom(cmd).Config
+
+	q, err := queue.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load queue: %w", err)
+	}
+	if q.IsEmpty() {
+		fmt.Println(warningStyle.Render("Queue is empty"))
+		return nil
+	}
+
+	if dryRun {
+		return handleDownloadMultiple(cfg, q.Items)
+	}
+
+	if !download.IsAvailable(cfg.RclonePath) {
+		return fmt.Errorf("rclone is not installed. Please install rclone to download media")
+	}
+
+	destDir, err := cfg.ResolveDownloadDir(downloadDest)
+	if err != nil {
+		return fmt.Errorf("failed to resolve download directory: %w", err)
+	}
+	concurrency := cfg.GetDownloadConcurrency()
+
+	if err := checkDownloadSpace(destDir, q.Items); err != nil {
+		return err
+	}
+
+	ctx := appFrom(cmd).SignalContext()
+
+	if queueDownloadAt != "" {
+		if err := sleepUntil(ctx, queue.NextClock(time.Now(), startAt)); err != nil {
+			fmt.Println(warningStyle.Render(fmt.Sprintf("\n⚠ Stopped before starting; %d item(s) left in queue", q.Len())))
+			return nil
+		}
+	}
+
+	// Keys that failed or can't be downloaded this run; skipped so the worker
+	// doesn't spin on them, but left in the queue for a later attempt.
+	skipped := make(map[string]bool)
+	downloaded := 0
+
+	for {
+		if window != nil && !window.Contains(time.Now()) {
+			next := window.NextStart(time.Now())
+			fmt.Println(infoStyle.Render(fmt.Sprintf("Outside download window %s; sleeping until %s", window, next.Format("Mon 15:04"))))
+			if err := sleepUntil(ctx, next); err != nil {
+				break
+			}
+		}
+
+		// Reload each pass so items queued by other instances are picked up.
+		q, err = queue.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load queue: %w", err)
+		}
+
+		// Take every item not yet tried this run. The window is checked again
+		// before each one starts, so nothing new begins after it closes.
+		var batch []*plex.MediaItem
+		for _, it := range q.Items {
+			if skipped[it.Key] {
+				continue
+			}
+			if it.RclonePath == "" {
+				fmt.Println(warningStyle.Render(fmt.Sprintf("⚠ Skipping %s (no rclone path)", it.FormatMediaTitle())))
+				skipped[it.Key] = true
+				continue
+			}
+			batch = append(batch, it)
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		files, err := downloadFiles(cfg, destDir, batch)
+		if err != nil {
+			return err
+		}
+
+		// Remove each item from the queue the moment it lands, so an
+		// interrupt mid-batch keeps only the unfinished ones.
+		var saveErr error
+		var completed []*plex.MediaItem
+		var completedPaths []string
+		var failures []string
+		_ = download.DownloadMultipleGated(ctx, files, cfg.RclonePath, concurrency, windowGate(window), func(i int, err error) {
+			item := batch[i]
+			if err != nil {
+				if ctx.Err() == nil {
+					failures = append(failures, fmt.Sprintf("✗ %s: %v", item.FormatMediaTitle(), err))
+					skipped[item.Key] = true
+				}
+				return
+			}
+			if err := q.RemoveByKeys([]string{item.Key}); err != nil && saveErr == nil {
+				saveErr = err
+			}
+			downloaded++
+			completed = append(completed, item)
+			completedPaths = append(completedPaths, files[i].Dest)
+		})
+		for _, item := range completed {
+			fmt.Println(successStyle.Render(fmt.Sprintf("✓ Downloaded %s", item.FormatMediaTitle())))
+		}
+		for _, f := range failures {
+			fmt.Println(errorStyle.Render(f))
+		}
+		recordDownloads(completed, completedPaths)
+		runPostDownloadHooks(cfg, completed, completedPaths)
+		notifyDownloads(cfg, completed)
+		if saveErr != nil {
+			return fmt.Errorf("failed to update queue: %w", saveErr)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	remaining := 0
+	if q, err := queue.Load(); err == nil {
+		remaining = q.Len()
+	}
+	if ctx.Err() != nil {
+		fmt.Println(warningStyle.Render(fmt.Sprintf("\n⚠ Interrupted: downloaded %d item(s), %d left in queue", downloaded, remaining)))
+		return nil
+	}
+	if len(skipped) > 0 {
+		sendNotification(cfg, notify.EventQueueComplete, "Queue finished with errors", fmt.Sprintf("Downloaded %d item(s); %d remain in the queue", downloaded, len(skipped)))
+		return fmt.Errorf("downloaded %d item(s); %d could not be downloaded and remain in the queue", downloaded, len(skipped))
+	}
+	sendNotification(cfg, notify.EventQueueComplete, "Queue complete", fmt.Sprintf("Downloaded %d item(s)", downloaded))
+	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Queue complete: downloaded %d item(s)", downloaded)))
+	return nil
+}
+
+// windowGate returns a gate for download.DownloadMultipleGated that holds
+// each transfer until window is open. A nil window lets every one start.
+func windowGate(window *queue.Window) func(ctx context.Context) error {
+	if window == nil {
+		return nil
+	}
+	return func(ctx context.Context) error {
+		now := time.Now()
+		if window.Contains(now) {
+			return nil
+		}
+		// The progress view is running, so wait quietly: the transfer shows
+		// as pending until the window opens.
+		timer := time.NewTimer(window.NextStart(now).Sub(now))
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// sleepUntil blocks until t or until ctx is cancelled, returning ctx.Err() in
+// the latter case.
+func sleepUntil(ctx context.Context, t time.Time) error {
+	d := time.Until(t)
+	if d <= 0 {
+		return nil
+	}
+	fmt.Println(infoStyle.Render(fmt.Sprintf("Waiting until %s (%s)...", t.Format("Mon 15:04"), d.Round(time.Minute))))
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// promptQueueActionManual - fallback for no-fzf queue action selection.
+// "Transfer to Outplayer" is only listed when outplayerCount > 0, so the option
+// numbering is built dynamically.
+func promptQueueActionManual(queueCount, outplayerCount int) (string, error) {
+	type option struct {
+		label string
+		token string
+	}
+	options := []option{
+		{fmt.Sprintf("Download All (%s)", ui.PluralizeItems(queueCount)), "download"},
+		{"Transfer to WebDAV", "transfer"},
+	}
+	if outplayerCount > 0 {
+		options = append(options, option{"Transfer to Outplayer", "transfer-outplayer"})
+	}
+	options = append(options,
+		option{"Clear Queue", "clear"},
+		option{"Remove Items", "remove"},
+		option{"Back to Browse", "back"},
+	)
+
+	fmt.Println(infoStyle.Render("\nQueue actions:"))
+	for i, opt := range options {
+		fmt.Printf("  %d. %s\n", i+1, opt.label)
+	}
+	fmt.Printf("\nChoice (1-%d): ", len(options))
+
+	var choice int
+	if _, err := fmt.Scanln(&choice); err != nil {
+		return "", fmt.Errorf("failed to read selection: %w", err)
+	}
+	if choice < 1 || choice > len(options) {
+		return "back", nil
+	}
+	return options[choice-1].token, nil
+}
+
+// removeFromQueueManual - fallback for no-fzf queue item removal
+func removeFromQueueManual(q *queue.Queue) error {
+	fmt.Println(infoStyle.Render("\nSelect items to remove:"))
+	for i, item := range q.Items {
+		fmt.Printf("  %d. %s\n", i+1, item.FormatMediaTitle())
+	}
+	fmt.Print("\nEnter item numbers to remove (comma-separated, e.g., 1,3,5): ")
+
+	var input string
+	if _, err := fmt.Scanln(&input); err != nil {
+		return fmt.Errorf("failed to read selection: %w", err)
+	}
+
+	// Parse comma-separated indices
+	parts := strings.Split(input, ",")
+	var indices []int
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		var num int
+		if _, err := fmt.Sscanf(part, "%d", &num); err == nil {
+			if num >= 1 && num <= q.Len() {
+				indices = append(indices, num-1) // Convert to 0-based index
+			}
+		}
+	}
+
+	if len(indices) > 0 {
+		q.Remove(indices)
+		if err := q.Save(); err != nil {
+			return fmt.Errorf("failed to save queue: %w", err)
+		}
+		fmt.Println(successStyle.Render(fmt.Sprintf("Removed %d item(s) from queue", len(indices))))
+	}
+
+	return nil
+}
+
+// selectMediaTypeManualWithQueue - fallback for no-fzf with queue option
+func selectMediaTypeManualWithQueue(queueCount, continueCount, libraryCount int) (string, error) {
+	fmt.Println(infoStyle.Render("\nSelect media type:"))
+
+	type option struct {
+		label string
+		token string
+	}
+	var options []option
+	if queueCount > 0 {
+		options = append(options, option{fmt.Sprintf("View Queue (%s)", ui.PluralizeItems(queueCount)), "queue"})
+	}
+	if continueCount > 0 {
+		options = append(options, option{fmt.Sprintf("Continue Watching (%s)", ui.PluralizeItems(continueCount)), "continue watching"})
+	}
+	options = append(options,
+		option{"Recently Added Movies", "recently added movies"},
+		option{"Recently Added TV Shows", "recently added tv shows"},
+		option{"Movies", "movies"},
+		option{"TV Shows", "tv shows"},
+		option{"All", "all"},
+	)
+	if libraryCount > 1 {
+		options = append(options, option{fmt.Sprintf("Libraries (%d)", libraryCount), "libraries"})
+	}
+
+	for i, opt := range options {
+		fmt.Printf("  %d. %s\n", i+1, opt.label)
+	}
+	fmt.Printf("\nChoice (1-%d): ", len(options))
+
+	var choice int
+	if _, err := fmt.Scanln(&choice); err != nil {
+		return "", fmt.Errorf("failed to read selection: %w", err)
+	}
+	if choice < 1 || choice > len(options) {
+		return "", fmt.Errorf("invalid selection")
+	}
+	return options[choice-1].token, nil
+}
+
+// promptActionManualWithQueue - fallback for no-fzf action selection with queue.
+// "Transfer to Outplayer" is only listed when outplayerCount > 0, so the option
+// numbering is built dynamically.
+func promptActionManualWithQueue(selectionCount, queueCount, outplayerCount, localCount, seasonCount int) (string, error) {
+	queueLabel := fmt.Sprintf("Add (%d) to Queue", selectionCount)
+	if queueCount > 0 {
+		queueLabel = fmt.Sprintf("Add (%d) to Queue (%d)", selectionCount, queueCount)
+	}
+
+	type option struct {
+		label string
+		token string
+	}
+	var options []option
+	if localCount > 0 {
+		options = append(options, option{ui.LocalPlayLabel(selectionCount, localCount), "watch local"})
+	}
+	options = append(options, option{"Watch", "watch"})
+	if seasonCount > 0 {
+		options = append(options, option{ui.WatchSeasonLabel(seasonCount), "watch season"})
+	}
+	options = append(options,
+		option{"Download", "download"},
+		option{queueLabel, "queue"},
+		option{"Transfer to WebDAV", "transfer"},
+	)
+	if outplayerCount > 0 {
+		options = append(options, option{"Transfer to Outplayer", "transfer-outplayer"})
+	}
+	options = append(options, option{"More...", "more"}, option{"Cancel", "cancel"})
+
+	fmt.Println(infoStyle.Render("\nSelect action:"))
+	for i, opt := range options {
+		fmt.Printf("  %d. %s\n", i+1, opt.label)
+	}
+	fmt.Printf("\nChoice (1-%d): ", len(options))
+
+	var choice int
+	if _, err := fmt.Scanln(&choice); err != nil {
+		return "", fmt.Errorf("failed to read selection: %w", err)
+	}
+	if choice < 1 || choice > len(options) {
+		return "cancel", nil
+	}
+	return options[choice-1].token, nil
+}
+
+// promptMoreActionManual - fallback for no-fzf selection of the "More..." submenu.
+func promptMoreActionManual() (string, error) {
+	fmt.Println(infoStyle.Render("\nMore actions:"))
+	fmt.Println("  1. Watch with Preset...")
+	fmt.Println("  2. SenPlayer Play")
+	fmt.Println("  3. SenPlayer Download")
+	fmt.Println("  4. Stream")
+	fmt.Println("  5. Open on IMDb")
+	fmt.Println("  6. Delete from Server...")
+	fmt.Println("  7. Back")
+	fmt.Print("\nChoice (1-7): ")
+
+	var choice int
+	if _, err := fmt.Scanln(&choice); err != nil {
+		return "", fmt.Errorf("failed to read selection: %w", err)
+	}
+
+	switch choice {
+	case 1:
+		return "watch preset", nil
+	case 2:
+		return "senplayer play", nil
+	case 3:
+		return "senplayer download", nil
+	case 4:
+		return "stream", nil
+	case 5:
+		return "open imdb", nil
+	case 6:
+		return "delete", nil
+	default:
+		return "cancel", nil
+	}
+}
+
+// selectPlaybackPreset asks which playback preset to use, via fzf when
+// available. Returns apperrors.ErrCancelled if the user backs out.
+func selectPlaybackPreset(cfg *config.Config) (string, error) {
+	names := player.PresetNames(cfg.PlaybackPresets)
+
+	if ui.IsAvailable(cfg.FzfPath) {
+		selected, _, err := ui.SelectWithFzf(names, "Select preset:", cfg.FzfPath)
+		return selected, err
+	}
+
+	fmt.Println(infoStyle.Render("\nSelect preset:"))
+	for i, name := range names {
+		fmt.Printf("  %d. %s\n", i+1, name)
+	}
+	fmt.Printf("\nChoice (1-%d): ", len(names))
+
+	var choice int
+	if _, err := fmt.Scanln(&choice); err != nil {
+		return "", fmt.Errorf("failed to read selection: %w", err)
+	}
+	if choice < 1 || choice > len(names) {
+		return "", apperrors.ErrCancelled
+	}
+	return names[choice-1], nil
+}
+
+// errTokenExpired is returned when the Plex token has expired and the user
+// can't or won't sign in again on the spot.
+var errTokenExpired = fmt.Errorf("your Plex token has expired or was revoked; run 'goplexcli login' again")
+
+// ensureValidToken checks the account token with plex.tv before a long
+// operation and, if it has expired, offers to sign in again by PIN rather
+// than failing partway through. When plex.tv can't be reached the operation
+// goes ahead, since the servers may still accept the token.
+func ensureValidToken(ctx context.Context, cfg *config.Config) error {
+	checkCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	err := plex.ValidateToken(checkCtx, cfg.PlexToken)
+	cancel()
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, apperrors.ErrAuthRequired) {
+		logging.Warn("could not validate the Plex token", "error", err)
+		return nil
+	}
+
+	fmt.Println(warningStyle.Render("Your Plex token has expired or was revoked."))
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return errTokenExpired
+	}
+	fmt.Print("Sign in again now? [Y/n] ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if a := strings.ToLower(strings.TrimSpace(answer)); a == "n" || a == "no" {
+		return errTokenExpired
+	}
+	return reauthWithPIN(ctx, cfg)
+}
+
+// reauthWithPIN signs in again with a plex.tv/link code and saves the new
+// account token and per-server tokens to cfg.
+func reauthWithPIN(ctx context.Context, cfg *config.Config) error {
+	// Codes expire after about 15 minutes anyway.
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Minute)
+	defer cancel()
+
+	pin, err := plex.RequestPIN(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start sign-in: %w", err)
+	}
+	fmt.Println(infoStyle.Render(fmt.Sprintf("\nGo to https://plex.tv/link and enter the code %s, or open:", pin.Code)))
+	fmt.Println(pin.AuthURL())
+	fmt.Println(infoStyle.Render("\nWaiting for you to sign in..."))
+
+	token, err := plex.WaitForPIN(ctx, pin)
+	if err != nil {
+		return fmt.Errorf("sign-in failed: %w", err)
+	}
+	servers, err := plex.ServersForToken(token)
+	if err != nil {
+		return err
+	}
+
+	// Per-server tokens are reissued along with the account token.
+	for i, s := range cfg.Servers {
+		if srv, ok := matchPlexServer(s, servers); ok {
+			cfg.Servers[i].Token = srv.AccessToken
+			cfg.Servers[i].Owned = &srv.Owned
+		}
+	}
+	cfg.PlexToken = token
+	if cfg.HomeUser != "" {
+		fmt.Println(infoStyle.Render(fmt.Sprintf("Signed in as the account owner; run 'goplexcli home switch' to switch back to %s.", cfg.HomeUser)))
+		cfg.HomeUser, cfg.AdminToken = "", ""
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	fmt.Println(successStyle.Render("✓ Signed in again"))
+	return nil
+}
+
+func runCacheUpdate(cmd *cobra.Command, args []string) error {
+	return updateCache(appFrom(cmd).Config, false)
+}
+
+func runCacheReindex(cmd *cobra.Command, args []string) error {
+	return updateCache(appFrom(cmd).Config, true)
+}
+
+func updateCache(cfg *config.Config, fullReindex bool) error {
+	if err := ensureValidToken(context.Background(), cfg); err != nil {
+		return err
+	}
+
+	// An incremental update fetches only items added since the last cache and
+	// merges them in. A full reindex (or an empty/missing cache) fetches
+	// everything and replaces the cache.
+	var existing *cache.Cache
+	var err error
+	incremental := false
+	if !fullReindex {
+		existing, err = cache.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load existing cache: %w", err)
+		}
+		incremental = len(existing.Media) > 0
+	}
+
+	action := "Reindexing"
+	if !fullReindex {
+		action = "Updating"
+	}
+
+	fmt.Println(titleStyle.Render(action + " Media Cache"))
+
+	// Newest addedAt already cached, keyed by server name then item type
+	// ("movie"/"episode"). Used to fetch only newer items during incremental
+	// updates.
+	maxAdded := map[string]map[string]int64{}
+	if incremental {
+		for _, item := range existing.Media {
+			byType := maxAdded[item.ServerName]
+			if byType == nil {
+				byType = map[string]int64{}
+				maxAdded[item.ServerName] = byType
+			}
+			if item.AddedAt > byType[item.Type] {
+				byType[item.Type] = item.AddedAt
+			}
+		}
+	}
+	// sinceFor maps a library type ("movie"/"show") to the newest addedAt known
+	// for the matching item type on the given server.
+	sinceFor := func(serverName, libType string) int64 {
+		itemType := "movie"
+		if libType == "show" {
+			itemType = "episode"
+		}
+		if byType, ok := maxAdded[serverName]; ok {
+			return byType[itemType]
+		}
+		return 0
+	}
+
+	// Check if we have multiple servers
+	enabledServers := cfg.GetEnabledServers()
+
+	var media []plex.MediaItem
+	ctx := context.Background()
+
+	if len(enabledServers) > 1 {
+		// Multi-server mode
+		fmt.Println(infoStyle.Render(fmt.Sprintf("Found %d enabled servers", len(enabledServers))))
+
+		// Build server configs
+		var serverConfigs []struct{ Name, URL, Token string }
+		for _, server := range enabledServers {
+			serverConfigs = append(serverConfigs, struct{ Name, URL, Token string }{
+				Name:  server.Name,
+				URL:   reachableServerURL(ctx, cfg, server),
+				Token: cfg.TokenForServer(server),
+			})
+		}
+
+		serverProgress := func(serverName, libraryName string, itemCount, totalItems, totalLibs, currentLib, serverNum, totalServers int) {
+			progress := fmt.Sprintf("%d items", itemCount)
+			if totalItems > 0 {
+				progress = fmt.Sprintf("%d/%d items", itemCount, totalItems)
+			}
+			printProgress("%s [Server %d/%d: %s] [%d/%d] %s: %s",
+				infoStyle.Render("Processing"),
+				serverNum,
+				totalServers,
+				serverName,
+				currentLib,
+				totalLibs,
+				libraryName,
+				progress,
+			)
+		}
+		mappings := toPlexPathMappings(cfg.PathMappings)
+		if incremental {
+			media, err = plex.GetNewMediaFromServers(ctx, serverConfigs, mappings, sinceFor, serverProgress)
+		} else {
+			media, err = plex.GetAllMediaFromServers(ctx, serverConfigs, mappings, serverProgress)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get media: %w", err)
+		}
+	} else {
+		// Single-server mode (legacy or single enabled server)
+		var serverURL, serverToken string
+		if len(enabledServers) == 1 {
+			serverURL = reachableServerURL(ctx, cfg, enabledServers[0])
+			serverToken = cfg.TokenForServer(enabledServers[0])
+		} else {
+			serverURL = cfg.PlexURL
+			serverToken = cfg.TokenForURL(serverURL)
+		}
+
+		fmt.Println(infoStyle.Render("Connecting to Plex server..."))
+
+		// Create Plex client
+		client, err := plex.New(serverURL, serverToken)
+		if err != nil {
+			return fmt.Errorf("failed to create plex client: %w", err)
+		}
+		client.SetPathMappings(toPlexPathMappings(cfg.PathMappings))
+
+		// Test connection
+		if err := client.Test(); err != nil {
+			return fmt.Errorf("failed to connect to plex server: %w", err)
+		}
+
+		fmt.Println(successStyle.Render("✓ Connected to Plex server"))
+		fmt.Println(infoStyle.Render("Fetching media library..."))
+
+		// Get media with progress
+		libraryProgress := func(libraryName string, itemCount, totalItems, totalLibs, currentLib int) {
+			progress := fmt.Sprintf("%d items", itemCount)
+			if totalItems > 0 {
+				progress = fmt.Sprintf("%d/%d items", itemCount, totalItems)
+			}
+			printProgress("%s [%d/%d] %s: %s",
+				infoStyle.Render("Processing libraries"),
+				currentLib,
+				totalLibs,
+				libraryName,
+				progress,
+			)
+		}
+		if incremental {
+			// The client uses serverURL as its server name when none is set,
+			// matching how items were tagged when first cached.
+			media, err = client.GetMediaSince(ctx, func(libType string) int64 {
+				return sinceFor(serverURL, libType)
+			}, libraryProgress)
+		} else {
+			media, err = client.GetAllMedia(ctx, libraryProgress)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get media: %w", err)
+		}
+	}
+
+	endProgress()
+
+	// For incremental updates, merge the newly fetched items into the existing
+	// cache (deduping by server + key); a full reindex replaces it outright.
+	finalMedia := media
+	if incremental {
+		merged, added := mergeMedia(existing.Media, media)
+		finalMedia = merged
+		if added == 0 {
+			fmt.Println(successStyle.Render("✓ Cache is already up to date — no new items"))
+		} else {
+			fmt.Println(successStyle.Render(fmt.Sprintf("✓ Added %d new item(s)", added)))
+		}
+	} else {
+		fmt.Println(successStyle.Render(fmt.Sprintf("✓ Retrieved %d media items", len(finalMedia))))
+	}
+
+	// Save to cache
+	mediaCache := &cache.Cache{
+		Media: finalMedia,
+	}
+	if cfg.Dedupe != "" {
+		if removed := mediaCache.Dedupe(cache.DedupePolicy(strings.ToLower(cfg.Dedupe))); len(removed) > 0 {
+			fmt.Println(successStyle.Render(fmt.Sprintf("✓ Removed %d duplicate(s) shared between servers", len(removed))))
+		}
+		finalMedia = mediaCache.Media
+	}
+	if incremental {
+		// An update only fetches new items; pick up what was watched since
+		// on the ones already cached.
+		changed, err := syncWatched(ctx, cfg, mediaCache)
+		if err != nil {
+			fmt.Println(warningStyle.Render(fmt.Sprintf("Watched state not fully synced: %v", err)))
+		}
+		if changed > 0 {
+			fmt.Println(successStyle.Render(fmt.Sprintf("✓ Synced watched state of %d item(s)", changed)))
+		}
+	}
+	if cfg.TMDBAPIKey != "" {
+		enrichFromTMDB(ctx, cfg, mediaCache.Media)
+	}
+
+	if err := mediaCache.Save(); err != nil {
+		return fmt.Errorf("failed to save cache: %w", err)
+	}
+
+	fmt.Println(successStyle.Render("✓ Cache saved successfully"))
+
+	// Count by type and by server
+	movieCount := 0
+	episodeCount := 0
+	serverCounts := make(map[string]int)
+
+	for _, item := range finalMedia {
+		switch item.Type {
+		case "movie":
+			movieCount++
+		case "episode":
+			episodeCount++
+		}
+		if item.ServerName != "" {
+			serverCounts[item.ServerName]++
+		}
+	}
+
+	fmt.Println(infoStyle.Render(fmt.Sprintf("\nTotal items: %d", len(finalMedia))))
+	fmt.Println(infoStyle.Render(fmt.Sprintf("  Movies: %d", movieCount)))
+	fmt.Println(infoStyle.Render(fmt.Sprintf("  Episodes: %d", episodeCount)))
+
+	if len(serverCounts) > 1 {
+		fmt.Println(infoStyle.Render("\nBy server:"))
+		for serverName, count := range serverCounts {
+			fmt.Println(infoStyle.Render(fmt.Sprintf("  %s: %d items", serverName, count)))
+		}
+	}
+
+	event, done := notify.EventCacheUpdated, "Cache updated"
+	if fullReindex {
+		event, done = notify.EventCacheReindexed, "Cache reindexed"
+	}
+	sendNotification(cfg, event, done, fmt.Sprintf("%d movies, %d episodes", movieCount, episodeCount))
+	return nil
+}
+
+func runCacheSyncWatched(cmd *cobra.Command, args []string) error {
+	app := appFrom(cmd)
+	cfg, mediaCache := app.Config, app.Cache
+	if len(mediaCache.Media) == 0 {
+		fmt.Println(warningStyle.Render("Cache is empty. Run 'goplexcli cache reindex' first."))
+		return nil
+	}
+	ctx := cmd.Context()
+	if err := ensureValidToken(ctx, cfg); err != nil {
+		return err
+	}
+
+	fmt.Println(titleStyle.Render("Syncing Watched State"))
+	changed, syncErr := syncWatched(ctx, cfg, mediaCache)
+	if changed > 0 {
+		if err := mediaCache.Save(); err != nil {
+			return fmt.Errorf("failed to save cache: %w", err)
+		}
+		fmt.Println(successStyle.Render(fmt.Sprintf("✓ Updated the watched state of %d item(s)", changed)))
+	} else if syncErr == nil {
+		fmt.Println(successStyle.Render("✓ Watched state is already up to date"))
+	}
+	return syncErr
+}
+
+// syncWatched copies the watched state of every cached item from its server
+// into mediaCache and returns how many items changed. Servers are named as
+// updateCache names them, so their items match. A server that fails is
+// reported and skipped; the error then counts the failures. The caller
+// saves the cache.
+func syncWatched(ctx context.Context, cfg *config.Config, mediaCache *cache.Cache) (int, error) {
+	type server struct{ name, url, token string }
+	var servers []server
+	if enabled := cfg.GetEnabledServers(); len(enabled) > 1 {
+		for _, s := range enabled {
+			servers = append(servers, server{s.Name, reachableServerURL(ctx, cfg, s), cfg.TokenForServer(s)})
+		}
+	} else if len(enabled) == 1 {
+		serverURL := reachableServerURL(ctx, cfg, enabled[0])
+		servers = append(servers, server{serverURL, serverURL, cfg.TokenForServer(enabled[0])})
+	} else {
+		servers = append(servers, server{cfg.PlexURL, cfg.PlexURL, cfg.TokenForURL(cfg.PlexURL)})
+	}
+
+	changed, failures := 0, 0
+	for _, s := range servers {
+		client, err := plex.NewWithName(s.url, s.token, s.name)
+		if err != nil {
+			return changed, fmt.Errorf("failed to create plex client: %w", err)
+		}
+		label := serverLabel(cfg, s.url)
+		states, err := client.GetWatchStates(ctx, func(fetched int) {
+			printProgress("%s %s: %d items", infoStyle.Render("Syncing watched state"), label, fetched)
+		})
+		endProgress()
+		if err != nil {
+			fmt.Println(warningStyle.Render(fmt.Sprintf("✗ %s: %v", label, err)))
+			failures++
+			continue
+		}
+		changed += mediaCache.ApplyWatchStates(s.name, states)
+	}
+	if failures > 0 {
+		return changed, fmt.Errorf("%d server(s) could not be synced", failures)
+	}
+	return changed, nil
+}
+
+// enrichFromTMDB fills in taglines, recommendations and missing summaries
+// and posters from TMDB, looking up only what the TMDB store lacks. Problems
+// are reported as warnings so they never fail the cache update.
+func enrichFromTMDB(ctx context.Context, cfg *config.Config, media []plex.MediaItem) {
+	store, err := tmdb.LoadStore()
+	if err != nil {
+		fmt.Println(warningStyle.Render(fmt.Sprintf("Skipping TMDB enrichment: %v", err)))
+		return
+	}
+
+	progressShown := false
+	fetched, failed, err := tmdb.New(cfg.TMDBAPIKey).Enrich(ctx, store, media, func(done, total int) {
+		progressShown = ui.Interactive()
+		printProgress("%s %d/%d", infoStyle.Render("Looking up items on TMDB"), done, total)
+	})
+	if progressShown {
+		fmt.Println()
+	}
+	if recordErr := tmdb.RecordEntries(fetched); recordErr != nil {
+		logging.Warn("failed to save TMDB store", "error", recordErr)
+	}
+
+	switch {
+	case err != nil:
+		fmt.Println(warningStyle.Render(fmt.Sprintf("TMDB enrichment stopped: %v", err)))
+	case failed > 0:
+		fmt.Println(warningStyle.Render(fmt.Sprintf("%d TMDB lookup(s) failed; they will be retried on the next update", failed)))
+	case len(fetched) > 0:
+		fmt.Println(successStyle.Render(fmt.Sprintf("✓ Looked up %d item(s) on TMDB", len(fetched))))
+	}
+}
+
+// mergeMedia combines newly fetched items into the existing cached items,
+// deduplicating by server name and key. Items present in both are replaced
+// with the freshly fetched version (picking up metadata changes). It returns
+// the merged slice and the number of items that were newly added.
+func mergeMedia(existing, fetched []plex.MediaItem) ([]plex.MediaItem, int) {
+	keyOf := func(m plex.MediaItem) string { return m.ServerName + "\x00" + m.Key }
+
+	merged := make([]plex.MediaItem, len(existing))
+	copy(merged, existing)
+
+	index := make(map[string]int, len(merged))
+	for i := range merged {
+		index[keyOf(merged[i])] = i
+	}
+
+	added := 0
+	for _, item := range fetched {
+		k := keyOf(item)
+		if i, ok := index[k]; ok {
+			merged[i] = item
+			continue
+		}
+		index[k] = len(merged)
+		merged = append(merged, item)
+		added++
+	}
+
+	return merged, added
+}
+
+func runCacheDedupe(cmd *cobra.Command, args []string) error {
+	app := appFrom(cmd)
+	policy := cmp.Or(cacheDedupePolicy, app.Config.Dedupe, string(cache.DedupeLocal))
+	switch cache.DedupePolicy(strings.ToLower(policy)) {
+	case cache.DedupeLocal, cache.DedupeQuality:
+	default:
+		return fmt.Errorf("invalid policy %q: must be \"local\" or \"quality\"", policy)
+	}
+
+	mediaCache := app.Cache
+	withGUID := 0
+	for _, item := range mediaCache.Media {
+		if item.GUID != "" {
+			withGUID++
+		}
+	}
+	if withGUID == 0 && len(mediaCache.Media) > 0 {
+		return fmt.Errorf("the cache has no GUIDs to match items by; run 'goplexcli cache reindex' first")
+	}
+
+	before := len(mediaCache.Media)
+	removed := mediaCache.Dedupe(cache.DedupePolicy(strings.ToLower(policy)))
+	if len(removed) == 0 {
+		fmt.Println(successStyle.Render("✓ No duplicates found"))
+		return nil
+	}
+
+	fmt.Println(titleStyle.Render(fmt.Sprintf("Duplicates (%d)", len(removed))))
+	for _, item := range removed {
+		fmt.Printf("  %s  %s\n", item.FormatMediaTitle(), infoStyle.Render("on "+item.ServerName))
+	}
+	if dryRun {
+		fmt.Println(warningStyle.Render(fmt.Sprintf("\nDry run: would remove %d of %d items (policy %s).", len(removed), before, policy)))
+		return nil
+	}
+	if err := mediaCache.Save(); err != nil {
+		return fmt.Errorf("failed to save cache: %w", err)
+	}
+	fmt.Println(successStyle.Render(fmt.Sprintf("\n✓ Removed %d duplicate(s); the cache now has %d items", len(removed), len(mediaCache.Media))))
+	return nil
+}
+
+func runCacheInfo(cmd *cobra.Command, args []string) error {
+	// Loaded here rather than through requireCache: cache info is useful
+	// before a login, too.
+	mediaCache, err := appFrom(cmd).MediaCache()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(titleStyle.Render("Cache Information"))
+
+	if len(mediaCache.Media) == 0 {
+		fmt.Println(warningStyle.Render("Cache is empty"))
+		return nil
+	}
+
+	fmt.Println(infoStyle.Render(fmt.Sprintf("Total items: %d", len(mediaCache.Media))))
+	fmt.Println(infoStyle.Render(fmt.Sprintf("Last updated: %s", mediaCache.LastUpdated.Local().Format(time.RFC822))))
+
+	// Count by type
+	movieCount := 0
+	episodeCount := 0
+	for _, item := range mediaCache.Media {
+		switch item.Type {
+		case "movie":
+			movieCount++
+		case "episode":
+			episodeCount++
+		}
+	}
+
+	fmt.Println(infoStyle.Render(fmt.Sprintf("Movies: %d", movieCount)))
+	fmt.Println(infoStyle.Render(fmt.Sprintf("Episodes: %d", episodeCount)))
+
+	if count, size, err := posters.Usage(); err == nil && count > 0 {
+		fmt.Println(infoStyle.Render(fmt.Sprintf("Posters: %d (%s)", count, plex.FormatSize(size))))
+	}
+
+	return nil
+}
+
+// posterFetchConcurrency bounds parallel poster downloads in 'cache
+// posters', to stay gentle on the Plex server.
+const posterFetchConcurrency = 6
+
+func runCachePosters(cmd *cobra.Command, args []string) error {
+	app := appFrom(cmd)
+	cfg, mediaCache := app.Config, app.Cache
+	ctx := app.SignalContext()
+
+	// Movie posters, and each show's poster once rather than every
+	// episode's still.
+	type poster struct{ serverURL, thumb string }
+	seen := make(map[poster]bool)
+	var todo []poster
+	for _, item := range mediaCache.Media {
+		thumb := item.Thumb
+		if item.Type == "episode" {
+			thumb = item.GrandparentThumb
+		}
+		serverURL := item.ServerURL
+		if serverURL == "" {
+			serverURL = cfg.PlexURL
+		}
+		p := poster{serverURL, thumb}
+		if thumb == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		todo = append(todo, p)
+	}
+	if len(todo) == 0 {
+		fmt.Println(warningStyle.Render("No posters to fetch. Run 'goplexcli cache reindex' first."))
+		return nil
+	}
+
+	fmt.Println(infoStyle.Render(fmt.Sprintf("Fetching %d posters...", len(todo))))
+	client := &http.Client{Timeout: 30 * time.Second}
+	var fetched, failed, done atomic.Int64
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(posterFetchConcurrency)
+	for _, p := range todo {
+		g.Go(func() error {
+			_, got, err := posters.Fetch(gctx, client, p.serverURL, p.thumb, cfg.TokenForURL(p.serverURL))
+			switch {
+			case err != nil:
+				failed.Add(1)
+				logging.Warn("poster fetch failed", "thumb", p.thumb, "err", err)
+			case got:
+				fetched.Add(1)
+			}
+			fmt.Printf("\r  %d/%d", done.Add(1), len(todo))
+			return nil
+		})
+	}
+	_ = g.Wait()
+	fmt.Println()
+	if ctx.Err() != nil {
+		fmt.Println(warningStyle.Render(fmt.Sprintf("Interrupted; %d poster(s) fetched so far are kept.", fetched.Load())))
+		return nil
+	}
+
+	removed, err := posters.Prune(posters.MaxBytes)
+	if err != nil {
+		return fmt.Errorf("failed to prune poster cache: %w", err)
+	}
+	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Fetched %d poster(s); %d already cached", fetched.Load(), int64(len(todo))-fetched.Load()-failed.Load())))
+	if failed.Load() > 0 {
+		fmt.Println(warningStyle.Render(fmt.Sprintf("⚠ %d poster(s) couldn't be fetched", failed.Load())))
+	}
+	if removed > 0 {
+		fmt.Println(warningStyle.Render(fmt.Sprintf("⚠ The poster cache is full; evicted %d least recently used poster(s)", removed)))
+	}
+	return nil
+}
+
+func runConfig(cmd *cobra.Command, args []string) error {
+	cfg := appFrom(cmd).Config
+
+	fmt.Println(titleStyle.Render("Configuration"))
+
+	if cfg.PlexURL == "" {
+		fmt.Println(warningStyle.Render("Not logged in. Run 'goplexcli login' first."))
+		return nil
+	}
+
+	if name := config.Profile(); name != "" {
+		fmt.Println(infoStyle.Render("Profile: " + name))
+	}
+	fmt.Println(infoStyle.Render("Plex URL: " + cfg.PlexURL))
+	if cfg.PlexUsername != "" {
+		fmt.Println(infoStyle.Render("Username: " + cfg.PlexUsername))
+	}
+	if cfg.HomeUser != "" {
+		fmt.Println(infoStyle.Render("Home user: " + cfg.HomeUser))
+	}
+	// Safely truncate token display to avoid panic on short tokens
+	tokenDisplay := cfg.PlexToken
+	if len(tokenDisplay) > 10 {
+		tokenDisplay = tokenDisplay[:10] + "..."
+	}
+	fmt.Println(infoStyle.Render("Token: " + tokenDisplay))
+
+	downloadDir := "(current directory)"
+	if cfg.DownloadDir != "" {
+		downloadDir = cfg.DownloadDir
+	}
+	fmt.Println(infoStyle.Render("Download dir: " + downloadDir))
+
+	configPath, _ := config.GetConfigPath()
+	fmt.Println(infoStyle.Render("\nConfig file: " + configPath))
+
+	cachePath, _ := cache.GetCachePath()
+	fmt.Println(infoStyle.Render("Cache file: " + cachePath))
+
+	return nil
+}
+
+func runUpdate(cmd *cobra.Command, args []string) error {
+	fmt.Println(titleStyle.Render("Update"))
+	ctx := context.Background()
+	return update.Run(ctx, update.DefaultRepo, version, updateCheckOnly, os.Stdout)
+}
+
+func runStream(cmd *cobra.Command, args []string) error {
+	cfg := appFrom(cmd).Config
+
+	fmt.Println(titleStyle.Render("Stream Discovery"))
+	fmt.Println(infoStyle.Render("Searching for goplexcli servers on local network...\n"))
+
+	// Discover servers with 3 second timeout
+	ctx := context.Background()
+	servers, err := stream.Discover(ctx, 3*time.Second)
+	if err != nil {
+		return fmt.Errorf("discovery failed: %w", err)
+	}
+
+	if len(servers) == 0 {
+		fmt.Println(warningStyle.Render("No stream servers found on the network"))
+		fmt.Println(infoStyle.Render("\nTo publish a stream:"))
+		fmt.Println(infoStyle.Render("  1. Run 'goplexcli browse' on another device"))
+		fmt.Println(infoStyle.Render("  2. Select a media item"))
+		fmt.Println(infoStyle.Render("  3. Choose 'Stream' option"))
+		return nil
+	}
+
+	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Found %d server(s)\n", len(servers))))
+
+	// Let user select a server if multiple found
+	var selectedServer *stream.DiscoveredServer
+	if len(servers) == 1 {
+		selectedServer = servers[0]
+		fmt.Println(infoStyle.Render(fmt.Sprintf("Connecting to: %s", selectedServer.Name)))
+	} else {
+		// Format servers for selection
+		var serverNames []string
+		for _, srv := range servers {
+			serverNames = append(serverNames, fmt.Sprintf("%s (%s)", srv.Name, serverAddress(srv)))
+		}
+
+		idx, err := chooseIndex(cfg, serverNames, "server")
+		if err != nil {
+			if errors.Is(err, apperrors.ErrCancelled) {
+				return nil
+			}
+			return err
+		}
+		selectedServer = servers[idx]
+	}
+
+	// Fetch streams from selected server
+	fmt.Println(infoStyle.Render("\nFetching available streams..."))
+	token, err := streamTokenFor(cfg, selectedServer)
+	if err != nil {
+		return err
+	}
+	streams, err := stream.FetchStreams(selectedServer, token)
+	if err != nil {
+		return fmt.Errorf("failed to fetch streams: %w", err)
+	}
+
+	if len(streams) == 0 {
+		fmt.Println(warningStyle.Render("No streams available on this server"))
+		return nil
+	}
+
+	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Found %d stream(s)\n", len(streams))))
+
+	// Let user select a stream
+	var selectedStream *stream.StreamItem
+	if len(streams) == 1 {
+		selectedStream = streams[0]
+	} else {
+		// Format streams for selection
+		var streamTitles []string
+		for _, s := range streams {
+			streamTitles = append(streamTitles, s.Title)
+		}
+
+		idx, err := chooseIndex(cfg, streamTitles, "stream")
+		if err != nil {
+			if errors.Is(err, apperrors.ErrCancelled) {
+				return nil
+			}
+			return err
+		}
+		selectedStream = streams[idx]
+	}
+
+	return playReceivedStream(cfg, selectedStream)
+}
+
+// runReceive discovers every stream server on the LAN, lists the streams
+// of all of them in one picker, and plays the chosen one locally.
+func runReceive(cmd *cobra.Command, args []string) error {
+	cfg := appFrom(cmd).Config
+
+	fmt.Println(infoStyle.Render("Searching for goplexcli servers on local network..."))
+	servers, err := stream.Discover(appFrom(cmd).SignalContext(), receiveTimeout)
+	if err != nil {
+		return fmt.Errorf("discovery failed: %w", err)
+	}
+	if len(servers) == 0 {
+		fmt.Println(warningStyle.Render("No stream servers found on the network"))
+		fmt.Println(infoStyle.Render("Run 'goplexcli serve' on another device to publish something."))
+		return nil
+	}
+
+	var (
+		found  []*stream.StreamItem
+		labels []string
+	)
+	for _, srv := range servers {
+		token, err := streamTokenFor(cfg, srv)
+		if err != nil {
+			return err
+		}
+		streams, err := stream.FetchStreams(srv, token)
+		if err != nil {
+			// One unreachable or locked server shouldn't hide the others.
+			fmt.Println(warningStyle.Render(fmt.Sprintf("⚠ %s (%s): %v", srv.Name, serverAddress(srv), err)))
+			continue
+		}
+		for _, item := range streams {
+			found = append(found, item)
+			labels = append(labels, fmt.Sprintf("%s  [%s]", item.Title, srv.Name))
+		}
+	}
+	if len(found) == 0 {
+		fmt.Println(warningStyle.Render(fmt.Sprintf("No streams published on %d server(s)", len(servers))))
+		return nil
+	}
+
+	idx, err := chooseIndex(cfg, labels, "stream")
+	if err != nil {
+		if errors.Is(err, apperrors.ErrCancelled) {
+			return nil
+		}
+		return err
+	}
+	return playReceivedStream(cfg, found[idx])
+}
+
+// partyHost returns the watch party to join: the one at --host, or one
+// found on the network. It returns nil when none was found or the pick was
+// cancelled.
+func partyHost(app *App) (*stream.DiscoveredServer, error) {
+	if partyJoinHost != "" {
+		return manualServer(partyJoinHost, partyJoinFingerprint)
+	}
+
+	fmt.Println(infoStyle.Render("Searching for watch parties on local network..."))
+	servers, err := stream.Discover(app.SignalContext(), 3*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("discovery failed: %w", err)
+	}
+	var hosts []*stream.DiscoveredServer
+	var names []string
+	for _, srv := range servers {
+		if srv.Party {
+			hosts = append(hosts, srv)
+			names = append(names, fmt.Sprintf("%s (%s)", srv.Name, serverAddress(srv)))
+		}
+	}
+	if len(hosts) == 0 {
+		fmt.Println(warningStyle.Render("No watch parties found on the network"))
+		fmt.Println(infoStyle.Render("Run 'goplexcli party host' on another device to start one."))
+		return nil, nil
+	}
+	host := hosts[0]
+	if len(hosts) > 1 {
+		idx, err := chooseIndex(app.Config, names, "party")
+		if err != nil {
+			if errors.Is(err, apperrors.ErrCancelled) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		host = hosts[idx]
+	}
+	return host, nil
+}
+
+// manualServer describes the stream server at addr ("host:port") for
+// commands given an address instead of discovering one. fingerprint pins
+// the certificate of a server that serves HTTPS.
+func manualServer(addr, fingerprint string) (*stream.DiscoveredServer, error) {
+	h, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid host %q, want host:port: %w", addr, err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port < 1 || port > 65535 {
+		return nil, fmt.Errorf("invalid port in host %q", addr)
+	}
+	if h == "" {
+		return nil, fmt.Errorf("invalid host %q, want host:port", addr)
+	}
+	return &stream.DiscoveredServer{
+		Name:        addr,
+		Host:        h,
+		Port:        port,
+		Addresses:   []string{h},
+		Fingerprint: fingerprint,
+	}, nil
+}
+
+// serverAddress returns the first address a discovered server advertised.
+func serverAddress(srv *stream.DiscoveredServer) string {
+	if len(srv.Addresses) > 0 {
+		return srv.Addresses[0]
+	}
+	return "unknown"
+}
+
+// streamTokenFor returns the access token to send to srv: --token, then
+// stream_token from the config, then a prompt if the server requires one.
+func streamTokenFor(cfg *config.Config, srv *stream.DiscoveredServer) (string, error) {
+	token := streamToken
+	if token == "" {
+		token = cfg.StreamToken
+	}
+	if token == "" && srv.AuthRequired {
+		fmt.Printf("%s requires an access token: ", srv.Name)
+		if _, err := fmt.Scanln(&token); err != nil {
+			return "", fmt.Errorf("failed to read token: %w", err)
+		}
+	}
+	return token, nil
+}
+
+// chooseIndex lets the user pick one of labels, with fzf when available
+// and a numbered prompt otherwise. noun names the items in prompts.
+func chooseIndex(cfg *config.Config, labels []string, noun string) (int, error) {
+	if ui.IsAvailable(cfg.FzfPath) {
+		_, idx, err := ui.SelectWithFzf(labels, "Select "+noun+":", cfg.FzfPath)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrCancelled) {
+				return 0, err
+			}
+			return 0, fmt.Errorf("%s selection failed: %w", noun, err)
+		}
+		return idx, nil
+	}
+
+	// Fallback to manual selection
+	fmt.Println(infoStyle.Render(fmt.Sprintf("Available %ss:", noun)))
+	for i, label := range labels {
+		fmt.Printf("  %d. %s\n", i+1, label)
+	}
+	fmt.Printf("\nSelect %s number: ", noun)
+	var choice int
+	if _, err := fmt.Scanln(&choice); err != nil {
+		return 0, fmt.Errorf("failed to read selection: %w", err)
+	}
+	if choice < 1 || choice > len(labels) {
+		return 0, fmt.Errorf("invalid selection")
+	}
+	return choice - 1, nil
+}
+
+// playReceivedStream plays a stream published by another device in the
+// configured player, or prints its URL when the player isn't installed.
+func playReceivedStream(cfg *config.Config, selectedStream *stream.StreamItem) error {
+	// Show stream info
+	fmt.Println(infoStyle.Render("\nStream: " + selectedStream.Title))
+	if selectedStream.Year > 0 {
+		fmt.Println(infoStyle.Render(fmt.Sprintf("Year: %d", selectedStream.Year)))
+	}
+	if selectedStream.Duration > 0 {
+		fmt.Println(infoStyle.Render(fmt.Sprintf("Duration: %d min", selectedStream.Duration/60000)))
+	}
+
+	// Check if the configured player is available
+	playerName := cfg.PlayerName()
+	if !player.IsPlayerAvailable(playerName, cfg.PlayerPath()) {
+		fmt.Println(warningStyle.Render(fmt.Sprintf("\n%s not found. You can still play the stream manually:", playerName)))
+		fmt.Println(infoStyle.Render(selectedStream.StreamURL))
+		return nil
+	}
+
+	fmt.Println(successStyle.Render("\n✓ Starting playback..."))
+
+	if _, err := player.PlayMultipleWith(playerName, cfg.PlayerPath(), []string{selectedStream.StreamURL}, player.PlaybackOptions{Titles: []string{selectedStream.Title}}); err != nil {
+		return fmt.Errorf("playback failed: %w", err)
+	}
+
+	fmt.Println(successStyle.Render("✓ Playback finished"))
+	return nil
+}
+
+func runCacheSearch(cmd *cobra.Command, args []string) error {
+	searchTitle := strings.Join(args, " ")
+
+	app := appFrom(cmd)
+	cfg, mediaCache := app.Config, app.Cache
+
+	fmt.Println(titleStyle.Render("Searching for: " + searchTitle))
+
+	// Search in cache first
+	fmt.Println(infoStyle.Render("\n=== Checking Cache ==="))
+
+	foundInCache := false
+	for _, item := range mediaCache.Media {
+		if strings.Contains(strings.ToLower(item.Title), strings.ToLower(searchTitle)) {
+			foundInCache = true
+			fmt.Println(successStyle.Render("✓ Found in cache:"))
+			fmt.Printf("  Title: %s\n", item.FormatMediaTitle())
+			fmt.Printf("  Type: %s\n", item.Type)
+			fmt.Printf("  Key: %s\n", item.Key)
+			fmt.Printf("  FilePath: %s\n", item.FilePath)
+			fmt.Printf("  RclonePath: %s\n", item.RclonePath)
+			fmt.Println()
+		}
+	}
+
+	if !foundInCache {
+		fmt.Println(warningStyle.Render("✗ Not found in cache"))
+	}
+
+	// Search in Plex directly
+	fmt.Println(infoStyle.Render("=== Checking Plex Server ==="))
+
+	client, err := plex.New(cfg.PlexURL, cfg.TokenForURL(cfg.PlexURL))
+	if err != nil {
+		return fmt.Errorf("failed to create plex client: %w", err)
+	}
+
+	if err := client.Test(); err != nil {
+		return fmt.Errorf("failed to connect to plex server: %w", err)
+	}
+
+	ctx := context.Background()
+	libraries, err := client.GetLibraries(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get libraries: %w", err)
+	}
+
+	foundInPlex := false
+	for _, lib := range libraries {
+		if lib.Type != "movie" && lib.Type != "show" {
+			continue
+		}
+
+		media, err := client.GetMediaFromSection(ctx, lib.Key, lib.Type)
+		if err != nil {
+			return fmt.Errorf("failed to get media from section %s: %w", lib.Title, err)
+		}
+
+		for _, item := range media {
+			if strings.Contains(strings.ToLower(item.Title), strings.ToLower(searchTitle)) {
+				foundInPlex = true
+				fmt.Println(successStyle.Render(fmt.Sprintf("✓ Found in Plex library '%s':", lib.Title)))
+				fmt.Printf("  Title: %s\n", item.FormatMediaTitle())
+				fmt.Printf("  Type: %s\n", item.Type)
+				fmt.Printf("  Year: %d\n", item.Year)
+				fmt.Printf("  Key: %s\n", item.Key)
+				fmt.Printf("  FilePath: %s\n", item.FilePath)
+				fmt.Printf("  RclonePath: %s\n", item.RclonePath)
+
+				if item.FilePath == "" {
+					fmt.Println(warningStyle.Render("  ⚠ WARNING: No file path found!"))
+				}
+				fmt.Println()
 			}
-			selected = targets[choice-1]
 		}
 	}
 
-	if dryRun {
-		fmt.Println(warningStyle.Render(fmt.Sprintf("\n[DRY RUN] Would upload %d file(s) to %s (%s):", len(rclonePaths), selected.Name, selected.URL)))
-		for _, p := range rclonePaths {
-			fmt.Println(infoStyle.Render("  - " + p))
-		}
-		return nil
+	if !foundInPlex {
+		fmt.Println(warningStyle.Render("✗ Not found in Plex"))
 	}
 
-	ctx := context.Background()
+	// Summary
+	fmt.Println(infoStyle.Render("=== Summary ==="))
+	if foundInCache && foundInPlex {
+		fmt.Println(successStyle.Render("✓ Item exists in both cache and Plex"))
+	} else if !foundInCache && foundInPlex {
+		fmt.Println(warningStyle.Render("⚠ Item exists in Plex but NOT in cache"))
+		fmt.Println(infoStyle.Render("  Run 'goplexcli cache reindex' to update the cache"))
+	} else if foundInCache && !foundInPlex {
+		fmt.Println(warningStyle.Render("⚠ Item exists in cache but NOT in Plex (stale cache)"))
+		fmt.Println(infoStyle.Render("  Run 'goplexcli cache reindex' to update the cache"))
+	} else {
+		fmt.Println(warningStyle.Render("✗ Item not found in either cache or Plex"))
+	}
 
-	// Fail fast if the target is unreachable (e.g. Wi-Fi transfer is off).
-	checkCtx, cancel := context.WithTimeout(ctx, 8*time.Second)
-	err := outplayer.Reachable(checkCtx, selected.URL)
-	cancel()
+	return nil
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	rows, err := export.Catalog(appFrom(cmd).Cache.Media, exportType)
 	if err != nil {
-		return fmt.Errorf("cannot reach %s (%s): %w\nMake sure Outplayer's Wi-Fi transfer is enabled and on the same network", selected.Name, selected.URL, err)
+		return err
 	}
-
-	fmt.Println(successStyle.Render(fmt.Sprintf("\n✓ Uploading %d item(s) to %s...", len(rclonePaths), selected.Name)))
-	if err := outplayer.Upload(ctx, rclonePaths, selected.URL, selected.Dir, cfg.RclonePath); err != nil {
-		return fmt.Errorf("upload failed: %w", err)
+	if !slices.Contains(export.CatalogFormats, exportFormat) {
+		return fmt.Errorf("unknown format %q (want %s)", exportFormat, strings.Join(export.CatalogFormats, ", "))
 	}
 
-	fmt.Println(successStyle.Render("✓ All uploads complete"))
+	if exportOutput == "" {
+		return export.WriteCatalog(os.Stdout, exportFormat, rows)
+	}
+	f, err := os.Create(exportOutput)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", exportOutput, err)
+	}
+	if err := export.WriteCatalog(f, exportFormat, rows); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", exportOutput, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", exportOutput, err)
+	}
+	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Wrote %d item(s) to %s", len(rows), exportOutput)))
 	return nil
 }
 
-func handleSenPlayer(cfg *config.Config, mediaItems []*plex.MediaItem, mode string) error {
-	if len(mediaItems) == 0 {
-		return fmt.Errorf("no media items provided")
+// m3uSource is one item of an exported playlist: a cached show episode or
+// movie, or a playlist or album track the cache doesn't index.
+type m3uSource struct {
+	item      *plex.MediaItem
+	serverURL string
+	// partKey, if set, is streamed directly instead of resolving item.Key.
+	partKey string
+}
+
+func runExportM3U(cmd *cobra.Command, args []string) error {
+	title := strings.Join(args, " ")
+
+	app := appFrom(cmd)
+	cfg, mediaCache := app.Config, app.Cache
+
+	// Plex clients per server, since a multi-server cache mixes items.
+	clients := make(map[string]*plex.Client)
+	clientFor := func(serverURL string) (*plex.Client, error) {
+		if serverURL == "" {
+			serverURL = cfg.PlexURL
+		}
+		if c, ok := clients[serverURL]; ok {
+			return c, nil
+		}
+		c, err := newStreamClient(cfg, serverURL)
+		if err != nil {
+			return nil, err
+		}
+		clients[serverURL] = c
+		return c, nil
 	}
 
-	// SenPlayer only supports one item at a time
-	if len(mediaItems) > 1 {
-		fmt.Println(warningStyle.Render("Note: SenPlayer only supports single selection, using first item"))
+	var sources []m3uSource
+	for _, item := range export.ResolveTitle(mediaCache.Media, title) {
+		sources = append(sources, m3uSource{item: item, serverURL: item.ServerURL})
+	}
+	if len(sources) == 0 {
+		// Playlists and albums come from the server: the cache doesn't
+		// index music, so their tracks stream from their own media parts.
+		client, err := clientFor(cfg.PlexURL)
+		if err != nil {
+			return err
+		}
+		tracks, err := serverTracks(client, title)
+		if err != nil {
+			return err
+		}
+		skipped := 0
+		for _, t := range tracks {
+			if t.PartKey == "" {
+				skipped++
+				continue
+			}
+			item := &plex.MediaItem{Key: t.Key, Title: t.Title, Type: "track", Duration: t.Duration}
+			sources = append(sources, m3uSource{item: item, serverURL: cfg.PlexURL, partKey: t.PartKey})
+		}
+		if skipped > 0 {
+			fmt.Println(warningStyle.Render(fmt.Sprintf("⚠ Skipping %d item(s) without a media file", skipped)))
+		}
+	}
+	if len(sources) == 0 {
+		return fmt.Errorf("no show, movie, playlist, or album named %q", title)
 	}
 
-	media := mediaItems[0]
-	actionText := "Playing"
-	if mode == "download" {
-		actionText = "Downloading"
+	// With --proxy, the URLs point at a stream server this command runs,
+	// so the file carries no Plex token.
+	var server *stream.Server
+	var webURL string
+	if exportProxy {
+		var err error
+		if server, err = stream.NewServer(exportPort); err != nil {
+			return fmt.Errorf("failed to create stream server: %w", err)
+		}
+		if _, err := secureStreamServer(cfg, server); err != nil {
+			return err
+		}
+		server.EnableProxy()
+		webURL = fmt.Sprintf("%s://%s:%d", server.Scheme(), stream.GetLocalIP(), exportPort)
 	}
-	fmt.Println(infoStyle.Render(fmt.Sprintf("\nPreparing for SenPlayer (%s): %s", actionText, media.FormatMediaTitle())))
 
-	// Create Plex client
-	client, err := plex.New(cfg.PlexURL, cfg.TokenForURL(cfg.PlexURL))
-	if err != nil {
-		return fmt.Errorf("failed to create plex client: %w", err)
+	fmt.Println(infoStyle.Render(fmt.Sprintf("Resolving stream URLs for %d item(s)...", len(sources))))
+	entries := make([]export.Entry, 0, len(sources))
+	for _, src := range sources {
+		client, err := clientFor(src.serverURL)
+		if err != nil {
+			return err
+		}
+		var streamURL string
+		if src.partKey != "" {
+			streamURL = client.PartStreamURL(src.partKey)
+		} else if streamURL, err = getStreamURL(client, src.item.Key); err != nil {
+			return fmt.Errorf("failed to get stream URL for %s: %w", src.item.FormatMediaTitle(), err)
+		}
+		if server != nil {
+			id := server.PublishStream(src.item, streamURL, client.ServerURL(), cfg.TokenForURL(src.serverURL))
+			streamURL = webURL + server.StreamPath(id)
+		}
+		entries = append(entries, export.Entry{
+			Title:    src.item.FormatMediaTitle(),
+			Duration: src.item.Duration / 1000,
+			URL:      streamURL,
+		})
 	}
 
-	// Get stream URL
-	streamURL, err := client.GetStreamURL(media.Key)
+	output := exportOutput
+	if output == "" {
+		output = strings.NewReplacer("/", "_", `\`, "_", ":", "_").Replace(title) + ".m3u8"
+	}
+	f, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
 	if err != nil {
-		return fmt.Errorf("failed to get stream URL: %w", err)
+		return fmt.Errorf("failed to create %s: %w", output, err)
 	}
-
-	// Build filename from media title
-	filename := media.Title
-	if media.Year > 0 {
-		filename = fmt.Sprintf("%s (%d)", media.Title, media.Year)
+	if err := export.WriteM3U(f, entries); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", output, err)
 	}
-	filename += ".mkv"
-
-	var senplayerURL string
-	if mode == "download" {
-		// Download format: SenPlayer://x-callback-url/download?url=<url>&name=<filename>
-		senplayerURL = fmt.Sprintf("SenPlayer://x-callback-url/download?url=%s&name=%s",
-			url.QueryEscape(streamURL),
-			url.QueryEscape(filename),
-		)
-	} else {
-		// Play format: SenPlayer://x-callback-url/play?url=<url>&name=<filename>&User-Agent=<ua>
-		senplayerURL = fmt.Sprintf("SenPlayer://x-callback-url/play?url=%s&name=%s&User-Agent=%s",
-			url.QueryEscape(streamURL),
-			url.QueryEscape(filename),
-			url.QueryEscape("GoplexCLI/1.0"),
-		)
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
 	}
 
-	// On macOS, open the URL directly
-	if runtime.GOOS == "darwin" {
-		fmt.Println(infoStyle.Render("Opening in SenPlayer..."))
-		cmd := exec.Command("open", senplayerURL)
-		if err := cmd.Run(); err != nil {
-			// If open fails, fall back to showing the URL
-			fmt.Println(warningStyle.Render("Could not open SenPlayer automatically"))
-			fmt.Println(infoStyle.Render("\nCopy this URL to open in SenPlayer:"))
-			fmt.Println(senplayerURL)
-		} else {
-			fmt.Println(successStyle.Render(fmt.Sprintf("✓ Sent to SenPlayer (%s)", mode)))
-		}
-	} else {
-		// On other platforms, show the URL for manual copying
-		fmt.Println(infoStyle.Render("\nCopy this URL to open in SenPlayer:"))
-		fmt.Println(senplayerURL)
+	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Wrote %d item(s) to %s", len(entries), output)))
+	if server == nil {
+		fmt.Println(warningStyle.Render("⚠ The playlist contains your Plex token; don't share it."))
+		fmt.Println(infoStyle.Render("To share one without it, rerun with --proxy."))
+		return nil
 	}
 
+	fmt.Println(infoStyle.Render("The playlist plays through this computer. Press Ctrl+C to stop.\n"))
+	if err := server.Start(app.SignalContext()); err != nil {
+		return fmt.Errorf("stream server failed: %w", err)
+	}
+	fmt.Println(warningStyle.Render("Stream server stopped"))
 	return nil
 }
 
-func handleStream(cfg *config.Config, media *plex.MediaItem) error {
-	fmt.Println(infoStyle.Render("\nPublishing stream: " + media.FormatMediaTitle()))
-
-	// Create Plex client
-	client, err := plex.New(cfg.PlexURL, cfg.TokenForURL(cfg.PlexURL))
+// serverTracks looks up a Plex playlist, then a music album, by name
+// (case-insensitive) and returns its tracks. Returns nil if neither exists.
+func serverTracks(client *plex.Client, name string) ([]plex.Track, error) {
+	ctx := context.Background()
+	playlists, err := client.GetPlaylists(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to create plex client: %w", err)
+		return nil, fmt.Errorf("failed to list playlists: %w", err)
+	}
+	for _, p := range playlists {
+		if !strings.EqualFold(p.Title, name) {
+			continue
+		}
+		tracks, err := client.GetPlaylistTracks(ctx, p.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load playlist %q: %w", p.Title, err)
+		}
+		return tracks, nil
 	}
 
-	// Get stream URL
-	streamURL, err := client.GetStreamURL(media.Key)
+	albumKey, err := client.FindAlbum(ctx, name)
 	if err != nil {
-		return fmt.Errorf("failed to get stream URL: %w", err)
+		return nil, fmt.Errorf("failed to search albums: %w", err)
 	}
-
-	// Create and start stream server
-	server, err := stream.NewServer(stream.DefaultPort)
+	if albumKey == "" {
+		return nil, nil
+	}
+	tracks, err := client.GetAlbumTracks(ctx, albumKey)
 	if err != nil {
-		return fmt.Errorf("failed to create stream server: %w", err)
+		return nil, fmt.Errorf("failed to load album %q: %w", name, err)
 	}
+	return tracks, nil
+}
 
-	// Publish the stream
-	streamID := server.PublishStream(media, streamURL, cfg.PlexURL, cfg.TokenForURL(cfg.PlexURL))
+func runStatsUsage(cmd *cobra.Command, args []string) error {
+	cfg := appFrom(cmd).Config
 
-	localIP := stream.GetLocalIP()
-	webURL := fmt.Sprintf("http://%s:%d", localIP, stream.DefaultPort)
+	if statsEnable || statsDisable {
+		cfg.UsageStats = statsEnable
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		if statsEnable {
+			fmt.Println(successStyle.Render("✓ Usage statistics enabled (stored locally only)"))
+		} else {
+			fmt.Println(successStyle.Render("✓ Usage statistics disabled"))
+		}
+	}
+	if statsReset {
+		if err := usage.Reset(); err != nil {
+			return fmt.Errorf("failed to reset usage stats: %w", err)
+		}
+		fmt.Println(successStyle.Render("✓ Usage statistics cleared"))
+	}
+	if statsEnable || statsDisable || statsReset {
+		return nil
+	}
 
-	// URL encode for deep links
-	encodedURL := url.QueryEscape(streamURL)
+	fmt.Println(titleStyle.Render("Usage Statistics"))
 
-	fmt.Println(successStyle.Render("✓ Stream published"))
-	fmt.Println(infoStyle.Render(fmt.Sprintf("Stream ID: %s", streamID)))
-	fmt.Println(infoStyle.Render(fmt.Sprintf("Title: %s", media.FormatMediaTitle())))
-	fmt.Println(warningStyle.Render(fmt.Sprintf("\nStream server running on port %d", stream.DefaultPort)))
+	path, err := usage.Path()
+	if err != nil {
+		return err
+	}
+	stats, err := usage.LoadFrom(path)
+	if err != nil {
+		return fmt.Errorf("failed to load usage stats: %w", err)
+	}
 
-	fmt.Println(successStyle.Render("\nClick to open in your player:"))
+	if !cfg.UsageStats {
+		fmt.Println(warningStyle.Render("Recording is off. Run 'goplexcli stats usage --enable' to start."))
+	}
+	if len(stats.Commands) == 0 {
+		fmt.Println(infoStyle.Render("No usage recorded yet."))
+		return nil
+	}
+
+	fmt.Println(infoStyle.Render(fmt.Sprintf("Since %s (%s)", time.Unix(stats.Since, 0).Format("2006-01-02"), path)))
 	fmt.Println()
+	fmt.Printf("%-24s %6s %10s  %s\n", "COMMAND", "RUNS", "MEDIAN", "LAST USED")
+	for _, e := range stats.Sorted() {
+		fmt.Printf("%-24s %6d %10s  %s\n", e.Name, e.Count, e.Median().Round(10*time.Millisecond), time.Unix(e.LastUsed, 0).Format("2006-01-02 15:04"))
+	}
 
-	playerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#C084FC")).Bold(true).Width(12)
-	linkStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#60A5FA")).Underline(true)
+	return nil
+}
 
-	fmt.Printf("  %s %s\n\n", playerStyle.Render("Infuse"), linkStyle.Render(fmt.Sprintf("infuse://x-callback-url/play?url=%s", encodedURL)))
-	fmt.Printf("  %s %s\n\n", playerStyle.Render("OutPlayer"), linkStyle.Render(fmt.Sprintf("outplayer://x-callback-url/play?url=%s", encodedURL)))
-	fmt.Printf("  %s %s\n\n", playerStyle.Render("SenPlayer"), linkStyle.Render(fmt.Sprintf("senplayer://x-callback-url/play?url=%s", encodedURL)))
-	fmt.Printf("  %s %s\n\n", playerStyle.Render("VLC"), linkStyle.Render(fmt.Sprintf("vlc://%s", encodedURL)))
-	fmt.Printf("  %s %s\n", playerStyle.Render("VidHub"), linkStyle.Render(fmt.Sprintf("open-vidhub://x-callback-url/open?url=%s", encodedURL)))
+func runHistory(cmd *cobra.Command, args []string) error {
+	sessions, err := recentSessions(args)
+	if err != nil {
+		return err
+	}
+	if len(sessions) == 0 {
+		fmt.Println(infoStyle.Render("No matching playback history."))
+		return nil
+	}
 
-	fmt.Println()
-	fmt.Println(successStyle.Render("Web UI: ") + linkStyle.Render(webURL))
-	fmt.Println()
-	fmt.Println(infoStyle.Render("Press Ctrl+C or 'q' to stop the server\n"))
+	fmt.Println(titleStyle.Render("Playback History"))
+	for _, s := range sessions {
+		fmt.Println("  " + historySessionLabel(s) + "  " + infoStyle.Render(s.Player))
+	}
+	return nil
+}
 
-	// Setup signal handling for graceful shutdown
-	ctx, cancel := context.WithCancel(context.Background())
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+func runHistoryReplay(cmd *cobra.Command, args []string) error {
+	app := appFrom(cmd)
+	cfg, mediaCache := app.Config, app.Cache
 
-	go func() {
-		<-sigChan
-		fmt.Println(warningStyle.Render("\n\nShutting down stream server..."))
-		cancel()
-	}()
+	sessions, err := recentSessions(args)
+	if err != nil {
+		return err
+	}
 
-	// Setup keyboard input for 'q' to quit
-	go func() {
-		oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
-		if err != nil {
-			return
+	// Offer each item once, at its latest session, and only if it's still
+	// in the library.
+	seen := make(map[string]bool)
+	var items []*plex.MediaItem
+	var labels []string
+	for _, s := range sessions {
+		if seen[s.Key] {
+			continue
 		}
-		defer func() {
-			_ = term.Restore(int(os.Stdin.Fd()), oldState)
-		}()
-
-		b := make([]byte, 1)
-		for {
-			n, err := os.Stdin.Read(b)
-			if err != nil || n == 0 {
-				return
-			}
-			if b[0] == 'q' || b[0] == 'Q' {
-				fmt.Println(warningStyle.Render("\n\nShutting down stream server..."))
-				cancel()
-				return
+		seen[s.Key] = true
+		for i := range mediaCache.Media {
+			if mediaCache.Media[i].Key == s.Key {
+				items = append(items, &mediaCache.Media[i])
+				labels = append(labels, historySessionLabel(s))
+				break
 			}
 		}
-	}()
+	}
+	if len(items) == 0 {
+		fmt.Println(infoStyle.Render("Nothing in the matching history is in the cache to replay."))
+		return nil
+	}
 
-	// Start server (blocks until context cancelled)
-	if err := server.Start(ctx); err != nil {
-		return fmt.Errorf("stream server failed: %w", err)
+	idx, err := chooseIndex(cfg, labels, "item")
+	if err != nil {
+		if errors.Is(err, apperrors.ErrCancelled) {
+			return nil
+		}
+		return err
 	}
+	return handleWatchMultiple(app, items[idx:idx+1])
+}
 
-	fmt.Println(successStyle.Render("✓ Stream server stopped"))
-	return nil
+// recentSessions loads the history and applies the title and --since and
+// --limit filters.
+func recentSessions(args []string) ([]history.Session, error) {
+	var since time.Time
+	if historySince != "" {
+		var err error
+		if since, err = history.ParseSince(historySince, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	hist, err := history.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load playback history: %w", err)
+	}
+	return hist.Recent(strings.Join(args, " "), since, historyLimit), nil
 }
 
-// handleQueueView displays queue and handles queue actions
-// Returns "done" (after download), "back" (continue browsing), or error
-func handleQueueView(cfg *config.Config, q *queue.Queue) (string, error) {
-	if q.IsEmpty() {
-		fmt.Println(warningStyle.Render("Queue is empty"))
-		return "back", nil
+func historySessionLabel(s history.Session) string {
+	label := fmt.Sprintf("%s  %s  %s watched",
+		time.Unix(s.Started, 0).Format("Mon 2006-01-02 15:04"),
+		s.Title,
+		s.Watched().Round(time.Minute),
+	)
+	if pct := s.Percent(); pct >= 0 {
+		label += fmt.Sprintf(", ended at %d%%", pct)
 	}
+	return label
+}
 
-	fmt.Println(titleStyle.Render("Download Queue"))
-	fmt.Println(infoStyle.Render(fmt.Sprintf("%d item(s) in queue:\n", q.Len())))
+func runHistoryItem(cmd *cobra.Command, args []string) error {
+	query := strings.Join(args, " ")
 
-	for i, item := range q.Items {
-		fmt.Printf("  %d. %s\n", i+1, item.FormatMediaTitle())
+	hist, err := history.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load playback history: %w", err)
 	}
-	fmt.Println()
 
-	// Prompt for queue action. "Transfer to Outplayer" is only offered when at
-	// least one Outplayer target is enabled (mirrors the browse action menu).
-	outplayerCount := len(cfg.GetEnabledOutplayerTargets())
-	var action string
-	var err error
+	items := hist.Find(query)
+	if len(items) == 0 {
+		fmt.Println(infoStyle.Render(fmt.Sprintf("No playback history for %q", query)))
+		return nil
+	}
 
-	if ui.IsAvailable(cfg.FzfPath) {
-		action, err = ui.PromptQueueAction(cfg.FzfPath, q.Len(), outplayerCount)
-		if err != nil {
-			if errors.Is(err, apperrors.ErrCancelled) {
-				return "back", nil
-			}
-			return "", err
+	for i, item := range items {
+		if i > 0 {
+			fmt.Println()
 		}
-	} else {
-		action, err = promptQueueActionManual(q.Len(), outplayerCount)
-		if err != nil {
-			return "", err
+		fmt.Println(titleStyle.Render(item.Title))
+		for _, s := range item.Sessions {
+			line := fmt.Sprintf("  %s  %8s → %-8s  %s watched",
+				time.Unix(s.Started, 0).Format("Mon 2006-01-02 15:04"),
+				progress.FormatDuration(s.StartMs),
+				progress.FormatDuration(s.EndMs),
+				s.Watched().Round(time.Minute),
+			)
+			if s.DurationMs > 0 {
+				line += fmt.Sprintf(" of %s", progress.FormatDuration(s.DurationMs))
+			}
+			fmt.Println(line + "  " + infoStyle.Render(s.Player))
 		}
 	}
+	return nil
+}
 
-	switch action {
-	case "download":
-		// Capture keys of items being downloaded before starting
-		// This allows us to remove only these items after download,
-		// preserving any new items added by other instances during download
-		keysToRemove := make([]string, len(q.Items))
-		for i, item := range q.Items {
-			keysToRemove[i] = item.Key
-		}
+func runCalendar(cmd *cobra.Command, args []string) error {
+	app := appFrom(cmd)
+	media := app.Cache.Media
 
-		err := handleDownloadMultiple(cfg, q.Items)
-		if err != nil {
-			return "", err
+	now := time.Now()
+	year, month := now.Year(), now.Month()
+	if calendarMonth != "" {
+		var err error
+		if year, month, err = calendar.ParseMonth(calendarMonth); err != nil {
+			return err
 		}
+	}
 
-		// Remove only the downloaded items (preserves items added during download)
-		if err := q.RemoveByKeys(keysToRemove); err != nil {
-			return "", fmt.Errorf("failed to update queue: %w", err)
-		}
-		return "done", nil
+	follow, scope := calendarShows(app.Favorites, media)
+	entries := calendar.Episodes(media, follow)
 
-	case "transfer":
-		// Transfers are non-destructive: the queue is left intact (the transfer
-		// handler returns nil on soft no-ops like cancelling target selection,
-		// so auto-removing here could silently clear the queue). Stay in the
-		// queue view so the user can also download or clear afterwards.
-		if err := handleTransferToWebDAV(cfg, q.Items); err != nil {
-			return "", err
-		}
-		return "back", nil
+	fmt.Println(titleStyle.Render("Air Dates"))
+	fmt.Println(infoStyle.Render(scope))
+	fmt.Println()
+	calendar.RenderMonth(os.Stdout, year, month, entries, now)
+	fmt.Println()
 
-	case "transfer-outplayer":
-		if err := handleTransferToOutplayer(cfg, q.Items); err != nil {
-			return "", err
-		}
-		return "back", nil
+	inMonth := calendar.InMonth(entries, year, month)
+	if len(inMonth) == 0 {
+		fmt.Println(infoStyle.Render("Nothing aired this month."))
+	}
+	for _, e := range inMonth {
+		fmt.Printf("  %s  %s\n", e.Date.Format("Mon Jan 02"), e.Item.FormatMediaTitle())
+	}
 
-	case "clear":
-		if err := q.Clear(); err != nil {
-			return "", fmt.Errorf("failed to clear queue: %w", err)
+	upcoming := calendar.Upcoming(entries, now)
+	if len(upcoming) > 0 {
+		fmt.Println()
+		fmt.Println(titleStyle.Render("Upcoming"))
+		for _, e := range upcoming {
+			fmt.Printf("  %s  %s\n", e.Date.Format("Mon Jan 02 2006"), e.Item.FormatMediaTitle())
 		}
-		fmt.Println(successStyle.Render("Queue cleared"))
-		return "back", nil
+	}
+	return nil
+}
 
-	case "remove":
-		if ui.IsAvailable(cfg.FzfPath) {
-			indices, err := ui.SelectQueueItemsForRemoval(q.Items, cfg.FzfPath)
-			if err != nil {
-				if errors.Is(err, apperrors.ErrCancelled) {
-					return "back", nil
-				}
-				return "", err
-			}
-			q.Remove(indices)
-			if err := q.Save(); err != nil {
-				return "", fmt.Errorf("failed to save queue: %w", err)
-			}
-			fmt.Println(successStyle.Render(fmt.Sprintf("Removed %d item(s) from queue", len(indices))))
-		} else {
-			err := removeFromQueueManual(q)
-			if err != nil {
-				return "", err
+// calendarShows picks the shows the calendar covers and describes the
+// choice: every show with --all, else the favorited shows, else the shows
+// with any watched or started episode, else every show.
+func calendarShows(fav *favorites.Store, media []plex.MediaItem) (func(show string) bool, string) {
+	if calendarAll {
+		return nil, "All shows"
+	}
+
+	shows := map[string]bool{}
+	if keys, err := fav.Keys(); err != nil {
+		logging.Warn("failed to load favorites", "error", err)
+	} else {
+		for _, k := range keys {
+			if show, ok := strings.CutPrefix(k, "show:"); ok {
+				shows[show] = true
 			}
 		}
-		return "back", nil
-
-	case "back":
-		return "back", nil
-
-	default:
-		return "back", nil
 	}
-}
+	if len(shows) > 0 {
+		return func(show string) bool { return shows[show] }, "Favorite shows (--all for every show)"
+	}
 
-// promptQueueActionManual - fallback for no-fzf queue action selection.
-// "Transfer to Outplayer" is only listed when outplayerCount > 0, so the option
-// numbering is built dynamically.
-func promptQueueActionManual(queueCount, outplayerCount int) (string, error) {
-	type option struct {
-		label string
-		token string
+	for _, item := range media {
+		if item.Type == "episode" && (item.ViewCount > 0 || item.ViewOffset > 0) {
+			shows[item.ParentTitle] = true
+		}
 	}
-	options := []option{
-		{fmt.Sprintf("Download All (%s)", ui.PluralizeItems(queueCount)), "download"},
-		{"Transfer to WebDAV", "transfer"},
+	if len(shows) > 0 {
+		return func(show string) bool { return shows[show] }, "Shows you've watched (favorite shows to choose, or --all for every show)"
 	}
-	if outplayerCount > 0 {
-		options = append(options, option{"Transfer to Outplayer", "transfer-outplayer"})
+	return nil, "All shows"
+}
+
+func runDelete(cmd *cobra.Command, args []string) error {
+	title := strings.Join(args, " ")
+
+	app := appFrom(cmd)
+	items := export.ResolveTitle(app.Cache.Media, title)
+	if len(items) == 0 {
+		return fmt.Errorf("no show or movie named %q in the cache", title)
 	}
-	options = append(options,
-		option{"Clear Queue", "clear"},
-		option{"Remove Items", "remove"},
-		option{"Back to Browse", "back"},
-	)
+	return handleDelete(app, items)
+}
 
-	fmt.Println(infoStyle.Render("\nQueue actions:"))
-	for i, opt := range options {
-		fmt.Printf("  %d. %s\n", i+1, opt.label)
+// handleDelete deletes items from their Plex servers after the user types
+// "delete" to confirm, then drops them from the local cache. With --dry-run
+// it only lists them.
+func handleDelete(app *App, items []*plex.MediaItem) error {
+	cfg := app.Config
+	fmt.Println(titleStyle.Render(fmt.Sprintf("Delete %s from Plex", ui.PluralizeItems(len(items)))))
+	for _, item := range items {
+		line := "  " + item.FormatMediaTitle()
+		if item.ServerName != "" {
+			line += infoStyle.Render("  (" + item.ServerName + ")")
+		}
+		fmt.Println(line)
+		if deleteAndFiles {
+			fmt.Println(infoStyle.Render("    " + item.RclonePath))
+		}
 	}
-	fmt.Printf("\nChoice (1-%d): ", len(options))
 
-	var choice int
-	if _, err := fmt.Scanln(&choice); err != nil {
-		return "", fmt.Errorf("failed to read selection: %w", err)
+	if dryRun {
+		fmt.Println(warningStyle.Render("\nDry run: nothing was deleted."))
+		return nil
 	}
-	if choice < 1 || choice > len(options) {
-		return "back", nil
+	if deleteAndFiles && !download.IsAvailable(cfg.RclonePath) {
+		return fmt.Errorf("rclone not found in PATH. Please install rclone or specify rclone_path in config")
 	}
-	return options[choice-1].token, nil
-}
 
-// removeFromQueueManual - fallback for no-fzf queue item removal
-func removeFromQueueManual(q *queue.Queue) error {
-	fmt.Println(infoStyle.Render("\nSelect items to remove:"))
-	for i, item := range q.Items {
-		fmt.Printf("  %d. %s\n", i+1, item.FormatMediaTitle())
+	fmt.Println(warningStyle.Render("\nPlex deletes the media files too. This can't be undone."))
+	fmt.Print(`Type "delete" to confirm: `)
+	reader := bufio.NewReader(os.Stdin)
+	answer, _ := reader.ReadString('\n')
+	if strings.TrimSpace(answer) != "delete" {
+		fmt.Println(warningStyle.Render("Deletion cancelled."))
+		return nil
 	}
-	fmt.Print("\nEnter item numbers to remove (comma-separated, e.g., 1,3,5): ")
 
-	var input string
-	if _, err := fmt.Scanln(&input); err != nil {
-		return fmt.Errorf("failed to read selection: %w", err)
-	}
+	// Plex clients per server, since a multi-server selection mixes items.
+	clients := make(map[string]*plex.Client)
+	ctx := context.Background()
+	var removed []*plex.MediaItem
+	var logEntries []deleted.Entry
+	var failures int
+	for _, item := range items {
+		serverURL := item.ServerURL
+		if serverURL == "" {
+			serverURL = cfg.PlexURL
+		}
+		client, ok := clients[serverURL]
+		if !ok {
+			c, err := plex.New(serverURL, cfg.TokenForURL(serverURL))
+			if err != nil {
+				return fmt.Errorf("failed to create plex client: %w", err)
+			}
+			client, clients[serverURL] = c, c
+		}
 
-	// Parse comma-separated indices
-	parts := strings.Split(input, ",")
-	var indices []int
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		var num int
-		if _, err := fmt.Sscanf(part, "%d", &num); err == nil {
-			if num >= 1 && num <= q.Len() {
-				indices = append(indices, num-1) // Convert to 0-based index
+		// Snapshot the external IDs first: once deleted, the server can't
+		// say what the item was.
+		guids, err := client.GetGUIDs(ctx, item.RatingKey())
+		if err != nil {
+			logging.Warn("failed to fetch GUIDs for the deletion log", "title", item.FormatMediaTitle(), "error", err)
+			guids = item.ExternalIDs()
+		}
+
+		if err := client.DeleteItem(ctx, item.RatingKey()); err != nil {
+			fmt.Println(errorStyle.Render(fmt.Sprintf("✗ %s: %v", item.FormatMediaTitle(), err)))
+			failures++
+			continue
+		}
+		if deleteAndFiles && item.RclonePath != "" {
+			if err := download.DeleteRemote(ctx, item.RclonePath, cfg.RclonePath); err != nil {
+				fmt.Println(warningStyle.Render(fmt.Sprintf("⚠ %s: removed from Plex, but %v", item.FormatMediaTitle(), err)))
 			}
 		}
+		removed = append(removed, item)
+		logEntries = append(logEntries, deleted.FromItem(item, guids, time.Now()))
+		fmt.Println(successStyle.Render("✓ Deleted " + item.FormatMediaTitle()))
 	}
 
-	if len(indices) > 0 {
-		q.Remove(indices)
-		if err := q.Save(); err != nil {
-			return fmt.Errorf("failed to save queue: %w", err)
+	if len(removed) > 0 {
+		if err := deleted.Record(logEntries...); err != nil {
+			fmt.Println(warningStyle.Render(fmt.Sprintf("⚠ Failed to record the deletions in the local log: %v", err)))
+		}
+
+		mediaCache, err := app.ReloadCache()
+		if err != nil {
+			logging.Warn("failed to load cache to drop deleted items", "error", err)
+		} else if mediaCache.RemoveItems(removed) > 0 {
+			if err := mediaCache.Save(); err != nil {
+				logging.Warn("failed to drop deleted items from the cache", "error", err)
+			}
 		}
-		fmt.Println(successStyle.Render(fmt.Sprintf("Removed %d item(s) from queue", len(indices))))
 	}
 
+	if failures > 0 {
+		return fmt.Errorf("%d of %d item(s) could not be deleted", failures, len(items))
+	}
 	return nil
 }
 
-// selectMediaTypeManualWithQueue - fallback for no-fzf with queue option
-func selectMediaTypeManualWithQueue(queueCount, continueCount int) (string, error) {
-	fmt.Println(infoStyle.Render("\nSelect media type:"))
-
-	type option struct {
-		label string
-		token string
+func runDownloadsList(cmd *cobra.Command, args []string) error {
+	index, err := download.LoadIndex()
+	if err != nil {
+		return fmt.Errorf("failed to load downloads index: %w", err)
 	}
-	var options []option
-	if queueCount > 0 {
-		options = append(options, option{fmt.Sprintf("View Queue (%s)", ui.PluralizeItems(queueCount)), "queue"})
+	if len(index.Entries) == 0 {
+		fmt.Println(infoStyle.Render("Nothing has been downloaded through goplexcli."))
+		return nil
 	}
-	if continueCount > 0 {
-		options = append(options, option{fmt.Sprintf("Continue Watching (%s)", ui.PluralizeItems(continueCount)), "continue watching"})
+
+	fmt.Println(titleStyle.Render(fmt.Sprintf("Downloads (%d)", len(index.Entries))))
+	missing := 0
+	for i := len(index.Entries) - 1; i >= 0; i-- {
+		e := index.Entries[i]
+		line := fmt.Sprintf("  %s  %s", time.Unix(e.DownloadedAt, 0).Format("2006-01-02 15:04"), e.Title)
+		if size := plex.FormatSize(e.Size); size != "" {
+			line += "  " + size
+		}
+		fmt.Println(line)
+		if e.Present() {
+			fmt.Println(infoStyle.Render("      " + e.Path))
+		} else {
+			missing++
+			fmt.Println(warningStyle.Render("      " + e.Path + " (missing)"))
+		}
 	}
-	options = append(options,
-		option{"Recently Added Movies", "recently added movies"},
-		option{"Recently Added TV Shows", "recently added tv shows"},
-		option{"Movies", "movies"},
-		option{"TV Shows", "tv shows"},
-		option{"All", "all"},
-	)
+	if missing > 0 {
+		fmt.Println(warningStyle.Render(fmt.Sprintf("\n%d download(s) no longer on disk; run 'goplexcli downloads clean' to forget them", missing)))
+	}
+	return nil
+}
 
-	for i, opt := range options {
-		fmt.Printf("  %d. %s\n", i+1, opt.label)
+func runDownloadsClean(cmd *cobra.Command, args []string) error {
+	if downloadsCleanFiles {
+		index, err := download.LoadIndex()
+		if err != nil {
+			return fmt.Errorf("failed to load downloads index: %w", err)
+		}
+		present := 0
+		for _, e := range index.Entries {
+			if e.Present() {
+				present++
+			}
+		}
+		if present > 0 {
+			fmt.Printf("Delete %d downloaded file(s) from disk? [y/N]: ", present)
+			var confirm string
+			_, _ = fmt.Scanln(&confirm)
+			if confirm != "y" && confirm != "Y" {
+				fmt.Println(warningStyle.Render("Clean cancelled."))
+				return nil
+			}
+		}
 	}
-	fmt.Printf("\nChoice (1-%d): ", len(options))
 
-	var choice int
-	if _, err := fmt.Scanln(&choice); err != nil {
-		return "", fmt.Errorf("failed to read selection: %w", err)
+	var removed, deletedFiles int
+	err := download.UpdateIndex(func(index *download.Index) error {
+		removed = len(index.Prune())
+		if !downloadsCleanFiles {
+			return nil
+		}
+		var kept []download.IndexEntry
+		for _, e := range index.Entries {
+			if err := os.Remove(e.Path); err != nil {
+				fmt.Println(warningStyle.Render(fmt.Sprintf("⚠ %s: %v", e.Title, err)))
+				kept = append(kept, e)
+				continue
+			}
+			deletedFiles++
+		}
+		index.Entries = kept
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to clean downloads index: %w", err)
 	}
-	if choice < 1 || choice > len(options) {
-		return "", fmt.Errorf("invalid selection")
+
+	if downloadsCleanFiles {
+		fmt.Println(successStyle.Render(fmt.Sprintf("✓ Deleted %d file(s); forgot %d missing download(s)", deletedFiles, removed)))
+	} else {
+		fmt.Println(successStyle.Render(fmt.Sprintf("✓ Forgot %d missing download(s)", removed)))
 	}
-	return options[choice-1].token, nil
+	return nil
 }
 
-// promptActionManualWithQueue - fallback for no-fzf action selection with queue.
-// "Transfer to Outplayer" is only listed when outplayerCount > 0, so the option
-// numbering is built dynamically.
-func promptActionManualWithQueue(selectionCount, queueCount, outplayerCount int) (string, error) {
-	queueLabel := fmt.Sprintf("Add (%d) to Queue", selectionCount)
-	if queueCount > 0 {
-		queueLabel = fmt.Sprintf("Add (%d) to Queue (%d)", selectionCount, queueCount)
+func runDeletedList(cmd *cobra.Command, args []string) error {
+	dlog, err := deleted.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load deletion log: %w", err)
+	}
+	if len(dlog.Entries) == 0 {
+		fmt.Println(infoStyle.Render("Nothing has been deleted through goplexcli."))
+		return nil
 	}
 
-	type option struct {
-		label string
-		token string
+	fmt.Println(titleStyle.Render(fmt.Sprintf("Deleted Media (%d)", len(dlog.Entries))))
+	for i := len(dlog.Entries) - 1; i >= 0; i-- {
+		e := dlog.Entries[i]
+		line := fmt.Sprintf("  %s  %s", time.Unix(e.DeletedAt, 0).Format("2006-01-02 15:04"), e.DisplayTitle())
+		if len(e.GUIDs) > 0 {
+			line += "  " + infoStyle.Render(strings.Join(e.GUIDs, " "))
+		}
+		fmt.Println(line)
 	}
-	options := []option{
-		{"Watch", "watch"},
-		{"Download", "download"},
-		{queueLabel, "queue"},
-		{"Transfer to WebDAV", "transfer"},
+	return nil
+}
+
+func runDeletedExport(cmd *cobra.Command, args []string) error {
+	if deletedFormat != "csv" && deletedFormat != "json" {
+		return fmt.Errorf("unknown format %q (want csv or json)", deletedFormat)
 	}
-	if outplayerCount > 0 {
-		options = append(options, option{"Transfer to Outplayer", "transfer-outplayer"})
+	dlog, err := deleted.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load deletion log: %w", err)
 	}
-	options = append(options, option{"More...", "more"}, option{"Cancel", "cancel"})
 
-	fmt.Println(infoStyle.Render("\nSelect action:"))
-	for i, opt := range options {
-		fmt.Printf("  %d. %s\n", i+1, opt.label)
+	write := func(w io.Writer) error {
+		if deletedFormat == "json" {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(dlog.Entries)
+		}
+		return deleted.WriteCSV(w, dlog.Entries)
 	}
-	fmt.Printf("\nChoice (1-%d): ", len(options))
 
-	var choice int
-	if _, err := fmt.Scanln(&choice); err != nil {
-		return "", fmt.Errorf("failed to read selection: %w", err)
+	if deletedOutput == "" {
+		return write(os.Stdout)
 	}
-	if choice < 1 || choice > len(options) {
-		return "cancel", nil
+	f, err := os.Create(deletedOutput)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", deletedOutput, err)
 	}
-	return options[choice-1].token, nil
+	if err := write(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", deletedOutput, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", deletedOutput, err)
+	}
+	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Wrote %d deletion(s) to %s", len(dlog.Entries), deletedOutput)))
+	return nil
 }
 
-// promptMoreActionManual - fallback for no-fzf selection of the "More..." submenu.
-func promptMoreActionManual() (string, error) {
-	fmt.Println(infoStyle.Render("\nMore actions:"))
-	fmt.Println("  1. SenPlayer Play")
-	fmt.Println("  2. SenPlayer Download")
-	fmt.Println("  3. Stream")
-	fmt.Println("  4. Back")
-	fmt.Print("\nChoice (1-4): ")
+func runChapters(cmd *cobra.Command, args []string) error {
+	title := strings.Join(args, " ")
 
-	var choice int
-	if _, err := fmt.Scanln(&choice); err != nil {
-		return "", fmt.Errorf("failed to read selection: %w", err)
+	app := appFrom(cmd)
+	cfg, mediaCache := app.Config, app.Cache
+
+	items := export.ResolveTitle(mediaCache.Media, title)
+	if len(items) == 0 {
+		return fmt.Errorf("no movie named %q in the cache", title)
+	}
+	if len(items) > 1 {
+		return fmt.Errorf("%q matches %d items; chapters are listed for a single movie", title, len(items))
 	}
+	item := items[0]
 
-	switch choice {
-	case 1:
-		return "senplayer play", nil
-	case 2:
-		return "senplayer download", nil
-	case 3:
-		return "stream", nil
-	default:
-		return "cancel", nil
+	serverURL := item.ServerURL
+	if serverURL == "" {
+		serverURL = cfg.PlexURL
+	}
+	client, err := plex.New(serverURL, cfg.TokenForURL(serverURL))
+	if err != nil {
+		return fmt.Errorf("failed to create plex client: %w", err)
+	}
+	chapters, err := client.GetChapters(cmd.Context(), item.RatingKey())
+	if err != nil {
+		return fmt.Errorf("failed to get chapters: %w", err)
 	}
-}
 
-func runCacheUpdate(cmd *cobra.Command, args []string) error {
-	return updateCache(false)
+	fmt.Println(titleStyle.Render(item.FormatMediaTitle()))
+	if len(chapters) == 0 {
+		fmt.Println(infoStyle.Render("No chapters."))
+		return nil
+	}
+	for _, ch := range chapters {
+		fmt.Printf("  %3d  %8s  %s\n", ch.Index, progress.FormatDuration(ch.StartMs), ch.Title)
+	}
+	return nil
 }
 
-func runCacheReindex(cmd *cobra.Command, args []string) error {
-	return updateCache(true)
+// liveTVClient returns a client for the primary server, which hosts the DVR.
+func liveTVClient(cfg *config.Config) (*plex.Client, error) {
+	client, err := plex.New(cfg.PlexURL, cfg.TokenForURL(cfg.PlexURL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create plex client: %w", err)
+	}
+	return client, nil
 }
 
-func updateCache(fullReindex bool) error {
-	// Load config
-	cfg, err := config.Load()
+// liveTVChannels returns every channel of the server's DVRs and what is
+// airing on each now, keyed by channel number. The guide is best effort.
+func liveTVChannels(ctx context.Context, client *plex.Client) ([]plex.Channel, map[string]plex.Airing, error) {
+	dvrs, err := client.GetDVRs(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return nil, nil, fmt.Errorf("failed to get DVRs: %w", err)
 	}
-
-	if err := cfg.Validate(); err != nil {
-		return fmt.Errorf("invalid config: %w. Please run 'goplexcli login' first", err)
+	if len(dvrs) == 0 {
+		return nil, nil, fmt.Errorf("no Plex DVR is set up on this server")
 	}
-
-	// An incremental update fetches only items added since the last cache and
-	// merges them in. A full reindex (or an empty/missing cache) fetches
-	// everything and replaces the cache.
-	var existing *cache.Cache
-	incremental := false
-	if !fullReindex {
-		existing, err = cache.Load()
+	var channels []plex.Channel
+	onNow := map[string]plex.Airing{}
+	for _, dvr := range dvrs {
+		chs, err := client.GetChannels(ctx, dvr)
 		if err != nil {
-			return fmt.Errorf("failed to load existing cache: %w", err)
+			return nil, nil, fmt.Errorf("failed to get channels: %w", err)
+		}
+		channels = append(channels, chs...)
+		airings, err := client.GetAirings(ctx, dvr, time.Now())
+		if err != nil {
+			logging.Warn("failed to get program guide", "dvr", dvr.Key, "error", err)
+			continue
+		}
+		for _, a := range airings {
+			if _, ok := onNow[a.ChannelNumber]; !ok {
+				onNow[a.ChannelNumber] = a
+			}
 		}
-		incremental = len(existing.Media) > 0
 	}
+	return channels, onNow, nil
+}
 
-	action := "Reindexing"
-	if !fullReindex {
-		action = "Updating"
+// channelLabel is a channel with what's on it, e.g.
+// "4.1   WRC     Drama: Pilot (until 18:30)".
+func channelLabel(ch plex.Channel, onNow map[string]plex.Airing) string {
+	label := fmt.Sprintf("%-6s %-8s", ch.Number, ch.CallSign)
+	a, ok := onNow[ch.Number]
+	if !ok {
+		return label + " " + ch.Title
 	}
+	title := a.Title
+	if a.ShowTitle != "" {
+		title = a.ShowTitle + ": " + a.Title
+	}
+	return fmt.Sprintf("%s %s (until %s)", label, title, a.Ends.Local().Format("15:04"))
+}
 
-	fmt.Println(titleStyle.Render(action + " Media Cache"))
-
-	// Newest addedAt already cached, keyed by server name then item type
-	// ("movie"/"episode"). Used to fetch only newer items during incremental
-	// updates.
-	maxAdded := map[string]map[string]int64{}
-	if incremental {
-		for _, item := range existing.Media {
-			byType := maxAdded[item.ServerName]
-			if byType == nil {
-				byType = map[string]int64{}
-				maxAdded[item.ServerName] = byType
-			}
-			if item.AddedAt > byType[item.Type] {
-				byType[item.Type] = item.AddedAt
-			}
-		}
+func runLiveTV(cmd *cobra.Command, args []string) error {
+	cfg := appFrom(cmd).Config
+	client, err := liveTVClient(cfg)
+	if err != nil {
+		return err
 	}
-	// sinceFor maps a library type ("movie"/"show") to the newest addedAt known
-	// for the matching item type on the given server.
-	sinceFor := func(serverName, libType string) int64 {
-		itemType := "movie"
-		if libType == "show" {
-			itemType = "episode"
-		}
-		if byType, ok := maxAdded[serverName]; ok {
-			return byType[itemType]
-		}
-		return 0
+	channels, onNow, err := liveTVChannels(cmd.Context(), client)
+	if err != nil {
+		return err
 	}
+	fmt.Println(titleStyle.Render("Live TV"))
+	for _, ch := range channels {
+		fmt.Println("  " + channelLabel(ch, onNow))
+	}
+	fmt.Println(infoStyle.Render("\nWatch one with 'goplexcli livetv watch <channel>'."))
+	return nil
+}
 
-	// Check if we have multiple servers
-	enabledServers := cfg.GetEnabledServers()
-
-	var media []plex.MediaItem
-	ctx := context.Background()
-
-	if len(enabledServers) > 1 {
-		// Multi-server mode
-		fmt.Println(infoStyle.Render(fmt.Sprintf("Found %d enabled servers", len(enabledServers))))
-
-		// Build server configs
-		var serverConfigs []struct{ Name, URL, Token string }
-		for _, server := range enabledServers {
-			serverConfigs = append(serverConfigs, struct{ Name, URL, Token string }{
-				Name:  server.Name,
-				URL:   server.URL,
-				Token: cfg.TokenForServer(server),
-			})
-		}
+func runLiveTVWatch(cmd *cobra.Command, args []string) error {
+	cfg := appFrom(cmd).Config
+	ctx := cmd.Context()
+	client, err := liveTVClient(cfg)
+	if err != nil {
+		return err
+	}
+	channels, onNow, err := liveTVChannels(ctx, client)
+	if err != nil {
+		return err
+	}
 
-		serverProgress := func(serverName, libraryName string, itemCount, totalItems, totalLibs, currentLib, serverNum, totalServers int) {
-			progress := fmt.Sprintf("%d items", itemCount)
-			if totalItems > 0 {
-				progress = fmt.Sprintf("%d/%d items", itemCount, totalItems)
+	var channel *plex.Channel
+	if len(args) == 1 {
+		for i, ch := range channels {
+			if ch.Number == args[0] || strings.EqualFold(ch.CallSign, args[0]) || strings.EqualFold(ch.Title, args[0]) {
+				channel = &channels[i]
+				break
 			}
-			fmt.Printf("\r\x1b[K%s [Server %d/%d: %s] [%d/%d] %s: %s",
-				infoStyle.Render("Processing"),
-				serverNum,
-				totalServers,
-				serverName,
-				currentLib,
-				totalLibs,
-				libraryName,
-				progress,
-			)
-		}
-		mappings := toPlexPathMappings(cfg.PathMappings)
-		if incremental {
-			media, err = plex.GetNewMediaFromServers(ctx, serverConfigs, mappings, sinceFor, serverProgress)
-		} else {
-			media, err = plex.GetAllMediaFromServers(ctx, serverConfigs, mappings, serverProgress)
 		}
-		if err != nil {
-			return fmt.Errorf("failed to get media: %w", err)
+		if channel == nil {
+			return fmt.Errorf("no channel %q; run 'goplexcli livetv' to list them", args[0])
 		}
 	} else {
-		// Single-server mode (legacy or single enabled server)
-		var serverURL, serverToken string
-		if len(enabledServers) == 1 {
-			serverURL = enabledServers[0].URL
-			serverToken = cfg.TokenForServer(enabledServers[0])
-		} else {
-			serverURL = cfg.PlexURL
-			serverToken = cfg.TokenForURL(serverURL)
+		labels := make([]string, len(channels))
+		for i, ch := range channels {
+			labels[i] = channelLabel(ch, onNow)
 		}
-
-		fmt.Println(infoStyle.Render("Connecting to Plex server..."))
-
-		// Create Plex client
-		client, err := plex.New(serverURL, serverToken)
+		idx, err := chooseIndex(cfg, labels, "channel")
 		if err != nil {
-			return fmt.Errorf("failed to create plex client: %w", err)
+			if errors.Is(err, apperrors.ErrCancelled) {
+				return nil
+			}
+			return err
 		}
-		client.SetPathMappings(toPlexPathMappings(cfg.PathMappings))
+		channel = &channels[idx]
+	}
 
-		// Test connection
-		if err := client.Test(); err != nil {
-			return fmt.Errorf("failed to connect to plex server: %w", err)
-		}
+	playerName := cfg.PlayerName()
+	if !player.IsPlayerAvailable(playerName, cfg.PlayerPath()) {
+		return fmt.Errorf("%s not found; install it or set the player in the config", playerName)
+	}
 
-		fmt.Println(successStyle.Render("✓ Connected to Plex server"))
-		fmt.Println(infoStyle.Render("Fetching media library..."))
+	fmt.Println(infoStyle.Render(fmt.Sprintf("Tuning %s %s...", channel.Number, channel.CallSign)))
+	streamURL, err := client.TuneChannel(ctx, *channel)
+	if err != nil {
+		return err
+	}
+	title := strings.TrimSpace(channel.Number + " " + channel.CallSign)
+	if _, err := player.PlayMultipleWith(playerName, cfg.PlayerPath(), []string{streamURL}, player.PlaybackOptions{Titles: []string{title}}); err != nil {
+		return fmt.Errorf("playback failed: %w", err)
+	}
+	return nil
+}
 
-		// Get media with progress
-		libraryProgress := func(libraryName string, itemCount, totalItems, totalLibs, currentLib int) {
-			progress := fmt.Sprintf("%d items", itemCount)
-			if totalItems > 0 {
-				progress = fmt.Sprintf("%d/%d items", itemCount, totalItems)
-			}
-			fmt.Printf("\r\x1b[K%s [%d/%d] %s: %s",
-				infoStyle.Render("Processing libraries"),
-				currentLib,
-				totalLibs,
-				libraryName,
-				progress,
-			)
+func runLiveTVRecordings(cmd *cobra.Command, args []string) error {
+	cfg := appFrom(cmd).Config
+	client, err := liveTVClient(cfg)
+	if err != nil {
+		return err
+	}
+	recordings, err := client.GetScheduledRecordings(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("failed to get scheduled recordings: %w", err)
+	}
+
+	fmt.Println(titleStyle.Render("Scheduled Recordings"))
+	if len(recordings) == 0 {
+		fmt.Println(infoStyle.Render("Nothing scheduled."))
+		return nil
+	}
+	for _, r := range recordings {
+		title := r.Title
+		if r.ShowTitle != "" {
+			title = fmt.Sprintf("%s S%02dE%02d - %s", r.ShowTitle, r.Season, r.Episode, r.Title)
 		}
-		if incremental {
-			// The client uses serverURL as its server name when none is set,
-			// matching how items were tagged when first cached.
-			media, err = client.GetMediaSince(ctx, func(libType string) int64 {
-				return sinceFor(serverURL, libType)
-			}, libraryProgress)
+		line := fmt.Sprintf("  %s  %-8s %s", r.Begins.Local().Format("Mon Jan 2 15:04"), r.CallSign, title)
+		if r.Status == "inprogress" {
+			fmt.Println(successStyle.Render(line + "  ● recording"))
 		} else {
-			media, err = client.GetAllMedia(ctx, libraryProgress)
-		}
-		if err != nil {
-			return fmt.Errorf("failed to get media: %w", err)
+			fmt.Println(line)
 		}
 	}
+	return nil
+}
 
-	fmt.Println() // New line after progress
-
-	// For incremental updates, merge the newly fetched items into the existing
-	// cache (deduping by server + key); a full reindex replaces it outright.
-	finalMedia := media
-	if incremental {
-		merged, added := mergeMedia(existing.Media, media)
-		finalMedia = merged
-		if added == 0 {
-			fmt.Println(successStyle.Render("✓ Cache is already up to date — no new items"))
-		} else {
-			fmt.Println(successStyle.Render(fmt.Sprintf("✓ Added %d new item(s)", added)))
-		}
-	} else {
-		fmt.Println(successStyle.Render(fmt.Sprintf("✓ Retrieved %d media items", len(finalMedia))))
+func runSessions(cmd *cobra.Command, args []string) error {
+	cfg := appFrom(cmd).Config
+	ctx := cmd.Context()
+	if sessionsInterval < time.Second {
+		return fmt.Errorf("--interval must be at least 1s")
+	}
+	client, err := plex.New(cfg.PlexURL, cfg.TokenForURL(cfg.PlexURL))
+	if err != nil {
+		return fmt.Errorf("failed to create plex client: %w", err)
 	}
 
-	// Save to cache
-	mediaCache := &cache.Cache{
-		Media: finalMedia,
+	fetch := func() ([]plex.Session, error) {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		return client.GetSessions(ctx)
+	}
+	stop := func(id string) error {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		return client.StopSession(ctx, id, sessionsStopReason)
 	}
+	return ui.RunSessionsMonitor(ui.NewSessionsMonitor(serverLabel(cfg, cfg.PlexURL), fetch, stop, sessionsInterval))
+}
 
-	if err := mediaCache.Save(); err != nil {
-		return fmt.Errorf("failed to save cache: %w", err)
+func runRefresh(cmd *cobra.Command, args []string) error {
+	title := strings.Join(args, " ")
+
+	app := appFrom(cmd)
+	cfg := app.Config
+	items := export.ResolveTitle(app.Cache.Media, title)
+	if len(items) == 0 {
+		return fmt.Errorf("no show or movie named %q in the cache", title)
 	}
 
-	fmt.Println(successStyle.Render("✓ Cache saved successfully"))
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	defer cancel()
 
-	// Count by type and by server
-	movieCount := 0
-	episodeCount := 0
-	serverCounts := make(map[string]int)
+	// A show resolves to its episodes; refresh the show itself once per
+	// server instead of every episode.
+	clients := make(map[string]*plex.Client)
+	done := make(map[string]bool)
+	var failures int
+	for _, item := range items {
+		serverURL := item.ServerURL
+		if serverURL == "" {
+			serverURL = cfg.PlexURL
+		}
+		if item.Type == "episode" && done[serverURL] {
+			continue
+		}
+		client, ok := clients[serverURL]
+		if !ok {
+			c, err := plex.New(serverURL, cfg.TokenForURL(serverURL))
+			if err != nil {
+				return fmt.Errorf("failed to create plex client: %w", err)
+			}
+			client, clients[serverURL] = c, c
+		}
 
-	for _, item := range finalMedia {
-		switch item.Type {
-		case "movie":
-			movieCount++
-		case "episode":
-			episodeCount++
+		key, name := item.RatingKey(), item.FormatMediaTitle()
+		if item.Type == "episode" {
+			done[serverURL] = true
+			showKey, err := client.ShowRatingKey(ctx, key)
+			if err != nil {
+				fmt.Println(warningStyle.Render(fmt.Sprintf("✗ %s: %v", item.ParentTitle, err)))
+				failures++
+				continue
+			}
+			key, name = showKey, item.ParentTitle
 		}
 		if item.ServerName != "" {
-			serverCounts[item.ServerName]++
+			name += " (" + item.ServerName + ")"
 		}
-	}
-
-	fmt.Println(infoStyle.Render(fmt.Sprintf("\nTotal items: %d", len(finalMedia))))
-	fmt.Println(infoStyle.Render(fmt.Sprintf("  Movies: %d", movieCount)))
-	fmt.Println(infoStyle.Render(fmt.Sprintf("  Episodes: %d", episodeCount)))
-
-	if len(serverCounts) > 1 {
-		fmt.Println(infoStyle.Render("\nBy server:"))
-		for serverName, count := range serverCounts {
-			fmt.Println(infoStyle.Render(fmt.Sprintf("  %s: %d items", serverName, count)))
+		if err := client.RefreshItem(ctx, key); err != nil {
+			fmt.Println(warningStyle.Render(fmt.Sprintf("✗ %s: %v", name, err)))
+			failures++
+			continue
 		}
+		fmt.Println(successStyle.Render("✓ Refreshing " + name))
 	}
 
+	if failures > 0 {
+		return fmt.Errorf("%d refresh(es) failed", failures)
+	}
 	return nil
 }
 
-// mergeMedia combines newly fetched items into the existing cached items,
-// deduplicating by server name and key. Items present in both are replaced
-// with the freshly fetched version (picking up metadata changes). It returns
-// the merged slice and the number of items that were newly added.
-func mergeMedia(existing, fetched []plex.MediaItem) ([]plex.MediaItem, int) {
-	keyOf := func(m plex.MediaItem) string { return m.ServerName + "\x00" + m.Key }
-
-	merged := make([]plex.MediaItem, len(existing))
-	copy(merged, existing)
-
-	index := make(map[string]int, len(merged))
-	for i := range merged {
-		index[keyOf(merged[i])] = i
-	}
-
-	added := 0
-	for _, item := range fetched {
-		k := keyOf(item)
-		if i, ok := index[k]; ok {
-			merged[i] = item
-			continue
+// serverLabel returns the configured name of the server at serverURL, or
+// the URL itself.
+func serverLabel(cfg *config.Config, serverURL string) string {
+	for _, s := range cfg.Servers {
+		if strings.TrimRight(s.URL, "/") == strings.TrimRight(serverURL, "/") && s.Name != "" {
+			return s.Name
 		}
-		index[k] = len(merged)
-		merged = append(merged, item)
-		added++
 	}
+	return serverURL
+}
 
-	return merged, added
+// itemInfo is what `info --json` prints.
+type itemInfo struct {
+	Title           string        `json:"title"`
+	Type            string        `json:"type"`
+	Year            int           `json:"year,omitempty"`
+	Show            string        `json:"show,omitempty"`
+	Season          int64         `json:"season,omitempty"`
+	Episode         int64         `json:"episode,omitempty"`
+	Summary         string        `json:"summary,omitempty"`
+	Rating          float64       `json:"rating,omitempty"`
+	ContentRating   string        `json:"content_rating,omitempty"`
+	Genre           string        `json:"genre,omitempty"`
+	Studio          string        `json:"studio,omitempty"`
+	DurationMs      int           `json:"duration_ms,omitempty"`
+	FilePath        string        `json:"file_path,omitempty"`
+	Size            int64         `json:"size,omitempty"` // bytes
+	Server          string        `json:"server,omitempty"`
+	ViewCount       int           `json:"view_count"`
+	ViewOffsetMs    int           `json:"view_offset_ms,omitempty"`
+	AddedAt         *time.Time    `json:"added_at,omitempty"`
+	LastViewedAt    *time.Time    `json:"last_viewed_at,omitempty"`
+	OriginallyAired string        `json:"originally_aired,omitempty"`
+	Details         *plex.Details `json:"details,omitempty"`
 }
 
-func runCacheInfo(cmd *cobra.Command, args []string) error {
-	mediaCache, err := cache.Load()
+func runInfo(cmd *cobra.Command, args []string) error {
+	app := appFrom(cmd)
+	cfg, mediaCache := app.Config, app.Cache
+
+	items, err := resolveTitleArg(cfg, mediaCache.Media, strings.Join(args, " "))
 	if err != nil {
-		return fmt.Errorf("failed to load cache: %w", err)
+		if errors.Is(err, apperrors.ErrCancelled) {
+			return nil
+		}
+		return err
 	}
-
-	fmt.Println(titleStyle.Render("Cache Information"))
-
-	if len(mediaCache.Media) == 0 {
-		fmt.Println(warningStyle.Render("Cache is empty"))
-		return nil
+	item := items[0]
+	if len(items) > 1 {
+		labels := make([]string, len(items))
+		for i, ep := range items {
+			labels[i] = ep.FormatMediaTitle()
+		}
+		idx, err := chooseIndex(cfg, labels, "episode")
+		if err != nil {
+			if errors.Is(err, apperrors.ErrCancelled) {
+				return nil
+			}
+			return err
+		}
+		item = items[idx]
 	}
 
-	fmt.Println(infoStyle.Render(fmt.Sprintf("Total items: %d", len(mediaCache.Media))))
-	fmt.Println(infoStyle.Render(fmt.Sprintf("Last updated: %s", mediaCache.LastUpdated.Format(time.RFC822))))
-
-	// Count by type
-	movieCount := 0
-	episodeCount := 0
-	for _, item := range mediaCache.Media {
-		switch item.Type {
-		case "movie":
-			movieCount++
-		case "episode":
-			episodeCount++
+	serverURL := item.ServerURL
+	if serverURL == "" {
+		serverURL = cfg.PlexURL
+	}
+	var details *plex.Details
+	client, err := plex.New(serverURL, cfg.TokenForURL(serverURL))
+	if err == nil {
+		ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+		details, err = client.GetDetails(ctx, item.RatingKey())
+		cancel()
+	}
+	if err != nil {
+		logging.Warn("failed to get item details from server", "error", err)
+	}
+
+	if infoJSON {
+		info := itemInfo{
+			Title:           item.Title,
+			Type:            item.Type,
+			Year:            item.Year,
+			Summary:         item.Summary,
+			Rating:          item.Rating,
+			ContentRating:   item.ContentRating,
+			Genre:           item.Genre,
+			Studio:          item.Studio,
+			DurationMs:      item.Duration,
+			FilePath:        item.FilePath,
+			Size:            item.Size,
+			Server:          item.ServerName,
+			ViewCount:       item.ViewCount,
+			ViewOffsetMs:    item.ViewOffset,
+			OriginallyAired: item.OriginallyAired,
+			Details:         details,
+		}
+		if item.Type == "episode" {
+			info.Show, info.Season, info.Episode = item.ParentTitle, item.ParentIndex, item.Index
+		}
+		if item.AddedAt > 0 {
+			t := time.Unix(item.AddedAt, 0).UTC()
+			info.AddedAt = &t
 		}
+		if item.LastViewedAt > 0 {
+			t := time.Unix(item.LastViewedAt, 0).UTC()
+			info.LastViewedAt = &t
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(info)
 	}
 
-	fmt.Println(infoStyle.Render(fmt.Sprintf("Movies: %d", movieCount)))
-	fmt.Println(infoStyle.Render(fmt.Sprintf("Episodes: %d", episodeCount)))
-
+	printItemInfo(item, details)
+	if details == nil {
+		fmt.Println(warningStyle.Render("\nCouldn't reach the server; format and tracks aren't shown."))
+	}
 	return nil
 }
 
-func runConfig(cmd *cobra.Command, args []string) error {
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+// printItemInfo prints an item's cached metadata and, when the server
+// answered, its format, tracks and full credits.
+func printItemInfo(item *plex.MediaItem, d *plex.Details) {
+	line := func(label, value string) { fmt.Printf("%-11s %s\n", label, value) }
+	row := func(label, value string) {
+		if value != "" {
+			line(label+":", value)
+		}
+	}
+	fmt.Println(titleStyle.Render(item.FormatMediaTitle()))
+
+	if item.Type == "episode" {
+		row("Show", item.ParentTitle)
+		row("Episode", fmt.Sprintf("Season %d, Episode %d", item.ParentIndex, item.Index))
+		row("Aired", item.OriginallyAired)
+	} else if item.Year > 0 {
+		row("Year", fmt.Sprintf("%d", item.Year))
+	}
+	if item.Rating > 0 {
+		row("Rating", fmt.Sprintf("%.1f/10", item.Rating))
+	}
+	row("Rated", item.ContentRating)
+	if item.Duration > 0 {
+		row("Duration", progress.FormatDuration(item.Duration))
+	}
+	row("Genre", item.Genre)
+	row("Studio", item.Studio)
+
+	directors, writers, cast := item.Director, item.Writer, item.Cast
+	if d != nil {
+		directors, writers, cast = strings.Join(d.Directors, ", "), strings.Join(d.Writers, ", "), strings.Join(d.Cast, ", ")
+	}
+	row("Director", directors)
+	row("Writer", writers)
+	row("Cast", cast)
+
+	if d != nil {
+		video := strings.Join(strings.Fields(plex.FormatResolution(d.VideoResolution)+" "+strings.ToUpper(d.VideoCodec)+" "+d.HDR), " ")
+		if d.Width > 0 && d.Height > 0 {
+			video += fmt.Sprintf(" (%dx%d)", d.Width, d.Height)
+		}
+		row("Video", strings.TrimSpace(video))
+		row("Bitrate", plex.FormatBitrate(d.Bitrate))
+		row("Container", d.Container)
+		for _, kind := range []struct{ typ, label string }{{"audio", "Audio"}, {"subtitle", "Subtitles"}} {
+			for i, s := range d.StreamsOf(kind.typ) {
+				label := ""
+				if i == 0 {
+					label = kind.label + ":"
+				}
+				title := s.Title
+				if s.Default {
+					title += " [default]"
+				}
+				if s.Forced {
+					title += " [forced]"
+				}
+				line(label, title)
+			}
+		}
 	}
 
-	fmt.Println(titleStyle.Render("Configuration"))
-
-	if cfg.PlexURL == "" {
-		fmt.Println(warningStyle.Render("Not logged in. Run 'goplexcli login' first."))
-		return nil
-	}
+	row("Size", plex.FormatSize(item.Size))
+	row("File", item.FilePath)
+	row("Server", item.ServerName)
 
-	fmt.Println(infoStyle.Render("Plex URL: " + cfg.PlexURL))
-	if cfg.PlexUsername != "" {
-		fmt.Println(infoStyle.Render("Username: " + cfg.PlexUsername))
+	switch {
+	case item.ViewCount > 0:
+		watched := "Watched"
+		if item.ViewCount > 1 {
+			watched = fmt.Sprintf("Watched %d times", item.ViewCount)
+		}
+		if item.LastViewedAt > 0 {
+			watched += ", last on " + time.Unix(item.LastViewedAt, 0).Format("2006-01-02")
+		}
+		row("Status", watched)
+	case item.ViewOffset > 0:
+		row("Status", fmt.Sprintf("In progress, at %s", progress.FormatDuration(item.ViewOffset)))
+	default:
+		row("Status", "Unwatched")
 	}
-	// Safely truncate token display to avoid panic on short tokens
-	tokenDisplay := cfg.PlexToken
-	if len(tokenDisplay) > 10 {
-		tokenDisplay = tokenDisplay[:10] + "..."
+	if item.AddedAt > 0 {
+		row("Added", time.Unix(item.AddedAt, 0).Format("2006-01-02"))
 	}
-	fmt.Println(infoStyle.Render("Token: " + tokenDisplay))
 
-	downloadDir := "(current directory)"
-	if cfg.DownloadDir != "" {
-		downloadDir = cfg.DownloadDir
+	if item.Summary != "" {
+		fmt.Println()
+		fmt.Println(item.Summary)
 	}
-	fmt.Println(infoStyle.Render("Download dir: " + downloadDir))
-
-	configPath, _ := config.GetConfigPath()
-	fmt.Println(infoStyle.Render("\nConfig file: " + configPath))
-
-	cachePath, _ := cache.GetCachePath()
-	fmt.Println(infoStyle.Render("Cache file: " + cachePath))
-
-	return nil
 }
 
-func runUpdate(cmd *cobra.Command, args []string) error {
-	fmt.Println(titleStyle.Render("Update"))
-	ctx := context.Background()
-	return update.Run(ctx, update.DefaultRepo, version, updateCheckOnly, os.Stdout)
-}
+// similarLookups bounds how many of a show's episodes runSimilar tries when
+// TMDB can't place the first ones.
+const similarLookups = 5
 
-func runStream(cmd *cobra.Command, args []string) error {
-	// Load config
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+func runSimilar(cmd *cobra.Command, args []string) error {
+	app := appFrom(cmd)
+	cfg, mediaCache := app.Config, app.Cache
+	if cfg.TMDBAPIKey == "" {
+		return fmt.Errorf("similar titles come from TMDB: set tmdb_api_key in the config first")
 	}
 
-	fmt.Println(titleStyle.Render("Stream Discovery"))
-	fmt.Println(infoStyle.Render("Searching for goplexcli servers on local network...\n"))
-
-	// Discover servers with 3 second timeout
-	ctx := context.Background()
-	servers, err := stream.Discover(ctx, 3*time.Second)
+	items, err := resolveTitleArg(cfg, mediaCache.Media, strings.Join(args, " "))
 	if err != nil {
-		return fmt.Errorf("discovery failed: %w", err)
+		if errors.Is(err, apperrors.ErrCancelled) {
+			return nil
+		}
+		return err
+	}
+	name := items[0].FormatMediaTitle()
+	if items[0].Type == "episode" {
+		name = items[0].ParentTitle
 	}
 
-	if len(servers) == 0 {
-		fmt.Println(warningStyle.Render("No stream servers found on the network"))
-		fmt.Println(infoStyle.Render("\nTo publish a stream:"))
-		fmt.Println(infoStyle.Render("  1. Run 'goplexcli browse' on another device"))
-		fmt.Println(infoStyle.Render("  2. Select a media item"))
-		fmt.Println(infoStyle.Render("  3. Choose 'Stream' option"))
+	store, err := tmdb.LoadStore()
+	if err != nil {
+		return fmt.Errorf("failed to load TMDB store: %w", err)
+	}
+	// Any episode of a show carries the show's recommendations.
+	var similar []tmdb.Title
+	client := tmdb.New(cfg.TMDBAPIKey)
+	tried := 0
+	for _, item := range items {
+		key := tmdb.Key(item)
+		if key == "" {
+			continue
+		}
+		entry, ok := store.Get(item)
+		if !ok {
+			if tried == similarLookups {
+				break
+			}
+			tried++
+			entry, err = client.Lookup(cmd.Context(), item)
+			if errors.Is(err, tmdb.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to look up %s on TMDB: %w", name, err)
+			}
+			entry.FetchedAt = time.Now().Unix()
+			if err := tmdb.RecordEntries(map[string]tmdb.Entry{key: entry}); err != nil {
+				logging.Warn("failed to save TMDB store", "error", err)
+			}
+		}
+		if len(entry.Similar) > 0 {
+			similar = entry.Similar
+			break
+		}
+	}
+	if len(similar) == 0 {
+		fmt.Println(warningStyle.Render(fmt.Sprintf("TMDB has no recommendations for %s.", name)))
+		if len(items[0].ExternalIDs()) == 0 {
+			fmt.Println(infoStyle.Render("Run 'goplexcli cache reindex' to record the IDs TMDB needs."))
+		}
 		return nil
 	}
 
-	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Found %d server(s)\n", len(servers))))
+	owned := map[string]bool{}
+	for _, item := range mediaCache.Media {
+		switch item.Type {
+		case "movie":
+			owned[strings.ToLower(tmdb.Title{Name: item.Title, Year: item.Year}.String())] = true
+		case "episode":
+			owned[strings.ToLower(item.ParentTitle)] = true
+		}
+	}
+	// Radarr can be asked for movies missing from the library; shows'
+	// recommendations carry TMDB IDs Sonarr can't add by.
+	radarr, _ := arrClients(cfg)
+	if items[0].Type != "movie" {
+		radarr = nil
+	}
+	var snap *arr.Snapshot
+	if radarr != nil {
+		refreshArrSnapshot(cfg)
+		snap = loadArrSnapshot(cfg)
+	}
 
-	// Let user select a server if multiple found
-	var selectedServer *stream.DiscoveredServer
-	if len(servers) == 1 {
-		selectedServer = servers[0]
-		fmt.Println(infoStyle.Render(fmt.Sprintf("Connecting to: %s", selectedServer.Name)))
-	} else {
-		// Format servers for selection
-		var serverNames []string
-		for _, srv := range servers {
-			addr := "unknown"
-			if len(srv.Addresses) > 0 {
-				addr = srv.Addresses[0]
+	var requestable []tmdb.Title
+	fmt.Println(titleStyle.Render("Similar to " + name))
+	for _, t := range similar {
+		if owned[strings.ToLower(t.String())] || owned[strings.ToLower(t.Name)] {
+			fmt.Println(successStyle.Render("✓ " + t.String() + "  (in your library)"))
+			continue
+		}
+		if snap != nil && t.ID > 0 {
+			if m := snap.Movie(t.ID); m != nil {
+				fmt.Println(infoStyle.Render("  " + t.String() + "  (in Radarr: " + m.Status() + ")"))
+				continue
 			}
-			serverNames = append(serverNames, fmt.Sprintf("%s (%s)", srv.Name, addr))
 		}
+		fmt.Println("  " + t.String())
+		if t.ID > 0 {
+			requestable = append(requestable, t)
+		}
+	}
+	if radarr == nil || len(requestable) == 0 || !ui.Interactive() {
+		return nil
+	}
+	return requestViaRadarr(cmd.Context(), cfg, radarr, requestable)
+}
 
-		if ui.IsAvailable(cfg.FzfPath) {
-			_, idx, err := ui.SelectWithFzf(serverNames, "Select server:", cfg.FzfPath)
-			if err != nil {
-				if errors.Is(err, apperrors.ErrCancelled) {
-					return nil
-				}
-				return fmt.Errorf("server selection failed: %w", err)
-			}
-			selectedServer = servers[idx]
-		} else {
-			// Fallback to manual selection
-			fmt.Println(infoStyle.Render("Available servers:"))
-			for i, name := range serverNames {
-				fmt.Printf("  %d. %s\n", i+1, name)
-			}
-			fmt.Print("\nSelect server number: ")
-			var choice int
-			if _, err := fmt.Scanln(&choice); err != nil {
-				return fmt.Errorf("failed to read selection: %w", err)
-			}
-			if choice < 1 || choice > len(servers) {
-				return fmt.Errorf("invalid selection")
+// requestViaRadarr offers the similar titles missing from both Plex and
+// Radarr, and asks Radarr to monitor and search for the one picked.
+func requestViaRadarr(ctx context.Context, cfg *config.Config, radarr *arr.Client, titles []tmdb.Title) error {
+	labels := make([]string, len(titles))
+	for i, t := range titles {
+		labels[i] = t.String()
+	}
+
+	var idx int
+	if ui.IsAvailable(cfg.FzfPath) {
+		_, i, err := ui.SelectWithFzf(labels, "Request via Radarr (Esc to skip):", cfg.FzfPath)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrCancelled) {
+				return nil
 			}
-			selectedServer = servers[choice-1]
+			return fmt.Errorf("selection failed: %w", err)
+		}
+		idx = i
+	} else {
+		fmt.Println(infoStyle.Render("\nRequest via Radarr:"))
+		for i, label := range labels {
+			fmt.Printf("  %d. %s\n", i+1, label)
+		}
+		fmt.Printf("\nEnter number (1-%d, Enter to skip): ", len(labels))
+		var choice int
+		if _, err := fmt.Scanln(&choice); err != nil || choice < 1 || choice > len(labels) {
+			return nil
 		}
+		idx = choice - 1
+	}
+
+	t := titles[idx]
+	opts := arr.AddOptions{QualityProfile: cfg.RadarrQualityProfile, RootFolder: cfg.RadarrRootFolder}
+	if err := radarr.AddMovie(ctx, t.ID, t.Name, t.Year, opts); err != nil {
+		return fmt.Errorf("failed to request %s: %w", t, err)
+	}
+	fmt.Println(successStyle.Render("✓ Requested " + t.String() + " via Radarr"))
+	// The next preview or 'similar' should see it.
+	if path, err := arr.SnapshotPath(); err == nil {
+		_ = os.Remove(path)
+	}
+	return nil
+}
+
+// offerOverseerrRequest asks whether to look for query on Overseerr (or
+// Jellyseerr) and, if so, lets the user request one of the titles found.
+func offerOverseerrRequest(ctx context.Context, cfg *config.Config, query string) error {
+	fmt.Printf("\nSearch Overseerr for \"%s\"? [y/N]: ", query)
+	var confirm string
+	_, _ = fmt.Scanln(&confirm)
+	if confirm != "y" && confirm != "Y" {
+		return nil
 	}
 
-	// Fetch streams from selected server
-	fmt.Println(infoStyle.Render("\nFetching available streams..."))
-	streams, err := stream.FetchStreams(selectedServer)
+	client := overseerr.New(cfg.OverseerrURL, cfg.OverseerrAPIKey)
+	results, err := client.Search(ctx, query)
 	if err != nil {
-		return fmt.Errorf("failed to fetch streams: %w", err)
+		return fmt.Errorf("failed to search Overseerr: %w", err)
 	}
-
-	if len(streams) == 0 {
-		fmt.Println(warningStyle.Render("No streams available on this server"))
+	if len(results) == 0 {
+		fmt.Println(warningStyle.Render(fmt.Sprintf("Overseerr found nothing for \"%s\".", query)))
 		return nil
 	}
 
-	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Found %d stream(s)\n", len(streams))))
-
-	// Let user select a stream
-	var selectedStream *stream.StreamItem
-	if len(streams) == 1 {
-		selectedStream = streams[0]
-	} else {
-		// Format streams for selection
-		var streamTitles []string
-		for _, s := range streams {
-			streamTitles = append(streamTitles, s.Title)
+	labels := make([]string, len(results))
+	for i, r := range results {
+		kind := "Movie"
+		if r.MediaType == "tv" {
+			kind = "TV Show"
 		}
-
-		if ui.IsAvailable(cfg.FzfPath) {
-			_, idx, err := ui.SelectWithFzf(streamTitles, "Select stream:", cfg.FzfPath)
-			if err != nil {
-				if errors.Is(err, apperrors.ErrCancelled) {
-					return nil
-				}
-				return fmt.Errorf("stream selection failed: %w", err)
-			}
-			selectedStream = streams[idx]
-		} else {
-			// Fallback to manual selection
-			fmt.Println(infoStyle.Render("Available streams:"))
-			for i, title := range streamTitles {
-				fmt.Printf("  %d. %s\n", i+1, title)
-			}
-			fmt.Print("\nSelect stream number: ")
-			var choice int
-			if _, err := fmt.Scanln(&choice); err != nil {
-				return fmt.Errorf("failed to read selection: %w", err)
-			}
-			if choice < 1 || choice > len(streams) {
-				return fmt.Errorf("invalid selection")
-			}
-			selectedStream = streams[choice-1]
+		labels[i] = r.String() + "  ·  " + kind
+		if status := r.Status.String(); status != "" {
+			labels[i] += "  ·  " + status
 		}
 	}
 
-	// Show stream info
-	fmt.Println(infoStyle.Render("\nStream: " + selectedStream.Title))
-	if selectedStream.Year > 0 {
-		fmt.Println(infoStyle.Render(fmt.Sprintf("Year: %d", selectedStream.Year)))
-	}
-	if selectedStream.Duration > 0 {
-		fmt.Println(infoStyle.Render(fmt.Sprintf("Duration: %d min", selectedStream.Duration/60000)))
+	var idx int
+	if ui.IsAvailable(cfg.FzfPath) {
+		_, i, err := ui.SelectWithFzf(labels, "Request:", cfg.FzfPath)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrCancelled) {
+				return nil
+			}
+			return fmt.Errorf("selection failed: %w", err)
+		}
+		idx = i
+	} else {
+		fmt.Println(infoStyle.Render("\nOverseerr results:"))
+		for i, label := range labels {
+			fmt.Printf("  %d. %s\n", i+1, label)
+		}
+		fmt.Printf("\nEnter number to request (1-%d, Enter to skip): ", len(labels))
+		var choice int
+		if _, err := fmt.Scanln(&choice); err != nil || choice < 1 || choice > len(labels) {
+			return nil
+		}
+		idx = choice - 1
 	}
 
-	// Check if MPV is available
-	if !player.IsAvailable(cfg.MPVPath) {
-		fmt.Println(warningStyle.Render("\nMPV not found. You can still play the stream manually:"))
-		fmt.Println(infoStyle.Render(selectedStream.StreamURL))
+	r := results[idx]
+	if !r.Status.Requestable() {
+		fmt.Println(infoStyle.Render(fmt.Sprintf("%s is already %s.", r, r.Status)))
 		return nil
 	}
-
-	fmt.Println(successStyle.Render("\n✓ Starting playback..."))
-
-	// Play with MPV
-	if err := player.Play(selectedStream.StreamURL, cfg.MPVPath); err != nil {
-		return fmt.Errorf("playback failed: %w", err)
+	if err := client.Request(ctx, r); err != nil {
+		return fmt.Errorf("failed to request %s: %w", r, err)
 	}
-
-	fmt.Println(successStyle.Render("✓ Playback finished"))
+	fmt.Println(successStyle.Render("✓ Requested " + r.String() + " via Overseerr"))
 	return nil
 }
 
-func runCacheSearch(cmd *cobra.Command, args []string) error {
-	searchTitle := strings.Join(args, " ")
+// arrTimeout bounds refreshing the Radarr/Sonarr snapshot, so an
+// unreachable server delays the picker only briefly.
+const arrTimeout = 5 * time.Second
 
-	// Load config
-	cfg, err := config.Load()
+// arrClients returns clients for the Radarr and Sonarr set up in the
+// config; either is nil when not configured.
+func arrClients(cfg *config.Config) (radarr, sonarr *arr.Client) {
+	if cfg.RadarrURL != "" && cfg.RadarrAPIKey != "" {
+		radarr = arr.NewRadarr(cfg.RadarrURL, cfg.RadarrAPIKey)
+	}
+	if cfg.SonarrURL != "" && cfg.SonarrAPIKey != "" {
+		sonarr = arr.NewSonarr(cfg.SonarrURL, cfg.SonarrAPIKey)
+	}
+	return radarr, sonarr
+}
+
+// refreshArrSnapshot fetches Radarr's and Sonarr's libraries for the
+// preview pane when the saved snapshot is missing, stale, or was taken
+// with different services configured. Failures are logged, not returned:
+// the status is a nicety.
+func refreshArrSnapshot(cfg *config.Config) {
+	radarr, sonarr := arrClients(cfg)
+	if radarr == nil && sonarr == nil {
+		return
+	}
+	if snap, err := arr.LoadSnapshot(); err == nil && snap != nil && !snap.Stale() &&
+		snap.Radarr == (radarr != nil) && snap.Sonarr == (sonarr != nil) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), arrTimeout)
+	defer cancel()
+	snap, err := arr.Fetch(ctx, radarr, sonarr)
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		logging.Warn("failed to fetch Radarr/Sonarr status", "error", err)
+		return
+	}
+	if err := snap.Save(); err != nil {
+		logging.Warn("failed to save Radarr/Sonarr snapshot", "error", err)
 	}
+}
 
-	if err := cfg.Validate(); err != nil {
-		return fmt.Errorf("invalid config: %w. Please run 'goplexcli login' first", err)
+// loadArrSnapshot returns the saved Radarr/Sonarr snapshot, or nil when
+// neither service is configured or there is none.
+func loadArrSnapshot(cfg *config.Config) *arr.Snapshot {
+	radarr, sonarr := arrClients(cfg)
+	if radarr == nil && sonarr == nil {
+		return nil
 	}
+	snap, err := arr.LoadSnapshot()
+	if err != nil {
+		logging.Debug("failed to load Radarr/Sonarr snapshot", "error", err)
+		return nil
+	}
+	return snap
+}
 
-	fmt.Println(titleStyle.Render("Searching for: " + searchTitle))
+func runServerList(cmd *cobra.Command, args []string) error {
+	cfg := appFrom(cmd).Config
 
-	// Search in cache first
-	fmt.Println(infoStyle.Render("\n=== Checking Cache ==="))
-	mediaCache, err := cache.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load cache: %w", err)
+	fmt.Println(titleStyle.Render("Configured Plex Servers"))
+
+	if len(cfg.Servers) == 0 {
+		fmt.Println(warningStyle.Render("No servers configured. Run 'goplexcli login' first."))
+		return nil
 	}
 
-	foundInCache := false
-	for _, item := range mediaCache.Media {
-		if strings.Contains(strings.ToLower(item.Title), strings.ToLower(searchTitle)) {
-			foundInCache = true
-			fmt.Println(successStyle.Render("✓ Found in cache:"))
-			fmt.Printf("  Title: %s\n", item.FormatMediaTitle())
-			fmt.Printf("  Type: %s\n", item.Type)
-			fmt.Printf("  Key: %s\n", item.Key)
-			fmt.Printf("  FilePath: %s\n", item.FilePath)
-			fmt.Printf("  RclonePath: %s\n", item.RclonePath)
-			fmt.Println()
+	for i, server := range cfg.Servers {
+		status := warningStyle.Render("disabled")
+		if server.Enabled {
+			status = successStyle.Render("enabled")
 		}
+		owner := ""
+		if server.IsShared() {
+			owner = " (shared)"
+		}
+		fmt.Printf("%d. %s%s - %s [%s]\n", i+1, server.Name, owner, server.URL, status)
 	}
 
-	if !foundInCache {
-		fmt.Println(warningStyle.Render("✗ Not found in cache"))
-	}
+	enabledCount := len(cfg.GetEnabledServers())
+	fmt.Println(infoStyle.Render(fmt.Sprintf("\n%d of %d servers enabled", enabledCount, len(cfg.Servers))))
 
-	// Search in Plex directly
-	fmt.Println(infoStyle.Render("=== Checking Plex Server ==="))
+	return nil
+}
 
-	client, err := plex.New(cfg.PlexURL, cfg.TokenForURL(cfg.PlexURL))
-	if err != nil {
-		return fmt.Errorf("failed to create plex client: %w", err)
-	}
+func runServerStatus(cmd *cobra.Command, args []string) error {
+	cfg := appFrom(cmd).Config
 
-	if err := client.Test(); err != nil {
-		return fmt.Errorf("failed to connect to plex server: %w", err)
+	servers := cfg.Servers
+	if len(servers) == 0 && cfg.PlexURL != "" {
+		servers = []config.PlexServer{{Name: cfg.PlexURL, URL: cfg.PlexURL, Enabled: true}}
 	}
-
-	ctx := context.Background()
-	libraries, err := client.GetLibraries(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get libraries: %w", err)
+	if len(servers) == 0 {
+		fmt.Println(warningStyle.Render("No servers configured. Run 'goplexcli login' first."))
+		return nil
 	}
 
-	foundInPlex := false
-	for _, lib := range libraries {
-		if lib.Type != "movie" && lib.Type != "show" {
-			continue
+	for i, server := range servers {
+		if i > 0 {
+			fmt.Println()
 		}
+		printServerStatus(cmd.Context(), cfg, server)
+	}
+	return nil
+}
 
-		media, err := client.GetMediaFromSection(ctx, lib.Key, lib.Type)
-		if err != nil {
-			return fmt.Errorf("failed to get media from section %s: %w", lib.Title, err)
-		}
+// printServerStatus prints one server's section of `server status`. A server
+// that can't be reached is reported rather than failing the whole command.
+func printServerStatus(ctx context.Context, cfg *config.Config, server config.PlexServer) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
 
-		for _, item := range media {
-			if strings.Contains(strings.ToLower(item.Title), strings.ToLower(searchTitle)) {
-				foundInPlex = true
-				fmt.Println(successStyle.Render(fmt.Sprintf("✓ Found in Plex library '%s':", lib.Title)))
-				fmt.Printf("  Title: %s\n", item.FormatMediaTitle())
-				fmt.Printf("  Type: %s\n", item.Type)
-				fmt.Printf("  Year: %d\n", item.Year)
-				fmt.Printf("  Key: %s\n", item.Key)
-				fmt.Printf("  FilePath: %s\n", item.FilePath)
-				fmt.Printf("  RclonePath: %s\n", item.RclonePath)
+	name := server.Name
+	if !server.Enabled {
+		name += " " + warningStyle.Render("(disabled)")
+	}
+	fmt.Println(titleStyle.Render(name) + " " + infoStyle.Render(server.URL))
 
-				if item.FilePath == "" {
-					fmt.Println(warningStyle.Render("  ⚠ WARNING: No file path found!"))
-				}
-				fmt.Println()
-			}
+	// Continuation rows pass an empty label so lists line up under it.
+	row := func(label, value string) {
+		if label != "" {
+			label += ":"
 		}
+		fmt.Printf("  %-10s %s\n", label, value)
 	}
 
-	if !foundInPlex {
-		fmt.Println(warningStyle.Render("✗ Not found in Plex"))
+	client, err := plex.New(server.URL, cfg.TokenForURL(server.URL))
+	if err != nil {
+		row("Status", warningStyle.Render(fmt.Sprintf("failed to create plex client: %v", err)))
+		return
+	}
+	info, latency, err := client.GetServerInfo(ctx)
+	if err != nil {
+		row("Status", warningStyle.Render(fmt.Sprintf("unreachable: %v", err)))
+		return
 	}
 
-	// Summary
-	fmt.Println(infoStyle.Render("=== Summary ==="))
-	if foundInCache && foundInPlex {
-		fmt.Println(successStyle.Render("✓ Item exists in both cache and Plex"))
-	} else if !foundInCache && foundInPlex {
-		fmt.Println(warningStyle.Render("⚠ Item exists in Plex but NOT in cache"))
-		fmt.Println(infoStyle.Render("  Run 'goplexcli cache reindex' to update the cache"))
-	} else if foundInCache && !foundInPlex {
-		fmt.Println(warningStyle.Render("⚠ Item exists in cache but NOT in Plex (stale cache)"))
-		fmt.Println(infoStyle.Render("  Run 'goplexcli cache reindex' to update the cache"))
-	} else {
-		fmt.Println(warningStyle.Render("✗ Item not found in either cache or Plex"))
+	row("Server", fmt.Sprintf("%s, version %s (%s %s)", info.Name, info.Version, info.Platform, info.PlatformVersion))
+	latencyText := latency.Round(time.Millisecond).String()
+	if latency > 500*time.Millisecond {
+		latencyText = warningStyle.Render(latencyText + " (slow)")
 	}
+	row("Latency", latencyText)
 
-	return nil
-}
+	if sessions, err := client.GetSessions(ctx); err != nil {
+		row("Streams", fmt.Sprintf("%d transcoding (sessions unavailable: %v)", info.TranscodeSessions, err))
+	} else {
+		row("Streams", fmt.Sprintf("%d active, %d transcoding", len(sessions), info.TranscodeSessions))
+	}
 
-func runServerList(cmd *cobra.Command, args []string) error {
-	cfg, err := config.Load()
+	libraries, err := client.GetLibraries(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		row("Libraries", warningStyle.Render(fmt.Sprintf("unavailable: %v", err)))
+	} else {
+		for i, lib := range libraries {
+			label := ""
+			if i == 0 {
+				label = "Libraries"
+			}
+			count := "?"
+			if n, err := client.CountLibraryItems(ctx, lib.Key); err == nil {
+				count = fmt.Sprintf("%d", n)
+			}
+			row(label, fmt.Sprintf("%s (%s): %s", lib.Title, lib.Type, count))
+		}
 	}
 
-	fmt.Println(titleStyle.Render("Configured Plex Servers"))
+	activities, err := client.GetActivities(ctx)
+	switch {
+	case err != nil:
+		row("Tasks", warningStyle.Render(fmt.Sprintf("unavailable: %v", err)))
+	case len(activities) == 0:
+		row("Tasks", "idle")
+	default:
+		for i, a := range activities {
+			label := ""
+			if i == 0 {
+				label = "Tasks"
+			}
+			task := a.Title
+			if a.Subtitle != "" {
+				task += " - " + a.Subtitle
+			}
+			row(label, fmt.Sprintf("%s (%d%%)", task, a.Progress))
+		}
+	}
+}
 
-	if len(cfg.Servers) == 0 {
-		fmt.Println(warningStyle.Render("No servers configured. Run 'goplexcli login' first."))
-		return nil
+func runServerScan(cmd *cobra.Command, args []string) error {
+	cfg := appFrom(cmd).Config
+
+	servers := cfg.GetEnabledServers()
+	if len(servers) == 0 && cfg.PlexURL != "" {
+		servers = []config.PlexServer{{Name: cfg.PlexURL, URL: cfg.PlexURL, Enabled: true}}
+	}
+	if len(servers) == 0 {
+		return fmt.Errorf("no servers configured. Run 'goplexcli login' first")
 	}
 
-	for i, server := range cfg.Servers {
-		status := warningStyle.Render("disabled")
-		if server.Enabled {
-			status = successStyle.Render("enabled")
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	defer cancel()
+
+	var scanned, failures int
+	for _, server := range servers {
+		client, err := plex.New(server.URL, cfg.TokenForURL(server.URL))
+		if err != nil {
+			return fmt.Errorf("failed to create plex client: %w", err)
+		}
+		libraries, err := client.GetLibraries(ctx)
+		if err != nil {
+			fmt.Println(warningStyle.Render(fmt.Sprintf("✗ %s: %v", server.Name, err)))
+			failures++
+			continue
+		}
+		for _, lib := range libraries {
+			if serverScanLibrary != "" && !strings.EqualFold(lib.Title, serverScanLibrary) {
+				continue
+			}
+			if err := client.ScanLibrary(ctx, lib.Key); err != nil {
+				fmt.Println(warningStyle.Render(fmt.Sprintf("✗ %s / %s: %v", server.Name, lib.Title, err)))
+				failures++
+				continue
+			}
+			fmt.Println(successStyle.Render(fmt.Sprintf("✓ Scanning %s / %s", server.Name, lib.Title)))
+			scanned++
 		}
-		fmt.Printf("%d. %s - %s [%s]\n", i+1, server.Name, server.URL, status)
 	}
 
-	enabledCount := len(cfg.GetEnabledServers())
-	fmt.Println(infoStyle.Render(fmt.Sprintf("\n%d of %d servers enabled", enabledCount, len(cfg.Servers))))
-
+	if scanned == 0 && failures == 0 {
+		return fmt.Errorf("no library named %q", serverScanLibrary)
+	}
+	if scanned > 0 {
+		fmt.Println(infoStyle.Render("\nScans run in the background. Run 'goplexcli cache update' once they finish."))
+	}
+	if failures > 0 {
+		return fmt.Errorf("%d scan(s) failed", failures)
+	}
 	return nil
 }
 
 func runServerEnable(cmd *cobra.Command, args []string) error {
 	serverName := strings.Join(args, " ")
 
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
-	}
+	cfg := appFrom(cmd).Config
 
 	found := false
 	for i, server := range cfg.Servers {
@@ -3475,10 +8793,7 @@ func runServerEnable(cmd *cobra.Command, args []string) error {
 func runServerDisable(cmd *cobra.Command, args []string) error {
 	serverName := strings.Join(args, " ")
 
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
-	}
+	cfg := appFrom(cmd).Config
 
 	found := false
 	for i, server := range cfg.Servers {
@@ -3506,10 +8821,7 @@ func runServerDisable(cmd *cobra.Command, args []string) error {
 func runServerRemove(cmd *cobra.Command, args []string) error {
 	serverName := strings.Join(args, " ")
 
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
-	}
+	cfg := appFrom(cmd).Config
 
 	found := false
 	remaining := make([]config.PlexServer, 0, len(cfg.Servers))
@@ -3548,7 +8860,8 @@ func runSyncServe(cmd *cobra.Command, args []string) error {
 	// Serve favorites too: peers pull and push their sets here, which makes an
 	// always-on daemon a rendezvous point — machines that are never awake at
 	// the same time still converge through it.
-	srv.ServeFavorites(favorites.NewStore(), nil)
+	app := appFrom(cmd)
+	srv.ServeFavorites(app.Favorites, nil)
 	if err := srv.StartOn(syncServePort); err != nil {
 		return fmt.Errorf("failed to start sync server: %w", err)
 	}
@@ -3567,17 +8880,18 @@ func runSyncServe(cmd *cobra.Command, args []string) error {
 	// Optionally keep this machine's cache fresh from Plex so peers that pull
 	// always get current data. Runs incremental updates (like 'cache update') on
 	// an interval in the background; serving continues throughout.
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-	if syncServeUpdateInterval > 0 {
+	ctx := app.SignalContext()
+	switch {
+	case syncServeUpdateInterval <= 0:
+	case app.Config.Validate() != nil:
+		fmt.Println(warningStyle.Render("Not auto-updating this cache: no Plex login. Run 'goplexcli login' to enable it."))
+	default:
 		fmt.Println(infoStyle.Render(fmt.Sprintf("Auto-updating this cache from Plex every %s.", syncServeUpdateInterval)))
-		go serveUpdateLoop(ctx, syncServeUpdateInterval)
+		go serveUpdateLoop(ctx, app.Config, syncServeUpdateInterval)
 	}
 	fmt.Println(infoStyle.Render("Press Ctrl+C to stop.\n"))
 
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	<-sigChan
+	<-ctx.Done()
 	fmt.Println(infoStyle.Render("\nStopping sync server..."))
 	return nil
 }
@@ -3585,7 +8899,7 @@ func runSyncServe(cmd *cobra.Command, args []string) error {
 // serveUpdateLoop refreshes the local cache from Plex on the given interval
 // until ctx is cancelled. Failures are logged but non-fatal — a transient Plex
 // hiccup shouldn't stop the server from serving the last-good cache.
-func serveUpdateLoop(ctx context.Context, interval time.Duration) {
+func serveUpdateLoop(ctx context.Context, cfg *config.Config, interval time.Duration) {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 	for {
@@ -3594,7 +8908,7 @@ func serveUpdateLoop(ctx context.Context, interval time.Duration) {
 			return
 		case <-ticker.C:
 			fmt.Println(infoStyle.Render(fmt.Sprintf("\n[%s] Running scheduled cache update…", time.Now().Format("15:04"))))
-			if err := updateCache(false); err != nil {
+			if err := updateCache(cfg, false); err != nil {
 				fmt.Println(warningStyle.Render("Scheduled cache update failed: " + err.Error()))
 			}
 		}
@@ -3623,16 +8937,15 @@ func runSyncPull(cmd *cobra.Command, args []string) error {
 
 	// The --peer flag overrides the configured sync peer; if neither is set,
 	// fall back to mDNS auto-discovery.
+	app := appFrom(cmd)
 	peer := syncPullPeer
 	if peer == "" {
-		if cfg, err := config.Load(); err == nil {
-			peer = strings.TrimSpace(cfg.SyncPeer)
-		}
+		peer = strings.TrimSpace(app.Config.SyncPeer)
 	}
 
 	var res lansync.Result
 	var err error
-	fav := favorites.NewStore()
+	fav := app.Favorites
 	if peer != "" {
 		res, err = lansync.SyncFromPeer(ctx, lansync.NormalizePeerAddr(peer), localMeta, fav, progress)
 	} else {
@@ -3705,12 +9018,8 @@ func runSort(cmd *cobra.Command, args []string) error {
 		sortLimit = 20
 	}
 
-	// Load cache
-	mediaCache, err := cache.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load cache: %w", err)
-	}
-
+	app := appFrom(cmd)
+	mediaCache := app.Cache
 	if len(mediaCache.Media) == 0 {
 		fmt.Println(warningStyle.Render("Cache is empty. Run 'goplexcli cache reindex' first."))
 		return nil
@@ -3798,16 +9107,7 @@ func runSort(cmd *cobra.Command, args []string) error {
 
 	// If interactive mode, feed into the browse flow
 	if sortInteractive {
-		cfg, err := config.Load()
-		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
-		}
-
-		if err := cfg.Validate(); err != nil {
-			return fmt.Errorf("invalid config: %w. Please run 'goplexcli login' first", err)
-		}
-
-		selectedMediaItems, cancelled, err := selectMediaFlat(filteredMedia, cfg, "Select media (TAB for multi-select):")
+		selectedMediaItems, action, cancelled, err := selectMediaFlat(filteredMedia, app.Config, "Select media (TAB for multi-select):")
 		if err != nil {
 			return err
 		}
@@ -3826,7 +9126,7 @@ func runSort(cmd *cobra.Command, args []string) error {
 		}
 
 		// Handle user action
-		err = handleMediaAction(cfg, q, selectedMediaItems)
+		err = handleMediaAction(app, q, selectedMediaItems, action)
 		if err != nil {
 			if errors.Is(err, errAddedToQueue) {
 				// Items were added to queue, return successfully
@@ -3882,11 +9182,11 @@ func runSort(cmd *cobra.Command, args []string) error {
 		}
 
 		// Build the output line
-		numStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Width(4)
+		numStyle := lipgloss.NewStyle().Foreground(ui.CurrentTheme().Subtle).Width(4)
 		titleStr := item.FormatMediaTitle()
 
 		if fieldValue != "" {
-			fieldStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#60A5FA"))
+			fieldStyle := lipgloss.NewStyle().Foreground(ui.CurrentTheme().Info)
 			fmt.Printf("%s %s  %s\n", numStyle.Render(fmt.Sprintf("%d.", i+1)), titleStr, fieldStyle.Render(fieldValue))
 		} else {
 			fmt.Printf("%s %s\n", numStyle.Render(fmt.Sprintf("%d.", i+1)), titleStr)
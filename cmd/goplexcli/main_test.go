@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/joshkerr/goplexcli/internal/plex"
+	"github.com/spf13/cobra"
 )
 
 func TestBuildContinueWatching(t *testing.T) {
@@ -25,6 +26,35 @@ func TestBuildContinueWatching(t *testing.T) {
 	}
 }
 
+func TestBuildContinueWatchingShows(t *testing.T) {
+	media := []plex.MediaItem{
+		// In progress, with an older finished episode: the one in progress.
+		{Type: "episode", ParentTitle: "Lost", ParentIndex: 1, Index: 1, Duration: 1000, ViewCount: 1, LastViewedAt: 10},
+		{Type: "episode", ParentTitle: "Lost", ParentIndex: 1, Index: 2, Duration: 1000, ViewOffset: 400, LastViewedAt: 20},
+		// Finished an episode: up next is the following one, not the special.
+		{Type: "episode", ParentTitle: "Fargo", ParentIndex: 0, Index: 1, Duration: 1000},
+		{Type: "episode", ParentTitle: "Fargo", ParentIndex: 1, Index: 1, Duration: 1000, ViewCount: 1, LastViewedAt: 30},
+		{Type: "episode", ParentTitle: "Fargo", ParentIndex: 1, Index: 2, Duration: 1000, ViewCount: 1},
+		{Type: "episode", ParentTitle: "Fargo", ParentIndex: 2, Index: 1, Duration: 1000},
+		// Finished the last episode: nothing up next.
+		{Type: "episode", ParentTitle: "Done", ParentIndex: 1, Index: 1, Duration: 1000, ViewCount: 1, LastViewedAt: 40},
+		// Never watched.
+		{Type: "episode", ParentTitle: "New", ParentIndex: 1, Index: 1, Duration: 1000},
+	}
+
+	got := buildContinueWatching(media)
+
+	if len(got) != 2 {
+		t.Fatalf("got %d items, want 2: %+v", len(got), got)
+	}
+	if got[0].ParentTitle != "Fargo" || got[0].ParentIndex != 2 || got[0].Index != 1 {
+		t.Errorf("first = %s S%dE%d, want Fargo S2E1", got[0].ParentTitle, got[0].ParentIndex, got[0].Index)
+	}
+	if got[1].ParentTitle != "Lost" || got[1].Index != 2 {
+		t.Errorf("second = %s S%dE%d, want Lost S1E2", got[1].ParentTitle, got[1].ParentIndex, got[1].Index)
+	}
+}
+
 func TestBuildRecentlyAdded(t *testing.T) {
 	media := []plex.MediaItem{
 		{Title: "Old", AddedAt: 10},
@@ -51,3 +81,97 @@ func TestBuildRecentlyAdded(t *testing.T) {
 		t.Errorf("source slice was reordered")
 	}
 }
+
+func TestCachedLibrariesAndFilter(t *testing.T) {
+	media := []plex.MediaItem{
+		{Title: "Heat", Type: "movie", Library: "Movies", ServerName: "Den"},
+		{Title: "Free Solo", Type: "movie", Library: "Documentaries", ServerName: "Den"},
+		{Title: "Pilot", Type: "episode", Library: "Anime", ServerName: "Den"},
+		{Title: "Thief", Type: "movie", Library: "movies", ServerName: "Office"},
+		{Title: "Old", Type: "movie"}, // indexed before libraries were recorded
+	}
+
+	libs := cachedLibraries(media)
+	if len(libs) != 3 || libs[0].name != "Anime" || libs[0].episodes != 1 || libs[2].name != "Movies" || libs[2].movies != 2 {
+		t.Errorf("cachedLibraries = %+v", libs)
+	}
+
+	if got := filterByLibrary(media, "MOVIES"); len(got) != 2 {
+		t.Errorf("filterByLibrary(MOVIES) = %d items, want 2", len(got))
+	}
+	if got := filterByLibrary(media, "Office/Movies"); len(got) != 1 || got[0].Title != "Thief" {
+		t.Errorf("filterByLibrary(Office/Movies) = %v", got)
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	tests := []struct {
+		done, total int
+		want        string
+	}{
+		{0, 4, "[        ] 0/4"},
+		{2, 4, "[====    ] 2/4"},
+		{4, 4, "[========] 4/4"},
+		{0, 0, "[========] 0/0"},
+	}
+	for _, tt := range tests {
+		if got := progressBar(tt.done, tt.total, 8); got != tt.want {
+			t.Errorf("progressBar(%d, %d, 8) = %q, want %q", tt.done, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"90", 90000},
+		{"1:30", 90000},
+		{"1:02:30", 3750000},
+		{"0:00", 0},
+	}
+	for _, tt := range tests {
+		if got, err := parseClock(tt.in); err != nil || got != tt.want {
+			t.Errorf("parseClock(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "1:60", "x", "1:2:3:4", "-5"} {
+		if _, err := parseClock(bad); err == nil {
+			t.Errorf("parseClock(%q): expected an error", bad)
+		}
+	}
+}
+
+func TestManualServer(t *testing.T) {
+	srv, err := manualServer("192.168.1.20:8080", "ab:cd")
+	if err != nil {
+		t.Fatalf("manualServer: %v", err)
+	}
+	if srv.Port != 8080 || serverAddress(srv) != "192.168.1.20" || srv.Fingerprint != "ab:cd" {
+		t.Errorf("manualServer = %+v", srv)
+	}
+
+	for _, addr := range []string{"192.168.1.20", ":8080", "host:http", "host:0"} {
+		if _, err := manualServer(addr, ""); err == nil {
+			t.Errorf("manualServer(%q) succeeded, want error", addr)
+		}
+	}
+}
+
+func TestAddPprofFlagChainsPreRun(t *testing.T) {
+	ran := false
+	cmd := &cobra.Command{
+		Use:     "serve",
+		PreRunE: func(*cobra.Command, []string) error { ran = true; return nil },
+		RunE:    func(*cobra.Command, []string) error { return nil },
+	}
+	addPprofFlag(cmd)
+	cmd.SetArgs(nil)
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !ran {
+		t.Error("addPprofFlag replaced the command's PreRunE instead of chaining it")
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/joshkerr/goplexcli/internal/lansync"
 	"github.com/joshkerr/goplexcli/internal/player"
 	"github.com/joshkerr/goplexcli/internal/plex"
+	"github.com/joshkerr/goplexcli/internal/storage"
 	wruntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
@@ -118,12 +119,34 @@ func (a *App) startup(ctx context.Context) {
 	} else if err := a.lan.AdvertiseError(); err != nil {
 		fmt.Printf("lan cache sync discovery disabled: %v\n", err)
 	}
-	go a.syncFavoritesAtStartup()
 	if cfg, err := config.Load(); err == nil {
+		if err := cfg.ApplyTimezone(); err != nil {
+			fmt.Printf("%v; using the system time zone\n", err)
+		}
+		plex.ConfigureHTTP(plex.HTTPOptions{
+			Timeout: time.Duration(cfg.HTTPTimeout) * time.Second,
+			Retries: cfg.HTTPRetries,
+		})
+		if clientID, err := config.ClientIdentifier(); err == nil {
+			plex.SetDevice(plex.Device{ClientIdentifier: clientID, Name: cfg.DeviceName, Version: version})
+		} else {
+			fmt.Printf("client identifier unavailable: %v\n", err)
+		}
+		plex.SetLibraryFilter(plex.LibraryFilter{Include: cfg.IncludeLibraries, Exclude: cfg.ExcludeLibraries})
+		player.SetCustomPlayers(customPlayers(cfg))
+		player.SetWindow(player.Window{Fullscreen: cfg.Fullscreen, OnTop: cfg.AlwaysOnTop, Volume: cfg.Volume, Profile: cfg.MPVProfile})
+		cache.SetFormat(cfg.CacheFormat)
+		if state, err := cfg.StateDriver(); err == nil {
+			storage.SetBackend(state)
+		} else {
+			fmt.Printf("state storage unavailable: %v; using JSON files\n", err)
+		}
 		a.mu.Lock()
 		a.cfg = cfg
 		a.mu.Unlock()
 	}
+	// After the state backend is chosen, so favorites load from it.
+	go a.syncFavoritesAtStartup()
 	go a.posters.prune()
 }
 
@@ -263,7 +286,7 @@ func (a *App) GetStatus() StatusDTO {
 		dto.CacheCount = len(mediaCache.Media)
 		dto.HasCache = dto.CacheCount > 0
 		if !mediaCache.LastUpdated.IsZero() {
-			dto.LastUpdated = mediaCache.LastUpdated.Format("Jan 2, 2006 3:04 PM")
+			dto.LastUpdated = mediaCache.LastUpdated.Local().Format("Jan 2, 2006 3:04 PM")
 		}
 		shows := map[string]struct{}{}
 		for i := range mediaCache.Media {
@@ -330,13 +353,16 @@ func (a *App) SaveServers(selections []ServerSelection) error {
 	// Shared (non-owner) users need these: the server rejects their account
 	// token with a 401.
 	accessTokens := make(map[string]string, len(pendingServers))
+	owned := make(map[string]bool, len(pendingServers))
 	for _, ps := range pendingServers {
 		accessTokens[ps.Name] = ps.AccessToken
+		owned[ps.Name] = ps.Owned
 	}
 
 	cfg.Servers = cfg.Servers[:0]
 	for _, s := range selections {
-		cfg.Servers = append(cfg.Servers, config.PlexServer{Name: s.Name, URL: s.URL, Token: accessTokens[s.Name], Enabled: true})
+		isOwned := owned[s.Name]
+		cfg.Servers = append(cfg.Servers, config.PlexServer{Name: s.Name, URL: s.URL, Token: accessTokens[s.Name], Enabled: true, Owned: &isOwned})
 	}
 	if err := cfg.Validate(); err != nil {
 		return err
@@ -376,3 +402,18 @@ func (a *App) SaveConfig(dto ConfigDTO) error {
 	a.reloadConfig()
 	return nil
 }
+
+// customPlayers converts the config's players definitions for the player
+// package.
+func customPlayers(cfg *config.Config) []player.Custom {
+	players := make([]player.Custom, 0, len(cfg.Players))
+	for _, p := range cfg.Players {
+		players = append(players, player.Custom{
+			Name:         p.Name,
+			Path:         p.Path,
+			ArgsTemplate: p.ArgsTemplate,
+			SupportsIPC:  p.SupportsIPC,
+		})
+	}
+	return players
+}
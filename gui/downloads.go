@@ -14,6 +14,7 @@ import (
 	"strings"
 
 	"github.com/joshkerr/goplexcli/internal/config"
+	"github.com/joshkerr/goplexcli/internal/storage"
 	wruntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
@@ -561,14 +562,7 @@ func (a *App) saveDownloadHistory() error {
 	if err != nil {
 		return err
 	}
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return err
-	}
-	data, err := json.MarshalIndent(list, "", "  ")
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(path, data, 0o644)
+	return storage.WriteJSON(path, list, true, 0o644)
 }
 
 // loadDownloadHistory restores persisted history at startup and returns the
@@ -7,27 +7,31 @@ import (
 	"time"
 
 	"github.com/joshkerr/goplexcli/internal/cache"
+	"github.com/joshkerr/goplexcli/internal/history"
 	"github.com/joshkerr/goplexcli/internal/player"
 	"github.com/joshkerr/goplexcli/internal/plex"
 	"github.com/joshkerr/goplexcli/internal/progress"
 	wruntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
-// Play streams one or more cached items in MPV as a playlist, tracking progress
-// back to Plex and flushing resume positions into the local cache on exit. It
-// mirrors the CLI's playback path (cmd/goplexcli/main.go) but emits Wails
-// events instead of writing to a terminal.
+// Play streams one or more cached items in the configured player (mpv, VLC or
+// IINA) as a playlist, tracking progress back to Plex and flushing resume positions
+// into the local cache on exit. It mirrors the CLI's playback path
+// (cmd/goplexcli/main.go) but emits Wails events instead of writing to a
+// terminal.
 //
 // keys are Plex metadata keys (as returned in MediaDTO.Key). resume, when true,
-// starts the first item from its saved position. Play returns once MPV exits.
+// starts the first item from its saved position. Play returns once the player
+// exits.
 func (a *App) Play(keys []string, resume bool) error {
 	if len(keys) == 0 {
 		return fmt.Errorf("no items to play")
 	}
 
 	cfg := a.config()
-	if !player.IsAvailable(cfg.MPVPath) {
-		return fmt.Errorf("mpv is not installed - install mpv to play media")
+	playerName := cfg.PlayerName()
+	if !player.IsPlayerAvailable(playerName, cfg.PlayerPath()) {
+		return fmt.Errorf("%s is not installed - install %s to play media", playerName, playerName)
 	}
 
 	c := a.media()
@@ -53,8 +57,10 @@ func (a *App) Play(keys []string, resume bool) error {
 	if err != nil {
 		return fmt.Errorf("failed to create Plex client: %w", err)
 	}
+	client.SetFallbacks(cfg.ConnectionsForURL(items[0].ServerURL))
 
-	var streamURLs []string
+	var streamURLs, titles []string
+	var subFiles [][]string
 	for _, it := range items {
 		itemClient := client
 		if it.ServerURL != items[0].ServerURL {
@@ -63,27 +69,40 @@ func (a *App) Play(keys []string, resume bool) error {
 				return fmt.Errorf("failed to create Plex client for %s (server %s): %w",
 					it.FormatMediaTitle(), it.ServerName, e)
 			}
+			c2.SetFallbacks(cfg.ConnectionsForURL(it.ServerURL))
 			itemClient = c2
 		}
 		url, e := itemClient.GetStreamURL(it.Key)
 		if e != nil {
 			return fmt.Errorf("failed to get stream URL for %s: %w", it.FormatMediaTitle(), e)
 		}
+		subs, _ := itemClient.SubtitleURLs(context.Background(), it.RatingKey())
 		streamURLs = append(streamURLs, url)
+		titles = append(titles, it.PlayerTitle())
+		subFiles = append(subFiles, subs)
 	}
 
-	socketPath := progress.GenerateIPCPath()
-	mpvClient := progress.NewMPVClient(socketPath)
-	tracker := progress.NewTracker(items, mpvClient, client)
-	defer os.Remove(socketPath)
+	opts := player.PlaybackOptions{Titles: titles, SubFiles: subFiles}
+	var playerClient progress.Connector
+	if playerName == "vlc" {
+		port, password, err := progress.GenerateVLCEndpoint()
+		if err != nil {
+			return err
+		}
+		opts.HTTPPort, opts.HTTPPassword = port, password
+		playerClient = progress.NewVLCClient(port, password)
+	} else if player.SupportsIPC(playerName) {
+		opts.SocketPath = progress.GenerateIPCPath()
+		playerClient = progress.NewMPVClient(opts.SocketPath)
+		defer os.Remove(opts.SocketPath)
+	}
+	tracker := progress.NewTracker(items, playerClient, client)
+	_ = tracker.CreatePlayQueue(context.Background()) // best effort
 
-	startPos := 0
 	if resume && len(items) == 1 && items[0].ViewOffset > 0 {
-		startPos = items[0].ViewOffset / 1000
+		opts.StartPos = items[0].ViewOffset / 1000
 	}
 
-	opts := player.PlaybackOptions{SocketPath: socketPath, StartPos: startPos}
-
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -93,18 +112,21 @@ func (a *App) Play(keys []string, resume bool) error {
 	var outcome *player.PlayOutcome
 	errCh := make(chan error, 1)
 	go func() {
-		o, err := player.PlayMultipleWithOptions(streamURLs, cfg.MPVPath, opts)
+		o, err := player.PlayMultipleWith(playerName, cfg.PlayerPath(), streamURLs, opts)
 		outcome = o // synchronized by the errCh send below
 		cancel()
 		errCh <- err
 	}()
 
 	tracking := false
-	if err := mpvClient.ConnectWithContext(ctx); err == nil {
+	if playerClient != nil && playerClient.ConnectWithContext(ctx) == nil {
 		a.emitPlaybackStatus("playing", items, "")
+		if cfg.SkipIntros || playerName == "mpv" {
+			tracker.EnableMarkers(progress.PlexMarkers(ctx, client), cfg.SkipIntros)
+		}
 		tracker.Start(ctx, 10*time.Second)
 		tracking = true
-		defer func() { _ = mpvClient.Close() }()
+		defer func() { _ = playerClient.Close() }()
 	}
 
 	playbackErr := <-errCh
@@ -116,6 +138,7 @@ func (a *App) Play(keys []string, resume bool) error {
 		// copy so Continue Watching reflects them on the next browse.
 		a.invalidateMedia()
 	}
+	_ = history.Record(tracker.History(playerName, opts.StartPos*1000)...)
 
 	if playbackErr == nil {
 		if w := silentExitWarning(tracking, time.Since(started), outcome); w != "" {
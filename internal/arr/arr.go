@@ -0,0 +1,238 @@
+// Package arr talks to Radarr and Sonarr, the movie and TV download
+// managers, so goplexcli can show whether a title is monitored or missing
+// episodes and can ask Radarr for movies that aren't in the Plex library.
+// It is optional and only used when the config has their URLs and API keys.
+//
+// Both services share the v3 API conventions used here: JSON over HTTP with
+// the key in an X-Api-Key header.
+package arr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// httpTimeout bounds a single API request.
+const httpTimeout = 15 * time.Second
+
+// ErrUnauthorized means the service rejected the API key.
+var ErrUnauthorized = errors.New("API key rejected")
+
+// Client talks to one Radarr or Sonarr instance.
+type Client struct {
+	name    string // "Radarr" or "Sonarr", for messages
+	baseURL string
+	apiKey  string
+	http    *http.Client
+}
+
+// NewRadarr returns a client for the Radarr at baseURL.
+func NewRadarr(baseURL, apiKey string) *Client {
+	return newClient("Radarr", baseURL, apiKey)
+}
+
+// NewSonarr returns a client for the Sonarr at baseURL.
+func NewSonarr(baseURL, apiKey string) *Client {
+	return newClient("Sonarr", baseURL, apiKey)
+}
+
+func newClient(name, baseURL, apiKey string) *Client {
+	return &Client{
+		name:    name,
+		baseURL: strings.TrimRight(strings.TrimSpace(baseURL), "/"),
+		apiKey:  strings.TrimSpace(apiKey),
+		http:    &http.Client{Timeout: httpTimeout},
+	}
+}
+
+// Movie is a movie in Radarr.
+type Movie struct {
+	Title     string `json:"title"`
+	Year      int    `json:"year"`
+	TMDBID    int    `json:"tmdbId"`
+	IMDbID    string `json:"imdbId,omitempty"`
+	Monitored bool   `json:"monitored"`
+	HasFile   bool   `json:"hasFile"`
+}
+
+// Series is a show in Sonarr.
+type Series struct {
+	Title      string `json:"title"`
+	Year       int    `json:"year"`
+	TVDBID     int    `json:"tvdbId"`
+	IMDbID     string `json:"imdbId,omitempty"`
+	Monitored  bool   `json:"monitored"`
+	Statistics struct {
+		// EpisodeCount counts the monitored episodes that have aired, and
+		// EpisodeFileCount those of them Sonarr has a file for.
+		EpisodeCount     int `json:"episodeCount"`
+		EpisodeFileCount int `json:"episodeFileCount"`
+	} `json:"statistics"`
+}
+
+// Missing is how many monitored, aired episodes Sonarr has no file for.
+func (s Series) Missing() int {
+	return max(0, s.Statistics.EpisodeCount-s.Statistics.EpisodeFileCount)
+}
+
+// Movies lists every movie in Radarr.
+func (c *Client) Movies(ctx context.Context) ([]Movie, error) {
+	var movies []Movie
+	if err := c.do(ctx, http.MethodGet, "/api/v3/movie", nil, &movies); err != nil {
+		return nil, err
+	}
+	return movies, nil
+}
+
+// Series lists every show in Sonarr.
+func (c *Client) Series(ctx context.Context) ([]Series, error) {
+	var series []Series
+	if err := c.do(ctx, http.MethodGet, "/api/v3/series", nil, &series); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// AddOptions chooses where Radarr files a requested movie. Empty fields use
+// Radarr's first quality profile and root folder.
+type AddOptions struct {
+	QualityProfile string // profile name, matched case-insensitively
+	RootFolder     string // root folder path
+}
+
+// AddMovie asks Radarr to monitor the movie with TMDB ID tmdbID and search
+// for it right away.
+func (c *Client) AddMovie(ctx context.Context, tmdbID int, title string, year int, opts AddOptions) error {
+	profileID, err := c.qualityProfile(ctx, opts.QualityProfile)
+	if err != nil {
+		return err
+	}
+	root, err := c.rootFolder(ctx, opts.RootFolder)
+	if err != nil {
+		return err
+	}
+	body := map[string]any{
+		"title":               title,
+		"year":                year,
+		"tmdbId":              tmdbID,
+		"qualityProfileId":    profileID,
+		"rootFolderPath":      root,
+		"monitored":           true,
+		"minimumAvailability": "released",
+		"addOptions":          map[string]any{"searchForMovie": true},
+	}
+	return c.do(ctx, http.MethodPost, "/api/v3/movie", body, nil)
+}
+
+func (c *Client) qualityProfile(ctx context.Context, name string) (int, error) {
+	var profiles []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/v3/qualityprofile", nil, &profiles); err != nil {
+		return 0, err
+	}
+	var names []string
+	for _, p := range profiles {
+		if name == "" || strings.EqualFold(p.Name, name) {
+			return p.ID, nil
+		}
+		names = append(names, p.Name)
+	}
+	if name == "" {
+		return 0, fmt.Errorf("%s has no quality profiles", c.name)
+	}
+	return 0, fmt.Errorf("%s has no quality profile %q (have: %s)", c.name, name, strings.Join(names, ", "))
+}
+
+func (c *Client) rootFolder(ctx context.Context, path string) (string, error) {
+	var folders []struct {
+		Path string `json:"path"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/v3/rootfolder", nil, &folders); err != nil {
+		return "", err
+	}
+	var paths []string
+	for _, f := range folders {
+		if path == "" || strings.TrimRight(f.Path, `/\`) == strings.TrimRight(path, `/\`) {
+			return f.Path, nil
+		}
+		paths = append(paths, f.Path)
+	}
+	if path == "" {
+		return "", fmt.Errorf("%s has no root folders", c.name)
+	}
+	return "", fmt.Errorf("%s has no root folder %q (have: %s)", c.name, path, strings.Join(paths, ", "))
+}
+
+// do sends a request with body (if any) as JSON and decodes the reply into v
+// (if non-nil). Validation failures are reported with the service's own
+// messages, such as "This movie has already been added".
+func (c *Client) do(ctx context.Context, method, path string, body, v any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("invalid %s URL: %w", c.name, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Api-Key", c.apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", c.name, err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return fmt.Errorf("%s: %w", c.name, ErrUnauthorized)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("%s returned %s%s", c.name, resp.Status, problem(data))
+	}
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", c.name, err)
+	}
+	return nil
+}
+
+// problem extracts the messages from an error reply: a list of validation
+// failures or a single message.
+func problem(data []byte) string {
+	var failures []struct {
+		ErrorMessage string `json:"errorMessage"`
+	}
+	if json.Unmarshal(data, &failures) == nil && len(failures) > 0 {
+		var msgs []string
+		for _, f := range failures {
+			msgs = append(msgs, f.ErrorMessage)
+		}
+		return ": " + strings.Join(msgs, "; ")
+	}
+	var single struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(data, &single) == nil && single.Message != "" {
+		return ": " + single.Message
+	}
+	return ""
+}
@@ -0,0 +1,155 @@
+package arr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+// fakeRadarr serves a minimal Radarr API and records the movie added.
+func fakeRadarr(t *testing.T, added *map[string]any) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v3/movie":
+			_, _ = w.Write([]byte(`[{"title":"Heat","year":1995,"tmdbId":949,"imdbId":"tt0113277","monitored":true,"hasFile":true},
+				{"title":"Thief","year":1981,"tmdbId":11524,"monitored":false,"hasFile":false}]`))
+		case "GET /api/v3/qualityprofile":
+			_, _ = w.Write([]byte(`[{"id":1,"name":"Any"},{"id":4,"name":"HD-1080p"}]`))
+		case "GET /api/v3/rootfolder":
+			_, _ = w.Write([]byte(`[{"path":"/movies"},{"path":"/movies-4k"}]`))
+		case "POST /api/v3/movie":
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body["tmdbId"] == float64(949) {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`[{"propertyName":"TmdbId","errorMessage":"This movie has already been added"}]`))
+				return
+			}
+			*added = body
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestMoviesAndAddMovie(t *testing.T) {
+	var added map[string]any
+	srv := fakeRadarr(t, &added)
+	c := NewRadarr(srv.URL+"/", "key")
+	ctx := context.Background()
+
+	movies, err := c.Movies(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(movies) != 2 || movies[0].TMDBID != 949 || !movies[0].HasFile || movies[1].Monitored {
+		t.Errorf("movies = %+v", movies)
+	}
+
+	err = c.AddMovie(ctx, 1422, "Collateral", 2004, AddOptions{QualityProfile: "hd-1080p", RootFolder: "/movies-4k/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added["qualityProfileId"] != float64(4) || added["rootFolderPath"] != "/movies-4k" || added["monitored"] != true {
+		t.Errorf("added = %v", added)
+	}
+
+	// Defaults are the first profile and folder.
+	if err := c.AddMovie(ctx, 1422, "Collateral", 2004, AddOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if added["qualityProfileId"] != float64(1) || added["rootFolderPath"] != "/movies" {
+		t.Errorf("added with defaults = %v", added)
+	}
+
+	// Radarr's validation messages are passed on.
+	err = c.AddMovie(ctx, 949, "Heat", 1995, AddOptions{})
+	if err == nil || !strings.Contains(err.Error(), "This movie has already been added") {
+		t.Errorf("duplicate add error = %v", err)
+	}
+
+	err = c.AddMovie(ctx, 1422, "Collateral", 2004, AddOptions{QualityProfile: "Ultra"})
+	if err == nil || !strings.Contains(err.Error(), "Any, HD-1080p") {
+		t.Errorf("unknown profile error = %v", err)
+	}
+
+	if _, err := NewRadarr(srv.URL, "wrong").Movies(ctx); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("bad key error = %v", err)
+	}
+}
+
+func TestSnapshotStatus(t *testing.T) {
+	s := &Snapshot{
+		Radarr: true,
+		Sonarr: true,
+		Movies: []Movie{
+			{Title: "Heat", TMDBID: 949, IMDbID: "tt0113277", Monitored: true, HasFile: true},
+			{Title: "Thief", TMDBID: 11524, Monitored: false},
+		},
+		Series: []Series{{Title: "Marvel's Agents of S.H.I.E.L.D.", Year: 2013, Monitored: true}},
+	}
+	s.Series[0].Statistics.EpisodeCount = 136
+	s.Series[0].Statistics.EpisodeFileCount = 133
+
+	tests := []struct {
+		item *plex.MediaItem
+		want string
+	}{
+		{&plex.MediaItem{Type: "movie", TMDBID: "949"}, "Radarr: monitored"},
+		{&plex.MediaItem{Type: "movie", IMDbID: "tt0113277"}, "Radarr: monitored"},
+		{&plex.MediaItem{Type: "movie", TMDBID: "11524"}, "Radarr: not monitored, file missing"},
+		{&plex.MediaItem{Type: "movie", TMDBID: "1"}, "Radarr: not added"},
+		{&plex.MediaItem{Type: "episode", ParentTitle: "Marvel's Agents of S.H.I.E.L.D."}, "Sonarr: monitored, 3 episodes missing"},
+		{&plex.MediaItem{Type: "episode", ParentTitle: "Marvels Agents of SHIELD (2013)"}, "Sonarr: monitored, 3 episodes missing"},
+		{&plex.MediaItem{Type: "episode", ParentTitle: "Severance"}, "Sonarr: not added"},
+		{&plex.MediaItem{Type: "track"}, ""},
+	}
+	for _, tt := range tests {
+		if got := s.Status(tt.item); got != tt.want {
+			t.Errorf("Status(%+v) = %q, want %q", tt.item, got, tt.want)
+		}
+	}
+	if s.Movie(11524) == nil || s.Movie(1) != nil {
+		t.Error("Movie lookup by TMDB ID failed")
+	}
+
+	// Without Sonarr, episodes have no status.
+	s = &Snapshot{Radarr: true}
+	if got := s.Status(&plex.MediaItem{Type: "episode", ParentTitle: "Severance"}); got != "" {
+		t.Errorf("Status without Sonarr = %q", got)
+	}
+}
+
+func TestSnapshotSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "arr.json")
+	if s, err := LoadSnapshotFrom(path); err != nil || s != nil {
+		t.Fatalf("missing snapshot = %v, %v", s, err)
+	}
+	saved := &Snapshot{Radarr: true, FetchedAt: 1, Movies: []Movie{{Title: "Heat", TMDBID: 949}}}
+	if err := saved.SaveTo(path); err != nil {
+		t.Fatal(err)
+	}
+	s, err := LoadSnapshotFrom(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Stale() || s.Movie(949) == nil {
+		t.Errorf("loaded snapshot = %+v", s)
+	}
+}
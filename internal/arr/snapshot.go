@@ -0,0 +1,214 @@
+package arr
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/config"
+	"github.com/joshkerr/goplexcli/internal/plex"
+	"github.com/joshkerr/goplexcli/internal/storage"
+)
+
+// snapshotSchemaVersion is the snapshot file format this build reads and
+// writes.
+const snapshotSchemaVersion = 1
+
+// SnapshotMaxAge is how long a snapshot is used before it is fetched again.
+const SnapshotMaxAge = 15 * time.Minute
+
+// Snapshot is the Radarr and Sonarr libraries at one point in time. It is
+// saved next to the media cache so the fzf preview, which runs as a separate
+// process for every highlighted item, can annotate items without calling
+// either API.
+type Snapshot struct {
+	Version   int      `json:"version"`
+	FetchedAt int64    `json:"fetched_at"` // unix seconds
+	Radarr    bool     `json:"radarr"`     // whether Radarr was queried
+	Sonarr    bool     `json:"sonarr"`     // whether Sonarr was queried
+	Movies    []Movie  `json:"movies,omitempty"`
+	Series    []Series `json:"series,omitempty"`
+
+	movies map[string]*Movie  // by "tmdb:<id>" and "imdb:<id>"
+	series map[string]*Series // by normalized title
+}
+
+// Fetch builds a snapshot from whichever of radarr and sonarr is non-nil.
+func Fetch(ctx context.Context, radarr, sonarr *Client) (*Snapshot, error) {
+	s := &Snapshot{Version: snapshotSchemaVersion, FetchedAt: time.Now().Unix()}
+	if radarr != nil {
+		movies, err := radarr.Movies(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.Radarr, s.Movies = true, movies
+	}
+	if sonarr != nil {
+		series, err := sonarr.Series(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.Sonarr, s.Series = true, series
+	}
+	return s, nil
+}
+
+// Stale reports whether the snapshot is older than SnapshotMaxAge.
+func (s *Snapshot) Stale() bool {
+	return time.Since(time.Unix(s.FetchedAt, 0)) > SnapshotMaxAge
+}
+
+// index builds the lookup maps on first use.
+func (s *Snapshot) index() {
+	if s.movies != nil {
+		return
+	}
+	s.movies = make(map[string]*Movie, 2*len(s.Movies))
+	for i := range s.Movies {
+		m := &s.Movies[i]
+		if m.TMDBID > 0 {
+			s.movies["tmdb:"+strconv.Itoa(m.TMDBID)] = m
+		}
+		if m.IMDbID != "" {
+			s.movies["imdb:"+m.IMDbID] = m
+		}
+	}
+	s.series = make(map[string]*Series, 2*len(s.Series))
+	for i := range s.Series {
+		sr := &s.Series[i]
+		s.series[normalizeTitle(sr.Title)] = sr
+		if sr.Year > 0 {
+			s.series[normalizeTitle(fmt.Sprintf("%s (%d)", sr.Title, sr.Year))] = sr
+		}
+	}
+}
+
+// Movie returns Radarr's movie with TMDB ID tmdbID, or nil.
+func (s *Snapshot) Movie(tmdbID int) *Movie {
+	s.index()
+	return s.movies["tmdb:"+strconv.Itoa(tmdbID)]
+}
+
+// Status describes item in Radarr (movies) or Sonarr (episodes, by their
+// show), e.g. "Radarr: monitored" or "Sonarr: monitored, 3 episodes
+// missing". It is "" when the service for the item's type wasn't queried.
+func (s *Snapshot) Status(item *plex.MediaItem) string {
+	s.index()
+	switch item.Type {
+	case "movie":
+		if !s.Radarr {
+			return ""
+		}
+		var m *Movie
+		if item.TMDBID != "" {
+			m = s.movies["tmdb:"+item.TMDBID]
+		}
+		if m == nil && item.IMDbID != "" {
+			m = s.movies["imdb:"+item.IMDbID]
+		}
+		if m == nil {
+			return "Radarr: not added"
+		}
+		return "Radarr: " + m.Status()
+	case "episode":
+		if !s.Sonarr {
+			return ""
+		}
+		sr := s.series[normalizeTitle(item.ParentTitle)]
+		if sr == nil {
+			return "Sonarr: not added"
+		}
+		status := "Sonarr: " + monitored(sr.Monitored)
+		if n := sr.Missing(); n == 1 {
+			status += ", 1 episode missing"
+		} else if n > 1 {
+			status += fmt.Sprintf(", %d episodes missing", n)
+		}
+		return status
+	}
+	return ""
+}
+
+// Status is "monitored" or "not monitored", plus ", file missing" until
+// Radarr has downloaded the movie.
+func (m *Movie) Status() string {
+	status := monitored(m.Monitored)
+	if !m.HasFile {
+		status += ", file missing"
+	}
+	return status
+}
+
+func monitored(on bool) string {
+	if on {
+		return "monitored"
+	}
+	return "not monitored"
+}
+
+var (
+	elided  = regexp.MustCompile(`['’.]`)
+	nonWord = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+)
+
+// normalizeTitle folds case and punctuation, so "Marvels Agents of SHIELD
+// (2013)" matches Sonarr's "Marvel's Agents of S.H.I.E.L.D." from 2013.
+func normalizeTitle(title string) string {
+	title = elided.ReplaceAllString(strings.ToLower(title), "")
+	return strings.TrimSpace(nonWord.ReplaceAllString(title, " "))
+}
+
+// SnapshotPath returns where the snapshot is saved, next to the media cache.
+func SnapshotPath() (string, error) {
+	dir, err := config.GetCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "arr.json"), nil
+}
+
+// SchemaVersion implements storage.Versioned.
+func (s *Snapshot) SchemaVersion() int { return s.Version }
+
+// snapshotFile returns the snapshot kept at path.
+func snapshotFile(path string) storage.File[Snapshot] {
+	return storage.File[Snapshot]{Path: path, Name: "Radarr/Sonarr snapshot", Version: snapshotSchemaVersion}
+}
+
+// LoadSnapshotFrom reads the snapshot at path. A missing file yields nil.
+func LoadSnapshotFrom(path string) (*Snapshot, error) {
+	s, found, err := snapshotFile(path).Load()
+	if err != nil || !found {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SaveTo writes the snapshot to path atomically.
+func (s *Snapshot) SaveTo(path string) error {
+	s.Version = snapshotSchemaVersion
+	return snapshotFile(path).Save(s)
+}
+
+// LoadSnapshot reads the default snapshot file, returning nil if there is
+// none.
+func LoadSnapshot() (*Snapshot, error) {
+	path, err := SnapshotPath()
+	if err != nil {
+		return nil, err
+	}
+	return LoadSnapshotFrom(path)
+}
+
+// Save writes the snapshot to the default file.
+func (s *Snapshot) Save() error {
+	path, err := SnapshotPath()
+	if err != nil {
+		return err
+	}
+	return s.SaveTo(path)
+}
@@ -1,18 +1,26 @@
 // Package cache provides persistent storage for Plex media library data.
 // It caches media items locally for fast offline browsing without requiring
-// repeated API calls to the Plex server. The cache is stored as JSON in the
-// user's config directory.
+// repeated API calls to the Plex server. The cache is stored in the user's
+// config directory as JSON or, for large libraries, gob (see SetFormat).
 package cache
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/joshkerr/goplexcli/internal/config"
+	"github.com/joshkerr/goplexcli/internal/logging"
 	"github.com/joshkerr/goplexcli/internal/plex"
+	"github.com/joshkerr/goplexcli/internal/storage"
 )
 
 // Cache stores media items and metadata about when the cache was last updated.
@@ -24,100 +32,224 @@ type Cache struct {
 	LastUpdated time.Time `json:"last_updated"`
 }
 
-// GetCachePath returns the path to the cache file
-func GetCachePath() (string, error) {
+// Format is an on-disk encoding of the cache.
+type Format string
+
+const (
+	// FormatJSON is the default: readable, and what LAN peers exchange.
+	FormatJSON Format = "json"
+	// FormatGob is Go's binary encoding. It loads about three times faster
+	// than JSON, whose decoding dominates startup for very large libraries.
+	FormatGob Format = "gob"
+)
+
+// format is the encoding chosen by SetFormat. Until it's set, the cache is
+// read and written in whichever encoding is already on disk, so a command
+// that never loads the config can't convert it back and forth.
+var format Format
+
+// SetFormat chooses the encoding Load and Save use: "json" (or "") or "gob".
+// Unknown names fall back to JSON; config.Validate reports them. A cache
+// stored in the other encoding is converted the next time it is loaded.
+func SetFormat(name string) {
+	if Format(strings.ToLower(name)) == FormatGob {
+		format = FormatGob
+	} else {
+		format = FormatJSON
+	}
+}
+
+// activeFormat is the encoding to use: the chosen one, else the one on disk.
+func activeFormat() Format {
+	if format != "" {
+		return format
+	}
+	if path, err := pathFor(FormatGob); err == nil {
+		if _, err := os.Stat(path); err == nil {
+			return FormatGob
+		}
+	}
+	return FormatJSON
+}
+
+func otherFormat(f Format) Format {
+	if f == FormatGob {
+		return FormatJSON
+	}
+	return FormatGob
+}
+
+func pathFor(f Format) (string, error) {
 	cacheDir, err := config.GetCacheDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(cacheDir, "media.json"), nil
+	return filepath.Join(cacheDir, "media."+string(f)), nil
+}
+
+// GetCachePath returns the path to the cache file
+func GetCachePath() (string, error) {
+	return pathFor(activeFormat())
 }
 
 // Load reads the cache from disk
 func Load() (*Cache, error) {
-	cachePath, err := GetCachePath()
-	if err != nil {
-		return nil, err
+	f := activeFormat()
+	cache, found, err := loadFormat(f)
+	if err != nil || found {
+		return cache, err
 	}
 
-	data, err := os.ReadFile(cachePath)
+	// Nothing in the chosen encoding yet: convert a cache stored in the
+	// other one, keeping its LastUpdated.
+	cache, found, err = loadFormat(otherFormat(f))
 	if err != nil {
-		if os.IsNotExist(err) {
-			return &Cache{Media: []plex.MediaItem{}, LastUpdated: time.Time{}}, nil
-		}
 		return nil, err
 	}
-
-	var cache Cache
-	if err := json.Unmarshal(data, &cache); err != nil {
-		return nil, err
+	if !found {
+		return &Cache{Media: []plex.MediaItem{}, LastUpdated: time.Time{}}, nil
 	}
-
-	return &cache, nil
+	if err := cache.write(f); err != nil {
+		return nil, fmt.Errorf("failed to convert cache to %s: %w", f, err)
+	}
+	return cache, nil
 }
 
-// Save writes the cache to disk
-func (c *Cache) Save() error {
-	cacheDir, err := config.GetCacheDir()
+func loadFormat(f Format) (c *Cache, found bool, err error) {
+	path, err := pathFor(f)
 	if err != nil {
-		return err
+		return nil, false, err
 	}
+	start := time.Now()
+	defer func() {
+		if found && err == nil {
+			logging.Debug("loaded cache", "path", path, "items", len(c.Media), "duration", time.Since(start).Round(time.Millisecond))
+		}
+	}()
 
-	// Create cache directory if it doesn't exist
-	if err := os.MkdirAll(cacheDir, 0755); err != nil {
-		return err
+	var cache Cache
+	if f == FormatJSON {
+		found, err := storage.ReadJSON(path, &cache)
+		return &cache, found, err
 	}
 
-	cachePath, err := GetCachePath()
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
 	if err != nil {
-		return err
+		return nil, false, err
 	}
+	defer file.Close()
+	if err := gob.NewDecoder(bufio.NewReader(file)).Decode(&cache); err != nil {
+		return nil, false, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return &cache, true, nil
+}
 
-	c.LastUpdated = time.Now()
-
-	// Compact JSON: the cache is machine-read only, and for large libraries
-	// indented output roughly doubles the file size and marshal time.
-	data, err := json.Marshal(c)
+// write stores the cache in encoding f and removes any copy in the other
+// encoding, so a stale file can't be picked up later.
+func (c *Cache) write(f Format) error {
+	path, err := pathFor(f)
 	if err != nil {
 		return err
 	}
+	start := time.Now()
 
-	// Write to a temp file and rename into place so an interrupted index run
-	// (crash, Ctrl-C, power loss) can never leave a truncated cache behind.
-	tmp, err := os.CreateTemp(cacheDir, ".media-*.json.tmp")
+	// Compact JSON: the cache is machine-read only, and for large libraries
+	// indented output roughly doubles the file size and marshal time. The
+	// write is atomic so an interrupted index run (crash, Ctrl-C, power loss)
+	// can never leave a truncated cache behind.
+	if f == FormatJSON {
+		err = storage.WriteJSON(path, c, false, 0644)
+	} else {
+		var buf bytes.Buffer
+		if err = gob.NewEncoder(&buf).Encode(c); err == nil {
+			err = storage.WriteAtomic(path, buf.Bytes(), 0644)
+		}
+	}
 	if err != nil {
 		return err
 	}
-	tmpPath := tmp.Name()
-	cleanup := func() {
-		_ = tmp.Close()
-		_ = os.Remove(tmpPath)
+	logging.Debug("saved cache", "path", path, "items", len(c.Media), "duration", time.Since(start).Round(time.Millisecond))
+
+	if other, err := pathFor(otherFormat(f)); err == nil {
+		if err := os.Remove(other); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
 	}
-	if _, err := tmp.Write(data); err != nil {
-		cleanup()
+	return nil
+}
+
+// Save writes the cache to disk
+func (c *Cache) Save() error {
+	c.LastUpdated = time.Now().UTC()
+	if err := c.write(activeFormat()); err != nil {
 		return err
 	}
-	if err := tmp.Close(); err != nil {
-		cleanup()
+
+	// Best-effort freshness sidecar so LAN peers can report cache size/age
+	// without parsing the (large) cache file. A failure here must not fail
+	// the save — the sidecar is an optimization, not the source of truth.
+	_ = SaveMeta(CacheMeta{Count: len(c.Media), LastUpdated: c.LastUpdated})
+	return nil
+}
+
+// ExportJSON writes the on-disk cache to w as JSON, whatever its encoding,
+// for sending to a LAN peer. A JSON cache is copied byte for byte; either
+// way LastUpdated is kept, so freshness comparisons stay meaningful.
+func ExportJSON(w io.Writer) error {
+	f := activeFormat()
+	if f == FormatJSON {
+		path, err := pathFor(f)
+		if err != nil {
+			return err
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = io.Copy(w, file)
 		return err
 	}
-	if err := os.Chmod(tmpPath, 0644); err != nil {
-		cleanup()
+
+	cache, found, err := loadFormat(f)
+	if err != nil {
 		return err
 	}
-	if err := os.Rename(tmpPath, cachePath); err != nil {
-		cleanup()
-		return err
+	if !found {
+		return os.ErrNotExist
 	}
+	return json.NewEncoder(w).Encode(cache)
+}
 
-	// Best-effort freshness sidecar so LAN peers can report cache size/age
-	// without parsing the (large) media.json. A failure here must not fail the
-	// save — the sidecar is an optimization, not the source of truth.
-	_ = SaveMeta(CacheMeta{Count: len(c.Media), LastUpdated: c.LastUpdated})
-	return nil
+// ImportJSON atomically replaces the on-disk cache with a JSON cache read
+// from r, such as one pulled from a LAN peer, keeping its LastUpdated.
+func ImportJSON(r io.Reader) error {
+	f := activeFormat()
+	if f == FormatJSON {
+		path, err := pathFor(f)
+		if err != nil {
+			return err
+		}
+		if err := storage.WriteAtomicFrom(path, r, 0644); err != nil {
+			return err
+		}
+		if other, err := pathFor(FormatGob); err == nil {
+			_ = os.Remove(other)
+		}
+		return nil
+	}
+
+	var cache Cache
+	if err := json.NewDecoder(r).Decode(&cache); err != nil {
+		return fmt.Errorf("failed to parse cache: %w", err)
+	}
+	return cache.write(f)
 }
 
-// CacheMeta is a tiny freshness summary written alongside media.json (meta.json)
+// CacheMeta is a tiny freshness summary written alongside the cache (meta.json)
 // so a process can report how big and how fresh its cache is without reading the
 // whole file. It powers the LAN cache-sync freshness comparison.
 type CacheMeta struct {
@@ -142,55 +274,23 @@ func LoadMeta() (CacheMeta, error) {
 	if err != nil {
 		return CacheMeta{}, err
 	}
-	data, err := os.ReadFile(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return CacheMeta{}, nil
-		}
-		return CacheMeta{}, err
-	}
 	var m CacheMeta
-	if err := json.Unmarshal(data, &m); err != nil {
+	if _, err := storage.ReadJSON(path, &m); err != nil {
 		return CacheMeta{}, err
 	}
 	return m, nil
 }
 
 // SaveMeta atomically writes the freshness sidecar. It's called by Save and by
-// the LAN sync pull (which writes media.json directly, bypassing Save) so the
-// sidecar always matches the cache on disk — preserving the original
+// the LAN sync pull (which writes the cache via ImportJSON, bypassing Save) so
+// the sidecar always matches the cache on disk — preserving the original
 // LastUpdated stamp rather than resetting it.
 func SaveMeta(m CacheMeta) error {
-	cacheDir, err := config.GetCacheDir()
-	if err != nil {
-		return err
-	}
-	if err := os.MkdirAll(cacheDir, 0755); err != nil {
-		return err
-	}
 	path, err := GetMetaPath()
 	if err != nil {
 		return err
 	}
-	data, err := json.Marshal(m)
-	if err != nil {
-		return err
-	}
-	tmp, err := os.CreateTemp(cacheDir, ".meta-*.json.tmp")
-	if err != nil {
-		return err
-	}
-	tmpPath := tmp.Name()
-	if _, err := tmp.Write(data); err != nil {
-		_ = tmp.Close()
-		_ = os.Remove(tmpPath)
-		return err
-	}
-	if err := tmp.Close(); err != nil {
-		_ = os.Remove(tmpPath)
-		return err
-	}
-	return os.Rename(tmpPath, path)
+	return storage.WriteJSON(path, m, false, 0644)
 }
 
 // IsStale checks if the cache is older than the given duration
@@ -222,6 +322,69 @@ func (c *Cache) ApplyOffsets(offsets map[string]int) bool {
 	return updated
 }
 
+// MarkWatched records the given items, matched by key and server, as watched
+// once more and clears their resume positions, mirroring what the server
+// does when they are marked as played. It returns how many were updated.
+// The caller saves the cache.
+func (c *Cache) MarkWatched(items []*plex.MediaItem) int {
+	marked := make(map[[2]string]bool, len(items))
+	for _, it := range items {
+		marked[[2]string{it.Key, it.ServerURL}] = true
+	}
+	now := time.Now().Unix()
+	updated := 0
+	for i := range c.Media {
+		m := &c.Media[i]
+		if marked[[2]string{m.Key, m.ServerURL}] {
+			m.ViewCount++
+			m.ViewOffset = 0
+			m.LastViewedAt = now
+			updated++
+		}
+	}
+	return updated
+}
+
+// ApplyWatchStates copies the server's watched state (see
+// plex.Client.GetWatchStates) into the cached items from serverName,
+// matched by key. Items the server didn't report are left alone. It returns
+// how many items changed. The caller saves the cache.
+func (c *Cache) ApplyWatchStates(serverName string, states map[string]plex.WatchState) int {
+	changed := 0
+	for i := range c.Media {
+		m := &c.Media[i]
+		if m.ServerName != serverName {
+			continue
+		}
+		s, ok := states[m.Key]
+		if !ok || (s.ViewCount == m.ViewCount && s.ViewOffset == m.ViewOffset && s.LastViewedAt == m.LastViewedAt) {
+			continue
+		}
+		m.ViewCount, m.ViewOffset, m.LastViewedAt = s.ViewCount, s.ViewOffset, s.LastViewedAt
+		changed++
+	}
+	return changed
+}
+
+// RemoveItems drops the given items from the cache, matching by key and
+// server, and returns how many were removed. The caller saves the cache.
+func (c *Cache) RemoveItems(items []*plex.MediaItem) int {
+	drop := make(map[[2]string]bool, len(items))
+	for _, it := range items {
+		drop[[2]string{it.Key, it.ServerURL}] = true
+	}
+	// A new slice, since callers often hold pointers into c.Media.
+	kept := make([]plex.MediaItem, 0, len(c.Media))
+	for _, m := range c.Media {
+		if !drop[[2]string{m.Key, m.ServerURL}] {
+			kept = append(kept, m)
+		}
+	}
+	removed := len(c.Media) - len(kept)
+	c.Media = kept
+	return removed
+}
+
 // GetMediaByTitle returns media items that match the given title
 func (c *Cache) GetMediaByTitle(title string) []plex.MediaItem {
 	var results []plex.MediaItem
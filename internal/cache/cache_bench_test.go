@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+	"github.com/sahilm/fuzzy"
+)
+
+// benchLibrarySize approximates a large real-world library; format and
+// lookup changes should be judged against this, not a toy cache.
+const benchLibrarySize = 100_000
+
+// benchFixture builds a deterministic library of n items: one movie for every
+// four episodes, with the metadata fields a reindex normally populates so
+// encode/decode cost is representative.
+func benchFixture(n int) *Cache {
+	media := make([]plex.MediaItem, n)
+	for i := range media {
+		item := plex.MediaItem{
+			Key:           fmt.Sprintf("/library/metadata/%d", 100000+i),
+			Summary:       "A long-form synopsis of roughly the length Plex returns for a typical title, used to keep the JSON payload realistic.",
+			Rating:        float64(i%100) / 10,
+			Duration:      (20 + i%100) * 60000,
+			FilePath:      fmt.Sprintf("/mnt/media/library/item-%d.mkv", i),
+			RclonePath:    fmt.Sprintf("remote:media/library/item-%d.mkv", i),
+			Thumb:         fmt.Sprintf("/library/metadata/%d/thumb/1700000000", 100000+i),
+			ServerName:    "Home",
+			ServerURL:     "http://192.168.1.10:32400",
+			ContentRating: "TV-14",
+			Studio:        "Studio",
+			Genre:         "Drama, Comedy",
+			Cast:          "Actor One, Actor Two, Actor Three",
+			AddedAt:       int64(1600000000 + i),
+		}
+		if i%5 == 0 {
+			item.Type = "movie"
+			item.Title = fmt.Sprintf("Movie Title %d", i)
+			item.Year = 1950 + i%75
+			item.Director = "Some Director"
+		} else {
+			item.Type = "episode"
+			item.Title = fmt.Sprintf("Episode Title %d", i)
+			item.ParentTitle = fmt.Sprintf("Show %d", i/100)
+			item.GrandTitle = fmt.Sprintf("Season %d", (i/10)%10+1)
+			item.ParentIndex = int64((i/10)%10 + 1)
+			item.Index = int64(i%10 + 1)
+		}
+		media[i] = item
+	}
+	return &Cache{Media: media, LastUpdated: time.Unix(1700000000, 0)}
+}
+
+// useTempConfigDir points the config/cache directory at a per-benchmark temp
+// dir on every platform GetConfigDir supports.
+func useTempConfigDir(b *testing.B) {
+	b.Helper()
+	dir := b.TempDir()
+	b.Setenv("XDG_CONFIG_HOME", dir)
+	b.Setenv("HOME", dir)
+	b.Setenv("APPDATA", dir)
+}
+
+// benchFormats runs fn once per cache encoding, as a sub-benchmark named
+// after it, so the encodings can be compared directly.
+func benchFormats(b *testing.B, fn func(b *testing.B)) {
+	defer func() { format = "" }()
+	for _, f := range []Format{FormatJSON, FormatGob} {
+		b.Run(string(f), func(b *testing.B) {
+			SetFormat(string(f))
+			useTempConfigDir(b)
+			fn(b)
+		})
+	}
+}
+
+func BenchmarkCacheSave(b *testing.B) {
+	benchFormats(b, func(b *testing.B) {
+		c := benchFixture(benchLibrarySize)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := c.Save(); err != nil {
+				b.Fatalf("Save: %v", err)
+			}
+		}
+	})
+}
+
+func BenchmarkCacheLoad(b *testing.B) {
+	benchFormats(b, func(b *testing.B) {
+		if err := benchFixture(benchLibrarySize).Save(); err != nil {
+			b.Fatalf("Save: %v", err)
+		}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			c, err := Load()
+			if err != nil {
+				b.Fatalf("Load: %v", err)
+			}
+			if len(c.Media) != benchLibrarySize {
+				b.Fatalf("loaded %d items, want %d", len(c.Media), benchLibrarySize)
+			}
+		}
+	})
+}
+
+func BenchmarkFormatForFzf(b *testing.B) {
+	c := benchFixture(benchLibrarySize)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = c.FormatForFzf()
+	}
+}
+
+// BenchmarkFuzzyFilter mirrors the browser's search path: fuzzy-match a short
+// query against every formatted title.
+func BenchmarkFuzzyFilter(b *testing.B) {
+	titles := benchFixture(benchLibrarySize).FormatForFzf()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = fuzzy.Find("show 42 ep", titles)
+	}
+}
+
+func BenchmarkGetMediaByTitle(b *testing.B) {
+	c := benchFixture(benchLibrarySize)
+	// Worst case: the match is the last item.
+	want := c.Media[len(c.Media)-1].Title
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if got := c.GetMediaByTitle(want); len(got) != 1 {
+			b.Fatalf("GetMediaByTitle found %d items, want 1", len(got))
+		}
+	}
+}
+
+func BenchmarkGetMediaByFormattedTitle(b *testing.B) {
+	c := benchFixture(benchLibrarySize)
+	want := c.Media[len(c.Media)-1].FormatMediaTitle()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.GetMediaByFormattedTitle(want); err != nil {
+			b.Fatalf("GetMediaByFormattedTitle: %v", err)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -34,6 +35,73 @@ func TestApplyOffsets(t *testing.T) {
 	}
 }
 
+func TestRemoveItems(t *testing.T) {
+	c := &Cache{Media: []plex.MediaItem{
+		{Key: "a", ServerURL: "http://one"},
+		{Key: "a", ServerURL: "http://two"},
+		{Key: "b", ServerURL: "http://one"},
+	}}
+	target := &c.Media[0]
+
+	if n := c.RemoveItems([]*plex.MediaItem{target, {Key: "missing"}}); n != 1 {
+		t.Fatalf("RemoveItems removed %d, want 1", n)
+	}
+	if len(c.Media) != 2 || c.Media[0].ServerURL != "http://two" || c.Media[1].Key != "b" {
+		t.Errorf("unexpected remaining media: %+v", c.Media)
+	}
+	// Pointers into the old slice must keep pointing at the item they did.
+	if target.Key != "a" || target.ServerURL != "http://one" {
+		t.Errorf("caller's item was overwritten: %+v", *target)
+	}
+}
+
+func TestMarkWatched(t *testing.T) {
+	c := &Cache{Media: []plex.MediaItem{
+		{Key: "a", ServerURL: "http://one", ViewOffset: 60000},
+		{Key: "a", ServerURL: "http://two", ViewOffset: 60000},
+		{Key: "b", ServerURL: "http://one", ViewCount: 2},
+	}}
+
+	if n := c.MarkWatched([]*plex.MediaItem{{Key: "a", ServerURL: "http://one"}, {Key: "b", ServerURL: "http://one"}}); n != 2 {
+		t.Fatalf("MarkWatched updated %d, want 2", n)
+	}
+	if m := c.Media[0]; m.ViewCount != 1 || m.ViewOffset != 0 || m.LastViewedAt == 0 {
+		t.Errorf("marked item = %+v, want watched once with no offset", m)
+	}
+	if m := c.Media[1]; m.ViewCount != 0 || m.ViewOffset != 60000 {
+		t.Errorf("same key on another server changed: %+v", m)
+	}
+	if c.Media[2].ViewCount != 3 {
+		t.Errorf("ViewCount = %d, want 3", c.Media[2].ViewCount)
+	}
+}
+
+func TestApplyWatchStates(t *testing.T) {
+	c := &Cache{Media: []plex.MediaItem{
+		{Key: "a", ServerName: "One", ViewOffset: 60000},
+		{Key: "a", ServerName: "Two", ViewOffset: 60000},
+		{Key: "b", ServerName: "One", ViewCount: 1, LastViewedAt: 100},
+		{Key: "c", ServerName: "One", ViewCount: 4},
+	}}
+	states := map[string]plex.WatchState{
+		"a": {ViewCount: 1, LastViewedAt: 200},
+		"b": {ViewCount: 1, LastViewedAt: 100},
+	}
+
+	if n := c.ApplyWatchStates("One", states); n != 1 {
+		t.Fatalf("ApplyWatchStates changed %d, want 1", n)
+	}
+	if m := c.Media[0]; m.ViewCount != 1 || m.ViewOffset != 0 || m.LastViewedAt != 200 {
+		t.Errorf("synced item = %+v", m)
+	}
+	if m := c.Media[1]; m.ViewOffset != 60000 || m.ViewCount != 0 {
+		t.Errorf("same key on another server changed: %+v", m)
+	}
+	if m := c.Media[3]; m.ViewCount != 4 {
+		t.Errorf("item the server didn't report changed: %+v", m)
+	}
+}
+
 func TestIsStale(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -235,3 +303,83 @@ func TestEmptyCache(t *testing.T) {
 		t.Errorf("GetMediaByTitle() = %d results, want 0", len(results))
 	}
 }
+
+func TestFormatMigration(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("APPDATA", dir)
+	defer func() { format = "" }()
+
+	c := &Cache{Media: []plex.MediaItem{{Key: "/library/1", Title: "Heat", Year: 1995, Type: "movie"}}}
+	if err := c.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	jsonPath, _ := pathFor(FormatJSON)
+	gobPath, _ := pathFor(FormatGob)
+
+	// Switching to gob converts the JSON cache on the next load.
+	SetFormat("gob")
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load (gob): %v", err)
+	}
+	if len(loaded.Media) != 1 || loaded.Media[0].Title != "Heat" || !loaded.LastUpdated.Equal(c.LastUpdated) {
+		t.Errorf("converted cache = %+v, want the saved one", loaded)
+	}
+	if _, err := os.Stat(gobPath); err != nil {
+		t.Errorf("gob cache not written: %v", err)
+	}
+	if _, err := os.Stat(jsonPath); !os.IsNotExist(err) {
+		t.Error("JSON cache should be removed after conversion")
+	}
+
+	// With no format set, the encoding on disk is kept.
+	format = ""
+	if path, _ := GetCachePath(); path != gobPath {
+		t.Errorf("GetCachePath = %s, want %s", path, gobPath)
+	}
+
+	// And back to JSON.
+	SetFormat("json")
+	if loaded, err = Load(); err != nil || len(loaded.Media) != 1 {
+		t.Fatalf("Load (json) = %+v, %v", loaded, err)
+	}
+	if _, err := os.Stat(gobPath); !os.IsNotExist(err) {
+		t.Error("gob cache should be removed after converting back")
+	}
+}
+
+func TestExportImportJSON(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("APPDATA", dir)
+	defer func() { format = "" }()
+
+	SetFormat("gob")
+	c := &Cache{Media: []plex.MediaItem{{Key: "/library/1", Title: "Heat", Type: "movie"}}}
+	if err := c.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// Peers always exchange JSON.
+	var buf bytes.Buffer
+	if err := ExportJSON(&buf); err != nil {
+		t.Fatalf("ExportJSON: %v", err)
+	}
+	var exported Cache
+	if err := json.Unmarshal(buf.Bytes(), &exported); err != nil || len(exported.Media) != 1 {
+		t.Fatalf("exported JSON = %q, %v", buf.String(), err)
+	}
+
+	exported.Media[0].Title = "Heat (Director's Cut)"
+	data, _ := json.Marshal(exported)
+	if err := ImportJSON(bytes.NewReader(data)); err != nil {
+		t.Fatalf("ImportJSON: %v", err)
+	}
+	loaded, err := Load()
+	if err != nil || loaded.Media[0].Title != "Heat (Director's Cut)" || !loaded.LastUpdated.Equal(c.LastUpdated) {
+		t.Errorf("imported cache = %+v, %v", loaded, err)
+	}
+}
@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"cmp"
+	"strings"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+// DedupePolicy decides which copy to keep when several servers have the same
+// item.
+type DedupePolicy string
+
+const (
+	// DedupeLocal keeps the copy on a server in the local network, then the
+	// best quality.
+	DedupeLocal DedupePolicy = "local"
+	// DedupeQuality keeps the best-quality copy, then the local one.
+	DedupeQuality DedupePolicy = "quality"
+)
+
+// Dedupe removes items that another server also has, keeping one copy of
+// each according to policy, and returns the removed items. Items are the same
+// when they share a Plex GUID (see sharedGUID); copies on the kept copy's own
+// server are left alone. The remaining items keep their order.
+func (c *Cache) Dedupe(policy DedupePolicy) []plex.MediaItem {
+	local := map[string]bool{}
+	isLocal := func(item *plex.MediaItem) bool {
+		l, ok := local[item.ServerURL]
+		if !ok {
+			l = plex.IsLocalURL(item.ServerURL)
+			local[item.ServerURL] = l
+		}
+		return l
+	}
+	better := func(a, b *plex.MediaItem) bool {
+		la, lb := isLocal(a), isLocal(b)
+		if policy == DedupeLocal && la != lb {
+			return la
+		}
+		if q := compareQuality(a, b); q != 0 {
+			return q > 0
+		}
+		return la && !lb
+	}
+
+	best := map[string]int{} // GUID -> index of the copy to keep
+	for i := range c.Media {
+		guid := sharedGUID(&c.Media[i])
+		if guid == "" {
+			continue
+		}
+		if j, ok := best[guid]; !ok || better(&c.Media[i], &c.Media[j]) {
+			best[guid] = i
+		}
+	}
+
+	var removed []plex.MediaItem
+	kept := make([]plex.MediaItem, 0, len(c.Media))
+	for i, item := range c.Media {
+		j, ok := best[sharedGUID(&item)]
+		if ok && j != i && item.ServerURL != c.Media[j].ServerURL {
+			removed = append(removed, item)
+			continue
+		}
+		kept = append(kept, item)
+	}
+	c.Media = kept
+	return removed
+}
+
+// sharedGUID returns the Plex GUID that identifies item across servers, or
+// "" if it has none. GUIDs that only mean something on one server (local
+// media, unmatched items) don't count, and neither do IMDb, TMDB or TVDB IDs:
+// a copy may carry only some of them, so matching on one could merge
+// different items. Items without a GUID are left alone.
+func sharedGUID(item *plex.MediaItem) string {
+	g := item.GUID
+	if g == "" || strings.HasPrefix(g, "local://") || strings.Contains(g, "agents.none") {
+		return ""
+	}
+	return g
+}
+
+// resolutionRank orders Plex's videoResolution values.
+var resolutionRank = map[string]int{"sd": 1, "480": 1, "576": 1, "720": 2, "1080": 3, "4k": 4, "8k": 5}
+
+// compareQuality compares two copies by resolution, then HDR, bitrate and
+// file size, returning a positive number if a is better.
+func compareQuality(a, b *plex.MediaItem) int {
+	hdr := func(item *plex.MediaItem) int {
+		if item.HDR != "" {
+			return 1
+		}
+		return 0
+	}
+	return cmp.Or(
+		cmp.Compare(resolutionRank[strings.ToLower(a.VideoResolution)], resolutionRank[strings.ToLower(b.VideoResolution)]),
+		cmp.Compare(hdr(a), hdr(b)),
+		cmp.Compare(a.Bitrate, b.Bitrate),
+		cmp.Compare(a.Size, b.Size),
+	)
+}
@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+func TestDedupe(t *testing.T) {
+	const (
+		home   = "http://192.168.1.10:32400"
+		friend = "https://plex.example.com"
+	)
+	media := func() []plex.MediaItem {
+		return []plex.MediaItem{
+			{Key: "1", Title: "Heat", GUID: "plex://movie/heat", ServerURL: home, VideoResolution: "1080"},
+			{Key: "2", Title: "Heat", GUID: "plex://movie/heat", ServerURL: friend, VideoResolution: "4k"},
+			{Key: "3", Title: "Home Video", GUID: "local://3", ServerURL: home},
+			{Key: "3", Title: "Home Video", GUID: "local://3", ServerURL: friend},
+			{Key: "4", Title: "Ronin", ServerURL: home},
+			{Key: "5", Title: "Ronin", ServerURL: friend},
+			// Without a Plex GUID, a shared IMDb ID isn't enough.
+			{Key: "8", Title: "Thief", Type: "movie", GUID: "local://8", IMDbID: "tt0083190", ServerURL: home},
+			{Key: "9", Title: "Thief", Type: "movie", IMDbID: "tt0083190", ServerURL: friend, VideoResolution: "4k"},
+			// Two copies on one server aren't cross-server duplicates.
+			{Key: "6", Title: "Alien", GUID: "plex://movie/alien", ServerURL: friend},
+			{Key: "7", Title: "Alien", GUID: "plex://movie/alien", ServerURL: friend},
+		}
+	}
+
+	c := &Cache{Media: media()}
+	removed := c.Dedupe(DedupeLocal)
+	if len(removed) != 1 || removed[0].Key != "2" {
+		t.Errorf("local policy removed %+v, want the friend's 4K Heat", removed)
+	}
+	if len(c.Media) != 9 || c.Media[0].Key != "1" {
+		t.Errorf("local policy kept %+v", c.Media)
+	}
+
+	c = &Cache{Media: media()}
+	removed = c.Dedupe(DedupeQuality)
+	if len(removed) != 1 || removed[0].Key != "1" {
+		t.Errorf("quality policy removed %+v, want the home copy of Heat", removed)
+	}
+	if len(c.Media) != 9 || c.Media[0].Key != "2" {
+		t.Errorf("quality policy kept %+v", c.Media)
+	}
+}
+
+func TestCompareQuality(t *testing.T) {
+	sd := &plex.MediaItem{VideoResolution: "sd", Bitrate: 9000}
+	hd := &plex.MediaItem{VideoResolution: "1080", Bitrate: 8000}
+	hdr := &plex.MediaItem{VideoResolution: "1080", Bitrate: 6000, HDR: "HDR10"}
+	if compareQuality(hd, sd) <= 0 || compareQuality(hdr, hd) <= 0 || compareQuality(hd, hd) != 0 {
+		t.Error("want resolution, then HDR, to outrank bitrate")
+	}
+	uhd := &plex.MediaItem{VideoResolution: "4k", HDR: "HDR10"}
+	if compareQuality(&plex.MediaItem{VideoResolution: "8K"}, uhd) <= 0 {
+		t.Error("want 8K to outrank 4K")
+	}
+}
@@ -0,0 +1,128 @@
+// Package calendar lays cached episodes out by the date they first aired, for
+// the 'goplexcli calendar' month view. It works entirely from the media
+// cache: Plex's originallyAvailableAt is indexed as MediaItem.OriginallyAired,
+// and episodes the server has announced but not yet aired carry a future date.
+package calendar
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+// dateLayout is how Plex formats originallyAvailableAt.
+const dateLayout = "2006-01-02"
+
+// Entry is one episode and the day it aired (or will air), at midnight UTC.
+type Entry struct {
+	Date time.Time
+	Item *plex.MediaItem
+}
+
+// Episodes returns the episodes with a known air date whose show passes
+// follow, oldest first. A nil follow keeps every show.
+func Episodes(media []plex.MediaItem, follow func(show string) bool) []Entry {
+	var entries []Entry
+	for i := range media {
+		item := &media[i]
+		if item.Type != "episode" || item.OriginallyAired == "" {
+			continue
+		}
+		if follow != nil && !follow(item.ParentTitle) {
+			continue
+		}
+		date, err := time.Parse(dateLayout, item.OriginallyAired)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, Entry{Date: date, Item: item})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Date.Equal(entries[j].Date) {
+			return entries[i].Date.Before(entries[j].Date)
+		}
+		a, b := entries[i].Item, entries[j].Item
+		if a.ParentTitle != b.ParentTitle {
+			return a.ParentTitle < b.ParentTitle
+		}
+		if a.ParentIndex != b.ParentIndex {
+			return a.ParentIndex < b.ParentIndex
+		}
+		return a.Index < b.Index
+	})
+	return entries
+}
+
+// InMonth returns the entries dated in the given month.
+func InMonth(entries []Entry, year int, month time.Month) []Entry {
+	var out []Entry
+	for _, e := range entries {
+		if e.Date.Year() == year && e.Date.Month() == month {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Upcoming returns the entries dated after today, soonest first.
+func Upcoming(entries []Entry, today time.Time) []Entry {
+	day := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	var out []Entry
+	for _, e := range entries {
+		if e.Date.After(day) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// ParseMonth parses a --month value such as "2024-06".
+func ParseMonth(s string) (int, time.Month, error) {
+	t, err := time.Parse("2006-01", s)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid month %q (want YYYY-MM)", s)
+	}
+	return t.Year(), t.Month(), nil
+}
+
+// RenderMonth writes a Sunday-first month grid like cal(1). Days with at
+// least one entry are marked with "*", and today, if it has none, with "<".
+func RenderMonth(w io.Writer, year int, month time.Month, entries []Entry, today time.Time) {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	days := first.AddDate(0, 1, -1).Day()
+
+	marked := map[int]bool{}
+	for _, e := range InMonth(entries, year, month) {
+		marked[e.Date.Day()] = true
+	}
+	todayDay := 0
+	if today.Year() == year && today.Month() == month {
+		todayDay = today.Day()
+	}
+
+	const width = 7 * 4
+	title := first.Format("January 2006")
+	fmt.Fprintf(w, "%*s\n", (width+len(title))/2, title)
+	fmt.Fprintln(w, " Su  Mo  Tu  We  Th  Fr  Sa")
+
+	var line strings.Builder
+	line.WriteString(strings.Repeat("    ", int(first.Weekday())))
+	for d := 1; d <= days; d++ {
+		mark := " "
+		switch {
+		case marked[d]:
+			mark = "*"
+		case d == todayDay:
+			mark = "<"
+		}
+		fmt.Fprintf(&line, "%3d%s", d, mark)
+		if (int(first.Weekday())+d)%7 == 0 || d == days {
+			fmt.Fprintln(w, strings.TrimRight(line.String(), " "))
+			line.Reset()
+		}
+	}
+}
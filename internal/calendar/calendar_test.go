@@ -0,0 +1,77 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+func testMedia() []plex.MediaItem {
+	return []plex.MediaItem{
+		{Type: "episode", ParentTitle: "B Show", ParentIndex: 1, Index: 2, OriginallyAired: "2024-06-12"},
+		{Type: "episode", ParentTitle: "A Show", ParentIndex: 3, Index: 1, OriginallyAired: "2024-06-12"},
+		{Type: "episode", ParentTitle: "A Show", ParentIndex: 3, Index: 2, OriginallyAired: "2024-07-01"},
+		{Type: "episode", ParentTitle: "A Show", ParentIndex: 3, Index: 3},                          // no date
+		{Type: "episode", ParentTitle: "A Show", ParentIndex: 3, Index: 4, OriginallyAired: "soon"}, // unparseable
+		{Type: "movie", Title: "Film", OriginallyAired: "2024-06-01"},
+	}
+}
+
+func TestEpisodes(t *testing.T) {
+	entries := Episodes(testMedia(), nil)
+	if len(entries) != 3 {
+		t.Fatalf("Episodes = %d entries, want 3", len(entries))
+	}
+	// Same day sorts by show name.
+	if entries[0].Item.ParentTitle != "A Show" || entries[1].Item.ParentTitle != "B Show" {
+		t.Errorf("same-day entries out of order: %s, %s", entries[0].Item.ParentTitle, entries[1].Item.ParentTitle)
+	}
+
+	followed := Episodes(testMedia(), func(show string) bool { return show == "B Show" })
+	if len(followed) != 1 || followed[0].Item.ParentTitle != "B Show" {
+		t.Errorf("follow filter kept %v", followed)
+	}
+
+	if got := InMonth(entries, 2024, time.June); len(got) != 2 {
+		t.Errorf("InMonth(June) = %d entries, want 2", len(got))
+	}
+	if got := Upcoming(entries, time.Date(2024, 6, 12, 20, 0, 0, 0, time.Local)); len(got) != 1 || got[0].Item.Index != 2 {
+		t.Errorf("Upcoming after Jun 12 = %v", got)
+	}
+}
+
+func TestParseMonth(t *testing.T) {
+	y, m, err := ParseMonth("2024-06")
+	if err != nil || y != 2024 || m != time.June {
+		t.Errorf("ParseMonth = %d, %v, %v", y, m, err)
+	}
+	if _, _, err := ParseMonth("June"); err == nil {
+		t.Error("ParseMonth should reject a non YYYY-MM value")
+	}
+}
+
+func TestRenderMonth(t *testing.T) {
+	var buf bytes.Buffer
+	RenderMonth(&buf, 2024, time.June, Episodes(testMedia(), nil), time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC))
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+
+	if strings.TrimSpace(lines[0]) != "June 2024" {
+		t.Errorf("title = %q", lines[0])
+	}
+	// June 1st 2024 is a Saturday: alone in the last column.
+	if lines[2] != strings.Repeat(" ", 24)+"  1" {
+		t.Errorf("first week = %q", lines[2])
+	}
+	if !strings.Contains(lines[3], "  3<") {
+		t.Errorf("today should be marked in %q", lines[3])
+	}
+	if !strings.Contains(lines[4], " 12*") {
+		t.Errorf("air date should be marked in %q", lines[4])
+	}
+	if last := lines[len(lines)-1]; !strings.HasSuffix(last, " 30") {
+		t.Errorf("last week = %q", last)
+	}
+}
@@ -5,95 +5,340 @@
 package config
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strings"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/storage"
 )
 
 // PlexServer represents a configured Plex server.
 // Multiple servers can be configured, with each individually enabled or disabled.
 type PlexServer struct {
 	// Name is a human-readable identifier for the server
-	Name string `json:"name"`
+	Name string `json:"name" toml:"name" yaml:"name"`
 	// URL is the base URL of the Plex server (e.g., "http://192.168.1.100:32400")
-	URL string `json:"url"`
+	URL string `json:"url" toml:"url" yaml:"url"`
 	// Token is this server's access token from plex.tv. Shared (non-owner)
 	// accounts cannot use their account token against a server — the server
 	// returns 401 — so the per-server token must be used when present. Empty
 	// for configs saved before this field existed; callers fall back to the
 	// account-wide PlexToken (see Config.TokenForServer).
-	Token string `json:"token,omitempty"`
+	Token string `json:"token,omitempty" toml:"token,omitempty" yaml:"token,omitempty"`
 	// Enabled determines whether this server is included when indexing media
-	Enabled bool `json:"enabled"`
+	Enabled bool `json:"enabled" toml:"enabled" yaml:"enabled"`
+	// Connections lists every address plex.tv advertises for the server,
+	// best first as ranked by probing at login. URL is normally the first;
+	// the others are fallbacks for when it can't be reached.
+	Connections []string `json:"connections,omitempty" toml:"connections,omitempty" yaml:"connections,omitempty"`
+	// Owned records whether the account owns the server, as opposed to a
+	// friend sharing it, as reported by plex.tv at login. Nil for servers
+	// saved before this field existed, which are treated as owned.
+	Owned *bool `json:"owned,omitempty" toml:"owned,omitempty" yaml:"owned,omitempty"`
+}
+
+// IsShared reports whether the server is known to belong to someone else.
+func (s PlexServer) IsShared() bool {
+	return s.Owned != nil && !*s.Owned
+}
+
+// HasURL reports whether u is the server's URL or one of its connections,
+// ignoring trailing slashes.
+func (s PlexServer) HasURL(u string) bool {
+	u = strings.TrimRight(u, "/")
+	if strings.TrimRight(s.URL, "/") == u {
+		return true
+	}
+	for _, c := range s.Connections {
+		if strings.TrimRight(c, "/") == u {
+			return true
+		}
+	}
+	return false
 }
 
 // Config holds all user configuration for goplexcli.
 // It supports both legacy single-server configurations and newer multi-server setups.
 type Config struct {
 	// Legacy single-server fields (maintained for backward compatibility)
-	PlexURL      string `json:"plex_url,omitempty"`
-	PlexToken    string `json:"plex_token"`
-	PlexUsername string `json:"plex_username,omitempty"`
+	PlexURL      string `json:"plex_url,omitempty" toml:"plex_url,omitempty" yaml:"plex_url,omitempty"`
+	PlexToken    string `json:"plex_token" toml:"plex_token" yaml:"plex_token"`
+	PlexUsername string `json:"plex_username,omitempty" toml:"plex_username,omitempty" yaml:"plex_username,omitempty"`
+
+	// HomeUser is the Plex Home user switched to with 'goplexcli home
+	// switch'; PlexToken and the server tokens are then that user's, so
+	// their watch history stays separate. AdminToken keeps the Home admin's
+	// account token for switching again. Both are empty for the account
+	// that logged in.
+	HomeUser   string `json:"home_user,omitempty" toml:"home_user,omitempty" yaml:"home_user,omitempty"`
+	AdminToken string `json:"admin_token,omitempty" toml:"admin_token,omitempty" yaml:"admin_token,omitempty"`
 
 	// Servers holds multi-server configuration. Each server can be independently
 	// enabled or disabled for indexing.
-	Servers []PlexServer `json:"servers,omitempty"`
+	Servers []PlexServer `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty"`
 
 	// Tool paths allow overriding the default paths to external binaries.
 	// If empty, the system PATH is searched.
-	MPVPath    string `json:"mpv_path,omitempty"`
-	RclonePath string `json:"rclone_path,omitempty"`
-	FzfPath    string `json:"fzf_path,omitempty"`
+	MPVPath    string `json:"mpv_path,omitempty" toml:"mpv_path,omitempty" yaml:"mpv_path,omitempty"`
+	VLCPath    string `json:"vlc_path,omitempty" toml:"vlc_path,omitempty" yaml:"vlc_path,omitempty"`
+	IINAPath   string `json:"iina_path,omitempty" toml:"iina_path,omitempty" yaml:"iina_path,omitempty"`
+	RclonePath string `json:"rclone_path,omitempty" toml:"rclone_path,omitempty" yaml:"rclone_path,omitempty"`
+	FzfPath    string `json:"fzf_path,omitempty" toml:"fzf_path,omitempty" yaml:"fzf_path,omitempty"`
+
+	// Player selects the video player used for watching: "mpv" (the
+	// default), "vlc", "iina" (macOS), or the name of one of Players.
+	// Progress tracking works with the built-in players and with custom
+	// players that set supports_ipc.
+	Player string `json:"player,omitempty" toml:"player,omitempty" yaml:"player,omitempty"`
+
+	// Players defines extra external players (smplayer, celluloid, a
+	// custom wrapper script) that Player can name.
+	Players []PlayerDefinition `json:"players,omitempty" toml:"players,omitempty" yaml:"players,omitempty"`
+
+	// Keybindings overrides keys in the built-in TUI browser, mapping an
+	// action ("up", "down", "search", "select", "toggle_poster", "quit" or
+	// "back", "clear_search") to comma-separated keys, e.g. {"select": "l",
+	// "back": "h"}. See ui.SetKeybindings.
+	Keybindings map[string]string `json:"keybindings,omitempty" toml:"keybindings,omitempty" yaml:"keybindings,omitempty"`
+
+	// Theme names the color theme: "dark" (the default), "light",
+	// "solarized", "dracula", or one defined in Themes.
+	Theme string `json:"theme,omitempty" toml:"theme,omitempty" yaml:"theme,omitempty"`
+	// Themes defines custom themes by name, each mapping color roles such
+	// as "accent" and "error" to colors and optionally naming a built-in
+	// "base" theme for the rest. See ui.SetTheme.
+	Themes map[string]map[string]string `json:"themes,omitempty" toml:"themes,omitempty" yaml:"themes,omitempty"`
+
+	// PreviewImages chooses how posters are drawn in the fzf preview:
+	// "auto" (the default) detects the terminal's graphics protocol, or
+	// "kitty", "iterm2", "sixel", "symbols" (chafa character art) or "off".
+	PreviewImages string `json:"preview_images,omitempty" toml:"preview_images,omitempty" yaml:"preview_images,omitempty"`
+
+	// FFmpegPath points at ffmpeg, used by the stream server's HLS endpoint.
+	// If empty, PATH is searched; without ffmpeg the endpoint is disabled.
+	FFmpegPath string `json:"ffmpeg_path,omitempty" toml:"ffmpeg_path,omitempty" yaml:"ffmpeg_path,omitempty"`
+
+	// PlaybackPresets define extra mpv options selectable by name with
+	// --preset or "Watch with Preset...", e.g. {"quiet": ["--volume=60"]}.
+	// They add to the built-in presets (night, normalize, stereo) and
+	// override one with the same name.
+	PlaybackPresets map[string][]string `json:"playback_presets,omitempty" toml:"playback_presets,omitempty" yaml:"playback_presets,omitempty"`
+
+	// SkipIntros makes playback seek past intros and credits Plex has
+	// detected. When false, mpv shows a hint and S skips instead.
+	SkipIntros bool `json:"skip_intros,omitempty" toml:"skip_intros,omitempty" yaml:"skip_intros,omitempty"`
+
+	// AutoplayNext queues a show's next episode into the running mpv or
+	// IINA playlist once the current one is watched. When false, the next
+	// episode is offered with a countdown after the player exits.
+	AutoplayNext bool `json:"autoplay_next,omitempty" toml:"autoplay_next,omitempty" yaml:"autoplay_next,omitempty"`
+
+	// Fullscreen, AlwaysOnTop and Volume set how the player window starts:
+	// fullscreen, above other windows, and at a volume from 1 to 130 (0
+	// keeps the player's own). All apply to mpv and IINA; VLC takes the
+	// first two.
+	Fullscreen  bool `json:"fullscreen,omitempty" toml:"fullscreen,omitempty" yaml:"fullscreen,omitempty"`
+	AlwaysOnTop bool `json:"always_on_top,omitempty" toml:"always_on_top,omitempty" yaml:"always_on_top,omitempty"`
+	Volume      int  `json:"volume,omitempty" toml:"volume,omitzero" yaml:"volume,omitempty"`
+
+	// ScreenshotDir is where Ctrl+S in mpv or IINA saves a still of the
+	// current frame. A leading "~" is expanded. Empty leaves the key unbound;
+	// --screenshot-dir sets it per run.
+	ScreenshotDir string `json:"screenshot_dir,omitempty" toml:"screenshot_dir,omitempty" yaml:"screenshot_dir,omitempty"`
+
+	// MPVProfile names a profile from mpv.conf (a [section]) to apply
+	// when playing with mpv or IINA.
+	MPVProfile string `json:"mpv_profile,omitempty" toml:"mpv_profile,omitempty" yaml:"mpv_profile,omitempty"`
+
+	// HLSTranscode makes the HLS endpoint re-encode video to H.264 rather
+	// than copy it, for sources (e.g. HEVC) a browser or TV can't decode.
+	HLSTranscode bool `json:"hls_transcode,omitempty" toml:"hls_transcode,omitempty" yaml:"hls_transcode,omitempty"`
+
+	// StreamAuth protects the stream server's web UI, /streams and HLS
+	// endpoints with an access token, printed when the server starts. It is
+	// StreamToken if set, otherwise a fresh random one each time.
+	StreamAuth bool `json:"stream_auth,omitempty" toml:"stream_auth,omitempty" yaml:"stream_auth,omitempty"`
+	// StreamToken is a fixed access token for the stream server. Setting it
+	// implies StreamAuth, and 'goplexcli stream' sends it to other servers.
+	StreamToken string `json:"stream_token,omitempty" toml:"stream_token,omitempty" yaml:"stream_token,omitempty"`
+
+	// StreamProxy relays stream bytes from Plex through the stream server
+	// instead of publishing Plex URLs, so consumers never see the Plex token.
+	StreamProxy bool `json:"stream_proxy,omitempty" toml:"stream_proxy,omitempty" yaml:"stream_proxy,omitempty"`
+
+	// RemoteControl serves a remote-control page on the stream server's port
+	// while mpv or IINA plays, so a phone on the LAN can pause, seek and skip.
+	// It uses the stream server's token and TLS settings.
+	RemoteControl bool `json:"remote_control,omitempty" toml:"remote_control,omitempty" yaml:"remote_control,omitempty"`
+
+	// StreamTLS serves the stream server over HTTPS. StreamCertFile and
+	// StreamKeyFile name a PEM certificate and key to use (setting them
+	// implies StreamTLS); without them a self-signed certificate is generated
+	// each time the server starts.
+	StreamTLS      bool   `json:"stream_tls,omitempty" toml:"stream_tls,omitempty" yaml:"stream_tls,omitempty"`
+	StreamCertFile string `json:"stream_cert_file,omitempty" toml:"stream_cert_file,omitempty" yaml:"stream_cert_file,omitempty"`
+	StreamKeyFile  string `json:"stream_key_file,omitempty" toml:"stream_key_file,omitempty" yaml:"stream_key_file,omitempty"`
+
+	// Timezone is the IANA time zone (e.g. "Europe/Berlin") dates and times
+	// are shown in. If empty, the system's local zone is used. Stored
+	// timestamps are always UTC, so this only affects display.
+	Timezone string `json:"timezone,omitempty" toml:"timezone,omitempty" yaml:"timezone,omitempty"`
+
+	// HTTPTimeout bounds each request to a Plex server, in seconds, retries
+	// included. 0 uses the default of 60.
+	HTTPTimeout int `json:"http_timeout,omitempty" toml:"http_timeout,omitzero" yaml:"http_timeout,omitempty"`
+	// HTTPRetries is how often a request that fails with a server error, a
+	// rate limit or a network error is retried, with exponential backoff.
+	// 0 uses the default of 3; a negative value disables retries.
+	HTTPRetries int `json:"http_retries,omitempty" toml:"http_retries,omitzero" yaml:"http_retries,omitempty"`
+
+	// DeviceName is how this install appears in the Plex devices dashboard
+	// and in other apps' now-playing lists. Blank uses the hostname.
+	DeviceName string `json:"device_name,omitempty" toml:"device_name,omitempty" yaml:"device_name,omitempty"`
+
+	// CacheFormat is the media cache's on-disk encoding: "json" (default) or
+	// "gob", which loads much faster for very large libraries. An existing
+	// cache is converted on its next load.
+	CacheFormat string `json:"cache_format,omitempty" toml:"cache_format,omitempty" yaml:"cache_format,omitempty"`
+
+	// StateBackend is where the queue, history, favorites and the other
+	// small state documents live: "json" (default), a file each, or
+	// "sqlite", one state.db database. Documents not yet in the database
+	// are read from their JSON files, so switching keeps existing state.
+	StateBackend string `json:"state_backend,omitempty" toml:"state_backend,omitempty" yaml:"state_backend,omitempty"`
+
+	// IncludeLibraries, when set, limits indexing to these library
+	// sections; ExcludeLibraries skips sections, e.g. "Home Videos". Both
+	// name a section by title, or as "Server/Title" for one server's. See
+	// plex.LibraryFilter.
+	IncludeLibraries []string `json:"include_libraries,omitempty" toml:"include_libraries,omitempty" yaml:"include_libraries,omitempty"`
+	ExcludeLibraries []string `json:"exclude_libraries,omitempty" toml:"exclude_libraries,omitempty" yaml:"exclude_libraries,omitempty"`
+
+	// Dedupe, when set, removes items that several servers share from the
+	// cache after each update, keeping one copy: "local" prefers a server on
+	// the local network, "quality" the best resolution and bitrate.
+	Dedupe string `json:"dedupe,omitempty" toml:"dedupe,omitempty" yaml:"dedupe,omitempty"`
+
+	// TMDBAPIKey enables TMDB enrichment: taglines, posters and summaries for
+	// items whose Plex metadata lacks them, and the 'similar' command. Either
+	// a v3 API key or a v4 read access token works.
+	TMDBAPIKey string `json:"tmdb_api_key,omitempty" toml:"tmdb_api_key,omitempty" yaml:"tmdb_api_key,omitempty"`
 
 	// RclonecpPath optionally points at the rclonecp GUI binary used by the
 	// GUI's "Send to rclonecp" handoff. If empty, PATH and conventional
 	// install locations are searched.
-	RclonecpPath string `json:"rclonecp_path,omitempty"`
+	RclonecpPath string `json:"rclonecp_path,omitempty" toml:"rclonecp_path,omitempty" yaml:"rclonecp_path,omitempty"`
 
 	// AutoSendRclonecp forwards every completed GUI download to rclonecp
 	// automatically, in addition to the manual per-download button.
-	AutoSendRclonecp bool `json:"auto_send_rclonecp,omitempty"`
+	AutoSendRclonecp bool `json:"auto_send_rclonecp,omitempty" toml:"auto_send_rclonecp,omitempty" yaml:"auto_send_rclonecp,omitempty"`
 
 	// DownloadDir is the destination directory for downloads. A leading "~"
 	// is expanded to the user's home directory. If empty, downloads go to the
 	// current working directory. Can be overridden per-run with --dest.
-	DownloadDir string `json:"download_dir,omitempty"`
+	DownloadDir string `json:"download_dir,omitempty" toml:"download_dir,omitempty" yaml:"download_dir,omitempty"`
+
+	// DownloadConcurrency is how many files a batch download (e.g. draining
+	// the queue) transfers in parallel. 0 or 1 downloads one at a time.
+	DownloadConcurrency int `json:"download_concurrency,omitempty" toml:"download_concurrency,omitzero" yaml:"download_concurrency,omitempty"`
+
+	// MovieTemplate and EpisodeTemplate lay downloads out under the download
+	// directory, e.g. "{show}/Season {season:02}/{show} - S{season:02}E{episode:02} - {title}.{ext}".
+	// Empty keeps the original file name. See download.RenderTemplate for
+	// the available fields.
+	MovieTemplate   string `json:"movie_template,omitempty" toml:"movie_template,omitempty" yaml:"movie_template,omitempty"`
+	EpisodeTemplate string `json:"episode_template,omitempty" toml:"episode_template,omitempty" yaml:"episode_template,omitempty"`
+
+	// PostDownloadCmd is a shell command run after each successful download,
+	// with GOPLEXCLI_TITLE, GOPLEXCLI_PATH, etc. describing the file (see
+	// download.RunPostDownloadHook). Empty disables the hook.
+	PostDownloadCmd string `json:"post_download_cmd,omitempty" toml:"post_download_cmd,omitempty" yaml:"post_download_cmd,omitempty"`
+
+	// Notifications shows a desktop notification when a long operation
+	// finishes: each download, a drained queue, and a cache update or
+	// reindex.
+	Notifications bool `json:"notifications,omitempty" toml:"notifications,omitempty" yaml:"notifications,omitempty"`
+
+	// WebhookURL receives a POST when a download finishes, the queue
+	// drains, playback ends, or the cache is updated. WebhookTemplate
+	// shapes the body (see notify.RenderPayload); empty sends the event as
+	// JSON.
+	WebhookURL      string `json:"webhook_url,omitempty" toml:"webhook_url,omitempty" yaml:"webhook_url,omitempty"`
+	WebhookTemplate string `json:"webhook_template,omitempty" toml:"webhook_template,omitempty" yaml:"webhook_template,omitempty"`
+
+	// DiscordPresence shows what is playing on the user's Discord profile
+	// while the Discord app runs. DiscordClientID is the ID of the Discord
+	// application to show as, whose name appears as "Watching <name>".
+	DiscordPresence bool   `json:"discord_presence,omitempty" toml:"discord_presence,omitempty" yaml:"discord_presence,omitempty"`
+	DiscordClientID string `json:"discord_client_id,omitempty" toml:"discord_client_id,omitempty" yaml:"discord_client_id,omitempty"`
+
+	// RadarrURL/RadarrAPIKey and SonarrURL/SonarrAPIKey connect to Radarr
+	// and Sonarr, so previews show whether an item is monitored or missing
+	// files, and 'similar' can request movies that aren't in Plex. Requested
+	// movies use RadarrQualityProfile (a profile name) and RadarrRootFolder;
+	// empty uses Radarr's first of each.
+	RadarrURL            string `json:"radarr_url,omitempty" toml:"radarr_url,omitempty" yaml:"radarr_url,omitempty"`
+	RadarrAPIKey         string `json:"radarr_api_key,omitempty" toml:"radarr_api_key,omitempty" yaml:"radarr_api_key,omitempty"`
+	RadarrQualityProfile string `json:"radarr_quality_profile,omitempty" toml:"radarr_quality_profile,omitempty" yaml:"radarr_quality_profile,omitempty"`
+	RadarrRootFolder     string `json:"radarr_root_folder,omitempty" toml:"radarr_root_folder,omitempty" yaml:"radarr_root_folder,omitempty"`
+	SonarrURL            string `json:"sonarr_url,omitempty" toml:"sonarr_url,omitempty" yaml:"sonarr_url,omitempty"`
+	SonarrAPIKey         string `json:"sonarr_api_key,omitempty" toml:"sonarr_api_key,omitempty" yaml:"sonarr_api_key,omitempty"`
+
+	// OverseerrURL and OverseerrAPIKey connect to Overseerr or Jellyseerr,
+	// so a search with no results in the cache can request the title there.
+	OverseerrURL    string `json:"overseerr_url,omitempty" toml:"overseerr_url,omitempty" yaml:"overseerr_url,omitempty"`
+	OverseerrAPIKey string `json:"overseerr_api_key,omitempty" toml:"overseerr_api_key,omitempty" yaml:"overseerr_api_key,omitempty"`
+
+	// VerifyHash compares each downloaded file's MD5 with the remote's after
+	// the transfer, on top of the always-on size check. It costs a full read
+	// of the local file, so it is off by default.
+	VerifyHash bool `json:"verify_hash,omitempty" toml:"verify_hash,omitempty" yaml:"verify_hash,omitempty"`
+
+	// UsageStats opts in to recording how often each command runs and how
+	// long it takes, in a local file only (see 'goplexcli stats usage').
+	// Nothing is ever sent over the network.
+	UsageStats bool `json:"usage_stats,omitempty" toml:"usage_stats,omitempty" yaml:"usage_stats,omitempty"`
 
 	// SyncPeer is the hostname or IP (optionally host:port) of another computer
 	// on the LAN to pull the media cache from ("Sync from LAN"). When set, sync
 	// goes straight to this host; when empty, mDNS auto-discovery is used.
-	SyncPeer string `json:"sync_peer,omitempty"`
+	SyncPeer string `json:"sync_peer,omitempty" toml:"sync_peer,omitempty" yaml:"sync_peer,omitempty"`
 
 	// PathMappings translate Plex on-disk file paths into rclone remote paths
 	// during cache indexing. If empty, a legacy heuristic is used.
-	PathMappings []PathMapping `json:"path_mappings,omitempty"`
+	PathMappings []PathMapping `json:"path_mappings,omitempty" toml:"path_mappings,omitempty" yaml:"path_mappings,omitempty"`
 
 	// WebDAVUser and WebDAVPass are the shared Basic Auth credentials used for
 	// every gowebdav server discovered on the LAN (the "transfer to webdav"
 	// action). gowebdav servers advertise themselves via mDNS but do not
 	// advertise credentials, so the same user/pass is assumed across all of
 	// them. Empty values mean connect anonymously.
-	WebDAVUser string `json:"webdav_user,omitempty"`
-	WebDAVPass string `json:"webdav_pass,omitempty"`
+	WebDAVUser string `json:"webdav_user,omitempty" toml:"webdav_user,omitempty" yaml:"webdav_user,omitempty"`
+	WebDAVPass string `json:"webdav_pass,omitempty" toml:"webdav_pass,omitempty" yaml:"webdav_pass,omitempty"`
 	// WebDAVDir is an optional sub-path under the server root to upload into
 	// (e.g. "incoming"). Empty uploads to the server root.
-	WebDAVDir string `json:"webdav_dir,omitempty"`
+	WebDAVDir string `json:"webdav_dir,omitempty" toml:"webdav_dir,omitempty" yaml:"webdav_dir,omitempty"`
 
 	// OutplayerTargets are user-defined Outplayer "Wi-Fi transfer" destinations.
 	// Unlike gowebdav servers they are not discovered on the LAN; each is
 	// configured explicitly with a base URL. Individually enabled or disabled;
 	// disabled targets are hidden from the transfer menu but kept in config.
-	OutplayerTargets []OutplayerTarget `json:"outplayer_targets,omitempty"`
+	OutplayerTargets []OutplayerTarget `json:"outplayer_targets,omitempty" toml:"outplayer_targets,omitempty" yaml:"outplayer_targets,omitempty"`
 
 	// WebDAVTargets are user-defined WebDAV upload destinations with their own
 	// per-server credentials. Unlike gowebdav servers (discovered via mDNS and
 	// sharing WebDAVUser/WebDAVPass), each of these is configured explicitly
 	// with a full base URL (scheme, host, port) and its own username/password.
-	WebDAVTargets []WebDAVTarget `json:"webdav_targets,omitempty"`
+	WebDAVTargets []WebDAVTarget `json:"webdav_targets,omitempty" toml:"webdav_targets,omitempty" yaml:"webdav_targets,omitempty"`
+
+	// fileValues holds the file's value of each field (by index) that a
+	// GOPLEXCLI_* variable overrode, for Save to write instead.
+	fileValues map[int]reflect.Value
 }
 
 // WebDAVTarget represents an explicitly configured WebDAV server used as a
@@ -101,22 +346,22 @@ type Config struct {
 // standard WebDAV server works.
 type WebDAVTarget struct {
 	// Name is a human-readable identifier for the target (e.g. "office-nas").
-	Name string `json:"name"`
+	Name string `json:"name" toml:"name" yaml:"name"`
 	// URL is the WebDAV base URL including scheme and port,
 	// e.g. "http://192.168.1.50:5005".
-	URL string `json:"url"`
+	URL string `json:"url" toml:"url" yaml:"url"`
 	// User and Pass are this server's Basic Auth credentials. Pass is stored
 	// in plaintext (same as the shared WebDAVPass); empty values connect
 	// anonymously.
-	User string `json:"user,omitempty"`
-	Pass string `json:"pass,omitempty"`
+	User string `json:"user,omitempty" toml:"user,omitempty" yaml:"user,omitempty"`
+	Pass string `json:"pass,omitempty" toml:"pass,omitempty" yaml:"pass,omitempty"`
 	// Dir is an optional sub-path under the server root to upload into.
-	Dir string `json:"dir,omitempty"`
+	Dir string `json:"dir,omitempty" toml:"dir,omitempty" yaml:"dir,omitempty"`
 	// Vendor is the rclone WebDAV vendor ("other", "nextcloud", "owncloud",
 	// "sharepoint", ...). Empty means "other", which suits generic servers.
-	Vendor string `json:"vendor,omitempty"`
+	Vendor string `json:"vendor,omitempty" toml:"vendor,omitempty" yaml:"vendor,omitempty"`
 	// Enabled determines whether this target appears in the transfer menu.
-	Enabled bool `json:"enabled"`
+	Enabled bool `json:"enabled" toml:"enabled" yaml:"enabled"`
 }
 
 // Validate checks that a WebDAV target has the required fields and a usable
@@ -140,16 +385,16 @@ func (t WebDAVTarget) Validate() error {
 // targets can be configured and each individually enabled or disabled.
 type OutplayerTarget struct {
 	// Name is a human-readable identifier for the target (e.g. "iPhone").
-	Name string `json:"name"`
+	Name string `json:"name" toml:"name" yaml:"name"`
 	// URL is the base URL of the Outplayer Wi-Fi transfer server, as shown in
 	// the app (e.g. "http://192.168.0.34").
-	URL string `json:"url"`
+	URL string `json:"url" toml:"url" yaml:"url"`
 	// Dir is the destination folder on the target to upload into. Empty means
 	// the server root. Note that some built-in folders (e.g. "Inbox") are not
 	// writable, so the root is the safe default.
-	Dir string `json:"dir,omitempty"`
+	Dir string `json:"dir,omitempty" toml:"dir,omitempty" yaml:"dir,omitempty"`
 	// Enabled determines whether this target appears in the transfer menu.
-	Enabled bool `json:"enabled"`
+	Enabled bool `json:"enabled" toml:"enabled" yaml:"enabled"`
 }
 
 // Validate checks that an Outplayer target has the required fields and a usable
@@ -167,6 +412,38 @@ func (t OutplayerTarget) Validate() error {
 	return nil
 }
 
+// PlayerDefinition describes a user-defined external player. ArgsTemplate
+// is the argument list passed to Path, with these placeholders replaced:
+// {url} the stream URL (an argument that is exactly "{url}" expands to one
+// argument per queued URL), {start} the resume position in seconds, {title}
+// the title of the first item, and {ipc} an mpv-compatible IPC socket path.
+type PlayerDefinition struct {
+	Name         string   `json:"name" toml:"name" yaml:"name"`
+	Path         string   `json:"path" toml:"path" yaml:"path"`
+	ArgsTemplate []string `json:"args_template" toml:"args_template" yaml:"args_template"`
+	// SupportsIPC marks the player as speaking mpv's JSON IPC protocol on
+	// the {ipc} socket, enabling progress tracking and resume.
+	SupportsIPC bool `json:"supports_ipc,omitempty" toml:"supports_ipc,omitempty" yaml:"supports_ipc,omitempty"`
+}
+
+// Validate checks that a player definition is usable.
+func (p PlayerDefinition) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	switch strings.ToLower(p.Name) {
+	case "mpv", "vlc", "iina":
+		return fmt.Errorf("name %q is a built-in player", p.Name)
+	}
+	if p.Path == "" {
+		return fmt.Errorf("path is required")
+	}
+	if p.SupportsIPC && !strings.Contains(strings.Join(p.ArgsTemplate, " "), "{ipc}") {
+		return fmt.Errorf("supports_ipc requires an {ipc} placeholder in args_template")
+	}
+	return nil
+}
+
 // PathMapping translates a Plex on-disk file path prefix into an rclone remote.
 // A file path beginning with Prefix has that prefix replaced by Remote. For
 // example {Prefix: "/home/joshkerr/plexcloudservers2/", Remote:
@@ -174,11 +451,53 @@ func (t OutplayerTarget) Validate() error {
 // "/home/joshkerr/plexcloudservers2/Media/TV/x.mkv" into
 // "plexcloudservers2:Media/TV/x.mkv".
 type PathMapping struct {
-	Prefix string `json:"prefix"`
-	Remote string `json:"remote"`
+	Prefix string `json:"prefix" toml:"prefix" yaml:"prefix"`
+	Remote string `json:"remote" toml:"remote" yaml:"remote"`
+}
+
+// ProfileEnv is the environment variable that selects a profile when
+// SetProfile hasn't.
+const ProfileEnv = "GOPLEXCLI_PROFILE"
+
+// profile is the name set with SetProfile.
+var profile string
+
+// SetProfile selects a profile: an independent config, cache and queue
+// under profiles/<name>/ in the config directory, e.g. for a second Plex
+// account. "" leaves the choice to GOPLEXCLI_PROFILE, and without it the
+// default files are used.
+func SetProfile(name string) error {
+	if name != "" {
+		if err := validateProfileName(name); err != nil {
+			return err
+		}
+	}
+	profile = name
+	return nil
+}
+
+// Profile returns the selected profile name, or "" for the default files.
+func Profile() string {
+	if profile != "" {
+		return profile
+	}
+	return os.Getenv(ProfileEnv)
+}
+
+func validateProfileName(name string) error {
+	if name == "." || name == ".." {
+		return fmt.Errorf("invalid profile name %q", name)
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return fmt.Errorf("invalid profile name %q: use letters, digits, '-', '_' and '.'", name)
+		}
+	}
+	return nil
 }
 
-// GetConfigDir returns the platform-specific config directory
+// GetConfigDir returns the platform-specific config directory, or the
+// selected profile's directory inside it (see SetProfile).
 func GetConfigDir() (string, error) {
 	var baseDir string
 
@@ -210,9 +529,29 @@ func GetConfigDir() (string, error) {
 	}
 
 	configDir := filepath.Join(baseDir, "goplexcli")
+	if name := Profile(); name != "" {
+		if err := validateProfileName(name); err != nil {
+			return "", err
+		}
+		configDir = filepath.Join(configDir, "profiles", name)
+	}
 	return configDir, nil
 }
 
+// StateDriver returns the storage driver state_backend chooses, or nil for
+// the default JSON files. Unknown names fall back to JSON; Validate reports
+// them.
+func (c *Config) StateDriver() (storage.Driver, error) {
+	if strings.ToLower(c.StateBackend) != "sqlite" {
+		return nil, nil
+	}
+	dir, err := GetConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return storage.NewSQLite(filepath.Join(dir, "state.db")), nil
+}
+
 // GetCacheDir returns the cache directory path
 func GetCacheDir() (string, error) {
 	configDir, err := GetConfigDir()
@@ -222,40 +561,72 @@ func GetCacheDir() (string, error) {
 	return filepath.Join(configDir, "cache"), nil
 }
 
-// GetConfigPath returns the full path to the config file
+// GetConfigPath returns the full path to the config file: the first of
+// config.json, config.toml, config.yaml and config.yml that exists, or
+// config.json if none does.
 func GetConfigPath() (string, error) {
 	configDir, err := GetConfigDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(configDir, "config.json"), nil
+	for _, name := range configNames {
+		path := filepath.Join(configDir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return filepath.Join(configDir, configNames[0]), nil
 }
 
-// Load reads the config file and returns a Config struct
-func Load() (*Config, error) {
+// Exists reports whether a config file has been written for the active
+// profile.
+func Exists() bool {
 	configPath, err := GetConfigPath()
 	if err != nil {
-		return nil, err
+		return false
 	}
+	_, err = os.Stat(configPath)
+	return err == nil
+}
 
-	data, err := os.ReadFile(configPath)
+// Load reads the config file and returns a Config struct. GOPLEXCLI_*
+// environment variables override the file (see EnvPrefix), and without a
+// file the config comes from them alone.
+func Load() (*Config, error) {
+	configPath, err := GetConfigPath()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return &Config{}, nil
-		}
 		return nil, err
 	}
 
 	var config Config
-	if err := json.Unmarshal(data, &config); err != nil {
+	data, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
 		return nil, err
 	}
+	if err == nil {
+		if err := decodeConfig(configPath, data, &config); err != nil {
+			return nil, err
+		}
+	}
 
 	// Migrate legacy single-server config to multi-server
 	if err := config.MigrateLegacy(); err != nil {
 		return nil, err
 	}
 
+	if err := config.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	// A legacy URL that only the environment sets still needs a server to
+	// connect to, but one Save leaves out like the URL itself.
+	if config.PlexURL != "" && len(config.Servers) == 0 {
+		config.rememberFileValue("Servers")
+		if err := config.MigrateLegacy(); err != nil {
+			return nil, err
+		}
+	}
+
 	return &config, nil
 }
 
@@ -276,7 +647,13 @@ func (c *Config) Save() error {
 		return err
 	}
 
-	data, err := json.MarshalIndent(c, "", "  ")
+	// Written in the file's own format, keeping a TOML or YAML file's
+	// comments.
+	old, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	data, err := encodeConfig(configPath, c.withoutEnv(), old)
 	if err != nil {
 		return err
 	}
@@ -313,22 +690,127 @@ func (c *Config) ResolveDownloadDir(override string) (string, error) {
 	if dir == "" {
 		return os.Getwd()
 	}
+	return absDir(dir, "download dir")
+}
 
+// ResolveScreenshotDir returns the directory screenshots are saved to: the
+// override argument (from --screenshot-dir), then ScreenshotDir, with "~"
+// expanded. It returns "" when neither is set.
+func (c *Config) ResolveScreenshotDir(override string) (string, error) {
+	dir := override
+	if dir == "" {
+		dir = c.ScreenshotDir
+	}
+	if dir == "" {
+		return "", nil
+	}
+	return absDir(dir, "screenshot dir")
+}
+
+// absDir expands a leading "~" in dir to the user's home directory and
+// makes it absolute. what names the setting in errors.
+func absDir(dir, what string) (string, error) {
 	if dir == "~" || strings.HasPrefix(dir, "~/") || strings.HasPrefix(dir, `~\`) {
 		home, err := os.UserHomeDir()
 		if err != nil {
-			return "", fmt.Errorf("cannot expand ~ in download dir: %w", err)
+			return "", fmt.Errorf("cannot expand ~ in %s: %w", what, err)
 		}
 		dir = filepath.Join(home, dir[1:])
 	}
 
 	abs, err := filepath.Abs(dir)
 	if err != nil {
-		return "", fmt.Errorf("invalid download dir %q: %w", dir, err)
+		return "", fmt.Errorf("invalid %s %q: %w", what, dir, err)
 	}
 	return abs, nil
 }
 
+// maxDownloadConcurrency caps parallel transfers; beyond this, rclone
+// processes mostly contend for the same upstream bandwidth.
+const maxDownloadConcurrency = 8
+
+// GetDownloadConcurrency returns the number of parallel downloads to run,
+// clamped to 1..maxDownloadConcurrency.
+func (c *Config) GetDownloadConcurrency() int {
+	switch {
+	case c.DownloadConcurrency < 1:
+		return 1
+	case c.DownloadConcurrency > maxDownloadConcurrency:
+		return maxDownloadConcurrency
+	}
+	return c.DownloadConcurrency
+}
+
+// NamingTemplate returns the configured download naming template for a media
+// type ("movie" or "episode"); other types always keep their original name.
+func (c *Config) NamingTemplate(mediaType string) string {
+	switch mediaType {
+	case "movie":
+		return c.MovieTemplate
+	case "episode":
+		return c.EpisodeTemplate
+	}
+	return ""
+}
+
+// PlayerName returns the configured video player: "mpv", "vlc", "iina" or
+// the name of a custom player from Players.
+func (c *Config) PlayerName() string {
+	switch name := strings.ToLower(c.Player); name {
+	case "vlc", "iina":
+		return name
+	}
+	if p := c.customPlayer(); p != nil {
+		return p.Name
+	}
+	return "mpv"
+}
+
+// PlayerPath returns the configured path for the selected player, or "" to
+// search PATH.
+func (c *Config) PlayerPath() string {
+	switch c.PlayerName() {
+	case "mpv":
+		return c.MPVPath
+	case "vlc":
+		return c.VLCPath
+	case "iina":
+		return c.IINAPath
+	}
+	if p := c.customPlayer(); p != nil {
+		return p.Path
+	}
+	return c.MPVPath
+}
+
+// customPlayer returns the Players entry Player names, matched without
+// regard to case, or nil.
+func (c *Config) customPlayer() *PlayerDefinition {
+	if c.Player == "" {
+		return nil
+	}
+	for i := range c.Players {
+		if strings.EqualFold(c.Players[i].Name, c.Player) {
+			return &c.Players[i]
+		}
+	}
+	return nil
+}
+
+// ApplyTimezone makes Timezone, if set, the process's local time zone, so
+// every time.Local rendering (cache info, history, calendar) uses it.
+func (c *Config) ApplyTimezone() error {
+	if c.Timezone == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", c.Timezone, err)
+	}
+	time.Local = loc
+	return nil
+}
+
 // TokenForServer returns the token to use when talking to a specific server:
 // the server's own access token when present, otherwise the account-wide
 // PlexToken. Owners can use their account token directly, but shared users
@@ -341,19 +823,41 @@ func (c *Config) TokenForServer(s PlexServer) string {
 }
 
 // TokenForURL returns the token to use for the server at the given URL,
-// matching configured servers while ignoring trailing slashes. It falls back
+// matching configured servers by URL or any of their connections while
+// ignoring trailing slashes. It falls back
 // to the account-wide PlexToken when no configured server matches or the
 // matching server has no token of its own.
 func (c *Config) TokenForURL(serverURL string) string {
-	target := strings.TrimRight(serverURL, "/")
 	for _, s := range c.Servers {
-		if strings.TrimRight(s.URL, "/") == target && s.Token != "" {
+		if s.HasURL(serverURL) && s.Token != "" {
 			return s.Token
 		}
 	}
 	return c.PlexToken
 }
 
+// IsSharedURL reports whether the configured server at serverURL is known
+// to belong to someone else (see PlexServer.IsShared).
+func (c *Config) IsSharedURL(serverURL string) bool {
+	for _, s := range c.Servers {
+		if s.HasURL(serverURL) {
+			return s.IsShared()
+		}
+	}
+	return false
+}
+
+// ConnectionsForURL returns the stored connections of the server reachable
+// at serverURL, or nil if no configured server has that URL.
+func (c *Config) ConnectionsForURL(serverURL string) []string {
+	for _, s := range c.Servers {
+		if s.HasURL(serverURL) {
+			return s.Connections
+		}
+	}
+	return nil
+}
+
 // GetEnabledServers returns all servers that should be indexed
 func (c *Config) GetEnabledServers() []PlexServer {
 	var enabled []PlexServer
@@ -410,6 +914,48 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	for i, p := range c.Players {
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("players[%d]: %w", i, err)
+		}
+	}
+
+	switch strings.ToLower(c.Player) {
+	case "", "mpv", "vlc", "iina":
+	default:
+		if c.customPlayer() == nil {
+			return fmt.Errorf("invalid player %q: must be \"mpv\", \"vlc\", \"iina\" or a name from players", c.Player)
+		}
+	}
+
+	if c.Volume < 0 || c.Volume > 130 {
+		return fmt.Errorf("invalid volume %d: must be between 0 and 130", c.Volume)
+	}
+
+	switch strings.ToLower(c.PreviewImages) {
+	case "", "auto", "kitty", "iterm2", "sixel", "symbols", "off":
+	default:
+		return fmt.Errorf("invalid preview_images %q: must be \"auto\", \"kitty\", \"iterm2\", \"sixel\", \"symbols\" or \"off\"", c.PreviewImages)
+	}
+
+	switch strings.ToLower(c.CacheFormat) {
+	case "", "json", "gob":
+	default:
+		return fmt.Errorf("invalid cache_format %q: must be \"json\" or \"gob\"", c.CacheFormat)
+	}
+
+	switch strings.ToLower(c.StateBackend) {
+	case "", "json", "sqlite":
+	default:
+		return fmt.Errorf("invalid state_backend %q: must be \"json\" or \"sqlite\"", c.StateBackend)
+	}
+
+	switch strings.ToLower(c.Dedupe) {
+	case "", "local", "quality":
+	default:
+		return fmt.Errorf("invalid dedupe %q: must be \"local\" or \"quality\"", c.Dedupe)
+	}
+
 	// Validate each configured server
 	for i, server := range c.Servers {
 		if server.Name == "" {
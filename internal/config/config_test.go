@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestValidate(t *testing.T) {
@@ -46,6 +47,46 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "unknown player",
+			config: Config{
+				PlexURL:   "http://192.168.1.100:32400",
+				PlexToken: "test-token",
+				Player:    "quicktime",
+			},
+			wantErr: true,
+			errMsg:  "invalid player",
+		},
+		{
+			name: "unknown cache format",
+			config: Config{
+				PlexURL:     "http://192.168.1.100:32400",
+				PlexToken:   "test-token",
+				CacheFormat: "msgpack",
+			},
+			wantErr: true,
+			errMsg:  "invalid cache_format",
+		},
+		{
+			name: "unknown dedupe policy",
+			config: Config{
+				PlexURL:   "http://192.168.1.100:32400",
+				PlexToken: "test-token",
+				Dedupe:    "newest",
+			},
+			wantErr: true,
+			errMsg:  "invalid dedupe",
+		},
+		{
+			name: "volume out of range",
+			config: Config{
+				PlexURL:   "http://192.168.1.100:32400",
+				PlexToken: "test-token",
+				Volume:    150,
+			},
+			wantErr: true,
+			errMsg:  "invalid volume",
+		},
 		{
 			name: "invalid URL scheme",
 			config: Config{
@@ -303,6 +344,104 @@ func TestResolveDownloadDir(t *testing.T) {
 	}
 }
 
+func TestResolveScreenshotDir(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatalf("UserHomeDir: %v", err)
+	}
+
+	if got, err := (&Config{}).ResolveScreenshotDir(""); err != nil || got != "" {
+		t.Errorf("unset = %q, %v; want empty", got, err)
+	}
+	cfg := Config{ScreenshotDir: filepath.Join("~", "Stills")}
+	if got, _ := cfg.ResolveScreenshotDir(""); got != filepath.Join(home, "Stills") {
+		t.Errorf("config dir = %q, want ~ expanded", got)
+	}
+	if got, _ := cfg.ResolveScreenshotDir(filepath.Join(home, "Posters")); got != filepath.Join(home, "Posters") {
+		t.Errorf("override = %q, want it to beat the config", got)
+	}
+}
+
+func TestGetDownloadConcurrency(t *testing.T) {
+	tests := []struct {
+		configured int
+		want       int
+	}{
+		{0, 1},
+		{-3, 1},
+		{1, 1},
+		{4, 4},
+		{100, maxDownloadConcurrency},
+	}
+	for _, tt := range tests {
+		cfg := Config{DownloadConcurrency: tt.configured}
+		if got := cfg.GetDownloadConcurrency(); got != tt.want {
+			t.Errorf("GetDownloadConcurrency() with %d = %d, want %d", tt.configured, got, tt.want)
+		}
+	}
+}
+
+func TestPlayerNameAndPath(t *testing.T) {
+	cfg := Config{MPVPath: "/opt/mpv", VLCPath: "/opt/vlc", IINAPath: "/opt/iina"}
+	if cfg.PlayerName() != "mpv" || cfg.PlayerPath() != "/opt/mpv" {
+		t.Errorf("default player = %s at %s, want mpv at /opt/mpv", cfg.PlayerName(), cfg.PlayerPath())
+	}
+
+	cfg.Player = "VLC"
+	if cfg.PlayerName() != "vlc" || cfg.PlayerPath() != "/opt/vlc" {
+		t.Errorf("player = %s at %s, want vlc at /opt/vlc", cfg.PlayerName(), cfg.PlayerPath())
+	}
+
+	cfg.Player = "iina"
+	if cfg.PlayerName() != "iina" || cfg.PlayerPath() != "/opt/iina" {
+		t.Errorf("player = %s at %s, want iina at /opt/iina", cfg.PlayerName(), cfg.PlayerPath())
+	}
+
+	cfg.Players = []PlayerDefinition{{Name: "smplayer", Path: "/opt/smplayer"}}
+	cfg.Player = "SMPlayer"
+	if cfg.PlayerName() != "smplayer" || cfg.PlayerPath() != "/opt/smplayer" {
+		t.Errorf("player = %s at %s, want smplayer at /opt/smplayer", cfg.PlayerName(), cfg.PlayerPath())
+	}
+}
+
+func TestPlayerDefinitionValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		player  PlayerDefinition
+		wantErr bool
+	}{
+		{"valid", PlayerDefinition{Name: "celluloid", Path: "celluloid", ArgsTemplate: []string{"{url}"}}, false},
+		{"valid ipc", PlayerDefinition{Name: "wrapper", Path: "/bin/w", ArgsTemplate: []string{"--input-ipc-server={ipc}", "{url}"}, SupportsIPC: true}, false},
+		{"missing name", PlayerDefinition{Path: "celluloid"}, true},
+		{"missing path", PlayerDefinition{Name: "celluloid"}, true},
+		{"built-in name", PlayerDefinition{Name: "MPV", Path: "/bin/mpv"}, true},
+		{"ipc without placeholder", PlayerDefinition{Name: "w", Path: "/bin/w", ArgsTemplate: []string{"{url}"}, SupportsIPC: true}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.player.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestApplyTimezone(t *testing.T) {
+	orig := time.Local
+	defer func() { time.Local = orig }()
+
+	if err := (&Config{}).ApplyTimezone(); err != nil || time.Local != orig {
+		t.Errorf("empty timezone should leave time.Local alone, err = %v", err)
+	}
+	if err := (&Config{Timezone: "Not/AZone"}).ApplyTimezone(); err == nil {
+		t.Error("an unknown timezone should be rejected")
+	}
+	if err := (&Config{Timezone: "UTC"}).ApplyTimezone(); err != nil {
+		t.Fatalf("ApplyTimezone(UTC): %v", err)
+	}
+	if time.Local.String() != "UTC" {
+		t.Errorf("time.Local = %s, want UTC", time.Local)
+	}
+}
+
 // contains checks if s contains substr
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(substr) == 0 ||
@@ -380,7 +519,8 @@ func TestTokenForURL(t *testing.T) {
 	cfg := &Config{
 		PlexToken: "account-token",
 		Servers: []PlexServer{
-			{Name: "Shared", URL: "http://shared:32400/", Token: "server-token", Enabled: true},
+			{Name: "Shared", URL: "http://shared:32400/", Token: "server-token", Enabled: true,
+				Connections: []string{"http://shared:32400", "https://1-2-3-4.abc.plex.direct:32400"}},
 			{Name: "Legacy", URL: "http://legacy:32400", Enabled: true},
 		},
 	}
@@ -392,6 +532,7 @@ func TestTokenForURL(t *testing.T) {
 	}{
 		{"matching server with token", "http://shared:32400", "server-token"},
 		{"trailing slash mismatch tolerated", "http://shared:32400/", "server-token"},
+		{"fallback connection of a server", "https://1-2-3-4.abc.plex.direct:32400", "server-token"},
 		{"matching server without token falls back", "http://legacy:32400", "account-token"},
 		{"unknown URL falls back", "http://other:32400", "account-token"},
 	}
@@ -402,6 +543,38 @@ func TestTokenForURL(t *testing.T) {
 			}
 		})
 	}
+
+	if got := cfg.ConnectionsForURL("https://1-2-3-4.abc.plex.direct:32400"); len(got) != 2 || got[0] != "http://shared:32400" {
+		t.Errorf("ConnectionsForURL(fallback) = %v, want the Shared server's connections", got)
+	}
+	if got := cfg.ConnectionsForURL("http://other:32400"); got != nil {
+		t.Errorf("ConnectionsForURL(unknown) = %v, want nil", got)
+	}
+}
+
+func TestIsSharedURL(t *testing.T) {
+	owned, shared := true, false
+	cfg := &Config{Servers: []PlexServer{
+		{Name: "Mine", URL: "http://mine:32400", Owned: &owned},
+		{Name: "Friend", URL: "https://friend.example:32400", Owned: &shared,
+			Connections: []string{"https://friend.example:32400", "https://5-6-7-8.abc.plex.direct:32400"}},
+		{Name: "Legacy", URL: "http://legacy:32400"},
+	}}
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"http://mine:32400", false},
+		{"https://friend.example:32400/", true},
+		{"https://5-6-7-8.abc.plex.direct:32400", true},
+		{"http://legacy:32400", false}, // ownership unknown
+		{"http://other:32400", false},
+	}
+	for _, tt := range tests {
+		if got := cfg.IsSharedURL(tt.url); got != tt.want {
+			t.Errorf("IsSharedURL(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
 }
 
 func TestOutplayerTargetsRoundTrip(t *testing.T) {
@@ -514,3 +687,46 @@ func TestWebDAVTargetsRoundTrip(t *testing.T) {
 		t.Errorf("round-trip mismatch: %+v", got)
 	}
 }
+
+func TestProfileDir(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("APPDATA", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv(ProfileEnv, "")
+	t.Cleanup(func() { _ = SetProfile("") })
+
+	base, err := GetConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(ProfileEnv, "family")
+	got, err := GetConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(base, "profiles", "family"); got != want {
+		t.Errorf("with %s: dir = %q, want %q", ProfileEnv, got, want)
+	}
+
+	// The flag wins over the environment.
+	if err := SetProfile("personal"); err != nil {
+		t.Fatal(err)
+	}
+	cacheDir, _ := GetCacheDir()
+	if want := filepath.Join(base, "profiles", "personal", "cache"); cacheDir != want {
+		t.Errorf("cache dir = %q, want %q", cacheDir, want)
+	}
+
+	for _, bad := range []string{"..", "a/b", `a\b`, "two words"} {
+		if err := SetProfile(bad); err == nil {
+			t.Errorf("SetProfile(%q): expected an error", bad)
+		}
+	}
+	_ = SetProfile("")
+	t.Setenv(ProfileEnv, "../escape")
+	if _, err := GetConfigDir(); err == nil {
+		t.Errorf("an invalid %s should be rejected", ProfileEnv)
+	}
+}
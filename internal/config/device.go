@@ -0,0 +1,47 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// clientIDFile holds the identifier this install presents to Plex. It lives
+// beside the config file rather than in it, so it survives the config being
+// rewritten or replaced.
+const clientIDFile = "client_id"
+
+// ClientIdentifier returns the identifier goplexcli sends Plex as
+// X-Plex-Client-Identifier, generating and saving a random one on first use.
+// Plex lists each identifier as one device, so keeping it stable gives this
+// install a single entry in the account's devices.
+func ClientIdentifier() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(configDir, clientIDFile)
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if id := strings.TrimSpace(string(data)); id != "" {
+		return id, nil
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	id := "goplexcli-" + hex.EncodeToString(buf)
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(id+"\n"), 0600); err != nil {
+		return "", err
+	}
+	return id, nil
+}
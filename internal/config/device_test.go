@@ -0,0 +1,43 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClientIdentifier(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("APPDATA", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv(ProfileEnv, "")
+
+	id, err := ClientIdentifier()
+	if err != nil {
+		t.Fatalf("ClientIdentifier: %v", err)
+	}
+	if !strings.HasPrefix(id, "goplexcli-") || len(id) != len("goplexcli-")+32 {
+		t.Errorf("ClientIdentifier = %q, want goplexcli- and 32 hex digits", id)
+	}
+
+	again, err := ClientIdentifier()
+	if err != nil {
+		t.Fatalf("ClientIdentifier (second call): %v", err)
+	}
+	if again != id {
+		t.Errorf("ClientIdentifier changed from %q to %q", id, again)
+	}
+
+	configDir, err := GetConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, clientIDFile), []byte("  chosen-id\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ClientIdentifier(); got != "chosen-id" {
+		t.Errorf("ClientIdentifier = %q, want the saved chosen-id", got)
+	}
+}
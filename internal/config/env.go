@@ -0,0 +1,106 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix starts the environment variables that override config fields:
+// the prefix plus the field's JSON name in upper case, e.g.
+// GOPLEXCLI_PLEX_TOKEN for plex_token. Lists and objects such as servers
+// take a JSON value.
+const EnvPrefix = "GOPLEXCLI_"
+
+// applyEnv overrides fields from GOPLEXCLI_* variables and remembers the
+// file's values, so Save never writes an override to disk.
+func (c *Config) applyEnv() error {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := jsonName(t.Field(i))
+		if name == "" {
+			continue
+		}
+		envName := EnvPrefix + strings.ToUpper(name)
+		raw, ok := os.LookupEnv(envName)
+		if !ok {
+			continue
+		}
+
+		field := v.Field(i)
+		value := reflect.New(field.Type()).Elem()
+		switch field.Kind() {
+		case reflect.String:
+			value.SetString(raw)
+		case reflect.Bool:
+			b, err := strconv.ParseBool(raw)
+			if err != nil {
+				return fmt.Errorf("%s: expected true or false, got %q", envName, raw)
+			}
+			value.SetBool(b)
+		case reflect.Int:
+			n, err := strconv.Atoi(raw)
+			if err != nil {
+				return fmt.Errorf("%s: expected a number, got %q", envName, raw)
+			}
+			value.SetInt(int64(n))
+		default:
+			if err := json.Unmarshal([]byte(raw), value.Addr().Interface()); err != nil {
+				return fmt.Errorf("%s: expected JSON: %w", envName, err)
+			}
+		}
+
+		c.remember(i)
+		field.Set(value)
+	}
+	return nil
+}
+
+// remember records the file's value of field i before it is overridden.
+func (c *Config) remember(i int) {
+	if c.fileValues == nil {
+		c.fileValues = make(map[int]reflect.Value)
+	}
+	if _, seen := c.fileValues[i]; seen {
+		return
+	}
+	field := reflect.ValueOf(c).Elem().Field(i)
+	orig := reflect.New(field.Type()).Elem()
+	orig.Set(field)
+	c.fileValues[i] = orig
+}
+
+// rememberFileValue is remember for the field called name, for one set
+// from an overridden field rather than by a variable of its own.
+func (c *Config) rememberFileValue(name string) {
+	if f, ok := reflect.TypeOf(c).Elem().FieldByName(name); ok {
+		c.remember(f.Index[0])
+	}
+}
+
+// withoutEnv returns a copy of c with overridden fields set back to the
+// values loaded from the file.
+func (c *Config) withoutEnv() *Config {
+	out := *c
+	v := reflect.ValueOf(&out).Elem()
+	for i, orig := range c.fileValues {
+		v.Field(i).Set(orig)
+	}
+	return &out
+}
+
+// jsonName returns the JSON key of a struct field, or "" if it has none.
+func jsonName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestApplyEnv(t *testing.T) {
+	t.Setenv("GOPLEXCLI_PLEX_TOKEN", "env-token")
+	t.Setenv("GOPLEXCLI_SKIP_INTROS", "true")
+	t.Setenv("GOPLEXCLI_DOWNLOAD_CONCURRENCY", "4")
+	t.Setenv("GOPLEXCLI_SERVERS", `[{"name":"CI","url":"http://plex:32400","enabled":true}]`)
+
+	cfg := &Config{PlexToken: "file-token", Player: "vlc"}
+	if err := cfg.applyEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.PlexToken != "env-token" || !cfg.SkipIntros || cfg.DownloadConcurrency != 4 {
+		t.Errorf("overrides not applied: %+v", cfg)
+	}
+	if len(cfg.Servers) != 1 || cfg.Servers[0].URL != "http://plex:32400" {
+		t.Errorf("servers = %+v", cfg.Servers)
+	}
+	if cfg.Player != "vlc" {
+		t.Errorf("player = %q, want the file's value", cfg.Player)
+	}
+
+	saved := cfg.withoutEnv()
+	if saved.PlexToken != "file-token" || saved.SkipIntros || saved.DownloadConcurrency != 0 || saved.Servers != nil {
+		t.Errorf("withoutEnv should restore the file's values, got %+v", saved)
+	}
+}
+
+func TestApplyEnvInvalid(t *testing.T) {
+	for name, value := range map[string]string{
+		"GOPLEXCLI_SKIP_INTROS":          "maybe",
+		"GOPLEXCLI_DOWNLOAD_CONCURRENCY": "many",
+		"GOPLEXCLI_SERVERS":              "not json",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, value)
+			err := (&Config{}).applyEnv()
+			if err == nil || !strings.Contains(err.Error(), name) {
+				t.Errorf("error = %v, want one naming %s", err, name)
+			}
+		})
+	}
+}
+
+func TestLoadFromEnvOnly(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("APPDATA", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("GOPLEXCLI_PLEX_URL", "http://plex:32400")
+	t.Setenv("GOPLEXCLI_PLEX_TOKEN", "tok")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("env-only config should validate: %v", err)
+	}
+	if len(cfg.Servers) != 1 || cfg.Servers[0].URL != "http://plex:32400" {
+		t.Errorf("legacy URL from env not migrated: %+v", cfg.Servers)
+	}
+
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	path, _ := GetConfigPath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, env := range []string{`"tok"`, "plex:32400"} {
+		if strings.Contains(string(data), env) {
+			t.Errorf("Save wrote %s from the environment to disk:\n%s", env, data)
+		}
+	}
+}
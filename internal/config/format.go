@@ -0,0 +1,47 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// configNames are the config file names Load looks for, in order. The
+// first one that exists wins; Save writes back to it in the same format.
+var configNames = []string{"config.json", "config.toml", "config.yaml", "config.yml"}
+
+// decodeConfig parses a config file by its extension into cfg. Every
+// format uses the same field names, set by the struct tags.
+func decodeConfig(path string, data []byte, cfg *Config) error {
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		_, err = toml.Decode(string(data), cfg)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, cfg)
+	default:
+		return json.Unmarshal(data, cfg)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// encodeConfig renders cfg in the format path's extension names. old is
+// the file being replaced, if any: a TOML or YAML rendering keeps its
+// comments.
+func encodeConfig(path string, cfg *Config, old []byte) ([]byte, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		return encodeTOML(cfg, old)
+	case ".yaml", ".yml":
+		return encodeYAML(cfg, old)
+	default:
+		return json.MarshalIndent(cfg, "", "  ")
+	}
+}
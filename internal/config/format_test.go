@@ -0,0 +1,216 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const tomlConfig = `# goplexcli config
+plex_token = "tok" # trailing comment
+player = 'iina'
+skip_intros = true
+download_concurrency = 3
+mpv_path = "C:\\Program Files\\mpv.exe"
+
+[playback_presets]
+quiet = ["--volume=60",
+         "--mute=no", ]
+
+[[servers]]
+name = "Home"
+url = "http://192.168.1.100:32400"
+enabled = true
+
+[[servers]]
+name = "Cabin"
+url = "https://cabin.example:32400"
+enabled = false
+`
+
+const yamlConfig = `---
+# goplexcli config
+plex_token: tok   # trailing comment
+player: iina
+skip_intros: true
+download_concurrency: 3
+mpv_path: 'C:\Program Files\mpv.exe'
+
+playback_presets:
+  quiet: ["--volume=60", "--mute=no"]
+
+servers:
+- name: Home
+  url: http://192.168.1.100:32400
+  enabled: true
+-   name: "Cabin"
+    url: "https://cabin.example:32400"
+    enabled: false
+`
+
+func wantParsed() Config {
+	return Config{
+		PlexToken:           "tok",
+		Player:              "iina",
+		SkipIntros:          true,
+		DownloadConcurrency: 3,
+		MPVPath:             `C:\Program Files\mpv.exe`,
+		PlaybackPresets:     map[string][]string{"quiet": {"--volume=60", "--mute=no"}},
+		Servers: []PlexServer{
+			{Name: "Home", URL: "http://192.168.1.100:32400", Enabled: true},
+			{Name: "Cabin", URL: "https://cabin.example:32400"},
+		},
+	}
+}
+
+func TestDecodeConfigFormats(t *testing.T) {
+	for _, tc := range []struct {
+		name, data string
+	}{
+		{"config.toml", tomlConfig},
+		{"config.yaml", yamlConfig},
+	} {
+		var cfg Config
+		if err := decodeConfig(tc.name, []byte(tc.data), &cfg); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if want := wantParsed(); !reflect.DeepEqual(cfg, want) {
+			t.Errorf("%s:\n got %+v\nwant %+v", tc.name, cfg, want)
+		}
+	}
+}
+
+func TestDecodeConfigErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"config.toml": "plex_token = \"tok\"\nplex_token = \"again\"\n",
+		"config.yml":  "plex_token: tok\n  nested: oops\n",
+		"config.yaml": "download_concurrency: many\n",
+	} {
+		var cfg Config
+		err := decodeConfig(name, []byte(data), &cfg)
+		if err == nil {
+			t.Errorf("%s: expected an error for %q", name, data)
+			continue
+		}
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("%s: error %q should name the file", name, err)
+		}
+	}
+	for _, data := range []string{
+		"a = 1 b = 2\n",
+		"a = [1, 2\n",
+		"a = \"open\n",
+		"[t]\n[t]\n",
+		"download_concurrency = \"3\"\n",
+	} {
+		var cfg Config
+		if err := decodeConfig("config.toml", []byte(data), &cfg); err == nil {
+			t.Errorf("decodeConfig(%q): expected an error", data)
+		}
+	}
+}
+
+func TestEncodeConfigRoundTrip(t *testing.T) {
+	cfg := wantParsed()
+	cfg.PostDownloadCmd = `echo "done: $GOPLEXCLI_TITLE" # not a comment`
+	cfg.SyncPeer = "no" // YAML 1.1 would read a bare no as false
+	cfg.PathMappings = []PathMapping{{Prefix: "/media", Remote: "nas:Media"}}
+	cfg.Servers[0].Connections = []string{"http://192.168.1.100:32400", "https://1-2-3-4.abc.plex.direct:32400"}
+
+	for _, name := range []string{"config.json", "config.toml", "config.yaml"} {
+		data, err := encodeConfig(name, &cfg, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var loaded Config
+		if err := decodeConfig(name, data, &loaded); err != nil {
+			t.Fatalf("%s: %v\n%s", name, err, data)
+		}
+		if !reflect.DeepEqual(loaded, cfg) {
+			t.Errorf("%s round trip:\n got %+v\nwant %+v\n%s", name, loaded, cfg, data)
+		}
+	}
+}
+
+func TestEncodeConfigKeepsComments(t *testing.T) {
+	for _, tc := range []struct {
+		name, data string
+		comments   []string
+	}{
+		{"config.toml", tomlConfig, []string{"# goplexcli config\n", `plex_token = "tok" # trailing comment`}},
+		{"config.yaml", yamlConfig, []string{"# goplexcli config\n", "plex_token: tok # trailing comment"}},
+	} {
+		data := strings.Replace(tc.data, "\nplayer", "\n# the default player\nplayer", 1)
+		var cfg Config
+		if err := decodeConfig(tc.name, []byte(data), &cfg); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		cfg.Player = "mpv"
+		cfg.Servers = cfg.Servers[:1]
+
+		out, err := encodeConfig(tc.name, &cfg, []byte(data))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		for _, want := range append(tc.comments, "# the default player\n") {
+			if !strings.Contains(string(out), want) {
+				t.Errorf("%s: %q lost from\n%s", tc.name, want, out)
+			}
+		}
+		var loaded Config
+		if err := decodeConfig(tc.name, out, &loaded); err != nil {
+			t.Fatalf("%s: %v\n%s", tc.name, err, out)
+		}
+		if !reflect.DeepEqual(loaded, cfg) {
+			t.Errorf("%s:\n got %+v\nwant %+v\n%s", tc.name, loaded, cfg, out)
+		}
+	}
+}
+
+func TestLoadSaveTOML(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("APPDATA", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+
+	configDir, err := GetConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(configDir, "config.toml")
+	if err := os.WriteFile(path, []byte(tomlConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, _ := GetConfigPath(); got != path {
+		t.Errorf("GetConfigPath = %q, want %q", got, path)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Player = "mpv"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Saved back as TOML, not into a new config.json that would shadow it.
+	if _, err := os.Stat(filepath.Join(configDir, "config.json")); !os.IsNotExist(err) {
+		t.Errorf("Save created config.json next to config.toml")
+	}
+	loaded, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Player != "mpv" || len(loaded.Servers) != 2 {
+		t.Errorf("after Save: player = %q, %d servers", loaded.Player, len(loaded.Servers))
+	}
+	if data, _ := os.ReadFile(path); !strings.HasPrefix(string(data), "# goplexcli config\n") {
+		t.Errorf("Save dropped the file's comments:\n%s", data)
+	}
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// encodeTOML renders cfg as TOML, carrying over old's comments: the
+// comment lines above each key or table header, and a comment after one,
+// stay with that key.
+func encodeTOML(cfg *Config, old []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := toml.NewEncoder(&buf)
+	enc.Indent = ""
+	if err := enc.Encode(cfg); err != nil {
+		return nil, err
+	}
+	if len(old) == 0 {
+		return buf.Bytes(), nil
+	}
+
+	above := map[string][]string{}
+	after := map[string]string{}
+	var pending []string
+	tomlLines(old, func(id, line string) {
+		if id == "" {
+			if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "#") || trimmed == "" && len(pending) > 0 {
+				pending = append(pending, line)
+			}
+			return
+		}
+		above[id] = pending
+		pending = nil
+		if _, comment := cutComment(line); comment != "" {
+			after[id] = comment
+		}
+	})
+
+	var out bytes.Buffer
+	tomlLines(buf.Bytes(), func(id, line string) {
+		for _, c := range above[id] {
+			out.WriteString(c + "\n")
+		}
+		if c := after[id]; c != "" {
+			line += " " + c
+		}
+		out.WriteString(line + "\n")
+	})
+	for _, c := range pending {
+		out.WriteString(c + "\n")
+	}
+	return out.Bytes(), nil
+}
+
+// tomlKey matches the key at the start of a key/value line.
+var tomlKey = regexp.MustCompile(`^\s*([A-Za-z0-9_-]+|"[^"]*")\s*=`)
+
+// tomlLines calls fn for each line of a TOML document with an id naming
+// the key or table header on it, or "" for other lines. An id is the
+// table plus the key, and repeated [[array]] headers are numbered, so the
+// same setting has the same id in any rendering.
+func tomlLines(data []byte, fn func(id, line string)) {
+	var table string
+	seen := map[string]int{}
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		var id string
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "[") {
+			header, _ := cutComment(trimmed)
+			header = strings.Join(strings.Fields(header), "")
+			seen[header]++
+			table = fmt.Sprintf("%s#%d", header, seen[header])
+			id = table
+		} else if m := tomlKey.FindStringSubmatch(line); m != nil {
+			id = table + " " + strings.Trim(m[1], `"`)
+		}
+		fn(id, line)
+	}
+}
+
+// cutComment splits a TOML line at a # outside quotes.
+func cutComment(line string) (value, comment string) {
+	var quote rune
+	escaped := false
+	for i, r := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && r == '\\':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return strings.TrimRight(line[:i], " \t"), line[i:]
+		}
+	}
+	return line, ""
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"bytes"
+
+	"gopkg.in/yaml.v3"
+)
+
+// encodeYAML renders cfg as YAML. Given old, the file being replaced, it
+// updates old's document in place instead, so its comments, key order and
+// quoting survive wherever the value they belong to does.
+func encodeYAML(cfg *Config, old []byte) ([]byte, error) {
+	var updated yaml.Node
+	if err := updated.Encode(cfg); err != nil {
+		return nil, err
+	}
+
+	doc := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{&updated}}
+	var existing yaml.Node
+	if yaml.Unmarshal(old, &existing) == nil && len(existing.Content) == 1 {
+		existing.Content[0] = mergeYAML(existing.Content[0], &updated)
+		doc = &existing
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// mergeYAML returns old with the values of updated. Nodes of the same kind
+// are updated in place, keeping old's comments and style; mapping keys
+// keep old's order, with new ones appended and missing ones dropped.
+func mergeYAML(old, updated *yaml.Node) *yaml.Node {
+	if old.Kind != updated.Kind {
+		return updated
+	}
+	switch old.Kind {
+	case yaml.MappingNode:
+		var content []*yaml.Node
+		for i := 0; i+1 < len(old.Content); i += 2 {
+			if j := yamlKeyIndex(updated, old.Content[i].Value); j >= 0 {
+				content = append(content, old.Content[i], mergeYAML(old.Content[i+1], updated.Content[j+1]))
+			}
+		}
+		for i := 0; i+1 < len(updated.Content); i += 2 {
+			if yamlKeyIndex(old, updated.Content[i].Value) < 0 {
+				content = append(content, updated.Content[i], updated.Content[i+1])
+			}
+		}
+		old.Content = content
+	case yaml.SequenceNode:
+		for i := range updated.Content {
+			if i < len(old.Content) {
+				updated.Content[i] = mergeYAML(old.Content[i], updated.Content[i])
+			}
+		}
+		old.Content = updated.Content
+	case yaml.ScalarNode:
+		if old.Value != updated.Value || old.ShortTag() != updated.ShortTag() {
+			old.Value, old.Tag, old.Style = updated.Value, updated.Tag, updated.Style
+		}
+	default:
+		return updated
+	}
+	return old
+}
+
+// yamlKeyIndex returns the index of key in mapping's content, or -1.
+func yamlKeyIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
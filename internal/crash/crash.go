@@ -0,0 +1,117 @@
+// Package crash writes structured crash reports when goplexcli panics. A report
+// captures the panic value, stack trace, build/runtime details, a sanitized
+// summary of the config (never tokens or passwords), and the most recent log
+// lines, so a bug report can be acted on without a back-and-forth.
+package crash
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/config"
+)
+
+// Report is everything recorded about a single crash.
+type Report struct {
+	Panic   any
+	Stack   []byte
+	Version string
+	Args    []string
+	// Config may be nil if it couldn't be loaded; only a summary is written.
+	Config *config.Config
+	Logs   []string
+	Time   time.Time
+}
+
+// GetCrashDir returns the directory crash reports are written to.
+func GetCrashDir() (string, error) {
+	cacheDir, err := config.GetCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "crashes"), nil
+}
+
+// Write saves the report as a text file in the crash directory and returns its
+// path.
+func Write(r Report) (string, error) {
+	dir, err := GetCrashDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("crash-%s.txt", r.Time.Format("20060102-150405")))
+	if err := os.WriteFile(path, []byte(r.Format()), 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// Format renders the report as plain text suitable for pasting into an issue.
+func (r Report) Format() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "goplexcli crash report\n")
+	fmt.Fprintf(&b, "time:    %s\n", r.Time.Format(time.RFC3339))
+	fmt.Fprintf(&b, "version: %s\n", r.Version)
+	fmt.Fprintf(&b, "go:      %s\n", runtime.Version())
+	fmt.Fprintf(&b, "os/arch: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	if len(r.Args) > 0 {
+		fmt.Fprintf(&b, "command: %s\n", strings.Join(r.Args, " "))
+	}
+
+	fmt.Fprintf(&b, "\npanic: %v\n\n", r.Panic)
+	b.Write(r.Stack)
+
+	b.WriteString("\n\nconfig:\n")
+	b.WriteString(ConfigSummary(r.Config))
+
+	b.WriteString("\nrecent log:\n")
+	if len(r.Logs) == 0 {
+		b.WriteString("  (none)\n")
+	}
+	for _, line := range r.Logs {
+		fmt.Fprintf(&b, "  %s\n", line)
+	}
+
+	return b.String()
+}
+
+// ConfigSummary describes the shape of the config without any secrets or
+// server addresses: counts, which tool paths are overridden, and which
+// optional features are switched on.
+func ConfigSummary(cfg *config.Config) string {
+	if cfg == nil {
+		return "  (not loaded)\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "  profile set: %t\n", config.Profile() != "")
+	fmt.Fprintf(&b, "  servers: %d (%d enabled)\n", len(cfg.Servers), len(cfg.GetEnabledServers()))
+	fmt.Fprintf(&b, "  token set: %t\n", cfg.PlexToken != "")
+	fmt.Fprintf(&b, "  home user set: %t\n", cfg.HomeUser != "")
+	fmt.Fprintf(&b, "  player: %s\n", cfg.PlayerName())
+	fmt.Fprintf(&b, "  skip intros: %t\n", cfg.SkipIntros)
+	fmt.Fprintf(&b, "  stream auth: %t, tls: %t, proxy: %t, remote control: %t\n", cfg.StreamAuth || cfg.StreamToken != "", cfg.StreamTLS || cfg.StreamCertFile != "", cfg.StreamProxy, cfg.RemoteControl)
+	fmt.Fprintf(&b, "  custom paths: mpv=%t vlc=%t iina=%t rclone=%t fzf=%t ffmpeg=%t\n", cfg.MPVPath != "", cfg.VLCPath != "", cfg.IINAPath != "", cfg.RclonePath != "", cfg.FzfPath != "", cfg.FFmpegPath != "")
+	fmt.Fprintf(&b, "  download dir set: %t\n", cfg.DownloadDir != "")
+	fmt.Fprintf(&b, "  download concurrency: %d\n", cfg.GetDownloadConcurrency())
+	fmt.Fprintf(&b, "  naming templates: movie=%t episode=%t\n", cfg.MovieTemplate != "", cfg.EpisodeTemplate != "")
+	fmt.Fprintf(&b, "  post-download hook: %t\n", cfg.PostDownloadCmd != "")
+	fmt.Fprintf(&b, "  verify hash: %t\n", cfg.VerifyHash)
+	fmt.Fprintf(&b, "  usage stats: %t\n", cfg.UsageStats)
+	fmt.Fprintf(&b, "  timezone set: %t\n", cfg.Timezone != "")
+	fmt.Fprintf(&b, "  path mappings: %d\n", len(cfg.PathMappings))
+	fmt.Fprintf(&b, "  webdav targets: %d, outplayer targets: %d\n", len(cfg.WebDAVTargets), len(cfg.OutplayerTargets))
+	return b.String()
+}
@@ -0,0 +1,63 @@
+package crash
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/config"
+)
+
+func TestFormatOmitsSecrets(t *testing.T) {
+	cfg := &config.Config{
+		PlexToken:  "super-secret-token",
+		WebDAVPass: "hunter2",
+		Servers: []config.PlexServer{
+			{Name: "Home", URL: "http://10.0.0.5:32400", Token: "server-token", Enabled: true},
+		},
+	}
+	r := Report{
+		Panic:   "boom",
+		Stack:   []byte("goroutine 1 [running]:\nmain.main()"),
+		Version: "1.2.3",
+		Config:  cfg,
+		Logs:    []string{"level=WARN msg=something"},
+		Time:    time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	out := r.Format()
+
+	for _, want := range []string{"panic: boom", "version: 1.2.3", "main.main()", "servers: 1 (1 enabled)", "level=WARN msg=something"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+	for _, secret := range []string{"super-secret-token", "server-token", "hunter2", "10.0.0.5"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("report leaked %q:\n%s", secret, out)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("APPDATA", dir)
+
+	path, err := Write(Report{Panic: "boom", Version: "dev"})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading report: %v", err)
+	}
+	if !strings.Contains(string(data), "panic: boom") {
+		t.Errorf("written report missing panic value:\n%s", data)
+	}
+	if !strings.Contains(string(data), "(not loaded)") {
+		t.Errorf("nil config should be reported as not loaded:\n%s", data)
+	}
+}
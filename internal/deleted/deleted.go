@@ -0,0 +1,167 @@
+// Package deleted keeps a local log of media deleted from Plex through
+// goplexcli, with enough metadata (title, year, external IDs, file path) to
+// find and re-acquire an item removed by mistake. It lives in a JSON file
+// next to the media cache; 'goplexcli deleted list' and 'deleted export'
+// read it.
+package deleted
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/config"
+	"github.com/joshkerr/goplexcli/internal/plex"
+	"github.com/joshkerr/goplexcli/internal/storage"
+)
+
+// schemaVersion is the log file format this build reads and writes.
+const schemaVersion = 1
+
+// Entry is a snapshot of one deleted item, taken just before deletion.
+type Entry struct {
+	DeletedAt  int64    `json:"deleted_at"` // unix seconds
+	Key        string   `json:"key"`
+	Server     string   `json:"server,omitempty"`
+	Type       string   `json:"type"`
+	Title      string   `json:"title"`
+	Show       string   `json:"show,omitempty"`
+	Season     int64    `json:"season,omitempty"`
+	Episode    int64    `json:"episode,omitempty"`
+	Year       int      `json:"year,omitempty"`
+	GUIDs      []string `json:"guids,omitempty"` // e.g. "imdb://tt0113277"
+	FilePath   string   `json:"file_path,omitempty"`
+	RclonePath string   `json:"rclone_path,omitempty"`
+	Size       int64    `json:"size,omitempty"`
+}
+
+// FromItem snapshots item as deleted at the given time. guids are the
+// item's external IDs, if they could be fetched.
+func FromItem(item *plex.MediaItem, guids []string, at time.Time) Entry {
+	e := Entry{
+		DeletedAt:  at.Unix(),
+		Key:        item.Key,
+		Server:     item.ServerName,
+		Type:       item.Type,
+		Title:      item.Title,
+		Year:       item.Year,
+		GUIDs:      guids,
+		FilePath:   item.FilePath,
+		RclonePath: item.RclonePath,
+		Size:       item.Size,
+	}
+	if item.Type == "episode" {
+		e.Show, e.Season, e.Episode = item.ParentTitle, item.ParentIndex, item.Index
+	}
+	return e
+}
+
+// DisplayTitle formats the entry like plex.MediaItem.FormatMediaTitle.
+func (e Entry) DisplayTitle() string {
+	if e.Type == "episode" {
+		return fmt.Sprintf("%s - S%02dE%02d - %s", e.Show, e.Season, e.Episode, e.Title)
+	}
+	if e.Year > 0 {
+		return fmt.Sprintf("%s (%d)", e.Title, e.Year)
+	}
+	return e.Title
+}
+
+// Log is the full deletion log, oldest entry first.
+type Log struct {
+	Version int     `json:"version"`
+	Entries []Entry `json:"entries"`
+}
+
+// Add appends entries. Unlike the playback history the log isn't capped:
+// deletions are rare and each one may be the only record of an item.
+func (l *Log) Add(entries ...Entry) {
+	l.Version = schemaVersion
+	l.Entries = append(l.Entries, entries...)
+}
+
+// Path returns the JSON file holding the log, alongside the media cache.
+func Path() (string, error) {
+	dir, err := config.GetCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "deleted.json"), nil
+}
+
+// SchemaVersion implements storage.Versioned.
+func (l *Log) SchemaVersion() int { return l.Version }
+
+// file returns the log stored at path.
+func file(path string) storage.File[Log] {
+	return storage.File[Log]{
+		Path:    path,
+		Name:    "deletion log",
+		Indent:  true,
+		Version: schemaVersion,
+		New:     func() *Log { return &Log{Version: schemaVersion} },
+	}
+}
+
+// LoadFrom reads the log at path. A missing file yields an empty log.
+func LoadFrom(path string) (*Log, error) {
+	l, _, err := file(path).Load()
+	return l, err
+}
+
+// SaveTo writes the log to path atomically.
+func (l *Log) SaveTo(path string) error {
+	return file(path).Save(l)
+}
+
+// Load reads the default log file.
+func Load() (*Log, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	return LoadFrom(path)
+}
+
+// Record appends entries to the default log file under its lock. A corrupt
+// file is reported rather than replaced.
+func Record(entries ...Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	return file(path).Update(func(l *Log) error {
+		l.Add(entries...)
+		return nil
+	})
+}
+
+// WriteCSV writes entries as CSV with a header row, one item per line and
+// external IDs space-separated, for spreadsheets and import tools.
+func WriteCSV(w io.Writer, entries []Entry) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"deleted_at", "type", "title", "year", "show", "season", "episode", "guids", "file_path", "server"})
+	for _, e := range entries {
+		var season, episode, year string
+		if e.Type == "episode" {
+			season, episode = strconv.FormatInt(e.Season, 10), strconv.FormatInt(e.Episode, 10)
+		}
+		if e.Year > 0 {
+			year = strconv.Itoa(e.Year)
+		}
+		_ = cw.Write([]string{
+			time.Unix(e.DeletedAt, 0).UTC().Format(time.RFC3339),
+			e.Type, e.Title, year, e.Show, season, episode,
+			strings.Join(e.GUIDs, " "), e.FilePath, e.Server,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,93 @@
+package deleted
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+	"github.com/joshkerr/goplexcli/internal/storage"
+)
+
+func TestFromItem(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	ep := &plex.MediaItem{
+		Key: "/library/metadata/9", Type: "episode", Title: "The Dundies",
+		ParentTitle: "The Office", ParentIndex: 2, Index: 1, FilePath: "/tv/office/s02e01.mkv",
+	}
+	e := FromItem(ep, []string{"tvdb://12345"}, at)
+	if e.Show != "The Office" || e.Season != 2 || e.Episode != 1 || e.DeletedAt != at.Unix() {
+		t.Errorf("unexpected episode entry %+v", e)
+	}
+	if got := e.DisplayTitle(); got != "The Office - S02E01 - The Dundies" {
+		t.Errorf("DisplayTitle() = %q", got)
+	}
+
+	movie := FromItem(&plex.MediaItem{Type: "movie", Title: "Heat", Year: 1995, ParentTitle: "ignored"}, nil, at)
+	if movie.Show != "" {
+		t.Errorf("movies should not carry a show, got %q", movie.Show)
+	}
+	if got := movie.DisplayTitle(); got != "Heat (1995)" {
+		t.Errorf("DisplayTitle() = %q", got)
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deleted.json")
+
+	l, err := LoadFrom(path)
+	if err != nil || len(l.Entries) != 0 {
+		t.Fatalf("LoadFrom(missing) = %+v, %v; want an empty log", l, err)
+	}
+
+	l.Add(Entry{Key: "/library/metadata/1", Type: "movie", Title: "Heat", GUIDs: []string{"imdb://tt0113277"}})
+	if err := l.SaveTo(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadFrom(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Entries) != 1 || got.Entries[0].GUIDs[0] != "imdb://tt0113277" {
+		t.Errorf("round trip lost data: %+v", got.Entries)
+	}
+}
+
+func TestLoadFromNewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deleted.json")
+	if err := os.WriteFile(path, []byte(`{"version": 99, "entries": []}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFrom(path); !errors.Is(err, storage.ErrNewerSchema) {
+		t.Errorf("LoadFrom(newer) error = %v, want ErrNewerSchema", err)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteCSV(&buf, []Entry{
+		{DeletedAt: 0, Type: "movie", Title: "Heat, the movie", Year: 1995, GUIDs: []string{"imdb://tt0113277", "tmdb://949"}},
+		{DeletedAt: 0, Type: "episode", Title: "Pilot", Show: "The Office", Season: 1, Episode: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(rows) != 3 || rows[0][0] != "deleted_at" {
+		t.Fatalf("want a header and two rows, got %q", rows)
+	}
+	if rows[1][2] != "Heat, the movie" || rows[1][3] != "1995" || rows[1][7] != "imdb://tt0113277 tmdb://949" || rows[1][5] != "" {
+		t.Errorf("unexpected movie row %q", rows[1])
+	}
+	if rows[2][4] != "The Office" || rows[2][5] != "1" || rows[2][6] != "1" {
+		t.Errorf("unexpected episode row %q", rows[2])
+	}
+}
@@ -0,0 +1,36 @@
+//go:build !windows
+
+package discord
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// dial connects to the first Discord IPC socket found. Discord creates
+// discord-ipc-0 through -9 in the runtime or temp directory, or inside the
+// Flatpak or Snap sandbox directory there.
+func dial() (net.Conn, error) {
+	var dirs []string
+	for _, env := range []string{"XDG_RUNTIME_DIR", "TMPDIR", "TMP", "TEMP"} {
+		if d := os.Getenv(env); d != "" {
+			dirs = append(dirs, d)
+		}
+	}
+	dirs = append(dirs, "/tmp")
+
+	for _, dir := range dirs {
+		for _, sub := range []string{"", "app/com.discordapp.Discord", "snap.discord"} {
+			for i := 0; i < 10; i++ {
+				path := filepath.Join(dir, sub, "discord-ipc-"+strconv.Itoa(i))
+				if conn, err := net.Dial("unix", path); err == nil {
+					return conn, nil
+				}
+			}
+		}
+	}
+	return nil, errors.New("Discord is not running")
+}
@@ -0,0 +1,41 @@
+//go:build windows
+
+package discord
+
+import (
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// pipeConn wraps an os.File to implement net.Conn for Windows named pipes.
+// The pipe is opened for synchronous I/O, which has no deadlines; Client
+// bounds each exchange itself.
+type pipeConn struct {
+	*os.File
+}
+
+func (p *pipeConn) LocalAddr() net.Addr                { return pipeAddr{p.Name()} }
+func (p *pipeConn) RemoteAddr() net.Addr               { return pipeAddr{p.Name()} }
+func (p *pipeConn) SetDeadline(t time.Time) error      { return nil }
+func (p *pipeConn) SetReadDeadline(t time.Time) error  { return nil }
+func (p *pipeConn) SetWriteDeadline(t time.Time) error { return nil }
+
+type pipeAddr struct{ name string }
+
+func (a pipeAddr) Network() string { return "pipe" }
+func (a pipeAddr) String() string  { return a.name }
+
+// dial connects to the first of Discord's named pipes, discord-ipc-0
+// through -9, that is open.
+func dial() (net.Conn, error) {
+	for i := 0; i < 10; i++ {
+		file, err := os.OpenFile(`\\.\pipe\discord-ipc-`+strconv.Itoa(i), os.O_RDWR, 0)
+		if err == nil {
+			return &pipeConn{file}, nil
+		}
+	}
+	return nil, errors.New("Discord is not running")
+}
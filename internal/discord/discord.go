@@ -0,0 +1,261 @@
+// Package discord shows what is playing as the user's Discord Rich Presence.
+// It talks to the Discord desktop app over its local IPC socket (a Unix
+// socket, or a named pipe on Windows); nothing goes over the network.
+package discord
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+// IPC frame opcodes.
+const (
+	opHandshake = 0
+	opFrame     = 1
+	opClose     = 2
+)
+
+// activityWatching is Discord's activity type for "Watching <app>".
+const activityWatching = 3
+
+// replyTimeout bounds each exchange with Discord, so a stale socket can't
+// hold up playback or progress reporting. A variable so tests can shorten
+// it.
+var replyTimeout = 5 * time.Second
+
+// maxFrameSize guards against a corrupt length prefix.
+const maxFrameSize = 64 << 10
+
+// maxFieldLen is the longest details or state string Discord accepts.
+const maxFieldLen = 128
+
+// Client is a connection to the running Discord app.
+type Client struct {
+	mu    sync.Mutex
+	conn  net.Conn
+	nonce int
+	// dead is set once Discord misses a reply; the connection is closed
+	// and every later update fails straight away.
+	dead bool
+}
+
+// Connect dials the Discord app and identifies as the Discord application
+// clientID, whose name is shown as "Watching <name>". It fails if Discord
+// isn't running.
+func Connect(clientID string) (*Client, error) {
+	conn, err := dial()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Discord: %w", err)
+	}
+	return newClient(conn, clientID)
+}
+
+// newClient performs the handshake on conn, closing it on failure.
+func newClient(conn net.Conn, clientID string) (*Client, error) {
+	c := &Client{conn: conn}
+	if err := c.exchange(func() error { return c.handshake(clientID) }); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to connect to Discord: %w", err)
+	}
+	return c, nil
+}
+
+// exchange runs fn, which talks to Discord, giving up after replyTimeout.
+// Named pipes on Windows have no deadlines, so a Discord that accepts the
+// connection but never answers would block a read forever; fn runs on its
+// own goroutine instead, and on timeout the connection is closed, which
+// ends the read where the platform allows, and the client marked dead.
+// Callers hold c.mu.
+func (c *Client) exchange(fn func() error) error {
+	if c.dead {
+		return fmt.Errorf("Discord stopped responding")
+	}
+	done := make(chan error, 1)
+	go func() { done <- fn() }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(replyTimeout):
+		c.dead = true
+		_ = c.conn.Close()
+		return fmt.Errorf("no reply from Discord within %v", replyTimeout)
+	}
+}
+
+func (c *Client) handshake(clientID string) error {
+	if err := c.send(opHandshake, map[string]any{"v": 1, "client_id": clientID}); err != nil {
+		return err
+	}
+	var ready struct {
+		Evt string `json:"evt"`
+	}
+	if err := c.receive(&ready); err != nil {
+		return err
+	}
+	if ready.Evt != "READY" {
+		return fmt.Errorf("unexpected %q event", ready.Evt)
+	}
+	return nil
+}
+
+// Activity is what Discord shows under the user's name.
+type Activity struct {
+	Details string    // first line, e.g. "Watching Heat (1995)"
+	State   string    // second line, e.g. "42:10/2:50:00"
+	Start   time.Time // when set, Discord shows the time elapsed since
+}
+
+// SetActivity replaces the user's activity; nil clears it.
+func (c *Client) SetActivity(a *Activity) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.exchange(func() error { return c.setActivity(a) }); err != nil {
+		return fmt.Errorf("failed to set Discord activity: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) setActivity(a *Activity) error {
+	var activity any
+	if a != nil {
+		act := map[string]any{
+			"type":    activityWatching,
+			"details": truncate(a.Details),
+			"state":   truncate(a.State),
+		}
+		if !a.Start.IsZero() {
+			act["timestamps"] = map[string]int64{"start": a.Start.Unix()}
+		}
+		activity = act
+	}
+	c.nonce++
+	err := c.send(opFrame, map[string]any{
+		"cmd":   "SET_ACTIVITY",
+		"args":  map[string]any{"pid": os.Getpid(), "activity": activity},
+		"nonce": strconv.Itoa(c.nonce),
+	})
+	if err != nil {
+		return err
+	}
+
+	var reply struct {
+		Evt  string `json:"evt"`
+		Data struct {
+			Message string `json:"message"`
+		} `json:"data"`
+	}
+	if err := c.receive(&reply); err != nil {
+		return err
+	}
+	if reply.Evt == "ERROR" {
+		return fmt.Errorf("%s", reply.Data.Message)
+	}
+	return nil
+}
+
+// Close disconnects from Discord, which clears the activity.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.dead {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// send writes one frame: opcode and payload length, little-endian, then the
+// JSON payload.
+func (c *Client) send(op uint32, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	frame := make([]byte, 8+len(data))
+	binary.LittleEndian.PutUint32(frame[0:], op)
+	binary.LittleEndian.PutUint32(frame[4:], uint32(len(data)))
+	copy(frame[8:], data)
+	_, err = c.conn.Write(frame)
+	return err
+}
+
+// receive reads one frame into v. A close frame becomes an error carrying
+// Discord's reason, such as an unknown client ID.
+func (c *Client) receive(v any) error {
+	var header [8]byte
+	if _, err := io.ReadFull(c.conn, header[:]); err != nil {
+		return err
+	}
+	op := binary.LittleEndian.Uint32(header[0:])
+	n := binary.LittleEndian.Uint32(header[4:])
+	if n > maxFrameSize {
+		return fmt.Errorf("frame too large (%d bytes)", n)
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(c.conn, body); err != nil {
+		return err
+	}
+	if op == opClose {
+		var reason struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(body, &reason)
+		return fmt.Errorf("Discord closed the connection: %s (code %d)", reason.Message, reason.Code)
+	}
+	return json.Unmarshal(body, v)
+}
+
+// Watching describes playback of item at posMs for Discord, e.g. "Watching
+// Breaking Bad (S02E03)" over "42:10/58:00". state is the tracker's
+// "playing", "paused" or "stopped"; stopped returns nil, clearing the
+// activity.
+func Watching(item *plex.MediaItem, posMs int, state string) *Activity {
+	if item == nil || state == "stopped" {
+		return nil
+	}
+	a := &Activity{Details: "Watching " + item.Title}
+	switch {
+	case item.Type == "episode" && item.ParentTitle != "":
+		a.Details = fmt.Sprintf("Watching %s (S%02dE%02d)", item.ParentTitle, item.ParentIndex, item.Index)
+	case item.Year > 0:
+		a.Details = fmt.Sprintf("Watching %s (%d)", item.Title, item.Year)
+	}
+
+	a.State = clock(posMs)
+	if item.Duration > 0 {
+		a.State += "/" + clock(item.Duration)
+	}
+	if state == "paused" {
+		a.State = "Paused at " + a.State
+	} else {
+		a.Start = time.Now().Add(-time.Duration(posMs) * time.Millisecond)
+	}
+	return a
+}
+
+// clock formats milliseconds as M:SS, or H:MM:SS from an hour up.
+func clock(ms int) string {
+	s := ms / 1000
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s%3600/60, s%60)
+	}
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+// truncate shortens s to Discord's field limit.
+func truncate(s string) string {
+	r := []rune(s)
+	if len(r) <= maxFieldLen {
+		return s
+	}
+	return string(r[:maxFieldLen-1]) + "…"
+}
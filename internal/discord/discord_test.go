@@ -0,0 +1,165 @@
+package discord
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+// fakeDiscord answers the handshake on conn, then replies to each command
+// with reply and sends the decoded commands on got.
+func fakeDiscord(t *testing.T, conn net.Conn, got chan<- map[string]any, reply func(cmd map[string]any) (uint32, any)) {
+	t.Helper()
+	read := func() (uint32, map[string]any, error) {
+		var header [8]byte
+		if _, err := io.ReadFull(conn, header[:]); err != nil {
+			return 0, nil, err
+		}
+		body := make([]byte, binary.LittleEndian.Uint32(header[4:]))
+		if _, err := io.ReadFull(conn, body); err != nil {
+			return 0, nil, err
+		}
+		var v map[string]any
+		return binary.LittleEndian.Uint32(header[:]), v, json.Unmarshal(body, &v)
+	}
+	write := func(op uint32, v any) {
+		data, _ := json.Marshal(v)
+		frame := binary.LittleEndian.AppendUint32(nil, op)
+		frame = binary.LittleEndian.AppendUint32(frame, uint32(len(data)))
+		conn.Write(append(frame, data...))
+	}
+
+	go func() {
+		defer conn.Close()
+		op, hello, err := read()
+		if err != nil || op != opHandshake {
+			return
+		}
+		if hello["client_id"] != "123" {
+			write(opClose, map[string]any{"code": 4000, "message": "Invalid Client ID"})
+			return
+		}
+		write(opFrame, map[string]any{"cmd": "DISPATCH", "evt": "READY"})
+		for {
+			_, cmd, err := read()
+			if err != nil {
+				return
+			}
+			got <- cmd
+			write(reply(cmd))
+		}
+	}()
+}
+
+func TestClient(t *testing.T) {
+	ok := func(cmd map[string]any) (uint32, any) {
+		return opFrame, map[string]any{"cmd": "SET_ACTIVITY", "nonce": cmd["nonce"]}
+	}
+
+	server, conn := net.Pipe()
+	got := make(chan map[string]any, 4)
+	fakeDiscord(t, server, got, ok)
+	c, err := newClient(conn, "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	start := time.Unix(1700000000, 0)
+	if err := c.SetActivity(&Activity{Details: "Watching Heat (1995)", State: "1:00/2:50:00", Start: start}); err != nil {
+		t.Fatal(err)
+	}
+	cmd := <-got
+	activity := cmd["args"].(map[string]any)["activity"].(map[string]any)
+	if cmd["cmd"] != "SET_ACTIVITY" || activity["details"] != "Watching Heat (1995)" || activity["type"] != float64(activityWatching) {
+		t.Errorf("SET_ACTIVITY = %v", cmd)
+	}
+	if ts := activity["timestamps"].(map[string]any); ts["start"] != float64(start.Unix()) {
+		t.Errorf("timestamps = %v", ts)
+	}
+
+	if err := c.SetActivity(nil); err != nil {
+		t.Fatal(err)
+	}
+	if cmd := <-got; cmd["args"].(map[string]any)["activity"] != nil {
+		t.Errorf("clearing should send a null activity, got %v", cmd)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	server, conn := net.Pipe()
+	fakeDiscord(t, server, nil, nil)
+	if _, err := newClient(conn, "999"); err == nil || !strings.Contains(err.Error(), "Invalid Client ID") {
+		t.Errorf("unknown client ID: err = %v", err)
+	}
+
+	server, conn = net.Pipe()
+	got := make(chan map[string]any, 1)
+	fakeDiscord(t, server, got, func(map[string]any) (uint32, any) {
+		return opFrame, map[string]any{"evt": "ERROR", "data": map[string]any{"message": "child \"activity\" fails"}}
+	})
+	c, err := newClient(conn, "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if err := c.SetActivity(&Activity{Details: "x"}); err == nil || !strings.Contains(err.Error(), "fails") {
+		t.Errorf("rejected activity: err = %v", err)
+	}
+}
+
+func TestWatching(t *testing.T) {
+	ep := &plex.MediaItem{Type: "episode", Title: "Bit by a Dead Bee", ParentTitle: "Breaking Bad", ParentIndex: 2, Index: 3, Duration: 58 * 60 * 1000}
+	a := Watching(ep, (42*60+10)*1000, "playing")
+	if a.Details != "Watching Breaking Bad (S02E03)" || a.State != "42:10/58:00" || a.Start.IsZero() {
+		t.Errorf("episode activity = %+v", a)
+	}
+
+	movie := &plex.MediaItem{Type: "movie", Title: "Heat", Year: 1995, Duration: 170 * 60 * 1000}
+	a = Watching(movie, 65*60*1000, "paused")
+	if a.Details != "Watching Heat (1995)" || a.State != "Paused at 1:05:00/2:50:00" || !a.Start.IsZero() {
+		t.Errorf("paused movie activity = %+v", a)
+	}
+
+	if Watching(movie, 0, "stopped") != nil {
+		t.Error("stopped playback should clear the activity")
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	defer func(d time.Duration) { replyTimeout = d }(replyTimeout)
+	replyTimeout = 50 * time.Millisecond
+
+	// A Discord that accepts the connection but never answers.
+	server, conn := net.Pipe()
+	go io.Copy(io.Discard, server)
+	if _, err := newClient(conn, "123"); err == nil || !strings.Contains(err.Error(), "no reply") {
+		t.Errorf("silent handshake: err = %v", err)
+	}
+
+	server, conn = net.Pipe()
+	got := make(chan map[string]any, 1)
+	fakeDiscord(t, server, got, func(map[string]any) (uint32, any) {
+		select {} // never replies
+	})
+	c, err := newClient(conn, "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetActivity(&Activity{Details: "x"}); err == nil || !strings.Contains(err.Error(), "no reply") {
+		t.Errorf("silent update: err = %v", err)
+	}
+	start := time.Now()
+	if err := c.SetActivity(nil); err == nil || time.Since(start) > replyTimeout {
+		t.Errorf("update after a timeout should fail at once: err = %v after %v", err, time.Since(start))
+	}
+	if err := c.Close(); err != nil {
+		t.Errorf("Close() = %v", err)
+	}
+}
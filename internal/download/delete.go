@@ -0,0 +1,31 @@
+package download
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/joshkerr/goplexcli/internal/logging"
+)
+
+// DeleteRemote deletes one file from an rclone remote with `rclone
+// deletefile`. It never removes directories.
+func DeleteRemote(ctx context.Context, rclonePath, rcloneBinary string) error {
+	if rclonePath == "" {
+		return fmt.Errorf("rclone path is empty")
+	}
+	if rcloneBinary == "" {
+		rcloneBinary = "rclone"
+	}
+
+	logging.Debug("running rclone", "command", "deletefile", "path", rclonePath)
+	out, err := exec.CommandContext(ctx, rcloneBinary, "deletefile", rclonePath).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("rclone deletefile failed: %w: %s", err, msg)
+		}
+		return fmt.Errorf("rclone deletefile failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package download
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+// FreeSpace returns the bytes available on the filesystem that dir is (or
+// will be) created on. dir doesn't need to exist yet: the nearest existing
+// ancestor is measured instead.
+func FreeSpace(dir string) (int64, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		if _, err := os.Stat(abs); err == nil {
+			break
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			break
+		}
+		abs = parent
+	}
+	free, err := freeSpace(abs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read free space for %s: %w", abs, err)
+	}
+	return free, nil
+}
+
+// CheckFreeSpace verifies dir has room for required bytes, returning an error
+// that states both the requirement and what's available when it doesn't.
+func CheckFreeSpace(dir string, required int64) error {
+	if required <= 0 {
+		return nil
+	}
+	free, err := FreeSpace(dir)
+	if err != nil {
+		return err
+	}
+	if free < required {
+		return fmt.Errorf("not enough free space in %s: need %s, have %s",
+			dir, plex.FormatSize(required), plex.FormatSize(free))
+	}
+	return nil
+}
+
+// TotalSize sums the known file sizes of items. unknown counts items whose
+// size isn't in the cache (indexed before sizes were recorded).
+func TotalSize(items []*plex.MediaItem) (total int64, unknown int) {
+	for _, item := range items {
+		if item.Size > 0 {
+			total += item.Size
+		} else {
+			unknown++
+		}
+	}
+	return total, unknown
+}
@@ -0,0 +1,41 @@
+package download
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+func TestTotalSize(t *testing.T) {
+	items := []*plex.MediaItem{{Size: 100}, {Size: 0}, {Size: 250}}
+
+	total, unknown := TotalSize(items)
+	if total != 350 || unknown != 1 {
+		t.Errorf("TotalSize() = %d, %d; want 350, 1", total, unknown)
+	}
+}
+
+func TestFreeSpaceMissingDir(t *testing.T) {
+	// A not-yet-created download dir measures its nearest existing parent.
+	dir := filepath.Join(t.TempDir(), "not", "created", "yet")
+	free, err := FreeSpace(dir)
+	if err != nil {
+		t.Fatalf("FreeSpace() error = %v", err)
+	}
+	if free <= 0 {
+		t.Errorf("FreeSpace() = %d, want > 0", free)
+	}
+}
+
+func TestCheckFreeSpace(t *testing.T) {
+	dir := t.TempDir()
+	if err := CheckFreeSpace(dir, 1); err != nil {
+		t.Errorf("1 byte should fit: %v", err)
+	}
+	err := CheckFreeSpace(dir, 1<<62)
+	if err == nil || !strings.Contains(err.Error(), "not enough free space") {
+		t.Errorf("expected not-enough-space error, got %v", err)
+	}
+}
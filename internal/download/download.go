@@ -14,6 +14,8 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshkerr/goplexcli/internal/logging"
+	"github.com/joshkerr/goplexcli/internal/ui"
 	rclone "github.com/joshkerr/rclone-golib"
 )
 
@@ -131,7 +133,7 @@ func Download(ctx context.Context, rclonePath, destinationDir, rcloneBinary stri
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		p := tea.NewProgram(rclone.NewModel(manager))
+		p := tea.NewProgram(rclone.NewModel(manager), ui.TeaOptions()...)
 		// Signal that UI is ready
 		close(uiReady)
 		if _, err := p.Run(); err != nil {
@@ -159,6 +161,7 @@ func Download(ctx context.Context, rclonePath, destinationDir, rcloneBinary stri
 	}
 	
 	// Execute the transfer
+	logging.Debug("running rclone", "command", "copyto", "source", rclonePath, "dest", destinationPath)
 	err := executor.Execute(transferID, opts)
 	if err != nil {
 		manager.Fail(transferID, err)
@@ -190,43 +193,116 @@ func DownloadMultiple(ctx context.Context, rclonePaths []string, destinationDir,
 	if len(rclonePaths) == 0 {
 		return fmt.Errorf("no rclone paths provided")
 	}
-	
+
+	// Set destination to current directory if not specified
+	if destinationDir == "" {
+		var err error
+		destinationDir, err = os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+	}
+
+	files := make([]File, len(rclonePaths))
+	for i, rclonePath := range rclonePaths {
+		files[i] = File{Source: rclonePath, Dest: filepath.Join(destinationDir, filepath.Base(rclonePath))}
+	}
+	return DownloadMultipleConcurrent(ctx, files, rcloneBinary, 1, nil)
+}
+
+// File is one transfer: an rclone remote source and the full local path it
+// should be written to. Dest's parent directories are created as needed, which
+// is what lets naming templates organize files into show/season folders.
+type File struct {
+	Source string
+	Dest   string
+
+	// Size is the expected size in bytes. When non-zero, a download whose
+	// local file ends up a different size is reported as failed.
+	Size int64
+
+	// CheckHash additionally compares the local file's MD5 with the one the
+	// remote reports, where the remote supports it (see VerifyFile).
+	CheckHash bool
+}
+
+// checkDests returns an error if two files share a destination. Their
+// transfers would write the same .partial file and resume state, corrupting
+// both.
+func checkDests(files []File) error {
+	seen := make(map[string]bool, len(files))
+	for _, f := range files {
+		dest := filepath.Clean(f.Dest)
+		if seen[dest] {
+			return fmt.Errorf("more than one file downloads to %s", dest)
+		}
+		seen[dest] = true
+	}
+	return nil
+}
+
+// DownloadMultipleConcurrent downloads multiple files, running up to
+// concurrency rclone transfers at once. All transfers share one progress view:
+// the aggregate pending/in-progress/completed/failed counts on top and a row
+// per file below. A concurrency below 1 is treated as 1.
+//
+// onDone, if non-nil, is called once per file as it finishes with the file's
+// index in files and its error (nil on success). Calls are serialized, so the
+// callback doesn't need its own locking.
+//
+// A file that downloads but fails VerifyFile is reported as failed and never
+// moved to its Dest, so callers such as the queue keep it for a retry instead
+// of treating it as done.
+// Files sharing a destination are rejected before anything starts.
+func DownloadMultipleConcurrent(ctx context.Context, files []File, rcloneBinary string, concurrency int, onDone func(index int, err error)) error {
+	return DownloadMultipleGated(ctx, files, rcloneBinary, concurrency, nil, onDone)
+}
+
+// DownloadMultipleGated is DownloadMultipleConcurrent with a gate checked
+// before each file starts, e.g. to hold transfers outside a download window.
+// gate blocks until the file may start; a file whose gate returns an error
+// is reported as failed with it and not transferred. A nil gate lets every
+// file start as soon as a transfer slot is free.
+func DownloadMultipleGated(ctx context.Context, files []File, rcloneBinary string, concurrency int, gate func(ctx context.Context) error, onDone func(index int, err error)) error {
+	if len(files) == 0 {
+		return fmt.Errorf("no rclone paths provided")
+	}
+
 	if rcloneBinary == "" {
 		rcloneBinary = "rclone"
 	}
-	
+
 	// Check if rclone is available
 	if _, err := exec.LookPath(rcloneBinary); err != nil {
 		return fmt.Errorf("rclone not found in PATH. Please install rclone or specify the path in config")
 	}
-	
-	// Set destination to current directory if not specified
-	if destinationDir == "" {
-		var err error
-		destinationDir, err = os.Getwd()
-		if err != nil {
-			return fmt.Errorf("failed to get current directory: %w", err)
+
+	if err := checkDests(files); err != nil {
+		return err
+	}
+
+	// Ensure every destination directory exists
+	for _, f := range files {
+		if err := os.MkdirAll(filepath.Dir(f.Dest), 0755); err != nil {
+			return fmt.Errorf("failed to create destination directory: %w", err)
 		}
 	}
-	
-	// Ensure destination directory exists
-	if err := os.MkdirAll(destinationDir, 0755); err != nil {
-		return fmt.Errorf("failed to create destination directory: %w", err)
+
+	if concurrency < 1 {
+		concurrency = 1
 	}
-	
+
 	// Create transfer manager and executor
 	manager := rclone.NewManager()
-	
+
 	// Add all transfers to manager
 	var transferIDs []string
-	for i, rclonePath := range rclonePaths {
-		filename := filepath.Base(rclonePath)
-		destinationPath := filepath.Join(destinationDir, filename)
-		transferID := generateTransferID(i, filename)
+	for i, f := range files {
+		transferID := generateTransferID(i, filepath.Base(f.Dest))
 		transferIDs = append(transferIDs, transferID)
-		manager.Add(transferID, rclonePath, destinationPath)
+		manager.Add(transferID, f.Source, f.Dest)
 	}
-	
+
 	// Start the Bubble Tea UI for progress in a goroutine
 	var wg sync.WaitGroup
 	var uiErr error
@@ -234,65 +310,125 @@ func DownloadMultiple(ctx context.Context, rclonePaths []string, destinationDir,
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		p := tea.NewProgram(rclone.NewModel(manager))
+		p := tea.NewProgram(rclone.NewModel(manager), ui.TeaOptions()...)
 		// Signal that UI is ready
 		close(uiReady)
 		if _, err := p.Run(); err != nil {
 			uiErr = err
 		}
 	}()
-	
+
 	// Wait for UI to be ready before proceeding
 	<-uiReady
-	
-	// Create executor
+
+	// Create executor. The manager is mutex-guarded, so one executor can be
+	// shared by every worker.
 	executor := rclone.NewExecutor(manager)
-	
-	// Execute transfers sequentially
-	var firstErr error
-	for i, transferID := range transferIDs {
-		manager.Start(transferID)
-		
-		opts := rclone.RcloneOptions{
-			Command:       rclone.RcloneCopyTo,
-			Source:        rclonePaths[i],
-			Destination:   filepath.Join(destinationDir, filepath.Base(rclonePaths[i])),
-			StatsInterval: "500ms",
-			Flags:         []string{"--ignore-checksum"},
-			Context:       ctx,
-		}
-		
-		err := executor.Execute(transferID, opts)
-		if err != nil {
-			manager.Fail(transferID, err)
-			if firstErr == nil {
-				firstErr = err
-			}
-		} else {
-			manager.Complete(transferID)
-			// Set modification time to now instead of preserving server time
-			destPath := filepath.Join(destinationDir, filepath.Base(rclonePaths[i]))
-			now := time.Now()
-			if chErr := os.Chtimes(destPath, now, now); chErr != nil {
-				fmt.Fprintf(os.Stderr, "warning: could not set modification time for %s: %v\n", destPath, chErr)
+
+	// Feed transfer indices to a fixed pool of workers. With concurrency 1
+	// this is the original sequential behaviour.
+	var (
+		errMu    sync.Mutex
+		firstErr error
+		workers  sync.WaitGroup
+	)
+	jobs := make(chan int)
+	for w := 0; w < concurrency; w++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for i := range jobs {
+				transferID := transferIDs[i]
+				destPath := files[i].Dest
+
+				var err error
+				if gate != nil {
+					err = gate(ctx)
+				}
+				if err == nil {
+					manager.Start(transferID)
+					err = transferFile(ctx, executor, manager, transferID, files[i], rcloneBinary)
+				}
+				if err != nil {
+					manager.Fail(transferID, err)
+				} else {
+					manager.Complete(transferID)
+					// Set modification time to now instead of preserving server time
+					now := time.Now()
+					if chErr := os.Chtimes(destPath, now, now); chErr != nil {
+						fmt.Fprintf(os.Stderr, "warning: could not set modification time for %s: %v\n", destPath, chErr)
+					}
+				}
+
+				errMu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				if onDone != nil {
+					onDone(i, err)
+				}
+				errMu.Unlock()
 			}
-		}
+		}()
 	}
-	
+	for i := range transferIDs {
+		jobs <- i
+	}
+	close(jobs)
+	workers.Wait()
+
 	// Wait for UI to finish
 	wg.Wait()
-	
+
 	if uiErr != nil {
 		return fmt.Errorf("UI error: %w", uiErr)
 	}
-	
+
 	if firstErr != nil {
 		return fmt.Errorf("download failed: %w", firstErr)
 	}
-	
+
 	return nil
 }
 
+// transferFile downloads f into its partial file, picking up where an
+// interrupted earlier attempt stopped if one left a partial behind, and moves
+// it into place once complete and verified. rclone can exit cleanly after a
+// truncated transfer, so only a verified file counts as complete. If the
+// transfer fails the partial is kept for next time; if verification fails
+// it is discarded.
+func transferFile(ctx context.Context, executor *rclone.Executor, manager *rclone.Manager, transferID string, f File, rcloneBinary string) error {
+	offset := resumeOffset(f)
+	if err := writePartialState(f); err != nil {
+		return fmt.Errorf("failed to record download state: %w", err)
+	}
+
+	var err error
+	if offset > 0 {
+		err = resumeTransfer(ctx, f, offset, rcloneBinary, func(written int64) {
+			var pct float64
+			if f.Size > 0 {
+				pct = float64(written) * 100 / float64(f.Size)
+			}
+			manager.UpdateProgress(transferID, pct, written, f.Size)
+		})
+	} else {
+		logging.Debug("running rclone", "command", "copyto", "source", f.Source, "dest", partialPath(f.Dest))
+		err = executor.Execute(transferID, rclone.RcloneOptions{
+			Command:       rclone.RcloneCopyTo,
+			Source:        f.Source,
+			Destination:   partialPath(f.Dest),
+			StatsInterval: "500ms",
+			Flags:         transferFlags,
+			Context:       ctx,
+		})
+	}
+	if err != nil {
+		return err
+	}
+	return finishVerified(ctx, f, rcloneBinary)
+}
+
 // IsAvailable checks if rclone is available on the system
 func IsAvailable(rclonePath string) bool {
 	if rclonePath == "" {
@@ -0,0 +1,26 @@
+package download
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCheckDests(t *testing.T) {
+	files := []File{
+		{Source: "remote:a.mkv", Dest: "/dl/Movie (2020)/Movie.mkv"},
+		{Source: "remote:b.mkv", Dest: "/dl/Other.mkv"},
+	}
+	if err := checkDests(files); err != nil {
+		t.Fatalf("checkDests with distinct destinations: %v", err)
+	}
+
+	files = append(files, File{Source: "remote:c.mkv", Dest: "/dl/Movie (2020)/../Movie (2020)/Movie.mkv"})
+	err := checkDests(files)
+	if err == nil || !strings.Contains(err.Error(), "Movie.mkv") {
+		t.Errorf("checkDests with a shared destination: err = %v", err)
+	}
+	if err := DownloadMultipleConcurrent(context.Background(), files, "true", 2, nil); err == nil {
+		t.Error("DownloadMultipleConcurrent accepted files sharing a destination")
+	}
+}
@@ -0,0 +1,62 @@
+package download
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+// RunPostDownloadHook runs the user's post_download_cmd for one finished
+// download. The command goes through the platform shell (sh -c, or cmd /C on
+// Windows) so pipes and quoting work as typed in config, and inherits the
+// terminal's stdout/stderr. Details about the item are passed as environment
+// variables rather than arguments so titles never need escaping:
+//
+//	GOPLEXCLI_TITLE, GOPLEXCLI_TYPE, GOPLEXCLI_YEAR, GOPLEXCLI_SHOW,
+//	GOPLEXCLI_SEASON, GOPLEXCLI_EPISODE, GOPLEXCLI_PATH, GOPLEXCLI_KEY
+func RunPostDownloadHook(command string, item *plex.MediaItem, localPath string) error {
+	if command == "" {
+		return nil
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	cmd.Env = append(os.Environ(), hookEnv(item, localPath)...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("post-download hook failed: %w", err)
+	}
+	return nil
+}
+
+// hookEnv builds the GOPLEXCLI_* variables describing a downloaded item.
+// Season and episode are only set for episodes.
+func hookEnv(item *plex.MediaItem, localPath string) []string {
+	env := []string{
+		"GOPLEXCLI_TITLE=" + item.Title,
+		"GOPLEXCLI_TYPE=" + item.Type,
+		"GOPLEXCLI_KEY=" + item.Key,
+		"GOPLEXCLI_PATH=" + localPath,
+	}
+	if item.Year > 0 {
+		env = append(env, "GOPLEXCLI_YEAR="+strconv.Itoa(item.Year))
+	}
+	if item.Type == "episode" {
+		env = append(env,
+			"GOPLEXCLI_SHOW="+item.ParentTitle,
+			"GOPLEXCLI_SEASON="+strconv.FormatInt(item.ParentIndex, 10),
+			"GOPLEXCLI_EPISODE="+strconv.FormatInt(item.Index, 10),
+		)
+	}
+	return env
+}
@@ -0,0 +1,56 @@
+package download
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+func TestHookEnvEpisode(t *testing.T) {
+	item := &plex.MediaItem{
+		Key:         "/library/metadata/42",
+		Title:       "Pilot",
+		Type:        "episode",
+		ParentTitle: "Some Show",
+		ParentIndex: 1,
+		Index:       3,
+	}
+
+	env := hookEnv(item, "/downloads/pilot.mkv")
+
+	for _, want := range []string{
+		"GOPLEXCLI_TITLE=Pilot",
+		"GOPLEXCLI_TYPE=episode",
+		"GOPLEXCLI_KEY=/library/metadata/42",
+		"GOPLEXCLI_PATH=/downloads/pilot.mkv",
+		"GOPLEXCLI_SHOW=Some Show",
+		"GOPLEXCLI_SEASON=1",
+		"GOPLEXCLI_EPISODE=3",
+	} {
+		if !slices.Contains(env, want) {
+			t.Errorf("hookEnv missing %q; got %v", want, env)
+		}
+	}
+}
+
+func TestHookEnvMovie(t *testing.T) {
+	item := &plex.MediaItem{Title: "Heat", Type: "movie", Year: 1995}
+
+	env := hookEnv(item, "/downloads/heat.mkv")
+
+	if !slices.Contains(env, "GOPLEXCLI_YEAR=1995") {
+		t.Errorf("hookEnv missing year; got %v", env)
+	}
+	for _, v := range env {
+		if v == "GOPLEXCLI_SEASON=0" || v == "GOPLEXCLI_SHOW=" {
+			t.Errorf("movie should not carry episode vars; got %q", v)
+		}
+	}
+}
+
+func TestRunPostDownloadHookEmpty(t *testing.T) {
+	if err := RunPostDownloadHook("", &plex.MediaItem{}, ""); err != nil {
+		t.Errorf("empty command should be a no-op, got %v", err)
+	}
+}
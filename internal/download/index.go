@@ -0,0 +1,186 @@
+package download
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/config"
+	"github.com/joshkerr/goplexcli/internal/plex"
+	"github.com/joshkerr/goplexcli/internal/storage"
+)
+
+// The downloads index records every file goplexcli has finished downloading,
+// so a later browse can offer the local copy instead of streaming it again.
+// It lives in a JSON file next to the media cache; 'goplexcli downloads list'
+// and 'downloads clean' read it.
+
+// indexSchemaVersion is the index file format this build reads and writes.
+const indexSchemaVersion = 1
+
+// IndexEntry is one downloaded file.
+type IndexEntry struct {
+	Key          string `json:"key"`
+	ServerURL    string `json:"server_url,omitempty"`
+	Title        string `json:"title"`
+	Path         string `json:"path"`
+	Size         int64  `json:"size,omitempty"`
+	DownloadedAt int64  `json:"downloaded_at"` // unix seconds
+}
+
+// NewIndexEntry records item as downloaded to path at the given time. The
+// size is taken from the file itself, falling back to the item's.
+func NewIndexEntry(item *plex.MediaItem, path string, at time.Time) IndexEntry {
+	e := IndexEntry{
+		Key:          item.Key,
+		ServerURL:    item.ServerURL,
+		Title:        item.FormatMediaTitle(),
+		Path:         path,
+		Size:         item.Size,
+		DownloadedAt: at.Unix(),
+	}
+	if info, err := os.Stat(path); err == nil {
+		e.Size = info.Size()
+	}
+	return e
+}
+
+// Present reports whether the entry's file is still on disk at its recorded
+// size.
+func (e IndexEntry) Present() bool {
+	info, err := os.Stat(e.Path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	return e.Size == 0 || info.Size() == e.Size
+}
+
+// Index is the full downloads index, oldest download first.
+type Index struct {
+	Version int          `json:"version"`
+	Entries []IndexEntry `json:"entries"`
+}
+
+// Add records entries, replacing any earlier entry for the same item or the
+// same file, so re-downloading doesn't leave duplicates behind.
+func (ix *Index) Add(entries ...IndexEntry) {
+	ix.Version = indexSchemaVersion
+	for _, e := range entries {
+		ix.Entries = removeEntries(ix.Entries, func(old IndexEntry) bool {
+			return sameItem(old, e.Key, e.ServerURL) || old.Path == e.Path
+		})
+		ix.Entries = append(ix.Entries, e)
+	}
+}
+
+// Local returns the downloaded copy of item, if there is one still on disk.
+func (ix *Index) Local(item *plex.MediaItem) (IndexEntry, bool) {
+	for i := len(ix.Entries) - 1; i >= 0; i-- {
+		if e := ix.Entries[i]; sameItem(e, item.Key, item.ServerURL) && e.Present() {
+			return e, true
+		}
+	}
+	return IndexEntry{}, false
+}
+
+// Prune drops entries whose files are gone or have changed size, and returns
+// them.
+func (ix *Index) Prune() []IndexEntry {
+	var stale []IndexEntry
+	ix.Entries = removeEntries(ix.Entries, func(e IndexEntry) bool {
+		if e.Present() {
+			return false
+		}
+		stale = append(stale, e)
+		return true
+	})
+	return stale
+}
+
+// sameItem matches an entry to a Plex item. Keys are only unique per server;
+// entries recorded without a server URL match any server.
+func sameItem(e IndexEntry, key, serverURL string) bool {
+	if e.Key != key {
+		return false
+	}
+	return e.ServerURL == "" || serverURL == "" ||
+		strings.TrimRight(e.ServerURL, "/") == strings.TrimRight(serverURL, "/")
+}
+
+func removeEntries(entries []IndexEntry, drop func(IndexEntry) bool) []IndexEntry {
+	kept := entries[:0]
+	for _, e := range entries {
+		if !drop(e) {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+// IndexPath returns the JSON file holding the index, alongside the media
+// cache. (The GUI's transfer history is the separate downloads.json.)
+func IndexPath() (string, error) {
+	dir, err := config.GetCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "local-downloads.json"), nil
+}
+
+// SchemaVersion implements storage.Versioned.
+func (ix *Index) SchemaVersion() int { return ix.Version }
+
+// indexFile returns the index stored at path.
+func indexFile(path string) storage.File[Index] {
+	return storage.File[Index]{
+		Path:    path,
+		Name:    "downloads index",
+		Indent:  true,
+		Version: indexSchemaVersion,
+		New:     func() *Index { return &Index{Version: indexSchemaVersion} },
+	}
+}
+
+// LoadIndexFrom reads the index at path. A missing file yields an empty
+// index.
+func LoadIndexFrom(path string) (*Index, error) {
+	ix, _, err := indexFile(path).Load()
+	return ix, err
+}
+
+// SaveTo writes the index to path atomically.
+func (ix *Index) SaveTo(path string) error {
+	return indexFile(path).Save(ix)
+}
+
+// LoadIndex reads the default index file.
+func LoadIndex() (*Index, error) {
+	path, err := IndexPath()
+	if err != nil {
+		return nil, err
+	}
+	return LoadIndexFrom(path)
+}
+
+// UpdateIndex loads the default index, applies fn and saves the result, all
+// under the index's lock so concurrent downloads don't lose each other's
+// entries.
+func UpdateIndex(fn func(ix *Index) error) error {
+	path, err := IndexPath()
+	if err != nil {
+		return err
+	}
+	return indexFile(path).Update(fn)
+}
+
+// RecordDownloads adds entries to the default index.
+func RecordDownloads(entries ...IndexEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return UpdateIndex(func(ix *Index) error {
+		ix.Add(entries...)
+		return nil
+	})
+}
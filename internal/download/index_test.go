@@ -0,0 +1,63 @@
+package download
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+func TestIndex(t *testing.T) {
+	dir := t.TempDir()
+	heat := &plex.MediaItem{Key: "/library/metadata/1", Title: "Heat", Year: 1995, ServerURL: "http://a:32400"}
+	heatPath := filepath.Join(dir, "Heat (1995).mkv")
+	if err := os.WriteFile(heatPath, make([]byte, 10), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var ix Index
+	ix.Add(NewIndexEntry(heat, heatPath, time.Now()))
+	// Re-downloading the same item replaces its entry.
+	ix.Add(NewIndexEntry(heat, heatPath, time.Now()))
+	if len(ix.Entries) != 1 || ix.Entries[0].Size != 10 {
+		t.Fatalf("entries = %+v, want one 10-byte entry", ix.Entries)
+	}
+
+	if e, ok := ix.Local(heat); !ok || e.Path != heatPath {
+		t.Errorf("Local(heat) = %+v, %v", e, ok)
+	}
+	otherServer := *heat
+	otherServer.ServerURL = "http://b:32400"
+	if _, ok := ix.Local(&otherServer); ok {
+		t.Error("the same key on another server shouldn't match")
+	}
+
+	// A file that changed size no longer counts as the download.
+	if err := os.WriteFile(heatPath, make([]byte, 3), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ix.Local(heat); ok {
+		t.Error("a resized file shouldn't match")
+	}
+	if stale := ix.Prune(); len(stale) != 1 || len(ix.Entries) != 0 {
+		t.Errorf("Prune = %+v leaving %+v", stale, ix.Entries)
+	}
+}
+
+func TestIndexRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "local-downloads.json")
+	ix, err := LoadIndexFrom(path)
+	if err != nil || len(ix.Entries) != 0 {
+		t.Fatalf("missing file: %+v, %v", ix, err)
+	}
+	ix.Add(IndexEntry{Key: "k", Title: "Heat (1995)", Path: "/x/heat.mkv", DownloadedAt: 1})
+	if err := ix.SaveTo(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadIndexFrom(path)
+	if err != nil || len(got.Entries) != 1 || got.Entries[0].Path != "/x/heat.mkv" {
+		t.Errorf("round trip = %+v, %v", got, err)
+	}
+}
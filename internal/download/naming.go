@@ -0,0 +1,149 @@
+package download
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+// Naming templates lay downloaded files out library-style under the download
+// directory instead of dropping them flat. A template is a slash-separated
+// relative path with {placeholder} fields, e.g.
+//
+//	{show}/Season {season:02}/{show} - S{season:02}E{episode:02} - {title}.{ext}
+//
+// Supported placeholders: title, show, season, episode, year, type, ext, and
+// filename (the original file name). Numeric fields accept a zero-padded
+// width such as {season:02}. Substituted values are sanitized so a title can
+// never introduce extra directories or characters Windows rejects.
+
+// templateFields lists the placeholders RenderTemplate understands.
+var templateFields = map[string]bool{
+	"title": true, "show": true, "season": true, "episode": true,
+	"year": true, "type": true, "ext": true, "filename": true,
+}
+
+// ValidateTemplate checks that a naming template is well-formed and only uses
+// known placeholders, so a typo in config fails before any download starts.
+func ValidateTemplate(tmpl string) error {
+	_, err := RenderTemplate(tmpl, &plex.MediaItem{RclonePath: "remote:dir/x.mkv"})
+	return err
+}
+
+// RenderTemplate expands tmpl for item and returns a relative, OS-native path.
+// An empty template yields the original file name.
+func RenderTemplate(tmpl string, item *plex.MediaItem) (string, error) {
+	filename := path.Base(strings.ReplaceAll(item.RclonePath, `\`, "/"))
+	if tmpl == "" {
+		return filename, nil
+	}
+
+	var b strings.Builder
+	rest := tmpl
+	for {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			b.WriteString(rest)
+			break
+		}
+		closing := strings.IndexByte(rest[open:], '}')
+		if closing < 0 {
+			return "", fmt.Errorf("invalid template %q: unclosed {", tmpl)
+		}
+		b.WriteString(rest[:open])
+
+		field := rest[open+1 : open+closing]
+		name, spec, _ := strings.Cut(field, ":")
+		if !templateFields[name] {
+			return "", fmt.Errorf("invalid template %q: unknown field {%s}", tmpl, name)
+		}
+		value, err := templateValue(item, name, spec, filename)
+		if err != nil {
+			return "", fmt.Errorf("invalid template %q: %w", tmpl, err)
+		}
+		b.WriteString(sanitizeSegment(value))
+
+		rest = rest[open+closing+1:]
+	}
+
+	// Clean each directory level; drop empty and dot segments so a template
+	// can't climb out of the download directory.
+	var parts []string
+	for _, seg := range strings.Split(b.String(), "/") {
+		seg = strings.TrimSpace(seg)
+		if seg == "" || seg == "." || seg == ".." {
+			continue
+		}
+		parts = append(parts, seg)
+	}
+	if len(parts) == 0 {
+		return filename, nil
+	}
+	return filepath.Join(parts...), nil
+}
+
+// templateValue resolves one placeholder. spec is the optional text after
+// the colon; for numeric fields it is a zero-padded width like "02".
+func templateValue(item *plex.MediaItem, name, spec, filename string) (string, error) {
+	var num int64
+	isNum := false
+	var value string
+
+	switch name {
+	case "title":
+		value = item.Title
+	case "show":
+		value = item.ParentTitle
+		if value == "" {
+			value = item.Title
+		}
+	case "type":
+		value = item.Type
+	case "filename":
+		value = filename
+	case "ext":
+		value = strings.TrimPrefix(path.Ext(filename), ".")
+	case "season":
+		num, isNum = item.ParentIndex, true
+	case "episode":
+		num, isNum = item.Index, true
+	case "year":
+		num, isNum = int64(item.Year), true
+	}
+
+	if !isNum {
+		if spec != "" {
+			return "", fmt.Errorf("{%s} does not take a format", name)
+		}
+		return value, nil
+	}
+	if spec == "" {
+		return strconv.FormatInt(num, 10), nil
+	}
+	width, err := strconv.Atoi(spec)
+	if err != nil || width < 1 || width > 9 {
+		return "", fmt.Errorf("bad width %q in {%s:%s}", spec, name, spec)
+	}
+	return fmt.Sprintf("%0*d", width, num), nil
+}
+
+// sanitizeSegment replaces characters that are path separators or invalid in
+// Windows file names, so substituted metadata stays inside one path segment.
+func sanitizeSegment(s string) string {
+	// "Title: Subtitle" reads better as "Title - Subtitle" than "Title_ Subtitle".
+	s = strings.ReplaceAll(s, ": ", " - ")
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		if r < 0x20 {
+			return -1
+		}
+		return r
+	}, s)
+}
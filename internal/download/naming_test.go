@@ -0,0 +1,79 @@
+package download
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	episode := &plex.MediaItem{
+		Title:       "Pilot: Part 1",
+		Type:        "episode",
+		ParentTitle: "Some/Show",
+		ParentIndex: 2,
+		Index:       7,
+		RclonePath:  "remote:TV/Some Show/s02e07.mkv",
+	}
+	movie := &plex.MediaItem{
+		Title:      "Heat",
+		Type:       "movie",
+		Year:       1995,
+		RclonePath: "remote:Movies/heat.1995.mp4",
+	}
+
+	tests := []struct {
+		name string
+		tmpl string
+		item *plex.MediaItem
+		want string
+	}{
+		{
+			name: "empty template keeps original name",
+			item: movie,
+			want: "heat.1995.mp4",
+		},
+		{
+			name: "episode library layout",
+			tmpl: "{show}/Season {season:02}/{show} - S{season:02}E{episode:02} - {title}.{ext}",
+			item: episode,
+			want: filepath.Join("Some_Show", "Season 02", "Some_Show - S02E07 - Pilot - Part 1.mkv"),
+		},
+		{
+			name: "movie with year",
+			tmpl: "Movies/{title} ({year})/{filename}",
+			item: movie,
+			want: filepath.Join("Movies", "Heat (1995)", "heat.1995.mp4"),
+		},
+		{
+			name: "dot segments are dropped",
+			tmpl: "../{title}.{ext}",
+			item: movie,
+			want: "Heat.mp4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderTemplate(tt.tmpl, tt.item)
+			if err != nil {
+				t.Fatalf("RenderTemplate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RenderTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	if err := ValidateTemplate("{show}/{title}.{ext}"); err != nil {
+		t.Errorf("valid template rejected: %v", err)
+	}
+	for _, bad := range []string{"{nope}", "{title", "{season:xx}", "{title:02}"} {
+		if err := ValidateTemplate(bad); err == nil {
+			t.Errorf("ValidateTemplate(%q) expected error", bad)
+		}
+	}
+}
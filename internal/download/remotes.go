@@ -0,0 +1,35 @@
+package download
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/joshkerr/goplexcli/internal/logging"
+)
+
+// ListRemotes returns the names of the remotes configured in rclone, each
+// with its trailing colon, e.g. "gdrive:".
+func ListRemotes(ctx context.Context, rcloneBinary string) ([]string, error) {
+	if rcloneBinary == "" {
+		rcloneBinary = "rclone"
+	}
+	logging.Debug("running rclone", "command", "listremotes")
+	out, err := exec.CommandContext(ctx, rcloneBinary, "listremotes").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rclone remotes: %w", err)
+	}
+	return parseRemotes(string(out)), nil
+}
+
+// parseRemotes splits `rclone listremotes` output, one "name:" per line.
+func parseRemotes(out string) []string {
+	var remotes []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); strings.HasSuffix(line, ":") {
+			remotes = append(remotes, line)
+		}
+	}
+	return remotes
+}
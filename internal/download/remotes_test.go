@@ -0,0 +1,17 @@
+package download
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRemotes(t *testing.T) {
+	got := parseRemotes("gdrive:\r\nmedia box:\n\nnot a remote\n")
+	want := []string{"gdrive:", "media box:"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseRemotes = %q, want %q", got, want)
+	}
+	if got := parseRemotes(""); got != nil {
+		t.Errorf("parseRemotes(empty) = %q, want nil", got)
+	}
+}
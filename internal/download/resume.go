@@ -0,0 +1,138 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/logging"
+)
+
+// Interrupted downloads are resumable. Every transfer writes into
+// "<dest>.partial" (rclone --inplace) next to a small "<dest>.partial.json"
+// recording what is being fetched. If the process is killed mid-transfer the
+// pair stays behind, and the next attempt at the same file appends the
+// missing bytes with `rclone cat --offset` instead of starting from zero.
+// Only a finished partial is renamed to its final name, so a half-written
+// file never looks complete.
+
+const partialSuffix = ".partial"
+
+// transferFlags are passed to every rclone copyto. --inplace makes rclone
+// write straight into our .partial file (rather than its own randomly named
+// temp file, which it deletes on failure) so an interrupted transfer leaves
+// something to resume; the retry flags ride out brief network drops with a
+// backoff instead of failing the whole file. --inplace needs rclone 1.63+.
+var transferFlags = []string{
+	"--ignore-checksum",
+	"--inplace",
+	"--retries", "3",
+	"--retries-sleep", "10s",
+	"--low-level-retries", "10",
+}
+
+// partialState is the sidecar describing an in-progress transfer.
+type partialState struct {
+	Source  string    `json:"source"`
+	Size    int64     `json:"size,omitempty"`
+	Started time.Time `json:"started"`
+}
+
+func partialPath(dest string) string      { return dest + partialSuffix }
+func partialStatePath(dest string) string { return dest + partialSuffix + ".json" }
+
+// resumeOffset returns how many bytes of f an earlier interrupted run already
+// wrote, or 0 if the transfer has to start over. A partial left by a different
+// source, or for a file that has since changed size, is discarded.
+func resumeOffset(f File) int64 {
+	info, err := os.Stat(partialPath(f.Dest))
+	if err != nil {
+		return 0
+	}
+
+	data, err := os.ReadFile(partialStatePath(f.Dest))
+	var st partialState
+	if err == nil {
+		err = json.Unmarshal(data, &st)
+	}
+	if err != nil || st.Source != f.Source || st.Size != f.Size || (f.Size > 0 && info.Size() > f.Size) {
+		discardPartial(f.Dest)
+		return 0
+	}
+	return info.Size()
+}
+
+// writePartialState records that f is being downloaded into its partial file.
+func writePartialState(f File) error {
+	data, err := json.Marshal(partialState{Source: f.Source, Size: f.Size, Started: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(partialStatePath(f.Dest), data, 0644)
+}
+
+// discardPartial removes a partial file and its sidecar.
+func discardPartial(dest string) {
+	_ = os.Remove(partialPath(dest))
+	_ = os.Remove(partialStatePath(dest))
+}
+
+// finishPartial moves a completed partial file to dest and drops its sidecar.
+func finishPartial(dest string) error {
+	if err := os.Rename(partialPath(dest), dest); err != nil {
+		return fmt.Errorf("failed to finalize download: %w", err)
+	}
+	_ = os.Remove(partialStatePath(dest))
+	return nil
+}
+
+// resumeTransfer appends the rest of f.Source, from offset onwards, to its
+// partial file. progress is called with the total bytes on disk as data
+// arrives.
+func resumeTransfer(ctx context.Context, f File, offset int64, rcloneBinary string, progress func(written int64)) error {
+	if rcloneBinary == "" {
+		rcloneBinary = "rclone"
+	}
+
+	out, err := os.OpenFile(partialPath(f.Dest), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open partial download: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, rcloneBinary, "cat", "--offset", strconv.FormatInt(offset, 10), f.Source)
+	logging.Debug("running rclone", "command", "cat", "offset", offset, "source", f.Source)
+	var stderr bytes.Buffer
+	cmd.Stdout = &progressWriter{w: out, n: offset, report: progress}
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+	closeErr := out.Close()
+
+	if runErr != nil {
+		return fmt.Errorf("resume failed: %w: %s", runErr, strings.TrimSpace(stderr.String()))
+	}
+	return closeErr
+}
+
+// progressWriter counts bytes written through it and reports the running
+// total.
+type progressWriter struct {
+	w      io.Writer
+	n      int64
+	report func(int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.n += int64(n)
+	if p.report != nil {
+		p.report(p.n)
+	}
+	return n, err
+}
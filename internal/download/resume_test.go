@@ -0,0 +1,118 @@
+package download
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestResumeOffset(t *testing.T) {
+	dir := t.TempDir()
+	f := File{Source: "remote:movies/heat.mkv", Dest: filepath.Join(dir, "heat.mkv"), Size: 100}
+
+	if got := resumeOffset(f); got != 0 {
+		t.Errorf("no partial: resumeOffset = %d, want 0", got)
+	}
+
+	if err := os.WriteFile(partialPath(f.Dest), make([]byte, 40), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writePartialState(f); err != nil {
+		t.Fatal(err)
+	}
+	if got := resumeOffset(f); got != 40 {
+		t.Errorf("matching partial: resumeOffset = %d, want 40", got)
+	}
+
+	other := f
+	other.Source = "remote:movies/other.mkv"
+	if got := resumeOffset(other); got != 0 {
+		t.Errorf("different source: resumeOffset = %d, want 0", got)
+	}
+	if _, err := os.Stat(partialPath(f.Dest)); !os.IsNotExist(err) {
+		t.Error("a partial from a different source should be discarded")
+	}
+}
+
+func TestResumeOffsetWithoutState(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "heat.mkv")
+	if err := os.WriteFile(partialPath(dest), make([]byte, 40), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := resumeOffset(File{Source: "remote:heat.mkv", Dest: dest}); got != 0 {
+		t.Errorf("partial without a state file: resumeOffset = %d, want 0", got)
+	}
+}
+
+func TestFinishPartial(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "heat.mkv")
+	f := File{Source: "remote:heat.mkv", Dest: dest}
+	if err := os.WriteFile(partialPath(dest), []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writePartialState(f); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := finishPartial(dest); err != nil {
+		t.Fatalf("finishPartial() error = %v", err)
+	}
+	if data, err := os.ReadFile(dest); err != nil || string(data) != "data" {
+		t.Errorf("dest = %q, %v; want the partial's contents", data, err)
+	}
+	for _, p := range []string{partialPath(dest), partialStatePath(dest)} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("%s should be removed after finishing", filepath.Base(p))
+		}
+	}
+}
+
+// fakeRcloneCat writes a shell script that implements `rclone cat --offset N
+// <file>` for a local source file.
+func fakeRcloneCat(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake rclone is a shell script")
+	}
+	path := filepath.Join(t.TempDir(), "rclone")
+	script := `#!/bin/sh
+# usage: rclone cat --offset N SOURCE
+tail -c +$(($3 + 1)) "$4"
+`
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestResumeTransfer(t *testing.T) {
+	rcloneBin := fakeRcloneCat(t)
+	dir := t.TempDir()
+
+	source := filepath.Join(dir, "source.bin")
+	if err := os.WriteFile(source, []byte("0123456789"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f := File{Source: source, Dest: filepath.Join(dir, "out.bin"), Size: 10}
+	if err := os.WriteFile(partialPath(f.Dest), []byte("0123"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var last int64
+	if err := resumeTransfer(context.Background(), f, 4, rcloneBin, func(n int64) { last = n }); err != nil {
+		t.Fatalf("resumeTransfer() error = %v", err)
+	}
+
+	data, err := os.ReadFile(partialPath(f.Dest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "0123456789" {
+		t.Errorf("partial = %q, want the full source", data)
+	}
+	if last != 10 {
+		t.Errorf("last progress = %d, want 10", last)
+	}
+}
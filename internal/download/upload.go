@@ -9,6 +9,8 @@ import (
 	"sync"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshkerr/goplexcli/internal/logging"
+	"github.com/joshkerr/goplexcli/internal/ui"
 	rclone "github.com/joshkerr/rclone-golib"
 )
 
@@ -100,7 +102,7 @@ func UploadToWebDAV(ctx context.Context, rclonePaths []string, baseURL, user, pa
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		p := tea.NewProgram(rclone.NewModel(manager))
+		p := tea.NewProgram(rclone.NewModel(manager), ui.TeaOptions()...)
 		close(uiReady)
 		if _, err := p.Run(); err != nil {
 			uiErr = err
@@ -121,6 +123,7 @@ func UploadToWebDAV(ctx context.Context, rclonePaths []string, baseURL, user, pa
 			Flags:         backendFlags,
 			Context:       ctx,
 		}
+		logging.Debug("running rclone", "command", "copyto", "source", j.src, "dest", j.dest)
 		if err := executor.Execute(j.id, opts); err != nil {
 			manager.Fail(j.id, err)
 			if firstErr == nil {
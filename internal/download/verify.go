@@ -0,0 +1,104 @@
+package download
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/joshkerr/goplexcli/internal/logging"
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+// VerifyFile checks a finished transfer: the local file must exist and, when
+// f.Size is known, match it exactly. With f.CheckHash it also asks rclone for
+// the remote's MD5 and compares it with the local file's. Remotes that can't
+// report an MD5 pass the hash check, since there is nothing to compare.
+func VerifyFile(ctx context.Context, f File, rcloneBinary string) error {
+	return verifyPath(ctx, f, f.Dest, rcloneBinary)
+}
+
+// finishVerified verifies f's completed partial file and only then moves it
+// to f.Dest, so a truncated or corrupt transfer never appears there as if it
+// had finished. A partial that fails is discarded: resuming bad data would
+// just fail again.
+func finishVerified(ctx context.Context, f File, rcloneBinary string) error {
+	if err := verifyPath(ctx, f, partialPath(f.Dest), rcloneBinary); err != nil {
+		discardPartial(f.Dest)
+		return err
+	}
+	return finishPartial(f.Dest)
+}
+
+// verifyPath is VerifyFile for f downloaded to path.
+func verifyPath(ctx context.Context, f File, path, rcloneBinary string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("verification failed: %w", err)
+	}
+	if f.Size > 0 && info.Size() != f.Size {
+		return fmt.Errorf("size mismatch: expected %s (%d bytes), got %d bytes", plex.FormatSize(f.Size), f.Size, info.Size())
+	}
+	if !f.CheckHash {
+		return nil
+	}
+
+	if rcloneBinary == "" {
+		rcloneBinary = "rclone"
+	}
+	logging.Debug("running rclone", "command", "md5sum", "source", f.Source)
+	out, err := exec.CommandContext(ctx, rcloneBinary, "md5sum", f.Source).Output()
+	if err != nil {
+		return fmt.Errorf("failed to get remote checksum: %w", err)
+	}
+	remote := parseMD5Output(out)
+	if remote == "" {
+		return nil
+	}
+
+	local, err := fileMD5(path)
+	if err != nil {
+		return fmt.Errorf("failed to checksum %s: %w", path, err)
+	}
+	if local != remote {
+		return fmt.Errorf("checksum mismatch: remote md5 %s, local %s", remote, local)
+	}
+	return nil
+}
+
+// parseMD5Output extracts the hash from `rclone md5sum` output ("<hash>  <name>").
+// It returns "" when the remote doesn't support MD5, which rclone reports as a
+// blank or non-hex hash column.
+func parseMD5Output(out []byte) string {
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return ""
+	}
+	hash := strings.ToLower(fields[0])
+	if len(hash) != 32 {
+		return ""
+	}
+	if _, err := hex.DecodeString(hash); err != nil {
+		return ""
+	}
+	return hash
+}
+
+// fileMD5 returns the lowercase hex MD5 of the file at path.
+func fileMD5(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,107 @@
+package download
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerifyFileSize(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "movie.mkv")
+	if err := os.WriteFile(dest, []byte("0123456789"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		size    int64
+		wantErr bool
+	}{
+		{"matching size", 10, false},
+		{"unknown size", 0, false},
+		{"truncated", 20, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyFile(context.Background(), File{Source: "remote:movie.mkv", Dest: dest, Size: tt.size}, "")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "size mismatch") {
+				t.Errorf("error should mention size mismatch, got %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyFileMissing(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "missing.mkv")
+	if err := VerifyFile(context.Background(), File{Dest: dest}, ""); err == nil {
+		t.Error("VerifyFile() should fail when the local file doesn't exist")
+	}
+}
+
+func TestParseMD5Output(t *testing.T) {
+	tests := []struct {
+		out  string
+		want string
+	}{
+		{"d41d8cd98f00b204e9800998ecf8427e  movie.mkv\n", "d41d8cd98f00b204e9800998ecf8427e"},
+		{"D41D8CD98F00B204E9800998ECF8427E  movie.mkv\n", "d41d8cd98f00b204e9800998ecf8427e"},
+		{"                                  movie.mkv\n", ""},
+		{"UNSUPPORTED  movie.mkv\n", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := parseMD5Output([]byte(tt.out)); got != tt.want {
+			t.Errorf("parseMD5Output(%q) = %q, want %q", tt.out, got, tt.want)
+		}
+	}
+}
+
+func TestFileMD5(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := fileMD5(path)
+	if err != nil {
+		t.Fatalf("fileMD5() error = %v", err)
+	}
+	if want := "d41d8cd98f00b204e9800998ecf8427e"; got != want {
+		t.Errorf("fileMD5(empty) = %q, want %q", got, want)
+	}
+}
+
+func TestFinishVerified(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "movie.mkv")
+	f := File{Source: "remote:movie.mkv", Dest: dest, Size: 10}
+
+	// A truncated partial is discarded, not moved into place.
+	if err := os.WriteFile(partialPath(dest), []byte("01234"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writePartialState(f); err != nil {
+		t.Fatal(err)
+	}
+	if err := finishVerified(context.Background(), f, ""); err == nil {
+		t.Fatal("finishVerified() accepted a truncated partial")
+	}
+	for _, path := range []string{dest, partialPath(dest), partialStatePath(dest)} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s should not exist after a failed verification", filepath.Base(path))
+		}
+	}
+
+	if err := os.WriteFile(partialPath(dest), []byte("0123456789"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := finishVerified(context.Background(), f, ""); err != nil {
+		t.Fatalf("finishVerified() error = %v", err)
+	}
+	if _, err := os.Stat(dest); err != nil {
+		t.Errorf("verified download not moved into place: %v", err)
+	}
+}
@@ -4,8 +4,11 @@
 package errors
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
 )
 
 // Common sentinel errors for error checking
@@ -55,6 +58,41 @@ func NewPlexErrorWithStatus(op, server string, statusCode int, err error) *PlexE
 	return &PlexError{Op: op, Server: server, StatusCode: statusCode, Err: err}
 }
 
+// Hint suggests what the user can do about a failed Plex request, such as
+// logging in again when the token has expired. It returns "" for other
+// errors, or when there is nothing to add to the error itself.
+func Hint(err error) string {
+	var pe *PlexError
+	if !errors.As(err, &pe) {
+		return ""
+	}
+	server := pe.Server
+	switch code := pe.StatusCode; {
+	case code == http.StatusUnauthorized:
+		return fmt.Sprintf("Your Plex token was rejected by %s; it has probably expired. Run 'goplexcli login' again.", server)
+	case code == http.StatusForbidden:
+		return fmt.Sprintf("%s refused the request. Some actions are only allowed for the server owner.", server)
+	case code == http.StatusNotFound:
+		return fmt.Sprintf("%s couldn't find it; it may have been removed. Run 'goplexcli cache update' to refresh the cache.", server)
+	case code == http.StatusTooManyRequests:
+		return fmt.Sprintf("%s is rate limiting requests. Wait a moment and try again.", server)
+	case code >= 500:
+		return fmt.Sprintf("%s reported an internal error. Try again shortly, or check the server's logs.", server)
+	case code != 0:
+		return ""
+	}
+
+	var netErr net.Error
+	isNet := errors.As(err, &netErr)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded) || isNet && netErr.Timeout():
+		return fmt.Sprintf("%s took too long to respond. Check its load with 'goplexcli server status', or raise http_timeout in the config.", server)
+	case isNet:
+		return fmt.Sprintf("Couldn't reach %s. Check that it is running and that its URL is right ('goplexcli server list').", server)
+	}
+	return ""
+}
+
 // ConfigError represents an error related to configuration.
 type ConfigError struct {
 	Field   string // Config field that has an issue
@@ -0,0 +1,32 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestHint(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("connection refused")}
+	tests := []struct {
+		name string
+		err  error
+		want string // substring; "" means no hint
+	}{
+		{"expired token", NewPlexErrorWithStatus("GetLibraries", "Basement", 401, fmt.Errorf("authentication failed")), "goplexcli login"},
+		{"wrapped", fmt.Errorf("failed to fetch: %w", NewPlexErrorWithStatus("GetDetails", "Basement", 404, fmt.Errorf("not found"))), "cache update"},
+		{"server error", NewPlexErrorWithStatus("GetSessions", "Basement", 503, fmt.Errorf("unavailable")), "internal error"},
+		{"unreachable", NewPlexError("GetLibraries", "Basement", dialErr), "Couldn't reach Basement"},
+		{"timeout", NewPlexError("GetLibraries", "Basement", context.DeadlineExceeded), "too long"},
+		{"other status", NewPlexErrorWithStatus("GetLibraries", "Basement", 400, fmt.Errorf("bad request")), ""},
+		{"not a plex error", fmt.Errorf("disk full"), ""},
+	}
+	for _, tt := range tests {
+		got := Hint(tt.err)
+		if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+			t.Errorf("%s: Hint = %q, want it to contain %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+// CatalogFormats lists the formats WriteCatalog accepts.
+var CatalogFormats = []string{"csv", "json", "md"}
+
+// CatalogRow is one cached item as written by WriteCatalog.
+type CatalogRow struct {
+	Type       string  `json:"type"`
+	Title      string  `json:"title"`
+	Year       int     `json:"year,omitempty"`
+	Show       string  `json:"show,omitempty"`
+	Season     int64   `json:"season,omitempty"`
+	Episode    int64   `json:"episode,omitempty"`
+	Rating     float64 `json:"rating,omitempty"`
+	DurationMs int     `json:"duration_ms,omitempty"`
+	FilePath   string  `json:"file_path,omitempty"`
+	Size       int64   `json:"size,omitempty"` // bytes
+	Server     string  `json:"server,omitempty"`
+}
+
+// Catalog turns cached media into catalog rows, keeping only items of type
+// typ ("movie" or "episode"; "show" is accepted for episodes) unless typ is
+// empty.
+func Catalog(media []plex.MediaItem, typ string) ([]CatalogRow, error) {
+	switch typ {
+	case "", "movie", "episode":
+	case "show":
+		typ = "episode"
+	default:
+		return nil, fmt.Errorf("unknown type %q (want movie or show)", typ)
+	}
+
+	rows := make([]CatalogRow, 0, len(media))
+	for _, item := range media {
+		if typ != "" && item.Type != typ {
+			continue
+		}
+		row := CatalogRow{
+			Type:       item.Type,
+			Title:      item.Title,
+			Year:       item.Year,
+			Rating:     item.Rating,
+			DurationMs: item.Duration,
+			FilePath:   item.FilePath,
+			Size:       item.Size,
+			Server:     item.ServerName,
+		}
+		if item.Type == "episode" {
+			row.Show, row.Season, row.Episode = item.ParentTitle, item.ParentIndex, item.Index
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// WriteCatalog writes rows as csv, json or md (a Markdown table).
+func WriteCatalog(w io.Writer, format string, rows []CatalogRow) error {
+	switch format {
+	case "csv":
+		return writeCatalogCSV(w, rows)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	case "md":
+		return writeCatalogMarkdown(w, rows)
+	}
+	return fmt.Errorf("unknown format %q (want %s)", format, strings.Join(CatalogFormats, ", "))
+}
+
+// writeCatalogCSV writes raw numbers (minutes, bytes) that spreadsheets can
+// sum and sort.
+func writeCatalogCSV(w io.Writer, rows []CatalogRow) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"type", "title", "year", "show", "season", "episode", "rating", "duration_min", "file_path", "size_bytes", "server"})
+	for _, r := range rows {
+		var year, season, episode, rating, duration, size string
+		if r.Year > 0 {
+			year = strconv.Itoa(r.Year)
+		}
+		if r.Type == "episode" {
+			season, episode = strconv.FormatInt(r.Season, 10), strconv.FormatInt(r.Episode, 10)
+		}
+		if r.Rating > 0 {
+			rating = strconv.FormatFloat(r.Rating, 'f', 1, 64)
+		}
+		if r.DurationMs > 0 {
+			duration = strconv.Itoa((r.DurationMs + 30000) / 60000)
+		}
+		if r.Size > 0 {
+			size = strconv.FormatInt(r.Size, 10)
+		}
+		_ = cw.Write([]string{r.Type, r.Title, year, r.Show, season, episode, rating, duration, r.FilePath, size, r.Server})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeCatalogMarkdown writes a table for reading, with human sizes and
+// episodes labelled like "Show S01E02 - Title".
+func writeCatalogMarkdown(w io.Writer, rows []CatalogRow) error {
+	var b strings.Builder
+	b.WriteString("| Title | Year | Rating | Duration | Size | File |\n")
+	b.WriteString("|---|---|---|---|---|---|\n")
+	for _, r := range rows {
+		title := r.Title
+		if r.Type == "episode" {
+			title = fmt.Sprintf("%s S%02dE%02d - %s", r.Show, r.Season, r.Episode, r.Title)
+		}
+		var year, rating, duration, size string
+		if r.Year > 0 {
+			year = strconv.Itoa(r.Year)
+		}
+		if r.Rating > 0 {
+			rating = strconv.FormatFloat(r.Rating, 'f', 1, 64)
+		}
+		if r.DurationMs > 0 {
+			duration = fmt.Sprintf("%d min", (r.DurationMs+30000)/60000)
+		}
+		if r.Size > 0 {
+			size = plex.FormatSize(r.Size)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+			markdownCell(title), year, rating, duration, size, markdownCell(r.FilePath))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCell keeps a value inside its table cell.
+func markdownCell(s string) string {
+	return strings.ReplaceAll(oneLine(s), "|", `\|`)
+}
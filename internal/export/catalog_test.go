@@ -0,0 +1,75 @@
+package export
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+var catalogMedia = []plex.MediaItem{
+	{Type: "movie", Title: "Heat", Year: 1995, Rating: 8.3, Duration: 10260000, FilePath: "/movies/Heat.mkv", Size: 4509715660, ServerName: "Home"},
+	{Type: "episode", Title: "Pilot | Part 1", ParentTitle: "The Show", ParentIndex: 1, Index: 2, Duration: 1800000},
+}
+
+func TestCatalog(t *testing.T) {
+	rows, err := Catalog(catalogMedia, "")
+	if err != nil || len(rows) != 2 {
+		t.Fatalf("Catalog() = %d rows, %v", len(rows), err)
+	}
+	if rows[1].Show != "The Show" || rows[1].Season != 1 || rows[1].Episode != 2 {
+		t.Errorf("episode row = %+v", rows[1])
+	}
+	if rows, _ := Catalog(catalogMedia, "show"); len(rows) != 1 || rows[0].Type != "episode" {
+		t.Errorf("Catalog(show) = %+v", rows)
+	}
+	if rows, _ := Catalog(catalogMedia, "movie"); len(rows) != 1 || rows[0].Title != "Heat" {
+		t.Errorf("Catalog(movie) = %+v", rows)
+	}
+	if _, err := Catalog(catalogMedia, "album"); err == nil {
+		t.Error("Catalog(album): expected an error")
+	}
+}
+
+func TestWriteCatalog(t *testing.T) {
+	rows, _ := Catalog(catalogMedia, "")
+
+	var b strings.Builder
+	if err := WriteCatalog(&b, "csv", rows); err != nil {
+		t.Fatal(err)
+	}
+	wantCSV := `type,title,year,show,season,episode,rating,duration_min,file_path,size_bytes,server
+movie,Heat,1995,,,,8.3,171,/movies/Heat.mkv,4509715660,Home
+episode,Pilot | Part 1,,The Show,1,2,,30,,,
+`
+	if got := b.String(); got != wantCSV {
+		t.Errorf("csv =\n%s\nwant\n%s", got, wantCSV)
+	}
+
+	b.Reset()
+	if err := WriteCatalog(&b, "md", rows); err != nil {
+		t.Fatal(err)
+	}
+	wantMD := `| Title | Year | Rating | Duration | Size | File |
+|---|---|---|---|---|---|
+| Heat | 1995 | 8.3 | 171 min | 4.2 GB | /movies/Heat.mkv |
+| The Show S01E02 - Pilot \| Part 1 |  |  | 30 min |  |  |
+`
+	if got := b.String(); got != wantMD {
+		t.Errorf("md =\n%s\nwant\n%s", got, wantMD)
+	}
+
+	b.Reset()
+	if err := WriteCatalog(&b, "json", rows); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []CatalogRow
+	if err := json.Unmarshal([]byte(b.String()), &decoded); err != nil || len(decoded) != 2 || decoded[0] != rows[0] {
+		t.Errorf("json round trip = %+v, %v", decoded, err)
+	}
+
+	if err := WriteCatalog(&b, "xml", rows); err == nil {
+		t.Error("WriteCatalog(xml): expected an error")
+	}
+}
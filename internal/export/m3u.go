@@ -0,0 +1,39 @@
+// Package export writes cached media out in formats other tools consume,
+// such as m3u playlists for external players.
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Entry is one playlist line: a display title, a length, and a playable URL.
+type Entry struct {
+	Title string
+	// Duration is the length in seconds; 0 means unknown.
+	Duration int
+	URL      string
+}
+
+// WriteM3U writes entries as an extended m3u playlist. Callers writing a
+// .m3u8 file get UTF-8 titles as-is, which is what the extension promises.
+func WriteM3U(w io.Writer, entries []Entry) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "#EXTM3U")
+	for _, e := range entries {
+		duration := e.Duration
+		if duration <= 0 {
+			duration = -1 // m3u convention for "unknown"
+		}
+		fmt.Fprintf(bw, "#EXTINF:%d,%s\n", duration, oneLine(e.Title))
+		fmt.Fprintln(bw, oneLine(e.URL))
+	}
+	return bw.Flush()
+}
+
+// oneLine keeps a value from breaking the line-oriented format.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,27 @@
+package export
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteM3U(t *testing.T) {
+	var b strings.Builder
+	err := WriteM3U(&b, []Entry{
+		{Title: "Show - S01E01 - Pilot", Duration: 2700, URL: "http://plex:32400/library/parts/1/file.mkv?download=1"},
+		{Title: "Multi\nline", URL: "http://plex:32400/library/parts/2/file.mkv"},
+	})
+	if err != nil {
+		t.Fatalf("WriteM3U() error = %v", err)
+	}
+
+	want := `#EXTM3U
+#EXTINF:2700,Show - S01E01 - Pilot
+http://plex:32400/library/parts/1/file.mkv?download=1
+#EXTINF:-1,Multi line
+http://plex:32400/library/parts/2/file.mkv
+`
+	if got := b.String(); got != want {
+		t.Errorf("WriteM3U() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,251 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+// ResolveTitle finds what a user-supplied title refers to in the cache: every
+// episode of a show with that name (in season/episode order), or else a movie
+// with that title. Matching is case-insensitive. It returns nil when nothing
+// matches.
+func ResolveTitle(media []plex.MediaItem, title string) []*plex.MediaItem {
+	var episodes, movies []*plex.MediaItem
+	for i := range media {
+		item := &media[i]
+		switch {
+		case item.Type == "episode" && strings.EqualFold(item.ParentTitle, title):
+			episodes = append(episodes, item)
+		case item.Type == "movie" && strings.EqualFold(item.Title, title):
+			movies = append(movies, item)
+		}
+	}
+	if len(episodes) > 0 {
+		sort.SliceStable(episodes, func(i, j int) bool {
+			if episodes[i].ParentIndex != episodes[j].ParentIndex {
+				return episodes[i].ParentIndex < episodes[j].ParentIndex
+			}
+			return episodes[i].Index < episodes[j].Index
+		})
+		return episodes
+	}
+	return movies
+}
+
+// Titles returns the distinct names ResolveTitle accepts, sorted: show
+// names when shows is set, movie titles when movies is set. Only titles
+// starting with prefix (case-insensitively) are included.
+func Titles(media []plex.MediaItem, shows, movies bool, prefix string) []string {
+	seen := make(map[string]struct{})
+	var titles []string
+	for _, item := range media {
+		var title string
+		switch {
+		case shows && item.Type == "episode":
+			title = item.ParentTitle
+		case movies && item.Type == "movie":
+			title = item.Title
+		default:
+			continue
+		}
+		if title == "" || !strings.HasPrefix(strings.ToLower(title), strings.ToLower(prefix)) {
+			continue
+		}
+		if _, dup := seen[title]; dup {
+			continue
+		}
+		seen[title] = struct{}{}
+		titles = append(titles, title)
+	}
+	sort.Strings(titles)
+	return titles
+}
+
+// MatchTitle finds the show names and movie titles a loosely typed title
+// may refer to, ignoring case and punctuation. It returns the best tier of
+// matches that exists: titles equal to the query, else titles starting with
+// it, else titles containing every word of it. It returns nil when nothing
+// matches.
+func MatchTitle(media []plex.MediaItem, query string) []string {
+	q := normalizeTitle(query)
+	if q == "" {
+		return nil
+	}
+	words := strings.Fields(q)
+
+	var exact, prefix, contains []string
+	for _, title := range Titles(media, true, true, "") {
+		t := normalizeTitle(title)
+		switch {
+		case t == q:
+			exact = append(exact, title)
+		case strings.HasPrefix(t, q):
+			prefix = append(prefix, title)
+		case containsWords(t, words):
+			contains = append(contains, title)
+		}
+	}
+	switch {
+	case len(exact) > 0:
+		return exact
+	case len(prefix) > 0:
+		return prefix
+	}
+	return contains
+}
+
+// NextEpisode picks the episode of a show to watch next from its episodes
+// in order (as ResolveTitle returns them): the first one not yet fully
+// watched, or the first episode when every one has been.
+func NextEpisode(episodes []*plex.MediaItem) *plex.MediaItem {
+	if len(episodes) == 0 {
+		return nil
+	}
+	for _, ep := range episodes {
+		if ep.ViewCount == 0 {
+			return ep
+		}
+	}
+	return episodes[0]
+}
+
+// EpisodeAfter returns the episode that follows ep in its show on the same
+// server, in season and episode order, or nil if ep is the last one.
+// Specials are skipped unless ep is one.
+func EpisodeAfter(media []plex.MediaItem, ep *plex.MediaItem) *plex.MediaItem {
+	if ep == nil || ep.Type != "episode" {
+		return nil
+	}
+	var next *plex.MediaItem
+	for i := range media {
+		item := &media[i]
+		if item.Type != "episode" || item.ServerName != ep.ServerName || item.ParentTitle != ep.ParentTitle {
+			continue
+		}
+		if item.ParentIndex == 0 && ep.ParentIndex != 0 {
+			continue
+		}
+		after := item.ParentIndex > ep.ParentIndex || (item.ParentIndex == ep.ParentIndex && item.Index > ep.Index)
+		if !after {
+			continue
+		}
+		if next == nil || item.ParentIndex < next.ParentIndex || (item.ParentIndex == next.ParentIndex && item.Index < next.Index) {
+			next = item
+		}
+	}
+	return next
+}
+
+// seasonKey identifies a season of a show on one server.
+type seasonKey struct {
+	server, show string
+	season       int64
+}
+
+// selectedSeasons returns the seasons the episodes among items belong to,
+// in the order they first appear.
+func selectedSeasons(items []*plex.MediaItem) []seasonKey {
+	seen := make(map[seasonKey]bool)
+	var seasons []seasonKey
+	for _, item := range items {
+		if item.Type != "episode" || item.ParentTitle == "" {
+			continue
+		}
+		key := seasonKey{item.ServerName, item.ParentTitle, item.ParentIndex}
+		if !seen[key] {
+			seen[key] = true
+			seasons = append(seasons, key)
+		}
+	}
+	return seasons
+}
+
+// CountSeasons returns how many seasons the episodes among items span.
+func CountSeasons(items []*plex.MediaItem) int {
+	return len(selectedSeasons(items))
+}
+
+// SeasonEpisodes returns every episode in media of the seasons the
+// episodes among selected belong to: season by season in the order they
+// were selected, each in episode order.
+func SeasonEpisodes(media []plex.MediaItem, selected []*plex.MediaItem) []*plex.MediaItem {
+	seasons := selectedSeasons(selected)
+	bySeason := make(map[seasonKey][]*plex.MediaItem, len(seasons))
+	for _, key := range seasons {
+		bySeason[key] = nil
+	}
+	for i := range media {
+		item := &media[i]
+		if item.Type != "episode" {
+			continue
+		}
+		key := seasonKey{item.ServerName, item.ParentTitle, item.ParentIndex}
+		if eps, ok := bySeason[key]; ok {
+			bySeason[key] = append(eps, item)
+		}
+	}
+
+	var episodes []*plex.MediaItem
+	for _, key := range seasons {
+		eps := bySeason[key]
+		sort.SliceStable(eps, func(i, j int) bool { return eps[i].Index < eps[j].Index })
+		episodes = append(episodes, eps...)
+	}
+	return episodes
+}
+
+// ParseEpisodes parses a list of episode numbers and ranges such as
+// "1-4,7" into a set. An empty list gives a nil set, meaning every episode.
+func ParseEpisodes(s string) (map[int64]bool, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	set := make(map[int64]bool)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		lo, hi, isRange := strings.Cut(part, "-")
+		first, err := strconv.ParseInt(strings.TrimSpace(lo), 10, 64)
+		last := first
+		if err == nil && isRange {
+			last, err = strconv.ParseInt(strings.TrimSpace(hi), 10, 64)
+		}
+		if err != nil || first < 0 || last < first {
+			return nil, fmt.Errorf("invalid episode range %q: use numbers and ranges like 1-4,7", part)
+		}
+		for n := first; n <= last; n++ {
+			set[n] = true
+		}
+	}
+	return set, nil
+}
+
+// normalizeTitle lowercases a title and reduces punctuation and runs of
+// spaces to single spaces, so "Spider-Man: Far From Home" matches
+// "spider man far from home".
+func normalizeTitle(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+func containsWords(title string, words []string) bool {
+	fields := strings.Fields(title)
+	for _, w := range words {
+		found := false
+		for _, f := range fields {
+			if strings.HasPrefix(f, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
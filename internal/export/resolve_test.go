@@ -0,0 +1,185 @@
+package export
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+func TestResolveTitle(t *testing.T) {
+	media := []plex.MediaItem{
+		{Key: "e3", Type: "episode", ParentTitle: "The Show", ParentIndex: 2, Index: 1},
+		{Key: "m1", Type: "movie", Title: "Heat"},
+		{Key: "e2", Type: "episode", ParentTitle: "The Show", ParentIndex: 1, Index: 2},
+		{Key: "e1", Type: "episode", ParentTitle: "The Show", ParentIndex: 1, Index: 1},
+		{Key: "x1", Type: "episode", ParentTitle: "Other Show", ParentIndex: 1, Index: 1},
+	}
+
+	tests := []struct {
+		title string
+		want  []string
+	}{
+		{"the show", []string{"e1", "e2", "e3"}},
+		{"HEAT", []string{"m1"}},
+		{"Missing", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, item := range ResolveTitle(media, tt.title) {
+			got = append(got, item.Key)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ResolveTitle(%q) = %v, want %v", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestTitles(t *testing.T) {
+	media := []plex.MediaItem{
+		{Type: "episode", ParentTitle: "The Show", Title: "Pilot"},
+		{Type: "episode", ParentTitle: "The Show", Title: "Second"},
+		{Type: "movie", Title: "Heat"},
+		{Type: "movie", Title: "The Thing"},
+		{Type: "episode", ParentTitle: "Other Show"},
+	}
+
+	tests := []struct {
+		shows, movies bool
+		prefix        string
+		want          []string
+	}{
+		{true, true, "", []string{"Heat", "Other Show", "The Show", "The Thing"}},
+		{true, false, "", []string{"Other Show", "The Show"}},
+		{false, true, "the", []string{"The Thing"}},
+		{true, true, "THE ", []string{"The Show", "The Thing"}},
+		{true, true, "x", nil},
+	}
+	for _, tt := range tests {
+		got := Titles(media, tt.shows, tt.movies, tt.prefix)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Titles(%t, %t, %q) = %v, want %v", tt.shows, tt.movies, tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestMatchTitle(t *testing.T) {
+	media := []plex.MediaItem{
+		{Type: "movie", Title: "The Matrix"},
+		{Type: "movie", Title: "The Matrix Reloaded"},
+		{Type: "movie", Title: "Spider-Man: Far From Home"},
+		{Type: "episode", ParentTitle: "The Office (US)"},
+		{Type: "episode", ParentTitle: "The Office (UK)"},
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"the matrix", []string{"The Matrix"}},
+		{"matrix reloaded", []string{"The Matrix Reloaded"}},
+		{"spider man far", []string{"Spider-Man: Far From Home"}},
+		{"the office", []string{"The Office (UK)", "The Office (US)"}},
+		{"office us", []string{"The Office (US)"}},
+		{"  ", nil},
+		{"heat", nil},
+	}
+	for _, tt := range tests {
+		if got := MatchTitle(media, tt.query); !slices.Equal(got, tt.want) {
+			t.Errorf("MatchTitle(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestNextEpisode(t *testing.T) {
+	eps := []*plex.MediaItem{
+		{Key: "e1", ViewCount: 1},
+		{Key: "e2", ViewOffset: 60000},
+		{Key: "e3"},
+	}
+	if got := NextEpisode(eps); got.Key != "e2" {
+		t.Errorf("NextEpisode = %s, want e2", got.Key)
+	}
+	eps[1].ViewCount, eps[2].ViewCount = 1, 1
+	if got := NextEpisode(eps); got.Key != "e1" {
+		t.Errorf("NextEpisode with all watched = %s, want e1", got.Key)
+	}
+	if got := NextEpisode(nil); got != nil {
+		t.Errorf("NextEpisode(nil) = %v, want nil", got)
+	}
+}
+
+func TestEpisodeAfter(t *testing.T) {
+	media := []plex.MediaItem{
+		{Key: "s2e1", Type: "episode", ParentTitle: "Lost", ParentIndex: 2, Index: 1},
+		{Key: "s1e2", Type: "episode", ParentTitle: "Lost", ParentIndex: 1, Index: 2},
+		{Key: "s1e1", Type: "episode", ParentTitle: "Lost", ParentIndex: 1, Index: 1},
+		{Key: "s0e1", Type: "episode", ParentTitle: "Lost", ParentIndex: 0, Index: 1},
+		{Key: "s0e2", Type: "episode", ParentTitle: "Lost", ParentIndex: 0, Index: 2},
+		{Key: "other", Type: "episode", ParentTitle: "Lost", ParentIndex: 1, Index: 3, ServerName: "Office"},
+	}
+	tests := []struct {
+		after int
+		want  string
+	}{
+		{2, "s1e2"}, // s1e1
+		{1, "s2e1"}, // s1e2: on to the next season
+		{0, ""},     // s2e1: the last one
+		{3, "s0e2"}, // s0e1: specials play in order
+		{4, "s1e1"}, // s0e2: then on to the first season
+	}
+	for _, tt := range tests {
+		got := EpisodeAfter(media, &media[tt.after])
+		if (got == nil && tt.want != "") || (got != nil && got.Key != tt.want) {
+			t.Errorf("EpisodeAfter(%s) = %v, want %q", media[tt.after].Key, got, tt.want)
+		}
+	}
+	if got := EpisodeAfter(media, &plex.MediaItem{Type: "movie"}); got != nil {
+		t.Errorf("EpisodeAfter(movie) = %v, want nil", got)
+	}
+}
+
+func TestSeasonEpisodes(t *testing.T) {
+	media := []plex.MediaItem{
+		{Key: "lost-s1e2", Type: "episode", ParentTitle: "Lost", ParentIndex: 1, Index: 2},
+		{Key: "lost-s1e1", Type: "episode", ParentTitle: "Lost", ParentIndex: 1, Index: 1},
+		{Key: "lost-s2e1", Type: "episode", ParentTitle: "Lost", ParentIndex: 2, Index: 1},
+		{Key: "lost-s1e1-office", Type: "episode", ParentTitle: "Lost", ParentIndex: 1, Index: 1, ServerName: "Office"},
+		{Key: "fargo-s1e1", Type: "episode", ParentTitle: "Fargo", ParentIndex: 1, Index: 1},
+		{Key: "heat", Type: "movie", Title: "Heat"},
+	}
+	selected := []*plex.MediaItem{&media[4], &media[0], &media[5], &media[1]}
+
+	if n := CountSeasons(selected); n != 2 {
+		t.Errorf("CountSeasons = %d, want 2", n)
+	}
+	var got []string
+	for _, ep := range SeasonEpisodes(media, selected) {
+		got = append(got, ep.Key)
+	}
+	want := []string{"fargo-s1e1", "lost-s1e1", "lost-s1e2"}
+	if !slices.Equal(got, want) {
+		t.Errorf("SeasonEpisodes = %v, want %v", got, want)
+	}
+	if got := SeasonEpisodes(media, []*plex.MediaItem{&media[5]}); len(got) != 0 {
+		t.Errorf("SeasonEpisodes(movie) = %v, want none", got)
+	}
+}
+
+func TestParseEpisodes(t *testing.T) {
+	got, err := ParseEpisodes("1-3, 7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 4 || !got[1] || !got[2] || !got[3] || !got[7] {
+		t.Errorf("ParseEpisodes(\"1-3, 7\") = %v", got)
+	}
+	if got, err := ParseEpisodes(""); got != nil || err != nil {
+		t.Errorf("ParseEpisodes(\"\") = %v, %v; want nil, nil", got, err)
+	}
+	for _, bad := range []string{"x", "4-2", "1,", "-3", "1-"} {
+		if _, err := ParseEpisodes(bad); err == nil {
+			t.Errorf("ParseEpisodes(%q): expected an error", bad)
+		}
+	}
+}
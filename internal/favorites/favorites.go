@@ -17,13 +17,13 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
 	"sort"
 	"sync"
 	"time"
 
 	"github.com/joshkerr/goplexcli/internal/config"
+	"github.com/joshkerr/goplexcli/internal/storage"
 )
 
 // tombstoneTTL is how long a removal is remembered. A tombstone only needs to
@@ -161,11 +161,12 @@ func (st *Store) load(now time.Time) (*Set, string, error) {
 		}
 		path = p
 	}
-	data, err := os.ReadFile(path)
+	// Read raw so Decode can migrate the v1 format.
+	data, _, err := storage.File[json.RawMessage]{Path: path, Name: "favorites"}.Load()
 	if err != nil {
 		return NewSet(), path, nil
 	}
-	s, err := Decode(data, now)
+	s, err := Decode(*data, now)
 	if err != nil {
 		// A corrupt file shouldn't brick favoriting; start fresh rather than
 		// failing every operation forever.
@@ -177,14 +178,7 @@ func (st *Store) load(now time.Time) (*Set, string, error) {
 // save writes the set. Callers must hold mu.
 func (st *Store) save(s *Set, path string, now time.Time) error {
 	s.prune(now)
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return err
-	}
-	data, err := json.MarshalIndent(s, "", "  ")
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(path, data, 0o644)
+	return storage.File[Set]{Path: path, Name: "favorites", Indent: true}.Save(s)
 }
 
 // Toggle flips a key and persists the change. Returns the new state: true if
@@ -0,0 +1,212 @@
+// Package history keeps a local log of every playback attempt: which item,
+// from where to where, for how long, and in which player. It lives in a JSON
+// file next to the media cache so "where did I stop last Tuesday?" can be
+// answered offline with 'goplexcli history item'.
+package history
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/config"
+	"github.com/joshkerr/goplexcli/internal/storage"
+)
+
+// schemaVersion is the history file format this build reads and writes.
+const schemaVersion = 1
+
+// maxSessions bounds the log; the oldest sessions are dropped first. Years of
+// nightly viewing fit comfortably.
+const maxSessions = 5000
+
+// Session is one playback attempt of one item. An item played as part of a
+// playlist gets its own session.
+type Session struct {
+	Key        string `json:"key"`
+	Title      string `json:"title"`
+	Player     string `json:"player"`
+	Started    int64  `json:"started"`  // unix seconds
+	Ended      int64  `json:"ended"`    // unix seconds
+	StartMs    int    `json:"start_ms"` // position when playback started
+	EndMs      int    `json:"end_ms"`   // last known position
+	DurationMs int    `json:"duration_ms,omitempty"`
+}
+
+// Watched returns how far playback advanced during the session. Seeking
+// backwards past the start counts as zero.
+func (s Session) Watched() time.Duration {
+	if s.EndMs <= s.StartMs {
+		return 0
+	}
+	return time.Duration(s.EndMs-s.StartMs) * time.Millisecond
+}
+
+// Percent returns how far into the item playback ended, 0-100, or -1 when
+// the item's duration wasn't known.
+func (s Session) Percent() int {
+	if s.DurationMs <= 0 {
+		return -1
+	}
+	return min(100, max(0, s.EndMs*100/s.DurationMs))
+}
+
+// Log is the full history file, oldest session first.
+type Log struct {
+	Version  int       `json:"version"`
+	Sessions []Session `json:"sessions"`
+}
+
+// Add appends sessions, dropping the oldest beyond maxSessions.
+func (l *Log) Add(sessions ...Session) {
+	l.Version = schemaVersion
+	l.Sessions = append(l.Sessions, sessions...)
+	if len(l.Sessions) > maxSessions {
+		l.Sessions = l.Sessions[len(l.Sessions)-maxSessions:]
+	}
+}
+
+// Item groups the sessions of one item, oldest first.
+type Item struct {
+	Key      string
+	Title    string
+	Sessions []Session
+}
+
+// Find returns the items whose title contains query (case-insensitive), most
+// recently played first.
+func (l *Log) Find(query string) []Item {
+	query = strings.ToLower(strings.TrimSpace(query))
+	byKey := map[string]*Item{}
+	var items []*Item
+	for _, s := range l.Sessions {
+		if !strings.Contains(strings.ToLower(s.Title), query) {
+			continue
+		}
+		it := byKey[s.Key]
+		if it == nil {
+			it = &Item{Key: s.Key}
+			byKey[s.Key] = it
+			items = append(items, it)
+		}
+		it.Title = s.Title // latest title wins if it was renamed
+		it.Sessions = append(it.Sessions, s)
+	}
+	for _, it := range items {
+		sort.SliceStable(it.Sessions, func(i, j int) bool { return it.Sessions[i].Started < it.Sessions[j].Started })
+	}
+
+	out := make([]Item, 0, len(items))
+	for _, it := range items {
+		out = append(out, *it)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Sessions[len(out[i].Sessions)-1].Started > out[j].Sessions[len(out[j].Sessions)-1].Started
+	})
+	return out
+}
+
+// Recent returns sessions newest first: those whose title contains query
+// (case-insensitive) and that started at or after since. A zero since
+// matches every session; limit caps the count when positive.
+func (l *Log) Recent(query string, since time.Time, limit int) []Session {
+	query = strings.ToLower(strings.TrimSpace(query))
+	var out []Session
+	for _, s := range l.Sessions {
+		if !strings.Contains(strings.ToLower(s.Title), query) {
+			continue
+		}
+		if !since.IsZero() && s.Started < since.Unix() {
+			continue
+		}
+		out = append(out, s)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Started > out[j].Started })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// ParseSince parses a --since value relative to now: a number of hours,
+// days or weeks ("12h", "7d", "2w") or a date ("2024-06-01", local time).
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return t, nil
+	}
+	units := map[string]time.Duration{"h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	if len(s) > 1 {
+		if unit, ok := units[s[len(s)-1:]]; ok {
+			if n, err := strconv.Atoi(s[:len(s)-1]); err == nil && n >= 0 {
+				return now.Add(-time.Duration(n) * unit), nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: use e.g. 12h, 7d, 2w or 2024-06-01", s)
+}
+
+// Path returns the JSON file holding the history, alongside the media cache.
+func Path() (string, error) {
+	dir, err := config.GetCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history.json"), nil
+}
+
+// SchemaVersion implements storage.Versioned.
+func (l *Log) SchemaVersion() int { return l.Version }
+
+// file returns the history stored at path.
+func file(path string) storage.File[Log] {
+	return storage.File[Log]{
+		Path:    path,
+		Name:    "playback history",
+		Indent:  true,
+		Version: schemaVersion,
+		New:     func() *Log { return &Log{Version: schemaVersion} },
+	}
+}
+
+// LoadFrom reads the history at path. A missing file yields an empty log.
+func LoadFrom(path string) (*Log, error) {
+	l, _, err := file(path).Load()
+	return l, err
+}
+
+// SaveTo writes the history to path atomically, so a concurrent reader never
+// sees a half-written file.
+func (l *Log) SaveTo(path string) error {
+	return file(path).Save(l)
+}
+
+// Load reads the default history file.
+func Load() (*Log, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	return LoadFrom(path)
+}
+
+// Record appends sessions to the default history file, holding its lock so
+// two players finishing at once don't lose a session. Unlike the usage
+// statistics, a corrupt file is reported rather than replaced, since it holds
+// data the user can't regenerate.
+func Record(sessions ...Session) error {
+	if len(sessions) == 0 {
+		return nil
+	}
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	return file(path).Update(func(l *Log) error {
+		l.Add(sessions...)
+		return nil
+	})
+}
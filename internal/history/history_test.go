@@ -0,0 +1,167 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/storage"
+)
+
+func TestWatched(t *testing.T) {
+	if got := (Session{StartMs: 60000, EndMs: 150000}).Watched(); got != 90*time.Second {
+		t.Errorf("Watched() = %v, want 1m30s", got)
+	}
+	if got := (Session{StartMs: 150000, EndMs: 60000}).Watched(); got != 0 {
+		t.Errorf("Watched() after seeking back = %v, want 0", got)
+	}
+}
+
+func TestFind(t *testing.T) {
+	l := &Log{}
+	l.Add(
+		Session{Key: "/library/metadata/1", Title: "Heat (1995)", Started: 100},
+		Session{Key: "/library/metadata/2", Title: "The Office - S02E01 - The Dundies", Started: 200},
+		Session{Key: "/library/metadata/1", Title: "Heat (1995)", Started: 300},
+		Session{Key: "/library/metadata/3", Title: "The Office - S02E02 - Sexual Harassment", Started: 250},
+	)
+
+	items := l.Find("heat")
+	if len(items) != 1 || len(items[0].Sessions) != 2 {
+		t.Fatalf("Find(heat) = %+v, want one item with two sessions", items)
+	}
+	if items[0].Sessions[0].Started != 100 {
+		t.Errorf("sessions should be oldest first: %+v", items[0].Sessions)
+	}
+
+	items = l.Find("OFFICE")
+	if len(items) != 2 {
+		t.Fatalf("Find(OFFICE) returned %d items, want 2", len(items))
+	}
+	if items[0].Key != "/library/metadata/3" {
+		t.Errorf("most recently played item should come first, got %s", items[0].Key)
+	}
+
+	if items := l.Find("alien"); len(items) != 0 {
+		t.Errorf("Find(alien) = %+v, want none", items)
+	}
+}
+
+func TestAddCapsSessions(t *testing.T) {
+	l := &Log{}
+	for i := 0; i < maxSessions+5; i++ {
+		l.Add(Session{Started: int64(i)})
+	}
+	if len(l.Sessions) != maxSessions {
+		t.Fatalf("kept %d sessions, want %d", len(l.Sessions), maxSessions)
+	}
+	if l.Sessions[0].Started != 5 {
+		t.Errorf("oldest sessions should be dropped first, first is %d", l.Sessions[0].Started)
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	l, err := LoadFrom(path)
+	if err != nil || len(l.Sessions) != 0 {
+		t.Fatalf("LoadFrom(missing) = %+v, %v; want empty log", l, err)
+	}
+
+	l.Add(Session{Key: "k", Title: "Heat (1995)", Player: "mpv", StartMs: 1000, EndMs: 5000})
+	if err := l.SaveTo(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadFrom(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Sessions) != 1 || got.Sessions[0] != l.Sessions[0] {
+		t.Errorf("round trip = %+v, want %+v", got.Sessions, l.Sessions)
+	}
+}
+
+func TestLoadFromNewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	if err := os.WriteFile(path, []byte(`{"version": 99, "sessions": []}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFrom(path); !errors.Is(err, storage.ErrNewerSchema) {
+		t.Errorf("LoadFrom(newer) error = %v, want ErrNewerSchema", err)
+	}
+}
+
+func TestLoadFromCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFrom(path); err == nil {
+		t.Error("expected an error for a corrupt history file")
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		s    Session
+		want int
+	}{
+		{Session{EndMs: 30000, DurationMs: 120000}, 25},
+		{Session{EndMs: 130000, DurationMs: 120000}, 100},
+		{Session{EndMs: 30000}, -1},
+	}
+	for _, tt := range tests {
+		if got := tt.s.Percent(); got != tt.want {
+			t.Errorf("%+v.Percent() = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestRecent(t *testing.T) {
+	l := &Log{}
+	l.Add(
+		Session{Key: "1", Title: "Heat (1995)", Started: 100},
+		Session{Key: "2", Title: "The Office - S02E01", Started: 300},
+		Session{Key: "3", Title: "The Office - S02E02", Started: 200},
+	)
+
+	keys := func(sessions []Session) string {
+		var k string
+		for _, s := range sessions {
+			k += s.Key
+		}
+		return k
+	}
+	if got := keys(l.Recent("", time.Time{}, 0)); got != "231" {
+		t.Errorf("Recent() = %s, want 231 (newest first)", got)
+	}
+	if got := keys(l.Recent("office", time.Time{}, 1)); got != "2" {
+		t.Errorf("Recent(office, limit 1) = %s, want 2", got)
+	}
+	if got := keys(l.Recent("", time.Unix(200, 0), 0)); got != "23" {
+		t.Errorf("Recent(since 200) = %s, want 23", got)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		"12h":        now.Add(-12 * time.Hour),
+		"7d":         now.AddDate(0, 0, -7),
+		"2w":         now.AddDate(0, 0, -14),
+		"2024-06-01": time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for in, want := range tests {
+		got, err := ParseSince(in, now)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("ParseSince(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "d", "7x", "-1d", "last week"} {
+		if _, err := ParseSince(bad, now); err == nil {
+			t.Errorf("ParseSince(%q): expected an error", bad)
+		}
+	}
+}
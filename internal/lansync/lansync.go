@@ -220,26 +220,25 @@ func (s *Server) serveMeta(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(m)
 }
 
-// serveCache streams the on-disk media.json gzipped. Serving the raw file (vs.
-// re-marshaling) preserves the exact LastUpdated stamp so freshness comparisons
-// stay meaningful as a cache hops between machines.
+// serveCache streams the on-disk cache gzipped, as JSON whatever its local
+// encoding so peers configured differently can still read it. ExportJSON
+// preserves the exact LastUpdated stamp so freshness comparisons stay
+// meaningful as a cache hops between machines.
 func (s *Server) serveCache(w http.ResponseWriter, r *http.Request) {
 	path, err := cache.GetCachePath()
 	if err != nil {
 		http.Error(w, "cache unavailable", http.StatusInternalServerError)
 		return
 	}
-	f, err := os.Open(path)
-	if err != nil {
+	if _, err := os.Stat(path); err != nil {
 		http.Error(w, "no cache", http.StatusNotFound)
 		return
 	}
-	defer f.Close()
 
 	w.Header().Set("Content-Type", "application/gzip")
 	gz := gzip.NewWriter(w)
 	defer gz.Close()
-	_, _ = io.Copy(gz, f)
+	_ = cache.ExportJSON(gz)
 }
 
 // serveFavorites shares the favorites set with peers. GET returns the local
@@ -371,7 +370,7 @@ func FetchMeta(ctx context.Context, p Peer) (Meta, error) {
 }
 
 // Pull downloads a peer's gzipped cache, decompresses it, atomically replaces
-// the local media.json, refreshes the freshness sidecar to match, and returns
+// the local cache (in the configured encoding), refreshes the freshness sidecar to match, and returns
 // the loaded cache.
 func Pull(ctx context.Context, p Peer) (*cache.Cache, error) {
 	path, err := cache.GetCachePath()
@@ -400,20 +399,7 @@ func Pull(ctx context.Context, p Peer) (*cache.Cache, error) {
 	}
 	defer gz.Close()
 
-	tmp, err := os.CreateTemp(filepath.Dir(path), ".media-sync-*.json.tmp")
-	if err != nil {
-		return nil, err
-	}
-	tmpPath := tmp.Name()
-	defer os.Remove(tmpPath)
-	if _, err := io.Copy(tmp, gz); err != nil {
-		tmp.Close()
-		return nil, err
-	}
-	if err := tmp.Close(); err != nil {
-		return nil, err
-	}
-	if err := os.Rename(tmpPath, path); err != nil {
+	if err := cache.ImportJSON(gz); err != nil {
 		return nil, err
 	}
 
@@ -6,6 +6,7 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	"strings"
 	"sync"
 )
 
@@ -21,8 +22,15 @@ var (
 
 	// output is the destination for log output (default: stderr)
 	output io.Writer = os.Stderr
+
+	// recent keeps the tail of everything logged so a crash report can include
+	// what happened just before the crash.
+	recent = &ringBuffer{max: recentLinesMax}
 )
 
+// recentLinesMax is how many log lines RecentLines retains.
+const recentLinesMax = 50
+
 // Level constants for convenience
 const (
 	LevelDebug = slog.LevelDebug
@@ -48,7 +56,7 @@ func Init(opts ...Option) {
 		logLevel.Set(cfg.level)
 		output = cfg.output
 
-		handler := slog.NewTextHandler(output, &slog.HandlerOptions{
+		handler := slog.NewTextHandler(io.MultiWriter(output, recent), &slog.HandlerOptions{
 			Level: logLevel,
 		})
 
@@ -149,3 +157,39 @@ func Enabled(level slog.Level) bool {
 func IsVerbose() bool {
 	return Enabled(LevelDebug)
 }
+
+// RecentLines returns the most recent log lines, oldest first.
+func RecentLines() []string {
+	return recent.lines()
+}
+
+// ringBuffer is an io.Writer that keeps the last max lines written to it.
+// slog handlers write one record per call, so each Write is one line.
+type ringBuffer struct {
+	mu   sync.Mutex
+	max  int
+	buf  []string
+	next int
+}
+
+func (r *ringBuffer) Write(p []byte) (int, error) {
+	line := strings.TrimRight(string(p), "\n")
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.buf) < r.max {
+		r.buf = append(r.buf, line)
+	} else {
+		r.buf[r.next] = line
+		r.next = (r.next + 1) % r.max
+	}
+	return len(p), nil
+}
+
+func (r *ringBuffer) lines() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]string, 0, len(r.buf))
+	out = append(out, r.buf[r.next:]...)
+	out = append(out, r.buf[:r.next]...)
+	return out
+}
@@ -0,0 +1,33 @@
+package logging
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRingBufferKeepsTail(t *testing.T) {
+	r := &ringBuffer{max: 3}
+	for i := 1; i <= 5; i++ {
+		fmt.Fprintf(r, "line %d\n", i)
+	}
+
+	got := r.lines()
+	want := []string{"line 3", "line 4", "line 5"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d lines, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestRingBufferPartial(t *testing.T) {
+	r := &ringBuffer{max: 3}
+	fmt.Fprint(r, "only\n")
+
+	if got := r.lines(); len(got) != 1 || got[0] != "only" {
+		t.Errorf("got %v, want [only]", got)
+	}
+}
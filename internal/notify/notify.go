@@ -0,0 +1,55 @@
+// Package notify tells the user when long operations finish, with desktop
+// notifications and webhooks. Desktop notifications shell out to the
+// platform's own tool: osascript on macOS, notify-send on Linux and other
+// Unix systems, and PowerShell toasts on Windows.
+package notify
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// appName is shown as the notification's source where the platform
+// supports it.
+const appName = "goplexcli"
+
+// Send shows a notification with the given title and message. It returns an
+// error if the platform's notification tool is missing or fails.
+func Send(title, message string) error {
+	cmd := command(runtime.GOOS, title, message)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to send notification: %w: %s", err, out)
+	}
+	return nil
+}
+
+// windowsToast shows a toast through the WinRT notification API. The title
+// and message arrive in environment variables so they never need escaping.
+const windowsToast = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $xml.GetElementsByTagName('text')
+$text.Item(0).AppendChild($xml.CreateTextNode($env:GOPLEXCLI_NOTIFY_TITLE)) > $null
+$text.Item(1).AppendChild($xml.CreateTextNode($env:GOPLEXCLI_NOTIFY_MESSAGE)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('` + appName + `').Show([Windows.UI.Notifications.ToastNotification]::new($xml))`
+
+// command builds the notification command for goos. Title and message are
+// passed as arguments or environment variables, never spliced into a
+// script, so quotes in media titles are harmless.
+func command(goos, title, message string) *exec.Cmd {
+	switch goos {
+	case "darwin":
+		return exec.Command("osascript",
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			title, message)
+	case "windows":
+		cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", windowsToast)
+		cmd.Env = append(os.Environ(), "GOPLEXCLI_NOTIFY_TITLE="+title, "GOPLEXCLI_NOTIFY_MESSAGE="+message)
+		return cmd
+	default:
+		return exec.Command("notify-send", "--app-name="+appName, title, message)
+	}
+}
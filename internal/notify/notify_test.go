@@ -0,0 +1,29 @@
+package notify
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	title, message := "Download complete", `"Heat" (1995)`
+
+	mac := command("darwin", title, message)
+	if mac.Args[0] != "osascript" || !slices.Equal(mac.Args[len(mac.Args)-2:], []string{title, message}) {
+		t.Errorf("darwin command = %q, want osascript with title and message as arguments", mac.Args)
+	}
+
+	linux := command("linux", title, message)
+	if want := []string{"notify-send", "--app-name=goplexcli", title, message}; !slices.Equal(linux.Args, want) {
+		t.Errorf("linux command = %q, want %q", linux.Args, want)
+	}
+
+	win := command("windows", title, message)
+	if win.Args[0] != "powershell" || strings.Contains(strings.Join(win.Args, " "), "Heat") {
+		t.Errorf("windows command = %q, want powershell without the message in the script", win.Args)
+	}
+	if !slices.Contains(win.Env, "GOPLEXCLI_NOTIFY_MESSAGE="+message) {
+		t.Error("windows command should pass the message in the environment")
+	}
+}
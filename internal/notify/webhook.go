@@ -0,0 +1,95 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"text/template"
+	"time"
+)
+
+// Event is something worth telling the user about once it has happened.
+// Webhooks receive it as their payload.
+type Event struct {
+	Name    string    `json:"event"` // one of the Event* constants
+	Title   string    `json:"title"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// Event names.
+const (
+	EventDownloadComplete = "download.complete"
+	EventQueueComplete    = "queue.complete"
+	EventPlaybackFinished = "playback.finished"
+	EventCacheUpdated     = "cache.updated"
+	EventCacheReindexed   = "cache.reindexed"
+)
+
+// webhookTimeout bounds a webhook POST, so a slow endpoint can't hold up the
+// command that sent it.
+const webhookTimeout = 10 * time.Second
+
+var webhookClient = &http.Client{Timeout: webhookTimeout}
+
+// RenderPayload builds the webhook body for e. Without a template it is e as
+// JSON. A template is a Go text/template executed with e; {{json .Message}}
+// writes a value as a quoted JSON string, e.g. for Discord:
+//
+//	{"content": {{json (printf "%s: %s" .Title .Message)}}}
+func RenderPayload(tmpl string, e Event) ([]byte, error) {
+	if tmpl == "" {
+		return json.Marshal(e)
+	}
+	t, err := template.New("webhook").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}).Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, e); err != nil {
+		return nil, fmt.Errorf("failed to render webhook template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// PostWebhook POSTs e to webhookURL, rendered with tmpl (see RenderPayload).
+// Bodies that are valid JSON are sent as application/json and anything else
+// as plain text, which suits services like ntfy. Errors never include the
+// URL, since webhook URLs often embed a secret.
+func PostWebhook(ctx context.Context, webhookURL, tmpl string, e Event) error {
+	body, err := RenderPayload(tmpl, e)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL")
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if json.Valid(body) {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPostWebhook(t *testing.T) {
+	var gotType, gotBody string
+	status := http.StatusNoContent
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotType, gotBody = r.Header.Get("Content-Type"), string(b)
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	e := Event{Name: EventDownloadComplete, Title: "Download complete", Message: `"Heat" (1995)`, Time: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	ctx := context.Background()
+
+	if err := PostWebhook(ctx, ts.URL, "", e); err != nil {
+		t.Fatal(err)
+	}
+	var got Event
+	if err := json.Unmarshal([]byte(gotBody), &got); err != nil || got != e || gotType != "application/json" {
+		t.Errorf("default payload = %s (%s), want the event as JSON", gotBody, gotType)
+	}
+
+	if err := PostWebhook(ctx, ts.URL, `{"content": {{json (printf "%s: %s" .Title .Message)}}}`, e); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"content": "Download complete: \"Heat\" (1995)"}`; gotBody != want {
+		t.Errorf("templated payload = %s, want %s", gotBody, want)
+	}
+
+	if err := PostWebhook(ctx, ts.URL, "{{.Title}} at {{.Time.Format \"15:04\"}}", e); err != nil {
+		t.Fatal(err)
+	}
+	if gotBody != "Download complete at 03:04" || !strings.HasPrefix(gotType, "text/plain") {
+		t.Errorf("plain payload = %q (%s)", gotBody, gotType)
+	}
+
+	if err := PostWebhook(ctx, ts.URL, "{{.Nope}}", e); err == nil {
+		t.Error("a template referencing an unknown field should fail")
+	}
+
+	status = http.StatusUnauthorized
+	if err := PostWebhook(ctx, ts.URL, "", e); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("non-2xx response: err = %v, want a 401 error", err)
+	}
+}
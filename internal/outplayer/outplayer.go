@@ -28,6 +28,8 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshkerr/goplexcli/internal/logging"
+	"github.com/joshkerr/goplexcli/internal/ui"
 	rclone "github.com/joshkerr/rclone-golib"
 )
 
@@ -119,7 +121,7 @@ func Upload(ctx context.Context, rclonePaths []string, baseURL, dir, rcloneBinar
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		p := tea.NewProgram(rclone.NewModel(manager), teaOptions...)
+		p := tea.NewProgram(rclone.NewModel(manager), append(ui.TeaOptions(), teaOptions...)...)
 		close(uiReady)
 		if _, err := p.Run(); err != nil {
 			uiErr = err
@@ -156,6 +158,7 @@ func Upload(ctx context.Context, rclonePaths []string, baseURL, dir, rcloneBinar
 // rclone.
 func uploadOne(ctx context.Context, uploadURL, uploadPath, src, filename, rcloneBinary string, manager *rclone.Manager, transferID string, total int64) error {
 	cmd := exec.CommandContext(ctx, rcloneBinary, "cat", src)
+	logging.Debug("running rclone", "command", "cat", "source", src)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 	stdout, err := cmd.StdoutPipe()
@@ -0,0 +1,198 @@
+// Package overseerr searches for and requests media through Overseerr or
+// Jellyseerr, which share one API, so titles that aren't on the Plex server
+// yet can be requested from the command line. It is optional and only used
+// when the config has the instance's URL and API key.
+package overseerr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// httpTimeout bounds a single API request.
+const httpTimeout = 15 * time.Second
+
+// ErrUnauthorized means the instance rejected the API key.
+var ErrUnauthorized = errors.New("Overseerr rejected the API key")
+
+// Client talks to one Overseerr or Jellyseerr instance.
+type Client struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+}
+
+// New returns a client for the instance at baseURL.
+func New(baseURL, apiKey string) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(strings.TrimSpace(baseURL), "/"),
+		apiKey:  strings.TrimSpace(apiKey),
+		http:    &http.Client{Timeout: httpTimeout},
+	}
+}
+
+// Status is how far along a title is on the media server, as Overseerr
+// tracks it.
+type Status int
+
+// Media statuses, numbered as in the Overseerr API.
+const (
+	StatusUnknown            Status = 1
+	StatusPending            Status = 2
+	StatusProcessing         Status = 3
+	StatusPartiallyAvailable Status = 4
+	StatusAvailable          Status = 5
+)
+
+// String describes the status for a result list; titles nobody has
+// requested yet are "".
+func (s Status) String() string {
+	switch s {
+	case StatusPending:
+		return "requested"
+	case StatusProcessing:
+		return "processing"
+	case StatusPartiallyAvailable:
+		return "partially available"
+	case StatusAvailable:
+		return "available"
+	}
+	return ""
+}
+
+// Requestable reports whether a request for a title with this status would
+// add anything. Partially available shows can still have seasons requested.
+func (s Status) Requestable() bool {
+	return s != StatusPending && s != StatusProcessing && s != StatusAvailable
+}
+
+// Result is a movie or show found by Search.
+type Result struct {
+	ID        int    // TMDB ID
+	MediaType string // "movie" or "tv"
+	Title     string
+	Year      int
+	Status    Status
+}
+
+// String formats the result as "Title (Year)", or just the title when the
+// year is unknown.
+func (r Result) String() string {
+	if r.Year > 0 {
+		return fmt.Sprintf("%s (%d)", r.Title, r.Year)
+	}
+	return r.Title
+}
+
+// searchResponse is the part of /search's reply we use.
+type searchResponse struct {
+	Results []struct {
+		ID           int    `json:"id"`
+		MediaType    string `json:"mediaType"`
+		Title        string `json:"title"` // movies
+		Name         string `json:"name"`  // shows
+		ReleaseDate  string `json:"releaseDate"`
+		FirstAirDate string `json:"firstAirDate"`
+		MediaInfo    *struct {
+			Status Status `json:"status"`
+		} `json:"mediaInfo"`
+	} `json:"results"`
+}
+
+// Search returns the movies and shows matching query, best match first.
+func (c *Client) Search(ctx context.Context, query string) ([]Result, error) {
+	// Overseerr rejects "+" for spaces; it wants them percent-encoded.
+	q := strings.ReplaceAll(url.QueryEscape(query), "+", "%20")
+	var resp searchResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/search?page=1&query="+q, nil, &resp); err != nil {
+		return nil, err
+	}
+	var results []Result
+	for _, r := range resp.Results {
+		if r.MediaType != "movie" && r.MediaType != "tv" {
+			continue // people
+		}
+		res := Result{ID: r.ID, MediaType: r.MediaType, Title: r.Title, Status: StatusUnknown}
+		date := r.ReleaseDate
+		if r.MediaType == "tv" {
+			res.Title, date = r.Name, r.FirstAirDate
+		}
+		if len(date) >= 4 {
+			res.Year, _ = strconv.Atoi(date[:4])
+		}
+		if r.MediaInfo != nil {
+			res.Status = r.MediaInfo.Status
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// Request asks for r to be added to the media server. Shows are requested
+// with all their seasons. Whether the request needs approval is up to the
+// instance's settings for the API key's user.
+func (c *Client) Request(ctx context.Context, r Result) error {
+	body := map[string]any{"mediaType": r.MediaType, "mediaId": r.ID}
+	if r.MediaType == "tv" {
+		body["seasons"] = "all"
+	}
+	return c.do(ctx, http.MethodPost, "/api/v1/request", body, nil)
+}
+
+// do sends a request with body (if any) as JSON and decodes the reply into v
+// (if non-nil). Errors carry the instance's message, such as "Request for
+// this media already exists".
+func (c *Client) do(ctx context.Context, method, path string, body, v any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("invalid Overseerr URL: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Api-Key", c.apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach Overseerr: %w", err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		var msg struct {
+			Message string `json:"message"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(data, &msg) == nil && msg.Message != "" {
+			return fmt.Errorf("Overseerr returned %s: %s", resp.Status, msg.Message)
+		}
+		return fmt.Errorf("Overseerr returned %s", resp.Status)
+	}
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse Overseerr response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package overseerr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSearchAndRequest(t *testing.T) {
+	var requested map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/search":
+			if !strings.Contains(r.URL.RawQuery, "query=the%20bear") {
+				t.Errorf("query = %q; spaces must be %%20", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`{"results":[
+				{"id":136315,"mediaType":"tv","name":"The Bear","firstAirDate":"2022-06-23","mediaInfo":{"status":4}},
+				{"id":2,"mediaType":"person","name":"Bear Grylls"},
+				{"id":17,"mediaType":"movie","title":"The Bear","releaseDate":"1988-10-19"}]}`))
+		case "POST /api/v1/request":
+			_ = json.NewDecoder(r.Body).Decode(&requested)
+			if requested["mediaId"] == float64(17) {
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte(`{"message":"Request for this media already exists."}`))
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	c := New(srv.URL+"/", "key")
+	ctx := context.Background()
+
+	results, err := c.Search(ctx, "the bear")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("results = %+v", results)
+	}
+	show, movie := results[0], results[1]
+	if show.String() != "The Bear (2022)" || show.MediaType != "tv" || show.Status != StatusPartiallyAvailable || !show.Status.Requestable() {
+		t.Errorf("show = %+v", show)
+	}
+	if movie.String() != "The Bear (1988)" || movie.Status != StatusUnknown || movie.Status.String() != "" {
+		t.Errorf("movie = %+v", movie)
+	}
+
+	if err := c.Request(ctx, show); err != nil {
+		t.Fatal(err)
+	}
+	if requested["mediaType"] != "tv" || requested["mediaId"] != float64(136315) || requested["seasons"] != "all" {
+		t.Errorf("requested = %v", requested)
+	}
+	err = c.Request(ctx, movie)
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("duplicate request error = %v", err)
+	}
+
+	if _, err := New(srv.URL, "wrong").Search(ctx, "x"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("bad key error = %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package player
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Custom is a user-defined external player, such as smplayer, celluloid or
+// a wrapper script. ArgsTemplate is the argument list passed to Path, with
+// the placeholders {url}, {start}, {title} and {ipc} filled in per run.
+type Custom struct {
+	Name         string
+	Path         string
+	ArgsTemplate []string
+	// SupportsIPC marks the player as serving mpv's JSON IPC protocol on
+	// the {ipc} socket, so progress tracking can use the mpv client.
+	SupportsIPC bool
+}
+
+var (
+	customPlayersMu sync.RWMutex
+	customPlayers   []Custom
+)
+
+// SetCustomPlayers registers the players PlayMultipleWith and
+// IsPlayerAvailable accept by name besides the built-ins. Like
+// plex.SetLibraryFilter, call it once at startup.
+func SetCustomPlayers(players []Custom) {
+	customPlayersMu.Lock()
+	defer customPlayersMu.Unlock()
+	customPlayers = players
+}
+
+// customPlayer returns the registered player called name, matched without
+// regard to case.
+func customPlayer(name string) (Custom, bool) {
+	customPlayersMu.RLock()
+	defer customPlayersMu.RUnlock()
+	for _, p := range customPlayers {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+	return Custom{}, false
+}
+
+// SupportsIPC reports whether the named player can be tracked through the
+// mpv IPC client: mpv, IINA, and custom players that declare it.
+func SupportsIPC(name string) bool {
+	switch name {
+	case "mpv", "iina":
+		return true
+	case "vlc":
+		return false
+	}
+	p, ok := customPlayer(name)
+	return ok && p.SupportsIPC
+}
+
+// buildCustomArgs fills in template. An argument that is exactly "{url}"
+// becomes one argument per URL so the player gets the whole playlist; {url}
+// inside a longer argument uses the first URL.
+func buildCustomArgs(template, urls []string, opts PlaybackOptions) []string {
+	first := ""
+	if len(urls) > 0 {
+		first = urls[0]
+	}
+	r := strings.NewReplacer(
+		"{url}", first,
+		"{start}", strconv.Itoa(opts.StartPos),
+		"{title}", opts.title(0),
+		"{ipc}", opts.SocketPath,
+	)
+
+	args := make([]string, 0, len(template)+len(urls))
+	for _, a := range template {
+		if a == "{url}" {
+			args = append(args, urls...)
+			continue
+		}
+		args = append(args, r.Replace(a))
+	}
+	return args
+}
+
+// playWithCustom launches a custom player and reports how the run ended.
+// A template without any {url} gets the URLs appended.
+func playWithCustom(p Custom, streamURLs []string, opts PlaybackOptions) (*PlayOutcome, error) {
+	if len(streamURLs) == 0 {
+		return nil, fmt.Errorf("no stream URLs provided")
+	}
+	if _, err := exec.LookPath(p.Path); err != nil {
+		return nil, fmt.Errorf("%s not found at %q. Check its path under players in config", p.Name, p.Path)
+	}
+
+	template := p.ArgsTemplate
+	if !strings.Contains(strings.Join(template, " "), "{url}") {
+		template = append(append([]string{}, template...), "{url}")
+	}
+	return runPlayer(p.Name, p.Path, buildCustomArgs(template, streamURLs, opts))
+}
@@ -0,0 +1,44 @@
+package player
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBuildCustomArgs(t *testing.T) {
+	urls := []string{"http://example.com/1.mkv", "http://example.com/2.mkv"}
+	opts := PlaybackOptions{SocketPath: "/tmp/mpv.sock", StartPos: 90, Titles: []string{"Heat"}}
+
+	args := buildCustomArgs([]string{"--title={title}", "--start={start}", "--ipc={ipc}", "{url}"}, urls, opts)
+	want := []string{"--title=Heat", "--start=90", "--ipc=/tmp/mpv.sock", urls[0], urls[1]}
+	if !slices.Equal(args, want) {
+		t.Errorf("args = %v, want %v", args, want)
+	}
+
+	// {url} inside a longer argument takes only the first URL.
+	args = buildCustomArgs([]string{"--file={url}"}, urls, opts)
+	if !slices.Equal(args, []string{"--file=" + urls[0]}) {
+		t.Errorf("embedded {url} = %v", args)
+	}
+}
+
+func TestCustomPlayerRegistry(t *testing.T) {
+	SetCustomPlayers([]Custom{
+		{Name: "smplayer", Path: "smplayer"},
+		{Name: "wrapper", Path: "/bin/wrapper", SupportsIPC: true},
+	})
+	defer SetCustomPlayers(nil)
+
+	if _, ok := customPlayer("SMPlayer"); !ok {
+		t.Error("lookup should ignore case")
+	}
+	if SupportsIPC("smplayer") || !SupportsIPC("wrapper") {
+		t.Error("SupportsIPC should follow the definition")
+	}
+	if !SupportsIPC("mpv") || !SupportsIPC("iina") || SupportsIPC("vlc") {
+		t.Error("SupportsIPC wrong for built-in players")
+	}
+	if SupportsIPC("unknown") {
+		t.Error("unknown players have no IPC")
+	}
+}
@@ -0,0 +1,77 @@
+package player
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// macIINAPath is the command-line launcher inside the IINA app bundle. IINA
+// can also install it on PATH as "iina" (Settings → Utilities).
+const macIINAPath = "/Applications/IINA.app/Contents/MacOS/iina-cli"
+
+// resolveIINAPath returns the IINA launcher to run: iinaPath if set,
+// otherwise "iina" from PATH, falling back to the app bundle.
+func resolveIINAPath(iinaPath string) string {
+	if iinaPath != "" {
+		return iinaPath
+	}
+	if _, err := exec.LookPath("iina"); err != nil {
+		if _, err := os.Stat(macIINAPath); err == nil {
+			return macIINAPath
+		}
+	}
+	return "iina"
+}
+
+// IsIINAAvailable checks if IINA is available on the system.
+func IsIINAAvailable(iinaPath string) bool {
+	_, err := exec.LookPath(resolveIINAPath(iinaPath))
+	return err == nil
+}
+
+// buildIINAArgs constructs the argument list for iina-cli. IINA embeds mpv
+// and accepts any mpv option spelled --mpv-<option>, so this is the mpv
+// argument list translated: the IPC server gives progress tracking through
+// the same client as mpv, and presets keep working. IINA rejects the "--no-"
+// form, so those flags become "--mpv-<option>=no". --keep-running makes
+// iina-cli wait for IINA to quit rather than exit once it has launched, so
+// the tracker sees the whole of playback.
+func buildIINAArgs(urls []string, socketPath string, startPos int, extraArgs []string) []string {
+	mpvArgs := buildMPVArgs(nil, socketPath, startPos, extraArgs)
+
+	args := make([]string, 0, len(mpvArgs)+len(urls)+1)
+	args = append(args, "--keep-running")
+	for _, a := range mpvArgs {
+		opt := strings.TrimPrefix(a, "--")
+		if name, ok := strings.CutPrefix(opt, "no-"); ok && !strings.Contains(name, "=") {
+			opt = name + "=no"
+		}
+		args = append(args, "--mpv-"+opt)
+	}
+	return append(args, urls...)
+}
+
+// PlayMultipleWithIINA launches IINA with the given options and reports how
+// the run ended. HTTPPort and HTTPPassword do not apply, and Titles and
+// SubFiles only for a single URL. The outcome is
+// non-nil whenever IINA actually ran.
+func PlayMultipleWithIINA(streamURLs []string, iinaPath string, opts PlaybackOptions) (*PlayOutcome, error) {
+	if len(streamURLs) == 0 {
+		return nil, fmt.Errorf("no stream URLs provided")
+	}
+
+	iinaPath = resolveIINAPath(iinaPath)
+	if _, err := exec.LookPath(iinaPath); err != nil {
+		return nil, fmt.Errorf("iina not found in PATH. Please install IINA or specify iina_path in config")
+	}
+
+	// iina-cli has no per-file options, so a title or subtitles would apply
+	// to the whole playlist; only a single item gets them.
+	extraArgs := opts.ExtraArgs
+	if len(streamURLs) == 1 {
+		extraArgs = append(opts.itemArgs(0), extraArgs...)
+	}
+	return runPlayer("iina", iinaPath, buildIINAArgs(streamURLs, opts.SocketPath, opts.StartPos, extraArgs))
+}
@@ -0,0 +1,30 @@
+package player
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBuildIINAArgs(t *testing.T) {
+	urls := []string{"http://example.com/1.mkv", "http://example.com/2.mkv"}
+
+	args := buildIINAArgs(urls, "/tmp/mpv.sock", 90, []string{"--audio-channels=stereo"})
+	for _, want := range []string{"--mpv-input-ipc-server=/tmp/mpv.sock", "--mpv-start=90", "--mpv-audio-channels=stereo", "--mpv-hr-seek=yes"} {
+		if !slices.Contains(args, want) {
+			t.Errorf("missing %s in %v", want, args)
+		}
+	}
+	if args[0] != "--keep-running" {
+		t.Errorf("want --keep-running first so iina-cli waits for playback: %v", args)
+	}
+	if !slices.Equal(args[len(args)-2:], urls) {
+		t.Errorf("URLs should come last: %v", args)
+	}
+
+	// Without tracking mpv gets --no-resume-playback, which IINA only
+	// accepts in its =no form.
+	args = buildIINAArgs(urls, "", 0, nil)
+	if !slices.Contains(args, "--mpv-resume-playback=no") {
+		t.Errorf("want --mpv-resume-playback=no in %v", args)
+	}
+}
@@ -1,5 +1,6 @@
 // Package player provides media playback functionality using external players.
-// It supports playing single files or multiple files as a playlist using mpv.
+// It supports playing single files or multiple files as a playlist using mpv,
+// VLC, IINA or a user-defined player.
 package player
 
 import (
@@ -20,15 +21,20 @@ var plexTokenPattern = regexp.MustCompile(`(?i)(X-Plex-Token=)[^&\s]+`)
 // or was killed by a signal. A clean exit — including the user quitting — is
 // not a PlaybackError.
 type PlaybackError struct {
+	Player   string // player name for the message; "" means mpv
 	ExitCode int    // mpv's exit code; -1 when killed by a signal
 	Signal   string // signal name when killed by a signal, "" otherwise
 	Detail   string // most relevant stderr line, "" if mpv wrote nothing useful
 }
 
 func (e *PlaybackError) Error() string {
-	cause := fmt.Sprintf("mpv exited %d", e.ExitCode)
+	name := e.Player
+	if name == "" {
+		name = "mpv"
+	}
+	cause := fmt.Sprintf("%s exited %d", name, e.ExitCode)
 	if e.Signal != "" {
-		cause = "mpv died: " + e.Signal
+		cause = name + " died: " + e.Signal
 	}
 	if e.Detail == "" {
 		return cause
@@ -115,6 +121,108 @@ func redactPlexToken(line string) string {
 type PlaybackOptions struct {
 	SocketPath string // IPC socket path for progress tracking (Unix socket or Windows named pipe, empty to disable)
 	StartPos   int    // Start position in seconds (0 to start from beginning)
+
+	// HTTPPort and HTTPPassword enable VLC's HTTP interface on 127.0.0.1
+	// for progress tracking (VLC only; port 0 disables it).
+	HTTPPort     int
+	HTTPPassword string
+
+	// ExtraArgs are additional mpv options, e.g. from a playback preset
+	// (see ResolvePreset).
+	ExtraArgs []string
+
+	// Titles are the names players show for each URL, by index, instead
+	// of the URL itself; SubFiles are external subtitle files or URLs to
+	// load with each. Either may be shorter than the URL list.
+	Titles   []string
+	SubFiles [][]string
+}
+
+// title returns the title of the i'th URL, or "".
+func (o PlaybackOptions) title(i int) string {
+	if i < len(o.Titles) {
+		return o.Titles[i]
+	}
+	return ""
+}
+
+// itemArgs returns the mpv options that describe the i'th URL: its title
+// and subtitle files.
+func (o PlaybackOptions) itemArgs(i int) []string {
+	var args []string
+	if t := o.title(i); t != "" {
+		args = append(args, "--force-media-title="+t)
+	}
+	if i < len(o.SubFiles) {
+		for _, sub := range o.SubFiles[i] {
+			args = append(args, "--sub-file="+sub)
+		}
+	}
+	return args
+}
+
+// mpvPlaylist returns urls with each one's itemArgs wrapped around it in a
+// per-file "--{ ... --}" group, so every playlist entry gets its own title
+// and subtitles.
+func mpvPlaylist(urls []string, opts PlaybackOptions) []string {
+	out := make([]string, 0, len(urls))
+	for i, u := range urls {
+		item := opts.itemArgs(i)
+		if len(item) == 0 {
+			out = append(out, u)
+			continue
+		}
+		out = append(out, "--{")
+		out = append(out, item...)
+		out = append(out, u, "--}")
+	}
+	return out
+}
+
+// Window sets how the player window starts. Volume 0 and an empty Profile
+// keep the player's own settings.
+type Window struct {
+	Fullscreen bool
+	OnTop      bool
+	Volume     int
+	Profile    string // mpv profile from mpv.conf; mpv and IINA only
+}
+
+var (
+	windowMu sync.RWMutex
+	window   Window
+)
+
+// SetWindow applies w to every playback. Like SetCustomPlayers, call it
+// once at startup.
+func SetWindow(w Window) {
+	windowMu.Lock()
+	defer windowMu.Unlock()
+	window = w
+}
+
+func currentWindow() Window {
+	windowMu.RLock()
+	defer windowMu.RUnlock()
+	return window
+}
+
+// mpvArgs returns the mpv options for w.
+func (w Window) mpvArgs() []string {
+	var args []string
+	if w.Profile != "" {
+		args = append(args, "--profile="+w.Profile)
+	}
+	if w.Fullscreen {
+		args = append(args, "--fullscreen")
+	}
+	if w.OnTop {
+		args = append(args, "--ontop")
+	}
+	if w.Volume > 0 {
+		args = append(args, fmt.Sprintf("--volume=%d", w.Volume))
+	}
+	return args
 }
 
 // MPVPlayer implements the Player interface using mpv media player.
@@ -159,8 +267,10 @@ func (p *MPVPlayer) getPath() string {
 	return p.Path
 }
 
-// buildMPVArgs constructs the argument list for MPV.
-func buildMPVArgs(urls []string, socketPath string, startPos int) []string {
+// buildMPVArgs constructs the argument list for MPV. The Window options and
+// then extraArgs go before the URLs so they apply to the whole playlist, and
+// a preset can override the window settings.
+func buildMPVArgs(urls []string, socketPath string, startPos int, extraArgs []string) []string {
 	args := []string{
 		"--force-seekable=yes",
 		"--hr-seek=yes",
@@ -179,6 +289,8 @@ func buildMPVArgs(urls []string, socketPath string, startPos int) []string {
 		args = append(args, fmt.Sprintf("--start=%d", startPos))
 	}
 
+	args = append(args, currentWindow().mpvArgs()...)
+	args = append(args, extraArgs...)
 	args = append(args, urls...)
 	return args
 }
@@ -196,7 +308,7 @@ func playWithMPV(mpvPath string, streamURLs []string, opts PlaybackOptions) (*Pl
 	}
 
 	// Build mpv command using buildMPVArgs
-	args := buildMPVArgs(streamURLs, opts.SocketPath, opts.StartPos)
+	args := buildMPVArgs(mpvPlaylist(streamURLs, opts), opts.SocketPath, opts.StartPos, opts.ExtraArgs)
 
 	cmd := exec.Command(mpvPath, args...)
 
@@ -265,6 +377,44 @@ func PlayMultipleWithOptions(streamURLs []string, mpvPath string, opts PlaybackO
 	return playWithMPV(mpvPath, streamURLs, opts)
 }
 
+// PlayMultipleWith launches the named player ("mpv", "vlc", "iina" or a
+// player registered with SetCustomPlayers) with custom options and reports
+// how the run ended.
+func PlayMultipleWith(name, path string, streamURLs []string, opts PlaybackOptions) (*PlayOutcome, error) {
+	switch name {
+	case "vlc":
+		return PlayMultipleWithVLC(streamURLs, path, opts)
+	case "iina":
+		return PlayMultipleWithIINA(streamURLs, path, opts)
+	}
+	if p, ok := customPlayer(name); ok {
+		if path != "" {
+			p.Path = path
+		}
+		return playWithCustom(p, streamURLs, opts)
+	}
+	return PlayMultipleWithOptions(streamURLs, path, opts)
+}
+
+// IsPlayerAvailable checks if the named player ("mpv", "vlc", "iina" or a
+// custom player) is available.
+func IsPlayerAvailable(name, path string) bool {
+	switch name {
+	case "vlc":
+		return IsVLCAvailable(path)
+	case "iina":
+		return IsIINAAvailable(path)
+	}
+	if p, ok := customPlayer(name); ok {
+		if path == "" {
+			path = p.Path
+		}
+		_, err := exec.LookPath(path)
+		return err == nil
+	}
+	return IsAvailable(path)
+}
+
 // IsAvailable checks if MPV is available on the system.
 // This is a convenience function for checking availability.
 func IsAvailable(mpvPath string) bool {
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -46,7 +47,7 @@ func TestBuildMPVArgs(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			args := buildMPVArgs(tt.urls, tt.socketPath, tt.startPos)
+			args := buildMPVArgs(tt.urls, tt.socketPath, tt.startPos, nil)
 
 			hasIPC := false
 			hasStart := false
@@ -69,6 +70,49 @@ func TestBuildMPVArgs(t *testing.T) {
 	}
 }
 
+func TestMPVPlaylist(t *testing.T) {
+	urls := []string{"http://example.com/1.mkv", "http://example.com/2.mkv", "http://example.com/3.mkv"}
+	opts := PlaybackOptions{
+		Titles:   []string{"Severance S01E01", "", "Severance S01E03"},
+		SubFiles: [][]string{nil, {"http://example.com/2.srt"}},
+	}
+
+	got := mpvPlaylist(urls, opts)
+	want := []string{
+		"--{", "--force-media-title=Severance S01E01", urls[0], "--}",
+		"--{", "--sub-file=http://example.com/2.srt", urls[1], "--}",
+		"--{", "--force-media-title=Severance S01E03", urls[2], "--}",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("mpvPlaylist = %v, want %v", got, want)
+	}
+
+	if got := mpvPlaylist(urls[:1], PlaybackOptions{}); !slices.Equal(got, urls[:1]) {
+		t.Errorf("without titles the URLs should pass through: %v", got)
+	}
+}
+
+func TestWindowArgs(t *testing.T) {
+	SetWindow(Window{Fullscreen: true, OnTop: true, Volume: 70, Profile: "big-screen"})
+	defer SetWindow(Window{})
+
+	args := buildMPVArgs([]string{"http://example.com/a.mkv"}, "", 0, []string{"--volume=40"})
+	for _, want := range []string{"--profile=big-screen", "--fullscreen", "--ontop", "--volume=70"} {
+		if !slices.Contains(args, want) {
+			t.Errorf("missing %s in %v", want, args)
+		}
+	}
+	// A preset comes later, so it wins over the configured volume.
+	if slices.Index(args, "--volume=40") < slices.Index(args, "--volume=70") {
+		t.Errorf("preset should follow the window options: %v", args)
+	}
+
+	args = buildVLCArgs([]string{"http://example.com/a.mkv"}, nil, 0, "", 0)
+	if !slices.Contains(args, "--fullscreen") || !slices.Contains(args, "--video-on-top") {
+		t.Errorf("VLC should start fullscreen and on top: %v", args)
+	}
+}
+
 // stubMPV writes an executable shell script that prints the given stderr lines
 // and exits with the given code, standing in for the real mpv binary.
 func stubMPV(t *testing.T, exitCode int, stderrLines []string) string {
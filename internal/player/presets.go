@@ -0,0 +1,55 @@
+package player
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// builtinPresets are the playback presets available without any config. Each
+// is a list of extra mpv options applied for the whole playback session.
+// Presets in config (playback_presets) are added to these and override a
+// built-in of the same name.
+var builtinPresets = map[string][]string{
+	// night: even out loud effects and quiet dialogue, and fold surround
+	// down to stereo so nothing is lost on TV or laptop speakers.
+	"night": {"--af-append=lavfi=[dynaudnorm=f=150:g=15:p=0.7]", "--audio-channels=stereo"},
+	// normalize: EBU R128 loudness normalization for consistent volume
+	// between items.
+	"normalize": {"--af-append=lavfi=[loudnorm=I=-16:TP=-1.5:LRA=11]"},
+	// stereo: downmix surround to two channels.
+	"stereo": {"--audio-channels=stereo"},
+}
+
+// PresetNames returns every available preset name, built-in and custom,
+// sorted.
+func PresetNames(custom map[string][]string) []string {
+	seen := make(map[string]bool, len(builtinPresets)+len(custom))
+	var names []string
+	for name := range builtinPresets {
+		seen[name] = true
+		names = append(names, name)
+	}
+	for name := range custom {
+		if !seen[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ResolvePreset returns the mpv options for the named preset, looking in
+// custom first. An empty name means no preset and returns nil.
+func ResolvePreset(name string, custom map[string][]string) ([]string, error) {
+	if name == "" {
+		return nil, nil
+	}
+	if args, ok := custom[name]; ok {
+		return args, nil
+	}
+	if args, ok := builtinPresets[name]; ok {
+		return args, nil
+	}
+	return nil, fmt.Errorf("unknown playback preset %q (available: %s)", name, strings.Join(PresetNames(custom), ", "))
+}
@@ -0,0 +1,50 @@
+package player
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestResolvePreset(t *testing.T) {
+	custom := map[string][]string{
+		"quiet": {"--volume=50"},
+		"night": {"--af=lavfi=[acompressor]"},
+	}
+
+	if args, err := ResolvePreset("", custom); err != nil || args != nil {
+		t.Errorf("empty name: got %v, %v; want nil, nil", args, err)
+	}
+
+	args, err := ResolvePreset("stereo", custom)
+	if err != nil || !slices.Contains(args, "--audio-channels=stereo") {
+		t.Errorf("built-in stereo: got %v, %v", args, err)
+	}
+
+	args, err = ResolvePreset("night", custom)
+	if err != nil || !slices.Equal(args, custom["night"]) {
+		t.Errorf("config should override built-in night: got %v, %v", args, err)
+	}
+
+	_, err = ResolvePreset("loud", custom)
+	if err == nil || !strings.Contains(err.Error(), "quiet") {
+		t.Errorf("unknown preset error should list available presets, got %v", err)
+	}
+}
+
+func TestPresetNames(t *testing.T) {
+	got := PresetNames(map[string][]string{"quiet": nil, "night": nil})
+	want := []string{"night", "normalize", "quiet", "stereo"}
+	if !slices.Equal(got, want) {
+		t.Errorf("PresetNames() = %v, want %v", got, want)
+	}
+}
+
+func TestBuildMPVArgsExtraArgsBeforeURLs(t *testing.T) {
+	args := buildMPVArgs([]string{"http://example.com/a.mkv"}, "", 0, []string{"--audio-channels=stereo"})
+	extra := slices.Index(args, "--audio-channels=stereo")
+	url := slices.Index(args, "http://example.com/a.mkv")
+	if extra < 0 || url < 0 || extra > url {
+		t.Errorf("extra args must precede URLs so they apply to the whole playlist: %v", args)
+	}
+}
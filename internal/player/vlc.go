@@ -0,0 +1,121 @@
+package player
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+)
+
+// macVLCPath is where the VLC app bundle keeps its command-line binary; it is
+// not on PATH by default.
+const macVLCPath = "/Applications/VLC.app/Contents/MacOS/VLC"
+
+// resolveVLCPath returns the VLC binary to run: vlcPath if set, otherwise
+// "vlc" from PATH, falling back to the app bundle on macOS.
+func resolveVLCPath(vlcPath string) string {
+	if vlcPath != "" {
+		return vlcPath
+	}
+	if runtime.GOOS == "darwin" {
+		if _, err := exec.LookPath("vlc"); err != nil {
+			if _, err := os.Stat(macVLCPath); err == nil {
+				return macVLCPath
+			}
+		}
+	}
+	return "vlc"
+}
+
+// IsVLCAvailable checks if VLC is available on the system.
+func IsVLCAvailable(vlcPath string) bool {
+	_, err := exec.LookPath(resolveVLCPath(vlcPath))
+	return err == nil
+}
+
+// buildVLCArgs constructs the argument list for VLC. With a port set, VLC's
+// HTTP interface is started on localhost for progress tracking. The start
+// position is an item option on the first URL only, matching mpv's --start;
+// each URL's title is an item option on it.
+func buildVLCArgs(urls, titles []string, httpPort int, httpPassword string, startPos int) []string {
+	args := []string{
+		// Don't hand the playlist to an already-running VLC: we need our own
+		// process to wait on and our own HTTP interface to poll.
+		"--no-one-instance",
+		"--play-and-exit",
+	}
+
+	// VLC's volume option isn't consistent across versions, and profiles
+	// are mpv's, so only the window placement applies.
+	w := currentWindow()
+	if w.Fullscreen {
+		args = append(args, "--fullscreen")
+	}
+	if w.OnTop {
+		args = append(args, "--video-on-top")
+	}
+
+	if httpPort > 0 {
+		args = append(args,
+			"--extraintf=http",
+			"--http-host=127.0.0.1",
+			"--http-port="+strconv.Itoa(httpPort),
+			"--http-password="+httpPassword,
+		)
+	}
+
+	for i, u := range urls {
+		args = append(args, u)
+		if i == 0 && startPos > 0 {
+			args = append(args, fmt.Sprintf(":start-time=%d", startPos))
+		}
+		if i < len(titles) && titles[i] != "" {
+			args = append(args, ":meta-title="+titles[i])
+		}
+	}
+	return args
+}
+
+// PlayMultipleWithVLC launches VLC with the given options and reports how the
+// run ended. Only StartPos, Titles, HTTPPort and HTTPPassword apply; the mpv-specific
+// options are ignored. The outcome is non-nil whenever VLC actually ran.
+func PlayMultipleWithVLC(streamURLs []string, vlcPath string, opts PlaybackOptions) (*PlayOutcome, error) {
+	if len(streamURLs) == 0 {
+		return nil, fmt.Errorf("no stream URLs provided")
+	}
+
+	vlcPath = resolveVLCPath(vlcPath)
+	if _, err := exec.LookPath(vlcPath); err != nil {
+		return nil, fmt.Errorf("vlc not found in PATH. Please install VLC or specify vlc_path in config")
+	}
+
+	return runPlayer("vlc", vlcPath, buildVLCArgs(streamURLs, opts.Titles, opts.HTTPPort, opts.HTTPPassword, opts.StartPos))
+}
+
+// runPlayer runs a player other than mpv and reports how the run ended. These
+// players have no documented exit codes beyond zero for success, so any
+// non-zero exit is a failure.
+func runPlayer(name, path string, args []string) (*PlayOutcome, error) {
+	cmd := exec.Command(path, args...)
+	tail := &stderrTail{}
+	cmd.Stderr = tail
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", name, err)
+	}
+
+	waitErr := cmd.Wait()
+	outcome := &PlayOutcome{ErrorLine: errorLineFromStderr(tail.Lines())}
+	var ee *exec.ExitError
+	if errors.As(waitErr, &ee) {
+		outcome.ExitCode = ee.ExitCode()
+		if sig := exitSignal(ee); sig != "" {
+			outcome.Signal = sig
+			return outcome, &PlaybackError{Player: name, ExitCode: -1, Signal: sig, Detail: outcome.ErrorLine}
+		}
+		return outcome, &PlaybackError{Player: name, ExitCode: ee.ExitCode(), Detail: outcome.ErrorLine}
+	}
+	return outcome, waitErr
+}
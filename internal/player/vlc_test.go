@@ -0,0 +1,57 @@
+package player
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBuildVLCArgs(t *testing.T) {
+	urls := []string{"http://example.com/1.mkv", "http://example.com/2.mkv"}
+
+	args := buildVLCArgs(urls, []string{"Heat", "Ronin"}, 8123, "pw", 300)
+	for _, want := range []string{"--play-and-exit", "--extraintf=http", "--http-host=127.0.0.1", "--http-port=8123", "--http-password=pw"} {
+		if !slices.Contains(args, want) {
+			t.Errorf("missing %s in %v", want, args)
+		}
+	}
+	// The start time is an item option and must follow the first URL only.
+	first := slices.Index(args, urls[0])
+	if first < 0 || first+1 >= len(args) || args[first+1] != ":start-time=300" {
+		t.Errorf("start-time should follow the first URL: %v", args)
+	}
+	if first+2 >= len(args) || args[first+2] != ":meta-title=Heat" {
+		t.Errorf("the title should follow the first URL: %v", args)
+	}
+	if !slices.Equal(args[len(args)-2:], []string{urls[1], ":meta-title=Ronin"}) {
+		t.Errorf("second URL and its title should be last: %v", args)
+	}
+
+	args = buildVLCArgs(urls, nil, 0, "", 0)
+	for _, a := range args {
+		if strings.HasPrefix(a, "--http") || strings.HasPrefix(a, ":start-time") {
+			t.Errorf("unexpected %s without tracking or resume: %v", a, args)
+		}
+	}
+}
+
+func TestPlayWithVLCReportsFailure(t *testing.T) {
+	stub := stubMPV(t, 1, []string{"main libvlc error: interface \"http\" initialization failed"})
+	_, err := PlayMultipleWithVLC([]string{"https://example.com/video"}, stub, PlaybackOptions{})
+	var perr *PlaybackError
+	if !errors.As(err, &perr) {
+		t.Fatalf("want *PlaybackError, got %v", err)
+	}
+	if !strings.Contains(perr.Error(), "vlc exited 1") {
+		t.Errorf("Error(): got %q, want it to name vlc", perr.Error())
+	}
+}
+
+func TestPlayWithVLCCleanExit(t *testing.T) {
+	stub := stubMPV(t, 0, nil)
+	outcome, err := PlayMultipleWithVLC([]string{"https://example.com/video"}, stub, PlaybackOptions{})
+	if err != nil || outcome == nil {
+		t.Errorf("exit 0: got %+v, %v", outcome, err)
+	}
+}
@@ -0,0 +1,157 @@
+package plex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrVerificationCodeRequired is returned by AuthenticateWithCode when the
+// account has two-factor authentication and no code, or a wrong one, was
+// given.
+var ErrVerificationCodeRequired = errors.New("a two-factor verification code is required")
+
+// plexTVVerificationCode is plex.tv's error code for a sign-in that needs a
+// two-factor code.
+const plexTVVerificationCode = 1029
+
+// signIn exchanges a username and password, plus a two-factor code for
+// accounts that have one, for an account token.
+func signIn(ctx context.Context, username, password, verificationCode string) (_ string, err error) {
+	defer func() { err = plexError("SignIn", plexTVURL, err) }()
+
+	form := url.Values{"login": {username}, "password": {password}, "rememberMe": {"true"}}
+	if verificationCode != "" {
+		form.Set("verificationCode", verificationCode)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, plexTVURL+"/api/v2/users/signin", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request to plex.tv failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// plex.tv explains a refused sign-in as {"errors": [{"code": ...,
+		// "message": ...}]}.
+		var failure struct {
+			Errors []struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		_ = json.Unmarshal(body, &failure)
+		for _, e := range failure.Errors {
+			if e.Code == plexTVVerificationCode || strings.Contains(strings.ToLower(e.Message), "verification code") {
+				return "", ErrVerificationCodeRequired
+			}
+		}
+		if len(failure.Errors) > 0 && failure.Errors[0].Message != "" {
+			return "", newStatusError(resp.StatusCode, "%s (status %d)", failure.Errors[0].Message, resp.StatusCode)
+		}
+		return "", newStatusError(resp.StatusCode, "unexpected status code %d from plex.tv", resp.StatusCode)
+	}
+
+	var account struct {
+		AuthToken string `json:"authToken"`
+	}
+	if err := json.Unmarshal(body, &account); err != nil {
+		return "", fmt.Errorf("failed to parse plex.tv response: %w", err)
+	}
+	if account.AuthToken == "" {
+		return "", fmt.Errorf("no auth token received")
+	}
+	return account.AuthToken, nil
+}
+
+// ValidateToken checks with plex.tv that an account token still works. An
+// expired or revoked token gives an error matching apperrors.ErrAuthRequired;
+// other errors mean plex.tv couldn't say either way.
+func ValidateToken(ctx context.Context, token string) (err error) {
+	defer func() { err = plexError("ValidateToken", plexTVURL, err) }()
+
+	var user struct {
+		Username string `json:"username"`
+	}
+	return plexTVRequest(ctx, http.MethodGet, "/api/v2/user", token, &user)
+}
+
+// PIN is a short code that links goplexcli to a Plex account once the user
+// enters it at plex.tv/link, so signing in needs no password.
+type PIN struct {
+	ID   int    `json:"id"`
+	Code string `json:"code"`
+}
+
+// AuthURL opens the plex.tv sign-in page with the code already filled in.
+func (p *PIN) AuthURL() string {
+	d := currentDevice()
+	return "https://app.plex.tv/auth#?" + url.Values{
+		"clientID":                    {d.ClientIdentifier},
+		"code":                        {p.Code},
+		"context[device][product]":    {plexProduct},
+		"context[device][version]":    {d.Version},
+		"context[device][platform]":   {platformName()},
+		"context[device][device]":     {platformName()},
+		"context[device][deviceName]": {d.Name},
+	}.Encode()
+}
+
+// pinPollInterval is how often WaitForPIN asks plex.tv whether the code has
+// been entered. A variable so tests can shorten it.
+var pinPollInterval = 2 * time.Second
+
+// RequestPIN asks plex.tv for a new sign-in code. Codes expire after about
+// 15 minutes.
+func RequestPIN(ctx context.Context) (_ *PIN, err error) {
+	defer func() { err = plexError("RequestPIN", plexTVURL, err) }()
+
+	var pin PIN
+	if err := plexTVRequest(ctx, http.MethodPost, "/api/v2/pins", "", &pin); err != nil {
+		return nil, err
+	}
+	if pin.Code == "" {
+		return nil, fmt.Errorf("no code received from plex.tv")
+	}
+	return &pin, nil
+}
+
+// WaitForPIN polls plex.tv until the user has entered pin's code and returns
+// the account token it was linked to. It gives up when ctx is done or the
+// code expires.
+func WaitForPIN(ctx context.Context, pin *PIN) (_ string, err error) {
+	defer func() { err = plexError("WaitForPIN", plexTVURL, err) }()
+
+	for {
+		var resp struct {
+			AuthToken string `json:"authToken"`
+		}
+		if err := plexTVRequest(ctx, http.MethodGet, fmt.Sprintf("/api/v2/pins/%d", pin.ID), "", &resp); err != nil {
+			return "", err
+		}
+		if resp.AuthToken != "" {
+			return resp.AuthToken, nil
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(pinPollInterval):
+		}
+	}
+}
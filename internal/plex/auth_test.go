@@ -0,0 +1,96 @@
+package plex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apperrors "github.com/joshkerr/goplexcli/internal/errors"
+)
+
+func TestValidateTokenAndPIN(t *testing.T) {
+	var polls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/user":
+			if r.Header.Get("X-Plex-Token") != "good-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"username":"josh"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v2/pins":
+			_, _ = w.Write([]byte(`{"id":42,"code":"ABCD"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/pins/42":
+			// Linked on the third poll.
+			if polls++; polls < 3 {
+				_, _ = w.Write([]byte(`{"id":42,"code":"ABCD","authToken":null}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":42,"code":"ABCD","authToken":"new-token"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+	old, oldPoll := plexTVURL, pinPollInterval
+	plexTVURL, pinPollInterval = ts.URL, time.Millisecond
+	defer func() { plexTVURL, pinPollInterval = old, oldPoll }()
+
+	ctx := context.Background()
+	if err := ValidateToken(ctx, "good-token"); err != nil {
+		t.Errorf("ValidateToken(good) = %v", err)
+	}
+	if err := ValidateToken(ctx, "expired"); !errors.Is(err, apperrors.ErrAuthRequired) {
+		t.Errorf("ValidateToken(expired) = %v, want ErrAuthRequired", err)
+	}
+
+	pin, err := RequestPIN(ctx)
+	if err != nil || pin.Code != "ABCD" {
+		t.Fatalf("RequestPIN = %+v, %v", pin, err)
+	}
+	token, err := WaitForPIN(ctx, pin)
+	if err != nil || token != "new-token" || polls != 3 {
+		t.Errorf("WaitForPIN = %q, %v after %d polls", token, err, polls)
+	}
+}
+
+func TestSignInVerificationCode(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v2/users/signin" {
+			http.NotFound(w, r)
+			return
+		}
+		switch {
+		case r.FormValue("password") != "hunter2":
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"errors":[{"code":1001,"message":"User could not be authenticated","status":401}]}`))
+		case r.FormValue("verificationCode") != "123456":
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"errors":[{"code":1029,"message":"Please enter the verification code","status":401}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"username":"josh","authToken":"2fa-token"}`))
+		}
+	}))
+	defer ts.Close()
+	old := plexTVURL
+	plexTVURL = ts.URL
+	defer func() { plexTVURL = old }()
+
+	ctx := context.Background()
+	if _, err := signIn(ctx, "josh", "hunter2", ""); !errors.Is(err, ErrVerificationCodeRequired) {
+		t.Errorf("signIn without a code = %v, want ErrVerificationCodeRequired", err)
+	}
+	if _, err := signIn(ctx, "josh", "hunter2", "000000"); !errors.Is(err, ErrVerificationCodeRequired) {
+		t.Errorf("signIn with a wrong code = %v, want ErrVerificationCodeRequired", err)
+	}
+	if token, err := signIn(ctx, "josh", "hunter2", "123456"); err != nil || token != "2fa-token" {
+		t.Errorf("signIn with the code = %q, %v", token, err)
+	}
+	_, err := signIn(ctx, "josh", "wrong", "")
+	if err == nil || errors.Is(err, ErrVerificationCodeRequired) || !errors.Is(err, apperrors.ErrAuthRequired) {
+		t.Errorf("signIn with a wrong password = %v, want an auth error", err)
+	}
+}
@@ -0,0 +1,57 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+)
+
+// Chapter is one chapter of an item, as read by Plex from the media file.
+type Chapter struct {
+	Index   int // 1-based
+	Title   string
+	StartMs int
+	EndMs   int
+}
+
+type chaptersResponse struct {
+	MediaContainer struct {
+		Metadata []struct {
+			Chapter []struct {
+				Index           int    `json:"index"`
+				Tag             string `json:"tag"`
+				StartTimeOffset int    `json:"startTimeOffset"`
+				EndTimeOffset   int    `json:"endTimeOffset"`
+			} `json:"Chapter"`
+		} `json:"Metadata"`
+	} `json:"MediaContainer"`
+}
+
+// GetChapters returns the chapters of the item with the given rating key, in
+// order. Files without chapter marks have none.
+func (c *Client) GetChapters(ctx context.Context, ratingKey string) (_ []Chapter, err error) {
+	defer func() { err = c.wrapErr("GetChapters", err) }()
+
+	url := fmt.Sprintf("%s/library/metadata/%s?includeChapters=1&X-Plex-Token=%s", c.ServerURL(), ratingKey, c.token)
+
+	var resp chaptersResponse
+	if err := c.getJSON(ctx, url, "chapters", &resp); err != nil {
+		return nil, err
+	}
+
+	var chapters []Chapter
+	for _, m := range resp.MediaContainer.Metadata {
+		for i, ch := range m.Chapter {
+			index := ch.Index
+			if index == 0 {
+				index = i + 1
+			}
+			chapters = append(chapters, Chapter{
+				Index:   index,
+				Title:   ch.Tag,
+				StartMs: ch.StartTimeOffset,
+				EndMs:   ch.EndTimeOffset,
+			})
+		}
+	}
+	return chapters, nil
+}
@@ -0,0 +1,44 @@
+package plex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetChapters(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/library/metadata/5" || r.URL.Query().Get("includeChapters") != "1" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"MediaContainer": map[string]any{"Metadata": []map[string]any{{
+				"ratingKey": "5",
+				"Chapter": []map[string]any{
+					{"index": 1, "tag": "Opening", "startTimeOffset": 0, "endTimeOffset": 310000},
+					{"index": 2, "tag": "The Bank", "startTimeOffset": 310000, "endTimeOffset": 905000},
+					{"tag": "Untagged", "startTimeOffset": 905000, "endTimeOffset": 1200000},
+				},
+			}}},
+		})
+	}))
+	defer ts.Close()
+
+	chapters, err := testPlexClient(ts.URL).GetChapters(context.Background(), "5")
+	if err != nil {
+		t.Fatalf("GetChapters: %v", err)
+	}
+	if len(chapters) != 3 {
+		t.Fatalf("got %d chapters, want 3", len(chapters))
+	}
+	if c := chapters[1]; c.Index != 2 || c.Title != "The Bank" || c.StartMs != 310000 || c.EndMs != 905000 {
+		t.Errorf("unexpected chapter %+v", c)
+	}
+	if c := chapters[2]; c.Index != 3 {
+		t.Errorf("a chapter without an index should be numbered by position, got %d", c.Index)
+	}
+}
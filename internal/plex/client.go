@@ -22,12 +22,6 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
-// sectionHTTPClient is shared by the indexing path (section listing and page
-// fetches). The per-request timeout ensures a hung or unreachable Plex server
-// fails an index run with an error instead of blocking it forever; pages are
-// small (see sectionPageSize), so healthy responses finish well within it.
-var sectionHTTPClient = &http.Client{Timeout: 60 * time.Second}
-
 // errPlexServerError indicates the Plex server returned a 5xx response for a
 // page request. Large libraries can make the server fail on big container
 // windows, so callers detect this and retry with a smaller page size.
@@ -50,10 +44,15 @@ func SilenceAPIWarnings() {
 
 type Client struct {
 	sdk          *plexgo.PlexAPI
-	serverURL    string
 	serverName   string
 	token        string
 	pathMappings []PathMapping
+
+	// connMu guards serverURL and fallbacks, which failover changes while
+	// other goroutines build requests.
+	connMu    sync.Mutex
+	serverURL string
+	fallbacks []string
 }
 
 // PathMapping describes how to translate a Plex on-disk file path into an
@@ -97,10 +96,30 @@ type MediaItem struct {
 	ContentRating    string // e.g., "PG-13", "TV-MA"
 	Studio           string // Production studio
 	Director         string // Director name(s)
+	Writer           string // Writer name(s), comma-separated
 	Genre            string // Genre(s), comma-separated
 	Cast             string // Cast members, comma-separated
 	AddedAt          int64  // Unix timestamp when added to library
 	OriginallyAired  string // Original air date for episodes
+	Size             int64  // File size in bytes (0 if unknown)
+	VideoResolution  string // Plex's videoResolution: "sd", "720", "1080", "4k", ...
+	VideoCodec       string // e.g. "h264", "hevc"
+	AudioCodec       string // e.g. "aac", "eac3"
+	Bitrate          int    // Overall bitrate in kbps (0 if unknown)
+	HDR              string // "HDR10", "Dolby Vision" or "HLG"; empty for SDR or unknown
+	GUID             string // Plex's GUID, e.g. "plex://movie/5d776..."; the same on every server for matched items
+	IMDbID           string // e.g. "tt0113277"; empty if the agent found none
+	TMDBID           string // e.g. "949"
+	TVDBID           string // e.g. "81189"
+
+	// Library is the title of the section the item was indexed from, e.g.
+	// "Documentaries". Empty in caches indexed before it was recorded.
+	Library string
+
+	// Filled in by TMDB enrichment (see internal/tmdb) when Plex lacks them.
+	Tagline   string
+	PosterURL string   // Absolute poster URL, set only when Plex has no poster
+	Similar   []string // Recommended titles, as "Title (Year)"
 }
 
 // New creates a new Plex client
@@ -113,9 +132,10 @@ func NewWithName(serverURL, token, serverName string) (*Client, error) {
 	sdk := plexgo.New(
 		plexgo.WithServerURL(serverURL),
 		plexgo.WithSecurity(token),
-		plexgo.WithClientIdentifier("goplexcli"),
-		plexgo.WithProduct("GoplexCLI"),
-		plexgo.WithVersion("1.0"),
+		plexgo.WithClientIdentifier(currentDevice().ClientIdentifier),
+		plexgo.WithProduct(plexProduct),
+		plexgo.WithVersion(currentDevice().Version),
+		plexgo.WithClient(httpClient),
 	)
 
 	// If no server name provided, use URL as fallback
@@ -138,8 +158,10 @@ func (c *Client) Test() error {
 
 // TestContext validates the connection to the Plex server, honoring the
 // caller's context for cancellation and deadlines.
-func (c *Client) TestContext(ctx context.Context) error {
-	_, err := c.sdk.General.GetIdentity(ctx)
+func (c *Client) TestContext(ctx context.Context) (err error) {
+	defer func() { err = c.wrapErr("Test", err) }()
+
+	_, err = c.sdk.General.GetIdentity(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to connect to plex server: %w", err)
 	}
@@ -165,9 +187,11 @@ type sectionsResponse struct {
 }
 
 // GetLibraries returns all library sections using direct HTTP to avoid unmarshaling issues
-func (c *Client) GetLibraries(ctx context.Context) ([]Library, error) {
+func (c *Client) GetLibraries(ctx context.Context) (_ []Library, err error) {
+	defer func() { err = c.wrapErr("GetLibraries", err) }()
+
 	// Use direct HTTP request to avoid library's unmarshaling issues with hidden field
-	url := fmt.Sprintf("%s/library/sections?X-Plex-Token=%s", c.serverURL, c.token)
+	url := fmt.Sprintf("%s/library/sections?X-Plex-Token=%s", c.ServerURL(), c.token)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -175,11 +199,8 @@ func (c *Client) GetLibraries(ctx context.Context) ([]Library, error) {
 	}
 
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("X-Plex-Client-Identifier", "goplexcli")
-	req.Header.Set("X-Plex-Product", "GoplexCLI")
-	req.Header.Set("X-Plex-Version", "1.0")
 
-	resp, err := sectionHTTPClient.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sections: %w", err)
 	}
@@ -188,12 +209,12 @@ func (c *Client) GetLibraries(ctx context.Context) ([]Library, error) {
 	// Check HTTP status code
 	if resp.StatusCode != http.StatusOK {
 		if resp.StatusCode == http.StatusUnauthorized {
-			return nil, fmt.Errorf("authentication failed: invalid or expired token (status %d)", resp.StatusCode)
+			return nil, newStatusError(resp.StatusCode, "authentication failed: invalid or expired token (status %d)", resp.StatusCode)
 		}
 		if resp.StatusCode == http.StatusNotFound {
-			return nil, fmt.Errorf("library sections endpoint not found - Plex API may have changed (status %d)", resp.StatusCode)
+			return nil, newStatusError(resp.StatusCode, "library sections endpoint not found - Plex API may have changed (status %d)", resp.StatusCode)
 		}
-		return nil, fmt.Errorf("unexpected status code %d from Plex server", resp.StatusCode)
+		return nil, newStatusError(resp.StatusCode, "unexpected status code %d from Plex server", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -241,7 +262,9 @@ type ProgressCallback func(libraryName string, itemCount int, totalItems int, to
 type ServerProgressCallback func(serverName, libraryName string, itemCount int, totalItems int, totalLibraries int, currentLibrary int, serverNum int, totalServers int)
 
 // GetAllMedia returns all media items from all libraries.
-func (c *Client) GetAllMedia(ctx context.Context, progressCallback ProgressCallback) ([]MediaItem, error) {
+func (c *Client) GetAllMedia(ctx context.Context, progressCallback ProgressCallback) (_ []MediaItem, err error) {
+	defer func() { err = c.wrapErr("GetAllMedia", err) }()
+
 	return c.getMedia(ctx, nil, progressCallback)
 }
 
@@ -249,7 +272,9 @@ func (c *Client) GetAllMedia(ctx context.Context, progressCallback ProgressCallb
 // for incremental cache updates. sinceFor receives the library type
 // ("movie" or "show") and returns the newest addedAt already known for that
 // type (return 0 to fetch the whole library).
-func (c *Client) GetMediaSince(ctx context.Context, sinceFor func(libType string) int64, progressCallback ProgressCallback) ([]MediaItem, error) {
+func (c *Client) GetMediaSince(ctx context.Context, sinceFor func(libType string) int64, progressCallback ProgressCallback) (_ []MediaItem, err error) {
+	defer func() { err = c.wrapErr("GetMediaSince", err) }()
+
 	return c.getMedia(ctx, sinceFor, progressCallback)
 }
 
@@ -262,7 +287,7 @@ func (c *Client) getMedia(ctx context.Context, sinceFor func(libType string) int
 
 	var tasks []sectionFetchTask
 	for _, lib := range libraries {
-		if lib.Type != "movie" && lib.Type != "show" {
+		if !indexed(c.serverName, lib) {
 			continue
 		}
 		var since int64
@@ -333,7 +358,7 @@ func getMediaFromServers(ctx context.Context, serverConfigs []struct{ Name, URL,
 		serverTaskStart := len(tasks)
 		libNum := 0
 		for _, lib := range libraries {
-			if lib.Type != "movie" && lib.Type != "show" {
+			if !indexed(serverConfig.Name, lib) {
 				continue
 			}
 			libNum++
@@ -410,6 +435,9 @@ func fetchSections(ctx context.Context, tasks []sectionFetchTask, onProgress fun
 				}
 				return fmt.Errorf("failed to get media from section %s: %w", task.lib.Title, err)
 			}
+			for j := range media {
+				media[j].Library = task.lib.Title
+			}
 			results[i] = media
 			return nil
 		})
@@ -459,41 +487,95 @@ const pageNetRetries = 2
 
 // sectionMetadata mirrors a single item in a library section's Metadata array.
 type sectionMetadata struct {
-	Key                   string       `json:"key"`
-	RatingKey             string       `json:"ratingKey"`
-	Title                 string       `json:"title"`
-	Year                  *int         `json:"year"`
-	Summary               *string      `json:"summary"`
-	Rating                *float32     `json:"rating"`
-	Duration              *int         `json:"duration"`
-	Thumb                 *string      `json:"thumb"`
-	GrandparentThumb      *string      `json:"grandparentThumb"`
-	GrandparentTitle      *string      `json:"grandparentTitle"`
-	ParentTitle           *string      `json:"parentTitle"`
-	Index                 *int         `json:"index"`
-	ParentIndex           *int         `json:"parentIndex"`
-	ViewOffset            *int         `json:"viewOffset"`
-	ViewCount             *int         `json:"viewCount"`
-	LastViewedAt          *int64       `json:"lastViewedAt"`
-	ContentRating         *string      `json:"contentRating"`
-	Studio                *string      `json:"studio"`
-	AddedAt               *int64       `json:"addedAt"`
-	OriginallyAvailableAt *string      `json:"originallyAvailableAt"`
-	Director              []taggedItem `json:"Director"`
-	Genre                 []taggedItem `json:"Genre"`
-	Role                  []taggedItem `json:"Role"`
-	Media                 []struct {
-		Part []struct {
-			File *string `json:"file"`
-		} `json:"Part"`
-	} `json:"Media"`
+	Key                   string         `json:"key"`
+	RatingKey             string         `json:"ratingKey"`
+	GUID                  *string        `json:"guid"`
+	ExternalGUIDs         []guidTag      `json:"Guid"`
+	Title                 string         `json:"title"`
+	Year                  *int           `json:"year"`
+	Summary               *string        `json:"summary"`
+	Rating                *float32       `json:"rating"`
+	Duration              *int           `json:"duration"`
+	Thumb                 *string        `json:"thumb"`
+	GrandparentThumb      *string        `json:"grandparentThumb"`
+	GrandparentTitle      *string        `json:"grandparentTitle"`
+	ParentTitle           *string        `json:"parentTitle"`
+	Index                 *int           `json:"index"`
+	ParentIndex           *int           `json:"parentIndex"`
+	ViewOffset            *int           `json:"viewOffset"`
+	ViewCount             *int           `json:"viewCount"`
+	LastViewedAt          *int64         `json:"lastViewedAt"`
+	ContentRating         *string        `json:"contentRating"`
+	Studio                *string        `json:"studio"`
+	AddedAt               *int64         `json:"addedAt"`
+	OriginallyAvailableAt *string        `json:"originallyAvailableAt"`
+	Director              []taggedItem   `json:"Director"`
+	Writer                []taggedItem   `json:"Writer"`
+	Genre                 []taggedItem   `json:"Genre"`
+	Role                  []taggedItem   `json:"Role"`
+	Media                 []sectionMedia `json:"Media"`
+}
+
+// sectionMedia is one version of an item: its format and file parts.
+type sectionMedia struct {
+	VideoResolution *string `json:"videoResolution"`
+	VideoCodec      *string `json:"videoCodec"`
+	AudioCodec      *string `json:"audioCodec"`
+	Bitrate         *int    `json:"bitrate"`
+	Part            []struct {
+		File   *string       `json:"file"`
+		Size   *int64        `json:"size"`
+		Stream []videoStream `json:"Stream"`
+	} `json:"Part"`
+}
+
+// applyTo copies the format of the media into item.
+// guidTag is one of an item's external identifiers, listed with
+// includeGuids=1.
+type guidTag struct {
+	ID string `json:"id"`
+}
+
+// applyExternalGUIDs records the IMDb, TMDB and TVDB IDs among guids on item.
+func applyExternalGUIDs(item *MediaItem, guids []guidTag) {
+	for _, g := range guids {
+		scheme, id, ok := strings.Cut(g.ID, "://")
+		if !ok {
+			continue
+		}
+		switch scheme {
+		case "imdb":
+			item.IMDbID = id
+		case "tmdb":
+			item.TMDBID = id
+		case "tvdb":
+			item.TVDBID = id
+		}
+	}
+}
+
+func (m sectionMedia) applyTo(item *MediaItem) {
+	item.VideoResolution = valueOrEmpty(m.VideoResolution)
+	item.VideoCodec = valueOrEmpty(m.VideoCodec)
+	item.AudioCodec = valueOrEmpty(m.AudioCodec)
+	item.Bitrate = valueOrZeroInt(m.Bitrate)
+	for _, part := range m.Part {
+		for _, s := range part.Stream {
+			if hdr := s.hdr(); hdr != "" {
+				item.HDR = hdr
+				return
+			}
+		}
+	}
 }
 
 // GetMediaFromSection returns media items from a specific library section.
 // It pages through the section rather than requesting everything at once,
 // because large libraries make the Plex server return HTTP 500 for a single
 // unpaginated /all request.
-func (c *Client) GetMediaFromSection(ctx context.Context, sectionKey, sectionType string) ([]MediaItem, error) {
+func (c *Client) GetMediaFromSection(ctx context.Context, sectionKey, sectionType string) (_ []MediaItem, err error) {
+	defer func() { err = c.wrapErr("GetMediaFromSection", err) }()
+
 	return c.getMediaFromSection(ctx, sectionKey, sectionType, 0, nil)
 }
 
@@ -514,10 +596,10 @@ func (c *Client) getMediaFromSection(ctx context.Context, sectionKey, sectionTyp
 	var baseURL string
 	if sectionType == "show" {
 		// For TV shows, specifically request type=4 (episodes)
-		baseURL = fmt.Sprintf("%s/library/sections/%s/all?type=4&X-Plex-Token=%s", c.serverURL, sectionKey, c.token)
+		baseURL = fmt.Sprintf("%s/library/sections/%s/all?type=4&includeGuids=1&X-Plex-Token=%s", c.ServerURL(), sectionKey, c.token)
 	} else {
 		// For movies, use the default all endpoint
-		baseURL = fmt.Sprintf("%s/library/sections/%s/all?X-Plex-Token=%s", c.serverURL, sectionKey, c.token)
+		baseURL = fmt.Sprintf("%s/library/sections/%s/all?includeGuids=1&X-Plex-Token=%s", c.ServerURL(), sectionKey, c.token)
 	}
 
 	// For incremental fetches, ask the server for newest items first so we can
@@ -556,6 +638,7 @@ func (c *Client) getMediaFromSection(ctx context.Context, sectionKey, sectionTyp
 
 			item := MediaItem{
 				Key:             metadata.Key,
+				GUID:            valueOrEmpty(metadata.GUID),
 				Title:           metadata.Title,
 				Year:            valueOrZeroInt(metadata.Year),
 				Type:            "movie",
@@ -564,23 +647,27 @@ func (c *Client) getMediaFromSection(ctx context.Context, sectionKey, sectionTyp
 				Duration:        valueOrZeroInt(metadata.Duration),
 				Thumb:           valueOrEmpty(metadata.Thumb),
 				ServerName:      c.serverName,
-				ServerURL:       c.serverURL,
+				ServerURL:       c.ServerURL(),
 				ViewOffset:      valueOrZeroInt(metadata.ViewOffset),
 				ViewCount:       valueOrZeroInt(metadata.ViewCount),
 				LastViewedAt:    valueOrZeroInt64(metadata.LastViewedAt),
 				ContentRating:   valueOrEmpty(metadata.ContentRating),
 				Studio:          valueOrEmpty(metadata.Studio),
 				Director:        strings.Join(extractTags(metadata.Director, 0), ", "),
+				Writer:          strings.Join(extractTags(metadata.Writer, 0), ", "),
 				Genre:           strings.Join(extractTags(metadata.Genre, 0), ", "),
 				Cast:            strings.Join(extractTags(metadata.Role, castLimit), ", "),
 				AddedAt:         valueOrZeroInt64(metadata.AddedAt),
 				OriginallyAired: valueOrEmpty(metadata.OriginallyAvailableAt),
 			}
+			applyExternalGUIDs(&item, metadata.ExternalGUIDs)
 
 			// Get file path
 			if len(metadata.Media) > 0 && len(metadata.Media[0].Part) > 0 {
 				item.FilePath = valueOrEmpty(metadata.Media[0].Part[0].File)
 				item.RclonePath = c.convertToRclonePath(item.FilePath)
+				item.Size = valueOrZeroInt64(metadata.Media[0].Part[0].Size)
+				metadata.Media[0].applyTo(&item)
 			} else {
 				apiLogger.Printf("warning: movie %q has no media parts", metadata.Title)
 			}
@@ -601,6 +688,7 @@ func (c *Client) getMediaFromSection(ctx context.Context, sectionKey, sectionTyp
 
 			item := MediaItem{
 				Key:              metadata.Key,
+				GUID:             valueOrEmpty(metadata.GUID),
 				Title:            metadata.Title,
 				Year:             valueOrZeroInt(metadata.Year),
 				Type:             "episode",
@@ -614,23 +702,27 @@ func (c *Client) getMediaFromSection(ctx context.Context, sectionKey, sectionTyp
 				Index:            int64(valueOrZeroInt(metadata.Index)),
 				ParentIndex:      int64(valueOrZeroInt(metadata.ParentIndex)),
 				ServerName:       c.serverName,
-				ServerURL:        c.serverURL,
+				ServerURL:        c.ServerURL(),
 				ViewOffset:       valueOrZeroInt(metadata.ViewOffset),
 				ViewCount:        valueOrZeroInt(metadata.ViewCount),
 				LastViewedAt:     valueOrZeroInt64(metadata.LastViewedAt),
 				ContentRating:    valueOrEmpty(metadata.ContentRating),
 				Studio:           valueOrEmpty(metadata.Studio),
 				Director:         strings.Join(extractTags(metadata.Director, 0), ", "),
+				Writer:           strings.Join(extractTags(metadata.Writer, 0), ", "),
 				Genre:            strings.Join(extractTags(metadata.Genre, 0), ", "),
 				Cast:             strings.Join(extractTags(metadata.Role, castLimit), ", "),
 				AddedAt:          valueOrZeroInt64(metadata.AddedAt),
 				OriginallyAired:  valueOrEmpty(metadata.OriginallyAvailableAt),
 			}
+			applyExternalGUIDs(&item, metadata.ExternalGUIDs)
 
 			// Get file path
 			if len(metadata.Media) > 0 && len(metadata.Media[0].Part) > 0 {
 				item.FilePath = valueOrEmpty(metadata.Media[0].Part[0].File)
 				item.RclonePath = c.convertToRclonePath(item.FilePath)
+				item.Size = valueOrZeroInt64(metadata.Media[0].Part[0].Size)
+				metadata.Media[0].applyTo(&item)
 			} else {
 				apiLogger.Printf("warning: episode %q has no media parts", metadata.Title)
 			}
@@ -758,7 +850,7 @@ func (c *Client) pageMetadata(ctx context.Context, baseURL, logKey string, since
 func (c *Client) fetchEpisodesPerShow(ctx context.Context, sectionKey string, since int64, onPage func(fetched, total int)) ([]sectionMetadata, error) {
 	// List the shows in this section. The default /all (no type) returns the
 	// show directories, a far smaller set than every episode.
-	showsURL := fmt.Sprintf("%s/library/sections/%s/all?X-Plex-Token=%s", c.serverURL, sectionKey, c.token)
+	showsURL := fmt.Sprintf("%s/library/sections/%s/all?X-Plex-Token=%s", c.ServerURL(), sectionKey, c.token)
 	shows, err := c.pageMetadata(ctx, showsURL, "section "+sectionKey+" shows", 0, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list shows: %w", err)
@@ -772,7 +864,7 @@ func (c *Client) fetchEpisodesPerShow(ctx context.Context, sectionKey string, si
 			continue
 		}
 
-		leavesURL := fmt.Sprintf("%s/library/metadata/%s/allLeaves?X-Plex-Token=%s", c.serverURL, show.RatingKey, c.token)
+		leavesURL := fmt.Sprintf("%s/library/metadata/%s/allLeaves?X-Plex-Token=%s", c.ServerURL(), show.RatingKey, c.token)
 
 		// Report progress cumulatively across shows so long traversals don't
 		// look frozen. base is the count before this show; pageMetadata reports
@@ -825,7 +917,7 @@ func (c *Client) fetchEpisodesPerShow(ctx context.Context, sectionKey string, si
 // running episode count before this show, used only to keep progress reporting
 // cumulative. A season that can't be fetched is logged and skipped.
 func (c *Client) fetchEpisodesPerSeason(ctx context.Context, showRatingKey string, base int, onPage func(fetched, total int)) ([]sectionMetadata, error) {
-	seasonsURL := fmt.Sprintf("%s/library/metadata/%s/children?X-Plex-Token=%s", c.serverURL, showRatingKey, c.token)
+	seasonsURL := fmt.Sprintf("%s/library/metadata/%s/children?X-Plex-Token=%s", c.ServerURL(), showRatingKey, c.token)
 	seasons, err := c.pageMetadata(ctx, seasonsURL, "show "+showRatingKey+" seasons", 0, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list seasons: %w", err)
@@ -837,7 +929,7 @@ func (c *Client) fetchEpisodesPerSeason(ctx context.Context, showRatingKey strin
 			continue
 		}
 
-		episodesURL := fmt.Sprintf("%s/library/metadata/%s/children?X-Plex-Token=%s", c.serverURL, season.RatingKey, c.token)
+		episodesURL := fmt.Sprintf("%s/library/metadata/%s/children?includeGuids=1&X-Plex-Token=%s", c.ServerURL(), season.RatingKey, c.token)
 
 		// Report cumulatively: base (episodes before this show) plus what this
 		// show has accumulated across earlier seasons plus the current page.
@@ -867,17 +959,16 @@ func (c *Client) fetchEpisodesPerSeason(ctx context.Context, showRatingKey strin
 func (c *Client) fetchSectionPage(ctx context.Context, baseURL, sectionKey string, start, size int) ([]sectionMetadata, int, error) {
 	url := fmt.Sprintf("%s&X-Plex-Container-Start=%d&X-Plex-Container-Size=%d", baseURL, start, size)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	// The pager retries failed pages itself, shrinking the window on server
+	// errors, so the transport must not retry them first.
+	req, err := http.NewRequestWithContext(withoutRetries(ctx), "GET", url, nil)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("X-Plex-Client-Identifier", "goplexcli")
-	req.Header.Set("X-Plex-Product", "GoplexCLI")
-	req.Header.Set("X-Plex-Version", "1.0")
 
-	resp, err := sectionHTTPClient.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get library items: %w", err)
 	}
@@ -886,59 +977,154 @@ func (c *Client) fetchSectionPage(ctx context.Context, baseURL, sectionKey strin
 	// Check HTTP status code
 	if resp.StatusCode != http.StatusOK {
 		if resp.StatusCode == http.StatusUnauthorized {
-			return nil, 0, fmt.Errorf("authentication failed: invalid or expired token (status %d)", resp.StatusCode)
+			return nil, 0, newStatusError(resp.StatusCode, "authentication failed: invalid or expired token (status %d)", resp.StatusCode)
 		}
 		if resp.StatusCode == http.StatusNotFound {
 			apiLogger.Printf("warning: section %s not found - it may have been removed", sectionKey)
-			return nil, 0, fmt.Errorf("library section %s not found (status %d)", sectionKey, resp.StatusCode)
+			return nil, 0, newStatusError(resp.StatusCode, "library section %s not found (status %d)", sectionKey, resp.StatusCode)
 		}
 		if resp.StatusCode >= 500 {
 			// Wrap with errPlexServerError so the pager can retry this page
 			// with a smaller container window.
-			return nil, 0, fmt.Errorf("unexpected status code %d from Plex server: %w", resp.StatusCode, errPlexServerError)
+			return nil, 0, newStatusError(resp.StatusCode, "unexpected status code %d from Plex server: %w", resp.StatusCode, errPlexServerError)
 		}
-		return nil, 0, fmt.Errorf("unexpected status code %d from Plex server", resp.StatusCode)
+		return nil, 0, newStatusError(resp.StatusCode, "unexpected status code %d from Plex server", resp.StatusCode)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	items, total, err := decodeSectionPage(resp.Body)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to read response: %w", err)
+		apiLogger.Printf("warning: failed to parse media response for section %s, API format may have changed: %v", sectionKey, err)
+		return nil, 0, fmt.Errorf("failed to parse media response: %w", err)
 	}
+	return items, total, nil
+}
 
-	var mediaResp struct {
-		MediaContainer struct {
-			TotalSize int               `json:"totalSize"`
-			Size      int               `json:"size"`
-			Metadata  []sectionMetadata `json:"Metadata"`
-		} `json:"MediaContainer"`
+// decodeSectionPage reads a section listing straight off r. Metadata entries
+// are decoded one at a time as they stream in and everything else is
+// skipped, so neither the raw body nor a second copy of the page is held in
+// memory, however large the section.
+func decodeSectionPage(r io.Reader) (items []sectionMetadata, totalSize int, err error) {
+	dec := json.NewDecoder(r)
+	err = decodeObject(dec, func(key string) error {
+		if key != "MediaContainer" {
+			return skipValue(dec)
+		}
+		return decodeObject(dec, func(key string) error {
+			switch key {
+			case "totalSize":
+				return dec.Decode(&totalSize)
+			case "Metadata":
+				return decodeArray(dec, func() error {
+					var m sectionMetadata
+					if err := dec.Decode(&m); err != nil {
+						return err
+					}
+					items = append(items, m)
+					return nil
+				})
+			default:
+				return skipValue(dec)
+			}
+		})
+	})
+	return items, totalSize, err
+}
+
+// decodeObject walks a JSON object, calling field for each key with dec
+// positioned at its value, which field must consume. A null is an empty
+// object.
+func decodeObject(dec *json.Decoder, field func(key string) error) error {
+	tok, err := dec.Token()
+	if err != nil || tok == nil {
+		return err
+	}
+	if tok != json.Delim('{') {
+		return fmt.Errorf("expected object, got %v", tok)
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if err := field(tok.(string)); err != nil {
+			return err
+		}
 	}
+	_, err = dec.Token() // closing '}'
+	return err
+}
 
-	if err := json.Unmarshal(body, &mediaResp); err != nil {
-		apiLogger.Printf("warning: failed to parse media response for section %s, API format may have changed: %v", sectionKey, err)
-		return nil, 0, fmt.Errorf("failed to parse media response: %w", err)
+// decodeArray walks a JSON array, calling elem with dec positioned at each
+// element, which elem must consume. A null is an empty array.
+func decodeArray(dec *json.Decoder, elem func() error) error {
+	tok, err := dec.Token()
+	if err != nil || tok == nil {
+		return err
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("expected array, got %v", tok)
+	}
+	for dec.More() {
+		if err := elem(); err != nil {
+			return err
+		}
 	}
+	_, err = dec.Token() // closing ']'
+	return err
+}
 
-	return mediaResp.MediaContainer.Metadata, mediaResp.MediaContainer.TotalSize, nil
+// skipValue consumes the next value, however deeply nested, without
+// decoding it.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
 }
 
 // GetStreamURL returns the direct stream URL for a media item
 // This gets the actual file URL that can be streamed by MPV
 func (c *Client) GetStreamURL(mediaKey string) (string, error) {
+	return c.GetStreamURLContext(context.Background(), mediaKey)
+}
+
+// GetStreamURLContext is GetStreamURL honoring the caller's context for
+// cancellation and deadlines.
+func (c *Client) GetStreamURLContext(ctx context.Context, mediaKey string) (_ string, err error) {
+	defer func() { err = c.wrapErr("GetStreamURL", err) }()
+
+	for {
+		streamURL, err := c.streamURL(ctx, mediaKey)
+		if err == nil || !isUnreachable(err) || !c.failover(ctx) {
+			return streamURL, err
+		}
+	}
+}
+
+func (c *Client) streamURL(ctx context.Context, mediaKey string) (string, error) {
 	// First, get the metadata for this item to find the media part key
-	url := fmt.Sprintf("%s%s?X-Plex-Token=%s", c.serverURL, mediaKey, c.token)
+	url := fmt.Sprintf("%s%s?X-Plex-Token=%s", c.ServerURL(), mediaKey, c.token)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("X-Plex-Client-Identifier", "goplexcli")
-	req.Header.Set("X-Plex-Product", "GoplexCLI")
-	req.Header.Set("X-Plex-Version", "1.0")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("failed to get metadata: %w", err)
 	}
@@ -947,12 +1133,12 @@ func (c *Client) GetStreamURL(mediaKey string) (string, error) {
 	// Check HTTP status code
 	if resp.StatusCode != http.StatusOK {
 		if resp.StatusCode == http.StatusUnauthorized {
-			return "", fmt.Errorf("authentication failed: invalid or expired token (status %d)", resp.StatusCode)
+			return "", newStatusError(resp.StatusCode, "authentication failed: invalid or expired token (status %d)", resp.StatusCode)
 		}
 		if resp.StatusCode == http.StatusNotFound {
-			return "", fmt.Errorf("media item not found: %s (status %d)", mediaKey, resp.StatusCode)
+			return "", newStatusError(resp.StatusCode, "media item not found: %s (status %d)", mediaKey, resp.StatusCode)
 		}
-		return "", fmt.Errorf("unexpected status code %d from Plex server", resp.StatusCode)
+		return "", newStatusError(resp.StatusCode, "unexpected status code %d from Plex server", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -988,7 +1174,7 @@ func (c *Client) GetStreamURL(mediaKey string) (string, error) {
 			// Use download=1 to get direct file (no transcoding)
 			// This is faster and works better with most players
 			streamURL := fmt.Sprintf("%s%s?download=1&X-Plex-Token=%s",
-				c.serverURL, *partKey, c.token)
+				c.ServerURL(), *partKey, c.token)
 			return streamURL, nil
 		}
 	}
@@ -996,22 +1182,13 @@ func (c *Client) GetStreamURL(mediaKey string) (string, error) {
 	// Fallback to simple download URL if part key not found
 	apiLogger.Printf("warning: could not find media part key for %s, using fallback URL", mediaKey)
 	streamURL := fmt.Sprintf("%s%s?download=1&X-Plex-Token=%s",
-		c.serverURL, mediaKey, c.token)
+		c.ServerURL(), mediaKey, c.token)
 	return streamURL, nil
 }
 
-// Plex client headers - consistent across all API calls
-const (
-	plexClientIdentifier = "goplexcli"
-	plexProduct          = "GoplexCLI"
-	plexVersion          = "1.0"
-)
-
-// timelineClient is used for timeline updates with a reasonable timeout
-// to prevent blocking if the Plex server is slow or unresponsive.
-var timelineClient = &http.Client{
-	Timeout: 5 * time.Second,
-}
+// timelineTimeout bounds timeline updates, retries included, so a slow or
+// unresponsive Plex server can't block playback tracking.
+const timelineTimeout = 5 * time.Second
 
 // UpdateTimeline reports playback progress to the Plex server.
 // This updates the resume position and shows "Now Playing" on the Plex dashboard.
@@ -1019,6 +1196,28 @@ var timelineClient = &http.Client{
 // timeMs is the current position in milliseconds.
 // durationMs is the total duration in milliseconds.
 func (c *Client) UpdateTimeline(ratingKey string, state string, timeMs int, durationMs int) error {
+	return c.UpdateTimelineContext(context.Background(), ratingKey, state, timeMs, durationMs)
+}
+
+// UpdateTimelineContext is UpdateTimeline honoring the caller's context for
+// cancellation and deadlines.
+func (c *Client) UpdateTimelineContext(ctx context.Context, ratingKey string, state string, timeMs int, durationMs int) (err error) {
+	defer func() { err = c.wrapErr("UpdateTimeline", err) }()
+	return c.updateTimeline(ctx, ratingKey, state, timeMs, durationMs, "")
+}
+
+// UpdateQueuedTimeline is UpdateTimeline for an item played from a play
+// queue made by CreatePlayQueue, which ties the report to the queue so Plex
+// apps show the queue's up-next items alongside it.
+func (c *Client) UpdateQueuedTimeline(ratingKey string, item QueueItem, state string, timeMs int, durationMs int) (err error) {
+	defer func() { err = c.wrapErr("UpdateTimeline", err) }()
+	return c.updateTimeline(context.Background(), ratingKey, state, timeMs, durationMs,
+		fmt.Sprintf("&containerKey=/playQueues/%d&playQueueItemID=%d", item.QueueID, item.ItemID))
+}
+
+// updateTimeline sends a timeline update, with extra appended to the query.
+func (c *Client) updateTimeline(ctx context.Context, ratingKey string, state string, timeMs int, durationMs int, extra string) error {
+
 	// Validate inputs
 	if ratingKey == "" {
 		return fmt.Errorf("ratingKey cannot be empty")
@@ -1033,28 +1232,26 @@ func (c *Client) UpdateTimeline(ratingKey string, state string, timeMs int, dura
 		durationMs = 0
 	}
 
-	url := fmt.Sprintf("%s/:/timeline?ratingKey=%s&key=/library/metadata/%s&state=%s&time=%d&duration=%d&X-Plex-Token=%s",
-		c.serverURL, ratingKey, ratingKey, state, timeMs, durationMs, c.token)
+	url := fmt.Sprintf("%s/:/timeline?ratingKey=%s&key=/library/metadata/%s&state=%s&time=%d&duration=%d%s&X-Plex-Token=%s",
+		c.ServerURL(), ratingKey, ratingKey, state, timeMs, durationMs, extra, c.token)
 
-	req, err := http.NewRequest("GET", url, nil)
+	ctx, cancel := context.WithTimeout(ctx, timelineTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create timeline request: %w", err)
 	}
 
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("X-Plex-Client-Identifier", plexClientIdentifier)
-	req.Header.Set("X-Plex-Product", plexProduct)
-	req.Header.Set("X-Plex-Version", plexVersion)
 
-	// Use timelineClient with timeout to prevent blocking on slow servers
-	resp, err := timelineClient.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to update timeline: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("timeline update failed with status %d", resp.StatusCode)
+		return newStatusError(resp.StatusCode, "timeline update failed with status %d", resp.StatusCode)
 	}
 
 	return nil
@@ -1114,6 +1311,55 @@ func legacyRclonePath(filePath string) string {
 	return fmt.Sprintf("%s:%s", remoteName, remotePath)
 }
 
+// RatingKey returns the numeric rating key from the item's metadata key,
+// e.g. "/library/metadata/12345" -> "12345".
+func (m *MediaItem) RatingKey() string {
+	return m.Key[strings.LastIndex(m.Key, "/")+1:]
+}
+
+// TopCast returns the n top-billed cast members, comma-separated, with "…"
+// appended when there are more.
+func (m *MediaItem) TopCast(n int) string {
+	names := strings.Split(m.Cast, ", ")
+	if m.Cast == "" || len(names) <= n {
+		return m.Cast
+	}
+	return strings.Join(names[:n], ", ") + ", …"
+}
+
+// ExternalIDs returns the item's IMDb, TMDB and TVDB IDs in GUID form, e.g.
+// "imdb://tt0113277", for matching it with other services.
+func (m *MediaItem) ExternalIDs() []string {
+	var ids []string
+	for _, id := range []struct{ scheme, id string }{{"imdb", m.IMDbID}, {"tmdb", m.TMDBID}, {"tvdb", m.TVDBID}} {
+		if id.id != "" {
+			ids = append(ids, id.scheme+"://"+id.id)
+		}
+	}
+	return ids
+}
+
+// IMDbURL returns the item's IMDb page, or "" if its IMDb ID isn't known.
+func (m *MediaItem) IMDbURL() string {
+	if m.IMDbID == "" {
+		return ""
+	}
+	return "https://www.imdb.com/title/" + m.IMDbID + "/"
+}
+
+// PlayerTitle returns the title a player shows for the item, e.g.
+// "Severance S01E02 - Half Loop" or "Heat (1995)", without the watch-state
+// markers FormatMediaTitle adds.
+func (m *MediaItem) PlayerTitle() string {
+	switch {
+	case m.Type == "episode":
+		return fmt.Sprintf("%s S%02dE%02d - %s", m.ParentTitle, m.ParentIndex, m.Index, m.Title)
+	case m.Year > 0:
+		return fmt.Sprintf("%s (%d)", m.Title, m.Year)
+	}
+	return m.Title
+}
+
 // FormatMediaTitle returns a formatted title for display
 func (m *MediaItem) FormatMediaTitle() string {
 	var title string
@@ -1151,13 +1397,31 @@ func (m *MediaItem) FormatMediaTitle() string {
 	return title
 }
 
+// FormatSize renders a byte count in binary units, e.g. "4.2 GB". Zero or
+// negative sizes (unknown) render as "".
+func FormatSize(bytes int64) string {
+	if bytes <= 0 {
+		return ""
+	}
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
 // Server represents a Plex server
 type Server struct {
 	Name        string
 	URL         string
 	Local       bool
 	Owned       bool
-	Connections []string
+	Connections []Connection
 	// AccessToken is the per-server token issued by plex.tv. For shared
 	// (non-owner) users this is the only token the server accepts; the
 	// account token used to talk to plex.tv gets a 401.
@@ -1167,48 +1431,49 @@ type Server struct {
 // Authenticate authenticates with Plex using username and password
 // Returns auth token and list of available servers
 func Authenticate(username, password string) (string, []Server, error) {
-	// Create SDK client for authentication
-	sdk := plexgo.New(
-		plexgo.WithClientIdentifier("goplexcli"),
-		plexgo.WithProduct("GoplexCLI"),
-		plexgo.WithVersion("1.0"),
-	)
-
-	ctx := context.Background()
+	return AuthenticateWithCode(username, password, "")
+}
 
-	// Sign in
-	res, err := sdk.Authentication.PostUsersSignInData(ctx, operations.PostUsersSignInDataRequest{
-		RequestBody: &operations.PostUsersSignInDataRequestBody{
-			Login:    username,
-			Password: password,
-		},
-	})
+// AuthenticateWithCode is Authenticate for accounts with two-factor
+// authentication, passing the authenticator app's current code. Without a
+// code, or with a wrong one, such an account gets an error matching
+// ErrVerificationCodeRequired.
+func AuthenticateWithCode(username, password, verificationCode string) (string, []Server, error) {
+	token, err := signIn(context.Background(), username, password, verificationCode)
 	if err != nil {
 		return "", nil, fmt.Errorf("authentication failed: %w", err)
 	}
 
-	if res.UserPlexAccount == nil {
-		return "", nil, fmt.Errorf("no auth token received")
+	servers, err := ServersForToken(token)
+	if err != nil {
+		return "", nil, err
 	}
+	return token, servers, nil
+}
 
-	token := res.UserPlexAccount.AuthToken
+// ServersForToken lists the servers a plex.tv token can reach, with the
+// per-server access tokens issued to that account. After switching Home
+// users (see SwitchHomeUser) they differ from the admin's.
+func ServersForToken(token string) ([]Server, error) {
+	ctx := context.Background()
 
 	// Get available servers/resources using the token
 	// Create a new SDK instance with the auth token
 	authSDK := plexgo.New(
 		plexgo.WithSecurity(token),
-		plexgo.WithClientIdentifier("goplexcli"),
-		plexgo.WithProduct("GoplexCLI"),
-		plexgo.WithVersion("1.0"),
+		plexgo.WithClientIdentifier(currentDevice().ClientIdentifier),
+		plexgo.WithProduct(plexProduct),
+		plexgo.WithVersion(currentDevice().Version),
+		plexgo.WithClient(httpClient),
 	)
 
 	resourcesRes, err := authSDK.Plex.GetServerResources(ctx, operations.GetServerResourcesRequest{})
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to get servers: %w", err)
+		return nil, fmt.Errorf("failed to get servers: %w", err)
 	}
 
 	if len(resourcesRes.PlexDevices) == 0 {
-		return "", nil, fmt.Errorf("no resources found")
+		return nil, fmt.Errorf("no resources found")
 	}
 
 	// Build list of available servers
@@ -1221,10 +1486,10 @@ func Authenticate(username, password string) (string, []Server, error) {
 				AccessToken: device.AccessToken,
 			}
 
-			// Collect all connection URLs
-			var connections []string
+			// Collect all connections
+			var connections []Connection
 			for _, conn := range device.Connections {
-				connections = append(connections, conn.URI)
+				connections = append(connections, Connection{URI: conn.URI, Local: conn.Local, Relay: conn.Relay})
 				// Set the preferred URL (local first)
 				if server.URL == "" {
 					server.URL = conn.URI
@@ -1244,10 +1509,10 @@ func Authenticate(username, password string) (string, []Server, error) {
 	}
 
 	if len(servers) == 0 {
-		return "", nil, fmt.Errorf("no servers found")
+		return nil, fmt.Errorf("no servers found")
 	}
 
-	return token, servers, nil
+	return servers, nil
 }
 
 // castLimit caps how many cast members (top-billed first) are stored per item.
@@ -1255,7 +1520,7 @@ func Authenticate(username, password string) (string, []Server, error) {
 // keep a generous slice of the billing rather than just the headline few.
 const castLimit = 20
 
-// taggedItem represents an item with a Tag field (used for Director, Genre, Role)
+// taggedItem represents an item with a Tag field (used for Director, Writer, Genre, Role)
 type taggedItem struct {
 	Tag string `json:"tag"`
 }
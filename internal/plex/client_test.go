@@ -65,3 +65,77 @@ func TestConvertToRclonePath(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		bytes int64
+		want  string
+	}{
+		{0, ""},
+		{512, "512 B"},
+		{1536, "1.5 KB"},
+		{4509715660, "4.2 GB"},
+	}
+	for _, tt := range tests {
+		if got := FormatSize(tt.bytes); got != tt.want {
+			t.Errorf("FormatSize(%d) = %q, want %q", tt.bytes, got, tt.want)
+		}
+	}
+}
+
+func TestPlayerTitle(t *testing.T) {
+	ep := &MediaItem{Type: "episode", Title: "Half Loop", ParentTitle: "Severance", ParentIndex: 1, Index: 2, ViewCount: 1, Duration: 1}
+	if got := ep.PlayerTitle(); got != "Severance S01E02 - Half Loop" {
+		t.Errorf("episode PlayerTitle() = %q", got)
+	}
+	movie := &MediaItem{Type: "movie", Title: "Heat", Year: 1995, ViewOffset: 10, Duration: 100}
+	if got := movie.PlayerTitle(); got != "Heat (1995)" {
+		t.Errorf("movie PlayerTitle() = %q", got)
+	}
+}
+
+func TestRatingKey(t *testing.T) {
+	tests := map[string]string{
+		"/library/metadata/12345": "12345",
+		"12345":                   "12345",
+		"":                        "",
+	}
+	for key, want := range tests {
+		m := &MediaItem{Key: key}
+		if got := m.RatingKey(); got != want {
+			t.Errorf("RatingKey(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestTopCast(t *testing.T) {
+	tests := map[string]string{
+		"":                 "",
+		"A, B":             "A, B",
+		"A, B, C":          "A, B, C",
+		"A, B, C, D, E, F": "A, B, C, …",
+	}
+	for cast, want := range tests {
+		m := &MediaItem{Cast: cast}
+		if got := m.TopCast(3); got != want {
+			t.Errorf("TopCast(%q) = %q, want %q", cast, got, want)
+		}
+	}
+}
+
+func TestExternalGUIDs(t *testing.T) {
+	var m MediaItem
+	applyExternalGUIDs(&m, []guidTag{{"imdb://tt0113277"}, {"tmdb://949"}, {"tvdb://81189"}, {"plex://movie/5d776"}, {"bogus"}})
+	if m.IMDbID != "tt0113277" || m.TMDBID != "949" || m.TVDBID != "81189" {
+		t.Fatalf("IDs = %q %q %q", m.IMDbID, m.TMDBID, m.TVDBID)
+	}
+	if got := m.ExternalIDs(); len(got) != 3 || got[0] != "imdb://tt0113277" || got[2] != "tvdb://81189" {
+		t.Errorf("ExternalIDs = %v", got)
+	}
+	if got := m.IMDbURL(); got != "https://www.imdb.com/title/tt0113277/" {
+		t.Errorf("IMDbURL = %q", got)
+	}
+	if (&MediaItem{}).IMDbURL() != "" {
+		t.Error("IMDbURL without an ID should be empty")
+	}
+}
@@ -0,0 +1,172 @@
+package plex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Connection is one address plex.tv advertises for a server.
+type Connection struct {
+	URI   string
+	Local bool // on the server's own network
+	Relay bool // through plex.tv's bandwidth-limited relay
+}
+
+// Probe is the outcome of checking whether a connection answers.
+type Probe struct {
+	Connection
+	Latency time.Duration
+	Err     error // nil when the server answered
+}
+
+// probeTimeout bounds each connection check. Reachable servers answer
+// /identity in milliseconds; anything slower is as good as down for picking
+// a connection.
+var probeTimeout = 3 * time.Second
+
+// ProbeConnections checks all conns at once with HEAD /identity and returns
+// the results in the same order.
+func ProbeConnections(ctx context.Context, conns []Connection, token string) []Probe {
+	probes := make([]Probe, len(conns))
+	var wg sync.WaitGroup
+	for i, conn := range conns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			probes[i] = Probe{Connection: conn}
+			probes[i].Latency, probes[i].Err = probe(ctx, conn.URI, token)
+		}()
+	}
+	wg.Wait()
+	return probes
+}
+
+func probe(ctx context.Context, uri, token string) (time.Duration, error) {
+	// An unreachable address should fail fast, not be retried.
+	ctx, cancel := context.WithTimeout(withoutRetries(ctx), probeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, uri+"/identity", nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Plex-Token", token)
+
+	start := time.Now()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	// Any answer but a server error means Plex itself is there; a proxy in
+	// front of a stopped server answers 502.
+	if resp.StatusCode >= 500 {
+		return 0, newStatusError(resp.StatusCode, "unexpected status code %d from Plex server", resp.StatusCode)
+	}
+	return time.Since(start), nil
+}
+
+// RankProbes sorts probes best first: reachable before unreachable, local
+// before remote, direct before relayed, then by latency.
+func RankProbes(probes []Probe) {
+	sort.SliceStable(probes, func(i, j int) bool {
+		a, b := probes[i], probes[j]
+		if (a.Err == nil) != (b.Err == nil) {
+			return a.Err == nil
+		}
+		if a.Local != b.Local {
+			return a.Local
+		}
+		if a.Relay != b.Relay {
+			return !a.Relay
+		}
+		return a.Latency < b.Latency
+	})
+}
+
+// IsLocalURL reports whether serverURL points into the local network: a
+// loopback or private IP, a .local name, or a plex.direct name encoding a
+// private IP, such as "192-168-1-10.abc123.plex.direct".
+func IsLocalURL(serverURL string) bool {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".local") {
+		return true
+	}
+	if strings.HasSuffix(host, ".plex.direct") {
+		host = strings.ReplaceAll(strings.SplitN(host, ".", 2)[0], "-", ".")
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast())
+}
+
+// SetFallbacks gives the client the server's other addresses, relay
+// included, to try in order when its own URL can't be reached.
+func (c *Client) SetFallbacks(urls []string) {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+	c.fallbacks = nil
+	for _, u := range urls {
+		if u != c.serverURL {
+			c.fallbacks = append(c.fallbacks, u)
+		}
+	}
+}
+
+// ServerURL returns the address the client is using, which differs from the
+// one it was created with after a failover. Requests build their URLs from
+// it, so it is safe to call while another goroutine fails over.
+func (c *Client) ServerURL() string {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+	return c.serverURL
+}
+
+// failover switches the client to the first fallback that answers a probe
+// and reports whether it found one. Fallbacks that fail are dropped.
+// The lock isn't held while probing, so concurrent failovers each probe a
+// different fallback.
+func (c *Client) failover(ctx context.Context) bool {
+	for {
+		c.connMu.Lock()
+		if len(c.fallbacks) == 0 {
+			c.connMu.Unlock()
+			return false
+		}
+		uri := c.fallbacks[0]
+		c.fallbacks = c.fallbacks[1:]
+		c.connMu.Unlock()
+
+		if _, err := probe(ctx, uri, c.token); err != nil {
+			apiLogger.Printf("fallback %s unreachable: %v", uri, err)
+			continue
+		}
+		c.connMu.Lock()
+		apiLogger.Printf("%s unreachable, using %s", c.serverURL, uri)
+		c.serverURL = uri
+		c.connMu.Unlock()
+		return true
+	}
+}
+
+// isUnreachable reports whether err means the server couldn't be reached at
+// all, as opposed to answering with an error.
+func isUnreachable(err error) bool {
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.code >= 500
+	}
+	var ne net.Error
+	return errors.As(err, &ne)
+}
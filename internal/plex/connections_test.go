@@ -0,0 +1,138 @@
+package plex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestProbeConnections(t *testing.T) {
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead || r.URL.Path != "/identity" {
+			t.Errorf("unexpected probe %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer up.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer broken.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	probes := ProbeConnections(context.Background(), []Connection{
+		{URI: down.URL, Local: true},
+		{URI: broken.URL},
+		{URI: up.URL},
+	}, "tok")
+	if len(probes) != 3 || probes[0].URI != down.URL || probes[2].URI != up.URL {
+		t.Fatalf("probes out of order: %+v", probes)
+	}
+	if probes[0].Err == nil || probes[1].Err == nil || probes[2].Err != nil {
+		t.Errorf("want only the last connection reachable: %+v", probes)
+	}
+}
+
+func TestRankProbes(t *testing.T) {
+	down := errors.New("down")
+	probes := []Probe{
+		{Connection: Connection{URI: "unreachable-local", Local: true}, Err: down},
+		{Connection: Connection{URI: "relay", Relay: true}, Latency: 5 * time.Millisecond},
+		{Connection: Connection{URI: "remote-slow"}, Latency: 90 * time.Millisecond},
+		{Connection: Connection{URI: "remote-fast"}, Latency: 40 * time.Millisecond},
+		{Connection: Connection{URI: "local", Local: true}, Latency: 60 * time.Millisecond},
+	}
+	RankProbes(probes)
+	want := []string{"local", "remote-fast", "remote-slow", "relay", "unreachable-local"}
+	for i, p := range probes {
+		if p.URI != want[i] {
+			t.Errorf("rank %d = %s, want %s", i, p.URI, want[i])
+		}
+	}
+}
+
+func TestStreamURLFailover(t *testing.T) {
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			return
+		}
+		_, _ = w.Write([]byte(`{"MediaContainer":{"Metadata":[{"Media":[{"Part":[{"key":"/library/parts/1/file.mkv"}]}]}]}}`))
+	}))
+	defer up.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	c := &Client{serverURL: down.URL, serverName: "test", token: "tok"}
+	c.SetFallbacks([]string{down.URL, "http://127.0.0.1:1", up.URL})
+	ctx := withoutRetries(context.Background())
+	got, err := c.GetStreamURLContext(ctx, "/library/metadata/1")
+	if err != nil {
+		t.Fatalf("GetStreamURLContext: %v", err)
+	}
+	if want := up.URL + "/library/parts/1/file.mkv?download=1&X-Plex-Token=tok"; got != want {
+		t.Errorf("stream URL = %s, want %s", got, want)
+	}
+	if c.ServerURL() != up.URL {
+		t.Errorf("ServerURL = %s, want %s after failover", c.ServerURL(), up.URL)
+	}
+
+	// With no fallbacks left the network error comes back.
+	c = &Client{serverURL: down.URL, serverName: "test", token: "tok"}
+	if _, err := c.GetStreamURLContext(ctx, "/library/metadata/1"); err == nil {
+		t.Error("want an error with no reachable connection")
+	}
+}
+
+// TestConcurrentFailover runs requests in parallel while the client fails
+// over; run with -race to check serverURL and fallbacks are guarded.
+func TestConcurrentFailover(t *testing.T) {
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			return
+		}
+		_, _ = w.Write([]byte(`{"MediaContainer":{"Metadata":[{"Media":[{"Part":[{"key":"/library/parts/1/file.mkv"}]}]}]}}`))
+	}))
+	defer up.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	c := &Client{serverURL: down.URL, serverName: "test", token: "tok"}
+	c.SetFallbacks([]string{"http://127.0.0.1:1", up.URL, up.URL})
+	ctx := withoutRetries(context.Background())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// A goroutine can run out of fallbacks after another one took
+			// the working address, so only the end state is checked.
+			_, _ = c.GetStreamURLContext(ctx, "/library/metadata/1")
+		}()
+	}
+	wg.Wait()
+	if c.ServerURL() != up.URL {
+		t.Errorf("ServerURL = %s, want %s after failover", c.ServerURL(), up.URL)
+	}
+}
+
+func TestIsLocalURL(t *testing.T) {
+	tests := map[string]bool{
+		"http://192.168.1.10:32400":                    true,
+		"http://localhost:32400":                       true,
+		"http://nas.local:32400":                       true,
+		"https://10-0-0-5.abc123.plex.direct:32400":    true,
+		"https://203-0-113-7.abc123.plex.direct:32400": false,
+		"https://plex.example.com":                     false,
+		"http://203.0.113.7:32400":                     false,
+	}
+	for u, want := range tests {
+		if got := IsLocalURL(u); got != want {
+			t.Errorf("IsLocalURL(%q) = %v, want %v", u, got, want)
+		}
+	}
+}
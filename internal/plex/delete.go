@@ -0,0 +1,43 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// DeleteItem removes the item with the given rating key from its library.
+// Plex deletes the item's media files along with it, and only allows this for
+// the server owner with "Allow media deletion" enabled in the server
+// settings.
+func (c *Client) DeleteItem(ctx context.Context, ratingKey string) (err error) {
+	defer func() { err = c.wrapErr("DeleteItem", err) }()
+
+	if ratingKey == "" {
+		return fmt.Errorf("ratingKey cannot be empty")
+	}
+	url := fmt.Sprintf("%s/library/metadata/%s?X-Plex-Token=%s", c.ServerURL(), ratingKey, c.token)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete item: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return newStatusError(resp.StatusCode, "the server refused the deletion (status %d): only the server owner can delete, with \"Allow media deletion\" enabled in the server settings", resp.StatusCode)
+	case http.StatusNotFound:
+		return newStatusError(resp.StatusCode, "item not found on the server (status %d); it may already be deleted", resp.StatusCode)
+	default:
+		return newStatusError(resp.StatusCode, "unexpected status code %d from Plex server", resp.StatusCode)
+	}
+}
@@ -0,0 +1,46 @@
+package plex
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDeleteItem(t *testing.T) {
+	var deleted []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("method = %s, want DELETE", r.Method)
+		}
+		switch r.URL.Path {
+		case "/library/metadata/7":
+			deleted = append(deleted, "7")
+		case "/library/metadata/8":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	c := testPlexClient(ts.URL)
+	if err := c.DeleteItem(context.Background(), "7"); err != nil {
+		t.Fatalf("DeleteItem: %v", err)
+	}
+	if len(deleted) != 1 {
+		t.Errorf("server saw %d deletes, want 1", len(deleted))
+	}
+
+	err := c.DeleteItem(context.Background(), "8")
+	if err == nil || !strings.Contains(err.Error(), "Allow media deletion") {
+		t.Errorf("forbidden delete error = %v, want a hint about server settings", err)
+	}
+	if err := c.DeleteItem(context.Background(), "9"); err == nil {
+		t.Error("missing item should return an error")
+	}
+	if err := c.DeleteItem(context.Background(), ""); err == nil {
+		t.Error("empty rating key should be rejected")
+	}
+}
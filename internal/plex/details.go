@@ -0,0 +1,237 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Stream is one video, audio or subtitle track of an item's file.
+type Stream struct {
+	Type     string `json:"type"` // "video", "audio" or "subtitle"
+	Codec    string `json:"codec,omitempty"`
+	Language string `json:"language,omitempty"`
+	Title    string `json:"title"` // Plex's display title, e.g. "English (AAC Stereo)"
+	Default  bool   `json:"default,omitempty"`
+	Forced   bool   `json:"forced,omitempty"`
+	// Key is the download path of a sidecar subtitle file, e.g.
+	// "/library/streams/123"; "" for tracks inside the media file.
+	Key string `json:"key,omitempty"`
+}
+
+// Details is what Plex knows about an item's file beyond the library
+// listing: its format, tracks and full credits.
+type Details struct {
+	Container       string   `json:"container,omitempty"`
+	VideoResolution string   `json:"video_resolution,omitempty"` // e.g. "1080", "4k", "sd"
+	VideoCodec      string   `json:"video_codec,omitempty"`
+	AudioCodec      string   `json:"audio_codec,omitempty"`
+	Width           int      `json:"width,omitempty"`
+	Height          int      `json:"height,omitempty"`
+	Bitrate         int      `json:"bitrate_kbps,omitempty"`
+	HDR             string   `json:"hdr,omitempty"` // "HDR10", "Dolby Vision" or "HLG"
+	Streams         []Stream `json:"streams,omitempty"`
+	Directors       []string `json:"directors,omitempty"`
+	Writers         []string `json:"writers,omitempty"`
+	Cast            []string `json:"cast,omitempty"`
+}
+
+type detailsResponse struct {
+	MediaContainer struct {
+		Metadata []struct {
+			Director []taggedItem `json:"Director"`
+			Writer   []taggedItem `json:"Writer"`
+			Role     []taggedItem `json:"Role"`
+			Media    []struct {
+				Container       string `json:"container"`
+				VideoResolution string `json:"videoResolution"`
+				VideoCodec      string `json:"videoCodec"`
+				AudioCodec      string `json:"audioCodec"`
+				Width           int    `json:"width"`
+				Height          int    `json:"height"`
+				Bitrate         int    `json:"bitrate"`
+				Part            []struct {
+					Stream []struct {
+						videoStream
+						Codec        string `json:"codec"`
+						Language     string `json:"language"`
+						DisplayTitle string `json:"displayTitle"`
+						Default      bool   `json:"default"`
+						Forced       bool   `json:"forced"`
+						Key          string `json:"key"`
+					} `json:"Stream"`
+				} `json:"Part"`
+			} `json:"Media"`
+		} `json:"Metadata"`
+	} `json:"MediaContainer"`
+}
+
+// videoStream holds the stream attributes that tell HDR formats apart.
+type videoStream struct {
+	StreamType  int    `json:"streamType"`
+	ColorTrc    string `json:"colorTrc"`
+	DOVIPresent bool   `json:"DOVIPresent"`
+}
+
+// hdr returns the HDR format of a video stream, or "" for SDR and other
+// stream types.
+func (s videoStream) hdr() string {
+	switch {
+	case s.StreamType != 1:
+		return ""
+	case s.DOVIPresent:
+		return "Dolby Vision"
+	case s.ColorTrc == "smpte2084":
+		return "HDR10"
+	case s.ColorTrc == "arib-std-b67":
+		return "HLG"
+	}
+	return ""
+}
+
+// streamTypes maps Plex's streamType numbers to Stream.Type.
+var streamTypes = map[int]string{1: "video", 2: "audio", 3: "subtitle"}
+
+// GetDetails returns the format, tracks and credits of the item with the
+// given rating key. Only the first version (Media) of an item is described.
+func (c *Client) GetDetails(ctx context.Context, ratingKey string) (_ *Details, err error) {
+	defer func() { err = c.wrapErr("GetDetails", err) }()
+
+	url := fmt.Sprintf("%s/library/metadata/%s?X-Plex-Token=%s", c.ServerURL(), ratingKey, c.token)
+
+	var resp detailsResponse
+	if err := c.getJSON(ctx, url, "item details", &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.MediaContainer.Metadata) == 0 {
+		return nil, fmt.Errorf("item %s not found", ratingKey)
+	}
+
+	m := resp.MediaContainer.Metadata[0]
+	d := &Details{
+		Directors: extractTags(m.Director, 0),
+		Writers:   extractTags(m.Writer, 0),
+		Cast:      extractTags(m.Role, 0),
+	}
+	if len(m.Media) == 0 {
+		return d, nil
+	}
+	media := m.Media[0]
+	d.Container = media.Container
+	d.VideoResolution = media.VideoResolution
+	d.VideoCodec = media.VideoCodec
+	d.AudioCodec = media.AudioCodec
+	d.Width, d.Height = media.Width, media.Height
+	d.Bitrate = media.Bitrate
+	for _, part := range media.Part {
+		for _, s := range part.Stream {
+			typ, ok := streamTypes[s.StreamType]
+			if !ok {
+				continue
+			}
+			if hdr := s.hdr(); hdr != "" && d.HDR == "" {
+				d.HDR = hdr
+			}
+			d.Streams = append(d.Streams, Stream{
+				Type:     typ,
+				Codec:    s.Codec,
+				Language: s.Language,
+				Title:    s.DisplayTitle,
+				Default:  s.Default,
+				Forced:   s.Forced,
+				Key:      s.Key,
+			})
+		}
+	}
+	return d, nil
+}
+
+// SubtitleURLs returns download URLs for the sidecar subtitle files (.srt
+// and the like beside the media file) of the item with the given rating
+// key, for players to load alongside the stream.
+func (c *Client) SubtitleURLs(ctx context.Context, ratingKey string) ([]string, error) {
+	d, err := c.GetDetails(ctx, ratingKey)
+	if err != nil {
+		return nil, err
+	}
+	var urls []string
+	for _, s := range d.StreamsOf("subtitle") {
+		if s.Key != "" {
+			urls = append(urls, fmt.Sprintf("%s%s?X-Plex-Token=%s", c.ServerURL(), s.Key, c.token))
+		}
+	}
+	return urls, nil
+}
+
+// StreamsOf returns the streams of the given type, in file order.
+func (d *Details) StreamsOf(typ string) []Stream {
+	var out []Stream
+	for _, s := range d.Streams {
+		if s.Type == typ {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// FormatResolution turns Plex's videoResolution ("1080", "4k", "sd") into a
+// label like "1080p", "4K" or "SD".
+func FormatResolution(res string) string {
+	switch res {
+	case "":
+		return ""
+	case "4k", "8k", "sd":
+		return strings.ToUpper(res)
+	}
+	if _, err := strconv.Atoi(res); err == nil {
+		return res + "p"
+	}
+	return res
+}
+
+// FormatBitrate renders a bitrate in kbps, e.g. "8.4 Mbps".
+func FormatBitrate(kbps int) string {
+	if kbps <= 0 {
+		return ""
+	}
+	if kbps < 1000 {
+		return fmt.Sprintf("%d kbps", kbps)
+	}
+	return fmt.Sprintf("%.1f Mbps", float64(kbps)/1000)
+}
+
+// FormatTech summarizes an item's format for listings, e.g.
+// "4K HEVC HDR10 · EAC3 · 42.0 Mbps". It is empty for items indexed before
+// the format was recorded.
+func (m *MediaItem) FormatTech() string {
+	var parts []string
+	video := strings.TrimSpace(strings.Join([]string{FormatResolution(m.VideoResolution), strings.ToUpper(m.VideoCodec), m.HDR}, " "))
+	for _, p := range []string{video, strings.ToUpper(m.AudioCodec), FormatBitrate(m.Bitrate)} {
+		if p != "" {
+			parts = append(parts, strings.Join(strings.Fields(p), " "))
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+// NormalizeResolution maps a user's resolution ("4k", "2160p", "UHD",
+// "1080p", "sd") to Plex's videoResolution value, or "" if it isn't one.
+func NormalizeResolution(res string) string {
+	res = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(res)), "p")
+	switch res {
+	case "4k", "2160", "uhd":
+		return "4k"
+	case "8k", "4320":
+		return "8k"
+	case "sd", "480", "576", "720", "1080":
+		return res
+	}
+	return ""
+}
+
+// MatchesResolution reports whether the item is in the given resolution,
+// as returned by NormalizeResolution.
+func (m *MediaItem) MatchesResolution(res string) bool {
+	return strings.EqualFold(m.VideoResolution, res)
+}
@@ -0,0 +1,119 @@
+package plex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGetDetails(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/library/metadata/7" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"MediaContainer": map[string]any{"Metadata": []map[string]any{{
+				"ratingKey": "7",
+				"Director":  []map[string]any{{"tag": "Michael Mann"}},
+				"Writer":    []map[string]any{{"tag": "Michael Mann"}},
+				"Role":      []map[string]any{{"tag": "Al Pacino"}, {"tag": "Robert De Niro"}},
+				"Media": []map[string]any{{
+					"container": "mkv", "videoResolution": "4k", "videoCodec": "hevc", "audioCodec": "eac3",
+					"width": 3840, "height": 2160, "bitrate": 42000,
+					"Part": []map[string]any{{"Stream": []map[string]any{
+						{"streamType": 1, "codec": "hevc", "displayTitle": "4K (HEVC Main 10 HDR)"},
+						{"streamType": 2, "codec": "eac3", "language": "English", "displayTitle": "English (EAC3 5.1)", "default": true},
+						{"streamType": 3, "codec": "srt", "language": "English", "displayTitle": "English (SRT Forced)", "forced": true},
+						{"streamType": 3, "codec": "subrip", "language": "German", "displayTitle": "Deutsch (SRT External)", "key": "/library/streams/42"},
+						{"streamType": 4, "displayTitle": "lyrics"},
+					}}},
+				}},
+			}}},
+		})
+	}))
+	defer ts.Close()
+
+	d, err := testPlexClient(ts.URL).GetDetails(context.Background(), "7")
+	if err != nil {
+		t.Fatalf("GetDetails: %v", err)
+	}
+	if d.VideoResolution != "4k" || d.VideoCodec != "hevc" || d.Bitrate != 42000 || d.Width != 3840 || d.Container != "mkv" {
+		t.Errorf("unexpected media %+v", d)
+	}
+	if len(d.Cast) != 2 || d.Directors[0] != "Michael Mann" || d.Writers[0] != "Michael Mann" {
+		t.Errorf("unexpected credits %v %v %v", d.Directors, d.Writers, d.Cast)
+	}
+	if len(d.Streams) != 4 {
+		t.Fatalf("got %d streams, want 4 (unknown types skipped)", len(d.Streams))
+	}
+	if audio := d.StreamsOf("audio"); len(audio) != 1 || !audio[0].Default || audio[0].Title != "English (EAC3 5.1)" {
+		t.Errorf("unexpected audio %+v", audio)
+	}
+	if subs := d.StreamsOf("subtitle"); len(subs) != 2 || !subs[0].Forced || subs[1].Key != "/library/streams/42" {
+		t.Errorf("unexpected subtitles %+v", subs)
+	}
+
+	urls, err := testPlexClient(ts.URL).SubtitleURLs(context.Background(), "7")
+	if err != nil || len(urls) != 1 || !strings.HasPrefix(urls[0], ts.URL+"/library/streams/42?X-Plex-Token=") {
+		t.Errorf("SubtitleURLs = %v, %v; want only the sidecar file", urls, err)
+	}
+
+	if _, err := testPlexClient(ts.URL).GetDetails(context.Background(), "8"); err == nil {
+		t.Error("a missing item should fail")
+	}
+}
+
+func TestFormatResolution(t *testing.T) {
+	for in, want := range map[string]string{"": "", "4k": "4K", "sd": "SD", "1080": "1080p", "720": "720p", "odd": "odd"} {
+		if got := FormatResolution(in); got != want {
+			t.Errorf("FormatResolution(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := FormatBitrate(8420); got != "8.4 Mbps" {
+		t.Errorf("FormatBitrate(8420) = %q", got)
+	}
+}
+
+func TestFormatTech(t *testing.T) {
+	m := &MediaItem{VideoResolution: "4k", VideoCodec: "hevc", HDR: "Dolby Vision", AudioCodec: "truehd", Bitrate: 58000}
+	if got, want := m.FormatTech(), "4K HEVC Dolby Vision · TRUEHD · 58.0 Mbps"; got != want {
+		t.Errorf("FormatTech() = %q, want %q", got, want)
+	}
+	m = &MediaItem{VideoCodec: "h264"}
+	if got := m.FormatTech(); got != "H264" {
+		t.Errorf("FormatTech() = %q, want H264", got)
+	}
+	if got := (&MediaItem{}).FormatTech(); got != "" {
+		t.Errorf("FormatTech() of an unindexed item = %q", got)
+	}
+}
+
+func TestNormalizeResolution(t *testing.T) {
+	for in, want := range map[string]string{"4k": "4k", "4K": "4k", "2160p": "4k", "UHD": "4k", "1080p": "1080", "720": "720", "SD": "sd", "hd": ""} {
+		if got := NormalizeResolution(in); got != want {
+			t.Errorf("NormalizeResolution(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if !(&MediaItem{VideoResolution: "4k"}).MatchesResolution("4k") {
+		t.Error("a 4k item should match 4k")
+	}
+}
+
+func TestSectionMediaApplyTo(t *testing.T) {
+	var m sectionMedia
+	data := `{"videoResolution": "4k", "videoCodec": "hevc", "audioCodec": "eac3", "bitrate": 42000,
+		"Part": [{"file": "/m/x.mkv", "Stream": [{"streamType": 2}, {"streamType": 1, "colorTrc": "smpte2084"}]}]}`
+	if err := json.Unmarshal([]byte(data), &m); err != nil {
+		t.Fatal(err)
+	}
+	var item MediaItem
+	m.applyTo(&item)
+	if item.VideoResolution != "4k" || item.VideoCodec != "hevc" || item.AudioCodec != "eac3" || item.Bitrate != 42000 || item.HDR != "HDR10" {
+		t.Errorf("unexpected item %+v", item)
+	}
+}
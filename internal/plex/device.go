@@ -0,0 +1,116 @@
+package plex
+
+import (
+	"net/http"
+	"os"
+	"runtime"
+	"sync"
+)
+
+// plexProduct is the product name Plex shows for goplexcli's sessions and
+// devices.
+const plexProduct = "GoplexCLI"
+
+// Device identifies this install to Plex. Every request the package makes
+// carries it as X-Plex-* headers, which is how Plex names goplexcli in its
+// devices dashboard and in other apps' now-playing lists.
+type Device struct {
+	// ClientIdentifier is unique to the install and stable across runs
+	// (see config.ClientIdentifier). Plex keeps one device per identifier,
+	// and signing in with it registers the device on the account.
+	ClientIdentifier string
+	// Name is the device's display name. Empty uses the hostname.
+	Name string
+	// Version is goplexcli's version.
+	Version string
+}
+
+var (
+	deviceMu sync.RWMutex
+	device   = Device{ClientIdentifier: "goplexcli", Version: "1.0"}
+)
+
+// SetDevice sets the identity sent with every request. Empty fields keep
+// their current value. Call it before making requests, typically once at
+// startup.
+func SetDevice(d Device) {
+	deviceMu.Lock()
+	defer deviceMu.Unlock()
+	if d.ClientIdentifier != "" {
+		device.ClientIdentifier = d.ClientIdentifier
+	}
+	if d.Name != "" {
+		device.Name = d.Name
+	}
+	if d.Version != "" {
+		device.Version = d.Version
+	}
+}
+
+// currentDevice returns the identity set with SetDevice, with the name
+// defaulted to the hostname.
+func currentDevice() Device {
+	deviceMu.RLock()
+	d := device
+	deviceMu.RUnlock()
+	if d.Name == "" {
+		if host, err := os.Hostname(); err == nil && host != "" {
+			d.Name = host
+		} else {
+			d.Name = plexProduct
+		}
+	}
+	return d
+}
+
+// platformName is the operating system as Plex apps report it.
+func platformName() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "macOS"
+	case "windows":
+		return "Windows"
+	case "linux":
+		return "Linux"
+	case "android":
+		return "Android"
+	case "freebsd":
+		return "FreeBSD"
+	}
+	return runtime.GOOS
+}
+
+// deviceHeaders returns the X-Plex-* headers that identify this install.
+func deviceHeaders() http.Header {
+	d := currentDevice()
+	h := make(http.Header)
+	h.Set("X-Plex-Client-Identifier", d.ClientIdentifier)
+	h.Set("X-Plex-Product", plexProduct)
+	h.Set("X-Plex-Version", d.Version)
+	h.Set("X-Plex-Platform", platformName())
+	h.Set("X-Plex-Device", platformName())
+	h.Set("X-Plex-Device-Name", d.Name)
+	return h
+}
+
+// deviceTransport adds the device headers to each request that doesn't set
+// them itself, so plexgo's requests are covered as well as the package's own.
+type deviceTransport struct {
+	base http.RoundTripper
+}
+
+func (t *deviceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	cloned := false
+	for name, values := range deviceHeaders() {
+		if req.Header.Get(name) != "" {
+			continue
+		}
+		if !cloned {
+			// A RoundTripper must not modify the caller's request.
+			req = req.Clone(req.Context())
+			cloned = true
+		}
+		req.Header[name] = values
+	}
+	return t.base.RoundTrip(req)
+}
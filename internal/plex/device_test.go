@@ -0,0 +1,55 @@
+package plex
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDeviceTransport(t *testing.T) {
+	old := device
+	t.Cleanup(func() { device = old })
+	SetDevice(Device{ClientIdentifier: "goplexcli-test", Name: "Den", Version: "2.3.4"})
+
+	var got http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+	req.Header.Set("X-Plex-Device-Name", "Explicit")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	want := map[string]string{
+		"X-Plex-Client-Identifier": "goplexcli-test",
+		"X-Plex-Product":           plexProduct,
+		"X-Plex-Version":           "2.3.4",
+		"X-Plex-Platform":          platformName(),
+		"X-Plex-Device-Name":       "Explicit",
+	}
+	for name, value := range want {
+		if got.Get(name) != value {
+			t.Errorf("%s = %q, want %q", name, got.Get(name), value)
+		}
+	}
+	if req.Header.Get("X-Plex-Client-Identifier") != "" {
+		t.Error("transport modified the caller's request")
+	}
+}
+
+func TestSetDeviceKeepsUnsetFields(t *testing.T) {
+	old := device
+	t.Cleanup(func() { device = old })
+	SetDevice(Device{ClientIdentifier: "first", Name: "Den", Version: "1.2"})
+	SetDevice(Device{Name: "Office"})
+
+	d := currentDevice()
+	if d.ClientIdentifier != "first" || d.Name != "Office" || d.Version != "1.2" {
+		t.Errorf("currentDevice = %+v, want first/Office/1.2", d)
+	}
+}
@@ -0,0 +1,61 @@
+package plex
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	apperrors "github.com/joshkerr/goplexcli/internal/errors"
+)
+
+// statusError is a failure the server reported with an HTTP status, kept so
+// wrapErr can put the code in the PlexError callers see.
+type statusError struct {
+	code int
+	err  error
+}
+
+func (e *statusError) Error() string { return e.err.Error() }
+func (e *statusError) Unwrap() error { return e.err }
+
+// Is lets callers test for the common cases with the sentinel errors, e.g.
+// errors.Is(err, apperrors.ErrAuthRequired) for an expired token.
+func (e *statusError) Is(target error) bool {
+	switch target {
+	case apperrors.ErrAuthRequired:
+		return e.code == http.StatusUnauthorized
+	case apperrors.ErrNotFound:
+		return e.code == http.StatusNotFound
+	}
+	return false
+}
+
+// newStatusError returns an error for a response with the given status code.
+func newStatusError(code int, format string, args ...any) error {
+	return &statusError{code: code, err: fmt.Errorf(format, args...)}
+}
+
+// wrapErr reports err as a failure of the client operation op; see
+// plexError.
+func (c *Client) wrapErr(op string, err error) error {
+	return plexError(op, c.serverName, err)
+}
+
+// plexError reports err as a failure of op against server, as an
+// apperrors.PlexError carrying the HTTP status, if any. Errors already
+// wrapped by a nested call are returned unchanged, so the innermost
+// operation is the one reported.
+func plexError(op, server string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var pe *apperrors.PlexError
+	if errors.As(err, &pe) {
+		return err
+	}
+	var se *statusError
+	if errors.As(err, &se) {
+		return apperrors.NewPlexErrorWithStatus(op, server, se.code, err)
+	}
+	return apperrors.NewPlexError(op, server, err)
+}
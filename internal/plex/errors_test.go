@@ -0,0 +1,38 @@
+package plex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apperrors "github.com/joshkerr/goplexcli/internal/errors"
+)
+
+func TestClientErrorsArePlexErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+	c := testPlexClient(ts.URL)
+	c.serverName = "Basement"
+
+	_, err := c.GetSessions(context.Background())
+	var pe *apperrors.PlexError
+	if !errors.As(err, &pe) {
+		t.Fatalf("GetSessions error %v is not a PlexError", err)
+	}
+	if pe.Op != "GetSessions" || pe.Server != "Basement" || pe.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unexpected PlexError %+v", pe)
+	}
+	if !errors.Is(err, apperrors.ErrAuthRequired) {
+		t.Error("a 401 should match ErrAuthRequired")
+	}
+
+	// Wrapping again, as a method calling another does, keeps the inner
+	// operation.
+	if err := c.wrapErr("Outer", err); !errors.As(err, &pe) || pe.Op != "GetSessions" {
+		t.Errorf("rewrapped error = %v", err)
+	}
+}
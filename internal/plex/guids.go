@@ -0,0 +1,45 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+)
+
+type guidsResponse struct {
+	MediaContainer struct {
+		Metadata []struct {
+			GUID string `json:"guid"`
+			Guid []struct {
+				ID string `json:"id"`
+			} `json:"Guid"`
+		} `json:"Metadata"`
+	} `json:"MediaContainer"`
+}
+
+// GetGUIDs returns the identifiers of the item with the given rating key:
+// external ones such as "imdb://tt0113277" or "tmdb://949" first, then
+// Plex's own "plex://..." GUID. They identify the item across servers and
+// services, unlike the rating key.
+func (c *Client) GetGUIDs(ctx context.Context, ratingKey string) (_ []string, err error) {
+	defer func() { err = c.wrapErr("GetGUIDs", err) }()
+
+	url := fmt.Sprintf("%s/library/metadata/%s?includeGuids=1&X-Plex-Token=%s", c.ServerURL(), ratingKey, c.token)
+
+	var resp guidsResponse
+	if err := c.getJSON(ctx, url, "guids", &resp); err != nil {
+		return nil, err
+	}
+
+	var guids []string
+	for _, m := range resp.MediaContainer.Metadata {
+		for _, g := range m.Guid {
+			if g.ID != "" {
+				guids = append(guids, g.ID)
+			}
+		}
+		if m.GUID != "" {
+			guids = append(guids, m.GUID)
+		}
+	}
+	return guids, nil
+}
@@ -0,0 +1,36 @@
+package plex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestGetGUIDs(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/library/metadata/3" || r.URL.Query().Get("includeGuids") != "1" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"MediaContainer": map[string]any{"Metadata": []map[string]any{{
+				"guid": "plex://movie/5d776825880197001ec967c6",
+				"Guid": []map[string]any{{"id": "imdb://tt0113277"}, {"id": "tmdb://949"}, {"id": ""}},
+			}}},
+		})
+	}))
+	defer ts.Close()
+
+	guids, err := testPlexClient(ts.URL).GetGUIDs(context.Background(), "3")
+	if err != nil {
+		t.Fatalf("GetGUIDs: %v", err)
+	}
+	want := []string{"imdb://tt0113277", "tmdb://949", "plex://movie/5d776825880197001ec967c6"}
+	if !slices.Equal(guids, want) {
+		t.Errorf("guids = %v, want %v", guids, want)
+	}
+}
@@ -0,0 +1,110 @@
+package plex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// plexTVURL is the base of the plex.tv account API; tests point it at a
+// local server.
+var plexTVURL = "https://plex.tv"
+
+// ErrPINRequired is returned by SwitchHomeUser when the user is protected
+// by a PIN and none (or the wrong one) was given.
+var ErrPINRequired = errors.New("a valid PIN is required for this user")
+
+// HomeUser is a member of a Plex Home: the admin account, or a managed or
+// invited user sharing it.
+type HomeUser struct {
+	ID    int    `json:"id"`
+	UUID  string `json:"uuid"`
+	Title string `json:"title"`
+	// Admin is the account that owns the Home.
+	Admin bool `json:"admin"`
+	// Restricted users are managed accounts with content restrictions,
+	// e.g. for kids.
+	Restricted bool `json:"restricted"`
+	// Protected users need a PIN to switch to.
+	Protected bool `json:"protected"`
+}
+
+// GetHomeUsers lists the users of the Plex Home the account token belongs
+// to. An account that isn't in a Home gets an error.
+func GetHomeUsers(ctx context.Context, accountToken string) (_ []HomeUser, err error) {
+	defer func() { err = plexError("GetHomeUsers", plexTVURL, err) }()
+
+	var resp struct {
+		Users []HomeUser `json:"users"`
+	}
+	if err := plexTVRequest(ctx, http.MethodGet, "/api/v2/home/users", accountToken, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list home users: %w", err)
+	}
+	return resp.Users, nil
+}
+
+// SwitchHomeUser switches the account to a Home user and returns that
+// user's plex.tv token. Playback, progress and watch history recorded with
+// it belong to that user alone. pin may be empty for unprotected users.
+func SwitchHomeUser(ctx context.Context, accountToken string, userID int, pin string) (_ string, err error) {
+	defer func() { err = plexError("SwitchHomeUser", plexTVURL, err) }()
+
+	path := fmt.Sprintf("/api/v2/home/users/%d/switch", userID)
+	if pin != "" {
+		path += "?pin=" + url.QueryEscape(pin)
+	}
+	var resp struct {
+		AuthToken string `json:"authToken"`
+	}
+	if err := plexTVRequest(ctx, http.MethodPost, path, accountToken, &resp); err != nil {
+		return "", err
+	}
+	if resp.AuthToken == "" {
+		return "", fmt.Errorf("no auth token received for home user")
+	}
+	return resp.AuthToken, nil
+}
+
+// plexTVRequest calls the plex.tv API with the account token, if any, and
+// decodes the JSON response into v.
+func plexTVRequest(ctx context.Context, method, path, token string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, method, plexTVURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		req.Header.Set("X-Plex-Token", token)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to plex.tv failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return newStatusError(resp.StatusCode, "authentication failed: invalid or expired token (status %d)", resp.StatusCode)
+	case resp.StatusCode == http.StatusForbidden && method == http.MethodPost:
+		// plex.tv answers a missing or wrong PIN with 403
+		return ErrPINRequired
+	case resp.StatusCode == http.StatusNotFound:
+		return newStatusError(resp.StatusCode, "not found on plex.tv (status %d); is this account part of a Plex Home?", resp.StatusCode)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return newStatusError(resp.StatusCode, "unexpected status code %d from plex.tv", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse plex.tv response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package plex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHomeUsers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Plex-Token") != "admin-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/home/users":
+			_, _ = w.Write([]byte(`{"users":[
+				{"id":1,"title":"Josh","admin":true},
+				{"id":2,"title":"Kids","restricted":true},
+				{"id":3,"title":"Guest","protected":true}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v2/home/users/2/switch":
+			_, _ = w.Write([]byte(`{"authToken":"kids-token"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v2/home/users/3/switch":
+			if r.URL.Query().Get("pin") != "1234" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte(`{"authToken":"guest-token"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+	old := plexTVURL
+	plexTVURL = ts.URL
+	defer func() { plexTVURL = old }()
+
+	ctx := context.Background()
+	users, err := GetHomeUsers(ctx, "admin-token")
+	if err != nil {
+		t.Fatalf("GetHomeUsers: %v", err)
+	}
+	if len(users) != 3 || !users[0].Admin || !users[1].Restricted || !users[2].Protected {
+		t.Errorf("users = %+v", users)
+	}
+	if _, err := GetHomeUsers(ctx, "bad-token"); err == nil {
+		t.Error("GetHomeUsers with a bad token: expected an error")
+	}
+
+	token, err := SwitchHomeUser(ctx, "admin-token", 2, "")
+	if err != nil || token != "kids-token" {
+		t.Errorf("SwitchHomeUser(2) = %q, %v", token, err)
+	}
+	if _, err := SwitchHomeUser(ctx, "admin-token", 3, ""); !errors.Is(err, ErrPINRequired) {
+		t.Errorf("SwitchHomeUser(3) without PIN: err = %v, want ErrPINRequired", err)
+	}
+	if token, err := SwitchHomeUser(ctx, "admin-token", 3, "1234"); err != nil || token != "guest-token" {
+		t.Errorf("SwitchHomeUser(3, pin) = %q, %v", token, err)
+	}
+}
@@ -0,0 +1,168 @@
+package plex
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// HTTPOptions tunes the HTTP client every Client shares.
+type HTTPOptions struct {
+	// Timeout bounds a whole request, retries included. Zero uses
+	// DefaultHTTPTimeout.
+	Timeout time.Duration
+	// Retries is how many times a request is retried after a 5xx or 429
+	// response or a network error. Negative disables retries; zero uses
+	// DefaultHTTPRetries.
+	Retries int
+}
+
+const (
+	// DefaultHTTPTimeout is the request timeout unless configured otherwise.
+	DefaultHTTPTimeout = 60 * time.Second
+	// DefaultHTTPRetries is the retry count unless configured otherwise.
+	DefaultHTTPRetries = 3
+)
+
+// maxRetryDelay caps the backoff between retries, including delays a server
+// asks for with Retry-After.
+const maxRetryDelay = 10 * time.Second
+
+// retryBaseDelay is the pause before the first retry; each later retry
+// doubles it. A variable so tests can shorten it.
+var retryBaseDelay = 500 * time.Millisecond
+
+var (
+	httpMu      sync.RWMutex
+	httpRetries = DefaultHTTPRetries
+)
+
+// httpClient is shared by every request the package makes, so connections to
+// a server are pooled and reused across clients. Its transport identifies
+// goplexcli with the device headers and retries failed idempotent requests
+// with exponential backoff.
+var httpClient = &http.Client{
+	Timeout:   DefaultHTTPTimeout,
+	Transport: &deviceTransport{base: &retryTransport{base: newPooledTransport()}},
+}
+
+// ConfigureHTTP applies opts to the shared HTTP client. Call it before
+// making requests, typically once at startup.
+func ConfigureHTTP(opts HTTPOptions) {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHTTPTimeout
+	}
+	retries := opts.Retries
+	switch {
+	case retries == 0:
+		retries = DefaultHTTPRetries
+	case retries < 0:
+		retries = 0
+	}
+
+	httpMu.Lock()
+	defer httpMu.Unlock()
+	httpClient.Timeout = timeout
+	httpRetries = retries
+}
+
+func newPooledTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	// Indexing fetches several sections of one server at once; keep enough
+	// idle connections per host that they aren't re-dialed for every page.
+	t.MaxIdleConnsPerHost = 2 * sectionFetchConcurrency
+	t.IdleConnTimeout = 90 * time.Second
+	return t
+}
+
+type noRetryKey struct{}
+
+// withoutRetries marks ctx so requests made with it are not retried by the
+// transport, for callers with their own retry strategy.
+func withoutRetries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRetryKey{}, true)
+}
+
+// retryTransport retries idempotent requests that fail with a network error,
+// a 5xx or a 429, backing off exponentially between attempts and honoring
+// Retry-After. Requests with a body are never retried.
+type retryTransport struct {
+	base http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	httpMu.RLock()
+	retries := httpRetries
+	httpMu.RUnlock()
+	if (req.Body != nil && req.Body != http.NoBody) || !isIdempotent(req.Method) || req.Context().Value(noRetryKey{}) != nil {
+		retries = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		resp, err := t.base.RoundTrip(req)
+		resp = logRequest(req, resp, err, start)
+		if attempt >= retries || !shouldRetry(resp, err) || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		delay := retryDelay(attempt, resp)
+		if resp != nil {
+			// Drain a little so the connection can be reused.
+			_, _ = io.CopyN(io.Discard, resp.Body, 4096)
+			resp.Body.Close()
+		}
+		apiLogger.Printf("retrying %s %s in %v (attempt %d/%d)", req.Method, req.URL.Path, delay, attempt+1, retries)
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// redactURL returns u as a string with any X-Plex-Token query parameter
+// replaced, so it is safe to log.
+func redactURL(u *url.URL) string {
+	q := u.Query()
+	if q.Get("X-Plex-Token") == "" {
+		return u.String()
+	}
+	q.Set("X-Plex-Token", "REDACTED")
+	redacted := *u
+	redacted.RawQuery = q.Encode()
+	return redacted.String()
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// retryDelay returns how long to wait before retry number attempt+1: the
+// server's Retry-After if it sent one, otherwise retryBaseDelay doubled per
+// attempt, capped at maxRetryDelay.
+func retryDelay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+			return min(time.Duration(secs)*time.Second, maxRetryDelay)
+		}
+	}
+	return min(retryBaseDelay<<attempt, maxRetryDelay)
+}
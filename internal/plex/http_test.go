@@ -0,0 +1,99 @@
+package plex
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func fastBackoff(t *testing.T) {
+	t.Helper()
+	old := retryBaseDelay
+	retryBaseDelay = time.Millisecond
+	t.Cleanup(func() { retryBaseDelay = old })
+}
+
+func TestRetryTransport(t *testing.T) {
+	fastBackoff(t)
+	var calls atomic.Int32
+	status := http.StatusServiceUnavailable
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(status)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	get := func(ctx context.Context, method string) int {
+		calls.Store(0)
+		req, _ := http.NewRequestWithContext(ctx, method, ts.URL, nil)
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: %v", method, err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := get(context.Background(), http.MethodGet); code != http.StatusOK || calls.Load() != 3 {
+		t.Errorf("GET after two 503s: status %d after %d calls, want 200 after 3", code, calls.Load())
+	}
+
+	status = http.StatusTooManyRequests
+	if code := get(context.Background(), http.MethodPut); code != http.StatusOK || calls.Load() != 3 {
+		t.Errorf("PUT after two 429s: status %d after %d calls, want 200 after 3", code, calls.Load())
+	}
+
+	if code := get(context.Background(), http.MethodPost); code != status || calls.Load() != 1 {
+		t.Errorf("POST must not be retried: status %d after %d calls", code, calls.Load())
+	}
+
+	if code := get(withoutRetries(context.Background()), http.MethodGet); code != status || calls.Load() != 1 {
+		t.Errorf("opted-out GET must not be retried: status %d after %d calls", code, calls.Load())
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	if d := retryDelay(2, nil); d != 4*retryBaseDelay {
+		t.Errorf("third retry delay = %v, want %v", d, 4*retryBaseDelay)
+	}
+	if d := retryDelay(20, nil); d != maxRetryDelay {
+		t.Errorf("delay should be capped at %v, got %v", maxRetryDelay, d)
+	}
+	resp := &http.Response{Header: http.Header{"Retry-After": {"2"}}}
+	if d := retryDelay(0, resp); d != 2*time.Second {
+		t.Errorf("Retry-After delay = %v, want 2s", d)
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	u, _ := url.Parse("http://plex:32400/library/sections?X-Plex-Token=secret&type=1")
+	got := redactURL(u)
+	if strings.Contains(got, "secret") || !strings.Contains(got, "X-Plex-Token=REDACTED") || !strings.Contains(got, "type=1") {
+		t.Errorf("redactURL = %q", got)
+	}
+	u, _ = url.Parse("http://plex:32400/identity")
+	if got := redactURL(u); got != "http://plex:32400/identity" {
+		t.Errorf("redactURL without a token = %q", got)
+	}
+}
+
+func TestConfigureHTTP(t *testing.T) {
+	t.Cleanup(func() { ConfigureHTTP(HTTPOptions{}) })
+
+	ConfigureHTTP(HTTPOptions{Timeout: 5 * time.Second, Retries: -1})
+	if httpClient.Timeout != 5*time.Second || httpRetries != 0 {
+		t.Errorf("got timeout %v, retries %d", httpClient.Timeout, httpRetries)
+	}
+	ConfigureHTTP(HTTPOptions{})
+	if httpClient.Timeout != DefaultHTTPTimeout || httpRetries != DefaultHTTPRetries {
+		t.Errorf("defaults not restored: timeout %v, retries %d", httpClient.Timeout, httpRetries)
+	}
+}
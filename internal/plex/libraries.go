@@ -0,0 +1,58 @@
+package plex
+
+import (
+	"strings"
+	"sync"
+)
+
+// LibraryFilter chooses which library sections indexing fetches. Entries
+// name a section by title ("Movies"), or by server and title ("Den/Movies")
+// to pick one server's section; both are matched case-insensitively. A
+// non-empty Include is an allowlist, and Exclude then drops sections from
+// what remains.
+type LibraryFilter struct {
+	Include []string
+	Exclude []string
+}
+
+// Allows reports whether the section titled title on server is indexed.
+func (f LibraryFilter) Allows(server, title string) bool {
+	if len(f.Include) > 0 && !matchesLibrary(f.Include, server, title) {
+		return false
+	}
+	return !matchesLibrary(f.Exclude, server, title)
+}
+
+func matchesLibrary(entries []string, server, title string) bool {
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if strings.EqualFold(e, title) || strings.EqualFold(e, server+"/"+title) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	libraryFilterMu sync.RWMutex
+	libraryFilter   LibraryFilter
+)
+
+// SetLibraryFilter limits the sections GetAllMedia, GetMediaSince and the
+// multi-server variants fetch. Like ConfigureHTTP, call it once at startup.
+func SetLibraryFilter(f LibraryFilter) {
+	libraryFilterMu.Lock()
+	defer libraryFilterMu.Unlock()
+	libraryFilter = f
+}
+
+// indexed reports whether lib on server should be fetched when indexing:
+// it must hold movies or shows and pass the library filter.
+func indexed(server string, lib Library) bool {
+	if lib.Type != "movie" && lib.Type != "show" {
+		return false
+	}
+	libraryFilterMu.RLock()
+	defer libraryFilterMu.RUnlock()
+	return libraryFilter.Allows(server, lib.Title)
+}
@@ -0,0 +1,41 @@
+package plex
+
+import "testing"
+
+func TestLibraryFilterAllows(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter LibraryFilter
+		server string
+		title  string
+		want   bool
+	}{
+		{"no filter", LibraryFilter{}, "Den", "Movies", true},
+		{"excluded by title", LibraryFilter{Exclude: []string{"home videos"}}, "Den", "Home Videos", false},
+		{"excluded on one server only", LibraryFilter{Exclude: []string{"Den/Fitness"}}, "Office", "Fitness", true},
+		{"excluded on that server", LibraryFilter{Exclude: []string{"Den/Fitness"}}, "Den", "Fitness", false},
+		{"in allowlist", LibraryFilter{Include: []string{"Movies", "TV Shows"}}, "Den", "TV Shows", true},
+		{"outside allowlist", LibraryFilter{Include: []string{"Movies"}}, "Den", "Anime", false},
+		{"allowed then excluded", LibraryFilter{Include: []string{"Movies"}, Exclude: []string{"Den/Movies"}}, "Den", "Movies", false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.Allows(tt.server, tt.title); got != tt.want {
+			t.Errorf("%s: Allows(%q, %q) = %v, want %v", tt.name, tt.server, tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestIndexedSkipsOtherTypesAndFiltered(t *testing.T) {
+	SetLibraryFilter(LibraryFilter{Exclude: []string{"Fitness"}})
+	t.Cleanup(func() { SetLibraryFilter(LibraryFilter{}) })
+
+	if indexed("Den", Library{Title: "Music", Type: "artist"}) {
+		t.Error("music library indexed")
+	}
+	if indexed("Den", Library{Title: "Fitness", Type: "movie"}) {
+		t.Error("excluded library indexed")
+	}
+	if !indexed("Den", Library{Title: "Movies", Type: "movie"}) {
+		t.Error("movie library not indexed")
+	}
+}
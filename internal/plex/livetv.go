@@ -0,0 +1,262 @@
+package plex
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// DVR is a Plex DVR: a tuner setup with its channel lineup and program guide.
+type DVR struct {
+	Key           string
+	Lineup        string // lineup URI, passed back when listing channels
+	EPGIdentifier string // guide provider, e.g. "tv.plex.providers.epg.cloud:5"
+}
+
+// Channel is a tunable live TV channel.
+type Channel struct {
+	Number     string // virtual channel number, e.g. "4.1"
+	CallSign   string
+	Title      string
+	Identifier string // passed to TuneChannel
+	DVRKey     string
+}
+
+// Airing is a program in the guide.
+type Airing struct {
+	Title         string
+	ShowTitle     string // series title for episodes, "" for movies and one-offs
+	ChannelNumber string
+	CallSign      string
+	Begins        time.Time
+	Ends          time.Time
+}
+
+// Recording is a recording the DVR has scheduled.
+type Recording struct {
+	Title     string
+	ShowTitle string
+	Season    int
+	Episode   int
+	CallSign  string
+	Begins    time.Time
+	Status    string // e.g. "scheduled", "inprogress"
+}
+
+type dvrsResponse struct {
+	MediaContainer struct {
+		Dvr []struct {
+			Key           string `json:"key"`
+			Lineup        string `json:"lineup"`
+			EPGIdentifier string `json:"epgIdentifier"`
+		} `json:"Dvr"`
+	} `json:"MediaContainer"`
+}
+
+// GetDVRs returns the server's DVRs. Servers without Plex DVR set up have
+// none.
+func (c *Client) GetDVRs(ctx context.Context) (_ []DVR, err error) {
+	defer func() { err = c.wrapErr("GetDVRs", err) }()
+
+	var resp dvrsResponse
+	if err := c.getJSON(ctx, fmt.Sprintf("%s/livetv/dvrs?X-Plex-Token=%s", c.ServerURL(), c.token), "DVRs", &resp); err != nil {
+		return nil, err
+	}
+	dvrs := make([]DVR, 0, len(resp.MediaContainer.Dvr))
+	for _, d := range resp.MediaContainer.Dvr {
+		dvrs = append(dvrs, DVR{Key: d.Key, Lineup: d.Lineup, EPGIdentifier: d.EPGIdentifier})
+	}
+	return dvrs, nil
+}
+
+type channelsResponse struct {
+	MediaContainer struct {
+		Channel []struct {
+			ChannelVcn string `json:"channelVcn"`
+			CallSign   string `json:"callSign"`
+			Title      string `json:"title"`
+			Identifier string `json:"identifier"`
+		} `json:"Channel"`
+	} `json:"MediaContainer"`
+}
+
+// GetChannels returns the channels in a DVR's lineup.
+func (c *Client) GetChannels(ctx context.Context, dvr DVR) (_ []Channel, err error) {
+	defer func() { err = c.wrapErr("GetChannels", err) }()
+
+	u := fmt.Sprintf("%s/livetv/epg/channels?lineup=%s&X-Plex-Token=%s", c.ServerURL(), url.QueryEscape(dvr.Lineup), c.token)
+	var resp channelsResponse
+	if err := c.getJSON(ctx, u, "channels", &resp); err != nil {
+		return nil, err
+	}
+	channels := make([]Channel, 0, len(resp.MediaContainer.Channel))
+	for _, ch := range resp.MediaContainer.Channel {
+		channels = append(channels, Channel{
+			Number:     ch.ChannelVcn,
+			CallSign:   ch.CallSign,
+			Title:      ch.Title,
+			Identifier: ch.Identifier,
+			DVRKey:     dvr.Key,
+		})
+	}
+	return channels, nil
+}
+
+// airingMetadata is a guide or recording entry; Media carries the channel
+// and time slot.
+type airingMetadata struct {
+	Title            string `json:"title"`
+	GrandparentTitle string `json:"grandparentTitle"`
+	ParentIndex      int    `json:"parentIndex"`
+	Index            int    `json:"index"`
+	Media            []struct {
+		ChannelVcn      string `json:"channelVcn"`
+		ChannelCallSign string `json:"channelCallSign"`
+		BeginsAt        int64  `json:"beginsAt"`
+		EndsAt          int64  `json:"endsAt"`
+	} `json:"Media"`
+}
+
+type gridResponse struct {
+	MediaContainer struct {
+		Metadata []airingMetadata `json:"Metadata"`
+	} `json:"MediaContainer"`
+}
+
+// GetAirings returns what is on each channel of a DVR's guide at the given
+// time, ordered by channel number.
+func (c *Client) GetAirings(ctx context.Context, dvr DVR, at time.Time) (_ []Airing, err error) {
+	defer func() { err = c.wrapErr("GetAirings", err) }()
+
+	if dvr.EPGIdentifier == "" {
+		return nil, fmt.Errorf("DVR %s has no program guide", dvr.Key)
+	}
+	u := fmt.Sprintf("%s/%s/grid?type=1,4&beginsAt%%3C=%d&endsAt%%3E=%d&X-Plex-Token=%s",
+		c.ServerURL(), dvr.EPGIdentifier, at.Unix(), at.Unix(), c.token)
+	var resp gridResponse
+	if err := c.getJSON(ctx, u, "program guide", &resp); err != nil {
+		return nil, err
+	}
+	var airings []Airing
+	for _, m := range resp.MediaContainer.Metadata {
+		for _, media := range m.Media {
+			airings = append(airings, Airing{
+				Title:         m.Title,
+				ShowTitle:     m.GrandparentTitle,
+				ChannelNumber: media.ChannelVcn,
+				CallSign:      media.ChannelCallSign,
+				Begins:        time.Unix(media.BeginsAt, 0),
+				Ends:          time.Unix(media.EndsAt, 0),
+			})
+		}
+	}
+	sort.SliceStable(airings, func(i, j int) bool { return channelLess(airings[i].ChannelNumber, airings[j].ChannelNumber) })
+	return airings, nil
+}
+
+// channelLess orders virtual channel numbers like "4.1" < "13.2" numerically.
+func channelLess(a, b string) bool {
+	var amaj, amin, bmaj, bmin int
+	na, _ := fmt.Sscanf(a, "%d.%d", &amaj, &amin)
+	nb, _ := fmt.Sscanf(b, "%d.%d", &bmaj, &bmin)
+	if na == 0 || nb == 0 {
+		return a < b
+	}
+	if amaj != bmaj {
+		return amaj < bmaj
+	}
+	return amin < bmin
+}
+
+type scheduledResponse struct {
+	MediaContainer struct {
+		MediaGrabOperation []struct {
+			Status   string         `json:"status"`
+			Metadata airingMetadata `json:"Metadata"`
+		} `json:"MediaGrabOperation"`
+	} `json:"MediaContainer"`
+}
+
+// GetScheduledRecordings returns the recordings the server's DVRs will make,
+// soonest first.
+func (c *Client) GetScheduledRecordings(ctx context.Context) (_ []Recording, err error) {
+	defer func() { err = c.wrapErr("GetScheduledRecordings", err) }()
+
+	var resp scheduledResponse
+	if err := c.getJSON(ctx, fmt.Sprintf("%s/media/subscriptions/scheduled?X-Plex-Token=%s", c.ServerURL(), c.token), "scheduled recordings", &resp); err != nil {
+		return nil, err
+	}
+	var recordings []Recording
+	for _, op := range resp.MediaContainer.MediaGrabOperation {
+		m := op.Metadata
+		r := Recording{
+			Title:     m.Title,
+			ShowTitle: m.GrandparentTitle,
+			Season:    m.ParentIndex,
+			Episode:   m.Index,
+			Status:    op.Status,
+		}
+		if len(m.Media) > 0 {
+			r.CallSign = m.Media[0].ChannelCallSign
+			r.Begins = time.Unix(m.Media[0].BeginsAt, 0)
+		}
+		recordings = append(recordings, r)
+	}
+	sort.SliceStable(recordings, func(i, j int) bool { return recordings[i].Begins.Before(recordings[j].Begins) })
+	return recordings, nil
+}
+
+type tuneResponse struct {
+	MediaContainer struct {
+		MediaSubscription []struct {
+			MediaGrabOperation []struct {
+				Metadata struct {
+					Key string `json:"key"`
+				} `json:"Metadata"`
+			} `json:"MediaGrabOperation"`
+		} `json:"MediaSubscription"`
+	} `json:"MediaContainer"`
+}
+
+// TuneChannel asks the DVR to tune a channel and returns an HLS URL for the
+// live stream, transcoded by the server so any player can open it.
+func (c *Client) TuneChannel(ctx context.Context, ch Channel) (_ string, err error) {
+	defer func() { err = c.wrapErr("TuneChannel", err) }()
+
+	u := fmt.Sprintf("%s/livetv/dvrs/%s/channels/%s/tune?X-Plex-Token=%s",
+		c.ServerURL(), url.PathEscape(ch.DVRKey), url.PathEscape(ch.Identifier), c.token)
+	var resp tuneResponse
+	if err := c.doJSON(ctx, http.MethodPost, u, "channel", &resp); err != nil {
+		return "", fmt.Errorf("failed to tune channel %s: %w", ch.Number, err)
+	}
+	subs := resp.MediaContainer.MediaSubscription
+	if len(subs) == 0 || len(subs[0].MediaGrabOperation) == 0 || subs[0].MediaGrabOperation[0].Metadata.Key == "" {
+		return "", fmt.Errorf("failed to tune channel %s: no tuner available", ch.Number)
+	}
+	return c.liveStreamURL(subs[0].MediaGrabOperation[0].Metadata.Key), nil
+}
+
+// liveStreamURL is the universal transcoder's HLS playlist for a tuned
+// live TV session.
+func (c *Client) liveStreamURL(key string) string {
+	session := make([]byte, 8)
+	_, _ = rand.Read(session)
+	q := url.Values{
+		"path":         {key},
+		"protocol":     {"hls"},
+		"directStream": {"1"},
+		"session":      {hex.EncodeToString(session)},
+		"X-Plex-Token": {c.token},
+	}
+	// The player fetches this URL without our transport, so the device
+	// headers go in the query string for the session to be attributed.
+	for name, values := range deviceHeaders() {
+		q[name] = values
+	}
+	return c.ServerURL() + "/video/:/transcode/universal/start.m3u8?" + q.Encode()
+}
@@ -0,0 +1,108 @@
+package plex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newLiveTVServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body any
+		switch {
+		case r.URL.Path == "/livetv/dvrs":
+			body = map[string]any{"Dvr": []map[string]any{{"key": "9", "lineup": "lineup://tv.plex.providers.epg.cloud/x", "epgIdentifier": "tv.plex.providers.epg.cloud:9"}}}
+		case r.URL.Path == "/livetv/epg/channels":
+			if r.URL.Query().Get("lineup") != "lineup://tv.plex.providers.epg.cloud/x" {
+				http.NotFound(w, r)
+				return
+			}
+			body = map[string]any{"Channel": []map[string]any{{"channelVcn": "4.1", "callSign": "WRC", "title": "NBC", "identifier": "004.1"}}}
+		case r.URL.Path == "/tv.plex.providers.epg.cloud:9/grid":
+			if r.URL.Query().Get("beginsAt<") != "1000" || r.URL.Query().Get("endsAt>") != "1000" {
+				t.Errorf("unexpected grid query %q", r.URL.RawQuery)
+			}
+			body = map[string]any{"Metadata": []map[string]any{
+				{"title": "News at 6", "Media": []map[string]any{{"channelVcn": "13.2", "channelCallSign": "WJLA", "beginsAt": 900, "endsAt": 2700}}},
+				{"title": "Pilot", "grandparentTitle": "Drama", "Media": []map[string]any{{"channelVcn": "4.1", "channelCallSign": "WRC", "beginsAt": 0, "endsAt": 1800}}},
+			}}
+		case r.URL.Path == "/media/subscriptions/scheduled":
+			body = map[string]any{"MediaGrabOperation": []map[string]any{
+				{"status": "scheduled", "Metadata": map[string]any{"title": "Later", "Media": []map[string]any{{"channelCallSign": "WRC", "beginsAt": 5000}}}},
+				{"status": "inprogress", "Metadata": map[string]any{"title": "Now", "grandparentTitle": "Show", "parentIndex": 2, "index": 3, "Media": []map[string]any{{"channelCallSign": "WJLA", "beginsAt": 100}}}},
+			}}
+		case r.URL.Path == "/livetv/dvrs/9/channels/004.1/tune" && r.Method == http.MethodPost:
+			body = map[string]any{"MediaSubscription": []map[string]any{{"MediaGrabOperation": []map[string]any{{"Metadata": map[string]any{"key": "/livetv/sessions/abc/1/index.m3u8"}}}}}}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"MediaContainer": body})
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestLiveTV(t *testing.T) {
+	ts := newLiveTVServer(t)
+	c := testPlexClient(ts.URL)
+	ctx := context.Background()
+
+	dvrs, err := c.GetDVRs(ctx)
+	if err != nil || len(dvrs) != 1 || dvrs[0].Key != "9" {
+		t.Fatalf("GetDVRs = %+v, %v", dvrs, err)
+	}
+
+	channels, err := c.GetChannels(ctx, dvrs[0])
+	if err != nil || len(channels) != 1 {
+		t.Fatalf("GetChannels = %+v, %v", channels, err)
+	}
+	if ch := channels[0]; ch.Number != "4.1" || ch.CallSign != "WRC" || ch.DVRKey != "9" {
+		t.Errorf("unexpected channel %+v", ch)
+	}
+
+	airings, err := c.GetAirings(ctx, dvrs[0], time.Unix(1000, 0))
+	if err != nil || len(airings) != 2 {
+		t.Fatalf("GetAirings = %+v, %v", airings, err)
+	}
+	if airings[0].ChannelNumber != "4.1" || airings[0].ShowTitle != "Drama" || !airings[1].Ends.Equal(time.Unix(2700, 0)) {
+		t.Errorf("airings should be in channel order: %+v", airings)
+	}
+
+	recordings, err := c.GetScheduledRecordings(ctx)
+	if err != nil || len(recordings) != 2 {
+		t.Fatalf("GetScheduledRecordings = %+v, %v", recordings, err)
+	}
+	if r := recordings[0]; r.Title != "Now" || r.Season != 2 || r.Episode != 3 || r.Status != "inprogress" {
+		t.Errorf("recordings should be soonest first: %+v", recordings)
+	}
+
+	streamURL, err := c.TuneChannel(ctx, channels[0])
+	if err != nil {
+		t.Fatalf("TuneChannel: %v", err)
+	}
+	u, err := url.Parse(streamURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(u.Path, "/video/:/transcode/universal/start.m3u8") || u.Query().Get("path") != "/livetv/sessions/abc/1/index.m3u8" || u.Query().Get("X-Plex-Token") != "tok" {
+		t.Errorf("unexpected stream URL %s", streamURL)
+	}
+
+	if _, err := c.TuneChannel(ctx, Channel{Number: "5.1", Identifier: "005.1", DVRKey: "9"}); err == nil {
+		t.Error("tuning an unknown channel should fail")
+	}
+}
+
+func TestChannelLess(t *testing.T) {
+	if !channelLess("4.1", "13.2") || channelLess("13.2", "4.1") || !channelLess("4.1", "4.2") {
+		t.Error("channel numbers should sort numerically")
+	}
+}
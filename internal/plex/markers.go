@@ -0,0 +1,62 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+)
+
+// Marker is a stretch of an item Plex has detected as its intro or credits.
+type Marker struct {
+	Type    string // "intro" or "credits"
+	StartMs int
+	EndMs   int
+	// Final is set on credits that run to the end of the item, with nothing
+	// after them worth staying for.
+	Final bool
+}
+
+type markersResponse struct {
+	MediaContainer struct {
+		Metadata []struct {
+			Marker []struct {
+				Type            string `json:"type"`
+				StartTimeOffset int    `json:"startTimeOffset"`
+				EndTimeOffset   int    `json:"endTimeOffset"`
+				Final           bool   `json:"final"`
+			} `json:"Marker"`
+		} `json:"Metadata"`
+	} `json:"MediaContainer"`
+}
+
+// GetMarkers returns the intro and credits markers of the item with the given
+// rating key, in playback order. Items Plex hasn't analyzed have none. Other
+// marker types (e.g. commercials from DVR recordings) are left out.
+func (c *Client) GetMarkers(ctx context.Context, ratingKey string) (_ []Marker, err error) {
+	defer func() { err = c.wrapErr("GetMarkers", err) }()
+
+	url := fmt.Sprintf("%s/library/metadata/%s?includeMarkers=1&X-Plex-Token=%s", c.ServerURL(), ratingKey, c.token)
+
+	var resp markersResponse
+	if err := c.getJSON(ctx, url, "markers", &resp); err != nil {
+		return nil, err
+	}
+
+	var markers []Marker
+	for _, m := range resp.MediaContainer.Metadata {
+		for _, mk := range m.Marker {
+			if mk.Type != "intro" && mk.Type != "credits" {
+				continue
+			}
+			if mk.EndTimeOffset <= mk.StartTimeOffset {
+				continue
+			}
+			markers = append(markers, Marker{
+				Type:    mk.Type,
+				StartMs: mk.StartTimeOffset,
+				EndMs:   mk.EndTimeOffset,
+				Final:   mk.Final,
+			})
+		}
+	}
+	return markers, nil
+}
@@ -0,0 +1,50 @@
+package plex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetMarkers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/library/metadata/42" || r.URL.Query().Get("includeMarkers") != "1" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"MediaContainer": map[string]any{"Metadata": []map[string]any{{
+				"ratingKey": "42",
+				"Marker": []map[string]any{
+					{"type": "intro", "startTimeOffset": 30000, "endTimeOffset": 85000},
+					{"type": "commercial", "startTimeOffset": 600000, "endTimeOffset": 660000},
+					{"type": "credits", "startTimeOffset": 1300000, "endTimeOffset": 1380000, "final": true},
+					{"type": "intro", "startTimeOffset": 5000, "endTimeOffset": 5000},
+				},
+			}}},
+		})
+	}))
+	defer ts.Close()
+
+	c := testPlexClient(ts.URL)
+	markers, err := c.GetMarkers(context.Background(), "42")
+	if err != nil {
+		t.Fatalf("GetMarkers: %v", err)
+	}
+	if len(markers) != 2 {
+		t.Fatalf("got %d markers, want 2 (commercials and empty spans are skipped): %+v", len(markers), markers)
+	}
+	if m := markers[0]; m.Type != "intro" || m.StartMs != 30000 || m.EndMs != 85000 || m.Final {
+		t.Errorf("unexpected intro marker %+v", m)
+	}
+	if m := markers[1]; m.Type != "credits" || !m.Final {
+		t.Errorf("unexpected credits marker %+v", m)
+	}
+
+	if _, err := c.GetMarkers(context.Background(), "7"); err == nil {
+		t.Error("missing item should return an error")
+	}
+}
@@ -0,0 +1,225 @@
+package plex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Playlist is a server-side Plex playlist.
+type Playlist struct {
+	Key   string // ratingKey, used to fetch the items
+	Title string
+	Type  string // video, audio, or photo
+	Count int    // number of items
+}
+
+type playlistsResponse struct {
+	MediaContainer struct {
+		Metadata []struct {
+			RatingKey    string `json:"ratingKey"`
+			Title        string `json:"title"`
+			PlaylistType string `json:"playlistType"`
+			LeafCount    *int   `json:"leafCount"`
+		} `json:"Metadata"`
+	} `json:"MediaContainer"`
+}
+
+// Track is a playlist or album item with what it takes to stream it, for
+// items the cache doesn't index, such as music.
+type Track struct {
+	Key      string // metadata key, "/library/metadata/N"
+	Title    string // "Artist - Title" for music, the plain title otherwise
+	Duration int    // milliseconds
+	PartKey  string // the media file, "" if Plex listed none
+}
+
+type tracksResponse struct {
+	MediaContainer struct {
+		Metadata []struct {
+			Key              string `json:"key"`
+			RatingKey        string `json:"ratingKey"`
+			Type             string `json:"type"`
+			Title            string `json:"title"`
+			GrandparentTitle string `json:"grandparentTitle"`
+			OriginalTitle    string `json:"originalTitle"`
+			Duration         int    `json:"duration"`
+			Media            []struct {
+				Part []struct {
+					Key string `json:"key"`
+				} `json:"Part"`
+			} `json:"Media"`
+		} `json:"Metadata"`
+	} `json:"MediaContainer"`
+}
+
+// tracks converts a response listing playable items, in order.
+func (r *tracksResponse) tracks() []Track {
+	tracks := make([]Track, 0, len(r.MediaContainer.Metadata))
+	for _, m := range r.MediaContainer.Metadata {
+		t := Track{Key: m.Key, Title: m.Title, Duration: m.Duration}
+		if m.Type == "track" {
+			// A compilation's track artist is in originalTitle; the album
+			// artist would be "Various Artists".
+			artist := m.GrandparentTitle
+			if m.OriginalTitle != "" {
+				artist = m.OriginalTitle
+			}
+			if artist != "" {
+				t.Title = artist + " - " + m.Title
+			}
+		}
+		if len(m.Media) > 0 && len(m.Media[0].Part) > 0 {
+			t.PartKey = m.Media[0].Part[0].Key
+		}
+		tracks = append(tracks, t)
+	}
+	return tracks
+}
+
+// GetPlaylists returns the playlists visible to this client's token.
+func (c *Client) GetPlaylists(ctx context.Context) (_ []Playlist, err error) {
+	defer func() { err = c.wrapErr("GetPlaylists", err) }()
+
+	url := fmt.Sprintf("%s/playlists?X-Plex-Token=%s", c.ServerURL(), c.token)
+
+	var resp playlistsResponse
+	if err := c.getJSON(ctx, url, "playlists", &resp); err != nil {
+		return nil, err
+	}
+
+	var playlists []Playlist
+	for _, m := range resp.MediaContainer.Metadata {
+		if m.RatingKey == "" {
+			apiLogger.Printf("warning: playlist %q missing ratingKey, skipping", m.Title)
+			continue
+		}
+		playlists = append(playlists, Playlist{
+			Key:   m.RatingKey,
+			Title: m.Title,
+			Type:  m.PlaylistType,
+			Count: valueOrZeroInt(m.LeafCount),
+		})
+	}
+	return playlists, nil
+}
+
+// GetPlaylistTracks returns a playlist's items, in playlist order, with
+// their media parts, so they can be streamed without the cache.
+func (c *Client) GetPlaylistTracks(ctx context.Context, playlistKey string) (_ []Track, err error) {
+	defer func() { err = c.wrapErr("GetPlaylistTracks", err) }()
+
+	url := fmt.Sprintf("%s/playlists/%s/items?X-Plex-Token=%s", c.ServerURL(), playlistKey, c.token)
+
+	var resp tracksResponse
+	if err := c.getJSON(ctx, url, "playlist items", &resp); err != nil {
+		return nil, err
+	}
+	return resp.tracks(), nil
+}
+
+// FindAlbum looks up a music album by title (case-insensitive) across the
+// server's music libraries, returning its ratingKey, or "" if there is none.
+func (c *Client) FindAlbum(ctx context.Context, title string) (_ string, err error) {
+	libraries, err := c.GetLibraries(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer func() { err = c.wrapErr("FindAlbum", err) }()
+
+	for _, lib := range libraries {
+		if lib.Type != "artist" {
+			continue
+		}
+		// type=9 lists albums; the title filter is a substring match, so
+		// the exact match is picked here.
+		reqURL := fmt.Sprintf("%s/library/sections/%s/all?type=9&title=%s&X-Plex-Token=%s",
+			c.ServerURL(), lib.Key, url.QueryEscape(title), c.token)
+		var resp struct {
+			MediaContainer struct {
+				Metadata []struct {
+					RatingKey string `json:"ratingKey"`
+					Title     string `json:"title"`
+				} `json:"Metadata"`
+			} `json:"MediaContainer"`
+		}
+		if err := c.getJSON(ctx, reqURL, "albums", &resp); err != nil {
+			return "", err
+		}
+		for _, m := range resp.MediaContainer.Metadata {
+			if strings.EqualFold(m.Title, title) && m.RatingKey != "" {
+				return m.RatingKey, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// GetAlbumTracks returns an album's tracks in order, with their media parts.
+func (c *Client) GetAlbumTracks(ctx context.Context, albumKey string) (_ []Track, err error) {
+	defer func() { err = c.wrapErr("GetAlbumTracks", err) }()
+
+	url := fmt.Sprintf("%s/library/metadata/%s/children?X-Plex-Token=%s", c.ServerURL(), albumKey, c.token)
+
+	var resp tracksResponse
+	if err := c.getJSON(ctx, url, "album tracks", &resp); err != nil {
+		return nil, err
+	}
+	return resp.tracks(), nil
+}
+
+// PartStreamURL returns the direct-play URL of a media part, as
+// GetStreamURL does for a metadata key.
+func (c *Client) PartStreamURL(partKey string) string {
+	return fmt.Sprintf("%s%s?download=1&X-Plex-Token=%s", c.ServerURL(), partKey, c.token)
+}
+
+// getJSON performs an authenticated GET against the server and decodes the
+// JSON response into v. what names the resource in error messages.
+func (c *Client) getJSON(ctx context.Context, url, what string, v any) error {
+	return c.doJSON(ctx, http.MethodGet, url, what, v)
+}
+
+// doJSON sends an authenticated request with the given method and decodes
+// the JSON response into v, unless v is nil.
+func (c *Client) doJSON(ctx context.Context, method, url, what string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		if resp.StatusCode == http.StatusUnauthorized {
+			return newStatusError(resp.StatusCode, "authentication failed: invalid or expired token (status %d)", resp.StatusCode)
+		}
+		if resp.StatusCode == http.StatusNotFound {
+			return newStatusError(resp.StatusCode, "%s not found (status %d)", what, resp.StatusCode)
+		}
+		return newStatusError(resp.StatusCode, "unexpected status code %d from Plex server", resp.StatusCode)
+	}
+
+	if v == nil {
+		return nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		apiLogger.Printf("warning: failed to parse %s response, API format may have changed: %v", what, err)
+		return fmt.Errorf("failed to parse %s: %w", what, err)
+	}
+	return nil
+}
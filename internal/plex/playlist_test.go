@@ -0,0 +1,121 @@
+package plex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestGetPlaylists(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/playlists":
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"MediaContainer": map[string]any{"Metadata": []map[string]any{
+					{"ratingKey": "101", "title": "Road Trip", "playlistType": "audio", "leafCount": 2},
+					{"title": "Broken"},
+				}},
+			})
+		case "/playlists/101/items":
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"MediaContainer": map[string]any{"Metadata": []map[string]any{
+					{"key": "/library/metadata/7", "type": "track", "title": "Song", "grandparentTitle": "Band", "duration": 200000,
+						"Media": []map[string]any{{"Part": []map[string]any{{"key": "/library/parts/70/1/file.flac"}}}}},
+					{"key": "/library/metadata/3", "type": "movie", "title": "Film"},
+				}},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	c := testPlexClient(ts.URL)
+	playlists, err := c.GetPlaylists(context.Background())
+	if err != nil {
+		t.Fatalf("GetPlaylists: %v", err)
+	}
+	if len(playlists) != 1 {
+		t.Fatalf("got %d playlists, want 1 (entries without a ratingKey are skipped)", len(playlists))
+	}
+	if p := playlists[0]; p.Key != "101" || p.Title != "Road Trip" || p.Type != "audio" || p.Count != 2 {
+		t.Errorf("unexpected playlist %+v", p)
+	}
+
+	tracks, err := c.GetPlaylistTracks(context.Background(), "101")
+	if err != nil {
+		t.Fatalf("GetPlaylistTracks: %v", err)
+	}
+	want := []Track{
+		{Key: "/library/metadata/7", Title: "Band - Song", Duration: 200000, PartKey: "/library/parts/70/1/file.flac"},
+		{Key: "/library/metadata/3", Title: "Film"},
+	}
+	if !slices.Equal(tracks, want) {
+		t.Errorf("tracks = %+v, want %+v (playlist order must be kept)", tracks, want)
+	}
+	if got := c.PartStreamURL(want[0].PartKey); got != ts.URL+"/library/parts/70/1/file.flac?download=1&X-Plex-Token=tok" {
+		t.Errorf("PartStreamURL = %q", got)
+	}
+
+	if _, err := c.GetPlaylistTracks(context.Background(), "999"); err == nil {
+		t.Error("missing playlist should return an error")
+	}
+}
+
+func TestFindAlbum(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/library/sections":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"MediaContainer": map[string]any{"Directory": []map[string]any{
+					{"key": "1", "title": "Movies", "type": "movie"},
+					{"key": "2", "title": "Music", "type": "artist"},
+				}},
+			})
+		case "/library/sections/2/all":
+			if r.URL.Query().Get("type") != "9" {
+				http.NotFound(w, r)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"MediaContainer": map[string]any{"Metadata": []map[string]any{
+					{"ratingKey": "40", "title": "Blue Train (Live)"},
+					{"ratingKey": "41", "title": "Blue Train"},
+				}},
+			})
+		case "/library/metadata/41/children":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"MediaContainer": map[string]any{"Metadata": []map[string]any{
+					{"key": "/library/metadata/42", "type": "track", "title": "Blue Train", "grandparentTitle": "Various Artists", "originalTitle": "John Coltrane",
+						"Media": []map[string]any{{"Part": []map[string]any{{"key": "/library/parts/42/1/file.mp3"}}}}},
+				}},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	c := testPlexClient(ts.URL)
+	key, err := c.FindAlbum(context.Background(), "blue train")
+	if err != nil || key != "41" {
+		t.Fatalf("FindAlbum = %q, %v; want the exact title match 41", key, err)
+	}
+	if key, err := c.FindAlbum(context.Background(), "Giant Steps"); err != nil || key != "" {
+		t.Errorf("FindAlbum(missing) = %q, %v", key, err)
+	}
+
+	tracks, err := c.GetAlbumTracks(context.Background(), key)
+	if err != nil {
+		t.Fatalf("GetAlbumTracks: %v", err)
+	}
+	if len(tracks) != 1 || tracks[0].Title != "John Coltrane - Blue Train" || tracks[0].PartKey != "/library/parts/42/1/file.mp3" {
+		t.Errorf("tracks = %+v, want the track artist and its part", tracks)
+	}
+}
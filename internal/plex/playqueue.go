@@ -0,0 +1,94 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// PlayQueue is a server-side play queue: the list a Plex player works
+// through, which other Plex apps show as what's playing and up next.
+type PlayQueue struct {
+	ID int
+	// ItemIDs are the playQueueItemIDs of the queued items, in the order
+	// they were queued.
+	ItemIDs []int
+}
+
+// Item returns where the index'th queued item sits in the queue, for
+// UpdateQueuedTimeline, and false if index is out of range.
+func (q *PlayQueue) Item(index int) (QueueItem, bool) {
+	if q == nil || index < 0 || index >= len(q.ItemIDs) {
+		return QueueItem{}, false
+	}
+	return QueueItem{QueueID: q.ID, ItemID: q.ItemIDs[index]}, true
+}
+
+// QueueItem identifies one entry of a play queue.
+type QueueItem struct {
+	QueueID int
+	ItemID  int
+}
+
+type identityResponse struct {
+	MediaContainer struct {
+		MachineIdentifier string `json:"machineIdentifier"`
+	} `json:"MediaContainer"`
+}
+
+type playQueueResponse struct {
+	MediaContainer struct {
+		PlayQueueID int `json:"playQueueID"`
+		Metadata    []struct {
+			PlayQueueItemID int `json:"playQueueItemID"`
+		} `json:"Metadata"`
+	} `json:"MediaContainer"`
+}
+
+// machineIdentifier returns the server's unique ID, which play queues and
+// remote players use to name it.
+func (c *Client) machineIdentifier(ctx context.Context) (string, error) {
+	var id identityResponse
+	if err := c.getJSON(ctx, fmt.Sprintf("%s/identity?X-Plex-Token=%s", c.ServerURL(), c.token), "server identity", &id); err != nil {
+		return "", err
+	}
+	if id.MediaContainer.MachineIdentifier == "" {
+		return "", fmt.Errorf("server did not report its machine identifier")
+	}
+	return id.MediaContainer.MachineIdentifier, nil
+}
+
+// CreatePlayQueue creates a video play queue holding the items with the
+// given rating keys, in order, as a Plex app does when it starts playing.
+func (c *Client) CreatePlayQueue(ctx context.Context, ratingKeys []string) (_ *PlayQueue, err error) {
+	defer func() { err = c.wrapErr("CreatePlayQueue", err) }()
+
+	if len(ratingKeys) == 0 {
+		return nil, fmt.Errorf("no items to queue")
+	}
+
+	machineID, err := c.machineIdentifier(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	uri := fmt.Sprintf("server://%s/com.plexapp.plugins.library/library/metadata/%s", machineID, strings.Join(ratingKeys, ","))
+	u := fmt.Sprintf("%s/playQueues?type=video&uri=%s&continuous=0&repeat=0&shuffle=0&X-Plex-Token=%s",
+		c.ServerURL(), url.QueryEscape(uri), c.token)
+
+	var resp playQueueResponse
+	if err := c.doJSON(ctx, http.MethodPost, u, "play queue", &resp); err != nil {
+		return nil, err
+	}
+
+	q := &PlayQueue{ID: resp.MediaContainer.PlayQueueID}
+	for _, m := range resp.MediaContainer.Metadata {
+		q.ItemIDs = append(q.ItemIDs, m.PlayQueueItemID)
+	}
+	if len(q.ItemIDs) != len(ratingKeys) {
+		return nil, fmt.Errorf("play queue holds %d items, want %d", len(q.ItemIDs), len(ratingKeys))
+	}
+	return q, nil
+}
@@ -0,0 +1,71 @@
+package plex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCreatePlayQueue(t *testing.T) {
+	var gotURI, gotTimeline string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/identity":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"MediaContainer": map[string]any{"machineIdentifier": "abc123"},
+			})
+		case r.URL.Path == "/playQueues" && r.Method == http.MethodPost:
+			gotURI = r.URL.Query().Get("uri")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"MediaContainer": map[string]any{
+					"playQueueID": 42,
+					"Metadata": []map[string]any{
+						{"ratingKey": "7", "playQueueItemID": 501},
+						{"ratingKey": "3", "playQueueItemID": 502},
+					},
+				},
+			})
+		case r.URL.Path == "/:/timeline":
+			gotTimeline = r.URL.RawQuery
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	c := testPlexClient(ts.URL)
+	q, err := c.CreatePlayQueue(context.Background(), []string{"7", "3"})
+	if err != nil {
+		t.Fatalf("CreatePlayQueue: %v", err)
+	}
+	if want := "server://abc123/com.plexapp.plugins.library/library/metadata/7,3"; gotURI != want {
+		t.Errorf("uri = %q, want %q", gotURI, want)
+	}
+	if q.ID != 42 || !slices.Equal(q.ItemIDs, []int{501, 502}) {
+		t.Errorf("unexpected queue %+v", q)
+	}
+
+	item, ok := q.Item(1)
+	if !ok || item != (QueueItem{QueueID: 42, ItemID: 502}) {
+		t.Fatalf("Item(1) = %+v, %v", item, ok)
+	}
+	if _, ok := q.Item(2); ok {
+		t.Error("Item past the end of the queue should report false")
+	}
+
+	if err := c.UpdateQueuedTimeline("3", item, "playing", 1000, 60000); err != nil {
+		t.Fatalf("UpdateQueuedTimeline: %v", err)
+	}
+	if want := "containerKey=/playQueues/42&playQueueItemID=502"; !strings.Contains(gotTimeline, want) {
+		t.Errorf("timeline query %q lacks %q", gotTimeline, want)
+	}
+
+	if _, err := c.CreatePlayQueue(context.Background(), nil); err == nil {
+		t.Error("an empty queue should return an error")
+	}
+}
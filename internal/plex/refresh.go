@@ -0,0 +1,58 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// ScanLibrary asks the server to scan a library section for new, changed and
+// removed files. The scan runs in the background; this returns once it has
+// been queued.
+func (c *Client) ScanLibrary(ctx context.Context, sectionKey string) (err error) {
+	defer func() { err = c.wrapErr("ScanLibrary", err) }()
+
+	u := fmt.Sprintf("%s/library/sections/%s/refresh?X-Plex-Token=%s", c.ServerURL(), sectionKey, c.token)
+	if err := c.getJSON(ctx, u, "library", nil); err != nil {
+		return fmt.Errorf("failed to scan library: %w", err)
+	}
+	return nil
+}
+
+// RefreshItem asks the server to refresh an item's metadata from its agents.
+// For a show this covers its seasons and episodes as well.
+func (c *Client) RefreshItem(ctx context.Context, ratingKey string) (err error) {
+	defer func() { err = c.wrapErr("RefreshItem", err) }()
+
+	if ratingKey == "" {
+		return fmt.Errorf("ratingKey cannot be empty")
+	}
+	u := fmt.Sprintf("%s/library/metadata/%s/refresh?X-Plex-Token=%s", c.ServerURL(), ratingKey, c.token)
+	if err := c.doJSON(ctx, http.MethodPut, u, "item", nil); err != nil {
+		return fmt.Errorf("failed to refresh metadata: %w", err)
+	}
+	return nil
+}
+
+type showKeyResponse struct {
+	MediaContainer struct {
+		Metadata []struct {
+			GrandparentRatingKey string `json:"grandparentRatingKey"`
+		} `json:"Metadata"`
+	} `json:"MediaContainer"`
+}
+
+// ShowRatingKey returns the rating key of the show an episode belongs to.
+func (c *Client) ShowRatingKey(ctx context.Context, episodeKey string) (_ string, err error) {
+	defer func() { err = c.wrapErr("ShowRatingKey", err) }()
+
+	var resp showKeyResponse
+	u := fmt.Sprintf("%s/library/metadata/%s?X-Plex-Token=%s", c.ServerURL(), episodeKey, c.token)
+	if err := c.getJSON(ctx, u, "episode", &resp); err != nil {
+		return "", err
+	}
+	if len(resp.MediaContainer.Metadata) == 0 || resp.MediaContainer.Metadata[0].GrandparentRatingKey == "" {
+		return "", fmt.Errorf("episode %s has no show", episodeKey)
+	}
+	return resp.MediaContainer.Metadata[0].GrandparentRatingKey, nil
+}
@@ -0,0 +1,53 @@
+package plex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestScanAndRefresh(t *testing.T) {
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/library/sections/2/refresh", "/library/metadata/100/refresh":
+		case "/library/metadata/555":
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{"MediaContainer": map[string]any{
+				"Metadata": []map[string]any{{"ratingKey": "555", "grandparentRatingKey": "100"}},
+			}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+	c := testPlexClient(ts.URL)
+	ctx := context.Background()
+
+	if err := c.ScanLibrary(ctx, "2"); err != nil {
+		t.Fatalf("ScanLibrary: %v", err)
+	}
+	show, err := c.ShowRatingKey(ctx, "555")
+	if err != nil || show != "100" {
+		t.Fatalf("ShowRatingKey = %q, %v", show, err)
+	}
+	if err := c.RefreshItem(ctx, show); err != nil {
+		t.Fatalf("RefreshItem: %v", err)
+	}
+	if err := c.RefreshItem(ctx, "999"); err == nil {
+		t.Error("refreshing a missing item should fail")
+	}
+
+	want := []string{"GET /library/sections/2/refresh", "GET /library/metadata/555", "PUT /library/metadata/100/refresh", "PUT /library/metadata/999/refresh"}
+	if len(requests) != len(want) {
+		t.Fatalf("requests = %v, want %v", requests, want)
+	}
+	for i := range want {
+		if requests[i] != want[i] {
+			t.Errorf("request %d = %q, want %q", i, requests[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// remoteTimeout bounds each attempt to reach a remote player, so a TV
+// that's switched off doesn't stall the command for long.
+const remoteTimeout = 5 * time.Second
+
+// RemotePlayer is a Plex app signed in to the account that can be
+// controlled through the companion API: a TV, a Shield, a phone.
+type RemotePlayer struct {
+	Name     string
+	Product  string
+	Platform string
+	// ClientID is the player's client identifier, which commands address.
+	ClientID string
+	// URIs are the addresses the player published, local ones first and
+	// relays last.
+	URIs []string
+	// accessToken is the token the player accepts commands with, when
+	// plex.tv hands one out.
+	accessToken string
+}
+
+type resource struct {
+	Name             string `json:"name"`
+	Product          string `json:"product"`
+	Platform         string `json:"platform"`
+	ClientIdentifier string `json:"clientIdentifier"`
+	Provides         string `json:"provides"`
+	AccessToken      string `json:"accessToken"`
+	Connections      []struct {
+		URI   string `json:"uri"`
+		Local bool   `json:"local"`
+		Relay bool   `json:"relay"`
+	} `json:"connections"`
+}
+
+// GetRemotePlayers lists the Plex players on the account, as plex.tv
+// knows them, sorted by name.
+func GetRemotePlayers(ctx context.Context, accountToken string) (_ []RemotePlayer, err error) {
+	defer func() { err = plexError("GetRemotePlayers", plexTVURL, err) }()
+
+	var resources []resource
+	if err := plexTVRequest(ctx, http.MethodGet, "/api/v2/resources?includeHttps=1&includeRelay=1", accountToken, &resources); err != nil {
+		return nil, fmt.Errorf("failed to list devices: %w", err)
+	}
+
+	var players []RemotePlayer
+	for _, r := range resources {
+		if !strings.Contains(","+r.Provides+",", ",player,") {
+			continue
+		}
+		conns := r.Connections
+		sort.SliceStable(conns, func(i, j int) bool {
+			if conns[i].Relay != conns[j].Relay {
+				return !conns[i].Relay
+			}
+			return conns[i].Local && !conns[j].Local
+		})
+		p := RemotePlayer{
+			Name:        r.Name,
+			Product:     r.Product,
+			Platform:    r.Platform,
+			ClientID:    r.ClientIdentifier,
+			accessToken: r.AccessToken,
+		}
+		for _, c := range conns {
+			p.URIs = append(p.URIs, c.URI)
+		}
+		players = append(players, p)
+	}
+	sort.SliceStable(players, func(i, j int) bool {
+		return strings.ToLower(players[i].Name) < strings.ToLower(players[j].Name)
+	})
+	return players, nil
+}
+
+// Remote sends playback commands to a RemotePlayer.
+type Remote struct {
+	player RemotePlayer
+	token  string
+
+	mu        sync.Mutex
+	commandID int
+}
+
+// NewRemote returns a Remote for p, sending accountToken with commands
+// unless plex.tv gave the player a token of its own.
+func NewRemote(p RemotePlayer, accountToken string) *Remote {
+	token := p.accessToken
+	if token == "" {
+		token = accountToken
+	}
+	return &Remote{player: p, token: token}
+}
+
+// Play resumes playback.
+func (r *Remote) Play(ctx context.Context) error {
+	return r.command(ctx, "Play", "/player/playback/play", nil)
+}
+
+// Pause pauses playback.
+func (r *Remote) Pause(ctx context.Context) error {
+	return r.command(ctx, "Pause", "/player/playback/pause", nil)
+}
+
+// Stop stops playback.
+func (r *Remote) Stop(ctx context.Context) error {
+	return r.command(ctx, "Stop", "/player/playback/stop", nil)
+}
+
+// SeekTo moves playback to offsetMs milliseconds in.
+func (r *Remote) SeekTo(ctx context.Context, offsetMs int) error {
+	return r.command(ctx, "SeekTo", "/player/playback/seekTo", url.Values{"offset": {strconv.Itoa(offsetMs)}})
+}
+
+// PlayMedia has the player play the item with ratingKey from the server c
+// talks to, starting offsetMs milliseconds in. The item is put in a play
+// queue first, as Plex apps do when they cast. The player fetches the
+// media itself, so it must be able to reach the server at c's address.
+func (r *Remote) PlayMedia(ctx context.Context, c *Client, ratingKey string, offsetMs int) error {
+	q, err := c.CreatePlayQueue(ctx, []string{ratingKey})
+	if err != nil {
+		return err
+	}
+	machineID, err := c.machineIdentifier(ctx)
+	if err != nil {
+		return c.wrapErr("PlayMedia", err)
+	}
+	server, err := url.Parse(c.ServerURL())
+	if err != nil {
+		return c.wrapErr("PlayMedia", fmt.Errorf("invalid server URL: %w", err))
+	}
+	port := server.Port()
+	if port == "" {
+		port = "32400"
+		if server.Scheme == "https" {
+			port = "443"
+		}
+	}
+
+	return r.command(ctx, "PlayMedia", "/player/playback/playMedia", url.Values{
+		"key":               {"/library/metadata/" + ratingKey},
+		"offset":            {strconv.Itoa(offsetMs)},
+		"machineIdentifier": {machineID},
+		"protocol":          {server.Scheme},
+		"address":           {server.Hostname()},
+		"port":              {port},
+		"token":             {c.token},
+		"containerKey":      {fmt.Sprintf("/playQueues/%d?own=1&window=200", q.ID)},
+	})
+}
+
+// command sends a companion API command to the player, trying its
+// addresses in turn until one answers.
+func (r *Remote) command(ctx context.Context, op, path string, params url.Values) (err error) {
+	defer func() { err = plexError("Remote"+op, r.player.Name, err) }()
+
+	if len(r.player.URIs) == 0 {
+		return fmt.Errorf("%s has published no address; is it on and signed in?", r.player.Name)
+	}
+
+	r.mu.Lock()
+	r.commandID++
+	id := r.commandID
+	r.mu.Unlock()
+
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("type", "video")
+	params.Set("commandID", strconv.Itoa(id))
+	query := params.Encode()
+
+	var lastErr error
+	for _, uri := range r.player.URIs {
+		lastErr = r.send(ctx, strings.TrimSuffix(uri, "/")+path+"?"+query)
+		if lastErr == nil || !isUnreachable(lastErr) {
+			return lastErr
+		}
+	}
+	return fmt.Errorf("%s could not be reached: %w", r.player.Name, lastErr)
+}
+
+// send makes one command request.
+func (r *Remote) send(ctx context.Context, u string) error {
+	ctx, cancel := context.WithTimeout(ctx, remoteTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Plex-Target-Client-Identifier", r.player.ClientID)
+	req.Header.Set("X-Plex-Token", r.token)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newStatusError(resp.StatusCode, "%s refused the command with status %d", r.player.Name, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package plex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestGetRemotePlayers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/resources" || r.Header.Get("X-Plex-Token") != "account" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`[
+			{"name":"Server","provides":"server","clientIdentifier":"srv"},
+			{"name":"shield","product":"Plex for Android (TV)","provides":"client,player,pubsub-player","clientIdentifier":"sh",
+			 "connections":[{"uri":"https://relay:443","relay":true},{"uri":"http://1.2.3.4:32500"},{"uri":"http://192.168.1.9:32500","local":true}]},
+			{"name":"Living Room","product":"Plex for LG","provides":"player","clientIdentifier":"lg","accessToken":"lg-token"}]`))
+	}))
+	defer ts.Close()
+	old := plexTVURL
+	plexTVURL = ts.URL
+	defer func() { plexTVURL = old }()
+
+	players, err := GetRemotePlayers(context.Background(), "account")
+	if err != nil {
+		t.Fatalf("GetRemotePlayers: %v", err)
+	}
+	if len(players) != 2 || players[0].Name != "Living Room" || players[1].Name != "shield" {
+		t.Fatalf("players = %+v, want Living Room and shield (servers are skipped)", players)
+	}
+	want := []string{"http://192.168.1.9:32500", "http://1.2.3.4:32500", "https://relay:443"}
+	if got := players[1].URIs; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("URIs = %v, want %v (local first, relays last)", got, want)
+	}
+	if NewRemote(players[0], "account").token != "lg-token" {
+		t.Error("a player's own access token should be preferred")
+	}
+}
+
+func TestRemoteCommands(t *testing.T) {
+	var got []*http.Request
+	player := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r)
+	}))
+	defer player.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/identity":
+			_ = json.NewEncoder(w).Encode(map[string]any{"MediaContainer": map[string]any{"machineIdentifier": "mid"}})
+		case "/playQueues":
+			_ = json.NewEncoder(w).Encode(map[string]any{"MediaContainer": map[string]any{
+				"playQueueID": 9, "Metadata": []map[string]any{{"playQueueItemID": 1}},
+			}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	r := NewRemote(RemotePlayer{Name: "tv", ClientID: "tv-id", URIs: []string{player.URL}}, "account")
+	ctx := context.Background()
+	if err := r.Pause(ctx); err != nil {
+		t.Fatalf("Pause: %v", err)
+	}
+	if err := r.SeekTo(ctx, 90000); err != nil {
+		t.Fatalf("SeekTo: %v", err)
+	}
+	if err := r.PlayMedia(ctx, testPlexClient(server.URL), "42", 5000); err != nil {
+		t.Fatalf("PlayMedia: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("player got %d commands, want 3", len(got))
+	}
+
+	pause := got[0]
+	if pause.URL.Path != "/player/playback/pause" || pause.Header.Get("X-Plex-Target-Client-Identifier") != "tv-id" ||
+		pause.Header.Get("X-Plex-Token") != "account" {
+		t.Errorf("pause request = %s %v", pause.URL, pause.Header)
+	}
+	if q := got[1].URL.Query(); q.Get("offset") != "90000" || q.Get("commandID") != "2" {
+		t.Errorf("seek query = %v, want offset 90000 and commandID 2", q)
+	}
+
+	serverURL, _ := url.Parse(server.URL)
+	q := got[2].URL.Query()
+	for key, want := range map[string]string{
+		"key":               "/library/metadata/42",
+		"offset":            "5000",
+		"machineIdentifier": "mid",
+		"address":           serverURL.Hostname(),
+		"port":              serverURL.Port(),
+		"protocol":          "http",
+		"token":             "tok",
+		"containerKey":      "/playQueues/9?own=1&window=200",
+	} {
+		if q.Get(key) != want {
+			t.Errorf("playMedia %s = %q, want %q", key, q.Get(key), want)
+		}
+	}
+
+	if err := NewRemote(RemotePlayer{Name: "off"}, "account").Play(ctx); err == nil {
+		t.Error("a player without addresses should return an error")
+	}
+}
@@ -255,7 +255,61 @@ func TestGetMediaFetchesSectionsInParallelPreservingOrder(t *testing.T) {
 			if want := fmt.Sprintf("Lib%s Movie %d", key, i); got[idx].Title != want {
 				t.Fatalf("item %d: got %q, want %q (results must stay in library order)", idx, got[idx].Title, want)
 			}
+			if got[idx].Library != "Library "+key {
+				t.Fatalf("item %d: library = %q, want %q", idx, got[idx].Library, "Library "+key)
+			}
 			idx++
 		}
 	}
 }
+
+func TestGetMediaFromSectionRecordsPartSize(t *testing.T) {
+	items := []map[string]any{{
+		"key":   "/library/metadata/1",
+		"guid":  "plex://movie/5d776b59ad5437001f79c6f8",
+		"Guid":  []map[string]any{{"id": "imdb://tt0113277"}, {"id": "tmdb://949"}},
+		"title": "Sized",
+		"Media": []map[string]any{{
+			"Part": []map[string]any{{"file": "/mnt/media/sized.mkv", "size": 4509715660}},
+		}},
+	}}
+	ts := newSectionServer(items, nil)
+	defer ts.Close()
+
+	got, err := testPlexClient(ts.URL).getMediaFromSection(context.Background(), "1", "movie", 0, nil)
+	if err != nil {
+		t.Fatalf("getMediaFromSection: %v", err)
+	}
+	if len(got) != 1 || got[0].Size != 4509715660 {
+		t.Fatalf("expected part size 4509715660, got %+v", got)
+	}
+	if got[0].GUID != "plex://movie/5d776b59ad5437001f79c6f8" {
+		t.Errorf("GUID = %q, want the listing's guid", got[0].GUID)
+	}
+	if got[0].IMDbID != "tt0113277" || got[0].TMDBID != "949" {
+		t.Errorf("external IDs = %q, %q; want the listing's Guid entries", got[0].IMDbID, got[0].TMDBID)
+	}
+}
+
+func TestDecodeSectionPage(t *testing.T) {
+	// totalSize after Metadata, plus nested fields the decoder has to skip.
+	body := `{"MediaContainer":{"size":2,"Directory":[{"a":[1,{"b":null}]}],` +
+		`"Metadata":[{"key":"/k/1","title":"One"},{"key":"/k/2","title":"Two","extra":{"x":[]}}],` +
+		`"totalSize":40},"other":"ignored"}`
+	items, total, err := decodeSectionPage(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("decodeSectionPage: %v", err)
+	}
+	if total != 40 || len(items) != 2 || items[0].Title != "One" || items[1].Key != "/k/2" {
+		t.Errorf("got %d items %+v, total %d", len(items), items, total)
+	}
+
+	items, total, err = decodeSectionPage(strings.NewReader(`{"MediaContainer":{"totalSize":0,"Metadata":null}}`))
+	if err != nil || len(items) != 0 || total != 0 {
+		t.Errorf("null Metadata: %d items, total %d, err %v", len(items), total, err)
+	}
+
+	if _, _, err := decodeSectionPage(strings.NewReader(body[:len(body)/2])); err == nil {
+		t.Error("want an error for a truncated response")
+	}
+}
@@ -0,0 +1,112 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Session is a stream the server is playing to someone right now.
+type Session struct {
+	ID         string // passed to StopSession
+	User       string
+	Title      string // e.g. "Heat (1995)" or "Show - S01E02 - Title"
+	Player     string // device name, or the app when unnamed
+	State      string // "playing", "paused" or "buffering"
+	ViewOffset int    // milliseconds
+	Duration   int    // milliseconds
+	Decision   string // "direct play", "direct stream" or "transcode"
+	Bandwidth  int    // kbps
+	Local      bool   // on the server's LAN
+}
+
+// Progress returns how far into the item the session is, 0-100.
+func (s Session) Progress() int {
+	if s.Duration <= 0 {
+		return 0
+	}
+	return min(s.ViewOffset*100/s.Duration, 100)
+}
+
+type sessionsResponse struct {
+	MediaContainer struct {
+		Metadata []struct {
+			Type             string `json:"type"`
+			Title            string `json:"title"`
+			GrandparentTitle string `json:"grandparentTitle"`
+			ParentIndex      int64  `json:"parentIndex"`
+			Index            int64  `json:"index"`
+			Year             int    `json:"year"`
+			Duration         int    `json:"duration"`
+			ViewOffset       int    `json:"viewOffset"`
+			User             struct {
+				Title string `json:"title"`
+			} `json:"User"`
+			Player struct {
+				Title   string `json:"title"`
+				Product string `json:"product"`
+				State   string `json:"state"`
+			} `json:"Player"`
+			Session struct {
+				ID        string `json:"id"`
+				Bandwidth int    `json:"bandwidth"`
+				Location  string `json:"location"`
+			} `json:"Session"`
+			TranscodeSession *struct {
+				VideoDecision string `json:"videoDecision"`
+				AudioDecision string `json:"audioDecision"`
+			} `json:"TranscodeSession"`
+		} `json:"Metadata"`
+	} `json:"MediaContainer"`
+}
+
+// GetSessions returns the server's active playback sessions. Only the
+// server owner sees other users' sessions.
+func (c *Client) GetSessions(ctx context.Context) (_ []Session, err error) {
+	defer func() { err = c.wrapErr("GetSessions", err) }()
+
+	var resp sessionsResponse
+	if err := c.getJSON(ctx, fmt.Sprintf("%s/status/sessions?X-Plex-Token=%s", c.ServerURL(), c.token), "sessions", &resp); err != nil {
+		return nil, err
+	}
+	sessions := make([]Session, 0, len(resp.MediaContainer.Metadata))
+	for _, m := range resp.MediaContainer.Metadata {
+		item := MediaItem{Type: m.Type, Title: m.Title, Year: m.Year, ParentTitle: m.GrandparentTitle, ParentIndex: m.ParentIndex, Index: m.Index}
+		s := Session{
+			ID:         m.Session.ID,
+			User:       m.User.Title,
+			Title:      item.FormatMediaTitle(),
+			Player:     m.Player.Title,
+			State:      m.Player.State,
+			ViewOffset: m.ViewOffset,
+			Duration:   m.Duration,
+			Decision:   "direct play",
+			Bandwidth:  m.Session.Bandwidth,
+			Local:      m.Session.Location == "lan",
+		}
+		if s.Player == "" {
+			s.Player = m.Player.Product
+		}
+		if t := m.TranscodeSession; t != nil {
+			s.Decision = "direct stream"
+			if t.VideoDecision == "transcode" || t.AudioDecision == "transcode" {
+				s.Decision = "transcode"
+			}
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, nil
+}
+
+// StopSession ends a playback session, showing reason to the viewer. Only
+// the server owner may stop sessions.
+func (c *Client) StopSession(ctx context.Context, id, reason string) (err error) {
+	defer func() { err = c.wrapErr("StopSession", err) }()
+
+	u := fmt.Sprintf("%s/status/sessions/terminate?sessionId=%s&reason=%s&X-Plex-Token=%s",
+		c.ServerURL(), url.QueryEscape(id), url.QueryEscape(reason), c.token)
+	if err := c.getJSON(ctx, u, "session", nil); err != nil {
+		return fmt.Errorf("failed to stop session: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package plex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetSessions(t *testing.T) {
+	var stopped, reason string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/status/sessions":
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{"MediaContainer": map[string]any{"Metadata": []map[string]any{
+				{
+					"type": "episode", "title": "Pilot", "grandparentTitle": "Severance", "parentIndex": 1, "index": 1,
+					"duration": 3000000, "viewOffset": 750000,
+					"User":             map[string]any{"title": "alice"},
+					"Player":           map[string]any{"title": "", "product": "Plex for Roku", "state": "playing"},
+					"Session":          map[string]any{"id": "s1", "bandwidth": 12000, "location": "wan"},
+					"TranscodeSession": map[string]any{"videoDecision": "transcode", "audioDecision": "copy"},
+				},
+				{
+					"type": "movie", "title": "Heat", "year": 1995, "duration": 10000000, "viewOffset": 0,
+					"User":    map[string]any{"title": "bob"},
+					"Player":  map[string]any{"title": "Living Room", "state": "paused"},
+					"Session": map[string]any{"id": "s2", "location": "lan"},
+				},
+			}}})
+		case "/status/sessions/terminate":
+			stopped, reason = r.URL.Query().Get("sessionId"), r.URL.Query().Get("reason")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	c := testPlexClient(ts.URL)
+	sessions, err := c.GetSessions(context.Background())
+	if err != nil {
+		t.Fatalf("GetSessions: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("got %d sessions, want 2", len(sessions))
+	}
+	s := sessions[0]
+	if s.ID != "s1" || s.User != "alice" || s.Title != "Severance - S01E01 - Pilot" || s.Player != "Plex for Roku" || s.Decision != "transcode" || s.Local || s.Progress() != 25 {
+		t.Errorf("unexpected session %+v", s)
+	}
+	if s := sessions[1]; s.Decision != "direct play" || !s.Local || s.State != "paused" || s.Title != "Heat (1995)" {
+		t.Errorf("unexpected session %+v", s)
+	}
+
+	if err := c.StopSession(context.Background(), "s1", "Server maintenance"); err != nil {
+		t.Fatalf("StopSession: %v", err)
+	}
+	if stopped != "s1" || reason != "Server maintenance" {
+		t.Errorf("terminated %q with %q", stopped, reason)
+	}
+}
@@ -0,0 +1,99 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ServerInfo identifies a server and its current load.
+type ServerInfo struct {
+	Name              string
+	Version           string
+	Platform          string // e.g. "Linux"
+	PlatformVersion   string
+	TranscodeSessions int // video transcodes running now
+}
+
+// Activity is a background task the server is running, such as a library
+// scan or a scheduled maintenance task.
+type Activity struct {
+	Title    string
+	Subtitle string
+	Progress int // 0-100
+}
+
+type serverInfoResponse struct {
+	MediaContainer struct {
+		FriendlyName                  string `json:"friendlyName"`
+		Version                       string `json:"version"`
+		Platform                      string `json:"platform"`
+		PlatformVersion               string `json:"platformVersion"`
+		TranscoderActiveVideoSessions int    `json:"transcoderActiveVideoSessions"`
+	} `json:"MediaContainer"`
+}
+
+// GetServerInfo returns the server's name, version and transcoder load, and
+// how long the request took, which approximates its latency.
+func (c *Client) GetServerInfo(ctx context.Context) (_ *ServerInfo, _ time.Duration, err error) {
+	defer func() { err = c.wrapErr("GetServerInfo", err) }()
+
+	var resp serverInfoResponse
+	start := time.Now()
+	if err := c.getJSON(ctx, fmt.Sprintf("%s/?X-Plex-Token=%s", c.ServerURL(), c.token), "server info", &resp); err != nil {
+		return nil, 0, err
+	}
+	latency := time.Since(start)
+	mc := resp.MediaContainer
+	return &ServerInfo{
+		Name:              mc.FriendlyName,
+		Version:           mc.Version,
+		Platform:          mc.Platform,
+		PlatformVersion:   mc.PlatformVersion,
+		TranscodeSessions: mc.TranscoderActiveVideoSessions,
+	}, latency, nil
+}
+
+type activitiesResponse struct {
+	MediaContainer struct {
+		Activity []struct {
+			Title    string `json:"title"`
+			Subtitle string `json:"subtitle"`
+			Progress int    `json:"progress"`
+		} `json:"Activity"`
+	} `json:"MediaContainer"`
+}
+
+// GetActivities returns the background tasks the server is running.
+func (c *Client) GetActivities(ctx context.Context) (_ []Activity, err error) {
+	defer func() { err = c.wrapErr("GetActivities", err) }()
+
+	var resp activitiesResponse
+	if err := c.getJSON(ctx, fmt.Sprintf("%s/activities?X-Plex-Token=%s", c.ServerURL(), c.token), "activities", &resp); err != nil {
+		return nil, err
+	}
+	activities := make([]Activity, 0, len(resp.MediaContainer.Activity))
+	for _, a := range resp.MediaContainer.Activity {
+		activities = append(activities, Activity{Title: a.Title, Subtitle: a.Subtitle, Progress: a.Progress})
+	}
+	return activities, nil
+}
+
+type countResponse struct {
+	MediaContainer struct {
+		TotalSize int `json:"totalSize"`
+	} `json:"MediaContainer"`
+}
+
+// CountLibraryItems returns how many top-level items (movies, shows,
+// artists) a library section holds, without listing them.
+func (c *Client) CountLibraryItems(ctx context.Context, sectionKey string) (_ int, err error) {
+	defer func() { err = c.wrapErr("CountLibraryItems", err) }()
+
+	u := fmt.Sprintf("%s/library/sections/%s/all?X-Plex-Container-Start=0&X-Plex-Container-Size=0&X-Plex-Token=%s", c.ServerURL(), sectionKey, c.token)
+	var resp countResponse
+	if err := c.getJSON(ctx, u, "library", &resp); err != nil {
+		return 0, err
+	}
+	return resp.MediaContainer.TotalSize, nil
+}
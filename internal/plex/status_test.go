@@ -0,0 +1,51 @@
+package plex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServerStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		switch r.URL.Path {
+		case "/":
+			body = map[string]any{"friendlyName": "Basement", "version": "1.41.3.9314", "platform": "Linux", "platformVersion": "6.8", "transcoderActiveVideoSessions": 2}
+		case "/activities":
+			body = map[string]any{"Activity": []map[string]any{{"title": "Scanning Movies", "subtitle": "Heat", "progress": 40}}}
+		case "/library/sections/1/all":
+			if r.URL.Query().Get("X-Plex-Container-Size") != "0" {
+				t.Errorf("counting should not list items: %q", r.URL.RawQuery)
+			}
+			body = map[string]any{"size": 0, "totalSize": 812}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"MediaContainer": body})
+	}))
+	defer ts.Close()
+	c := testPlexClient(ts.URL)
+	ctx := context.Background()
+
+	info, latency, err := c.GetServerInfo(ctx)
+	if err != nil {
+		t.Fatalf("GetServerInfo: %v", err)
+	}
+	if info.Name != "Basement" || info.Version != "1.41.3.9314" || info.TranscodeSessions != 2 || latency <= 0 {
+		t.Errorf("unexpected info %+v, latency %v", info, latency)
+	}
+
+	activities, err := c.GetActivities(ctx)
+	if err != nil || len(activities) != 1 || activities[0].Progress != 40 {
+		t.Errorf("GetActivities = %+v, %v", activities, err)
+	}
+
+	if n, err := c.CountLibraryItems(ctx, "1"); err != nil || n != 812 {
+		t.Errorf("CountLibraryItems = %d, %v", n, err)
+	}
+}
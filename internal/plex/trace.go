@@ -0,0 +1,131 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/logging"
+)
+
+var (
+	traceMu     sync.Mutex
+	tracing     bool
+	traceBodies io.Writer
+)
+
+// SetHTTPTrace turns request tracing on or off. While it is on, every request
+// the package makes is logged at info level with its method, URL (token
+// redacted), status, latency and response size. If bodies is non-nil, text
+// response bodies are also appended to it, with tokens redacted. Without
+// tracing, the same lines are logged at debug level.
+func SetHTTPTrace(on bool, bodies io.Writer) {
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	tracing = on
+	traceBodies = bodies
+}
+
+// logRequest logs one attempt at a request, if tracing or verbose logging is
+// on. A response's line is written once its body is closed, so it can report
+// the payload size; the response returned wraps the body to do that.
+func logRequest(req *http.Request, resp *http.Response, err error, start time.Time) *http.Response {
+	traceMu.Lock()
+	level, bodies := slog.LevelInfo, traceBodies
+	if !tracing {
+		level, bodies = slog.LevelDebug, nil
+	}
+	traceMu.Unlock()
+	if !logging.Enabled(level) {
+		return resp
+	}
+
+	args := []any{"method", req.Method, "url", redactURL(req.URL)}
+	if err != nil {
+		args = append(args, "duration", time.Since(start).Round(time.Millisecond), "error", err)
+		logging.Logger().Log(context.Background(), level, "http request", args...)
+		return resp
+	}
+	args = append(args, "status", resp.StatusCode, "latency", time.Since(start).Round(time.Millisecond))
+
+	body := &tracedBody{ReadCloser: resp.Body, level: level, args: args, start: start}
+	if bodies != nil {
+		body.dump = bodies
+		body.header = fmt.Sprintf("=== %s %s -> %d", req.Method, redactURL(req.URL), resp.StatusCode)
+		body.text = isTextContent(resp.Header.Get("Content-Type"))
+	}
+	resp.Body = body
+	return resp
+}
+
+// tracedBody counts the bytes read from a response body and, on Close, logs
+// the request and writes the body to the trace file.
+type tracedBody struct {
+	io.ReadCloser
+	level slog.Level
+	args  []any
+	start time.Time
+	n     int64
+
+	dump   io.Writer
+	header string
+	text   bool
+	buf    strings.Builder
+
+	once sync.Once
+}
+
+func (b *tracedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	if b.dump != nil && b.text {
+		b.buf.Write(p[:n])
+	}
+	return n, err
+}
+
+func (b *tracedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		args := append(b.args, "bytes", b.n, "duration", time.Since(b.start).Round(time.Millisecond))
+		logging.Logger().Log(context.Background(), b.level, "http request", args...)
+		if b.dump == nil {
+			return
+		}
+		body := fmt.Sprintf("(%d bytes of binary data)", b.n)
+		if b.text {
+			body = redactTokens(b.buf.String())
+		}
+		traceMu.Lock()
+		defer traceMu.Unlock()
+		fmt.Fprintf(b.dump, "%s %s (%d bytes)\n%s\n\n", b.header, time.Now().Format(time.RFC3339), b.n, body)
+	})
+	return err
+}
+
+// isTextContent reports whether a response body of this Content-Type is
+// worth dumping: XML, JSON and other text.
+func isTextContent(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "json") || strings.HasSuffix(mediaType, "xml")
+}
+
+// tokenField matches token attributes and fields in Plex's XML and JSON
+// responses, such as the accessToken plex.tv returns for each server.
+var tokenField = regexp.MustCompile(`((?i:accessToken|authToken|authenticationToken|X-Plex-Token)"?\s*[=:]\s*"?)[^"&\s<]+`)
+
+// redactTokens replaces the tokens in a response body, so a trace file is
+// safe to share.
+func redactTokens(body string) string {
+	return tokenField.ReplaceAllString(body, "${1}REDACTED")
+}
@@ -0,0 +1,64 @@
+package plex
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPTraceBodies(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/poster" {
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Write([]byte{0xff, 0xd8, 0xff})
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		io.WriteString(w, `<Device name="nas" accessToken="s3cret"/>`)
+	}))
+	defer ts.Close()
+
+	var bodies bytes.Buffer
+	SetHTTPTrace(true, &bodies)
+	t.Cleanup(func() { SetHTTPTrace(false, nil) })
+
+	for _, path := range []string{"/resources?X-Plex-Token=tok", "/poster"} {
+		resp, err := httpClient.Get(ts.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	got := bodies.String()
+	for _, want := range []string{
+		"=== GET " + ts.URL + "/resources?X-Plex-Token=REDACTED -> 200",
+		`accessToken="REDACTED"`,
+		"=== GET " + ts.URL + "/poster -> 200",
+		"(3 bytes of binary data)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("trace is missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "s3cret") || strings.Contains(got, "tok\n") {
+		t.Errorf("trace leaks a token:\n%s", got)
+	}
+}
+
+func TestRedactTokens(t *testing.T) {
+	for in, want := range map[string]string{
+		`{"authToken":"abc","name":"x"}`:      `{"authToken":"REDACTED","name":"x"}`,
+		`<Server accessToken="abc" name="x">`: `<Server accessToken="REDACTED" name="x">`,
+		`uri?X-Plex-Token=abc&x=1`:            `uri?X-Plex-Token=REDACTED&x=1`,
+		`no secrets here`:                     `no secrets here`,
+	} {
+		if got := redactTokens(in); got != want {
+			t.Errorf("redactTokens(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
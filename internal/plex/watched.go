@@ -0,0 +1,83 @@
+package plex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// MarkWatched marks an item as fully watched on the server, as "Mark as
+// Played" does in Plex Web: its view count goes up and its resume position
+// is cleared.
+func (c *Client) MarkWatched(ctx context.Context, ratingKey string) (err error) {
+	defer func() { err = c.wrapErr("MarkWatched", err) }()
+
+	if ratingKey == "" {
+		return fmt.Errorf("ratingKey cannot be empty")
+	}
+	u := fmt.Sprintf("%s/:/scrobble?identifier=com.plexapp.plugins.library&key=%s&X-Plex-Token=%s", c.ServerURL(), ratingKey, c.token)
+	if err := c.getJSON(ctx, u, "item", nil); err != nil {
+		return fmt.Errorf("failed to mark as watched: %w", err)
+	}
+	return nil
+}
+
+// WatchState is an item's watched state on its server.
+type WatchState struct {
+	ViewCount    int
+	ViewOffset   int
+	LastViewedAt int64
+}
+
+// GetWatchStates returns the watched state of every movie and episode in
+// the indexed libraries, keyed by item key. It pages through the same
+// listings as GetAllMedia, so it costs about as much as a reindex on the
+// server's side but leaves the rest of the cache alone. onProgress, if
+// non-nil, is called after each page with the running item count.
+func (c *Client) GetWatchStates(ctx context.Context, onProgress func(fetched int)) (_ map[string]WatchState, err error) {
+	defer func() { err = c.wrapErr("GetWatchStates", err) }()
+
+	libraries, err := c.GetLibraries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[string]WatchState)
+	for _, lib := range libraries {
+		if !indexed(c.serverName, lib) {
+			continue
+		}
+		base := len(states)
+		report := func(fetched, total int) {
+			if onProgress != nil {
+				onProgress(base + fetched)
+			}
+		}
+
+		listURL := fmt.Sprintf("%s/library/sections/%s/all?X-Plex-Token=%s", c.ServerURL(), lib.Key, c.token)
+		if lib.Type == "show" {
+			listURL = fmt.Sprintf("%s/library/sections/%s/all?type=4&X-Plex-Token=%s", c.ServerURL(), lib.Key, c.token)
+		}
+		metadata, err := c.pageMetadata(ctx, listURL, "section "+lib.Key, 0, report)
+		if err != nil && lib.Type == "show" && errors.Is(err, errPlexServerError) {
+			// As in getMediaFromSection, walk the shows one by one when the
+			// server can't list every episode at once.
+			metadata, err = c.fetchEpisodesPerShow(ctx, lib.Key, 0, report)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get watched state from section %s: %w", lib.Title, err)
+		}
+
+		for _, m := range metadata {
+			if m.Key == "" {
+				continue
+			}
+			states[m.Key] = WatchState{
+				ViewCount:    valueOrZeroInt(m.ViewCount),
+				ViewOffset:   valueOrZeroInt(m.ViewOffset),
+				LastViewedAt: valueOrZeroInt64(m.LastViewedAt),
+			}
+		}
+	}
+	return states, nil
+}
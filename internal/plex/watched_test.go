@@ -0,0 +1,94 @@
+package plex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMarkWatched(t *testing.T) {
+	var scrobbled []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/:/scrobble" || r.URL.Query().Get("identifier") != "com.plexapp.plugins.library" {
+			http.NotFound(w, r)
+			return
+		}
+		if key := r.URL.Query().Get("key"); key != "404" {
+			scrobbled = append(scrobbled, key)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer ts.Close()
+	c := testPlexClient(ts.URL)
+	ctx := context.Background()
+
+	if err := c.MarkWatched(ctx, "555"); err != nil {
+		t.Fatalf("MarkWatched: %v", err)
+	}
+	if len(scrobbled) != 1 || scrobbled[0] != "555" {
+		t.Errorf("scrobbled %v, want [555]", scrobbled)
+	}
+	if err := c.MarkWatched(ctx, "404"); err == nil {
+		t.Error("MarkWatched of a missing item should fail")
+	}
+	if err := c.MarkWatched(ctx, ""); err == nil {
+		t.Error("MarkWatched without a key should fail")
+	}
+}
+
+func TestGetWatchStates(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/library/sections":
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"MediaContainer": map[string]any{"Directory": []map[string]any{
+					{"key": "1", "title": "Movies", "type": "movie"},
+					{"key": "2", "title": "TV", "type": "show"},
+					{"key": "3", "title": "Music", "type": "artist"},
+				}},
+			})
+		case "/library/sections/1/all":
+			writeContainerPage(w, r, []map[string]any{
+				{"key": "/library/metadata/1", "viewCount": 2, "lastViewedAt": 1700000000},
+				{"key": "/library/metadata/2"},
+			})
+		case "/library/sections/2/all":
+			if r.URL.Query().Get("type") != "4" {
+				t.Errorf("show section listed without type=4: %s", r.URL.RawQuery)
+			}
+			writeContainerPage(w, r, []map[string]any{
+				{"key": "/library/metadata/10", "viewOffset": 60000, "lastViewedAt": 1700000100},
+			})
+		default:
+			t.Errorf("unexpected request for %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	var progress int
+	states, err := testPlexClient(ts.URL).GetWatchStates(context.Background(), func(fetched int) { progress = fetched })
+	if err != nil {
+		t.Fatalf("GetWatchStates: %v", err)
+	}
+	want := map[string]WatchState{
+		"/library/metadata/1":  {ViewCount: 2, LastViewedAt: 1700000000},
+		"/library/metadata/2":  {},
+		"/library/metadata/10": {ViewOffset: 60000, LastViewedAt: 1700000100},
+	}
+	if len(states) != len(want) {
+		t.Fatalf("got %d states, want %d: %v", len(states), len(want), states)
+	}
+	for key, w := range want {
+		if states[key] != w {
+			t.Errorf("states[%q] = %+v, want %+v", key, states[key], w)
+		}
+	}
+	if progress != 3 {
+		t.Errorf("last progress = %d, want 3", progress)
+	}
+}
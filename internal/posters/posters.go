@@ -0,0 +1,208 @@
+// Package posters keeps poster images for the terminal UI in a persistent
+// cache under the goplexcli cache directory, so they survive reboots and
+// previews work offline once fetched. The cache is capped at MaxBytes and
+// evicts the least recently used posters first.
+package posters
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/config"
+	"github.com/joshkerr/goplexcli/internal/storage"
+)
+
+// MaxBytes caps the cache. Full-size Plex posters run 100-500 KB, so this
+// holds a few thousand.
+const MaxBytes = int64(1 << 30)
+
+// maxImageBytes rejects responses that can't be a poster.
+const maxImageBytes = int64(12 << 20)
+
+// touchAfter throttles refreshing a hit's mtime, which is what Prune's LRU
+// order goes by.
+const touchAfter = time.Hour
+
+// Dir returns the poster cache directory. It is separate from the GUI's
+// poster cache, which holds resized renditions under its own cap.
+func Dir() (string, error) {
+	dir, err := config.GetCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "thumbs"), nil
+}
+
+// Path returns where the poster at thumbPath on serverURL is cached.
+func Path(serverURL, thumbPath string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(serverURL + "\x00" + thumbPath))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".jpg"), nil
+}
+
+// Cached returns the cached path of a poster and whether it is cached,
+// marking it as recently used.
+func Cached(serverURL, thumbPath string) (string, bool) {
+	path, err := Path(serverURL, thumbPath)
+	if err != nil {
+		return "", false
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Size() == 0 {
+		return path, false
+	}
+	if time.Since(info.ModTime()) > touchAfter {
+		now := time.Now()
+		_ = os.Chtimes(path, now, now)
+	}
+	return path, true
+}
+
+// Fetch returns the local path of a poster, downloading it from the Plex
+// server first if it isn't cached. fetched reports whether it downloaded;
+// callers fetching one poster at a time should Prune after a download.
+func Fetch(ctx context.Context, client *http.Client, serverURL, thumbPath, token string) (path string, fetched bool, err error) {
+	if thumbPath == "" {
+		return "", false, fmt.Errorf("no poster")
+	}
+	return fetch(ctx, client, serverURL, thumbPath, serverURL+thumbPath+"?X-Plex-Token="+url.QueryEscape(token))
+}
+
+// FetchURL is Fetch for a poster hosted outside Plex, such as on TMDB.
+func FetchURL(ctx context.Context, client *http.Client, imageURL string) (path string, fetched bool, err error) {
+	if imageURL == "" {
+		return "", false, fmt.Errorf("no poster")
+	}
+	return fetch(ctx, client, "", imageURL, imageURL)
+}
+
+// fetch returns the cached poster for serverURL and thumbPath, downloading
+// it from reqURL if it isn't cached.
+func fetch(ctx context.Context, client *http.Client, serverURL, thumbPath, reqURL string) (path string, fetched bool, err error) {
+	path, ok := Cached(serverURL, thumbPath)
+	if ok {
+		return path, false, nil
+	}
+	if path == "" {
+		return "", false, fmt.Errorf("no cache directory")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return "", false, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", false, fmt.Errorf("poster request returned %s", resp.Status)
+	}
+
+	// Written atomically so a concurrent reader or an interrupted download
+	// never sees a truncated poster.
+	if err := storage.WriteAtomicFrom(path, &cappedReader{r: resp.Body, left: maxImageBytes}, 0644); err != nil {
+		return "", false, err
+	}
+	return path, true, nil
+}
+
+// errTooLarge is returned by cappedReader past its limit.
+var errTooLarge = errors.New("poster exceeds size limit")
+
+// cappedReader fails with errTooLarge once more than left bytes have been
+// read, so an oversized response aborts the write instead of being cached
+// cut short.
+type cappedReader struct {
+	r    io.Reader
+	left int64
+}
+
+func (c *cappedReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.left -= int64(n)
+	if c.left < 0 {
+		return n, errTooLarge
+	}
+	return n, err
+}
+
+// Usage returns how many posters are cached and their total size.
+func Usage() (count int, size int64, err error) {
+	files, err := cachedFiles()
+	for _, f := range files {
+		size += f.size
+	}
+	return len(files), size, err
+}
+
+// Prune removes the least recently used posters until the cache fits in
+// maxBytes, returning how many were removed.
+func Prune(maxBytes int64) (int, error) {
+	files, err := cachedFiles()
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, f := range files {
+		total += f.size
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].mod.Before(files[j].mod) })
+	removed := 0
+	for _, f := range files {
+		if total <= maxBytes {
+			break
+		}
+		if os.Remove(f.path) == nil {
+			total -= f.size
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+type cachedFile struct {
+	path string
+	size int64
+	mod  time.Time
+}
+
+func cachedFiles() ([]cachedFile, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	files := make([]cachedFile, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".jpg" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, cachedFile{filepath.Join(dir, entry.Name()), info.Size(), info.ModTime()})
+	}
+	return files, nil
+}
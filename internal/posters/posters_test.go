@@ -0,0 +1,101 @@
+package posters
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setCacheHome(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("APPDATA", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+}
+
+func TestFetch(t *testing.T) {
+	setCacheHome(t)
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("X-Plex-Token") != "tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("jpeg:" + r.URL.Path))
+	}))
+	defer ts.Close()
+	ctx := context.Background()
+
+	path, fetched, err := Fetch(ctx, ts.Client(), ts.URL, "/library/metadata/1/thumb/9", "tok")
+	if err != nil || !fetched {
+		t.Fatalf("Fetch = %q, %t, %v", path, fetched, err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "jpeg:/library/metadata/1/thumb/9" {
+		t.Errorf("cached poster = %q", data)
+	}
+
+	again, fetched, err := Fetch(ctx, ts.Client(), ts.URL, "/library/metadata/1/thumb/9", "tok")
+	if err != nil || fetched || again != path || requests != 1 {
+		t.Errorf("second Fetch = %q, %t, %v after %d requests; want a cache hit", again, fetched, err, requests)
+	}
+
+	// The same thumb path on another server is a different poster.
+	other, _ := Path("http://other:32400", "/library/metadata/1/thumb/9")
+	if other == path {
+		t.Error("Path doesn't distinguish servers")
+	}
+
+	if _, _, err := Fetch(ctx, ts.Client(), ts.URL, "/library/metadata/2/thumb/9", "bad"); err == nil {
+		t.Error("Fetch with a bad token: expected an error")
+	}
+	if n, _, _ := Usage(); n != 1 {
+		t.Errorf("Usage = %d posters after a failed fetch, want 1", n)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	setCacheHome(t)
+	dir, _ := Dir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for i, name := range []string{"old", "mid", "new"} {
+		path := filepath.Join(dir, name+".jpg")
+		if err := os.WriteFile(path, []byte(strings.Repeat("x", 100)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		mod := now.Add(time.Duration(i-3) * time.Hour)
+		if err := os.Chtimes(path, mod, mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := Prune(150)
+	if err != nil || removed != 2 {
+		t.Fatalf("Prune = %d, %v; want 2 removed", removed, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "new.jpg")); err != nil {
+		t.Error("Prune evicted the most recently used poster")
+	}
+	if n, size, _ := Usage(); n != 1 || size != 100 {
+		t.Errorf("Usage after Prune = %d, %d", n, size)
+	}
+}
+
+func TestCappedReader(t *testing.T) {
+	if data, err := io.ReadAll(&cappedReader{r: strings.NewReader("12345"), left: 5}); err != nil || string(data) != "12345" {
+		t.Errorf("read at the limit = %q, %v", data, err)
+	}
+	if _, err := io.ReadAll(&cappedReader{r: strings.NewReader("123456"), left: 5}); !errors.Is(err, errTooLarge) {
+		t.Errorf("read past the limit error = %v, want errTooLarge", err)
+	}
+}
@@ -0,0 +1,123 @@
+package preview
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+// The data file fzf's preview reads holds a header line followed by one
+// JSON row per line, so a preview parses only the row it shows rather
+// than the whole library. Its index file, at IndexPath, holds each row's
+// byte offset as a fixed-width big-endian uint64, so row i is found with
+// a single read at 8*i.
+
+// header is the data file's first line.
+type header struct {
+	PlexURL string `json:"plex_url,omitempty"`
+	// Seasons marks each row as a season (its episodes) rather than a
+	// single item.
+	Seasons bool `json:"seasons,omitempty"`
+}
+
+// IndexPath returns the path of the index file for the data file at path.
+func IndexPath(path string) string {
+	return path + ".idx"
+}
+
+// WriteMedia writes the data file at path, and its index, for a picker
+// whose row i previews media[i]. plexURL is the server for items that
+// don't record their own.
+func WriteMedia(path, plexURL string, media []plex.MediaItem) error {
+	return writeData(path, header{PlexURL: plexURL}, len(media), func(i int) any { return media[i] })
+}
+
+// WriteSeasons is WriteMedia for a season picker: row i previews the
+// summary of seasons[i], a season's episodes.
+func WriteSeasons(path string, seasons [][]plex.MediaItem) error {
+	return writeData(path, header{Seasons: true}, len(seasons), func(i int) any { return seasons[i] })
+}
+
+func writeData(path string, h header, n int, row func(i int) any) error {
+	// Restrictive permissions keep the library listing private.
+	data, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer data.Close()
+	w := bufio.NewWriter(data)
+
+	line, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	offset := int64(len(line) + 1)
+	_, _ = w.Write(append(line, '\n'))
+
+	index := make([]byte, 8*n)
+	for i := range n {
+		line, err := json.Marshal(row(i))
+		if err != nil {
+			return err
+		}
+		binary.BigEndian.PutUint64(index[8*i:], uint64(offset))
+		offset += int64(len(line) + 1)
+		_, _ = w.Write(append(line, '\n'))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := data.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(IndexPath(path), index, 0600)
+}
+
+// errOutOfRange is returned by readRow for an index past the last row.
+var errOutOfRange = errors.New("index out of range")
+
+// readRow returns the data file's header and the JSON of row index.
+func readRow(path string, index int) (h header, row []byte, err error) {
+	if index < 0 {
+		return h, nil, errOutOfRange
+	}
+	idx, err := os.Open(IndexPath(path))
+	if err != nil {
+		return h, nil, err
+	}
+	defer idx.Close()
+	var buf [8]byte
+	if _, err := idx.ReadAt(buf[:], 8*int64(index)); err != nil {
+		if err == io.EOF {
+			return h, nil, errOutOfRange
+		}
+		return h, nil, err
+	}
+	offset := int64(binary.BigEndian.Uint64(buf[:]))
+
+	data, err := os.Open(path)
+	if err != nil {
+		return h, nil, err
+	}
+	defer data.Close()
+	line, err := bufio.NewReader(data).ReadBytes('\n')
+	if err != nil {
+		return h, nil, fmt.Errorf("reading header: %w", err)
+	}
+	if err := json.Unmarshal(line, &h); err != nil {
+		return h, nil, fmt.Errorf("parsing header: %w", err)
+	}
+	if _, err := data.Seek(offset, io.SeekStart); err != nil {
+		return h, nil, err
+	}
+	if row, err = bufio.NewReader(data).ReadBytes('\n'); err != nil {
+		return h, nil, fmt.Errorf("reading row %d: %w", index, err)
+	}
+	return h, row, nil
+}
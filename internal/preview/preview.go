@@ -4,57 +4,189 @@
 package preview
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/joshkerr/goplexcli/internal/arr"
 	"github.com/joshkerr/goplexcli/internal/plex"
+	"github.com/joshkerr/goplexcli/internal/posters"
+	"github.com/joshkerr/goplexcli/internal/termimg"
 )
 
-type previewData struct {
-	Media     []plex.MediaItem `json:"media"`
-	PlexURL   string           `json:"plex_url"`
-	PlexToken string           `json:"plex_token"`
-}
-
-// Run reads the JSON data file, looks up the item at index, and writes the
-// formatted preview to out. Returns an error suitable for surfacing in fzf's
-// preview pane (also rendered to out so the user sees it).
-func Run(out io.Writer, dataFile, indexStr string) error {
+// Run reads the row at index of the data file written by WriteMedia or
+// WriteSeasons and writes the formatted preview to out, headed by the
+// item's poster drawn with images and fetched with token. With a
+// Radarr/Sonarr snapshot, the item's status there is shown too. Returns an
+// error suitable for surfacing in fzf's preview pane (also rendered to out
+// so the user sees it).
+func Run(out io.Writer, dataFile, indexStr, token string, images termimg.Protocol, snap *arr.Snapshot) error {
 	index, err := strconv.Atoi(indexStr)
 	if err != nil {
 		fmt.Fprintf(out, "Invalid index: %v\n", err)
 		return err
 	}
 
-	data, err := os.ReadFile(dataFile)
+	h, row, err := readRow(dataFile, index)
+	if errors.Is(err, errOutOfRange) {
+		fmt.Fprintln(out, "Index out of range")
+		return fmt.Errorf("index %d out of range", index)
+	}
 	if err != nil {
 		fmt.Fprintf(out, "Error reading data file: %v\n", err)
 		return err
 	}
 
-	var pd previewData
-	if err := json.Unmarshal(data, &pd); err != nil {
+	if h.Seasons {
+		var episodes []plex.MediaItem
+		if err := json.Unmarshal(row, &episodes); err != nil {
+			fmt.Fprintf(out, "Error parsing data: %v\n", err)
+			return err
+		}
+		renderSeason(out, episodes)
+		return nil
+	}
+
+	var item plex.MediaItem
+	if err := json.Unmarshal(row, &item); err != nil {
 		fmt.Fprintf(out, "Error parsing data: %v\n", err)
 		return err
 	}
+	drawPoster(out, images, item, h.PlexURL, token)
+	var status string
+	if snap != nil {
+		status = snap.Status(&item)
+	}
+	render(out, item, status)
+	return nil
+}
 
-	if index < 0 || index >= len(pd.Media) {
-		fmt.Fprintln(out, "Index out of range")
-		return fmt.Errorf("index %d out of range", index)
+// posterTimeout bounds fetching an uncached poster, so a slow server delays
+// the preview text only briefly.
+const posterTimeout = 3 * time.Second
+
+// drawPoster draws the item's poster above its details, sized to the preview
+// pane fzf reports. Items without a Plex poster fall back to TMDB's. Posters
+// that can't be fetched or drawn are left out.
+func drawPoster(out io.Writer, images termimg.Protocol, item plex.MediaItem, plexURL, token string) {
+	if images == termimg.None {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), posterTimeout)
+	defer cancel()
+	path, fetched, err := fetchPoster(ctx, http.DefaultClient, item, plexURL, token)
+	if err != nil {
+		return
+	}
+	if fetched {
+		_, _ = posters.Prune(posters.MaxBytes)
 	}
 
-	render(out, pd.Media[index])
-	return nil
+	cols, rows := posterSize(envInt("FZF_PREVIEW_COLUMNS", 60), envInt("FZF_PREVIEW_LINES", 40))
+	_ = termimg.Draw(out, images, path, cols, rows)
+}
+
+// fetchPoster returns the cached path of the poster the preview shows for
+// item, downloading it first if needed, and whether it downloaded.
+func fetchPoster(ctx context.Context, client *http.Client, item plex.MediaItem, plexURL, token string) (string, bool, error) {
+	thumb := item.Thumb
+	if thumb == "" {
+		thumb = item.GrandparentThumb
+	}
+	if thumb == "" {
+		return posters.FetchURL(ctx, client, item.PosterURL)
+	}
+	serverURL := item.ServerURL
+	if serverURL == "" {
+		serverURL = plexURL
+	}
+	return posters.Fetch(ctx, client, serverURL, thumb, token)
 }
 
-func render(out io.Writer, item plex.MediaItem) {
+// prefetchConcurrency bounds parallel downloads in Prefetch, to stay gentle
+// on the Plex server.
+const prefetchConcurrency = 4
+
+// Prefetch downloads the posters the preview shows for media into the
+// poster cache, in list order, until ctx is done, so previews find them
+// cached and render instantly. Run it in the background while the picker
+// is open.
+func Prefetch(ctx context.Context, media []plex.MediaItem, plexURL, token string) {
+	client := &http.Client{Timeout: 20 * time.Second}
+	jobs := make(chan int)
+	var fetched atomic.Bool
+	var wg sync.WaitGroup
+	for range min(prefetchConcurrency, len(media)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if _, got, err := fetchPoster(ctx, client, media[i], plexURL, token); err == nil && got {
+					fetched.Store(true)
+				}
+			}
+		}()
+	}
+feed:
+	for i := range media {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if fetched.Load() {
+		_, _ = posters.Prune(posters.MaxBytes)
+	}
+}
+
+// posterSize fits a 2:3 poster into at most half the width and half the
+// height of a pane of cols by lines cells, assuming cells twice as tall as
+// they are wide.
+func posterSize(cols, lines int) (int, int) {
+	w := min(cols/2, 30)
+	h := w * 3 / 4
+	if h > lines/2 {
+		h = lines / 2
+		w = h * 4 / 3
+	}
+	return w, h
+}
+
+func envInt(name string, fallback int) int {
+	if n, err := strconv.Atoi(os.Getenv(name)); err == nil && n > 0 {
+		return n
+	}
+	return fallback
+}
+
+// previewCast is how many top-billed cast members the preview lists; the
+// full cast is in the info command.
+const previewCast = 5
+
+// previewSimilar is how many TMDB recommendations the preview lists; the
+// similar command shows them all.
+const previewSimilar = 5
+
+// render writes item's details; arrStatus, if set, is its Radarr or Sonarr
+// status.
+func render(out io.Writer, item plex.MediaItem, arrStatus string) {
 	fmt.Fprintln(out, strings.Repeat("─", 60))
 	fmt.Fprintf(out, " %s\n", item.Title)
+	if item.Tagline != "" {
+		fmt.Fprintf(out, " %s\n", item.Tagline)
+	}
 	fmt.Fprintln(out, strings.Repeat("─", 60))
 
 	switch item.Type {
@@ -89,6 +221,9 @@ func render(out io.Writer, item plex.MediaItem) {
 			fmt.Fprintln(out, "\nUnwatched")
 		}
 	}
+	if arrStatus != "" {
+		fmt.Fprintln(out, arrStatus)
+	}
 
 	if item.Rating > 0 || item.ContentRating != "" {
 		fmt.Fprintln(out)
@@ -104,14 +239,13 @@ func render(out io.Writer, item plex.MediaItem) {
 	}
 
 	if item.Duration > 0 {
-		minutes := item.Duration / 60000
-		if minutes >= 60 {
-			hours := minutes / 60
-			mins := minutes % 60
-			fmt.Fprintf(out, "Duration: %dh %dm\n", hours, mins)
-		} else {
-			fmt.Fprintf(out, "Duration: %d min\n", minutes)
-		}
+		fmt.Fprintf(out, "Duration: %s\n", formatMinutes(item.Duration))
+	}
+	if tech := item.FormatTech(); tech != "" {
+		fmt.Fprintf(out, "Format: %s\n", tech)
+	}
+	if size := plex.FormatSize(item.Size); size != "" {
+		fmt.Fprintf(out, "Size: %s\n", size)
 	}
 
 	if item.Genre != "" {
@@ -120,8 +254,11 @@ func render(out io.Writer, item plex.MediaItem) {
 	if item.Director != "" {
 		fmt.Fprintf(out, "Director: %s\n", item.Director)
 	}
+	if item.Writer != "" {
+		fmt.Fprintf(out, "Writer: %s\n", item.Writer)
+	}
 	if item.Cast != "" {
-		fmt.Fprintf(out, "Cast: %s\n", item.Cast)
+		fmt.Fprintf(out, "Cast: %s\n", item.TopCast(previewCast))
 	}
 	if item.Studio != "" {
 		fmt.Fprintf(out, "Studio: %s\n", item.Studio)
@@ -131,6 +268,10 @@ func render(out io.Writer, item plex.MediaItem) {
 		fmt.Fprintf(out, "\nSummary:\n%s\n", wrapText(item.Summary, 56))
 	}
 
+	if len(item.Similar) > 0 {
+		fmt.Fprintf(out, "\nSimilar: %s\n", wrapText(strings.Join(item.Similar[:min(len(item.Similar), previewSimilar)], ", "), 56))
+	}
+
 	if item.AddedAt > 0 {
 		addedTime := time.Unix(item.AddedAt, 0)
 		fmt.Fprintf(out, "\nAdded: %s\n", addedTime.Format("Jan 2, 2006"))
@@ -144,6 +285,94 @@ func render(out io.Writer, item plex.MediaItem) {
 	fmt.Fprintln(out, "\nPress Ctrl+P to toggle this preview")
 }
 
+// renderSeason summarizes a season: what's left to watch, then one line per
+// episode with a watched marker (✓ watched, ◐ in progress, · unwatched), its
+// air date and runtime.
+func renderSeason(out io.Writer, episodes []plex.MediaItem) {
+	fmt.Fprintln(out, strings.Repeat("─", 60))
+	if len(episodes) == 0 {
+		fmt.Fprintln(out, " No episodes")
+		fmt.Fprintln(out, strings.Repeat("─", 60))
+		return
+	}
+	season := fmt.Sprintf("Season %d", episodes[0].ParentIndex)
+	if episodes[0].ParentIndex == 0 {
+		season = "Specials"
+	}
+	fmt.Fprintf(out, " %s — %s\n", episodes[0].ParentTitle, season)
+	fmt.Fprintln(out, strings.Repeat("─", 60))
+
+	watched, remainingMs := 0, 0
+	for _, ep := range episodes {
+		state := watchState(ep)
+		if state == stateWatched {
+			watched++
+		} else {
+			remainingMs += ep.Duration - ep.ViewOffset
+		}
+	}
+	fmt.Fprintf(out, "\n%d episodes, %d watched\n", len(episodes), watched)
+	if remainingMs > 0 {
+		fmt.Fprintf(out, "Remaining: %s\n", formatMinutes(remainingMs))
+	} else if watched == len(episodes) {
+		fmt.Fprintln(out, "All watched")
+	}
+	fmt.Fprintln(out)
+
+	for _, ep := range episodes {
+		marker := "·"
+		switch watchState(ep) {
+		case stateWatched:
+			marker = "✓"
+		case stateInProgress:
+			marker = "◐"
+		}
+		line := fmt.Sprintf("%s E%02d  %s", marker, ep.Index, ep.Title)
+		var meta []string
+		if ep.OriginallyAired != "" {
+			meta = append(meta, ep.OriginallyAired)
+		}
+		if ep.Duration > 0 {
+			meta = append(meta, formatMinutes(ep.Duration))
+		}
+		if len(meta) > 0 {
+			line += "  (" + strings.Join(meta, ", ") + ")"
+		}
+		fmt.Fprintln(out, line)
+	}
+
+	fmt.Fprintln(out, strings.Repeat("─", 60))
+	fmt.Fprintln(out, "\nPress Ctrl+P to toggle this preview")
+}
+
+// Watch states for the season summary, using the same rules as render.
+const (
+	stateUnwatched = iota
+	stateInProgress
+	stateWatched
+)
+
+func watchState(item plex.MediaItem) int {
+	switch {
+	case item.ViewCount > 0:
+		return stateWatched
+	case item.ViewOffset > 0 && item.Duration > 0 && item.ViewOffset*100/item.Duration >= 95:
+		return stateWatched
+	case item.ViewOffset > 0:
+		return stateInProgress
+	}
+	return stateUnwatched
+}
+
+// formatMinutes renders a millisecond duration as "1h 5m" or "45 min".
+func formatMinutes(ms int) string {
+	minutes := ms / 60000
+	if minutes >= 60 {
+		return fmt.Sprintf("%dh %dm", minutes/60, minutes%60)
+	}
+	return fmt.Sprintf("%d min", minutes)
+}
+
 func wrapText(text string, width int) string {
 	words := strings.Fields(text)
 	if len(words) == 0 {
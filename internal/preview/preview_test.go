@@ -0,0 +1,160 @@
+package preview
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+	"github.com/joshkerr/goplexcli/internal/posters"
+	"github.com/joshkerr/goplexcli/internal/termimg"
+)
+
+func TestRunSeason(t *testing.T) {
+	season := []plex.MediaItem{
+		{Title: "Pilot", Type: "episode", ParentTitle: "Show", ParentIndex: 2, Index: 1, Duration: 3000000, ViewCount: 1, OriginallyAired: "2020-01-05"},
+		{Title: "Second", Type: "episode", ParentTitle: "Show", ParentIndex: 2, Index: 2, Duration: 3000000, ViewOffset: 600000},
+		{Title: "Third", Type: "episode", ParentTitle: "Show", ParentIndex: 2, Index: 3, Duration: 2400000},
+	}
+	path := filepath.Join(t.TempDir(), "data.jsonl")
+	if err := WriteSeasons(path, [][]plex.MediaItem{season}); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := Run(&out, path, "0", "", termimg.None, nil); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"Show — Season 2",
+		"3 episodes, 1 watched",
+		"Remaining: 1h 20m", // 40 min left of Second plus all 40 of Third
+		"✓ E01  Pilot  (2020-01-05, 50 min)",
+		"◐ E02  Second",
+		"· E03  Third",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("season preview missing %q:\n%s", want, got)
+		}
+	}
+
+	if err := Run(&out, path, "1", "", termimg.None, nil); err == nil {
+		t.Error("an index past the last season should fail")
+	}
+}
+
+func TestRunMedia(t *testing.T) {
+	media := []plex.MediaItem{
+		{Title: "Heat", Type: "movie", Year: 1995},
+		{Title: "Thief", Type: "movie", Year: 1981, Summary: "A safecracker\nwants out."},
+		{Title: "Collateral", Type: "movie", Year: 2004},
+	}
+	path := filepath.Join(t.TempDir(), "data.jsonl")
+	if err := WriteMedia(path, "http://plex:32400", media); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, item := range media {
+		var out bytes.Buffer
+		if err := Run(&out, path, strconv.Itoa(i), "", termimg.None, nil); err != nil {
+			t.Fatalf("Run(%d): %v", i, err)
+		}
+		if want := fmt.Sprintf(" %s\n", item.Title); !strings.Contains(out.String(), want) {
+			t.Errorf("preview of row %d lacks %q:\n%s", i, want, out.String())
+		}
+	}
+
+	var out bytes.Buffer
+	if err := Run(&out, path, "3", "", termimg.None, nil); err == nil || !strings.Contains(out.String(), "Index out of range") {
+		t.Errorf("Run past the last row = %v, %q; want an out of range error", err, out.String())
+	}
+}
+
+func TestPrefetch(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("APPDATA", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte("jpeg"))
+	}))
+	defer ts.Close()
+
+	media := []plex.MediaItem{
+		{Title: "Heat", Thumb: "/library/metadata/1/thumb"},
+		{Title: "Pilot", GrandparentThumb: "/library/metadata/2/thumb"},
+		{Title: "No poster"},
+	}
+	Prefetch(context.Background(), media, ts.URL, "tok")
+	for _, thumb := range []string{"/library/metadata/1/thumb", "/library/metadata/2/thumb"} {
+		if _, ok := posters.Cached(ts.URL, thumb); !ok {
+			t.Errorf("poster %s wasn't prefetched", thumb)
+		}
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("server got %d requests, want 2", n)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	Prefetch(ctx, []plex.MediaItem{{Thumb: "/library/metadata/3/thumb"}}, ts.URL, "tok")
+	if _, ok := posters.Cached(ts.URL, "/library/metadata/3/thumb"); ok {
+		t.Error("a cancelled prefetch shouldn't fetch anything")
+	}
+}
+
+func TestPosterSize(t *testing.T) {
+	tests := []struct{ cols, lines, w, h int }{
+		{80, 60, 30, 22}, // capped width
+		{40, 40, 20, 15},
+		{80, 20, 13, 10}, // short pane
+	}
+	for _, tt := range tests {
+		if w, h := posterSize(tt.cols, tt.lines); w != tt.w || h != tt.h {
+			t.Errorf("posterSize(%d, %d) = %d, %d; want %d, %d", tt.cols, tt.lines, w, h, tt.w, tt.h)
+		}
+	}
+}
+
+func TestRenderDetails(t *testing.T) {
+	var out bytes.Buffer
+	render(&out, plex.MediaItem{
+		Title:           "Heat",
+		Type:            "movie",
+		Director:        "Michael Mann",
+		Writer:          "Michael Mann",
+		Cast:            "Al Pacino, Robert De Niro, Val Kilmer, Jon Voight, Tom Sizemore, Diane Venora",
+		VideoResolution: "1080",
+		VideoCodec:      "h264",
+		AudioCodec:      "dts",
+		Size:            12 << 30,
+		Tagline:         "A Los Angeles crime saga",
+		Similar:         []string{"Collateral (2004)", "Thief (1981)"},
+	}, "Radarr: monitored")
+	got := out.String()
+	for _, want := range []string{
+		"Director: Michael Mann\n",
+		"Writer: Michael Mann\n",
+		"Cast: Al Pacino, Robert De Niro, Val Kilmer, Jon Voight, Tom Sizemore, …\n",
+		"Format: 1080p H264 · DTS\n",
+		"Size: 12.0 GB\n",
+		" A Los Angeles crime saga\n",
+		"Similar: Collateral (2004), Thief (1981)\n",
+		"Radarr: monitored\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("preview missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package progress
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+// markerPollInterval is how often the player is checked for entering an intro
+// or credits marker. Much finer than the Plex reporting interval, since a
+// skip that lands ten seconds into the intro is hardly a skip.
+const markerPollInterval = time.Second
+
+// markerEndSlackMs is how close to a marker's end playback can get before
+// it's no longer worth skipping or offering to.
+const markerEndSlackMs = 2000
+
+// SkipKey is the key bound to skip the current intro or credits when they
+// aren't skipped automatically, and skipKeyDefault is mpv's own binding for
+// it, restored once the marker has passed.
+const (
+	SkipKey        = "S"
+	skipKeyDefault = "screenshot video"
+)
+
+// Seeker is a player the tracker can control as well as observe. MPVClient
+// implements it, which covers mpv and IINA.
+type Seeker interface {
+	Seek(seconds float64) error
+	ShowText(text string, d time.Duration) error
+	BindKey(key, command string) error
+}
+
+// MarkerFetcher returns the markers of the item with the given rating key.
+type MarkerFetcher func(ratingKey string) ([]plex.Marker, error)
+
+// PlexMarkers returns a MarkerFetcher backed by client.
+func PlexMarkers(ctx context.Context, client *plex.Client) MarkerFetcher {
+	return func(ratingKey string) ([]plex.Marker, error) {
+		return client.GetMarkers(ctx, ratingKey)
+	}
+}
+
+// markerRef identifies one marker of one playlist item.
+type markerRef struct {
+	index   int
+	startMs int
+}
+
+// markerState is the marker handling state, only touched by markerLoop.
+type markerState struct {
+	fetch   MarkerFetcher
+	skip    bool
+	seeker  Seeker
+	byIndex map[int][]plex.Marker
+	hinted  *markerRef
+	skipped map[markerRef]bool
+}
+
+// EnableMarkers makes the tracker act on intro and credits markers during
+// playback: with skip set it seeks past them automatically (once each, so
+// seeking back to rewatch one works), otherwise it shows a hint and binds
+// SkipKey to skip. It must be called before Start, and reports false (doing
+// nothing) for players that can't seek.
+func (t *Tracker) EnableMarkers(fetch MarkerFetcher, skip bool) bool {
+	seeker, ok := t.player.(Seeker)
+	if !ok || fetch == nil {
+		return false
+	}
+	t.markers = &markerState{
+		fetch:   fetch,
+		skip:    skip,
+		seeker:  seeker,
+		byIndex: make(map[int][]plex.Marker),
+		skipped: make(map[markerRef]bool),
+	}
+	return true
+}
+
+// markerLoop checks for markers every markerPollInterval until stopped.
+func (t *Tracker) markerLoop(ctx context.Context) {
+	ticker := time.NewTicker(markerPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.stopCh:
+			return
+		case <-ticker.C:
+			t.checkMarkers()
+		}
+	}
+}
+
+// checkMarkers skips or offers to skip the marker playback is in, if any.
+func (t *Tracker) checkMarkers() {
+	ms := t.markers
+	index, err := t.player.GetPlaylistPos()
+	if err != nil {
+		return
+	}
+	item := t.Item(index)
+	if item == nil {
+		return
+	}
+	pos, err := t.player.GetTimePos()
+	if err != nil {
+		return
+	}
+
+	markers, fetched := ms.byIndex[index]
+	if !fetched {
+		markers, err = ms.fetch(extractRatingKey(item.Key))
+		if err != nil {
+			log.Printf("Failed to fetch markers: %v", err)
+		}
+		// Cache failures too: one warning per item is enough.
+		ms.byIndex[index] = markers
+	}
+
+	m := activeMarker(markers, int(pos*1000))
+	if m == nil {
+		if ms.hinted != nil {
+			_ = ms.seeker.BindKey(SkipKey, skipKeyDefault)
+			ms.hinted = nil
+		}
+		return
+	}
+
+	ref := markerRef{index: index, startMs: m.StartMs}
+	endSec := float64(m.EndMs) / 1000
+	if ms.skip {
+		if ms.skipped[ref] {
+			return
+		}
+		ms.skipped[ref] = true
+		if err := ms.seeker.Seek(endSec); err != nil {
+			log.Printf("Failed to skip %s: %v", m.Type, err)
+			return
+		}
+		_ = ms.seeker.ShowText("Skipped "+m.Type, 2*time.Second)
+		return
+	}
+
+	if ms.hinted != nil && *ms.hinted == ref {
+		return
+	}
+	if err := ms.seeker.BindKey(SkipKey, fmt.Sprintf("seek %.3f absolute", endSec)); err != nil {
+		// Older mpv without keybind: a hint for a key that does nothing
+		// would only confuse.
+		return
+	}
+	ms.hinted = &ref
+	_ = ms.seeker.ShowText(fmt.Sprintf("Press %s to skip %s", SkipKey, m.Type), 5*time.Second)
+}
+
+// activeMarker returns the marker containing posMs, unless playback is
+// already at its very end.
+func activeMarker(markers []plex.Marker, posMs int) *plex.Marker {
+	for i := range markers {
+		m := &markers[i]
+		if posMs >= m.StartMs && posMs < m.EndMs-markerEndSlackMs {
+			return m
+		}
+	}
+	return nil
+}
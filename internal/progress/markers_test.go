@@ -0,0 +1,138 @@
+package progress
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+// fakeSeeker is a fakePlayer that records the commands sent to it.
+type fakeSeeker struct {
+	fakePlayer
+	seeks    []float64
+	texts    []string
+	bindings map[string]string
+}
+
+func (f *fakeSeeker) Seek(seconds float64) error {
+	f.seeks = append(f.seeks, seconds)
+	f.pos = seconds
+	return nil
+}
+
+func (f *fakeSeeker) ShowText(text string, d time.Duration) error {
+	f.texts = append(f.texts, text)
+	return nil
+}
+
+func (f *fakeSeeker) BindKey(key, command string) error {
+	if f.bindings == nil {
+		f.bindings = map[string]string{}
+	}
+	f.bindings[key] = command
+	return nil
+}
+
+func markerTracker(t *testing.T, p PlayerClient, skip bool) (*Tracker, *int) {
+	t.Helper()
+	items := []*plex.MediaItem{{Key: "/library/metadata/9", Title: "Pilot", Duration: 1500000}}
+	tracker := NewTracker(items, p, nil)
+	fetches := 0
+	fetch := func(ratingKey string) ([]plex.Marker, error) {
+		fetches++
+		if ratingKey != "9" {
+			t.Errorf("markers fetched for rating key %q, want 9", ratingKey)
+		}
+		return []plex.Marker{
+			{Type: "intro", StartMs: 30000, EndMs: 90000},
+			{Type: "credits", StartMs: 1400000, EndMs: 1500000, Final: true},
+		}, nil
+	}
+	if !tracker.EnableMarkers(fetch, skip) {
+		t.Fatal("EnableMarkers should accept a player that can seek")
+	}
+	return tracker, &fetches
+}
+
+func TestMarkersAutoSkip(t *testing.T) {
+	p := &fakeSeeker{fakePlayer: fakePlayer{pos: 10}}
+	tracker, fetches := markerTracker(t, p, true)
+
+	tracker.checkMarkers()
+	if len(p.seeks) != 0 {
+		t.Fatalf("no skip expected before the intro, got %v", p.seeks)
+	}
+
+	p.pos = 31
+	tracker.checkMarkers()
+	if len(p.seeks) != 1 || p.seeks[0] != 90 {
+		t.Fatalf("seeks = %v, want a skip to 90s", p.seeks)
+	}
+
+	// Seeking back into the intro means the user wants to watch it.
+	p.pos = 40
+	tracker.checkMarkers()
+	if len(p.seeks) != 1 {
+		t.Errorf("an intro should only be skipped once, seeks = %v", p.seeks)
+	}
+	if *fetches != 1 {
+		t.Errorf("markers fetched %d times, want once per item", *fetches)
+	}
+}
+
+func TestMarkersHint(t *testing.T) {
+	p := &fakeSeeker{fakePlayer: fakePlayer{pos: 45}}
+	tracker, _ := markerTracker(t, p, false)
+
+	tracker.checkMarkers()
+	if len(p.seeks) != 0 {
+		t.Fatalf("hint mode must not seek, got %v", p.seeks)
+	}
+	if got := p.bindings[SkipKey]; got != "seek 90.000 absolute" {
+		t.Errorf("%s bound to %q, want a seek past the intro", SkipKey, got)
+	}
+	if len(p.texts) != 1 || !strings.Contains(p.texts[0], "skip intro") {
+		t.Errorf("texts = %v, want a skip hint", p.texts)
+	}
+
+	// The hint is shown once per marker, not on every poll.
+	p.pos = 50
+	tracker.checkMarkers()
+	if len(p.texts) != 1 {
+		t.Errorf("hint repeated: %v", p.texts)
+	}
+
+	// Past the intro the key gets its usual binding back.
+	p.pos = 120
+	tracker.checkMarkers()
+	if got := p.bindings[SkipKey]; got != skipKeyDefault {
+		t.Errorf("%s bound to %q after the intro, want %q", SkipKey, got, skipKeyDefault)
+	}
+}
+
+func TestEnableMarkersNeedsSeeker(t *testing.T) {
+	tracker := NewTracker(nil, &fakePlayer{}, nil)
+	if tracker.EnableMarkers(func(string) ([]plex.Marker, error) { return nil, nil }, true) {
+		t.Error("a player that can't seek should not get marker handling")
+	}
+}
+
+func TestActiveMarker(t *testing.T) {
+	markers := []plex.Marker{{Type: "intro", StartMs: 1000, EndMs: 10000}}
+	tests := []struct {
+		posMs int
+		want  bool
+	}{
+		{999, false},
+		{1000, true},
+		{7999, true},
+		{8000, false}, // too close to the end to bother
+	}
+	for _, tt := range tests {
+		if got := activeMarker(markers, tt.posMs) != nil; got != tt.want {
+			t.Errorf("activeMarker(%d) = %v, want %v", tt.posMs, got, tt.want)
+		}
+	}
+}
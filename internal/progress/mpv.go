@@ -1,8 +1,9 @@
 // Package progress provides playback progress tracking for media players.
-// It includes an IPC client for communicating with MPV media player to track
-// playback position and state, which is then used to report progress to Plex.
+// It includes an IPC client for communicating with MPV media player and an
+// HTTP client for VLC's web interface to track playback position and state,
+// which is then used to report progress to Plex.
 //
-// The IPC connection uses Unix domain sockets on macOS/Linux and named pipes on Windows.
+// The MPV IPC connection uses Unix domain sockets on macOS/Linux and named pipes on Windows.
 package progress
 
 import (
@@ -14,8 +15,11 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"sync"
 	"time"
+
+	"github.com/joshkerr/goplexcli/internal/logging"
 )
 
 // Connection retry settings for MPV IPC socket
@@ -114,6 +118,7 @@ func (c *MPVClient) ConnectWithContext(ctx context.Context) error {
 		if err == nil {
 			c.conn = conn
 			c.reader = bufio.NewReader(conn)
+			logging.Debug("connected to mpv ipc", "socket", c.socketPath, "attempts", i+1)
 			return nil
 		}
 		lastErr = err
@@ -176,6 +181,7 @@ func (c *MPVClient) sendCommand(cmd mpvCommand) (*mpvResponse, error) {
 	if _, err := c.conn.Write(data); err != nil {
 		return nil, fmt.Errorf("failed to send command: %w", err)
 	}
+	logging.Debug("mpv ipc send", "request_id", cmd.RequestID, "command", cmd.Command)
 
 	// Read responses until we find one with our request_id
 	// This handles async events that MPV sends unsolicited
@@ -193,6 +199,7 @@ func (c *MPVClient) sendCommand(cmd mpvCommand) (*mpvResponse, error) {
 
 		// Skip async events (they have "event" field but no matching request_id)
 		if resp.Event != "" {
+			logging.Debug("mpv ipc event", "event", resp.Event)
 			continue
 		}
 
@@ -201,6 +208,8 @@ func (c *MPVClient) sendCommand(cmd mpvCommand) (*mpvResponse, error) {
 			continue // Not our response, keep reading
 		}
 
+		logging.Debug("mpv ipc reply", "request_id", resp.RequestID, "error", resp.Error, "data", resp.Data)
+
 		// Check for MPV errors
 		if resp.Error != "success" {
 			return &resp, fmt.Errorf("MPV error: %s", resp.Error)
@@ -246,6 +255,116 @@ func (c *MPVClient) GetPaused() (bool, error) {
 	return paused, nil
 }
 
+// Seek jumps to an absolute position in seconds.
+func (c *MPVClient) Seek(seconds float64) error {
+	_, err := c.sendCommand(buildMPVCommand("seek", strconv.FormatFloat(seconds, 'f', 3, 64), "absolute"))
+	return err
+}
+
+// SeekRelative moves seconds forward, or back if negative.
+func (c *MPVClient) SeekRelative(seconds float64) error {
+	_, err := c.sendCommand(buildMPVCommand("seek", strconv.FormatFloat(seconds, 'f', 3, 64), "relative"))
+	return err
+}
+
+// SetPaused pauses or resumes playback.
+func (c *MPVClient) SetPaused(paused bool) error {
+	value := "no"
+	if paused {
+		value = "yes"
+	}
+	_, err := c.sendCommand(buildMPVCommand("set", "pause", value))
+	return err
+}
+
+// PlaylistNext skips to the next playlist item, like mpv's Enter key.
+func (c *MPVClient) PlaylistNext() error {
+	_, err := c.sendCommand(buildMPVCommand("playlist-next"))
+	return err
+}
+
+// AppendFile adds a file or URL to the end of mpv's playlist, to play after
+// the ones already queued.
+func (c *MPVClient) AppendFile(path string) error {
+	_, err := c.sendCommand(buildMPVCommand("loadfile", path, "append"))
+	return err
+}
+
+// Quit asks mpv to exit, like its q key.
+func (c *MPVClient) Quit() error {
+	_, err := c.sendCommand(buildMPVCommand("quit"))
+	return err
+}
+
+// ShowText displays text on mpv's on-screen display for d.
+func (c *MPVClient) ShowText(text string, d time.Duration) error {
+	_, err := c.sendCommand(buildMPVCommand("show-text", text, strconv.FormatInt(d.Milliseconds(), 10)))
+	return err
+}
+
+// BindKey binds key to an mpv input command for the rest of the session,
+// replacing its current binding. Requires mpv 0.37 or later.
+func (c *MPVClient) BindKey(key, command string) error {
+	_, err := c.sendCommand(buildMPVCommand("keybind", key, command))
+	return err
+}
+
+// ScreenshotKey is the key BindScreenshotKey binds.
+const ScreenshotKey = "Ctrl+s"
+
+// ScreenshotToFile saves the current frame, without subtitles, to path. The
+// image format follows path's extension (png, jpg or webp).
+func (c *MPVClient) ScreenshotToFile(path string) error {
+	_, err := c.sendCommand(buildMPVCommand("screenshot-to-file", path, "video"))
+	return err
+}
+
+// BindScreenshotKey binds ScreenshotKey to save the current frame into dir,
+// named after the title and position, e.g. "Heat (1995) 4521.250000.png".
+// Requires mpv 0.37 or later, like BindKey.
+func (c *MPVClient) BindScreenshotKey(dir string) error {
+	return c.BindKey(ScreenshotKey, screenshotCommand(dir))
+}
+
+// screenshotCommand is the input command BindScreenshotKey binds. mpv
+// expands the ${...} properties each time the key is pressed, so every
+// still gets its own name.
+func screenshotCommand(dir string) string {
+	path := filepath.Join(dir, "${media-title} ${=time-pos}.png")
+	return "screenshot-to-file " + strconv.Quote(path) + " video"
+}
+
+// GetChapter returns the current chapter (0-indexed), or -1 before the first
+// chapter of a file that has them.
+func (c *MPVClient) GetChapter() (int, error) {
+	resp, err := c.sendCommand(buildMPVCommand("get_property", "chapter"))
+	if err != nil {
+		return 0, err
+	}
+
+	// MPV returns numbers as float64 in JSON
+	chapter, ok := resp.Data.(float64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected chapter type: %T", resp.Data)
+	}
+
+	return int(chapter), nil
+}
+
+// SetChapter jumps to a chapter (0-indexed). It fails if the file isn't
+// loaded yet or has no such chapter.
+func (c *MPVClient) SetChapter(chapter int) error {
+	_, err := c.sendCommand(buildMPVCommand("set", "chapter", strconv.Itoa(chapter)))
+	return err
+}
+
+// AddChapter moves delta chapters forward (or back, if negative), like
+// mpv's PgUp/PgDn keys.
+func (c *MPVClient) AddChapter(delta int) error {
+	_, err := c.sendCommand(buildMPVCommand("add", "chapter", strconv.Itoa(delta)))
+	return err
+}
+
 // GetPlaylistPos returns the current playlist position (0-indexed).
 func (c *MPVClient) GetPlaylistPos() (int, error) {
 	cmd := buildMPVCommand("get_property", "playlist-pos")
@@ -2,6 +2,7 @@ package progress
 
 import (
 	"encoding/json"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -99,3 +100,11 @@ func TestNewMPVClient(t *testing.T) {
 		t.Error("expected nil connection for new client")
 	}
 }
+
+func TestScreenshotCommand(t *testing.T) {
+	got := screenshotCommand(filepath.Join("stills", "Plex Shots"))
+	name := filepath.Join("stills", "Plex Shots", "${media-title} ${=time-pos}.png")
+	if want := `screenshot-to-file "` + strings.ReplaceAll(name, `\`, `\\`) + `" video`; got != want {
+		t.Errorf("screenshotCommand = %s, want %s", got, want)
+	}
+}
@@ -9,16 +9,38 @@ import (
 	"sync"
 	"time"
 
+	"github.com/joshkerr/goplexcli/internal/history"
 	"github.com/joshkerr/goplexcli/internal/plex"
 )
 
 // Position change threshold in seconds - only report if position changed by more than this
 const minPositionChangeSec = 5.0
 
-// Tracker monitors MPV playback and reports progress to Plex.
+// heartbeatInterval is the longest the tracker goes without a timeline update.
+// While paused the position doesn't move, so without a heartbeat Plex would
+// consider the session dead and drop it from its dashboard.
+const heartbeatInterval = 30 * time.Second
+
+// PlayerClient is the playback state the tracker polls. MPVClient and
+// VLCClient implement it.
+type PlayerClient interface {
+	GetTimePos() (float64, error)
+	GetPaused() (bool, error)
+	GetPlaylistPos() (int, error)
+}
+
+// Connector is a PlayerClient that must connect to the player once it has
+// started, and be closed afterwards.
+type Connector interface {
+	PlayerClient
+	ConnectWithContext(ctx context.Context) error
+	Close() error
+}
+
+// Tracker monitors player playback and reports progress to Plex.
 type Tracker struct {
 	items      []*plex.MediaItem
-	mpv        *MPVClient
+	player     PlayerClient
 	plexClient *plex.Client
 	index      int
 	mu         sync.RWMutex
@@ -30,19 +52,64 @@ type Tracker struct {
 	// local cache after playback so items appear in "Continue Watching"
 	// without a full reindex.
 	offsets map[int]int
+	// played records, per playlist index, the first and last reported
+	// positions and when they were seen, for the playback history.
+	played map[int]*playedSpan
+	// lastReport and lastState describe the most recent timeline update,
+	// used to send heartbeats while the position stands still.
+	lastReport time.Time
+	lastState  string
+	// markers is set by EnableMarkers to skip intros and credits.
+	markers *markerState
+	// onReport is set by OnReport to follow playback.
+	onReport func(item *plex.MediaItem, posMs int, state string)
+	// onFinished is set by OnFinished; finished records the playlist
+	// indexes it has been called for.
+	onFinished func(index int, item *plex.MediaItem)
+	finished   map[int]bool
+	// queue is set by CreatePlayQueue to report items as part of it.
+	queue *plex.PlayQueue
+}
+
+// finishedFraction is how much of an item must have played for it to count
+// as watched, as Plex and HasResumableProgress treat it.
+const finishedFraction = 0.95
+
+// playedSpan is the stretch of one item seen during a session.
+type playedSpan struct {
+	startMs, endMs int
+	started, ended time.Time
 }
 
 // NewTracker creates a new progress tracker.
-func NewTracker(items []*plex.MediaItem, mpv *MPVClient, plexClient *plex.Client) *Tracker {
+func NewTracker(items []*plex.MediaItem, player PlayerClient, plexClient *plex.Client) *Tracker {
 	return &Tracker{
 		items:      items,
-		mpv:        mpv,
+		player:     player,
 		plexClient: plexClient,
 		stopCh:     make(chan struct{}),
 		offsets:    make(map[int]int),
+		played:     make(map[int]*playedSpan),
+		finished:   make(map[int]bool),
 	}
 }
 
+// AddItem appends item to the playlist being tracked, for a file added to
+// the player's playlist while it plays, and returns its index.
+func (t *Tracker) AddItem(item *plex.MediaItem) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.items = append(t.items, item)
+	return len(t.items) - 1
+}
+
+// Len returns the number of items being tracked.
+func (t *Tracker) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.items)
+}
+
 // CurrentIndex returns the current playlist index.
 func (t *Tracker) CurrentIndex() int {
 	t.mu.RLock()
@@ -59,6 +126,16 @@ func (t *Tracker) SetIndex(idx int) {
 	}
 }
 
+// Item returns the item at playlist index, or nil if there is none.
+func (t *Tracker) Item(index int) *plex.MediaItem {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if index >= 0 && index < len(t.items) {
+		return t.items[index]
+	}
+	return nil
+}
+
 // CurrentMedia returns the currently playing media item.
 func (t *Tracker) CurrentMedia() *plex.MediaItem {
 	t.mu.RLock()
@@ -69,6 +146,51 @@ func (t *Tracker) CurrentMedia() *plex.MediaItem {
 	return nil
 }
 
+// CreatePlayQueue creates a Plex play queue holding the tracked items and
+// ties timeline reports to it, so other Plex apps show them as one queue
+// with its up-next items. Items added later with AddItem aren't in the
+// queue and are reported on their own. Items from several servers can't
+// share a queue.
+func (t *Tracker) CreatePlayQueue(ctx context.Context) error {
+	if t.plexClient == nil {
+		return fmt.Errorf("no Plex client")
+	}
+	t.mu.RLock()
+	keys := make([]string, 0, len(t.items))
+	for _, item := range t.items {
+		if item.ServerURL != t.items[0].ServerURL {
+			t.mu.RUnlock()
+			return fmt.Errorf("items span several servers")
+		}
+		keys = append(keys, extractRatingKey(item.Key))
+	}
+	t.mu.RUnlock()
+
+	q, err := t.plexClient.CreatePlayQueue(ctx, keys)
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.queue = q
+	return nil
+}
+
+// OnReport registers fn to be called with every position the tracker
+// records: when playback starts, moves, pauses or resumes, switches items,
+// and stops (state "stopped"). It must be called before Start.
+func (t *Tracker) OnReport(fn func(item *plex.MediaItem, posMs int, state string)) {
+	t.onReport = fn
+}
+
+// OnFinished registers fn to be called, once per playlist index, when an
+// item has played far enough to count as watched (95%). It runs on the
+// tracking goroutine, so fn should return quickly. It must be called before
+// Start.
+func (t *Tracker) OnFinished(fn func(index int, item *plex.MediaItem)) {
+	t.onFinished = fn
+}
+
 // extractRatingKey extracts the numeric rating key from a Plex media key.
 // e.g., "/library/metadata/12345" -> "12345"
 func extractRatingKey(key string) string {
@@ -80,13 +202,21 @@ func extractRatingKey(key string) string {
 }
 
 // Start begins tracking playback progress.
-// It polls MPV every interval and reports to Plex.
+// It polls the player every interval and reports to Plex.
 func (t *Tracker) Start(ctx context.Context, interval time.Duration) {
 	t.wg.Add(1)
 	go func() {
 		defer t.wg.Done()
 		t.trackLoop(ctx, interval)
 	}()
+
+	if t.markers != nil {
+		t.wg.Add(1)
+		go func() {
+			defer t.wg.Done()
+			t.markerLoop(ctx)
+		}()
+	}
 }
 
 // Stop stops the progress tracker. It is safe to call multiple times.
@@ -113,6 +243,48 @@ func (t *Tracker) Progress() map[string]int {
 	return out
 }
 
+// History returns a playback history session for each item seen playing, in
+// playlist order. If nothing was ever reported (tracking unavailable, or the
+// player quit before loading), the attempt is still recorded as a session for
+// the first item at startMs. Call after Stop.
+func (t *Tracker) History(player string, startMs int) []history.Session {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	session := func(index int) history.Session {
+		media := *t.items[index]
+		// Title without the watched/in-progress marker, which is stale by now.
+		media.ViewCount, media.ViewOffset = 0, 0
+		return history.Session{
+			Key:        media.Key,
+			Title:      media.FormatMediaTitle(),
+			Player:     player,
+			DurationMs: media.Duration,
+		}
+	}
+
+	var sessions []history.Session
+	for index := range t.items {
+		span := t.played[index]
+		if span == nil {
+			continue
+		}
+		s := session(index)
+		s.Started, s.Ended = span.started.Unix(), span.ended.Unix()
+		s.StartMs, s.EndMs = span.startMs, span.endMs
+		sessions = append(sessions, s)
+	}
+
+	if len(sessions) == 0 && len(t.items) > 0 {
+		now := time.Now().Unix()
+		s := session(0)
+		s.Started, s.Ended = now, now
+		s.StartMs, s.EndMs = startMs, startMs
+		sessions = append(sessions, s)
+	}
+	return sessions
+}
+
 // trackLoop is the main tracking loop.
 func (t *Tracker) trackLoop(ctx context.Context, interval time.Duration) {
 	ticker := time.NewTicker(interval)
@@ -121,8 +293,8 @@ func (t *Tracker) trackLoop(ctx context.Context, interval time.Duration) {
 	var lastPos float64
 	lastIndex := -1
 
-	// Wait for MPV to be ready and report initial position
-	// MPV needs time to load the video before time-pos is available
+	// Wait for the player to be ready and report initial position
+	// The player needs time to load the video before its position is available
 	t.waitForReadyAndReport(&lastPos, &lastIndex, ctx)
 
 	for {
@@ -139,10 +311,10 @@ func (t *Tracker) trackLoop(ctx context.Context, interval time.Duration) {
 	}
 }
 
-// waitForReadyAndReport waits for MPV to be ready and reports initial position.
-// MPV needs time to load the video before properties like time-pos are available.
+// waitForReadyAndReport waits for the player to be ready and reports initial position.
+// Players need time to load the video before properties like time-pos are available.
 func (t *Tracker) waitForReadyAndReport(lastPos *float64, lastIndex *int, ctx context.Context) {
-	// Try every second for up to 30 seconds for MPV to be ready
+	// Try every second for up to 30 seconds for the player to be ready
 	for i := 0; i < 30; i++ {
 		select {
 		case <-ctx.Done():
@@ -151,18 +323,18 @@ func (t *Tracker) waitForReadyAndReport(lastPos *float64, lastIndex *int, ctx co
 		}
 
 		// Try to get playlist position
-		playlistPos, err := t.mpv.GetPlaylistPos()
+		playlistPos, err := t.player.GetPlaylistPos()
 		if err != nil {
 			continue
 		}
 
 		// Try to get time position
-		pos, err := t.mpv.GetTimePos()
+		pos, err := t.player.GetTimePos()
 		if err != nil {
 			continue
 		}
 
-		// MPV is ready - report initial position
+		// Player is ready - report initial position
 		*lastIndex = playlistPos
 		*lastPos = pos
 		t.SetIndex(playlistPos)
@@ -173,21 +345,21 @@ func (t *Tracker) waitForReadyAndReport(lastPos *float64, lastIndex *int, ctx co
 
 // tick performs one tracking iteration.
 func (t *Tracker) tick(lastPos *float64, lastIndex *int) {
-	if t.mpv == nil {
+	if t.player == nil {
 		return
 	}
 
 	// Get current playlist position
-	playlistPos, err := t.mpv.GetPlaylistPos()
+	playlistPos, err := t.player.GetPlaylistPos()
 	if err != nil {
-		// MPV may have exited
+		// The player may have exited
 		return
 	}
 
 	// Check if playlist position changed
 	if playlistPos != *lastIndex {
 		// Report final position for previous item
-		if *lastIndex >= 0 && *lastIndex < len(t.items) {
+		if *lastIndex >= 0 && *lastIndex < t.Len() {
 			t.reportPosition(*lastIndex, *lastPos, "stopped")
 		}
 		*lastIndex = playlistPos
@@ -196,24 +368,30 @@ func (t *Tracker) tick(lastPos *float64, lastIndex *int) {
 	}
 
 	// Get current time position
-	pos, err := t.mpv.GetTimePos()
+	pos, err := t.player.GetTimePos()
 	if err != nil {
 		return
 	}
 
-	// Only report if position changed significantly
-	if math.Abs(pos-*lastPos) > minPositionChangeSec {
-		// Get pause state
-		paused, err := t.mpv.GetPaused()
-		if err != nil {
-			paused = false
-		}
+	// Get pause state
+	paused, err := t.player.GetPaused()
+	if err != nil {
+		paused = false
+	}
 
-		state := "playing"
-		if paused {
-			state = "paused"
-		}
+	state := "playing"
+	if paused {
+		state = "paused"
+	}
+
+	// Report if position changed significantly, on pausing or resuming, and
+	// as a heartbeat so a long pause doesn't look like an abandoned session.
+	t.mu.RLock()
+	stateChanged := state != t.lastState
+	heartbeatDue := time.Since(t.lastReport) >= heartbeatInterval
+	t.mu.RUnlock()
 
+	if math.Abs(pos-*lastPos) > minPositionChangeSec || stateChanged || heartbeatDue {
 		t.reportPosition(playlistPos, pos, state)
 		*lastPos = pos
 	}
@@ -221,52 +399,77 @@ func (t *Tracker) tick(lastPos *float64, lastIndex *int) {
 
 // reportPosition reports the current playback position to Plex.
 func (t *Tracker) reportPosition(index int, posSeconds float64, state string) {
+	t.mu.Lock()
 	if index < 0 || index >= len(t.items) {
+		t.mu.Unlock()
 		return
 	}
 
 	media := t.items[index]
 	timeMs := int(posSeconds * 1000)
+	justFinished := media.Duration > 0 && float64(timeMs) >= finishedFraction*float64(media.Duration) && !t.finished[index]
+	if justFinished {
+		t.finished[index] = true
+	}
 
 	// Record the latest position so it can be flushed into the local cache
 	// when playback ends. This happens regardless of whether Plex reporting
 	// is available, so "Continue Watching" stays accurate even offline.
-	t.mu.Lock()
+	now := time.Now()
+	t.lastReport, t.lastState = now, state
 	t.offsets[index] = timeMs
+	if span := t.played[index]; span != nil {
+		span.endMs, span.ended = timeMs, now
+	} else {
+		t.played[index] = &playedSpan{startMs: timeMs, endMs: timeMs, started: now, ended: now}
+	}
+	queueItem, queued := t.queue.Item(index)
 	t.mu.Unlock()
 
+	if t.onReport != nil {
+		t.onReport(media, timeMs, state)
+	}
+	if justFinished && t.onFinished != nil {
+		t.onFinished(index, media)
+	}
+
 	if t.plexClient == nil {
 		return
 	}
 
 	ratingKey := extractRatingKey(media.Key)
-	err := t.plexClient.UpdateTimeline(ratingKey, state, timeMs, media.Duration)
+	var err error
+	if queued {
+		err = t.plexClient.UpdateQueuedTimeline(ratingKey, queueItem, state, timeMs, media.Duration)
+	} else {
+		err = t.plexClient.UpdateTimeline(ratingKey, state, timeMs, media.Duration)
+	}
 	if err != nil {
 		log.Printf("Failed to update timeline: %v", err)
 	}
 }
 
 // reportFinalPosition reports the final position when playback ends.
-// Uses the last known position since MPV may have already exited.
+// Uses the last known position since the player may have already exited.
 func (t *Tracker) reportFinalPosition(lastPos float64, lastIndex int) {
 	if t.plexClient == nil {
 		return
 	}
 
-	// Try to get current position from MPV (may fail if MPV exited)
+	// Try to get current position from the player (may fail if it exited)
 	pos := lastPos
 	index := lastIndex
-	if t.mpv != nil {
-		if currentPos, err := t.mpv.GetTimePos(); err == nil {
+	if t.player != nil {
+		if currentPos, err := t.player.GetTimePos(); err == nil {
 			pos = currentPos
 		}
-		if currentIndex, err := t.mpv.GetPlaylistPos(); err == nil {
+		if currentIndex, err := t.player.GetPlaylistPos(); err == nil {
 			index = currentIndex
 		}
 	}
 
 	// Report final position if we have valid data
-	if index >= 0 && index < len(t.items) {
+	if index >= 0 && index < t.Len() {
 		t.reportPosition(index, pos, "stopped")
 	}
 }
@@ -1,7 +1,10 @@
 package progress
 
 import (
+	"fmt"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/joshkerr/goplexcli/internal/plex"
 )
@@ -65,6 +68,126 @@ func TestTrackerProgress(t *testing.T) {
 	}
 }
 
+func TestTrackerOnReport(t *testing.T) {
+	items := []*plex.MediaItem{
+		{Key: "/library/metadata/1", Title: "Movie 1", Duration: 7200000},
+		{Key: "/library/metadata/2", Title: "Movie 2", Duration: 5400000},
+	}
+
+	tracker := NewTracker(items, nil, nil)
+	var reports []string
+	tracker.OnReport(func(item *plex.MediaItem, posMs int, state string) {
+		reports = append(reports, fmt.Sprintf("%s@%d:%s", item.Title, posMs, state))
+	})
+
+	tracker.reportPosition(0, 90, "playing")
+	tracker.reportPosition(1, 12, "paused")
+	tracker.reportPosition(5, 1, "playing") // out of range: not reported
+
+	want := []string{"Movie 1@90000:playing", "Movie 2@12000:paused"}
+	if !slices.Equal(reports, want) {
+		t.Errorf("reports = %v, want %v", reports, want)
+	}
+}
+
+func TestTrackerOnFinished(t *testing.T) {
+	items := []*plex.MediaItem{
+		{Key: "/library/metadata/1", Title: "Pilot", Duration: 100000},
+	}
+
+	tracker := NewTracker(items, nil, nil)
+	var finished []string
+	tracker.OnFinished(func(index int, item *plex.MediaItem) {
+		finished = append(finished, fmt.Sprintf("%d:%s", index, item.Title))
+	})
+
+	tracker.reportPosition(0, 90, "playing") // 90%: not yet
+	tracker.reportPosition(0, 95, "playing")
+	tracker.reportPosition(0, 99, "playing") // only once per item
+	next := tracker.AddItem(&plex.MediaItem{Key: "/library/metadata/2", Title: "Tabula Rasa", Duration: 100000})
+	tracker.reportPosition(next, 96, "stopped")
+
+	want := []string{"0:Pilot", "1:Tabula Rasa"}
+	if !slices.Equal(finished, want) {
+		t.Errorf("finished = %v, want %v", finished, want)
+	}
+	if tracker.Len() != 2 {
+		t.Errorf("Len = %d, want 2", tracker.Len())
+	}
+}
+
+func TestTrackerHistory(t *testing.T) {
+	items := []*plex.MediaItem{
+		{Key: "/library/metadata/1", Title: "Pilot", Type: "episode", ParentTitle: "Lost", ParentIndex: 1, Index: 1, Duration: 2700000, ViewOffset: 60000},
+		{Key: "/library/metadata/2", Title: "Tabula Rasa", Type: "episode", ParentTitle: "Lost", ParentIndex: 1, Index: 3, Duration: 2640000},
+	}
+
+	tracker := NewTracker(items, nil, nil)
+	tracker.reportPosition(0, 60, "playing")
+	tracker.reportPosition(0, 600, "stopped")
+
+	got := tracker.History("mpv", 60000)
+	if len(got) != 1 {
+		t.Fatalf("expected one session, got %+v", got)
+	}
+	s := got[0]
+	if s.Key != "/library/metadata/1" || s.Player != "mpv" || s.StartMs != 60000 || s.EndMs != 600000 {
+		t.Errorf("unexpected session %+v", s)
+	}
+	if s.Title != "Lost - S01E01 - Pilot" {
+		t.Errorf("title should not carry the progress marker, got %q", s.Title)
+	}
+
+	// An attempt where nothing was tracked is still recorded.
+	got = NewTracker(items, nil, nil).History("vlc", 60000)
+	if len(got) != 1 || got[0].Key != "/library/metadata/1" || got[0].StartMs != 60000 || got[0].EndMs != 60000 {
+		t.Errorf("untracked attempt = %+v", got)
+	}
+}
+
+// fakePlayer is a PlayerClient with fixed state.
+type fakePlayer struct {
+	pos    float64
+	paused bool
+}
+
+func (f *fakePlayer) GetTimePos() (float64, error) { return f.pos, nil }
+func (f *fakePlayer) GetPaused() (bool, error)     { return f.paused, nil }
+func (f *fakePlayer) GetPlaylistPos() (int, error) { return 0, nil }
+
+func TestTrackerPausedHeartbeat(t *testing.T) {
+	items := []*plex.MediaItem{{Key: "/library/metadata/1", Title: "Movie 1", Duration: 7200000}}
+	p := &fakePlayer{pos: 600}
+	tracker := NewTracker(items, p, nil)
+
+	lastPos, lastIndex := 0.0, -1
+	tracker.tick(&lastPos, &lastIndex)
+	if tracker.lastState != "playing" {
+		t.Fatalf("first tick should report playing, got %q", tracker.lastState)
+	}
+
+	// Pausing is reported straight away even though the position is unchanged.
+	p.paused = true
+	tracker.tick(&lastPos, &lastIndex)
+	if tracker.lastState != "paused" {
+		t.Fatalf("pause should be reported, got %q", tracker.lastState)
+	}
+
+	// While paused nothing is sent until the heartbeat is due...
+	reported := tracker.lastReport
+	tracker.tick(&lastPos, &lastIndex)
+	if !tracker.lastReport.Equal(reported) {
+		t.Error("no update expected before the heartbeat interval")
+	}
+
+	// ...and then the paused state is repeated.
+	tracker.lastReport = time.Now().Add(-heartbeatInterval)
+	tracker.tick(&lastPos, &lastIndex)
+	if !tracker.lastReport.After(reported) || tracker.lastState != "paused" {
+		t.Errorf("expected a paused heartbeat, last state %q at %v", tracker.lastState, tracker.lastReport)
+	}
+}
+
 func TestExtractRatingKey(t *testing.T) {
 	tests := []struct {
 		key      string
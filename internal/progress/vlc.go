@@ -0,0 +1,187 @@
+package progress
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// VLCClient reads playback state from VLC's HTTP interface (started with
+// --extraintf=http). VLC only serves it with a password set, which it expects
+// as HTTP Basic Auth with an empty user name.
+type VLCClient struct {
+	baseURL    string
+	password   string
+	httpClient *http.Client
+}
+
+// vlcStatus is the subset of /requests/status.json the tracker needs.
+type vlcStatus struct {
+	Time        float64 `json:"time"`  // seconds into the current item
+	State       string  `json:"state"` // "playing", "paused" or "stopped"
+	CurrentPLID int     `json:"currentplid"`
+}
+
+// vlcNode is a node of /requests/playlist.json. Playable items are "leaf"
+// nodes; their ids are strings even though status.json reports numbers.
+type vlcNode struct {
+	ID       string    `json:"id"`
+	Type     string    `json:"type"`
+	Children []vlcNode `json:"children"`
+}
+
+// NewVLCClient creates a client for a VLC HTTP interface listening on
+// 127.0.0.1:port with the given password.
+func NewVLCClient(port int, password string) *VLCClient {
+	return &VLCClient{
+		baseURL:    fmt.Sprintf("http://127.0.0.1:%d", port),
+		password:   password,
+		httpClient: &http.Client{Timeout: 2 * time.Second},
+	}
+}
+
+// GenerateVLCEndpoint picks a free local port and a random password for VLC's
+// HTTP interface, so each playback session gets its own.
+func GenerateVLCEndpoint() (port int, password string, err error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to find a free port: %w", err)
+	}
+	port = l.Addr().(*net.TCPAddr).Port
+	_ = l.Close()
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return 0, "", fmt.Errorf("failed to generate password: %w", err)
+	}
+	return port, hex.EncodeToString(buf), nil
+}
+
+// ConnectWithContext waits for VLC's HTTP interface to answer. Like the MPV
+// client it retries while VLC starts up, and gives up early if ctx is
+// cancelled (e.g. VLC exited).
+func (c *VLCClient) ConnectWithContext(ctx context.Context) error {
+	var lastErr error
+	for i := 0; i < maxConnectRetries; i++ {
+		if _, lastErr = c.status(ctx); lastErr == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(connectRetryDelay):
+		}
+	}
+	return fmt.Errorf("failed to connect to VLC HTTP interface after retries: %w", lastErr)
+}
+
+// Close is a no-op; the HTTP interface needs no persistent connection.
+func (c *VLCClient) Close() error {
+	return nil
+}
+
+// GetTimePos returns the current playback position in seconds.
+func (c *VLCClient) GetTimePos() (float64, error) {
+	st, err := c.status(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	if st.State == "stopped" {
+		return 0, fmt.Errorf("vlc is not playing")
+	}
+	return st.Time, nil
+}
+
+// GetPaused returns whether playback is paused.
+func (c *VLCClient) GetPaused() (bool, error) {
+	st, err := c.status(context.Background())
+	if err != nil {
+		return false, err
+	}
+	return st.State == "paused", nil
+}
+
+// GetPlaylistPos returns the 0-based index of the current playlist item.
+func (c *VLCClient) GetPlaylistPos() (int, error) {
+	st, err := c.status(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	if st.CurrentPLID < 0 {
+		return 0, fmt.Errorf("vlc is not playing")
+	}
+
+	var root vlcNode
+	if err := c.get(context.Background(), "/requests/playlist.json", &root); err != nil {
+		return 0, err
+	}
+	idx := vlcPlaylistIndex(root, fmt.Sprint(st.CurrentPLID))
+	if idx < 0 {
+		return 0, fmt.Errorf("vlc playlist item %d not found", st.CurrentPLID)
+	}
+	return idx, nil
+}
+
+// vlcPlaylistIndex returns the position of the leaf with the given id within
+// the playlist, or -1. The playlist is the first child of the root; the
+// second is the media library, which must not be counted.
+func vlcPlaylistIndex(root vlcNode, id string) int {
+	if len(root.Children) == 0 {
+		return -1
+	}
+	idx := 0
+	var walk func(n vlcNode) int
+	walk = func(n vlcNode) int {
+		if n.Type == "leaf" {
+			if n.ID == id {
+				return idx
+			}
+			idx++
+			return -1
+		}
+		for _, child := range n.Children {
+			if found := walk(child); found >= 0 {
+				return found
+			}
+		}
+		return -1
+	}
+	return walk(root.Children[0])
+}
+
+func (c *VLCClient) status(ctx context.Context) (*vlcStatus, error) {
+	var st vlcStatus
+	if err := c.get(ctx, "/requests/status.json", &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// get fetches a VLC JSON endpoint into v.
+func (c *VLCClient) get(ctx context.Context, path string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth("", c.password)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("vlc request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("vlc returned status %d for %s", resp.StatusCode, path)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode vlc response: %w", err)
+	}
+	return nil
+}
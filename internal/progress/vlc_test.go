@@ -0,0 +1,104 @@
+package progress
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeVLC serves canned status.json and playlist.json responses, checking the
+// password like VLC does.
+func fakeVLC(t *testing.T, status string) *VLCClient {
+	t.Helper()
+	playlist := `{"type":"node","id":"0","children":[
+		{"type":"node","id":"1","name":"Playlist","children":[
+			{"type":"leaf","id":"4","name":"Episode 1"},
+			{"type":"leaf","id":"5","name":"Episode 2"}]},
+		{"type":"node","id":"2","name":"Media Library","children":[
+			{"type":"leaf","id":"9","name":"Other"}]}]}`
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pass, ok := r.BasicAuth(); !ok || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/requests/status.json":
+			_, _ = w.Write([]byte(status))
+		case "/requests/playlist.json":
+			_, _ = w.Write([]byte(playlist))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return &VLCClient{baseURL: srv.URL, password: "secret", httpClient: srv.Client()}
+}
+
+func TestVLCClientState(t *testing.T) {
+	c := fakeVLC(t, `{"time":754,"length":2700,"state":"paused","currentplid":5}`)
+
+	pos, err := c.GetTimePos()
+	if err != nil || pos != 754 {
+		t.Errorf("GetTimePos() = %v, %v; want 754", pos, err)
+	}
+	paused, err := c.GetPaused()
+	if err != nil || !paused {
+		t.Errorf("GetPaused() = %v, %v; want true", paused, err)
+	}
+	idx, err := c.GetPlaylistPos()
+	if err != nil || idx != 1 {
+		t.Errorf("GetPlaylistPos() = %v, %v; want 1", idx, err)
+	}
+}
+
+func TestVLCClientStopped(t *testing.T) {
+	c := fakeVLC(t, `{"time":0,"length":0,"state":"stopped","currentplid":-1}`)
+
+	if _, err := c.GetTimePos(); err == nil {
+		t.Error("GetTimePos() should fail when VLC is stopped")
+	}
+	if _, err := c.GetPlaylistPos(); err == nil {
+		t.Error("GetPlaylistPos() should fail when nothing is playing")
+	}
+}
+
+func TestVLCClientWrongPassword(t *testing.T) {
+	c := fakeVLC(t, `{}`)
+	c.password = "wrong"
+	if _, err := c.GetPaused(); err == nil {
+		t.Error("expected an error for a rejected password")
+	}
+}
+
+func TestVLCPlaylistIndex(t *testing.T) {
+	root := vlcNode{Children: []vlcNode{
+		{Type: "node", Children: []vlcNode{{Type: "leaf", ID: "3"}, {Type: "leaf", ID: "7"}}},
+		{Type: "node", Children: []vlcNode{{Type: "leaf", ID: "8"}}},
+	}}
+	if got := vlcPlaylistIndex(root, "7"); got != 1 {
+		t.Errorf("vlcPlaylistIndex(7) = %d, want 1", got)
+	}
+	if got := vlcPlaylistIndex(root, "8"); got != -1 {
+		t.Errorf("media library items must not count: vlcPlaylistIndex(8) = %d, want -1", got)
+	}
+	if got := vlcPlaylistIndex(vlcNode{}, "3"); got != -1 {
+		t.Errorf("empty tree: got %d, want -1", got)
+	}
+}
+
+func TestGenerateVLCEndpoint(t *testing.T) {
+	port, pass, err := GenerateVLCEndpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if port <= 0 || len(pass) != 32 {
+		t.Errorf("GenerateVLCEndpoint() = %d, %q", port, pass)
+	}
+}
+
+var (
+	_ Connector = (*MPVClient)(nil)
+	_ Connector = (*VLCClient)(nil)
+)
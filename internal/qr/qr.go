@@ -0,0 +1,503 @@
+// Package qr encodes short text, such as a LAN URL, as a QR code and draws
+// it in the terminal so a phone can open it with one camera scan.
+//
+// It implements just what that needs: byte mode, error correction level M,
+// and versions 1 to 10 (up to 213 bytes).
+package qr
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrTooLong is returned when the text doesn't fit in a version 10 code.
+var ErrTooLong = errors.New("text too long for a QR code")
+
+// Code is an encoded QR symbol.
+type Code struct {
+	// Size is the width and height in modules, without the quiet zone.
+	Size    int
+	modules [][]bool
+}
+
+// Dark reports whether the module at column x, row y is dark. Coordinates
+// outside the symbol are light, like the quiet zone around it.
+func (c *Code) Dark(x, y int) bool {
+	if x < 0 || y < 0 || x >= c.Size || y >= c.Size {
+		return false
+	}
+	return c.modules[y][x]
+}
+
+// blockLayout is the error correction structure of one version at level M:
+// groups of blocks with their data codeword counts, each block followed by
+// ecLen error correction codewords.
+type blockLayout struct {
+	ecLen  int
+	groups [][2]int // {number of blocks, data codewords per block}
+}
+
+var layouts = [...]blockLayout{
+	1:  {10, [][2]int{{1, 16}}},
+	2:  {16, [][2]int{{1, 28}}},
+	3:  {26, [][2]int{{1, 44}}},
+	4:  {18, [][2]int{{2, 32}}},
+	5:  {24, [][2]int{{2, 43}}},
+	6:  {16, [][2]int{{4, 27}}},
+	7:  {18, [][2]int{{4, 31}}},
+	8:  {22, [][2]int{{2, 38}, {2, 39}}},
+	9:  {22, [][2]int{{3, 36}, {2, 37}}},
+	10: {26, [][2]int{{4, 43}, {1, 44}}},
+}
+
+var alignmentPositions = [...][]int{
+	2:  {6, 18},
+	3:  {6, 22},
+	4:  {6, 26},
+	5:  {6, 30},
+	6:  {6, 34},
+	7:  {6, 22, 38},
+	8:  {6, 24, 42},
+	9:  {6, 26, 46},
+	10: {6, 28, 50},
+}
+
+func (l blockLayout) dataLen() int {
+	n := 0
+	for _, g := range l.groups {
+		n += g[0] * g[1]
+	}
+	return n
+}
+
+// Encode encodes text in the smallest version that holds it.
+func Encode(text string) (*Code, error) {
+	data := []byte(text)
+	version := 0
+	for v := 1; v < len(layouts); v++ {
+		if 4+countBits(v)+8*len(data) <= 8*layouts[v].dataLen() {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrTooLong
+	}
+
+	s := newSymbol(version)
+	s.placeData(interleave(layouts[version], encodeData(data, version)))
+
+	// Pick the mask with the lowest penalty, as the standard asks.
+	best, bestScore := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		s.applyMask(mask)
+		s.drawFormat(mask)
+		if score := s.penalty(); bestScore < 0 || score < bestScore {
+			best, bestScore = mask, score
+		}
+		s.applyMask(mask) // XOR again to undo
+	}
+	s.applyMask(best)
+	s.drawFormat(best)
+
+	return &Code{Size: s.size, modules: s.modules}, nil
+}
+
+func countBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+// encodeData builds the data codewords: byte mode indicator, character
+// count, the bytes, a terminator, and the alternating pad bytes.
+func encodeData(data []byte, version int) []byte {
+	capacity := layouts[version].dataLen()
+	var bw bitWriter
+	bw.write(0x4, 4)
+	bw.write(len(data), countBits(version))
+	for _, b := range data {
+		bw.write(int(b), 8)
+	}
+	terminator := 8*capacity - bw.n
+	if terminator > 4 {
+		terminator = 4
+	}
+	bw.write(0, terminator)
+	if rem := bw.n % 8; rem != 0 {
+		bw.write(0, 8-rem)
+	}
+	for pad := 0; len(bw.bytes) < capacity; pad++ {
+		bw.bytes = append(bw.bytes, [2]byte{0xEC, 0x11}[pad%2])
+	}
+	return bw.bytes
+}
+
+type bitWriter struct {
+	bytes []byte
+	n     int // bits written
+}
+
+func (w *bitWriter) write(value, bits int) {
+	for i := bits - 1; i >= 0; i-- {
+		if w.n%8 == 0 {
+			w.bytes = append(w.bytes, 0)
+		}
+		if value>>i&1 == 1 {
+			w.bytes[len(w.bytes)-1] |= 0x80 >> (w.n % 8)
+		}
+		w.n++
+	}
+}
+
+// interleave splits data into blocks, appends each block's error
+// correction codewords, and interleaves the lot in the symbol's order.
+func interleave(layout blockLayout, data []byte) []byte {
+	var blocks, ecBlocks [][]byte
+	for _, g := range layout.groups {
+		for i := 0; i < g[0]; i++ {
+			block := data[:g[1]]
+			data = data[g[1]:]
+			blocks = append(blocks, block)
+			ecBlocks = append(ecBlocks, reedSolomon(block, layout.ecLen))
+		}
+	}
+
+	var out []byte
+	longest := layout.groups[len(layout.groups)-1][1]
+	for i := 0; i < longest; i++ {
+		for _, b := range blocks {
+			if i < len(b) {
+				out = append(out, b[i])
+			}
+		}
+	}
+	for i := 0; i < layout.ecLen; i++ {
+		for _, b := range ecBlocks {
+			out = append(out, b[i])
+		}
+	}
+	return out
+}
+
+// gfMul multiplies in GF(256) with the QR polynomial x^8+x^4+x^3+x^2+1.
+func gfMul(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11D
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
+
+// reedSolomon returns the n error correction codewords for data.
+func reedSolomon(data []byte, n int) []byte {
+	// Generator polynomial (x - α^0)(x - α^1)...(x - α^(n-1)), leading
+	// coefficient dropped.
+	gen := make([]byte, n)
+	gen[n-1] = 1
+	root := byte(1)
+	for i := 0; i < n; i++ {
+		for j := range gen {
+			gen[j] = gfMul(gen[j], root)
+			if j+1 < n {
+				gen[j] ^= gen[j+1]
+			}
+		}
+		root = gfMul(root, 2)
+	}
+
+	rem := make([]byte, n)
+	for _, b := range data {
+		factor := b ^ rem[0]
+		copy(rem, rem[1:])
+		rem[n-1] = 0
+		for i := range rem {
+			rem[i] ^= gfMul(gen[i], factor)
+		}
+	}
+	return rem
+}
+
+// symbol is a QR matrix under construction. reserved marks function
+// modules, which data and masks leave alone.
+type symbol struct {
+	version  int
+	size     int
+	modules  [][]bool
+	reserved [][]bool
+}
+
+func newSymbol(version int) *symbol {
+	size := 17 + 4*version
+	s := &symbol{version: version, size: size}
+	s.modules = make([][]bool, size)
+	s.reserved = make([][]bool, size)
+	for i := range s.modules {
+		s.modules[i] = make([]bool, size)
+		s.reserved[i] = make([]bool, size)
+	}
+
+	for i := 0; i < size; i++ {
+		s.set(6, i, i%2 == 0)
+		s.set(i, 6, i%2 == 0)
+	}
+	s.drawFinder(3, 3)
+	s.drawFinder(size-4, 3)
+	s.drawFinder(3, size-4)
+
+	pos := alignmentPositions[version]
+	for i, x := range pos {
+		for j, y := range pos {
+			// Skip the three corners the finders occupy.
+			if i == 0 && j == 0 || i == 0 && j == len(pos)-1 || i == len(pos)-1 && j == 0 {
+				continue
+			}
+			s.drawAlignment(x, y)
+		}
+	}
+
+	// Reserve the format areas now; drawFormat fills them per mask.
+	s.drawFormat(0)
+	s.drawVersion()
+	return s
+}
+
+func (s *symbol) set(x, y int, dark bool) {
+	s.modules[y][x] = dark
+	s.reserved[y][x] = true
+}
+
+// drawFinder draws a finder pattern centred on (cx, cy), with its light
+// separator ring.
+func (s *symbol) drawFinder(cx, cy int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			x, y := cx+dx, cy+dy
+			if x < 0 || y < 0 || x >= s.size || y >= s.size {
+				continue
+			}
+			d := max(abs(dx), abs(dy))
+			s.set(x, y, d != 2 && d != 4)
+		}
+	}
+}
+
+func (s *symbol) drawAlignment(cx, cy int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			s.set(cx+dx, cy+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// formatBits returns the 15-bit format information for level M and mask.
+func formatBits(mask int) int {
+	data := 0<<3 | mask // level M is 00
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+func (s *symbol) drawFormat(mask int) {
+	bits := formatBits(mask)
+	bit := func(i int) bool { return bits>>i&1 == 1 }
+
+	// Around the top-left finder.
+	for i := 0; i <= 5; i++ {
+		s.set(8, i, bit(i))
+	}
+	s.set(8, 7, bit(6))
+	s.set(8, 8, bit(7))
+	s.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		s.set(14-i, 8, bit(i))
+	}
+
+	// Split between the other two finders.
+	for i := 0; i < 8; i++ {
+		s.set(s.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		s.set(8, s.size-15+i, bit(i))
+	}
+	s.set(8, s.size-8, true) // the dark module
+}
+
+// drawVersion draws the version information versions 7 and up carry.
+func (s *symbol) drawVersion() {
+	if s.version < 7 {
+		return
+	}
+	rem := s.version
+	for i := 0; i < 12; i++ {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+	bits := s.version<<12 | rem
+	for i := 0; i < 18; i++ {
+		dark := bits>>i&1 == 1
+		a, b := s.size-11+i%3, i/3
+		s.set(a, b, dark)
+		s.set(b, a, dark)
+	}
+}
+
+// placeData fills the non-function modules with codewords in the zigzag
+// order: two-module columns from the right, alternately up and down,
+// skipping the vertical timing pattern.
+func (s *symbol) placeData(codewords []byte) {
+	i := 0
+	for right := s.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < s.size; vert++ {
+			y := vert
+			if upward {
+				y = s.size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if s.reserved[y][x] || i >= len(codewords)*8 {
+					continue
+				}
+				s.modules[y][x] = codewords[i>>3]>>(7-i&7)&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+func (s *symbol) applyMask(mask int) {
+	for y := 0; y < s.size; y++ {
+		for x := 0; x < s.size; x++ {
+			if s.reserved[y][x] {
+				continue
+			}
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert {
+				s.modules[y][x] = !s.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores the symbol by the standard's four rules: long runs,
+// 2x2 blocks, finder look-alikes, and dark/light imbalance.
+func (s *symbol) penalty() int {
+	score := 0
+	at := func(x, y int, transpose bool) bool {
+		if transpose {
+			return s.modules[x][y]
+		}
+		return s.modules[y][x]
+	}
+
+	for _, transpose := range []bool{false, true} {
+		for y := 0; y < s.size; y++ {
+			run := 1
+			for x := 1; x < s.size; x++ {
+				if at(x, y, transpose) == at(x-1, y, transpose) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					score += run - 2
+				}
+				run = 1
+			}
+			if run >= 5 {
+				score += run - 2
+			}
+
+			// 1:1:3:1:1 dark-light pattern with four light modules on a side.
+			for x := 0; x+11 <= s.size; x++ {
+				var line [11]bool
+				for k := range line {
+					line[k] = at(x+k, y, transpose)
+				}
+				if line == [11]bool{true, false, true, true, true, false, true, false, false, false, false} ||
+					line == [11]bool{false, false, false, false, true, false, true, true, true, false, true} {
+					score += 40
+				}
+			}
+		}
+	}
+
+	dark := 0
+	for y := 0; y < s.size; y++ {
+		for x := 0; x < s.size; x++ {
+			if s.modules[y][x] {
+				dark++
+			}
+			if x+1 < s.size && y+1 < s.size {
+				c := s.modules[y][x]
+				if c == s.modules[y][x+1] && c == s.modules[y+1][x] && c == s.modules[y+1][x+1] {
+					score += 3
+				}
+			}
+		}
+	}
+	total := s.size * s.size
+	score += abs(dark*20-total*10) / total * 10
+	return score
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// quietZone is the light border, in modules, drawn around the symbol.
+const quietZone = 2
+
+// String draws the code with Unicode half blocks, two rows per line, for a
+// terminal with light text on a dark background: light modules are drawn
+// and dark ones left blank, so phones see the usual dark-on-light symbol.
+func (c *Code) String() string {
+	var b strings.Builder
+	for y := -quietZone; y < c.Size+quietZone; y += 2 {
+		for x := -quietZone; x < c.Size+quietZone; x++ {
+			top, bottom := !c.Dark(x, y), !c.Dark(x, y+1)
+			if y+1 >= c.Size+quietZone {
+				bottom = false
+			}
+			switch {
+			case top && bottom:
+				b.WriteRune('█')
+			case top:
+				b.WriteRune('▀')
+			case bottom:
+				b.WriteRune('▄')
+			default:
+				b.WriteRune(' ')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
@@ -0,0 +1,238 @@
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReedSolomon(t *testing.T) {
+	// "HELLO WORLD" at 1-M, from the worked example in the standard's
+	// tutorials.
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := reedSolomon(data, 10); !bytes.Equal(got, want) {
+		t.Errorf("reedSolomon = %v, want %v", got, want)
+	}
+}
+
+func TestFormatBits(t *testing.T) {
+	for mask, want := range map[int]int{
+		0: 0b101010000010010,
+		5: 0b100000011001110,
+		7: 0b100101010100000,
+	} {
+		if got := formatBits(mask); got != want {
+			t.Errorf("formatBits(%d) = %015b, want %015b", mask, got, want)
+		}
+	}
+}
+
+func TestEncodeVersions(t *testing.T) {
+	for _, tc := range []struct {
+		n, size int
+	}{
+		{1, 21},
+		{14, 21},
+		{15, 25},
+		{84, 37},
+		{213, 57},
+	} {
+		c, err := Encode(strings.Repeat("a", tc.n))
+		if err != nil {
+			t.Fatalf("Encode(%d bytes): %v", tc.n, err)
+		}
+		if c.Size != tc.size {
+			t.Errorf("Encode(%d bytes): size = %d, want %d", tc.n, c.Size, tc.size)
+		}
+	}
+	if _, err := Encode(strings.Repeat("a", 214)); !errors.Is(err, ErrTooLong) {
+		t.Errorf("Encode(214 bytes) error = %v, want ErrTooLong", err)
+	}
+}
+
+// TestRoundTrip reads encoded symbols back the way a scanner would: format
+// information, unmasking, the zigzag walk and de-interleaving, then checks
+// the text and every block's error correction.
+func TestRoundTrip(t *testing.T) {
+	for _, text := range []string{
+		"x",
+		"http://192.168.1.20:8765",
+		"https://192.168.1.20:8765/?token=0123456789abcdef0123456789abcdef",
+		strings.Repeat("goplexcli ", 21),
+	} {
+		c, err := Encode(text)
+		if err != nil {
+			t.Fatalf("Encode(%q): %v", text, err)
+		}
+		version := (c.Size - 17) / 4
+		s := newSymbol(version)
+
+		// Both copies of the format information must agree.
+		var first, second int
+		for i := 0; i <= 5; i++ {
+			first |= b2i(c.Dark(8, i)) << i
+		}
+		first |= b2i(c.Dark(8, 7))<<6 | b2i(c.Dark(8, 8))<<7 | b2i(c.Dark(7, 8))<<8
+		for i := 9; i < 15; i++ {
+			first |= b2i(c.Dark(14-i, 8)) << i
+		}
+		for i := 0; i < 8; i++ {
+			second |= b2i(c.Dark(c.Size-1-i, 8)) << i
+		}
+		for i := 8; i < 15; i++ {
+			second |= b2i(c.Dark(8, c.Size-15+i)) << i
+		}
+		if first != second {
+			t.Fatalf("%q: format copies differ: %015b vs %015b", text, first, second)
+		}
+		mask := -1
+		for m := 0; m < 8; m++ {
+			if formatBits(m) == first {
+				mask = m
+			}
+		}
+		if mask < 0 {
+			t.Fatalf("%q: unreadable format information %015b", text, first)
+		}
+
+		for y := range s.modules {
+			copy(s.modules[y], c.modules[y])
+		}
+		s.applyMask(mask)
+		codewords := readData(s)
+
+		layout := layouts[version]
+		var blocks [][]byte
+		for _, g := range layout.groups {
+			for i := 0; i < g[0]; i++ {
+				blocks = append(blocks, make([]byte, 0, g[1]+layout.ecLen))
+			}
+		}
+		pos := 0
+		longest := layout.groups[len(layout.groups)-1][1]
+		for i := 0; i < longest; i++ {
+			for b := range blocks {
+				if i < cap(blocks[b])-layout.ecLen {
+					blocks[b] = append(blocks[b], codewords[pos])
+					pos++
+				}
+			}
+		}
+		var data []byte
+		for b := range blocks {
+			data = append(data, blocks[b]...)
+		}
+		for i := 0; i < layout.ecLen; i++ {
+			for b := range blocks {
+				blocks[b] = append(blocks[b], codewords[pos])
+				pos++
+			}
+		}
+		for b, block := range blocks {
+			n := len(block) - layout.ecLen
+			if got := reedSolomon(block[:n], layout.ecLen); !bytes.Equal(got, block[n:]) {
+				t.Errorf("%q: block %d error correction doesn't match", text, b)
+			}
+		}
+
+		// Byte mode header, then the text.
+		r := bitReader{data: data}
+		if mode := r.read(4); mode != 0x4 {
+			t.Fatalf("%q: mode = %x, want byte mode", text, mode)
+		}
+		n := r.read(countBits(version))
+		got := make([]byte, n)
+		for i := range got {
+			got[i] = byte(r.read(8))
+		}
+		if string(got) != text {
+			t.Errorf("decoded %q, want %q", got, text)
+		}
+	}
+}
+
+func readData(s *symbol) []byte {
+	var out []byte
+	i := 0
+	for right := s.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < s.size; vert++ {
+			y := vert
+			if upward {
+				y = s.size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if s.reserved[y][x] {
+					continue
+				}
+				if i%8 == 0 {
+					out = append(out, 0)
+				}
+				if s.modules[y][x] {
+					out[len(out)-1] |= 0x80 >> (i % 8)
+				}
+				i++
+			}
+		}
+	}
+	return out
+}
+
+type bitReader struct {
+	data []byte
+	n    int
+}
+
+func (r *bitReader) read(bits int) int {
+	v := 0
+	for i := 0; i < bits; i++ {
+		v = v<<1 | int(r.data[r.n/8]>>(7-r.n%8)&1)
+		r.n++
+	}
+	return v
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func TestFinderPatterns(t *testing.T) {
+	c, err := Encode("hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, corner := range [][2]int{{0, 0}, {c.Size - 7, 0}, {0, c.Size - 7}} {
+		for dy := 0; dy < 7; dy++ {
+			for dx := 0; dx < 7; dx++ {
+				d := max(abs(dx-3), abs(dy-3))
+				if want := d != 2; c.Dark(corner[0]+dx, corner[1]+dy) != want {
+					t.Fatalf("finder at %v: module (%d,%d) dark = %t", corner, dx, dy, !want)
+				}
+			}
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	c, err := Encode("hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(c.String(), "\n"), "\n")
+	if want := (c.Size + 2*quietZone + 1) / 2; len(lines) != want {
+		t.Errorf("%d lines, want %d", len(lines), want)
+	}
+	// The quiet zone is drawn light: a full row of blocks on top.
+	if want := strings.Repeat("█", c.Size+2*quietZone); lines[0] != want {
+		t.Errorf("first line = %q, want %q", lines[0], want)
+	}
+}
@@ -1,24 +1,13 @@
 package queue
 
 import (
-	"context"
-	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
 	"time"
 
-	"github.com/gofrs/flock"
 	"github.com/joshkerr/goplexcli/internal/config"
 	"github.com/joshkerr/goplexcli/internal/plex"
-)
-
-const (
-	// lockTimeout is the maximum time to wait for acquiring a lock.
-	// Kept short (5s) so users don't wait too long if another instance crashes while holding the lock.
-	lockTimeout = 5 * time.Second
-	// lockRetryInterval is how often to retry acquiring a lock
-	lockRetryInterval = 100 * time.Millisecond
+	"github.com/joshkerr/goplexcli/internal/storage"
 )
 
 // Queue represents a persistent download queue
@@ -31,22 +20,38 @@ type Queue struct {
 // When non-empty, it's used instead of config.GetCacheDir().
 var testQueueDir string
 
+// file returns the queue's state file.
+func file() (storage.File[Queue], error) {
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return storage.File[Queue]{}, err
+	}
+	return storage.File[Queue]{
+		Path:   filepath.Join(cacheDir, "queue.json"),
+		Name:   "queue",
+		Indent: true,
+		New: func() *Queue {
+			return &Queue{Items: []*plex.MediaItem{}, LastUpdated: time.Time{}}
+		},
+	}, nil
+}
+
 // GetQueuePath returns the path to the queue file
 func GetQueuePath() (string, error) {
-	cacheDir, err := getCacheDir()
+	f, err := file()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(cacheDir, "queue.json"), nil
+	return f.Path, nil
 }
 
 // GetLockPath returns the path to the queue lock file
 func GetLockPath() (string, error) {
-	cacheDir, err := getCacheDir()
+	f, err := file()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(cacheDir, "queue.lock"), nil
+	return f.LockPath(), nil
 }
 
 // getCacheDir returns the cache directory, using testQueueDir if set (for testing)
@@ -59,53 +64,21 @@ func getCacheDir() (string, error) {
 
 // withLock executes a function while holding a lock on the queue.
 // If exclusive is true, acquires an exclusive (write) lock; otherwise acquires a shared (read) lock.
-func withLock(exclusive bool, fn func() error) error {
-	lockPath, err := GetLockPath()
-	if err != nil {
-		return fmt.Errorf("failed to acquire queue lock: %w", err)
-	}
-
-	// For exclusive locks, ensure the cache directory exists (needed for write operations)
-	if exclusive {
-		cacheDir, err := getCacheDir()
-		if err != nil {
-			return fmt.Errorf("failed to acquire queue lock: %w", err)
-		}
-		if err := os.MkdirAll(cacheDir, 0755); err != nil {
-			return fmt.Errorf("failed to acquire queue lock: %w", err)
-		}
-	}
-
-	fileLock := flock.New(lockPath)
-	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
-	defer cancel()
-
-	var locked bool
-	if exclusive {
-		locked, err = fileLock.TryLockContext(ctx, lockRetryInterval)
-	} else {
-		locked, err = fileLock.TryRLockContext(ctx, lockRetryInterval)
-	}
+func withLock(exclusive bool, fn func(f storage.File[Queue]) error) error {
+	f, err := file()
 	if err != nil {
 		return fmt.Errorf("failed to acquire queue lock: %w", err)
 	}
-	if !locked {
-		return fmt.Errorf("failed to acquire queue lock within %v (another instance may be using the queue)", lockTimeout)
-	}
-	defer func() {
-		_ = fileLock.Unlock() // Error intentionally ignored - lock released on process exit regardless
-	}()
-
-	return fn()
+	return f.Lock(exclusive, func() error { return fn(f) })
 }
 
 // withExclusiveLock executes a function while holding an exclusive lock on the queue
-func withExclusiveLock(fn func() error) error {
+func withExclusiveLock(fn func(f storage.File[Queue]) error) error {
 	return withLock(true, fn)
 }
 
 // withSharedLock executes a function while holding a shared (read) lock on the queue
-func withSharedLock(fn func() error) error {
+func withSharedLock(fn func(f storage.File[Queue]) error) error {
 	return withLock(false, fn)
 }
 
@@ -113,28 +86,10 @@ func withSharedLock(fn func() error) error {
 func Load() (*Queue, error) {
 	var q *Queue
 
-	err := withSharedLock(func() error {
-		queuePath, err := GetQueuePath()
-		if err != nil {
-			return err
-		}
-
-		data, err := os.ReadFile(queuePath)
-		if err != nil {
-			if os.IsNotExist(err) {
-				q = &Queue{Items: []*plex.MediaItem{}, LastUpdated: time.Time{}}
-				return nil
-			}
-			return err
-		}
-
-		var loaded Queue
-		if err := json.Unmarshal(data, &loaded); err != nil {
-			return err
-		}
-
-		q = &loaded
-		return nil
+	err := withSharedLock(func(f storage.File[Queue]) error {
+		var err error
+		q, _, err = f.Load()
+		return err
 	})
 
 	if err != nil {
@@ -146,55 +101,21 @@ func Load() (*Queue, error) {
 
 // Save writes the queue to disk with exclusive lock and atomic write for concurrent safety
 func (q *Queue) Save() error {
-	return withExclusiveLock(func() error {
-		queuePath, err := GetQueuePath()
-		if err != nil {
-			return err
+	return withExclusiveLock(func(f storage.File[Queue]) error {
+		q.LastUpdated = time.Now().UTC()
+		if err := f.Save(q); err != nil {
+			return fmt.Errorf("failed to write queue: %w", err)
 		}
-
-		q.LastUpdated = time.Now()
-
-		data, err := json.MarshalIndent(q, "", "  ")
-		if err != nil {
-			return err
-		}
-
-		// Atomic write: write to temp file then rename
-		tempPath := queuePath + ".tmp"
-		if err := os.WriteFile(tempPath, data, 0644); err != nil {
-			return fmt.Errorf("failed to write temp file: %w", err)
-		}
-
-		if err := os.Rename(tempPath, queuePath); err != nil {
-			// Clean up temp file on rename failure (error ignored - best effort cleanup)
-			_ = os.Remove(tempPath)
-			return fmt.Errorf("failed to rename temp file: %w", err)
-		}
-
 		return nil
 	})
 }
 
 // Clear removes all items from the queue and deletes the file with exclusive lock
 func (q *Queue) Clear() error {
-	return withExclusiveLock(func() error {
+	return withExclusiveLock(func(f storage.File[Queue]) error {
 		q.Items = []*plex.MediaItem{}
-		q.LastUpdated = time.Now()
-
-		queuePath, err := GetQueuePath()
-		if err != nil {
-			return err
-		}
-
-		// Remove the file if it exists
-		if err := os.Remove(queuePath); err != nil && !os.IsNotExist(err) {
-			return err
-		}
-
-		// Clean up any stale temp file (error ignored - best effort cleanup)
-		_ = os.Remove(queuePath + ".tmp")
-
-		return nil
+		q.LastUpdated = time.Now().UTC()
+		return f.Remove()
 	})
 }
 
@@ -268,27 +189,17 @@ func (q *Queue) RemoveByKeys(keys []string) error {
 		return nil
 	}
 
-	return withExclusiveLock(func() error {
-		queuePath, err := GetQueuePath()
-		if err != nil {
-			return err
-		}
-
+	return withExclusiveLock(func(f storage.File[Queue]) error {
 		// Reload queue from disk to get current state (including items added by other instances)
-		data, err := os.ReadFile(queuePath)
+		diskQueue, found, err := f.Load()
 		if err != nil {
-			if os.IsNotExist(err) {
-				// Queue file doesn't exist, nothing to remove
-				q.Items = []*plex.MediaItem{}
-				q.LastUpdated = time.Now()
-				return nil
-			}
 			return err
 		}
-
-		var diskQueue Queue
-		if err := json.Unmarshal(data, &diskQueue); err != nil {
-			return err
+		if !found {
+			// Queue file doesn't exist, nothing to remove
+			q.Items = []*plex.MediaItem{}
+			q.LastUpdated = time.Now().UTC()
+			return nil
 		}
 
 		// Build set of keys to remove
@@ -307,35 +218,17 @@ func (q *Queue) RemoveByKeys(keys []string) error {
 
 		// Update in-memory queue
 		q.Items = remaining
-		q.LastUpdated = time.Now()
+		q.LastUpdated = time.Now().UTC()
 
 		// If queue is empty, delete the file
 		if len(remaining) == 0 {
-			if err := os.Remove(queuePath); err != nil && !os.IsNotExist(err) {
-				return err
-			}
-			// Clean up any stale temp file (error ignored - best effort cleanup)
-			_ = os.Remove(queuePath + ".tmp")
-			return nil
+			return f.Remove()
 		}
 
 		// Save remaining items back to disk with atomic write
-		data, err = json.MarshalIndent(q, "", "  ")
-		if err != nil {
-			return err
+		if err := f.Save(q); err != nil {
+			return fmt.Errorf("failed to write queue: %w", err)
 		}
-
-		tempPath := queuePath + ".tmp"
-		if err := os.WriteFile(tempPath, data, 0644); err != nil {
-			return fmt.Errorf("failed to write temp file: %w", err)
-		}
-
-		if err := os.Rename(tempPath, queuePath); err != nil {
-			// Clean up temp file on rename failure (error ignored - best effort cleanup)
-			_ = os.Remove(tempPath)
-			return fmt.Errorf("failed to rename temp file: %w", err)
-		}
-
 		return nil
 	})
 }
@@ -0,0 +1,102 @@
+package queue
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Window is a daily time-of-day range during which the queue worker is
+// allowed to start downloads. Start and End are offsets from local midnight.
+// A window whose End is before its Start wraps past midnight (e.g. 22:00-06:00).
+type Window struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// ParseClock parses a 24-hour "HH:MM" time of day into an offset from midnight.
+func ParseClock(s string) (time.Duration, error) {
+	hh, mm, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok {
+		return 0, fmt.Errorf("invalid time %q: expected HH:MM", s)
+	}
+	h, err := strconv.Atoi(hh)
+	if err != nil || h < 0 || h > 23 {
+		return 0, fmt.Errorf("invalid time %q: hour must be 00-23", s)
+	}
+	m, err := strconv.Atoi(mm)
+	if err != nil || m < 0 || m > 59 || len(mm) != 2 {
+		return 0, fmt.Errorf("invalid time %q: minute must be 00-59", s)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// ParseWindow parses a "HH:MM-HH:MM" range, e.g. "01:00-07:00".
+func ParseWindow(s string) (Window, error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return Window{}, fmt.Errorf("invalid window %q: expected HH:MM-HH:MM", s)
+	}
+	start, err := ParseClock(from)
+	if err != nil {
+		return Window{}, err
+	}
+	end, err := ParseClock(to)
+	if err != nil {
+		return Window{}, err
+	}
+	if start == end {
+		return Window{}, fmt.Errorf("invalid window %q: start and end are the same", s)
+	}
+	return Window{Start: start, End: end}, nil
+}
+
+// Contains reports whether t falls inside the window (start inclusive, end exclusive).
+func (w Window) Contains(t time.Time) bool {
+	offset := sinceMidnight(t)
+	if w.Start < w.End {
+		return offset >= w.Start && offset < w.End
+	}
+	// Wraps midnight: inside if after start OR before end.
+	return offset >= w.Start || offset < w.End
+}
+
+// NextStart returns the next time the window opens at or after t. If t is
+// already inside the window, t itself is returned.
+func (w Window) NextStart(t time.Time) time.Time {
+	if w.Contains(t) {
+		return t
+	}
+	return NextClock(t, w.Start)
+}
+
+// String formats the window as "HH:MM-HH:MM".
+func (w Window) String() string {
+	return formatClock(w.Start) + "-" + formatClock(w.End)
+}
+
+// NextClock returns the next wall-clock occurrence of the given time of day
+// at or after t, in t's location. Computed from the calendar date rather than
+// by adding 24h so DST transitions land on the right local time.
+func NextClock(t time.Time, clock time.Duration) time.Time {
+	h := int(clock / time.Hour)
+	m := int((clock % time.Hour) / time.Minute)
+	next := time.Date(t.Year(), t.Month(), t.Day(), h, m, 0, 0, t.Location())
+	if next.Before(t) {
+		next = time.Date(t.Year(), t.Month(), t.Day()+1, h, m, 0, 0, t.Location())
+	}
+	return next
+}
+
+// sinceMidnight returns how far t is into its local day.
+func sinceMidnight(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour +
+		time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second
+}
+
+// formatClock renders a midnight offset as "HH:MM".
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int((d%time.Hour)/time.Minute))
+}
@@ -0,0 +1,94 @@
+package queue
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"02:00", 2 * time.Hour, false},
+		{"23:59", 23*time.Hour + 59*time.Minute, false},
+		{"00:00", 0, false},
+		{"24:00", 0, true},
+		{"7:5", 0, true},
+		{"noon", 0, true},
+		{"12:60", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseClock(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseClock(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseClock(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseWindow(t *testing.T) {
+	w, err := ParseWindow("01:00-07:00")
+	if err != nil {
+		t.Fatalf("ParseWindow returned error: %v", err)
+	}
+	if w.Start != time.Hour || w.End != 7*time.Hour {
+		t.Errorf("got %v, want 01:00-07:00", w)
+	}
+	if w.String() != "01:00-07:00" {
+		t.Errorf("String() = %q, want %q", w.String(), "01:00-07:00")
+	}
+
+	for _, bad := range []string{"01:00", "01:00-01:00", "1am-7am"} {
+		if _, err := ParseWindow(bad); err == nil {
+			t.Errorf("ParseWindow(%q) expected error", bad)
+		}
+	}
+}
+
+func TestWindowContains(t *testing.T) {
+	at := func(h, m int) time.Time {
+		return time.Date(2025, 3, 10, h, m, 0, 0, time.UTC)
+	}
+
+	day := Window{Start: time.Hour, End: 7 * time.Hour}
+	if !day.Contains(at(1, 0)) || !day.Contains(at(6, 59)) {
+		t.Error("expected 01:00 and 06:59 inside 01:00-07:00")
+	}
+	if day.Contains(at(7, 0)) || day.Contains(at(0, 59)) {
+		t.Error("expected 07:00 and 00:59 outside 01:00-07:00")
+	}
+
+	overnight := Window{Start: 22 * time.Hour, End: 6 * time.Hour}
+	if !overnight.Contains(at(23, 0)) || !overnight.Contains(at(3, 0)) {
+		t.Error("expected 23:00 and 03:00 inside 22:00-06:00")
+	}
+	if overnight.Contains(at(12, 0)) {
+		t.Error("expected 12:00 outside 22:00-06:00")
+	}
+}
+
+func TestNextStart(t *testing.T) {
+	w := Window{Start: time.Hour, End: 7 * time.Hour}
+
+	inside := time.Date(2025, 3, 10, 3, 0, 0, 0, time.UTC)
+	if got := w.NextStart(inside); !got.Equal(inside) {
+		t.Errorf("NextStart inside window = %v, want %v", got, inside)
+	}
+
+	afternoon := time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)
+	want := time.Date(2025, 3, 11, 1, 0, 0, 0, time.UTC)
+	if got := w.NextStart(afternoon); !got.Equal(want) {
+		t.Errorf("NextStart after window = %v, want %v", got, want)
+	}
+
+	early := time.Date(2025, 3, 10, 0, 30, 0, 0, time.UTC)
+	want = time.Date(2025, 3, 10, 1, 0, 0, 0, time.UTC)
+	if got := w.NextStart(early); !got.Equal(want) {
+		t.Errorf("NextStart before window = %v, want %v", got, want)
+	}
+}
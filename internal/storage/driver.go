@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Driver stores named documents. Load and Save do no locking of their own;
+// wrap read-modify-write sequences in Update (or reads in View) so they are
+// safe against other goplexcli processes.
+type Driver interface {
+	// Load decodes the named document into v, returning false if it doesn't
+	// exist yet.
+	Load(name string, v interface{}) (bool, error)
+	// Save replaces the named document with v atomically.
+	Save(name string, v interface{}) error
+	// Remove deletes the named document. Removing a missing one is not an
+	// error.
+	Remove(name string) error
+	// View runs fn holding a shared lock on the named document.
+	View(name string, fn func() error) error
+	// Update runs fn holding an exclusive lock on the named document.
+	Update(name string, fn func() error) error
+}
+
+var (
+	backendMu sync.RWMutex
+	backend   Driver
+)
+
+// SetBackend makes d the driver every File stores its document in, keyed by
+// the file's base name ("history" for history.json). Nil restores the
+// default: each File is the JSON file at its Path.
+func SetBackend(d Driver) {
+	backendMu.Lock()
+	backend = d
+	backendMu.Unlock()
+}
+
+// Backend returns the driver set with SetBackend, or nil for the default.
+func Backend() Driver {
+	backendMu.RLock()
+	defer backendMu.RUnlock()
+	return backend
+}
+
+// JSONDriver keeps each document in "<Dir>/<name>.json", locked through
+// "<Dir>/<name>.lock".
+type JSONDriver struct {
+	Dir string
+	// Indent writes human-readable JSON. Leave it off for large,
+	// machine-read documents like the media cache, where indentation
+	// roughly doubles the size and marshal time.
+	Indent bool
+	// Perm is the file mode for written documents; 0 means 0644.
+	Perm os.FileMode
+}
+
+// NewJSON returns a JSON driver rooted at dir.
+func NewJSON(dir string, indent bool) *JSONDriver {
+	return &JSONDriver{Dir: dir, Indent: indent}
+}
+
+// Path returns the file holding the named document.
+func (d *JSONDriver) Path(name string) string {
+	return filepath.Join(d.Dir, name+".json")
+}
+
+// LockPath returns the lock file guarding the named document.
+func (d *JSONDriver) LockPath(name string) string {
+	return filepath.Join(d.Dir, name+".lock")
+}
+
+func (d *JSONDriver) Load(name string, v interface{}) (bool, error) {
+	return ReadJSON(d.Path(name), v)
+}
+
+func (d *JSONDriver) Save(name string, v interface{}) error {
+	perm := d.Perm
+	if perm == 0 {
+		perm = 0644
+	}
+	return WriteJSON(d.Path(name), v, d.Indent, perm)
+}
+
+func (d *JSONDriver) Remove(name string) error {
+	if err := os.Remove(d.Path(name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (d *JSONDriver) View(name string, fn func() error) error {
+	return WithLock(d.LockPath(name), name, false, fn)
+}
+
+func (d *JSONDriver) Update(name string, fn func() error) error {
+	return WithLock(d.LockPath(name), name, true, fn)
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testDriver exercises a Driver's document lifecycle.
+func testDriver(t *testing.T, d Driver) {
+	t.Helper()
+	type doc struct {
+		Items []string `json:"items"`
+	}
+
+	err := d.Update("queue", func() error {
+		var q doc
+		if found, err := d.Load("queue", &q); found || err != nil {
+			t.Errorf("Load(missing) = %v, %v", found, err)
+		}
+		q.Items = append(q.Items, "heat")
+		return d.Save("queue", &q)
+	})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	var got doc
+	err = d.View("queue", func() error {
+		_, err := d.Load("queue", &got)
+		return err
+	})
+	if err != nil || len(got.Items) != 1 || got.Items[0] != "heat" {
+		t.Errorf("View/Load = %+v, %v", got, err)
+	}
+
+	if err := d.Remove("queue"); err != nil {
+		t.Fatal(err)
+	}
+	if found, err := d.Load("queue", &got); found || err != nil {
+		t.Errorf("Load(removed) = %v, %v", found, err)
+	}
+	if err := d.Remove("queue"); err != nil {
+		t.Errorf("removing a missing document should not fail: %v", err)
+	}
+}
+
+func TestJSONDriver(t *testing.T) {
+	d := NewJSON(t.TempDir(), false)
+	testDriver(t, d)
+
+	if err := d.Save("queue", map[string]int{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(d.Dir, "queue.json")); err != nil {
+		t.Errorf("document should be stored as queue.json: %v", err)
+	}
+}
+
+// newTestSQLite returns a SQLite driver in a temp dir, skipping the test in
+// builds without cgo.
+func newTestSQLite(t *testing.T) *SQLiteDriver {
+	t.Helper()
+	d := NewSQLite(filepath.Join(t.TempDir(), "state.db"))
+	t.Cleanup(func() { d.Close() })
+	if _, err := d.open(); err != nil {
+		if strings.Contains(err.Error(), "CGO_ENABLED=0") {
+			t.Skip("SQLite needs a cgo build")
+		}
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestSQLiteDriver(t *testing.T) {
+	d := newTestSQLite(t)
+	testDriver(t, d)
+
+	// Every document shares the one database.
+	if err := d.Save("history", map[string]int{"a": 1}); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(d.Path))
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".json") {
+			t.Errorf("SQLite driver wrote %s", e.Name())
+		}
+	}
+}
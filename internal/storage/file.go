@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Versioned is a document that records the schema version it was written
+// with, so File can refuse one from a newer goplexcli.
+type Versioned interface {
+	SchemaVersion() int
+}
+
+// File is a state document of type T, read and written whole. It is the
+// JSON file at Path unless SetBackend chose another driver. Load and Save
+// do no locking of their own; Update wraps the read-modify-write in the
+// document's lock so separate goplexcli processes don't lose each other's
+// changes.
+type File[T any] struct {
+	// Path is the JSON file, e.g. ".../history.json". Its base name,
+	// without the extension, names the document in other backends.
+	Path string
+	// Name describes the document in errors, e.g. "playback history".
+	Name string
+	// Indent writes human-readable JSON. Leave it off for large,
+	// machine-read documents, where indentation roughly doubles the size
+	// and marshal time.
+	Indent bool
+	// Version is the newest schema version this build reads. A Versioned
+	// document with a later version fails to load with ErrNewerSchema.
+	Version int
+	// New returns the document a missing file loads as; nil means T's zero
+	// value.
+	New func() *T
+}
+
+// LockPath returns the lock file guarding the document.
+func (f File[T]) LockPath() string {
+	return strings.TrimSuffix(f.Path, ".json") + ".lock"
+}
+
+// key is the document's name in a driver.
+func (f File[T]) key() string {
+	return strings.TrimSuffix(filepath.Base(f.Path), filepath.Ext(f.Path))
+}
+
+// driver returns the backend holding the document.
+func (f File[T]) driver() Driver {
+	if d := Backend(); d != nil {
+		return d
+	}
+	return &JSONDriver{Dir: filepath.Dir(f.Path), Indent: f.Indent}
+}
+
+// Load reads the document, reporting whether it exists. A missing document
+// yields New's. With another backend set, a document it doesn't have yet
+// is read from the JSON file, so switching backends keeps existing state;
+// the next Save moves it over.
+func (f File[T]) Load() (*T, bool, error) {
+	doc := new(T)
+	if f.New != nil {
+		doc = f.New()
+	}
+	d := f.driver()
+	found, err := d.Load(f.key(), doc)
+	if _, isJSON := d.(*JSONDriver); !found && err == nil && !isJSON {
+		found, err = ReadJSON(f.Path, doc)
+	}
+	if err != nil {
+		return nil, found, fmt.Errorf("parse %s: %w", f.Name, err)
+	}
+	if v, ok := any(doc).(Versioned); ok {
+		if err := CheckSchema(f.Name, v.SchemaVersion(), f.Version); err != nil {
+			return nil, found, err
+		}
+	}
+	return doc, found, nil
+}
+
+// Save replaces the document atomically.
+func (f File[T]) Save(doc *T) error {
+	return f.driver().Save(f.key(), doc)
+}
+
+// Remove deletes the document, and its JSON file under another backend.
+// Removing a missing one is not an error.
+func (f File[T]) Remove() error {
+	if err := f.driver().Remove(f.key()); err != nil {
+		return err
+	}
+	if err := os.Remove(f.Path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Lock runs fn holding the document's lock, exclusive for writers or
+// shared for readers.
+func (f File[T]) Lock(exclusive bool, fn func() error) error {
+	if exclusive {
+		return f.driver().Update(f.key(), fn)
+	}
+	return f.driver().View(f.key(), fn)
+}
+
+// Update loads the document, applies fn and saves the result, all under
+// the exclusive lock. A load error, such as a corrupt or newer file, is
+// returned without calling fn, so data the user can't regenerate is never
+// overwritten.
+func (f File[T]) Update(fn func(doc *T) error) error {
+	return f.Lock(true, func() error {
+		doc, _, err := f.Load()
+		if err != nil {
+			return err
+		}
+		if err := fn(doc); err != nil {
+			return err
+		}
+		return f.Save(doc)
+	})
+}
@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type testDoc struct {
+	Version int      `json:"version"`
+	Items   []string `json:"items"`
+}
+
+func (d *testDoc) SchemaVersion() int { return d.Version }
+
+func TestFile(t *testing.T) {
+	f := File[testDoc]{
+		Path:    filepath.Join(t.TempDir(), "queue.json"),
+		Name:    "queue",
+		Version: 1,
+		New:     func() *testDoc { return &testDoc{Version: 1} },
+	}
+
+	doc, found, err := f.Load()
+	if err != nil || found || doc.Version != 1 {
+		t.Fatalf("Load(missing) = %+v, %v, %v", doc, found, err)
+	}
+
+	for _, item := range []string{"heat", "ronin"} {
+		err := f.Update(func(d *testDoc) error {
+			d.Items = append(d.Items, item)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+	doc, found, err = f.Load()
+	if err != nil || !found || len(doc.Items) != 2 {
+		t.Errorf("Load() = %+v, %v, %v", doc, found, err)
+	}
+	if f.LockPath() != filepath.Join(filepath.Dir(f.Path), "queue.lock") {
+		t.Errorf("LockPath() = %s", f.LockPath())
+	}
+
+	if err := f.Save(&testDoc{Version: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := f.Load(); !errors.Is(err, ErrNewerSchema) {
+		t.Errorf("Load(newer) error = %v, want ErrNewerSchema", err)
+	}
+	if err := f.Update(func(*testDoc) error { return nil }); !errors.Is(err, ErrNewerSchema) {
+		t.Errorf("Update(newer) error = %v, want ErrNewerSchema", err)
+	}
+
+	if err := f.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(f.Path); !os.IsNotExist(err) {
+		t.Errorf("file should be gone: %v", err)
+	}
+	if err := f.Remove(); err != nil {
+		t.Errorf("removing a missing file should not fail: %v", err)
+	}
+}
+
+func TestFileWithBackend(t *testing.T) {
+	d := newTestSQLite(t)
+	SetBackend(d)
+	t.Cleanup(func() { SetBackend(nil) })
+
+	f := File[testDoc]{Path: filepath.Join(t.TempDir(), "history.json"), Name: "history", Version: 1}
+	// A document the backend doesn't have yet is read from its JSON file.
+	if err := WriteJSON(f.Path, &testDoc{Version: 1, Items: []string{"heat"}}, false, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err := f.Update(func(d *testDoc) error {
+		d.Items = append(d.Items, "ronin")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	var stored testDoc
+	if found, err := d.Load("history", &stored); !found || err != nil || len(stored.Items) != 2 {
+		t.Errorf("backend document = %+v, %v, %v; want both items", stored, found, err)
+	}
+
+	if err := f.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, err := f.Load(); found || err != nil {
+		t.Errorf("Load(removed) found = %v, %v; the JSON file should be gone too", found, err)
+	}
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+const (
+	// LockTimeout is the maximum time to wait for a lock. Kept short so users
+	// don't wait long if another instance crashed while holding it.
+	LockTimeout = 5 * time.Second
+	// lockRetryInterval is how often to retry acquiring a lock.
+	lockRetryInterval = 100 * time.Millisecond
+)
+
+// WithLock runs fn while holding a lock on lockPath, shared for readers or
+// exclusive for writers, so separate goplexcli processes can safely
+// read-modify-write the same state file. name describes the state in error
+// messages. Locks are not reentrant: fn must not take the same lock again.
+func WithLock(lockPath, name string, exclusive bool, fn func() error) error {
+	// For exclusive locks, ensure the directory exists (needed for writes)
+	if exclusive {
+		if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+			return fmt.Errorf("failed to acquire %s lock: %w", name, err)
+		}
+	}
+
+	fileLock := flock.New(lockPath)
+	ctx, cancel := context.WithTimeout(context.Background(), LockTimeout)
+	defer cancel()
+
+	var locked bool
+	var err error
+	if exclusive {
+		locked, err = fileLock.TryLockContext(ctx, lockRetryInterval)
+	} else {
+		locked, err = fileLock.TryRLockContext(ctx, lockRetryInterval)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to acquire %s lock: %w", name, err)
+	}
+	if !locked {
+		return fmt.Errorf("failed to acquire %s lock within %v (another instance may be using the %s)", name, LockTimeout, name)
+	}
+	defer func() {
+		_ = fileLock.Unlock() // Error intentionally ignored - lock released on process exit regardless
+	}()
+
+	return fn()
+}
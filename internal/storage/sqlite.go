@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // registers the "sqlite3" database/sql driver
+)
+
+// SQLiteDriver keeps every document as a row of one SQLite database, so all
+// of goplexcli's state is a single file that is updated in place instead
+// of rewritten. Documents are stored as JSON, like JSONDriver's.
+//
+// A Load-modify-Save spans several statements, so View and Update lock
+// through "<name>.lock" files next to the database, as JSONDriver does;
+// SQLite's own locking only makes each statement atomic.
+//
+// It needs a cgo build. Without one, opening the database fails.
+type SQLiteDriver struct {
+	// Path is the database file, created on first use.
+	Path string
+
+	once sync.Once
+	db   *sql.DB
+	err  error
+}
+
+// NewSQLite returns a SQLite driver for the database at path.
+func NewSQLite(path string) *SQLiteDriver {
+	return &SQLiteDriver{Path: path}
+}
+
+// open opens the database and creates its table on first use.
+func (d *SQLiteDriver) open() (*sql.DB, error) {
+	d.once.Do(func() {
+		if err := os.MkdirAll(filepath.Dir(d.Path), 0755); err != nil {
+			d.err = fmt.Errorf("failed to open state database: %w", err)
+			return
+		}
+		// The busy timeout makes a writer wait out another process's
+		// statement instead of failing with "database is locked".
+		db, err := sql.Open("sqlite3", d.Path+"?_busy_timeout=5000&_journal_mode=WAL")
+		if err == nil {
+			_, err = db.Exec(`CREATE TABLE IF NOT EXISTS documents (
+				name       TEXT PRIMARY KEY,
+				data       BLOB NOT NULL,
+				updated_at INTEGER NOT NULL
+			)`)
+		}
+		if err != nil {
+			if db != nil {
+				db.Close()
+			}
+			d.err = fmt.Errorf("failed to open state database: %w", err)
+			return
+		}
+		d.db = db
+	})
+	return d.db, d.err
+}
+
+// LockPath returns the lock file guarding the named document.
+func (d *SQLiteDriver) LockPath(name string) string {
+	return filepath.Join(filepath.Dir(d.Path), name+".lock")
+}
+
+func (d *SQLiteDriver) Load(name string, v interface{}) (bool, error) {
+	db, err := d.open()
+	if err != nil {
+		return false, err
+	}
+	var data []byte
+	err = db.QueryRow(`SELECT data FROM documents WHERE name = ?`, name).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, json.Unmarshal(data, v)
+}
+
+func (d *SQLiteDriver) Save(name string, v interface{}) error {
+	db, err := d.open()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`INSERT INTO documents (name, data, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET data = excluded.data, updated_at = excluded.updated_at`,
+		name, data, time.Now().Unix())
+	return err
+}
+
+func (d *SQLiteDriver) Remove(name string) error {
+	db, err := d.open()
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM documents WHERE name = ?`, name)
+	return err
+}
+
+func (d *SQLiteDriver) View(name string, fn func() error) error {
+	return WithLock(d.LockPath(name), name, false, fn)
+}
+
+func (d *SQLiteDriver) Update(name string, fn func() error) error {
+	return WithLock(d.LockPath(name), name, true, fn)
+}
+
+// Close closes the database, if it was opened.
+func (d *SQLiteDriver) Close() error {
+	if d.db == nil {
+		return nil
+	}
+	return d.db.Close()
+}
@@ -0,0 +1,109 @@
+// Package storage is the shared persistence layer for goplexcli's local state
+// files: the media cache, the download queue, playback history, usage
+// statistics and favorites. It provides atomic writes, cross-process file
+// locks and schema version checks, and File, a document loaded and saved
+// whole, which most of those stores are built on.
+//
+// A File's document lives in a Driver: by default the JSON file at its
+// path, or a single SQLite database for every document (see SetBackend and
+// the state_backend config setting).
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ErrNewerSchema reports a state file written by a newer goplexcli than the
+// one reading it. Callers should refuse to overwrite such a file.
+var ErrNewerSchema = errors.New("written by a newer version of goplexcli")
+
+// CheckSchema returns an error wrapping ErrNewerSchema if a document at
+// version is newer than supported. name identifies the document in the
+// message.
+func CheckSchema(name string, version, supported int) error {
+	if version > supported {
+		return fmt.Errorf("%s has schema version %d (this build supports up to %d): %w", name, version, supported, ErrNewerSchema)
+	}
+	return nil
+}
+
+// WriteAtomic writes data to path via a temp file in the same directory and a
+// rename, so readers never see a half-written file and a crash mid-write
+// (Ctrl-C, power loss) leaves the previous contents intact. The parent
+// directory is created if needed.
+func WriteAtomic(path string, data []byte, perm os.FileMode) error {
+	return WriteAtomicFrom(path, bytes.NewReader(data), perm)
+}
+
+// WriteAtomicFrom is WriteAtomic for a stream, e.g. a download.
+func WriteAtomicFrom(path string, r io.Reader, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	cleanup := func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+	}
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		cleanup()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		cleanup()
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		cleanup()
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		cleanup()
+		return err
+	}
+	return nil
+}
+
+// ReadJSON decodes the JSON file at path into v. A missing file is not an
+// error: it returns false and leaves v untouched.
+func ReadJSON(path string, v interface{}) (bool, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// WriteJSON encodes v, indented or compact, and writes it atomically.
+func WriteJSON(path string, v interface{}, indent bool, perm os.FileMode) error {
+	var data []byte
+	var err error
+	if indent {
+		data, err = json.MarshalIndent(v, "", "  ")
+	} else {
+		data, err = json.Marshal(v)
+	}
+	if err != nil {
+		return err
+	}
+	return WriteAtomic(path, data, perm)
+}
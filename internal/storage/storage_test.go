@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nested", "state.json")
+
+	if err := WriteAtomic(path, []byte("first"), 0600); err != nil {
+		t.Fatalf("WriteAtomic() error = %v", err)
+	}
+	if err := WriteAtomic(path, []byte("second"), 0600); err != nil {
+		t.Fatalf("WriteAtomic() overwrite error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "second" {
+		t.Errorf("contents = %q, %v; want %q", data, err, "second")
+	}
+	if info, err := os.Stat(path); err == nil && info.Mode().Perm() != 0600 && os.PathSeparator == '/' {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("temp files left behind: %v", entries)
+	}
+}
+
+func TestWriteAtomicFromFailureKeepsOldContents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "media.json")
+	if err := WriteAtomic(path, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := WriteAtomicFrom(path, failingReader{}, 0644)
+	if err == nil {
+		t.Fatal("expected the reader's error")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "old" {
+		t.Errorf("a failed write must not touch the file, got %q", data)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("temp file not cleaned up: %v", entries)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("connection reset") }
+
+func TestReadWriteJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doc.json")
+
+	var v map[string]int
+	if found, err := ReadJSON(path, &v); found || err != nil {
+		t.Fatalf("ReadJSON(missing) = %v, %v; want false, nil", found, err)
+	}
+
+	if err := WriteJSON(path, map[string]int{"a": 1}, true, 0644); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "\n") {
+		t.Errorf("indented JSON expected, got %q", data)
+	}
+
+	found, err := ReadJSON(path, &v)
+	if !found || err != nil || v["a"] != 1 {
+		t.Errorf("ReadJSON() = %v, %v, %v", found, err, v)
+	}
+
+	if err := os.WriteFile(path, []byte("{broken"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadJSON(path, &v); err == nil {
+		t.Error("expected a decode error for corrupt JSON")
+	}
+}
+
+func TestCheckSchema(t *testing.T) {
+	if err := CheckSchema("queue", 1, 2); err != nil {
+		t.Errorf("older schema should be accepted: %v", err)
+	}
+	err := CheckSchema("queue", 3, 2)
+	if !errors.Is(err, ErrNewerSchema) {
+		t.Errorf("CheckSchema(3, 2) = %v, want ErrNewerSchema", err)
+	}
+}
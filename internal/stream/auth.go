@@ -0,0 +1,119 @@
+package stream
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// TokenParam is the query parameter carrying the access token, for clients
+// that can't set headers: players opening an HLS URL, or a browser following
+// a printed link.
+const TokenParam = "token"
+
+// tokenCookie remembers a token given as a query parameter, so the web UI's
+// reloads and links keep working without repeating it.
+const tokenCookie = "goplexcli_token"
+
+// authTXT is the mDNS TXT record a protected server advertises, so clients
+// know to ask for the token before fetching /streams.
+const authTXT = "auth=token"
+
+// ErrUnauthorized is returned by FetchStreams when the server rejects the
+// token, or wants one and none was given.
+var ErrUnauthorized = errors.New("stream server requires a valid access token")
+
+// GenerateToken returns a random access token for RequireToken.
+func GenerateToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// RequireToken protects the web UI, /streams and /hls/ with token, which
+// clients present as a bearer token, as the Basic Auth password (any user
+// name), or as the "token" query parameter. /health stays open. It must be
+// called before Start and before publishing streams.
+func (s *Server) RequireToken(token string) {
+	s.token = token
+}
+
+// authorized reports whether r carries the server's token. A server without
+// one accepts every request.
+func (s *Server) authorized(r *http.Request) bool {
+	if s.token == "" {
+		return true
+	}
+	var given string
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		given = strings.TrimPrefix(auth, "Bearer ")
+	} else if _, pass, ok := r.BasicAuth(); ok {
+		given = pass
+	} else if q := r.URL.Query().Get(TokenParam); q != "" {
+		given = q
+	} else if c, err := r.Cookie(tokenCookie); err == nil {
+		given = c.Value
+	}
+	return subtle.ConstantTimeCompare([]byte(given), []byte(s.token)) == 1
+}
+
+// protect wraps h so it only runs for authorized requests. Browsers get a
+// Basic Auth prompt; a token given in the URL is kept in a cookie.
+func (s *Server) protect(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.authorized(r) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="goplexcli"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if s.token != "" && r.URL.Query().Get(TokenParam) != "" {
+			http.SetCookie(w, &http.Cookie{
+				Name:     tokenCookie,
+				Value:    s.token,
+				Path:     "/",
+				HttpOnly: true,
+				SameSite: http.SameSiteStrictMode,
+			})
+		}
+		h(w, r)
+	}
+}
+
+// withToken appends the server's token to a server-relative path, if it has
+// one, so the URL works when handed to a player.
+func (s *Server) withToken(path string) string {
+	if s.token == "" {
+		return path
+	}
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + TokenParam + "=" + url.QueryEscape(s.token)
+}
+
+// tokenizePlaylist adds the server's token to every segment URI of an HLS
+// playlist. Players fetch segments relative to the playlist URL but drop its
+// query string, so without this a protected server would refuse them.
+func (s *Server) tokenizePlaylist(playlist []byte) []byte {
+	var out bytes.Buffer
+	sc := bufio.NewScanner(bytes.NewReader(playlist))
+	for sc.Scan() {
+		line := sc.Text()
+		if line != "" && !strings.HasPrefix(line, "#") {
+			line = s.withToken(line)
+		}
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+	return out.Bytes()
+}
@@ -0,0 +1,111 @@
+package stream
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestProtect(t *testing.T) {
+	s, err := NewServer(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.RequireToken("secret")
+	h := s.protect(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name string
+		req  func() *http.Request
+		want int
+	}{
+		{"no token", func() *http.Request { return httptest.NewRequest(http.MethodGet, "/streams", nil) }, http.StatusUnauthorized},
+		{"bearer", func() *http.Request {
+			r := httptest.NewRequest(http.MethodGet, "/streams", nil)
+			r.Header.Set("Authorization", "Bearer secret")
+			return r
+		}, http.StatusOK},
+		{"wrong bearer", func() *http.Request {
+			r := httptest.NewRequest(http.MethodGet, "/streams", nil)
+			r.Header.Set("Authorization", "Bearer nope")
+			return r
+		}, http.StatusUnauthorized},
+		{"basic", func() *http.Request {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.SetBasicAuth("anyone", "secret")
+			return r
+		}, http.StatusOK},
+		{"query", func() *http.Request { return httptest.NewRequest(http.MethodGet, "/?token=secret", nil) }, http.StatusOK},
+		{"cookie", func() *http.Request {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.AddCookie(&http.Cookie{Name: tokenCookie, Value: "secret"})
+			return r
+		}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h(rec, tt.req())
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.want)
+		}
+		if rec.Code == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s: 401 without a WWW-Authenticate header", tt.name)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodGet, "/?token=secret", nil))
+	if c := rec.Result().Cookies(); len(c) != 1 || c[0].Value != "secret" {
+		t.Errorf("query token should set the cookie, got %v", c)
+	}
+}
+
+func TestProtectWithoutToken(t *testing.T) {
+	s, err := NewServer(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	s.protect(func(w http.ResponseWriter, r *http.Request) {})(rec, httptest.NewRequest(http.MethodGet, "/streams", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("an open server should accept anonymous requests, status = %d", rec.Code)
+	}
+}
+
+func TestTokenizePlaylist(t *testing.T) {
+	s := &Server{token: "a b"}
+	got := string(s.tokenizePlaylist([]byte("#EXTM3U\n#EXTINF:6.0,\nseg00000.ts\n\n")))
+	want := "#EXTM3U\n#EXTINF:6.0,\nseg00000.ts?token=a+b\n\n"
+	if got != want {
+		t.Errorf("tokenizePlaylist = %q, want %q", got, want)
+	}
+	if got := s.withToken("/hls/x/index.m3u8?a=1"); got != "/hls/x/index.m3u8?a=1&token=a+b" {
+		t.Errorf("withToken = %q", got)
+	}
+}
+
+func TestFetchStreamsToken(t *testing.T) {
+	s, err := NewServer(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.RequireToken("secret")
+	ts := httptest.NewServer(s.protect(s.handleListStreams))
+	defer ts.Close()
+
+	host, portStr, _ := net.SplitHostPort(ts.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	server := &DiscoveredServer{Name: "test", Port: port, Addresses: []string{host}}
+
+	if _, err := FetchStreams(server, ""); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("FetchStreams without token: err = %v, want ErrUnauthorized", err)
+	}
+	if _, err := FetchStreams(server, "secret"); err != nil {
+		t.Errorf("FetchStreams with token: %v", err)
+	}
+}
@@ -0,0 +1,241 @@
+package stream
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HLSOptions configures the optional ffmpeg-backed HLS endpoint, which
+// re-streams a published item as /hls/{id}/index.m3u8 for browsers and smart
+// TVs that can't play a raw MKV direct URL.
+type HLSOptions struct {
+	// FFmpegPath is the ffmpeg executable. If empty, "ffmpeg" is used.
+	FFmpegPath string
+
+	// TranscodeVideo re-encodes video to H.264 instead of copying it. Copying
+	// is nearly free but only works when the source is already H.264 (or
+	// HEVC on Apple devices); transcoding plays anywhere at a CPU cost.
+	TranscodeVideo bool
+}
+
+// hlsStartTimeout bounds how long a playlist request waits for ffmpeg to
+// write the first segment.
+const hlsStartTimeout = 30 * time.Second
+
+// hlsFileRe matches the only files an HLS session directory serves, so a
+// request can never reach outside it.
+var hlsFileRe = regexp.MustCompile(`^(index\.m3u8|seg[0-9]+\.ts)$`)
+
+// hlsSession is one running ffmpeg segmenter writing into dir.
+type hlsSession struct {
+	dir    string
+	cancel context.CancelFunc
+	done   chan struct{} // closed when ffmpeg exits
+	err    error         // set before done is closed
+}
+
+// hlsManager starts an ffmpeg segmenter per stream on first request and
+// tears them all down when the server stops.
+type hlsManager struct {
+	opts    HLSOptions
+	baseDir string
+
+	mu       sync.Mutex
+	sessions map[string]*hlsSession
+}
+
+func newHLSManager(opts HLSOptions) (*hlsManager, error) {
+	if opts.FFmpegPath == "" {
+		opts.FFmpegPath = "ffmpeg"
+	}
+	if _, err := exec.LookPath(opts.FFmpegPath); err != nil {
+		return nil, fmt.Errorf("ffmpeg not found: %w", err)
+	}
+	baseDir, err := os.MkdirTemp("", "goplexcli-hls-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HLS directory: %w", err)
+	}
+	return &hlsManager{
+		opts:     opts,
+		baseDir:  baseDir,
+		sessions: make(map[string]*hlsSession),
+	}, nil
+}
+
+// ffmpegHLSArgs builds the ffmpeg command line that segments source into an
+// event playlist in dir. Audio is always converted to stereo AAC, the one
+// codec every HLS client supports.
+func ffmpegHLSArgs(source, dir string, transcodeVideo bool) []string {
+	args := []string{"-hide_banner", "-loglevel", "error", "-i", source, "-map", "0:v:0", "-map", "0:a:0?"}
+	if transcodeVideo {
+		args = append(args, "-c:v", "libx264", "-preset", "veryfast", "-crf", "23", "-pix_fmt", "yuv420p")
+	} else {
+		args = append(args, "-c:v", "copy")
+	}
+	return append(args,
+		"-c:a", "aac", "-ac", "2", "-b:a", "192k",
+		"-f", "hls",
+		"-hls_time", "6",
+		"-hls_list_size", "0",
+		"-hls_playlist_type", "event",
+		"-hls_segment_filename", filepath.Join(dir, "seg%05d.ts"),
+		filepath.Join(dir, "index.m3u8"),
+	)
+}
+
+// session returns the running segmenter for id, starting one on source if
+// there isn't one yet.
+func (m *hlsManager) session(id, source string) (*hlsSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if sess, ok := m.sessions[id]; ok {
+		return sess, nil
+	}
+
+	dir := filepath.Join(m.baseDir, id)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := exec.CommandContext(ctx, m.opts.FFmpegPath, ffmpegHLSArgs(source, dir, m.opts.TranscodeVideo)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	// Don't hang on a killed ffmpeg whose children still hold stderr open.
+	cmd.WaitDelay = 2 * time.Second
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to start ffmpeg: %w", err)
+	}
+
+	sess := &hlsSession{dir: dir, cancel: cancel, done: make(chan struct{})}
+	go func() {
+		if err := cmd.Wait(); err != nil && ctx.Err() == nil {
+			sess.err = fmt.Errorf("ffmpeg: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		close(sess.done)
+	}()
+	m.sessions[id] = sess
+	return sess, nil
+}
+
+// waitForPlaylist blocks until ffmpeg has written the playlist, exited, or
+// the timeout passes.
+func (sess *hlsSession) waitForPlaylist(ctx context.Context) error {
+	playlist := filepath.Join(sess.dir, "index.m3u8")
+	deadline := time.NewTimer(hlsStartTimeout)
+	defer deadline.Stop()
+	tick := time.NewTicker(200 * time.Millisecond)
+	defer tick.Stop()
+
+	for {
+		if _, err := os.Stat(playlist); err == nil {
+			return nil
+		}
+		select {
+		case <-sess.done:
+			if _, err := os.Stat(playlist); err == nil {
+				return nil
+			}
+			if sess.err != nil {
+				return sess.err
+			}
+			return errors.New("ffmpeg exited without producing a playlist")
+		case <-deadline.C:
+			return errors.New("timed out waiting for ffmpeg to start")
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tick.C:
+		}
+	}
+}
+
+// stop kills the segmenter for id and deletes its segments.
+func (m *hlsManager) stop(id string) {
+	m.mu.Lock()
+	sess, ok := m.sessions[id]
+	delete(m.sessions, id)
+	m.mu.Unlock()
+
+	if ok {
+		sess.cancel()
+		<-sess.done
+		_ = os.RemoveAll(sess.dir)
+	}
+}
+
+// close stops every segmenter and removes the HLS directory.
+func (m *hlsManager) close() {
+	m.mu.Lock()
+	ids := make([]string, 0, len(m.sessions))
+	for id := range m.sessions {
+		ids = append(ids, id)
+	}
+	m.mu.Unlock()
+
+	for _, id := range ids {
+		m.stop(id)
+	}
+	_ = os.RemoveAll(m.baseDir)
+}
+
+// handleHLS serves /hls/{id}/index.m3u8 and its segments, starting ffmpeg on
+// the first playlist request for a stream.
+func (s *Server) handleHLS(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, file, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/hls/"), "/")
+	if !ok || !hlsFileRe.MatchString(file) {
+		http.NotFound(w, r)
+		return
+	}
+	item, ok := s.GetStream(id)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	sess, err := s.hls.session(id, item.StreamURL)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if file == "index.m3u8" {
+		if err := sess.waitForPlaylist(r.Context()); err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+		// The playlist grows while ffmpeg runs; players must re-fetch it.
+		w.Header().Set("Cache-Control", "no-cache")
+		if s.token != "" {
+			data, err := os.ReadFile(filepath.Join(sess.dir, file))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+			_, _ = w.Write(s.tokenizePlaylist(data))
+			return
+		}
+	} else {
+		w.Header().Set("Content-Type", "video/mp2t")
+	}
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	http.ServeFile(w, r, filepath.Join(sess.dir, file))
+}
@@ -0,0 +1,100 @@
+package stream
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFFmpegHLSArgs(t *testing.T) {
+	copyArgs := ffmpegHLSArgs("http://plex/file.mkv", "/tmp/hls", false)
+	if !slices.Contains(copyArgs, "copy") || slices.Contains(copyArgs, "libx264") {
+		t.Errorf("remux args should copy video: %v", copyArgs)
+	}
+	if last := copyArgs[len(copyArgs)-1]; last != filepath.Join("/tmp/hls", "index.m3u8") {
+		t.Errorf("playlist should be the final argument, got %q", last)
+	}
+
+	transcodeArgs := ffmpegHLSArgs("http://plex/file.mkv", "/tmp/hls", true)
+	if !slices.Contains(transcodeArgs, "libx264") {
+		t.Errorf("transcode args should encode H.264: %v", transcodeArgs)
+	}
+}
+
+// fakeFFmpeg writes a shell script standing in for ffmpeg: it writes a
+// playlist and one segment next to its final argument, then idles.
+func fakeFFmpeg(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ffmpeg is a shell script")
+	}
+	path := filepath.Join(t.TempDir(), "ffmpeg")
+	script := `#!/bin/sh
+for last; do :; done
+dir=$(dirname "$last")
+echo data > "$dir/seg00000.ts"
+printf '#EXTM3U\n#EXTINF:6.0,\nseg00000.ts\n' > "$last"
+exec sleep 30
+`
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestHandleHLS(t *testing.T) {
+	s, err := NewServer(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.EnableHLS(HLSOptions{FFmpegPath: fakeFFmpeg(t)}); err != nil {
+		t.Fatalf("EnableHLS: %v", err)
+	}
+	defer s.hls.close()
+
+	id := "stream-1"
+	s.streams[id] = &StreamItem{ID: id, StreamURL: "http://plex/file.mkv"}
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		s.handleHLS(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	rec := get("/hls/" + id + "/index.m3u8")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("playlist status = %d, body %q", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/vnd.apple.mpegurl" {
+		t.Errorf("playlist Content-Type = %q", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "seg00000.ts") {
+		t.Errorf("playlist missing segment: %q", rec.Body.String())
+	}
+
+	if rec := get("/hls/" + id + "/seg00000.ts"); rec.Code != http.StatusOK {
+		t.Errorf("segment status = %d", rec.Code)
+	}
+
+	for _, path := range []string{
+		"/hls/unknown/index.m3u8",
+		"/hls/" + id + "/../../etc/passwd",
+		"/hls/" + id + "/other.txt",
+		"/hls/" + id,
+	} {
+		if rec := get(path); rec.Code != http.StatusNotFound {
+			t.Errorf("GET %s status = %d, want 404", path, rec.Code)
+		}
+	}
+
+	dir := s.hls.sessions[id].dir
+	s.RemoveStream(id)
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("removing the stream should delete its segments, stat err = %v", err)
+	}
+}
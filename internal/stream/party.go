@@ -0,0 +1,271 @@
+package stream
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// partyTXT is the mDNS TXT record a server hosting a watch party advertises.
+const partyTXT = "party=1"
+
+// PartyState is what a watch-party host broadcasts: the stream everyone
+// plays and where the host's player is in it.
+type PartyState struct {
+	StreamID string  `json:"stream_id"`
+	Paused   bool    `json:"paused"`
+	Position float64 `json:"position"`
+}
+
+// party fans the host's state out to the guests' /party/events streams.
+type party struct {
+	mu    sync.Mutex
+	state PartyState
+	subs  map[chan PartyState]struct{}
+	done  chan struct{} // closed on Shutdown, ending every event stream
+}
+
+// EnableParty makes this server host a watch party for the published
+// stream id: guests find it over mDNS, read GET /party, and follow
+// GET /party/events. It must be called before Start.
+func (s *Server) EnableParty(streamID string) {
+	s.party = &party{
+		state: PartyState{StreamID: streamID, Paused: true},
+		subs:  make(map[chan PartyState]struct{}),
+		done:  make(chan struct{}),
+	}
+}
+
+// BroadcastParty sends the host player's state to every guest.
+func (s *Server) BroadcastParty(paused bool, position float64) {
+	p := s.party
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.state.Paused, p.state.Position = paused, position
+	for ch := range p.subs {
+		// Guests only need the latest state; drop a stale one they
+		// haven't read yet.
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- p.state
+	}
+}
+
+// HostParty broadcasts player's state every interval until ctx is done.
+// Broadcasting on a timer, not only on changes, lets guests that drift or
+// join late catch up.
+func (s *Server) HostParty(ctx context.Context, player Remote, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		// A player still loading the file has no state yet; skip the tick.
+		if paused, err := player.GetPaused(); err == nil {
+			if pos, err := player.GetTimePos(); err == nil {
+				s.BroadcastParty(paused, pos)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *party) subscribe() (chan PartyState, PartyState) {
+	ch := make(chan PartyState, 1)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.subs[ch] = struct{}{}
+	return ch, p.state
+}
+
+func (p *party) unsubscribe(ch chan PartyState) {
+	p.mu.Lock()
+	delete(p.subs, ch)
+	p.mu.Unlock()
+}
+
+func (p *party) close() {
+	select {
+	case <-p.done:
+	default:
+		close(p.done)
+	}
+}
+
+// handleParty serves GET /party, the current state as JSON, and
+// GET /party/events, a server-sent event stream of every broadcast.
+func (s *Server) handleParty(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	p := s.party
+	if p == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	ch, state := p.subscribe()
+	defer p.unsubscribe(ch)
+
+	if r.URL.Path != "/party/events" {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(state)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	for {
+		data, _ := json.Marshal(state)
+		if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+			return
+		}
+		flusher.Flush()
+
+		select {
+		case state = <-ch:
+		case <-r.Context().Done():
+			return
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// FetchParty returns the current state of the watch party a discovered
+// server hosts, including the stream to play.
+func FetchParty(server *DiscoveredServer, token string) (*PartyState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := partyGet(ctx, server, token, "/party")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var state PartyState
+	if err := json.NewDecoder(resp.Body).Decode(&state); err != nil {
+		return nil, fmt.Errorf("failed to decode party state: %w", err)
+	}
+	return &state, nil
+}
+
+// FollowParty mirrors the host's play, pause and seeks onto player until
+// ctx is done or the host ends the party, which returns nil. Positions
+// within tolerance seconds of the host's are left alone, so guests don't
+// stutter from constant small seeks.
+func FollowParty(ctx context.Context, server *DiscoveredServer, token string, player Remote, tolerance float64) error {
+	resp, err := partyGet(ctx, server, token, "/party/events")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var state PartyState
+		if err := json.Unmarshal([]byte(data), &state); err != nil {
+			return fmt.Errorf("failed to decode party state: %w", err)
+		}
+		if err := mirror(player, state, tolerance); err != nil {
+			return fmt.Errorf("failed to sync player: %w", err)
+		}
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return scanner.Err()
+}
+
+// mirror brings player in line with the host's state.
+func mirror(player Remote, state PartyState, tolerance float64) error {
+	paused, err := player.GetPaused()
+	if err != nil {
+		return err
+	}
+	if paused != state.Paused {
+		if err := player.SetPaused(state.Paused); err != nil {
+			return err
+		}
+	}
+	pos, err := player.GetTimePos()
+	if err != nil {
+		return err
+	}
+	if math.Abs(pos-state.Position) > tolerance {
+		return player.Seek(state.Position)
+	}
+	return nil
+}
+
+// partyGet requests path from the first of server's addresses that
+// answers, with the same token and TLS pinning as FetchStreams.
+func partyGet(ctx context.Context, server *DiscoveredServer, token, path string) (*http.Response, error) {
+	if len(server.Addresses) == 0 {
+		return nil, fmt.Errorf("no addresses available for server")
+	}
+	scheme := "http"
+	if server.Fingerprint != "" {
+		scheme = "https"
+	}
+	// No client timeout: the event stream stays open for the whole party.
+	client := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: clientTLSConfig(server.Fingerprint)},
+	}
+
+	var lastErr error
+	for _, addr := range server.Addresses {
+		host := addr
+		if strings.Contains(addr, ":") {
+			host = "[" + addr + "]"
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s://%s:%d%s", scheme, host, server.Port, path), nil)
+		if err != nil {
+			return nil, err
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		switch resp.StatusCode {
+		case http.StatusOK:
+			return resp, nil
+		case http.StatusUnauthorized:
+			resp.Body.Close()
+			return nil, ErrUnauthorized
+		case http.StatusNotFound:
+			resp.Body.Close()
+			return nil, fmt.Errorf("%s is not hosting a watch party", server.Name)
+		}
+		resp.Body.Close()
+		lastErr = fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+	return nil, fmt.Errorf("failed to reach %s: %w", server.Name, lastErr)
+}
@@ -0,0 +1,141 @@
+package stream
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMirror(t *testing.T) {
+	f := &fakeRemote{pos: 100}
+
+	if err := mirror(f, PartyState{Paused: true, Position: 100.5}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if !f.paused || f.pos != 100 {
+		t.Errorf("small drift: paused = %t, pos = %v; want paused at 100", f.paused, f.pos)
+	}
+
+	if err := mirror(f, PartyState{Paused: false, Position: 40}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if f.paused || f.pos != 40 {
+		t.Errorf("host seeked: paused = %t, pos = %v; want playing at 40", f.paused, f.pos)
+	}
+}
+
+// lockedRemote guards a fakeRemote the follower updates from its goroutine.
+type lockedRemote struct {
+	mu sync.Mutex
+	fakeRemote
+}
+
+func (l *lockedRemote) GetPaused() (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.fakeRemote.GetPaused()
+}
+
+func (l *lockedRemote) GetTimePos() (float64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.fakeRemote.GetTimePos()
+}
+
+func (l *lockedRemote) SetPaused(p bool) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.fakeRemote.SetPaused(p)
+}
+
+func (l *lockedRemote) Seek(secs float64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.fakeRemote.Seek(secs)
+}
+
+func (l *lockedRemote) state() (bool, float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.paused, l.pos
+}
+
+func TestFollowParty(t *testing.T) {
+	s, err := NewServer(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.RequireToken("secret")
+	s.EnableParty("abc")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/party", s.protect(s.handleParty))
+	mux.HandleFunc("/party/events", s.protect(s.handleParty))
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	defer s.party.close()
+
+	host, portStr, _ := net.SplitHostPort(ts.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	discovered := &DiscoveredServer{Name: "host", Addresses: []string{host}, Port: port}
+
+	if _, err := FetchParty(discovered, "wrong"); err != ErrUnauthorized {
+		t.Errorf("FetchParty with a wrong token: err = %v, want ErrUnauthorized", err)
+	}
+	state, err := FetchParty(discovered, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.StreamID != "abc" || !state.Paused {
+		t.Errorf("initial state = %+v", state)
+	}
+
+	guest := &lockedRemote{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- FollowParty(ctx, discovered, "secret", guest, 1) }()
+
+	waitFor := func(paused bool, pos float64) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			s.BroadcastParty(paused, pos)
+			if p, at := guest.state(); p == paused && at == pos {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		p, at := guest.state()
+		t.Fatalf("guest at paused = %t, pos = %v; want %t, %v", p, at, paused, pos)
+	}
+	waitFor(false, 600)
+	waitFor(true, 30)
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("FollowParty after cancel: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("FollowParty didn't return after cancel")
+	}
+}
+
+func TestPartyDisabled(t *testing.T) {
+	s, err := NewServer(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	s.handleParty(rec, httptest.NewRequest(http.MethodGet, "/party", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+	s.BroadcastParty(true, 1) // no party: a no-op, not a panic
+}
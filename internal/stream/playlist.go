@@ -0,0 +1,37 @@
+package stream
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/joshkerr/goplexcli/internal/export"
+)
+
+// PlaylistPath returns the server-relative URL of the M3U playlist of all
+// published streams, with the access token if RequireToken was called.
+func (s *Server) PlaylistPath() string {
+	return s.withToken("/playlist.m3u8")
+}
+
+// handlePlaylist serves /playlist.m3u8: every published stream, oldest
+// first, as an extended M3U that smart TVs, Kodi and VLC open directly.
+func (s *Server) handlePlaylist(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	streams := s.publicStreams(r)
+	sort.SliceStable(streams, func(i, j int) bool {
+		return streams[i].PublishedAt.Before(streams[j].PublishedAt)
+	})
+	entries := make([]export.Entry, len(streams))
+	for i, item := range streams {
+		entries[i] = export.Entry{Title: item.Title, Duration: item.Duration / 1000, URL: item.StreamURL}
+	}
+
+	w.Header().Set("Content-Type", "audio/x-mpegurl; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="goplexcli.m3u8"`)
+	w.Header().Set("Cache-Control", "no-cache")
+	_ = export.WriteM3U(w, entries)
+}
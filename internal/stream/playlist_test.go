@@ -0,0 +1,42 @@
+package stream
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandlePlaylist(t *testing.T) {
+	s, err := NewServer(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	s.streams["b"] = &StreamItem{ID: "b", Title: "Second", Duration: 90000, StreamURL: "http://plex/b", PublishedAt: now}
+	s.streams["a"] = &StreamItem{ID: "a", Title: "First\nTitle", StreamURL: "http://plex/a", PublishedAt: now.Add(-time.Minute)}
+
+	rec := httptest.NewRecorder()
+	s.handlePlaylist(rec, httptest.NewRequest(http.MethodGet, "/playlist.m3u8", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	want := "#EXTM3U\n#EXTINF:-1,First Title\nhttp://plex/a\n#EXTINF:90,Second\nhttp://plex/b\n"
+	if got := rec.Body.String(); got != want {
+		t.Errorf("playlist = %q, want %q", got, want)
+	}
+
+	s.RequireToken("secret")
+	s.EnableProxy()
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/playlist.m3u8", nil)
+	req.Host = "10.0.0.5:8765"
+	s.handlePlaylist(rec, req)
+	want = "#EXTM3U\n#EXTINF:-1,First Title\nhttp://10.0.0.5:8765/proxy/a?token=secret\n#EXTINF:90,Second\nhttp://10.0.0.5:8765/proxy/b?token=secret\n"
+	if got := rec.Body.String(); got != want {
+		t.Errorf("proxied playlist = %q, want %q", got, want)
+	}
+	if got := s.PlaylistPath(); got != "/playlist.m3u8?token=secret" {
+		t.Errorf("PlaylistPath = %q", got)
+	}
+}
@@ -0,0 +1,95 @@
+package stream
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+)
+
+// EnableProxy makes the server relay stream and poster bytes from Plex
+// through /proxy/{id} and /proxy/{id}/poster, and publish those URLs instead
+// of Plex's, so consumers never see the Plex token. It must be called before
+// Start.
+func (s *Server) EnableProxy() {
+	s.proxy = true
+}
+
+// StreamPath returns the server-relative URL a consumer should play for the
+// stream, or "" when the server isn't proxying and the Plex URL is used
+// directly. It carries the access token if RequireToken was called.
+func (s *Server) StreamPath(id string) string {
+	if !s.proxy {
+		return ""
+	}
+	return s.withToken("/proxy/" + id)
+}
+
+// publicStreams returns the published streams as consumers should see them.
+// When proxying, the Plex URLs are swapped for this server's, built from the
+// host the request reached so they resolve from the consumer's side.
+func (s *Server) publicStreams(r *http.Request) []*StreamItem {
+	streams := s.ListStreams()
+	if !s.proxy {
+		return streams
+	}
+	base := s.Scheme() + "://" + r.Host
+	out := make([]*StreamItem, len(streams))
+	for i, item := range streams {
+		c := *item
+		c.StreamURL = base + s.StreamPath(item.ID)
+		if c.PosterURL != "" {
+			c.PosterURL = base + s.withToken("/proxy/"+item.ID+"/poster")
+		}
+		out[i] = &c
+	}
+	return out
+}
+
+// handleProxy serves /proxy/{id} and /proxy/{id}/poster by reverse-proxying
+// the Plex URL. Range requests pass through, so players can seek.
+func (s *Server) handleProxy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, rest, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/proxy/"), "/")
+	item, ok := s.GetStream(id)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	source := item.StreamURL
+	switch rest {
+	case "":
+	case "poster":
+		source = item.PosterURL
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if source == "" {
+		http.NotFound(w, r)
+		return
+	}
+	target, err := url.Parse(source)
+	if err != nil {
+		http.Error(w, "invalid source URL", http.StatusInternalServerError)
+		return
+	}
+
+	rp := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.Out.URL = target
+			pr.Out.Host = target.Host
+			// The consumer's credentials are for this server, not Plex.
+			pr.Out.Header.Del("Authorization")
+			pr.Out.Header.Del("Cookie")
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, "failed to reach Plex", http.StatusBadGateway)
+		},
+	}
+	rp.ServeHTTP(w, r)
+}
@@ -0,0 +1,102 @@
+package stream
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProxy(t *testing.T) {
+	content := strings.NewReader("0123456789")
+	var plexAuth string
+	plex := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		plexAuth = r.Header.Get("Authorization")
+		if r.URL.Query().Get("X-Plex-Token") != "plextoken" {
+			http.Error(w, "no token", http.StatusUnauthorized)
+			return
+		}
+		http.ServeContent(w, r, "file.mkv", time.Time{}, content)
+	}))
+	defer plex.Close()
+
+	s, err := NewServer(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.RequireToken("secret")
+	s.EnableProxy()
+	id := "stream-1"
+	s.streams[id] = &StreamItem{
+		ID:        id,
+		StreamURL: plex.URL + "/library/parts/1/file.mkv?X-Plex-Token=plextoken",
+		PosterURL: plex.URL + "/thumb?X-Plex-Token=plextoken",
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/streams", s.protect(s.handleListStreams))
+	mux.HandleFunc("/proxy/", s.protect(s.handleProxy))
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/streams", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if strings.Contains(string(body), "plextoken") {
+		t.Fatalf("/streams leaks the Plex token: %s", body)
+	}
+	var list struct {
+		Streams []*StreamItem `json:"streams"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil || len(list.Streams) != 1 {
+		t.Fatalf("decode /streams: %v, %s", err, body)
+	}
+	if want := ts.URL + "/proxy/" + id + "?token=secret"; list.Streams[0].StreamURL != want {
+		t.Errorf("StreamURL = %q, want %q", list.Streams[0].StreamURL, want)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, list.Streams[0].StreamURL, nil)
+	req.Header.Set("Range", "bytes=2-5")
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusPartialContent || string(body) != "2345" {
+		t.Errorf("ranged proxy response = %d %q, want 206 \"2345\"", resp.StatusCode, body)
+	}
+	if plexAuth != "" {
+		t.Errorf("the consumer's Authorization header reached Plex: %q", plexAuth)
+	}
+
+	for _, path := range []string{"/proxy/unknown", "/proxy/" + id + "/other"} {
+		resp, err := http.Get(ts.URL + path + "?token=secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("GET %s = %d, want 404", path, resp.StatusCode)
+		}
+	}
+}
+
+func TestStreamPathWithoutProxy(t *testing.T) {
+	s, err := NewServer(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.StreamPath("x"); got != "" {
+		t.Errorf("StreamPath without proxy = %q, want empty", got)
+	}
+}
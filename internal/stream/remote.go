@@ -0,0 +1,124 @@
+package stream
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// Remote is the player the remote-control endpoints drive. MPVClient from
+// the progress package implements it.
+type Remote interface {
+	GetPaused() (bool, error)
+	GetTimePos() (float64, error)
+	SetPaused(paused bool) error
+	Seek(seconds float64) error
+	SeekRelative(seconds float64) error
+	PlaylistNext() error
+	GetChapter() (int, error)
+	AddChapter(delta int) error
+}
+
+// SetRemote attaches the player driven by POST /play, /pause, /seek,
+// /chapter and /next, or detaches it with nil. It can be called while the server runs,
+// e.g. as playback starts and stops.
+func (s *Server) SetRemote(r Remote) {
+	s.remoteMu.Lock()
+	s.remote = r
+	s.remoteMu.Unlock()
+}
+
+func (s *Server) currentRemote() Remote {
+	s.remoteMu.RLock()
+	defer s.remoteMu.RUnlock()
+	return s.remote
+}
+
+// handleRemote serves the remote-control endpoints. Each answers with the
+// player's state afterwards, so the web page can show it:
+//
+//	POST /play                 resume
+//	POST /pause                pause
+//	POST /seek?to=SECONDS      jump to an absolute position
+//	POST /seek?by=SECONDS      move relative to the current position
+//	POST /chapter?by=N         move N chapters forward, or back if negative
+//	POST /next                 skip to the next playlist item
+//	GET  /remote/status        just the state
+func (s *Server) handleRemote(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Path == "/remote/status"
+	if status && r.Method != http.MethodGet || !status && r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	remote := s.currentRemote()
+	if remote == nil {
+		http.Error(w, "Nothing is playing", http.StatusServiceUnavailable)
+		return
+	}
+
+	var err error
+	switch r.URL.Path {
+	case "/remote/status":
+	case "/play":
+		err = remote.SetPaused(false)
+	case "/pause":
+		err = remote.SetPaused(true)
+	case "/next":
+		err = remote.PlaylistNext()
+	case "/chapter":
+		delta, perr := strconv.Atoi(r.FormValue("by"))
+		if perr != nil || delta == 0 {
+			http.Error(w, "chapter needs a non-zero 'by'", http.StatusBadRequest)
+			return
+		}
+		err = remote.AddChapter(delta)
+	case "/seek":
+		if to := r.FormValue("to"); to != "" {
+			var secs float64
+			if secs, err = strconv.ParseFloat(to, 64); err != nil || secs < 0 {
+				http.Error(w, "invalid 'to' position", http.StatusBadRequest)
+				return
+			}
+			err = remote.Seek(secs)
+		} else if by := r.FormValue("by"); by != "" {
+			var secs float64
+			if secs, err = strconv.ParseFloat(by, 64); err != nil {
+				http.Error(w, "invalid 'by' offset", http.StatusBadRequest)
+				return
+			}
+			err = remote.SeekRelative(secs)
+		} else {
+			http.Error(w, "seek needs 'to' or 'by'", http.StatusBadRequest)
+			return
+		}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	// The state is informational; a player still loading the file has none.
+	paused, _ := remote.GetPaused()
+	pos, _ := remote.GetTimePos()
+	state := map[string]interface{}{
+		"paused":   paused,
+		"position": pos,
+	}
+	// Files without chapters have no chapter property.
+	if chapter, err := remote.GetChapter(); err == nil {
+		state["chapter"] = chapter
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(state)
+}
+
+// handleRemotePage serves the phone-sized remote control page at /remote.
+func (s *Server) handleRemotePage(w http.ResponseWriter, r *http.Request) {
+	data := struct{ ServerName string }{s.hostname}
+	if err := templates.ExecuteTemplate(w, "remote.html", data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
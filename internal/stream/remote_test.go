@@ -0,0 +1,106 @@
+package stream
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type fakeRemote struct {
+	paused  bool
+	pos     float64
+	next    int
+	chapter int
+}
+
+func (f *fakeRemote) GetPaused() (bool, error)        { return f.paused, nil }
+func (f *fakeRemote) GetTimePos() (float64, error)    { return f.pos, nil }
+func (f *fakeRemote) SetPaused(p bool) error          { f.paused = p; return nil }
+func (f *fakeRemote) Seek(secs float64) error         { f.pos = secs; return nil }
+func (f *fakeRemote) SeekRelative(secs float64) error { f.pos += secs; return nil }
+func (f *fakeRemote) PlaylistNext() error             { f.next++; return nil }
+func (f *fakeRemote) GetChapter() (int, error)        { return f.chapter, nil }
+func (f *fakeRemote) AddChapter(delta int) error      { f.chapter += delta; return nil }
+
+func TestHandleRemote(t *testing.T) {
+	s, err := NewServer(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(method, target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		s.handleRemote(rec, httptest.NewRequest(method, target, nil))
+		return rec
+	}
+
+	if rec := do(http.MethodPost, "/pause"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("without a player: status = %d, want 503", rec.Code)
+	}
+
+	f := &fakeRemote{pos: 100}
+	s.SetRemote(f)
+
+	if rec := do(http.MethodPost, "/pause"); rec.Code != http.StatusOK || !f.paused {
+		t.Errorf("/pause: status = %d, paused = %t", rec.Code, f.paused)
+	}
+	if rec := do(http.MethodPost, "/play"); rec.Code != http.StatusOK || f.paused {
+		t.Errorf("/play: status = %d, paused = %t", rec.Code, f.paused)
+	}
+	if rec := do(http.MethodPost, "/seek?by=-30"); rec.Code != http.StatusOK || f.pos != 70 {
+		t.Errorf("/seek?by=-30: status = %d, pos = %v", rec.Code, f.pos)
+	}
+	rec := do(http.MethodPost, "/seek?to=12.5")
+	if rec.Code != http.StatusOK || f.pos != 12.5 {
+		t.Errorf("/seek?to=12.5: status = %d, pos = %v", rec.Code, f.pos)
+	}
+	if !strings.Contains(rec.Body.String(), `"position":12.5`) {
+		t.Errorf("response should report the state, got %s", rec.Body.String())
+	}
+	if rec := do(http.MethodPost, "/next"); rec.Code != http.StatusOK || f.next != 1 {
+		t.Errorf("/next: status = %d, next = %d", rec.Code, f.next)
+	}
+	rec = do(http.MethodPost, "/chapter?by=2")
+	if rec.Code != http.StatusOK || f.chapter != 2 {
+		t.Errorf("/chapter?by=2: status = %d, chapter = %d", rec.Code, f.chapter)
+	}
+	if !strings.Contains(rec.Body.String(), `"chapter":2`) {
+		t.Errorf("response should report the chapter, got %s", rec.Body.String())
+	}
+	if rec := do(http.MethodGet, "/remote/status"); rec.Code != http.StatusOK {
+		t.Errorf("/remote/status: status = %d", rec.Code)
+	}
+
+	for _, tc := range []struct {
+		method, target string
+		want           int
+	}{
+		{http.MethodGet, "/pause", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/remote/status", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/seek", http.StatusBadRequest},
+		{http.MethodPost, "/seek?to=-1", http.StatusBadRequest},
+		{http.MethodPost, "/seek?by=soon", http.StatusBadRequest},
+		{http.MethodPost, "/chapter", http.StatusBadRequest},
+	} {
+		if rec := do(tc.method, tc.target); rec.Code != tc.want {
+			t.Errorf("%s %s: status = %d, want %d", tc.method, tc.target, rec.Code, tc.want)
+		}
+	}
+
+	s.SetRemote(nil)
+	if rec := do(http.MethodPost, "/next"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("after detaching: status = %d, want 503", rec.Code)
+	}
+}
+
+func TestRemotePage(t *testing.T) {
+	s, err := NewServer(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	s.handleRemotePage(rec, httptest.NewRequest(http.MethodGet, "/remote", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "/remote/status") {
+		t.Errorf("remote page: status = %d", rec.Code)
+	}
+}
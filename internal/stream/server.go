@@ -2,7 +2,9 @@ package stream
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -30,6 +32,9 @@ type StreamItem struct {
 	Summary     string    `json:"summary,omitempty"`
 	StreamURL   string    `json:"stream_url"`
 	PosterURL   string    `json:"poster_url,omitempty"`
+	// HLSPath is the server-relative HLS playlist path, set only when the
+	// server has HLS enabled (see EnableHLS).
+	HLSPath     string    `json:"hls_path,omitempty"`
 	PublishedAt time.Time `json:"published_at"`
 }
 
@@ -41,6 +46,17 @@ type Server struct {
 	streamsMu  sync.RWMutex
 	httpServer *http.Server
 	mdnsServer *zeroconf.Server
+	hls        *hlsManager // nil unless EnableHLS was called
+	token      string      // empty unless RequireToken was called
+	proxy      bool        // set by EnableProxy
+
+	remote   Remote // nil unless a player is attached with SetRemote
+	remoteMu sync.RWMutex
+
+	party *party // nil unless EnableParty was called
+
+	tlsConfig   *tls.Config // nil unless EnableTLS was called
+	fingerprint string
 }
 
 // NewServer creates a new stream server
@@ -61,23 +77,57 @@ func NewServer(port int) (*Server, error) {
 	}, nil
 }
 
+// EnableHLS turns on the /hls/{id}/index.m3u8 endpoint. It must be called
+// before Start and fails if ffmpeg can't be found.
+func (s *Server) EnableHLS(opts HLSOptions) error {
+	hls, err := newHLSManager(opts)
+	if err != nil {
+		return err
+	}
+	s.hls = hls
+	return nil
+}
+
 // Start starts the HTTP and mDNS services
 func (s *Server) Start(ctx context.Context) error {
 	// Setup HTTP server
 	mux := http.NewServeMux()
-	mux.HandleFunc("/", s.handleWebUI)
-	mux.HandleFunc("/streams", s.handleListStreams)
+	mux.HandleFunc("/", s.protect(s.handleWebUI))
+	mux.HandleFunc("/streams", s.protect(s.handleListStreams))
+	mux.HandleFunc("/playlist.m3u8", s.protect(s.handlePlaylist))
 	mux.HandleFunc("/health", s.handleHealth)
+	mux.HandleFunc("/remote", s.protect(s.handleRemotePage))
+	for _, path := range []string{"/remote/status", "/play", "/pause", "/seek", "/chapter", "/next"} {
+		mux.HandleFunc(path, s.protect(s.handleRemote))
+	}
+	if s.hls != nil {
+		mux.HandleFunc("/hls/", s.protect(s.handleHLS))
+	}
+	if s.proxy {
+		mux.HandleFunc("/proxy/", s.protect(s.handleProxy))
+	}
+	if s.party != nil {
+		mux.HandleFunc("/party", s.protect(s.handleParty))
+		mux.HandleFunc("/party/events", s.protect(s.handleParty))
+	}
 
 	s.httpServer = &http.Server{
-		Addr:    fmt.Sprintf(":%d", s.port),
-		Handler: mux,
+		Addr:      fmt.Sprintf(":%d", s.port),
+		Handler:   mux,
+		TLSConfig: s.tlsConfig,
 	}
 
 	// Start HTTP server in background
 	errChan := make(chan error, 1)
 	go func() {
-		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if s.tlsConfig != nil {
+			// The certificate is already in TLSConfig
+			err = s.httpServer.ListenAndServeTLS("", "")
+		} else {
+			err = s.httpServer.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			errChan <- fmt.Errorf("http server failed: %w", err)
 		}
 	}()
@@ -86,12 +136,22 @@ func (s *Server) Start(ctx context.Context) error {
 	time.Sleep(100 * time.Millisecond)
 
 	// Register mDNS service
+	txt := []string{"path=/streams"}
+	if s.token != "" {
+		txt = append(txt, authTXT)
+	}
+	if s.tlsConfig != nil {
+		txt = append(txt, fingerprintTXT+s.fingerprint)
+	}
+	if s.party != nil {
+		txt = append(txt, partyTXT)
+	}
 	mdnsServer, err := zeroconf.Register(
 		s.hostname,      // Instance name
 		ServiceType,     // Service type
 		ServiceDomain,   // Domain
 		s.port,          // Port
-		[]string{"path=/streams"}, // TXT records
+		txt,             // TXT records
 		nil,             // Network interface (nil = all)
 	)
 	if err != nil {
@@ -112,6 +172,10 @@ func (s *Server) Start(ctx context.Context) error {
 
 // Shutdown gracefully shuts down the server
 func (s *Server) Shutdown() error {
+	if s.hls != nil {
+		s.hls.close()
+	}
+
 	// Shutdown mDNS in background with timeout
 	if s.mdnsServer != nil {
 		done := make(chan struct{})
@@ -128,6 +192,11 @@ func (s *Server) Shutdown() error {
 		}
 	}
 	
+	// End the party's event streams, which Shutdown would otherwise wait on
+	if s.party != nil {
+		s.party.close()
+	}
+
 	// Shutdown HTTP server
 	if s.httpServer != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -159,7 +228,10 @@ func (s *Server) PublishStream(media *plex.MediaItem, streamURL string, plexURL
 		Summary:     media.Summary,
 		StreamURL:   streamURL,
 		PosterURL:   posterURL,
-		PublishedAt: time.Now(),
+		PublishedAt: time.Now().UTC(),
+	}
+	if s.hls != nil {
+		stream.HLSPath = s.withToken("/hls/" + id + "/index.m3u8")
 	}
 
 	s.streams[id] = stream
@@ -169,8 +241,12 @@ func (s *Server) PublishStream(media *plex.MediaItem, streamURL string, plexURL
 // RemoveStream removes a published stream
 func (s *Server) RemoveStream(id string) {
 	s.streamsMu.Lock()
-	defer s.streamsMu.Unlock()
 	delete(s.streams, id)
+	s.streamsMu.Unlock()
+
+	if s.hls != nil {
+		s.hls.stop(id)
+	}
 }
 
 // GetStream retrieves a stream by ID
@@ -201,7 +277,7 @@ func (s *Server) handleListStreams(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	streams := s.ListStreams()
+	streams := s.publicStreams(r)
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(map[string]interface{}{
 		"streams": streams,
@@ -238,6 +314,14 @@ type DiscoveredServer struct {
 	Host      string
 	Port      int
 	Addresses []string
+	// AuthRequired is set when the server advertises that /streams needs
+	// an access token.
+	AuthRequired bool
+	// Fingerprint is the SHA-256 of the server's TLS certificate, set when
+	// it serves HTTPS.
+	Fingerprint string
+	// Party is set when the server hosts a watch party.
+	Party bool
 }
 
 // Discover finds goplexcli servers on the local network
@@ -274,6 +358,17 @@ func Discover(ctx context.Context, timeout time.Duration) ([]*DiscoveredServer,
 				Port:      entry.Port,
 				Addresses: addresses,
 			}
+			for _, txt := range entry.Text {
+				if txt == authTXT {
+					server.AuthRequired = true
+				}
+				if fp, ok := strings.CutPrefix(txt, fingerprintTXT); ok {
+					server.Fingerprint = fp
+				}
+				if txt == partyTXT {
+					server.Party = true
+				}
+			}
 			servers = append(servers, server)
 			mu.Unlock()
 		}
@@ -300,8 +395,9 @@ func Discover(ctx context.Context, timeout time.Duration) ([]*DiscoveredServer,
 	return servers, nil
 }
 
-// FetchStreams fetches available streams from a discovered server
-func FetchStreams(server *DiscoveredServer) ([]*StreamItem, error) {
+// FetchStreams fetches available streams from a discovered server. token is
+// sent as a bearer token when non-empty.
+func FetchStreams(server *DiscoveredServer, token string) ([]*StreamItem, error) {
 	if len(server.Addresses) == 0 {
 		return nil, fmt.Errorf("no addresses available for server")
 	}
@@ -314,10 +410,25 @@ func FetchStreams(server *DiscoveredServer) ([]*StreamItem, error) {
 		if strings.Contains(addr, ":") {
 			host = "[" + addr + "]"
 		}
-		url := fmt.Sprintf("http://%s:%d/streams", host, server.Port)
+		scheme := "http"
+		if server.Fingerprint != "" {
+			scheme = "https"
+		}
+		url := fmt.Sprintf("%s://%s:%d/streams", scheme, host, server.Port)
 		
-		client := &http.Client{Timeout: 5 * time.Second}
-		resp, err := client.Get(url)
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		client := &http.Client{
+			Timeout:   5 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: clientTLSConfig(server.Fingerprint)},
+		}
+		resp, err := client.Do(req)
 		if err != nil {
 			lastErr = err
 			continue
@@ -327,6 +438,9 @@ func FetchStreams(server *DiscoveredServer) ([]*StreamItem, error) {
 		result, err := func() ([]*StreamItem, error) {
 			defer resp.Body.Close()
 			
+			if resp.StatusCode == http.StatusUnauthorized {
+				return nil, ErrUnauthorized
+			}
 			if resp.StatusCode != http.StatusOK {
 				return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
 			}
@@ -341,6 +455,9 @@ func FetchStreams(server *DiscoveredServer) ([]*StreamItem, error) {
 			return result.Streams, nil
 		}()
 		
+		if errors.Is(err, ErrUnauthorized) {
+			return nil, err
+		}
 		if err != nil {
 			lastErr = err
 			continue
@@ -0,0 +1,143 @@
+package stream
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+)
+
+// TLSOptions configures HTTPS for the stream server. With both paths empty
+// a self-signed certificate is generated for the session.
+type TLSOptions struct {
+	CertFile string
+	KeyFile  string
+}
+
+// fingerprintTXT prefixes the mDNS TXT record carrying the certificate's
+// SHA-256 fingerprint, which clients pin instead of trusting a self-signed
+// certificate blindly.
+const fingerprintTXT = "fp="
+
+// selfSignedValidity is how long a generated certificate is valid. It only
+// lives as long as the server, so this just needs to outlast a session.
+const selfSignedValidity = 30 * 24 * time.Hour
+
+// EnableTLS serves HTTPS instead of HTTP. It must be called before Start.
+func (s *Server) EnableTLS(opts TLSOptions) error {
+	var cert tls.Certificate
+	var err error
+	switch {
+	case opts.CertFile != "" && opts.KeyFile != "":
+		cert, err = tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+	case opts.CertFile != "" || opts.KeyFile != "":
+		return fmt.Errorf("TLS needs both a certificate and a key file")
+	default:
+		cert, err = selfSignedCert(s.hostname)
+		if err != nil {
+			return err
+		}
+	}
+	s.tlsConfig = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	s.fingerprint = certFingerprint(cert.Certificate[0])
+	return nil
+}
+
+// Scheme returns "https" when TLS is enabled and "http" otherwise, for
+// building URLs to the server.
+func (s *Server) Scheme() string {
+	if s.tlsConfig != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// Fingerprint returns the SHA-256 fingerprint of the server's certificate as
+// hex, or "" without TLS. Users can compare it with what a browser shows.
+func (s *Server) Fingerprint() string {
+	return s.fingerprint
+}
+
+// certFingerprint hashes a DER certificate.
+func certFingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:])
+}
+
+// selfSignedCert creates an ECDSA certificate for the host name, localhost
+// and every local IP address, so it matches whichever the client dials.
+func selfSignedCert(hostname string) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to generate TLS key: %w", err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to generate certificate serial: %w", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: hostname, Organization: []string{"goplexcli"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(selfSignedValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{hostname, "localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	if !strings.HasSuffix(hostname, ".local") {
+		tmpl.DNSNames = append(tmpl.DNSNames, hostname+".local")
+	}
+	if addrs, err := net.InterfaceAddrs(); err == nil {
+		for _, addr := range addrs {
+			if ipnet, ok := addr.(*net.IPNet); ok && !ipnet.IP.IsLoopback() {
+				tmpl.IPAddresses = append(tmpl.IPAddresses, ipnet.IP)
+			}
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to create TLS certificate: %w", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
+
+// clientTLSConfig returns the TLS config for fetching from a discovered
+// server. A server that advertised a fingerprint is pinned to it, which is
+// how self-signed certificates are trusted; otherwise the system roots are.
+func clientTLSConfig(fingerprint string) *tls.Config {
+	if fingerprint == "" {
+		return &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// Verification is done by pinning below, not by a CA chain.
+		InsecureSkipVerify: true,
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return fmt.Errorf("stream server sent no certificate")
+			}
+			if got := certFingerprint(cs.PeerCertificates[0].Raw); !strings.EqualFold(got, fingerprint) {
+				return fmt.Errorf("stream server certificate fingerprint %s does not match the advertised %s", got, fingerprint)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,54 @@
+package stream
+
+import (
+	"net"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestEnableTLSSelfSigned(t *testing.T) {
+	s, err := NewServer(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Scheme() != "http" || s.Fingerprint() != "" {
+		t.Fatalf("a new server should be plain HTTP")
+	}
+	if err := s.EnableTLS(TLSOptions{}); err != nil {
+		t.Fatalf("EnableTLS: %v", err)
+	}
+	if s.Scheme() != "https" || len(s.Fingerprint()) != 64 {
+		t.Errorf("Scheme = %q, Fingerprint = %q", s.Scheme(), s.Fingerprint())
+	}
+
+	ts := httptest.NewUnstartedServer(s.protect(s.handleListStreams))
+	ts.TLS = s.tlsConfig
+	ts.StartTLS()
+	defer ts.Close()
+
+	host, portStr, _ := net.SplitHostPort(ts.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	server := &DiscoveredServer{Name: "test", Port: port, Addresses: []string{host}, Fingerprint: s.Fingerprint()}
+	if _, err := FetchStreams(server, ""); err != nil {
+		t.Errorf("FetchStreams with the pinned fingerprint: %v", err)
+	}
+
+	server.Fingerprint = certFingerprint([]byte("some other certificate"))
+	if _, err := FetchStreams(server, ""); err == nil {
+		t.Error("FetchStreams should reject a certificate that doesn't match the fingerprint")
+	}
+}
+
+func TestEnableTLSNeedsBothFiles(t *testing.T) {
+	s, err := NewServer(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.EnableTLS(TLSOptions{CertFile: "cert.pem"}); err == nil {
+		t.Error("a certificate without a key should be rejected")
+	}
+	if err := s.EnableTLS(TLSOptions{CertFile: "missing.pem", KeyFile: "missing.key"}); err == nil {
+		t.Error("missing certificate files should be reported")
+	}
+}
@@ -38,20 +38,22 @@ func init() {
 
 // WebHandler serves the web UI
 func (s *Server) handleWebUI(w http.ResponseWriter, r *http.Request) {
-	streams := s.ListStreams()
+	streams := s.publicStreams(r)
 	
 	data := struct {
-		Streams    []*StreamItem
-		ServerName string
-		Port       int
-		Time       string
-		LocalIP    string
+		Streams      []*StreamItem
+		ServerName   string
+		Port         int
+		Time         string
+		LocalIP      string
+		PlaylistPath string
 	}{
-		Streams:    streams,
-		ServerName: s.hostname,
-		Port:       s.port,
-		Time:       time.Now().Format("15:04:05"),
-		LocalIP:    getLocalIP(),
+		Streams:      streams,
+		ServerName:   s.hostname,
+		Port:         s.port,
+		Time:         time.Now().Format("15:04:05"),
+		LocalIP:      getLocalIP(),
+		PlaylistPath: s.PlaylistPath(),
 	}
 	
 	if err := templates.ExecuteTemplate(w, "index.html", data); err != nil {
@@ -0,0 +1,96 @@
+package termimg
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"io"
+)
+
+// paletteLevels is the number of levels per channel in the fixed sixel
+// palette: a 6x6x6 color cube, which every sixel terminal's 256 color
+// registers hold and which needs no per-image quantization pass.
+const paletteLevels = 6
+
+// writeSixel encodes img as sixel graphics. Each band of six pixel rows is
+// drawn once per color it uses, with runs of the same column pattern
+// compressed.
+func writeSixel(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+	colors := paletteLevels * paletteLevels * paletteLevels
+
+	idx := make([]int, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r, g, bl, _ := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+			idx[y*width+x] = level(r)*paletteLevels*paletteLevels + level(g)*paletteLevels + level(bl)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "\x1bPq\"1;1;%d;%d", width, height)
+	for i := 0; i < colors; i++ {
+		step := 100 / (paletteLevels - 1)
+		r, g, bl := i/(paletteLevels*paletteLevels), i/paletteLevels%paletteLevels, i%paletteLevels
+		fmt.Fprintf(bw, "#%d;2;%d;%d;%d", i, r*step, g*step, bl*step)
+	}
+
+	row := make([]byte, width)
+	used := make([]bool, colors)
+	for top := 0; top < height; top += 6 {
+		bottom := min(top+6, height)
+		clear(used)
+		for i := top * width; i < bottom*width; i++ {
+			used[idx[i]] = true
+		}
+		first := true
+		for c := range used {
+			if !used[c] {
+				continue
+			}
+			if !first {
+				bw.WriteByte('$') // back to the start of the band
+			}
+			first = false
+			for x := 0; x < width; x++ {
+				bits := 0
+				for y := top; y < bottom; y++ {
+					if idx[y*width+x] == c {
+						bits |= 1 << (y - top)
+					}
+				}
+				row[x] = byte('?' + bits)
+			}
+			fmt.Fprintf(bw, "#%d", c)
+			writeRuns(bw, row)
+		}
+		bw.WriteByte('-') // next band
+	}
+	bw.WriteString("\x1b\\\n")
+	return bw.Flush()
+}
+
+// level maps a 16-bit color channel to the nearest palette level.
+func level(v uint32) int {
+	return (int(v>>8)*(paletteLevels-1) + 127) / 255
+}
+
+// writeRuns writes sixel characters, using the repeat introducer for runs
+// longer than three.
+func writeRuns(w *bufio.Writer, row []byte) {
+	for i := 0; i < len(row); {
+		j := i
+		for j < len(row) && row[j] == row[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			fmt.Fprintf(w, "!%d%c", n, row[i])
+		} else {
+			for k := i; k < j; k++ {
+				w.WriteByte(row[k])
+			}
+		}
+		i = j
+	}
+}
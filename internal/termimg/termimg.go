@@ -0,0 +1,169 @@
+// Package termimg draws images inline in a terminal, using the terminal's
+// own graphics protocol (kitty, iTerm2 or sixel) where it has one and chafa's
+// character art otherwise. goplexcli uses it for posters in the fzf preview,
+// which passes these sequences through to the terminal.
+package termimg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	_ "image/jpeg" // posters are JPEG
+	"image/png"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Protocol is a way of drawing an image in a terminal.
+type Protocol string
+
+const (
+	// None draws nothing.
+	None Protocol = "off"
+	// Kitty is the kitty graphics protocol (kitty, Ghostty, Konsole).
+	Kitty Protocol = "kitty"
+	// ITerm2 is iTerm2's inline image protocol (iTerm2, WezTerm).
+	ITerm2 Protocol = "iterm2"
+	// Sixel is DEC sixel graphics (foot, mlterm, xterm -ti vt340, ...).
+	Sixel Protocol = "sixel"
+	// Symbols is chafa's character art, for terminals without graphics.
+	Symbols Protocol = "symbols"
+)
+
+// ParseProtocol parses a configured protocol. "" and "auto" mean detect it
+// from the environment.
+func ParseProtocol(s string, getenv func(string) string) (Protocol, error) {
+	switch p := Protocol(strings.ToLower(s)); p {
+	case "", "auto":
+		return Detect(getenv), nil
+	case Kitty, ITerm2, Sixel, Symbols, None:
+		return p, nil
+	}
+	return None, fmt.Errorf("unknown image protocol %q (want auto, kitty, iterm2, sixel, symbols or off)", s)
+}
+
+// Detect guesses the best protocol the terminal supports from its
+// environment variables, which fzf passes on to the preview command. Under
+// tmux or screen, graphics sequences don't reach the terminal, so only
+// chafa's symbols are used.
+func Detect(getenv func(string) string) Protocol {
+	term, program := getenv("TERM"), getenv("TERM_PROGRAM")
+	switch {
+	case getenv("TMUX") != "" || strings.HasPrefix(term, "screen") || strings.HasPrefix(term, "tmux"):
+	case getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" || term == "xterm-ghostty" || program == "ghostty":
+		return Kitty
+	case program == "iTerm.app" || program == "WezTerm" || getenv("LC_TERMINAL") == "iTerm2":
+		return ITerm2
+	case strings.HasPrefix(term, "foot") || strings.HasPrefix(term, "mlterm") || strings.HasPrefix(term, "contour") || strings.Contains(term, "sixel"):
+		return Sixel
+	}
+	if _, err := exec.LookPath("chafa"); err == nil {
+		return Symbols
+	}
+	return None
+}
+
+// cellWidth and cellHeight approximate a terminal cell in pixels, for
+// protocols that need a pixel size rather than a cell count.
+const (
+	cellWidth  = 10
+	cellHeight = 20
+)
+
+// Draw writes the image file at path to w using p, sized to cols by rows
+// terminal cells, and ends on a new line below it.
+func Draw(w io.Writer, p Protocol, path string, cols, rows int) error {
+	if p == None || cols <= 0 || rows <= 0 {
+		return nil
+	}
+	if p == Symbols {
+		cmd := exec.Command("chafa", "--size", fmt.Sprintf("%dx%d", cols, rows), "--format", "symbols", "--animate", "off", path)
+		cmd.Stdout = w
+		return cmd.Run()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	switch p {
+	case ITerm2:
+		// iTerm2 decodes the JPEG itself.
+		_, err = fmt.Fprintf(w, "\x1b]1337;File=inline=1;width=%d;height=%d;preserveAspectRatio=1;size=%d:%s\a\n",
+			cols, rows, len(data), base64.StdEncoding.EncodeToString(data))
+		return err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("decode %s: %w", path, err)
+	}
+	switch p {
+	case Kitty:
+		return writeKitty(w, img, cols, rows)
+	case Sixel:
+		return writeSixel(w, fit(img, cols*cellWidth, rows*cellHeight))
+	}
+	return fmt.Errorf("unknown image protocol %q", p)
+}
+
+// kittyClear deletes the images kitty is showing, such as the previous
+// item's poster.
+const kittyClear = "\x1b_Ga=d,q=2\x1b\\"
+
+// writeKitty sends img as PNG in the chunks the kitty protocol requires,
+// scaled by the terminal into cols by rows cells. q=2 keeps the terminal
+// from answering on stdin, which fzf would read as keystrokes.
+func writeKitty(w io.Writer, img image.Image, cols, rows int) error {
+	if _, err := io.WriteString(w, kittyClear); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return err
+	}
+	payload := base64.StdEncoding.EncodeToString(buf.Bytes())
+	const chunk = 4096
+	for i := 0; i < len(payload); i += chunk {
+		end := min(i+chunk, len(payload))
+		more := 0
+		if end < len(payload) {
+			more = 1
+		}
+		var err error
+		if i == 0 {
+			_, err = fmt.Fprintf(w, "\x1b_Ga=T,f=100,q=2,c=%d,r=%d,m=%d;%s\x1b\\", cols, rows, more, payload[i:end])
+		} else {
+			_, err = fmt.Fprintf(w, "\x1b_Gm=%d;%s\x1b\\", more, payload[i:end])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// fit scales img with nearest-neighbour sampling to fit in maxW by maxH
+// pixels, keeping its aspect ratio.
+func fit(img image.Image, maxW, maxH int) image.Image {
+	b := img.Bounds()
+	if b.Dx() == 0 || b.Dy() == 0 {
+		return img
+	}
+	w, h := maxW, b.Dy()*maxW/b.Dx()
+	if h > maxH {
+		w, h = b.Dx()*maxH/b.Dy(), maxH
+	}
+	w, h = max(w, 1), max(h, 1)
+	out := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			out.Set(x, y, img.At(b.Min.X+x*b.Dx()/w, b.Min.Y+y*b.Dy()/h))
+		}
+	}
+	return out
+}
@@ -0,0 +1,211 @@
+package termimg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	t.Setenv("PATH", "") // no chafa
+	tests := []struct {
+		env  map[string]string
+		want Protocol
+	}{
+		{map[string]string{"TERM": "xterm-kitty"}, Kitty},
+		{map[string]string{"TERM": "xterm-256color", "KITTY_WINDOW_ID": "1"}, Kitty},
+		{map[string]string{"TERM_PROGRAM": "ghostty"}, Kitty},
+		{map[string]string{"TERM_PROGRAM": "iTerm.app"}, ITerm2},
+		{map[string]string{"TERM_PROGRAM": "WezTerm"}, ITerm2},
+		{map[string]string{"TERM": "foot"}, Sixel},
+		{map[string]string{"TERM": "xterm-kitty", "TMUX": "/tmp/tmux-1000/default,1,0"}, None},
+		{map[string]string{"TERM": "xterm-256color"}, None},
+	}
+	for _, tt := range tests {
+		if got := Detect(func(k string) string { return tt.env[k] }); got != tt.want {
+			t.Errorf("Detect(%v) = %q, want %q", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestParseProtocol(t *testing.T) {
+	env := func(k string) string { return map[string]string{"TERM": "xterm-kitty"}[k] }
+	for in, want := range map[string]Protocol{"": Kitty, "auto": Kitty, "Sixel": Sixel, "off": None} {
+		if got, err := ParseProtocol(in, env); err != nil || got != want {
+			t.Errorf("ParseProtocol(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseProtocol("braille", env); err == nil {
+		t.Error("ParseProtocol(braille): expected an error")
+	}
+}
+
+func TestFit(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 200, 300))
+	if b := fit(img, 100, 300).Bounds(); b.Dx() != 100 || b.Dy() != 150 {
+		t.Errorf("fit to width = %v", b)
+	}
+	if b := fit(img, 400, 150).Bounds(); b.Dx() != 100 || b.Dy() != 150 {
+		t.Errorf("fit to height = %v", b)
+	}
+}
+
+// testPoster writes a JPEG and returns its path.
+func testPoster(t *testing.T) string {
+	img := image.NewRGBA(image.Rect(0, 0, 20, 30))
+	for y := 0; y < 30; y++ {
+		for x := 0; x < 20; x++ {
+			img.Set(x, y, color.RGBA{uint8(x * 12), uint8(y * 8), 128, 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "poster.jpg")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDrawKitty(t *testing.T) {
+	var out bytes.Buffer
+	if err := Draw(&out, Kitty, testPoster(t), 10, 8); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), kittyClear) {
+		t.Errorf("kitty output should clear the previous image first")
+	}
+	chunks := regexp.MustCompile("\x1b_G([^;]*);([^\x1b]*)\x1b\\\\").FindAllStringSubmatch(strings.TrimPrefix(out.String(), kittyClear), -1)
+	if len(chunks) == 0 || !strings.Contains(chunks[0][1], "a=T,f=100,q=2,c=10,r=8") {
+		t.Fatalf("unexpected kitty output %q", out.String())
+	}
+	var payload string
+	for i, c := range chunks {
+		last := i == len(chunks)-1
+		if strings.HasSuffix(c[1], "m=1") == last {
+			t.Errorf("chunk %d has control %q", i, c[1])
+		}
+		payload += c[2]
+	}
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := png.Decode(bytes.NewReader(data)); err != nil {
+		t.Errorf("kitty payload isn't a PNG: %v", err)
+	}
+}
+
+func TestDrawITerm2(t *testing.T) {
+	path := testPoster(t)
+	data, _ := os.ReadFile(path)
+	var out bytes.Buffer
+	if err := Draw(&out, ITerm2, path, 10, 8); err != nil {
+		t.Fatal(err)
+	}
+	want := "\x1b]1337;File=inline=1;width=10;height=8;preserveAspectRatio=1;size=" + strconv.Itoa(len(data)) + ":" + base64.StdEncoding.EncodeToString(data) + "\a\n"
+	if out.String() != want {
+		t.Errorf("iTerm2 output = %q", out.String())
+	}
+}
+
+func TestWriteSixel(t *testing.T) {
+	// 2x7 pixels: two bands, palette colors only, so decoding is exact.
+	red, blue := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}
+	img := image.NewRGBA(image.Rect(0, 0, 2, 7))
+	for y := 0; y < 7; y++ {
+		img.Set(0, y, red)
+		img.Set(1, y, blue)
+	}
+	img.Set(1, 6, red)
+
+	var out bytes.Buffer
+	if err := writeSixel(&out, img); err != nil {
+		t.Fatal(err)
+	}
+	got := decodeSixel(t, out.String())
+	for y := 0; y < 7; y++ {
+		for x := 0; x < 2; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			want := [3]int{int(r>>8) * 100 / 255, int(g>>8) * 100 / 255, int(b>>8) * 100 / 255}
+			if got[[2]int{x, y}] != want {
+				t.Errorf("pixel (%d,%d) = %v, want %v", x, y, got[[2]int{x, y}], want)
+			}
+		}
+	}
+}
+
+// decodeSixel is a minimal sixel decoder for the subset writeSixel emits,
+// returning each pixel's palette color in percent.
+func decodeSixel(t *testing.T, s string) map[[2]int][3]int {
+	t.Helper()
+	if !strings.HasPrefix(s, "\x1bPq") || !strings.HasSuffix(s, "\x1b\\\n") {
+		t.Fatalf("not a sixel sequence: %q", s)
+	}
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "\x1bPq"), "\x1b\\\n")
+	palette := map[int][3]int{}
+	pixels := map[[2]int][3]int{}
+	num := func(i *int) int {
+		start := *i
+		for *i < len(s) && s[*i] >= '0' && s[*i] <= '9' {
+			*i++
+		}
+		n, _ := strconv.Atoi(s[start:*i])
+		return n
+	}
+	x, band, current := 0, 0, 0
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == '"':
+			for i++; i < len(s) && (s[i] == ';' || s[i] >= '0' && s[i] <= '9'); i++ {
+			}
+		case c == '#':
+			i++
+			current = num(&i)
+			if i < len(s) && s[i] == ';' {
+				var v [4]int
+				for k := 0; k < 4; k++ {
+					i++
+					v[k] = num(&i)
+				}
+				palette[current] = [3]int{v[1], v[2], v[3]}
+			}
+		case c == '$':
+			x = 0
+			i++
+		case c == '-':
+			x, band = 0, band+1
+			i++
+		case c == '!' || c >= '?' && c <= '~':
+			n := 1
+			if c == '!' {
+				i++
+				n = num(&i)
+			}
+			bits := int(s[i] - '?')
+			for k := 0; k < n; k++ {
+				for dy := 0; dy < 6; dy++ {
+					if bits&(1<<dy) != 0 {
+						pixels[[2]int{x, band*6 + dy}] = palette[current]
+					}
+				}
+				x++
+			}
+			i++
+		default:
+			t.Fatalf("unexpected %q at %d", c, i)
+		}
+	}
+	return pixels
+}
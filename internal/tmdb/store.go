@@ -0,0 +1,230 @@
+package tmdb
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/config"
+	"github.com/joshkerr/goplexcli/internal/plex"
+	"github.com/joshkerr/goplexcli/internal/storage"
+)
+
+// storeSchemaVersion is the store file format this build reads and writes.
+const storeSchemaVersion = 1
+
+// RefreshAfter is how long a stored entry is used before it is looked up
+// again, picking up new recommendations.
+const RefreshAfter = 90 * 24 * time.Hour
+
+// enrichConcurrency is how many lookups Enrich runs at once, well within
+// TMDB's rate limit.
+const enrichConcurrency = 4
+
+// Store holds TMDB entries keyed by Key, so lookups survive cache reindexes
+// and are shared by copies of an item on several servers.
+type Store struct {
+	Version int              `json:"version"`
+	Entries map[string]Entry `json:"entries"`
+}
+
+// Key identifies item in the store by its type and first external ID, or
+// returns "" if the item has none.
+func Key(item *plex.MediaItem) string {
+	ids := item.ExternalIDs()
+	if len(ids) == 0 {
+		return ""
+	}
+	return item.Type + ":" + ids[0]
+}
+
+// Get returns item's entry if the store has one younger than RefreshAfter.
+func (s *Store) Get(item *plex.MediaItem) (Entry, bool) {
+	e, ok := s.Entries[Key(item)]
+	if !ok || time.Since(time.Unix(e.FetchedAt, 0)) > RefreshAfter {
+		return Entry{}, false
+	}
+	return e, true
+}
+
+// Apply fills in item's tagline and recommendations from e, and its summary
+// and poster only where Plex has none.
+func Apply(item *plex.MediaItem, e Entry) {
+	if item.Tagline == "" {
+		item.Tagline = e.Tagline
+	}
+	if item.Summary == "" {
+		item.Summary = e.Overview
+	}
+	if item.Thumb == "" && item.GrandparentThumb == "" {
+		item.PosterURL = e.PosterURL
+	}
+	if len(e.Similar) > 0 {
+		item.Similar = make([]string, len(e.Similar))
+		for i, t := range e.Similar {
+			item.Similar[i] = t.String()
+		}
+	}
+}
+
+// wants reports whether Enrich should look item up: every movie, but only
+// episodes whose Plex summary or poster is missing, since TMDB has little to
+// add to a well-matched episode and libraries hold thousands of them.
+func wants(item *plex.MediaItem) bool {
+	switch item.Type {
+	case "movie":
+		return true
+	case "episode":
+		return item.Summary == "" || (item.Thumb == "" && item.GrandparentThumb == "")
+	}
+	return false
+}
+
+// Enrich applies the store's entries to items, first looking up the ones it
+// lacks or holds stale. Fetched entries are added to the store and also
+// returned, for the caller to record with RecordEntries. Lookups that fail
+// are counted and skipped; a rejected API key or a cancelled ctx stops
+// enrichment and is returned as an error. progress, if non-nil, is called as
+// lookups finish.
+func (c *Client) Enrich(ctx context.Context, s *Store, items []plex.MediaItem, progress func(done, total int)) (fetched map[string]Entry, failed int, err error) {
+	if s.Entries == nil {
+		s.Entries = map[string]Entry{}
+	}
+
+	// Look up each key once, however many servers have the item.
+	todo := map[string]*plex.MediaItem{}
+	for i := range items {
+		item := &items[i]
+		if key := Key(item); key != "" && wants(item) {
+			if _, ok := s.Get(item); !ok {
+				todo[key] = item
+			}
+		}
+	}
+
+	fetched = map[string]Entry{}
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		sem  = make(chan struct{}, enrichConcurrency)
+		done int
+	)
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	for key, item := range todo {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+
+			e, lookupErr := c.Lookup(ctx, item)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case lookupErr == nil, errors.Is(lookupErr, ErrNotFound):
+				e.FetchedAt = time.Now().Unix()
+				fetched[key] = e
+				s.Entries[key] = e
+			case errors.Is(lookupErr, ErrUnauthorized):
+				cancel(lookupErr)
+			case ctx.Err() == nil:
+				failed++
+			}
+			done++
+			if progress != nil {
+				progress(done, len(todo))
+			}
+		}()
+	}
+	wg.Wait()
+	if err := context.Cause(ctx); err != nil {
+		return fetched, failed, err
+	}
+
+	for i := range items {
+		if e, ok := s.Entries[Key(&items[i])]; ok {
+			Apply(&items[i], e)
+		}
+	}
+	return fetched, failed, nil
+}
+
+// StorePath returns the JSON file holding the store, alongside the media
+// cache.
+func StorePath() (string, error) {
+	dir, err := config.GetCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tmdb.json"), nil
+}
+
+// SchemaVersion implements storage.Versioned.
+func (s *Store) SchemaVersion() int { return s.Version }
+
+// storeFile returns the store kept at path.
+func storeFile(path string) storage.File[Store] {
+	return storage.File[Store]{
+		Path:    path,
+		Name:    "TMDB store",
+		Version: storeSchemaVersion,
+		New:     func() *Store { return &Store{Version: storeSchemaVersion} },
+	}
+}
+
+// LoadStoreFrom reads the store at path. A missing file yields an empty
+// store.
+func LoadStoreFrom(path string) (*Store, error) {
+	s, _, err := storeFile(path).Load()
+	if err != nil {
+		return nil, err
+	}
+	if s.Entries == nil {
+		s.Entries = map[string]Entry{}
+	}
+	return s, nil
+}
+
+// SaveTo writes the store to path atomically.
+func (s *Store) SaveTo(path string) error {
+	s.Version = storeSchemaVersion
+	return storeFile(path).Save(s)
+}
+
+// LoadStore reads the default store file.
+func LoadStore() (*Store, error) {
+	path, err := StorePath()
+	if err != nil {
+		return nil, err
+	}
+	return LoadStoreFrom(path)
+}
+
+// RecordEntries merges entries into the default store under its lock, so
+// concurrent runs don't lose each other's lookups.
+func RecordEntries(entries map[string]Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	path, err := StorePath()
+	if err != nil {
+		return err
+	}
+	return storeFile(path).Update(func(s *Store) error {
+		if s.Entries == nil {
+			s.Entries = map[string]Entry{}
+		}
+		for key, e := range entries {
+			s.Entries[key] = e
+		}
+		s.Version = storeSchemaVersion
+		return nil
+	})
+}
@@ -0,0 +1,285 @@
+// Package tmdb fills in metadata Plex lacks from The Movie Database: taglines,
+// posters, summaries and similar-title recommendations. It is optional and
+// only used when the config has a TMDB API key.
+//
+// Items are looked up by the IMDb, TMDB and TVDB IDs Plex records during
+// indexing. Results are kept in a store next to the media cache (see Store),
+// so each item is fetched once and survives reindexing.
+package tmdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+// DefaultBaseURL is TMDB's v3 API.
+const DefaultBaseURL = "https://api.themoviedb.org/3"
+
+// ImageBaseURL prefixes TMDB's poster and still paths, at a size that suits
+// both the terminal preview and the GUI.
+const ImageBaseURL = "https://image.tmdb.org/t/p/w500"
+
+// httpTimeout bounds a single API request.
+const httpTimeout = 15 * time.Second
+
+// maxSimilar is how many recommendations are kept per item.
+const maxSimilar = 10
+
+var (
+	// ErrNotFound means TMDB has nothing for the item, or the item has no ID
+	// TMDB can look up.
+	ErrNotFound = errors.New("not found on TMDB")
+	// ErrUnauthorized means TMDB rejected the API key.
+	ErrUnauthorized = errors.New("TMDB rejected the API key")
+)
+
+// Title is a recommended movie or show.
+type Title struct {
+	ID   int    `json:"id,omitempty"` // TMDB movie or show ID
+	Name string `json:"name"`
+	Year int    `json:"year,omitempty"`
+}
+
+// String formats the title as "Name (Year)", or just the name when the year
+// is unknown.
+func (t Title) String() string {
+	if t.Year > 0 {
+		return fmt.Sprintf("%s (%d)", t.Name, t.Year)
+	}
+	return t.Name
+}
+
+// Entry is what TMDB knows about one movie or episode. Episodes carry their
+// show's tagline and recommendations.
+type Entry struct {
+	Tagline   string  `json:"tagline,omitempty"`
+	Overview  string  `json:"overview,omitempty"`
+	PosterURL string  `json:"poster_url,omitempty"`
+	Similar   []Title `json:"similar,omitempty"`
+	// FetchedAt is when the entry was looked up, in unix seconds. Entries
+	// for items TMDB doesn't know are stored empty, so they aren't retried
+	// on every cache update.
+	FetchedAt int64 `json:"fetched_at"`
+}
+
+// Client talks to the TMDB API.
+type Client struct {
+	apiKey  string
+	baseURL string
+	http    *http.Client
+
+	mu    sync.Mutex
+	shows map[int]*details // show details by TMDB ID, shared by its episodes
+}
+
+// New returns a client authenticating with apiKey, which may be a v3 API key
+// or a v4 read access token.
+func New(apiKey string) *Client {
+	return &Client{
+		apiKey:  strings.TrimSpace(apiKey),
+		baseURL: DefaultBaseURL,
+		http:    &http.Client{Timeout: httpTimeout},
+		shows:   map[int]*details{},
+	}
+}
+
+// details is the part of a movie or show response we use.
+type details struct {
+	Title           string `json:"title"` // movies
+	Name            string `json:"name"`  // shows
+	Tagline         string `json:"tagline"`
+	Overview        string `json:"overview"`
+	PosterPath      string `json:"poster_path"`
+	Recommendations struct {
+		Results []result `json:"results"`
+	} `json:"recommendations"`
+}
+
+// result is a movie, show or episode in a list of results.
+type result struct {
+	ID           int    `json:"id"`
+	Title        string `json:"title"`
+	Name         string `json:"name"`
+	ReleaseDate  string `json:"release_date"`
+	FirstAirDate string `json:"first_air_date"`
+	Overview     string `json:"overview"`
+	StillPath    string `json:"still_path"`
+	ShowID       int    `json:"show_id"`
+}
+
+func (r result) title() Title {
+	t := Title{ID: r.ID, Name: r.Title}
+	date := r.ReleaseDate
+	if t.Name == "" {
+		t.Name, date = r.Name, r.FirstAirDate
+	}
+	if len(date) >= 4 {
+		t.Year, _ = strconv.Atoi(date[:4])
+	}
+	return t
+}
+
+// findResponse is the reply to /find, which maps an external ID to TMDB
+// items.
+type findResponse struct {
+	MovieResults     []result `json:"movie_results"`
+	TVEpisodeResults []result `json:"tv_episode_results"`
+}
+
+// Lookup fetches TMDB's metadata for a movie or episode. Movies are looked
+// up by their TMDB ID, or by IMDb ID when Plex recorded none; episodes by
+// their IMDb or TVDB ID. It returns ErrNotFound for other item types and for
+// items TMDB doesn't know.
+func (c *Client) Lookup(ctx context.Context, item *plex.MediaItem) (Entry, error) {
+	switch item.Type {
+	case "movie":
+		return c.lookupMovie(ctx, item)
+	case "episode":
+		return c.lookupEpisode(ctx, item)
+	}
+	return Entry{}, ErrNotFound
+}
+
+func (c *Client) lookupMovie(ctx context.Context, item *plex.MediaItem) (Entry, error) {
+	id := item.TMDBID
+	if id == "" && item.IMDbID != "" {
+		found, err := c.find(ctx, item.IMDbID, "imdb_id")
+		if err != nil {
+			return Entry{}, err
+		}
+		if len(found.MovieResults) == 0 {
+			return Entry{}, ErrNotFound
+		}
+		id = strconv.Itoa(found.MovieResults[0].ID)
+	}
+	if id == "" {
+		return Entry{}, ErrNotFound
+	}
+
+	var d details
+	if err := c.get(ctx, "/movie/"+url.PathEscape(id), url.Values{"append_to_response": {"recommendations"}}, &d); err != nil {
+		return Entry{}, err
+	}
+	return d.entry(), nil
+}
+
+func (c *Client) lookupEpisode(ctx context.Context, item *plex.MediaItem) (Entry, error) {
+	var episode *result
+	for _, ext := range []struct{ id, source string }{{item.IMDbID, "imdb_id"}, {item.TVDBID, "tvdb_id"}} {
+		if ext.id == "" {
+			continue
+		}
+		found, err := c.find(ctx, ext.id, ext.source)
+		if err != nil {
+			return Entry{}, err
+		}
+		if len(found.TVEpisodeResults) > 0 {
+			episode = &found.TVEpisodeResults[0]
+			break
+		}
+	}
+	if episode == nil {
+		return Entry{}, ErrNotFound
+	}
+
+	show, err := c.show(ctx, episode.ShowID)
+	if err != nil {
+		return Entry{}, err
+	}
+	e := show.entry()
+	e.Overview = episode.Overview
+	if episode.StillPath != "" {
+		e.PosterURL = ImageBaseURL + episode.StillPath
+	}
+	return e, nil
+}
+
+// show returns a show's details, fetching each show once per client.
+func (c *Client) show(ctx context.Context, id int) (*details, error) {
+	c.mu.Lock()
+	d, ok := c.shows[id]
+	c.mu.Unlock()
+	if ok {
+		return d, nil
+	}
+	d = &details{}
+	if err := c.get(ctx, "/tv/"+strconv.Itoa(id), url.Values{"append_to_response": {"recommendations"}}, d); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.shows[id] = d
+	c.mu.Unlock()
+	return d, nil
+}
+
+func (d *details) entry() Entry {
+	e := Entry{Tagline: d.Tagline, Overview: d.Overview}
+	if d.PosterPath != "" {
+		e.PosterURL = ImageBaseURL + d.PosterPath
+	}
+	for _, r := range d.Recommendations.Results {
+		if len(e.Similar) == maxSimilar {
+			break
+		}
+		e.Similar = append(e.Similar, r.title())
+	}
+	return e
+}
+
+func (c *Client) find(ctx context.Context, externalID, source string) (*findResponse, error) {
+	var found findResponse
+	if err := c.get(ctx, "/find/"+url.PathEscape(externalID), url.Values{"external_source": {source}}, &found); err != nil {
+		return nil, err
+	}
+	return &found, nil
+}
+
+// get fetches path from the API and decodes the JSON reply into v. A v4
+// token (a JWT) is sent as a bearer credential, a v3 key as a parameter.
+func (c *Client) get(ctx context.Context, path string, params url.Values, v any) error {
+	if params == nil {
+		params = url.Values{}
+	}
+	bearer := strings.HasPrefix(c.apiKey, "eyJ")
+	if !bearer {
+		params.Set("api_key", c.apiKey)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if bearer {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query TMDB: %w", err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("TMDB returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse TMDB response: %w", err)
+	}
+	return nil
+}
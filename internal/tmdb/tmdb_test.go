@@ -0,0 +1,166 @@
+package tmdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+// fakeTMDB serves a movie (Heat, TMDB 949, IMDb tt0113277) and an episode of
+// Breaking Bad (show 1396, episode IMDb tt0959621).
+func fakeTMDB(t *testing.T, showFetches *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("api_key") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/movie/949":
+			_, _ = w.Write([]byte(`{"title":"Heat","tagline":"A Los Angeles crime saga","overview":"Obsessive master thief...",
+				"poster_path":"/heat.jpg","recommendations":{"results":[{"id":1,"title":"Collateral","release_date":"2004-08-05"},{"id":2,"title":"Thief"}]}}`))
+		case "/find/tt0113277":
+			_, _ = w.Write([]byte(`{"movie_results":[{"id":949}],"tv_episode_results":[]}`))
+		case "/find/tt0959621":
+			_, _ = w.Write([]byte(`{"movie_results":[],"tv_episode_results":[{"id":62085,"overview":"Walter White...","still_path":"/pilot.jpg","show_id":1396}]}`))
+		case "/tv/1396":
+			showFetches.Add(1)
+			_, _ = w.Write([]byte(`{"name":"Breaking Bad","tagline":"Remember my name","poster_path":"/bb.jpg",
+				"recommendations":{"results":[{"id":60059,"name":"Better Call Saul","first_air_date":"2015-02-08"}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestClient(srv *httptest.Server, key string) *Client {
+	c := New(key)
+	c.baseURL = srv.URL
+	return c
+}
+
+func TestLookup(t *testing.T) {
+	var showFetches atomic.Int32
+	c := newTestClient(fakeTMDB(t, &showFetches), "key")
+	ctx := context.Background()
+
+	// By IMDb ID when Plex recorded no TMDB ID.
+	e, err := c.Lookup(ctx, &plex.MediaItem{Type: "movie", IMDbID: "tt0113277"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Tagline != "A Los Angeles crime saga" || e.PosterURL != ImageBaseURL+"/heat.jpg" {
+		t.Errorf("movie entry = %+v", e)
+	}
+	if len(e.Similar) != 2 || e.Similar[0].String() != "Collateral (2004)" || e.Similar[1].String() != "Thief" || e.Similar[0].ID != 1 {
+		t.Errorf("similar = %v", e.Similar)
+	}
+
+	// Episodes take their still and overview, and their show's tagline and
+	// recommendations; the show is fetched once.
+	for range 2 {
+		e, err = c.Lookup(ctx, &plex.MediaItem{Type: "episode", IMDbID: "tt0959621"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if e.Overview != "Walter White..." || e.PosterURL != ImageBaseURL+"/pilot.jpg" || e.Tagline != "Remember my name" {
+		t.Errorf("episode entry = %+v", e)
+	}
+	if len(e.Similar) != 1 || e.Similar[0].Name != "Better Call Saul" {
+		t.Errorf("episode similar = %v", e.Similar)
+	}
+	if n := showFetches.Load(); n != 1 {
+		t.Errorf("show fetched %d times, want 1", n)
+	}
+
+	if _, err := c.Lookup(ctx, &plex.MediaItem{Type: "movie", TMDBID: "5"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown movie: err = %v, want ErrNotFound", err)
+	}
+	if _, err := c.Lookup(ctx, &plex.MediaItem{Type: "movie"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("movie without IDs: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestEnrich(t *testing.T) {
+	var showFetches atomic.Int32
+	srv := fakeTMDB(t, &showFetches)
+	items := []plex.MediaItem{
+		{Type: "movie", Title: "Heat", TMDBID: "949", Summary: "Plex's summary", Thumb: "/library/metadata/1/thumb"},
+		{Type: "movie", Title: "Heat", TMDBID: "949", ServerURL: "http://other:32400"},
+		// Well-matched episodes aren't looked up.
+		{Type: "episode", IMDbID: "tt0959621", Summary: "Plex's summary", Thumb: "/thumb"},
+		{Type: "movie", Title: "Unknown", TMDBID: "5"},
+	}
+
+	store := &Store{}
+	fetched, failed, err := newTestClient(srv, "key").Enrich(context.Background(), store, items, nil)
+	if err != nil || failed != 0 {
+		t.Fatalf("Enrich: failed=%d err=%v", failed, err)
+	}
+	if len(fetched) != 2 {
+		t.Errorf("fetched %d entries, want Heat and the unknown movie", len(fetched))
+	}
+	if showFetches.Load() != 0 {
+		t.Error("a well-matched episode was looked up")
+	}
+
+	heat := items[0]
+	if heat.Tagline == "" || heat.Summary != "Plex's summary" || heat.PosterURL != "" {
+		t.Errorf("Plex's metadata should win: %+v", heat)
+	}
+	if len(heat.Similar) != 2 || heat.Similar[0] != "Collateral (2004)" {
+		t.Errorf("similar = %v", heat.Similar)
+	}
+	if other := items[1]; other.Summary == "" || other.PosterURL != ImageBaseURL+"/heat.jpg" {
+		t.Errorf("thin copy should take TMDB's summary and poster: %+v", other)
+	}
+
+	// Stored entries, including misses, aren't fetched again.
+	fetched, _, _ = newTestClient(srv, "key").Enrich(context.Background(), store, items, nil)
+	if len(fetched) != 0 {
+		t.Errorf("refetched %d stored entries", len(fetched))
+	}
+
+	// A rejected key stops enrichment.
+	_, _, err = newTestClient(srv, "wrong").Enrich(context.Background(), &Store{}, items, nil)
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("bad key: err = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tmdb.json")
+	s, err := LoadStoreFrom(path)
+	if err != nil || len(s.Entries) != 0 {
+		t.Fatalf("missing file: %+v, %v", s, err)
+	}
+
+	heat := &plex.MediaItem{Type: "movie", TMDBID: "949"}
+	s.Entries[Key(heat)] = Entry{Tagline: "A Los Angeles crime saga", FetchedAt: time.Now().Unix()}
+	s.Entries["movie:tmdb://1"] = Entry{FetchedAt: time.Now().Add(-RefreshAfter - time.Hour).Unix()}
+	if err := s.SaveTo(path); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadStoreFrom(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := got.Get(heat); !ok || e.Tagline != "A Los Angeles crime saga" {
+		t.Errorf("Get(heat) = %+v, %v", e, ok)
+	}
+	if _, ok := got.Get(&plex.MediaItem{Type: "movie", TMDBID: "1"}); ok {
+		t.Error("a stale entry should be refetched")
+	}
+}
@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"os/exec"
+	"sort"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -53,35 +54,130 @@ type keyMap struct {
 	ClearSearch  key.Binding
 }
 
-var keys = keyMap{
-	Up: key.NewBinding(
-		key.WithKeys("up", "k"),
-		key.WithHelp("↑/k", "up"),
-	),
-	Down: key.NewBinding(
-		key.WithKeys("down", "j"),
-		key.WithHelp("↓/j", "down"),
-	),
-	Search: key.NewBinding(
-		key.WithKeys("/"),
-		key.WithHelp("/", "search"),
-	),
-	Select: key.NewBinding(
-		key.WithKeys("enter"),
-		key.WithHelp("enter", "select"),
-	),
-	TogglePoster: key.NewBinding(
-		key.WithKeys("p"),
-		key.WithHelp("p", "toggle poster"),
-	),
-	Quit: key.NewBinding(
-		key.WithKeys("q", "ctrl+c", "esc"),
-		key.WithHelp("q", "quit"),
-	),
-	ClearSearch: key.NewBinding(
-		key.WithKeys("esc"),
-		key.WithHelp("esc", "clear search"),
-	),
+// keys is the browser's active key map: the defaults, with any overrides
+// from SetKeybindings.
+var keys = defaultKeys()
+
+func defaultKeys() keyMap {
+	return keyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		Search: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "search"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "select"),
+		),
+		TogglePoster: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "toggle poster"),
+		),
+		Quit: key.NewBinding(
+			key.WithKeys("q", "ctrl+c", "esc"),
+			key.WithHelp("q", "quit"),
+		),
+		ClearSearch: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "clear search"),
+		),
+	}
+}
+
+// keyActions are the browser actions SetKeybindings accepts, by config name.
+var keyActions = map[string]func(*keyMap) *key.Binding{
+	"up":            func(km *keyMap) *key.Binding { return &km.Up },
+	"down":          func(km *keyMap) *key.Binding { return &km.Down },
+	"search":        func(km *keyMap) *key.Binding { return &km.Search },
+	"select":        func(km *keyMap) *key.Binding { return &km.Select },
+	"toggle_poster": func(km *keyMap) *key.Binding { return &km.TogglePoster },
+	"quit":          func(km *keyMap) *key.Binding { return &km.Quit },
+	"clear_search":  func(km *keyMap) *key.Binding { return &km.ClearSearch },
+}
+
+// SetKeybindings replaces the browser's keys for the actions in bindings,
+// which maps an action name ("up", "down", "search", "select",
+// "toggle_poster", "quit" or "back", "clear_search") to a comma-separated
+// list of keys in Bubble Tea's notation, such as "l", "ctrl+n" or "space".
+// A key taken by an override is removed from the action that had it by
+// default, so {"select": "l"} needs no other changes. Actions not listed
+// keep their defaults; a nil map restores them all.
+func SetKeybindings(bindings map[string]string) error {
+	km := defaultKeys()
+	overridden := map[*key.Binding]bool{}
+	claimed := map[string]string{} // key -> action that claimed it
+
+	names := make([]string, 0, len(bindings))
+	for name := range bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		action := strings.ToLower(strings.TrimSpace(name))
+		if action == "back" {
+			// Quitting the browser returns to the previous menu.
+			action = "quit"
+		}
+		binding, ok := keyActions[action]
+		if !ok {
+			return fmt.Errorf("unknown action %q", name)
+		}
+		var ks, help []string
+		for _, k := range strings.Split(bindings[name], ",") {
+			k = strings.ToLower(strings.TrimSpace(k))
+			if k == "" {
+				continue
+			}
+			display := k
+			switch k {
+			case "space":
+				k = " "
+			case "up":
+				display = "↑"
+			case "down":
+				display = "↓"
+			}
+			if other, ok := claimed[k]; ok && other != action {
+				return fmt.Errorf("key %q is bound to both %q and %q", display, other, action)
+			}
+			claimed[k] = action
+			ks = append(ks, k)
+			help = append(help, display)
+		}
+		if len(ks) == 0 {
+			return fmt.Errorf("no keys given for %q", name)
+		}
+		b := binding(&km)
+		*b = key.NewBinding(key.WithKeys(ks...), key.WithHelp(strings.Join(help, "/"), b.Help().Desc))
+		overridden[b] = true
+	}
+
+	// Free claimed keys from the defaults of the other actions.
+	for _, binding := range keyActions {
+		b := binding(&km)
+		if overridden[b] {
+			continue
+		}
+		var kept []string
+		for _, k := range b.Keys() {
+			if _, ok := claimed[k]; !ok {
+				kept = append(kept, k)
+			}
+		}
+		if len(kept) < len(b.Keys()) {
+			b.SetKeys(kept...)
+		}
+	}
+
+	keys = km
+	return nil
 }
 
 // NewBrowser creates a new browser model
@@ -132,15 +228,15 @@ func (m *BrowserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		// If searching, handle search input
 		if m.searching {
-			switch msg.Type {
-			case tea.KeyEsc:
+			switch {
+			case key.Matches(msg, keys.ClearSearch):
 				m.searching = false
 				m.searchInput.Blur()
 				m.searchInput.SetValue("")
 				m.filteredMedia = m.media
 				m.cursor = 0
 				return m, nil
-			case tea.KeyEnter:
+			case msg.Type == tea.KeyEnter:
 				m.searching = false
 				m.searchInput.Blur()
 				return m, nil
@@ -201,16 +297,16 @@ func (m *BrowserModel) View() string {
 	// Header with enhanced styling
 	headerStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#C084FC")).
-		Background(lipgloss.Color("#1F1F23")).
+		Foreground(theme.Accent).
+		Background(theme.Header).
 		Padding(0, 1).
 		BorderStyle(lipgloss.ThickBorder()).
-		BorderForeground(lipgloss.Color("#C084FC")).
+		BorderForeground(theme.Accent).
 		BorderBottom(true).
 		Width(m.width - 2)
 
 	countStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#9CA3AF"))
+		Foreground(theme.Muted)
 
 	header := fmt.Sprintf("Media Browser %s", countStyle.Render(fmt.Sprintf("(%d items)", len(m.filteredMedia))))
 	b.WriteString(headerStyle.Render(header))
@@ -219,25 +315,25 @@ func (m *BrowserModel) View() string {
 	// Search bar with improved styling
 	if m.searching {
 		searchLabelStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#C084FC")).
+			Foreground(theme.Accent).
 			Bold(true)
 		b.WriteString(searchLabelStyle.Render("  Search: "))
 		b.WriteString(m.searchInput.View())
 		b.WriteString("\n")
 		// Divider line (guard against narrow terminals)
 		if m.width > 6 {
-			dividerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#374151"))
+			dividerStyle := lipgloss.NewStyle().Foreground(theme.Divider)
 			b.WriteString(dividerStyle.Render("  " + strings.Repeat("─", min(m.width-6, 60))))
 		}
 		b.WriteString("\n\n")
 	} else if m.searchInput.Value() != "" {
 		filterLabelStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#9CA3AF"))
+			Foreground(theme.Muted)
 		filterValueStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#C084FC")).
+			Foreground(theme.Accent).
 			Bold(true)
 		hintStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")).
+			Foreground(theme.Subtle).
 			Italic(true)
 		b.WriteString(fmt.Sprintf("  %s %s %s",
 			filterLabelStyle.Render("Filter:"),
@@ -246,7 +342,7 @@ func (m *BrowserModel) View() string {
 		b.WriteString("\n")
 		// Divider line (guard against narrow terminals)
 		if m.width > 6 {
-			dividerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#374151"))
+			dividerStyle := lipgloss.NewStyle().Foreground(theme.Divider)
 			b.WriteString(dividerStyle.Render("  " + strings.Repeat("─", min(m.width-6, 60))))
 		}
 		b.WriteString("\n")
@@ -267,7 +363,7 @@ func (m *BrowserModel) View() string {
 			Width(listWidth).
 			Height(listHeight).
 			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#4B5563"))
+			BorderForeground(theme.Border)
 
 		var listItems []string
 		for i := listStart; i < listEnd; i++ {
@@ -316,20 +412,20 @@ func (m *BrowserModel) View() string {
 	// Footer with styled help bar
 	b.WriteString("\n\n")
 	keyStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#C084FC")).
+		Foreground(theme.Accent).
 		Bold(true)
 	descStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6B7280"))
+		Foreground(theme.Subtle)
 	sepStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#374151"))
+		Foreground(theme.Divider)
 
 	sep := sepStyle.Render(" · ")
 	help := "  " +
-		keyStyle.Render("↑↓") + descStyle.Render(" navigate") + sep +
-		keyStyle.Render("/") + descStyle.Render(" search") + sep +
-		keyStyle.Render("p") + descStyle.Render(" poster") + sep +
-		keyStyle.Render("enter") + descStyle.Render(" select") + sep +
-		keyStyle.Render("q") + descStyle.Render(" quit")
+		keyStyle.Render(keys.Up.Help().Key+" "+keys.Down.Help().Key) + descStyle.Render(" navigate") + sep +
+		keyStyle.Render(keys.Search.Help().Key) + descStyle.Render(" search") + sep +
+		keyStyle.Render(keys.TogglePoster.Help().Key) + descStyle.Render(" poster") + sep +
+		keyStyle.Render(keys.Select.Help().Key) + descStyle.Render(" select") + sep +
+		keyStyle.Render(keys.Quit.Help().Key) + descStyle.Render(" quit")
 	b.WriteString(help)
 
 	return b.String()
@@ -343,17 +439,17 @@ func (m *BrowserModel) formatListItem(item plex.MediaItem, cursor string, select
 
 	if selected {
 		// Selected item: accent color with subtle background highlight
-		mainFg = lipgloss.Color("#C084FC")
-		dimFg = lipgloss.Color("#9CA3AF")
-		bg = lipgloss.Color("#2D2D35")
+		mainFg = theme.Accent
+		dimFg = theme.Muted
+		bg = theme.Highlight
 		bold = true
 	} else if alternate {
 		// Alternating rows: slightly dimmer for visual rhythm
-		mainFg = lipgloss.Color("#9CA3AF")
-		dimFg = lipgloss.Color("#6B7280")
+		mainFg = theme.Muted
+		dimFg = theme.Subtle
 	} else {
-		mainFg = lipgloss.Color("#D1D5DB")
-		dimFg = lipgloss.Color("#6B7280")
+		mainFg = theme.Text
+		dimFg = theme.Subtle
 	}
 
 	mainStyle := lipgloss.NewStyle().Foreground(mainFg).Bold(bold)
@@ -378,6 +474,9 @@ func (m *BrowserModel) formatListItem(item plex.MediaItem, cursor string, select
 	default:
 		parts = append(parts, mainStyle.Render(item.Title))
 	}
+	if size := plex.FormatSize(item.Size); size != "" {
+		parts = append(parts, dimStyle.Render("  "+size))
+	}
 
 	return strings.Join(parts, "")
 }
@@ -387,22 +486,22 @@ func (m *BrowserModel) renderDetails(item plex.MediaItem, width, height int) str
 		Width(width).
 		Height(height).
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#4B5563")).
+		BorderForeground(theme.Border).
 		Padding(1)
 
 	var details strings.Builder
 
 	// Title with accent color
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#C084FC"))
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Accent)
 	details.WriteString(titleStyle.Render(item.Title))
 	details.WriteString("\n\n")
 
 	// Styled labels and values
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6B7280")).
+		Foreground(theme.Subtle).
 		Width(10)
 	valueStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#E5E7EB"))
+		Foreground(theme.Text)
 
 	if item.Type == "movie" && item.Year > 0 {
 		details.WriteString(labelStyle.Render("Year"))
@@ -419,7 +518,7 @@ func (m *BrowserModel) renderDetails(item plex.MediaItem, width, height int) str
 
 	if item.Rating > 0 {
 		details.WriteString(labelStyle.Render("Rating"))
-		ratingStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FBBF24"))
+		ratingStyle := lipgloss.NewStyle().Foreground(theme.Warning)
 		details.WriteString(ratingStyle.Render(fmt.Sprintf("%.1f", item.Rating)))
 		details.WriteString(valueStyle.Render("/10"))
 		details.WriteString("\n")
@@ -432,9 +531,27 @@ func (m *BrowserModel) renderDetails(item plex.MediaItem, width, height int) str
 		details.WriteString("\n")
 	}
 
+	if tech := item.FormatTech(); tech != "" {
+		details.WriteString(labelStyle.Render("Format"))
+		details.WriteString(valueStyle.Render(tech))
+		details.WriteString("\n")
+	}
+
+	if item.Director != "" {
+		details.WriteString(labelStyle.Render("Director"))
+		details.WriteString(valueStyle.Render(item.Director))
+		details.WriteString("\n")
+	}
+
+	if item.Cast != "" {
+		details.WriteString(labelStyle.Render("Cast"))
+		details.WriteString(valueStyle.Render(item.TopCast(3)))
+		details.WriteString("\n")
+	}
+
 	if item.Summary != "" {
 		details.WriteString("\n")
-		summaryStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#9CA3AF"))
+		summaryStyle := lipgloss.NewStyle().Foreground(theme.Muted)
 		wrapped := wrapText(item.Summary, width-4)
 		details.WriteString(summaryStyle.Render(wrapped))
 	}
@@ -450,7 +567,7 @@ func (m *BrowserModel) renderDetails(item plex.MediaItem, width, height int) str
 		} else if !m.posterLoading[item.Thumb] {
 			// Show styled loading indicator
 			loadingStyle := lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#6B7280")).
+				Foreground(theme.Subtle).
 				Italic(true)
 			details.WriteString("\n\n")
 			details.WriteString(loadingStyle.Render("Loading poster..."))
@@ -464,14 +581,14 @@ func (m *BrowserModel) renderDetailsCompact(item plex.MediaItem) string {
 	// Box container for compact details
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#4B5563")).
+		BorderForeground(theme.Border).
 		Padding(0, 1)
 
 	var content strings.Builder
 
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#C084FC"))
-	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280"))
-	sepStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#374151"))
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Accent)
+	dimStyle := lipgloss.NewStyle().Foreground(theme.Subtle)
+	sepStyle := lipgloss.NewStyle().Foreground(theme.Divider)
 
 	content.WriteString(titleStyle.Render(item.Title))
 
@@ -483,7 +600,7 @@ func (m *BrowserModel) renderDetailsCompact(item plex.MediaItem) string {
 
 	if item.Rating > 0 {
 		content.WriteString(sepStyle.Render(" · "))
-		ratingStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FBBF24"))
+		ratingStyle := lipgloss.NewStyle().Foreground(theme.Warning)
 		content.WriteString(ratingStyle.Render(fmt.Sprintf("%.1f", item.Rating)))
 		content.WriteString(dimStyle.Render("/10"))
 	}
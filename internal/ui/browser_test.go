@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSetKeybindings(t *testing.T) {
+	t.Cleanup(func() { _ = SetKeybindings(nil) })
+	press := func(r rune) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}} }
+
+	if err := SetKeybindings(map[string]string{"select": "l, enter", "back": "h", "up": "k"}); err != nil {
+		t.Fatal(err)
+	}
+	if !key.Matches(press('l'), keys.Select) || !key.Matches(tea.KeyMsg{Type: tea.KeyEnter}, keys.Select) {
+		t.Error("select should match l and enter")
+	}
+	if !key.Matches(press('h'), keys.Quit) || key.Matches(press('q'), keys.Quit) {
+		t.Error("back should replace quit's keys")
+	}
+	if keys.Select.Help().Key != "l/enter" || keys.Select.Help().Desc != "select" {
+		t.Errorf("select help = %+v", keys.Select.Help())
+	}
+	// Defaults for actions not listed stay.
+	if !key.Matches(press('j'), keys.Down) || !key.Matches(press('/'), keys.Search) {
+		t.Error("unlisted actions lost their default keys")
+	}
+
+	// A key moved to another action leaves its default one.
+	if err := SetKeybindings(map[string]string{"toggle_poster": "j"}); err != nil {
+		t.Fatal(err)
+	}
+	if key.Matches(press('j'), keys.Down) || !key.Matches(tea.KeyMsg{Type: tea.KeyDown}, keys.Down) {
+		t.Error("j should move from down to toggle_poster")
+	}
+
+	for _, bad := range []map[string]string{
+		{"jump": "g"},
+		{"select": " , "},
+		{"up": "x", "down": "x"},
+	} {
+		if err := SetKeybindings(bad); err == nil {
+			t.Errorf("SetKeybindings(%v) should fail", bad)
+		}
+	}
+
+	if err := SetKeybindings(nil); err != nil || !key.Matches(press('q'), keys.Quit) {
+		t.Errorf("nil should restore the defaults (err %v)", err)
+	}
+}
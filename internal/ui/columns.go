@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+// maxTitleWidth caps the title column so long episode titles don't push
+// the other columns off screen.
+const maxTitleWidth = 60
+
+// mediaColumns returns the columns a picker shows for item: a type icon,
+// the title, year, rating, runtime, a watched marker (✓ watched, ▶ and
+// the percentage in progress), resolution and file size.
+func mediaColumns(item *plex.MediaItem) []string {
+	icon := "📼"
+	switch item.Type {
+	case "movie":
+		icon = "🎬"
+	case "episode":
+		icon = "📺"
+	}
+
+	title := item.Title
+	if item.Type == "episode" {
+		title = fmt.Sprintf("%s - S%02dE%02d - %s", item.ParentTitle, item.ParentIndex, item.Index, item.Title)
+	}
+
+	var year, rating, runtime, watched string
+	if item.Year > 0 {
+		year = fmt.Sprint(item.Year)
+	}
+	if item.Rating > 0 {
+		rating = fmt.Sprintf("★ %.1f", item.Rating)
+	}
+	if item.Duration > 0 {
+		runtime = formatRuntime(item.Duration)
+		switch {
+		case item.ViewCount > 0:
+			watched = "✓"
+		case item.ViewOffset > 0:
+			// As FormatMediaTitle and HasResumableProgress count it
+			if pct := int(float64(item.ViewOffset) * 100 / float64(item.Duration)); pct >= 95 {
+				watched = "✓"
+			} else {
+				watched = fmt.Sprintf("▶ %d%%", pct)
+			}
+		}
+	}
+
+	res := plex.FormatResolution(item.VideoResolution)
+	if item.HDR != "" {
+		res = strings.TrimSpace(res + " HDR")
+	}
+
+	return []string{icon, truncate(title, maxTitleWidth), year, rating, runtime, watched, res, plex.FormatSize(item.Size)}
+}
+
+// formatRuntime renders a millisecond duration as "1h 05m" or "45m".
+func formatRuntime(ms int) string {
+	minutes := ms / 60000
+	if minutes >= 60 {
+		return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
+
+// columnRows lays out rows of columns for fzf: each column is padded one
+// space past its widest entry, columns empty in every row are dropped, and
+// the rest are joined with tabs behind the row's index. fzf runs with
+// --delimiter=\t and --with-nth=2.. to hide the index, and --tabstop=1 so
+// each tab takes a single cell, leaving the columns aligned.
+func columnRows(rows [][]string) []string {
+	var widths []int
+	for _, row := range rows {
+		for c, col := range row {
+			if c == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[c] = max(widths[c], lipgloss.Width(col))
+		}
+	}
+
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		var padded []string
+		for c, col := range row {
+			if widths[c] > 0 {
+				padded = append(padded, col+strings.Repeat(" ", widths[c]-lipgloss.Width(col)+1))
+			}
+		}
+		lines[i] = fmt.Sprintf("%d\t%s", i, strings.TrimRight(strings.Join(padded, "\t"), " \t"))
+	}
+	return lines
+}
+
+// mediaRows is columnRows of mediaColumns for each item.
+func mediaRows(media []*plex.MediaItem) []string {
+	rows := make([][]string, len(media))
+	for i, item := range media {
+		rows[i] = mediaColumns(item)
+	}
+	return columnRows(rows)
+}
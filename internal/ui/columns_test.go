@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+func TestMediaColumns(t *testing.T) {
+	movie := &plex.MediaItem{
+		Title: "Heat", Type: "movie", Year: 1995, Rating: 8.3, Duration: 170 * 60000,
+		ViewOffset: 60 * 60000, VideoResolution: "4k", HDR: "HDR10", Size: 3 << 30,
+	}
+	want := []string{"🎬", "Heat", "1995", "★ 8.3", "2h 50m", "▶ 35%", "4K HDR", "3.0 GB"}
+	if got := mediaColumns(movie); !slices.Equal(got, want) {
+		t.Errorf("movie columns = %q, want %q", got, want)
+	}
+
+	episode := &plex.MediaItem{
+		Title: "Half Loop", Type: "episode", ParentTitle: "Severance", ParentIndex: 1, Index: 2,
+		Duration: 55 * 60000, ViewCount: 1, VideoResolution: "1080",
+	}
+	want = []string{"📺", "Severance - S01E02 - Half Loop", "", "", "55m", "✓", "1080p", ""}
+	if got := mediaColumns(episode); !slices.Equal(got, want) {
+		t.Errorf("episode columns = %q, want %q", got, want)
+	}
+}
+
+func TestColumnRows(t *testing.T) {
+	got := columnRows([][]string{
+		{"🎬", "Heat", "", "1995"},
+		{"📺", "Severance - S01E02", "", ""},
+	})
+	want := []string{
+		"0\t🎬 \tHeat               \t1995",
+		"1\t📺 \tSeverance - S01E02",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("columnRows = %q, want %q", got, want)
+	}
+}
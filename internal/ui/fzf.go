@@ -2,20 +2,25 @@ package ui
 
 import (
 	"bytes"
-	"crypto/md5"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
+	"maps"
 	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/joshkerr/goplexcli/internal/config"
 	"github.com/joshkerr/goplexcli/internal/errors"
 	"github.com/joshkerr/goplexcli/internal/plex"
+	"github.com/joshkerr/goplexcli/internal/posters"
+	"github.com/joshkerr/goplexcli/internal/preview"
 )
 
 // SelectWithFzf presents items in fzf and returns the selected item
@@ -84,8 +89,41 @@ func SelectWithFzf(items []string, prompt string, fzfPath string) (string, int,
 
 // SelectMediaWithPreview presents media in fzf with preview window showing metadata and poster
 func SelectMediaWithPreview(media []plex.MediaItem, prompt string, fzfPath string, plexURL string, plexToken string) ([]int, error) {
+	indices, _, err := selectMediaWithPreview(media, prompt, fzfPath, plexURL, plexToken, false)
+	return indices, err
+}
+
+// inlineActions maps the keys SelectMediaWithActions accepts a selection
+// with to the action each picks, as PromptActionWithQueue names them.
+var inlineActions = map[string]string{
+	"ctrl-w": "watch",
+	"ctrl-d": "download",
+	"ctrl-q": "queue",
+}
+
+// InlineActionHelp describes the keys SelectMediaWithActions handles.
+const InlineActionHelp = "enter: choose action · ctrl-w watch · ctrl-d download · ctrl-q queue"
+
+// SelectMediaWithActions is SelectMediaWithPreview that also accepts the
+// selection with one of the inline action keys (ctrl-w watch, ctrl-d
+// download, ctrl-q queue), returning the action picked, or "" for Enter,
+// so the caller can skip asking what to do.
+func SelectMediaWithActions(media []plex.MediaItem, prompt string, fzfPath string, plexURL string, plexToken string) ([]int, string, error) {
+	return selectMediaWithPreview(media, prompt, fzfPath, plexURL, plexToken, true)
+}
+
+// splitExpect splits the output of fzf run with --expect into the inline
+// action its first line names ("" for Enter) and the selected lines.
+func splitExpect(output string) (action, selected string) {
+	key, selected, _ := strings.Cut(output, "\n")
+	return inlineActions[strings.TrimSpace(key)], selected
+}
+
+// selectMediaWithPreview runs the media picker; with actions it accepts
+// the inline action keys as well as Enter and returns the action.
+func selectMediaWithPreview(media []plex.MediaItem, prompt, fzfPath, plexURL, plexToken string, actions bool) ([]int, string, error) {
 	if len(media) == 0 {
-		return nil, fmt.Errorf("no items to select from")
+		return nil, "", fmt.Errorf("no items to select from")
 	}
 
 	if fzfPath == "" {
@@ -94,26 +132,24 @@ func SelectMediaWithPreview(media []plex.MediaItem, prompt string, fzfPath strin
 
 	// Check if fzf is available
 	if _, err := exec.LookPath(fzfPath); err != nil {
-		return nil, fmt.Errorf("fzf not found in PATH. Please install fzf or specify the path in config")
+		return nil, "", fmt.Errorf("fzf not found in PATH. Please install fzf or specify the path in config")
 	}
 
-	// Create formatted items with index prefix for preview script
-	var items []string
-	for i, item := range media {
-		items = append(items, fmt.Sprintf("%d\t%s", i, item.FormatMediaTitle()))
+	// Aligned columns behind each row's index, which the preview script gets
+	items := make([]*plex.MediaItem, len(media))
+	for i := range media {
+		items[i] = &media[i]
 	}
-	input := strings.Join(items, "\n")
+	input := strings.Join(mediaRows(items), "\n")
 
 	// Create a temporary preview script and data file
-	previewScript, err := createPreviewScript(media, plexURL, plexToken)
+	previewScript, err := createPreviewScript(media, plexURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create preview script: %w", err)
+		return nil, "", fmt.Errorf("failed to create preview script: %w", err)
 	}
 	defer os.Remove(previewScript)
-
-	// Also clean up the data file containing the token
-	dataPath := filepath.Join(os.TempDir(), "goplexcli-preview-data.json")
-	defer os.Remove(dataPath)
+	defer removePreviewData()
+	defer prefetchPosters(media, plexURL, plexToken)()
 
 	// Build fzf command with preview and multi-select support
 	args := []string{
@@ -123,6 +159,7 @@ func SelectMediaWithPreview(media []plex.MediaItem, prompt string, fzfPath strin
 		"--border",
 		"--delimiter=\t",
 		"--with-nth=2..",
+		"--tabstop=1",
 		"--prompt=" + prompt + " ",
 		"--preview=" + previewScript + " {1}",
 		"--preview-window=right:50%:wrap",
@@ -130,8 +167,15 @@ func SelectMediaWithPreview(media []plex.MediaItem, prompt string, fzfPath strin
 		"--no-mouse",
 		"--bind=ctrl-/:toggle-preview",
 	}
+	if actions {
+		// fzf prints the key that accepted the selection on a line of its
+		// own before it: empty for Enter.
+		keys := slices.Sorted(maps.Keys(inlineActions))
+		args = append(args, "--expect="+strings.Join(keys, ","), "--header="+InlineActionHelp)
+	}
 
 	cmd := exec.Command(fzfPath, args...)
+	cmd.Env = previewEnv(plexToken)
 
 	// Set up pipes
 	cmd.Stdin = strings.NewReader(input)
@@ -145,16 +189,21 @@ func SelectMediaWithPreview(media []plex.MediaItem, prompt string, fzfPath strin
 		if exitErr, ok := err.(*exec.ExitError); ok {
 			// Exit code 130 means user cancelled with Ctrl-C
 			if exitErr.ExitCode() == 130 {
-				return nil, errors.ErrCancelled
+				return nil, "", errors.ErrCancelled
 			}
 		}
-		return nil, fmt.Errorf("fzf failed: %w", err)
+		return nil, "", fmt.Errorf("fzf failed: %w", err)
 	}
 
 	// Get selected items and extract indices
-	output := strings.TrimSpace(outBuf.String())
+	output := outBuf.String()
+	var action string
+	if actions {
+		action, output = splitExpect(output)
+	}
+	output = strings.TrimSpace(output)
 	if output == "" {
-		return nil, fmt.Errorf("no selection made")
+		return nil, "", fmt.Errorf("no selection made")
 	}
 
 	// Parse multiple selections (one per line)
@@ -190,9 +239,9 @@ func SelectMediaWithPreview(media []plex.MediaItem, prompt string, fzfPath strin
 
 	if len(indices) == 0 {
 		if invalidCount > 0 {
-			return nil, fmt.Errorf("no valid selection made (%d invalid selections ignored)", invalidCount)
+			return nil, "", fmt.Errorf("no valid selection made (%d invalid selections ignored)", invalidCount)
 		}
-		return nil, fmt.Errorf("no valid selection made")
+		return nil, "", fmt.Errorf("no valid selection made")
 	}
 
 	// Warn if some selections were invalid
@@ -200,7 +249,7 @@ func SelectMediaWithPreview(media []plex.MediaItem, prompt string, fzfPath strin
 		fmt.Fprintf(os.Stderr, "Warning: %d invalid selection(s) were ignored\n", invalidCount)
 	}
 
-	return indices, nil
+	return indices, action, nil
 }
 
 // SelectMediaWithCustomLabels is like SelectMediaWithPreview but uses caller-supplied
@@ -222,18 +271,26 @@ func SelectMediaWithCustomLabels(media []plex.MediaItem, labels []string, prompt
 		return -1, fmt.Errorf("fzf not found in PATH. Please install fzf or specify the path in config")
 	}
 
-	items := make([]string, len(media))
-	for i, label := range labels {
-		items[i] = fmt.Sprintf("%d\t%s", i, label)
-	}
-	input := strings.Join(items, "\n")
-
-	previewScript, err := createPreviewScript(media, plexURL, plexToken)
+	previewScript, err := createPreviewScript(media, plexURL)
 	if err != nil {
 		return -1, fmt.Errorf("failed to create preview script: %w", err)
 	}
 	defer os.Remove(previewScript)
-	defer os.Remove(filepath.Join(os.TempDir(), "goplexcli-preview-data.json"))
+	defer removePreviewData()
+	defer prefetchPosters(media, plexURL, plexToken)()
+
+	return selectIndexedWithPreview(labels, prompt, fzfPath, previewScript, plexToken)
+}
+
+// selectIndexedWithPreview runs a single-select fzf over labels, with
+// previewScript called on the highlighted row's index, and returns that index.
+// plexToken, if set, is handed to the preview via previewEnv.
+func selectIndexedWithPreview(labels []string, prompt, fzfPath, previewScript, plexToken string) (int, error) {
+	items := make([]string, len(labels))
+	for i, label := range labels {
+		items[i] = fmt.Sprintf("%d\t%s", i, label)
+	}
+	input := strings.Join(items, "\n")
 
 	args := []string{
 		"--height=50%",
@@ -250,6 +307,7 @@ func SelectMediaWithCustomLabels(media []plex.MediaItem, labels []string, prompt
 	}
 
 	cmd := exec.Command(fzfPath, args...)
+	cmd.Env = previewEnv(plexToken)
 	cmd.Stdin = strings.NewReader(input)
 	cmd.Stderr = os.Stderr
 
@@ -273,41 +331,115 @@ func SelectMediaWithCustomLabels(media []plex.MediaItem, labels []string, prompt
 	if _, err := fmt.Sscanf(parts[0], "%d", &index); err != nil {
 		return -1, fmt.Errorf("failed to parse selection: %w", err)
 	}
-	if index < 0 || index >= len(media) {
+	if index < 0 || index >= len(labels) {
 		return -1, fmt.Errorf("selection index %d out of range", index)
 	}
 	return index, nil
 }
 
-// createPreviewScript writes the JSON data file consumed by the preview
+// createPreviewScript writes the data file consumed by the preview
 // subcommand and emits a wrapper script that fzf invokes for each row.
 // The wrapper just calls back into the running goplexcli binary's hidden
 // `__preview` subcommand, so there is no separate helper executable to
-// install or discover.
-func createPreviewScript(media []plex.MediaItem, plexURL string, plexToken string) (string, error) {
-	tmpDir := os.TempDir()
+// install or discover. The file holds no token; see previewEnv.
+func createPreviewScript(media []plex.MediaItem, plexURL string) (string, error) {
+	return writePreviewScript(func(dataPath string) error {
+		return preview.WriteMedia(dataPath, plexURL, media)
+	})
+}
+
+var (
+	previewPostersMu sync.RWMutex
+	previewPosters   bool
+)
 
-	dataPath := filepath.Join(tmpDir, "goplexcli-preview-data.json")
+// SetPreviewPosters sets whether the preview draws posters, so pickers
+// prefetch them while open. Like SetTheme, call it once at startup.
+func SetPreviewPosters(on bool) {
+	previewPostersMu.Lock()
+	defer previewPostersMu.Unlock()
+	previewPosters = on
+}
 
-	type PreviewData struct {
-		Media     []plex.MediaItem `json:"media"`
-		PlexURL   string           `json:"plex_url"`
-		PlexToken string           `json:"plex_token"`
+// prefetchPosters starts fetching the posters of media into the poster
+// cache in the background, if the preview draws them, and returns a
+// function that stops it.
+func prefetchPosters(media []plex.MediaItem, plexURL, plexToken string) (stop func()) {
+	previewPostersMu.RLock()
+	on := previewPosters
+	previewPostersMu.RUnlock()
+	if !on {
+		return func() {}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		preview.Prefetch(ctx, media, plexURL, plexToken)
+	}()
+	return func() {
+		cancel()
+		<-done
 	}
+}
+
+// PreviewTokenEnv is the environment variable that carries the Plex token
+// from a picker to its preview subcommand, which needs it to fetch posters.
+const PreviewTokenEnv = "GOPLEXCLI_PREVIEW_TOKEN"
 
-	data := PreviewData{
-		Media:     media,
-		PlexURL:   plexURL,
-		PlexToken: plexToken,
+// previewEnv is the environment fzf runs with: ours plus the token in
+// PreviewTokenEnv. fzf passes it on to each preview command, so the token
+// reaches the preview without being written to disk. The active profile is
+// passed in config.ProfileEnv too, so a picker opened with --profile
+// previews with that profile's config and caches.
+func previewEnv(plexToken string) []string {
+	env := os.Environ()
+	if plexToken != "" {
+		env = append(env, PreviewTokenEnv+"="+plexToken)
 	}
+	if profile := config.Profile(); profile != "" {
+		env = append(env, config.ProfileEnv+"="+profile)
+	}
+	return env
+}
 
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return "", err
+// createSeasonPreviewScript is createPreviewScript for a season picker: row i
+// previews the summary of seasons[i], a season's episodes.
+func createSeasonPreviewScript(seasons [][]plex.MediaItem) (string, error) {
+	return writePreviewScript(func(dataPath string) error {
+		return preview.WriteSeasons(dataPath, seasons)
+	})
+}
+
+// previewDataPath is where pickers write the preview data file.
+func previewDataPath() string {
+	return filepath.Join(os.TempDir(), "goplexcli-preview-data.jsonl")
+}
+
+// removePreviewData deletes the preview data file and its index.
+func removePreviewData() {
+	_ = os.Remove(previewDataPath())
+	_ = os.Remove(preview.IndexPath(previewDataPath()))
+}
+
+// RemovePreviewFiles deletes the preview data file and the wrapper scripts,
+// for callers exiting without the pickers' own clean-up running, e.g. on
+// Ctrl-C.
+func RemovePreviewFiles() {
+	removePreviewData()
+	tmpDir := os.TempDir()
+	for _, name := range []string{"goplexcli-preview.sh", "goplexcli-preview.bat"} {
+		_ = os.Remove(filepath.Join(tmpDir, name))
 	}
+}
 
-	// Restrictive permissions protect the embedded Plex token.
-	if err := os.WriteFile(dataPath, jsonData, 0600); err != nil {
+// writePreviewScript writes the preview data file with write and the
+// wrapper script around it, returning the script's path.
+func writePreviewScript(write func(dataPath string) error) (string, error) {
+	tmpDir := os.TempDir()
+
+	dataPath := previewDataPath()
+	if err := write(dataPath); err != nil {
 		return "", err
 	}
 
@@ -347,6 +479,9 @@ func createPreviewScript(media []plex.MediaItem, plexURL string, plexToken strin
 
 // IsAvailable checks if fzf is available on the system
 func IsAvailable(fzfPath string) bool {
+	if !interactive {
+		return false
+	}
 	if fzfPath == "" {
 		fzfPath = "fzf"
 	}
@@ -388,6 +523,15 @@ func SelectMediaType(fzfPath string) (string, error) {
 	return strings.ToLower(selected), nil
 }
 
+// ListLabel is an item's title for pick lists, followed by its file size
+// when known, e.g. "Heat (1995)  4.2 GB".
+func ListLabel(item *plex.MediaItem) string {
+	if size := plex.FormatSize(item.Size); size != "" {
+		return item.FormatMediaTitle() + "  " + size
+	}
+	return item.FormatMediaTitle()
+}
+
 // PluralizeItems returns "1 item" or "N items" based on count
 func PluralizeItems(count int) string {
 	if count == 1 {
@@ -396,20 +540,49 @@ func PluralizeItems(count int) string {
 	return fmt.Sprintf("%d items", count)
 }
 
+// LocalPlayLabel is the action offered when localCount of the selectionCount
+// selected items have been downloaded.
+func LocalPlayLabel(selectionCount, localCount int) string {
+	if selectionCount == 1 {
+		return "Play Local File"
+	}
+	return fmt.Sprintf("Play Local Files (%d of %d)", localCount, selectionCount)
+}
+
+// WatchSeasonLabel is the action that plays every episode of the
+// seasonCount seasons the selection touches.
+func WatchSeasonLabel(seasonCount int) string {
+	if seasonCount == 1 {
+		return "Watch Season"
+	}
+	return fmt.Sprintf("Watch Seasons (%d)", seasonCount)
+}
+
 // PromptActionWithQueue asks the user what action to take, showing queue count.
-// "Transfer to Outplayer" is only offered when outplayerCount > 0.
-func PromptActionWithQueue(fzfPath string, selectionCount, queueCount, outplayerCount int) (string, error) {
+// "Transfer to Outplayer" is only offered when outplayerCount > 0, "Play
+// Local File" only when localCount > 0, and "Watch Season" only when the
+// selection's episodes span seasonCount > 0 seasons.
+func PromptActionWithQueue(fzfPath string, selectionCount, queueCount, outplayerCount, localCount, seasonCount int) (string, error) {
 	queueLabel := fmt.Sprintf("Add (%d) to Queue", selectionCount)
 	if queueCount > 0 {
 		queueLabel = fmt.Sprintf("Add (%d) to Queue (%d)", selectionCount, queueCount)
 	}
 
-	actions := []string{
-		"Watch",
+	localLabel := LocalPlayLabel(selectionCount, localCount)
+	var actions []string
+	if localCount > 0 {
+		actions = append(actions, localLabel)
+	}
+	actions = append(actions, "Watch")
+	seasonLabel := WatchSeasonLabel(seasonCount)
+	if seasonCount > 0 {
+		actions = append(actions, seasonLabel)
+	}
+	actions = append(actions,
 		"Download",
 		queueLabel,
 		"Transfer to WebDAV",
-	}
+	)
 	if outplayerCount > 0 {
 		actions = append(actions, "Transfer to Outplayer")
 	}
@@ -433,18 +606,28 @@ func PromptActionWithQueue(fzfPath string, selectionCount, queueCount, outplayer
 	if selected == "More..." {
 		return "more", nil
 	}
+	if localCount > 0 && selected == localLabel {
+		return "watch local", nil
+	}
+	if seasonCount > 0 && selected == seasonLabel {
+		return "watch season", nil
+	}
 
 	return strings.ToLower(selected), nil
 }
 
 // PromptMoreAction shows the secondary action menu containing the less-common
-// playback/streaming options (SenPlayer, Stream) that would otherwise clutter
-// the main action menu. Returns "cancel" when the user backs out.
+// options (playback presets, SenPlayer, Stream, IMDb, server-side deletion)
+// that would otherwise clutter the main action menu. Returns "cancel" when the user backs
+// out.
 func PromptMoreAction(fzfPath string) (string, error) {
 	actions := []string{
+		"Watch with Preset...",
 		"SenPlayer Play",
 		"SenPlayer Download",
 		"Stream",
+		"Open on IMDb",
+		"Delete from Server...",
 		"Back",
 	}
 
@@ -453,19 +636,27 @@ func PromptMoreAction(fzfPath string) (string, error) {
 		return "", err
 	}
 
-	if selected == "Back" {
+	switch selected {
+	case "Back":
 		return "cancel", nil
+	case "Watch with Preset...":
+		return "watch preset", nil
+	case "Open on IMDb":
+		return "open imdb", nil
+	case "Delete from Server...":
+		return "delete", nil
 	}
 
 	return strings.ToLower(selected), nil
 }
 
 // SelectMediaTypeWithQueue presents the top-level browse menu. It adds a
-// "View Queue" option when the queue has items and a "Continue Watching" hub
-// when continueCount items have resumable progress. Returns a normalized
+// "View Queue" option when the queue has items, a "Continue Watching" hub
+// when continueCount items have resumable progress, and a "Libraries" picker
+// when the cache spans more than one library section. Returns a normalized
 // selection token: "queue", "continue watching", "recently added movies",
-// "recently added tv shows", "movies", "tv shows", or "all".
-func SelectMediaTypeWithQueue(fzfPath string, queueCount, continueCount int) (string, error) {
+// "recently added tv shows", "movies", "tv shows", "all", or "libraries".
+func SelectMediaTypeWithQueue(fzfPath string, queueCount, continueCount, libraryCount int) (string, error) {
 	var types []string
 
 	if queueCount > 0 {
@@ -475,6 +666,9 @@ func SelectMediaTypeWithQueue(fzfPath string, queueCount, continueCount int) (st
 		types = append(types, fmt.Sprintf("Continue Watching (%s)", PluralizeItems(continueCount)))
 	}
 	types = append(types, "Recently Added Movies", "Recently Added TV Shows", "Movies", "TV Shows", "All")
+	if libraryCount > 1 {
+		types = append(types, fmt.Sprintf("Libraries (%d)", libraryCount))
+	}
 
 	selected, _, err := SelectWithFzf(types, "Select media type:", fzfPath)
 	if err != nil {
@@ -486,6 +680,8 @@ func SelectMediaTypeWithQueue(fzfPath string, queueCount, continueCount int) (st
 		return "queue", nil
 	case strings.HasPrefix(selected, "Continue Watching"):
 		return "continue watching", nil
+	case strings.HasPrefix(selected, "Libraries"):
+		return "libraries", nil
 	}
 
 	return strings.ToLower(selected), nil
@@ -543,12 +739,8 @@ func SelectQueueItemsForRemoval(queue []*plex.MediaItem, fzfPath string) ([]int,
 		return nil, fmt.Errorf("fzf not found in PATH. Please install fzf or specify the path in config")
 	}
 
-	// Create formatted items with index prefix
-	var items []string
-	for i, item := range queue {
-		items = append(items, fmt.Sprintf("%d\t%s", i, item.FormatMediaTitle()))
-	}
-	input := strings.Join(items, "\n")
+	// Aligned columns behind each row's index
+	input := strings.Join(mediaRows(queue), "\n")
 
 	// Build fzf command with multi-select
 	args := []string{
@@ -558,6 +750,7 @@ func SelectQueueItemsForRemoval(queue []*plex.MediaItem, fzfPath string) ([]int,
 		"--border",
 		"--delimiter=\t",
 		"--with-nth=2..",
+		"--tabstop=1",
 		"--prompt=Select items to remove (TAB for multi-select): ",
 	}
 
@@ -635,54 +828,27 @@ func SelectMedia(media []plex.MediaItem, prompt string, fzfPath string) (*plex.M
 	return &media[index], nil
 }
 
-// DownloadPoster downloads the poster image and returns the local path
+// DownloadPoster returns the local path of an item's poster, downloading it
+// into the persistent poster cache if needed. It returns "" when the poster
+// can't be fetched.
 func DownloadPoster(plexURL, thumbPath, token string) string {
 	if thumbPath == "" {
 		return ""
 	}
-
-	// Create cache directory
-	cacheDir := filepath.Join(os.TempDir(), "goplexcli-posters")
-	if err := os.MkdirAll(cacheDir, 0755); err != nil {
-		return ""
-	}
-
-	// Create filename from hash of thumb path
-	hash := md5.Sum([]byte(thumbPath))
-	posterFile := filepath.Join(cacheDir, fmt.Sprintf("%x.jpg", hash))
-
-	// Check if already downloaded
-	if _, err := os.Stat(posterFile); err == nil {
-		return posterFile
-	}
-
-	// Download poster
-	url := plexURL + thumbPath + "?X-Plex-Token=" + token
-	resp, err := http.Get(url)
+	path, fetched, err := posters.Fetch(context.Background(), posterHTTPClient, plexURL, thumbPath, token)
 	if err != nil {
 		return ""
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return ""
+	if fetched {
+		_, _ = posters.Prune(posters.MaxBytes)
 	}
-
-	// Save to file
-	out, err := os.Create(posterFile)
-	if err != nil {
-		return ""
-	}
-	defer out.Close()
-
-	if _, err := io.Copy(out, resp.Body); err != nil {
-		os.Remove(posterFile)
-		return ""
-	}
-
-	return posterFile
+	return path
 }
 
+// posterHTTPClient bounds poster downloads so a stalled server can't hang
+// the browser's poster pane.
+var posterHTTPClient = &http.Client{Timeout: 20 * time.Second}
+
 // GetUniqueTVShows extracts unique TV show titles from a slice of media items.
 // It only considers items with Type "episode" and a non-empty ParentTitle.
 // Returns an alphabetically sorted slice of unique show names.
@@ -769,6 +935,45 @@ func GetSeasonsForShow(episodes []plex.MediaItem, showName string) []int {
 	return seasons
 }
 
+// SelectSeasonWithPreview is SelectSeason with a preview pane summarizing the
+// highlighted season: its episodes with watched markers and air dates, and
+// the runtime left to watch. episodes may hold other shows' episodes too.
+func SelectSeasonWithPreview(seasons []int, episodes []plex.MediaItem, showName string, fzfPath string) (int, error) {
+	if len(seasons) == 0 {
+		return -1, fmt.Errorf("no seasons to select from")
+	}
+
+	if fzfPath == "" {
+		fzfPath = "fzf"
+	}
+	if _, err := exec.LookPath(fzfPath); err != nil {
+		return -1, fmt.Errorf("fzf not found in PATH. Please install fzf or specify the path in config")
+	}
+
+	labels := make([]string, len(seasons))
+	groups := make([][]plex.MediaItem, len(seasons))
+	for i, s := range seasons {
+		labels[i] = fmt.Sprintf("Season %d", s)
+		if s == 0 {
+			labels[i] = "Specials"
+		}
+		groups[i] = GetEpisodesForSeason(episodes, showName, s)
+	}
+
+	previewScript, err := createSeasonPreviewScript(groups)
+	if err != nil {
+		return -1, fmt.Errorf("failed to create preview script: %w", err)
+	}
+	defer os.Remove(previewScript)
+	defer removePreviewData()
+
+	index, err := selectIndexedWithPreview(labels, fmt.Sprintf("Select season for %s:", showName), fzfPath, previewScript, "")
+	if err != nil {
+		return -1, err
+	}
+	return seasons[index], nil
+}
+
 // GetEpisodesForSeason filters episodes for a specific show and season number.
 // It returns all episodes matching the show name and season (ParentIndex).
 // Use seasonNum=0 to get specials. Returns episodes sorted by episode number (Index).
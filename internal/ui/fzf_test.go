@@ -1,8 +1,13 @@
 package ui
 
 import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
+	"github.com/joshkerr/goplexcli/internal/config"
 	"github.com/joshkerr/goplexcli/internal/plex"
 )
 
@@ -79,6 +84,17 @@ func TestPluralizeItems(t *testing.T) {
 	}
 }
 
+func TestListLabel(t *testing.T) {
+	item := &plex.MediaItem{Title: "Heat", Type: "movie", Year: 1995}
+	if got := ListLabel(item); got != "Heat (1995)" {
+		t.Errorf("ListLabel without a size = %q", got)
+	}
+	item.Size = 3 << 30
+	if got := ListLabel(item); got != "Heat (1995)  3.0 GB" {
+		t.Errorf("ListLabel = %q", got)
+	}
+}
+
 func TestIsAvailable(t *testing.T) {
 	// Test with non-existent binary
 	if IsAvailable("nonexistent-binary-12345") {
@@ -334,3 +350,60 @@ func TestSelectSeason_EmptyList(t *testing.T) {
 		t.Errorf("Expected 'no seasons to select from' error, got: %s", err.Error())
 	}
 }
+
+func TestPreviewTokenStaysOffDisk(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	script, err := createPreviewScript([]plex.MediaItem{{Title: "Heat"}}, "http://plex:32400")
+	if err != nil {
+		t.Fatalf("createPreviewScript: %v", err)
+	}
+	defer RemovePreviewFiles()
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(script), "goplexcli-preview-data.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "token") {
+		t.Errorf("preview data mentions a token: %s", data)
+	}
+
+	env := previewEnv("secret")
+	if !slices.Contains(env, PreviewTokenEnv+"=secret") {
+		t.Errorf("previewEnv lacks %s", PreviewTokenEnv)
+	}
+	if env := previewEnv(""); slices.ContainsFunc(env, func(v string) bool { return strings.HasPrefix(v, PreviewTokenEnv+"=") }) {
+		t.Errorf("previewEnv without a token still sets %s", PreviewTokenEnv)
+	}
+}
+
+func TestSplitExpect(t *testing.T) {
+	tests := []struct {
+		output, action, selected string
+	}{
+		{"\n0\tHeat\n", "", "0\tHeat\n"},
+		{"ctrl-w\n0\tHeat\n2\tThief\n", "watch", "0\tHeat\n2\tThief\n"},
+		{"ctrl-d\n1\tCollateral\n", "download", "1\tCollateral\n"},
+		{"ctrl-q\n1\tCollateral\n", "queue", "1\tCollateral\n"},
+	}
+	for _, tt := range tests {
+		action, selected := splitExpect(tt.output)
+		if action != tt.action || selected != tt.selected {
+			t.Errorf("splitExpect(%q) = %q, %q; want %q, %q", tt.output, action, selected, tt.action, tt.selected)
+		}
+	}
+}
+
+func TestPreviewEnvProfile(t *testing.T) {
+	t.Setenv(config.ProfileEnv, "")
+	if err := config.SetProfile("work"); err != nil {
+		t.Fatal(err)
+	}
+	defer config.SetProfile("")
+
+	env := previewEnv("tok")
+	if !slices.Contains(env, config.ProfileEnv+"=work") {
+		t.Errorf("previewEnv doesn't pass the profile: %v", env)
+	}
+	if !slices.Contains(env, PreviewTokenEnv+"=tok") {
+		t.Errorf("previewEnv doesn't pass the token: %v", env)
+	}
+}
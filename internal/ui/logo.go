@@ -17,31 +17,22 @@ func Logo(version string) {
 		`   ██████   ██████  ██      ███████ ███████ ██   ██  ██████ ███████ ██ `,
 	}
 
-	// Gradient colors from light green to dark green (top to bottom)
-	gradientColors := []lipgloss.Color{
-		lipgloss.Color("#86EFAC"), // Light green
-		lipgloss.Color("#4ADE80"), // Green 400
-		lipgloss.Color("#22C55E"), // Green 500
-		lipgloss.Color("#16A34A"), // Green 600
-		lipgloss.Color("#15803D"), // Green 700
-	}
-
-	// Print logo with gradient
+	// Print logo with the theme's gradient, top to bottom
 	fmt.Println()
 	for i, line := range lines {
 		style := lipgloss.NewStyle().
-			Foreground(gradientColors[i]).
+			Foreground(logoColor(i, len(lines))).
 			Bold(true)
 		fmt.Println(style.Render(line))
 	}
 
 	// Style for the tagline
 	taglineStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6B7280")) // Gray
+		Foreground(theme.Subtle)
 
 	// Style for the version
 	versionStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#4ADE80")). // Green
+		Foreground(theme.Success).
 		Bold(true)
 
 	fmt.Printf("\n  %s %s\n\n",
@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/joshkerr/goplexcli/internal/progress"
+)
+
+// nowPlayingBarWidth is the number of cells in NowPlayingLine's bar.
+const nowPlayingBarWidth = 20
+
+// NowPlayingLine renders a one-line playback status, e.g.
+// "▶ Severance S01E02 - Half Loop  12:34 / 55:00 [████░░░░] 22%", fitted to
+// width columns by shortening the title. durMs 0 (unknown) drops the bar.
+func NowPlayingLine(title string, posMs, durMs int, paused bool, width int) string {
+	icon := stateIcon("playing")
+	if paused {
+		icon = stateIcon("paused")
+	}
+
+	status := progress.FormatDuration(posMs)
+	if durMs > 0 {
+		pct := min(max(posMs*100/durMs, 0), 100)
+		filled := nowPlayingBarWidth * pct / 100
+		status = fmt.Sprintf("%s / %s %s%s %d%%", status, progress.FormatDuration(durMs),
+			strings.Repeat("█", filled), strings.Repeat("░", nowPlayingBarWidth-filled), pct)
+	}
+
+	// icon, a space, the title, two spaces, the status
+	room := width - 4 - len([]rune(status))
+	if room < 1 {
+		return icon + " " + status
+	}
+	return icon + " " + truncate(title, room) + "  " + status
+}
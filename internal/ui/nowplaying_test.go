@@ -0,0 +1,23 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNowPlayingLine(t *testing.T) {
+	line := NowPlayingLine("Severance S01E02 - Half Loop", 754_000, 3_300_000, false, 100)
+	if !strings.HasPrefix(line, "▶ Severance S01E02 - Half Loop  12:34 / 55:00 ") || !strings.HasSuffix(line, " 22%") {
+		t.Errorf("unexpected line %q", line)
+	}
+
+	paused := NowPlayingLine("Heat (1995)", 0, 0, true, 100)
+	if paused != "⏸ Heat (1995)  0:00" {
+		t.Errorf("paused without duration = %q", paused)
+	}
+
+	narrow := NowPlayingLine("A very long title that cannot fit", 60_000, 120_000, false, 50)
+	if n := len([]rune(narrow)); n > 50 || !strings.Contains(narrow, "…") {
+		t.Errorf("line should shorten the title to fit 50 columns: %q (%d)", narrow, n)
+	}
+}
@@ -1,15 +1,20 @@
 package ui
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/joshkerr/goplexcli/internal/errors"
 	"github.com/joshkerr/goplexcli/internal/plex"
 	"github.com/joshkerr/goplexcli/internal/progress"
+	"golang.org/x/term"
 )
 
 // ResumeChoice represents the user's choice for resuming playback.
@@ -189,3 +194,72 @@ func runFzfWithHeader(options []string, fzfPath string, header string) (string,
 
 	return selected, nil
 }
+
+// stdinKeys delivers the bytes typed on stdin to PromptCountdown and
+// TransportControls. One reader serves them all, so a key typed after a
+// prompt ran out or playback ended isn't lost to an abandoned read.
+var (
+	stdinOnce sync.Once
+	stdinKeys chan byte
+)
+
+func readStdinKeys() <-chan byte {
+	stdinOnce.Do(func() {
+		stdinKeys = make(chan byte)
+		go func() {
+			r := bufio.NewReader(os.Stdin)
+			for {
+				b, err := r.ReadByte()
+				if err != nil {
+					close(stdinKeys)
+					return
+				}
+				stdinKeys <- b
+			}
+		}()
+	})
+	return stdinKeys
+}
+
+// PromptCountdown shows prompt with a countdown from d and reports whether
+// to go ahead: on Enter, or when the countdown runs out. Typing anything
+// else (such as "n") and Enter declines. Without an interactive terminal
+// it declines at once.
+func PromptCountdown(prompt string, d time.Duration) bool {
+	if !interactive || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false
+	}
+	return countdown(os.Stdout, readStdinKeys(), prompt, d, time.Second)
+}
+
+// countdown runs PromptCountdown's prompt on w, reading an answer line from
+// keys and redrawing every tick.
+func countdown(w io.Writer, keys <-chan byte, prompt string, d, tick time.Duration) bool {
+	deadline := time.NewTimer(d)
+	defer deadline.Stop()
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	var line strings.Builder
+	for left := d; ; {
+		if line.Len() == 0 {
+			fmt.Fprintf(w, "\r\x1b[K%s %ds ", prompt, int(left.Round(time.Second).Seconds()))
+		}
+		select {
+		case b, ok := <-keys:
+			if !ok {
+				return false
+			}
+			if b != '\n' && b != '\r' {
+				line.WriteByte(b)
+				continue
+			}
+			return strings.TrimSpace(line.String()) == ""
+		case <-deadline.C:
+			fmt.Fprintln(w)
+			return true
+		case <-ticker.C:
+			left -= tick
+		}
+	}
+}
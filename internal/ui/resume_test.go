@@ -1,8 +1,10 @@
 package ui
 
 import (
+	"io"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/joshkerr/goplexcli/internal/plex"
 )
@@ -146,3 +148,27 @@ func TestCountItemsWithProgress(t *testing.T) {
 		})
 	}
 }
+
+func TestCountdown(t *testing.T) {
+	answer := func(line string) bool {
+		keys := make(chan byte, len(line)+1)
+		for _, b := range []byte(line + "\n") {
+			keys <- b
+		}
+		return countdown(io.Discard, keys, "Play next?", time.Minute, time.Minute)
+	}
+	if !answer("") {
+		t.Error("Enter should go ahead")
+	}
+	if answer("n") {
+		t.Error(`"n" should decline`)
+	}
+
+	var out strings.Builder
+	if !countdown(&out, nil, "Play next?", 30*time.Millisecond, 10*time.Millisecond) {
+		t.Error("running out should go ahead")
+	}
+	if !strings.Contains(out.String(), "Play next?") {
+		t.Errorf("prompt not shown: %q", out.String())
+	}
+}
@@ -0,0 +1,204 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+type sessionsMsg struct {
+	sessions []plex.Session
+	err      error
+}
+
+type sessionsTickMsg struct{}
+
+type sessionStoppedMsg struct {
+	title string
+	err   error
+}
+
+// SessionsModel is a live-updating table of a server's playback sessions.
+type SessionsModel struct {
+	server   string
+	fetch    func() ([]plex.Session, error)
+	stop     func(id string) error
+	interval time.Duration
+
+	sessions []plex.Session
+	err      error
+	cursor   int
+	confirm  bool   // asking whether to stop the selected session
+	status   string // outcome of the last stop
+	updated  time.Time
+	once     bool // printing a single snapshot, without the key help
+}
+
+// NewSessionsMonitor creates a table that calls fetch every interval. stop
+// ends a session; x asks to stop the selected one.
+func NewSessionsMonitor(server string, fetch func() ([]plex.Session, error), stop func(id string) error, interval time.Duration) *SessionsModel {
+	return &SessionsModel{server: server, fetch: fetch, stop: stop, interval: interval}
+}
+
+// RunSessionsMonitor shows the table full screen until the user quits.
+// Without an interactive terminal it prints the table once instead.
+func RunSessionsMonitor(m *SessionsModel) error {
+	if !interactive {
+		m.once = true
+		m.Update(m.refresh())
+		fmt.Println(m.View())
+		return m.err
+	}
+	_, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+	return err
+}
+
+func (m *SessionsModel) Init() tea.Cmd {
+	return m.refresh
+}
+
+func (m *SessionsModel) refresh() tea.Msg {
+	sessions, err := m.fetch()
+	return sessionsMsg{sessions, err}
+}
+
+func (m *SessionsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case sessionsMsg:
+		m.sessions, m.err, m.updated = msg.sessions, msg.err, time.Now()
+		m.cursor = max(0, min(m.cursor, len(m.sessions)-1))
+		return m, tea.Tick(m.interval, func(time.Time) tea.Msg { return sessionsTickMsg{} })
+
+	case sessionsTickMsg:
+		return m, m.refresh
+
+	case sessionStoppedMsg:
+		if msg.err != nil {
+			m.status = msg.err.Error()
+		} else {
+			m.status = "Stopped " + msg.title
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.confirm {
+			m.confirm = false
+			if msg.String() == "y" && m.cursor < len(m.sessions) {
+				s := m.sessions[m.cursor]
+				return m, func() tea.Msg { return sessionStoppedMsg{s.Title, m.stop(s.ID)} }
+			}
+			m.status = ""
+			return m, nil
+		}
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.sessions)-1 {
+				m.cursor++
+			}
+		case "x":
+			if len(m.sessions) > 0 {
+				m.confirm = true
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *SessionsModel) View() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Accent)
+	dimStyle := lipgloss.NewStyle().Foreground(theme.Subtle)
+	selectedStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Accent).Background(theme.Highlight)
+	warnStyle := lipgloss.NewStyle().Foreground(theme.Warning)
+	errStyle := lipgloss.NewStyle().Foreground(theme.Error)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Now Playing on " + m.server))
+	if !m.updated.IsZero() {
+		b.WriteString(dimStyle.Render("  updated " + m.updated.Format("15:04:05")))
+	}
+	b.WriteString("\n\n")
+
+	switch {
+	case m.err != nil:
+		b.WriteString(errStyle.Render(m.err.Error()))
+		b.WriteString("\n")
+	case m.updated.IsZero():
+		b.WriteString(dimStyle.Render("Loading..."))
+		b.WriteString("\n")
+	case len(m.sessions) == 0:
+		b.WriteString(dimStyle.Render("Nobody is watching."))
+		b.WriteString("\n")
+	default:
+		b.WriteString(dimStyle.Render(sessionRow("USER", "TITLE", "PLAYER", "PROGRESS", "STREAM", "BANDWIDTH")))
+		b.WriteString("\n")
+		for i, s := range m.sessions {
+			line := sessionRow(s.User, s.Title, s.Player, fmt.Sprintf("%3d%% %s", s.Progress(), stateIcon(s.State)), s.Decision, sessionBandwidth(s))
+			if i == m.cursor {
+				line = selectedStyle.Render(line)
+			}
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+	}
+
+	if m.once {
+		return strings.TrimRight(b.String(), "\n")
+	}
+	b.WriteString("\n")
+	switch {
+	case m.confirm && m.cursor < len(m.sessions):
+		b.WriteString(warnStyle.Render(fmt.Sprintf("Stop %s for %s? [y/N]", m.sessions[m.cursor].Title, m.sessions[m.cursor].User)))
+	case m.status != "":
+		b.WriteString(warnStyle.Render(m.status))
+	default:
+		b.WriteString(dimStyle.Render("↑/↓ select • x stop session • q quit"))
+	}
+	return b.String()
+}
+
+// sessionRow lays out one row of the sessions table, truncating long
+// values to their column.
+func sessionRow(user, title, player, progress, stream, bandwidth string) string {
+	return fmt.Sprintf("%-12s %-40s %-18s %-9s %-13s %s",
+		truncate(user, 12), truncate(title, 40), truncate(player, 18), progress, stream, bandwidth)
+}
+
+func sessionBandwidth(s plex.Session) string {
+	bw := plex.FormatBitrate(s.Bandwidth)
+	if bw == "" {
+		bw = "-"
+	}
+	if s.Local {
+		return bw + " (LAN)"
+	}
+	return bw
+}
+
+func stateIcon(state string) string {
+	switch state {
+	case "paused":
+		return "⏸"
+	case "buffering":
+		return "…"
+	}
+	return "▶"
+}
+
+// truncate shortens s to at most n runes, marking the cut with "…".
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
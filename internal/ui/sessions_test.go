@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshkerr/goplexcli/internal/plex"
+)
+
+func TestSessionsMonitor(t *testing.T) {
+	sessions := []plex.Session{
+		{ID: "s1", User: "alice", Title: "Heat (1995)", State: "playing", Duration: 100, ViewOffset: 40, Decision: "transcode", Bandwidth: 8000},
+		{ID: "s2", User: "bob", Title: "Severance - S01E01 - Good News About Hell", State: "paused", Decision: "direct play", Local: true},
+	}
+	var stopped string
+	m := NewSessionsMonitor("Home", func() ([]plex.Session, error) { return sessions, nil },
+		func(id string) error { stopped = id; return nil }, time.Second)
+
+	m.Update(m.Init()())
+	view := m.View()
+	for _, want := range []string{"Now Playing on Home", "alice", " 40% ▶", "transcode", "8.0 Mbps", "(LAN)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	key := func(s string) tea.Msg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	m.Update(key("j"))
+	m.Update(key("x"))
+	if !strings.Contains(m.View(), "Stop Severance") {
+		t.Fatalf("x should ask to stop the selected session:\n%s", m.View())
+	}
+	_, cmd := m.Update(key("y"))
+	if cmd == nil {
+		t.Fatal("confirming should stop the session")
+	}
+	m.Update(cmd())
+	if stopped != "s2" || !strings.Contains(m.View(), "Stopped Severance") {
+		t.Errorf("stopped %q, view:\n%s", stopped, m.View())
+	}
+
+	// Sessions ending moves the cursor back onto the list.
+	sessions = sessions[:1]
+	m.Update(m.refresh())
+	if m.cursor != 0 {
+		t.Errorf("cursor = %d after the list shrank", m.cursor)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("Severance", 5); got != "Seve…" {
+		t.Errorf("truncate = %q", got)
+	}
+	if got := truncate("Heat", 5); got != "Heat" {
+		t.Errorf("truncate = %q", got)
+	}
+}
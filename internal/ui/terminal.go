@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"golang.org/x/term"
+)
+
+// interactive reports whether stdout is a capable terminal, so full-screen
+// UIs, fzf pickers and in-place progress lines can be used. It is decided
+// once, by ConfigureTerminal.
+var interactive = true
+
+// ConfigureTerminal adapts output to where it is going. Colors and other
+// styling are turned off with noColor (--no-color), a non-empty NO_COLOR
+// (https://no-color.org), TERM=dumb, or a stdout that isn't a terminal.
+// The last two also make the session non-interactive: fzf is treated as
+// unavailable, so pickers fall back to numbered prompts, and progress UIs
+// print no animation, keeping cron jobs and logs free of escape codes.
+func ConfigureTerminal(noColor bool) {
+	dumb := os.Getenv("TERM") == "dumb" || !term.IsTerminal(int(os.Stdout.Fd()))
+	if noColor || os.Getenv("NO_COLOR") != "" || dumb {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+	interactive = !dumb
+}
+
+// Interactive reports whether stdout is an interactive terminal (see
+// ConfigureTerminal).
+func Interactive() bool { return interactive }
+
+// TeaOptions returns the options Bubble Tea programs should run with: none on
+// an interactive terminal, otherwise no renderer and no keyboard input, so a
+// progress UI still tracks its transfers but draws nothing.
+func TeaOptions() []tea.ProgramOption {
+	if interactive {
+		return nil
+	}
+	return []tea.ProgramOption{tea.WithoutRenderer(), tea.WithInput(nil)}
+}
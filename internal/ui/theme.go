@@ -0,0 +1,177 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is the palette the CLI, the TUI browser and the logo draw with. Each
+// field is a color role rather than a specific color, so themes for light
+// and dark terminals can share one set of styles.
+type Theme struct {
+	Accent    lipgloss.Color // titles, the selected item, key hints
+	Success   lipgloss.Color
+	Error     lipgloss.Color
+	Info      lipgloss.Color
+	Warning   lipgloss.Color // warnings and ratings
+	Text      lipgloss.Color // regular text in the TUI
+	Muted     lipgloss.Color // secondary text such as summaries
+	Subtle    lipgloss.Color // labels, hints and the logo tagline
+	Border    lipgloss.Color // pane borders
+	Divider   lipgloss.Color // separator lines
+	Highlight lipgloss.Color // background of the selected row
+	Header    lipgloss.Color // background of the TUI header
+	Logo      []lipgloss.Color
+}
+
+// themes are the built-in themes. "dark" is the default and the original
+// goplexcli palette.
+var themes = map[string]Theme{
+	"dark": {
+		Accent: "#C084FC", Success: "#4ADE80", Error: "#F87171", Info: "#60A5FA", Warning: "#FBBF24",
+		Text: "#E5E7EB", Muted: "#9CA3AF", Subtle: "#6B7280",
+		Border: "#4B5563", Divider: "#374151", Highlight: "#2D2D35", Header: "#1F1F23",
+		Logo: []lipgloss.Color{"#86EFAC", "#4ADE80", "#22C55E", "#16A34A", "#15803D"},
+	},
+	"light": {
+		Accent: "#7C3AED", Success: "#15803D", Error: "#B91C1C", Info: "#1D4ED8", Warning: "#B45309",
+		Text: "#1F2937", Muted: "#4B5563", Subtle: "#6B7280",
+		Border: "#9CA3AF", Divider: "#D1D5DB", Highlight: "#EDE9FE", Header: "#F3F4F6",
+		Logo: []lipgloss.Color{"#22C55E", "#16A34A", "#15803D", "#166534", "#14532D"},
+	},
+	"solarized": {
+		Accent: "#D33682", Success: "#859900", Error: "#DC322F", Info: "#268BD2", Warning: "#B58900",
+		Text: "#93A1A1", Muted: "#839496", Subtle: "#657B83",
+		Border: "#586E75", Divider: "#073642", Highlight: "#073642", Header: "#002B36",
+		Logo: []lipgloss.Color{"#2AA198", "#268BD2", "#6C71C4", "#D33682", "#CB4B16"},
+	},
+	"dracula": {
+		Accent: "#BD93F9", Success: "#50FA7B", Error: "#FF5555", Info: "#8BE9FD", Warning: "#F1FA8C",
+		Text: "#F8F8F2", Muted: "#BFBFBF", Subtle: "#6272A4",
+		Border: "#6272A4", Divider: "#44475A", Highlight: "#44475A", Header: "#282A36",
+		Logo: []lipgloss.Color{"#50FA7B", "#8BE9FD", "#BD93F9", "#FF79C6", "#FFB86C"},
+	},
+}
+
+// DefaultTheme is the theme used when none is configured.
+const DefaultTheme = "dark"
+
+// theme is the active theme.
+var theme = themes[DefaultTheme]
+
+// CurrentTheme returns the active theme.
+func CurrentTheme() Theme { return theme }
+
+// ThemeNames lists the built-in themes.
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// themeRoles maps the role names custom themes use to Theme fields.
+var themeRoles = map[string]func(*Theme) *lipgloss.Color{
+	"accent":    func(t *Theme) *lipgloss.Color { return &t.Accent },
+	"success":   func(t *Theme) *lipgloss.Color { return &t.Success },
+	"error":     func(t *Theme) *lipgloss.Color { return &t.Error },
+	"info":      func(t *Theme) *lipgloss.Color { return &t.Info },
+	"warning":   func(t *Theme) *lipgloss.Color { return &t.Warning },
+	"text":      func(t *Theme) *lipgloss.Color { return &t.Text },
+	"muted":     func(t *Theme) *lipgloss.Color { return &t.Muted },
+	"subtle":    func(t *Theme) *lipgloss.Color { return &t.Subtle },
+	"border":    func(t *Theme) *lipgloss.Color { return &t.Border },
+	"divider":   func(t *Theme) *lipgloss.Color { return &t.Divider },
+	"highlight": func(t *Theme) *lipgloss.Color { return &t.Highlight },
+	"header":    func(t *Theme) *lipgloss.Color { return &t.Header },
+}
+
+// SetTheme activates the theme called name: a built-in one, or one defined
+// in custom. A custom theme maps role names ("accent", "success", "error",
+// "info", "warning", "text", "muted", "subtle", "border", "divider",
+// "highlight", "header") to colors, and "logo" to a comma-separated list of
+// colors for the logo's gradient, top to bottom. Roles it leaves out come
+// from the built-in theme named by its "base" key, or from the default.
+// Colors are hex ("#C084FC", "#FFF") or ANSI numbers ("205"). An empty name
+// selects the default theme.
+func SetTheme(name string, custom map[string]map[string]string) error {
+	if name == "" {
+		name = DefaultTheme
+	}
+	palette, ok := custom[name]
+	if !ok {
+		t, ok := themes[strings.ToLower(name)]
+		if !ok {
+			return fmt.Errorf("unknown theme %q (built-in themes: %s)", name, strings.Join(ThemeNames(), ", "))
+		}
+		theme = t
+		return nil
+	}
+
+	base := palette["base"]
+	if base == "" {
+		base = DefaultTheme
+	}
+	t, ok := themes[strings.ToLower(base)]
+	if !ok {
+		return fmt.Errorf("theme %q: unknown base theme %q", name, base)
+	}
+	t.Logo = append([]lipgloss.Color(nil), t.Logo...)
+	for role, value := range palette {
+		role = strings.ToLower(strings.TrimSpace(role))
+		switch role {
+		case "base":
+			continue
+		case "logo":
+			var logo []lipgloss.Color
+			for _, c := range strings.Split(value, ",") {
+				c = strings.TrimSpace(c)
+				if !validColor(c) {
+					return fmt.Errorf("theme %q: invalid logo color %q", name, c)
+				}
+				logo = append(logo, lipgloss.Color(c))
+			}
+			t.Logo = logo
+			continue
+		}
+		field, ok := themeRoles[role]
+		if !ok {
+			return fmt.Errorf("theme %q: unknown color role %q", name, role)
+		}
+		value = strings.TrimSpace(value)
+		if !validColor(value) {
+			return fmt.Errorf("theme %q: invalid %s color %q", name, role, value)
+		}
+		*field(&t) = lipgloss.Color(value)
+	}
+	theme = t
+	return nil
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9A-Fa-f]{3}|[0-9A-Fa-f]{6})$`)
+
+// validColor reports whether c is a color lipgloss understands: hex, or an
+// ANSI color number.
+func validColor(c string) bool {
+	if hexColor.MatchString(c) {
+		return true
+	}
+	n, err := strconv.Atoi(c)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// logoColor returns the gradient color for line i of a logo with n lines,
+// stretching or sampling the theme's gradient to fit.
+func logoColor(i, n int) lipgloss.Color {
+	if len(theme.Logo) == 0 {
+		return theme.Success
+	}
+	return theme.Logo[i*len(theme.Logo)/n]
+}
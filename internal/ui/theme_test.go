@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestSetTheme(t *testing.T) {
+	t.Cleanup(func() { _ = SetTheme("", nil) })
+
+	if err := SetTheme("Dracula", nil); err != nil {
+		t.Fatal(err)
+	}
+	if CurrentTheme().Accent != "#BD93F9" {
+		t.Errorf("dracula accent = %s", CurrentTheme().Accent)
+	}
+
+	custom := map[string]map[string]string{
+		"mine": {"base": "light", "accent": "#ff0", "logo": "#111111, 202"},
+	}
+	if err := SetTheme("mine", custom); err != nil {
+		t.Fatal(err)
+	}
+	got := CurrentTheme()
+	if got.Accent != "#ff0" || got.Error != themes["light"].Error {
+		t.Errorf("custom theme = %+v, want light with a yellow accent", got)
+	}
+	if len(got.Logo) != 2 || logoColor(0, 5) != "#111111" || logoColor(4, 5) != lipgloss.Color("202") {
+		t.Errorf("logo gradient = %v", got.Logo)
+	}
+
+	for name, palette := range map[string]map[string]string{
+		"nope":     nil,
+		"badbase":  {"base": "neon"},
+		"badrole":  {"sparkle": "#fff"},
+		"badcolor": {"accent": "purple"},
+		"badlogo":  {"logo": "#fff,,#000"},
+	} {
+		custom := map[string]map[string]string{}
+		if palette != nil {
+			custom[name] = palette
+		}
+		if err := SetTheme(name, custom); err == nil {
+			t.Errorf("SetTheme(%q) should fail", name)
+		}
+	}
+
+	if err := SetTheme("", nil); err != nil || CurrentTheme().Accent != themes[DefaultTheme].Accent {
+		t.Errorf("empty name should select the default theme (err %v)", err)
+	}
+}
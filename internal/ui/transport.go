@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// Transport is the player control TransportControls drives; the mpv IPC
+// client implements it.
+type Transport interface {
+	GetPaused() (bool, error)
+	SetPaused(paused bool) error
+	SeekRelative(seconds float64) error
+	PlaylistNext() error
+	Quit() error
+}
+
+// TransportHelp describes the keys TransportControls handles.
+const TransportHelp = "space pause · ←/→ seek 10s · ↑/↓ seek 1m · n next · q stop"
+
+// Transport key actions, as returned by keyParser.feed.
+const (
+	keyPause = "pause"
+	keyNext  = "next"
+	keyQuit  = "quit"
+)
+
+// seekKeys maps arrow keys (the final byte of their escape sequence) to
+// seek offsets in seconds.
+var seekKeys = map[byte]float64{'C': 10, 'D': -10, 'A': 60, 'B': -60}
+
+// keyParser turns raw terminal bytes into transport actions, following
+// arrow-key escape sequences (ESC [ A-D) across calls.
+type keyParser struct {
+	state int // 0 plain, 1 after ESC, 2 after ESC [
+}
+
+// feed consumes one byte and returns the action it completes (keyPause,
+// keyNext, keyQuit or "") or, for an arrow key, the seek offset in seconds.
+func (p *keyParser) feed(b byte) (action string, seek float64) {
+	switch p.state {
+	case 1:
+		p.state = 0
+		if b == '[' || b == 'O' {
+			p.state = 2
+		}
+		return "", 0
+	case 2:
+		p.state = 0
+		return "", seekKeys[b]
+	}
+
+	switch b {
+	case 0x1b:
+		p.state = 1
+	case ' ', 'p':
+		return keyPause, 0
+	case 'n':
+		return keyNext, 0
+	case 'q', 0x03: // Ctrl-C arrives as a byte in raw mode
+		return keyQuit, 0
+	}
+	return "", 0
+}
+
+// TransportControls lets the terminal drive t until ctx is done: space
+// pauses, arrows seek, n skips and q stops. The terminal is put in raw mode
+// for single keypresses and restored on return. Without an interactive
+// terminal it just waits for ctx.
+func TransportControls(ctx context.Context, t Transport) {
+	fd := int(os.Stdin.Fd())
+	if !interactive || !term.IsTerminal(fd) {
+		<-ctx.Done()
+		return
+	}
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		<-ctx.Done()
+		return
+	}
+	defer func() { _ = term.Restore(fd, oldState) }()
+
+	keys := readStdinKeys()
+	var p keyParser
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case b, ok := <-keys:
+			if !ok {
+				<-ctx.Done()
+				return
+			}
+			runTransportKey(t, &p, b)
+		}
+	}
+}
+
+// runTransportKey feeds b to p and carries out the resulting action on t.
+func runTransportKey(t Transport, p *keyParser, b byte) {
+	action, seek := p.feed(b)
+	var err error
+	switch {
+	case seek != 0:
+		err = t.SeekRelative(seek)
+	case action == keyPause:
+		var paused bool
+		if paused, err = t.GetPaused(); err == nil {
+			err = t.SetPaused(!paused)
+		}
+	case action == keyNext:
+		err = t.PlaylistNext()
+	case action == keyQuit:
+		err = t.Quit()
+	}
+	if err != nil {
+		// Raw mode needs the explicit carriage return.
+		fmt.Fprintf(os.Stderr, "\r\n%v\r\n", err)
+	}
+}
@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+)
+
+// fakeTransport records what TransportControls asked of it.
+type fakeTransport struct {
+	paused bool
+	calls  []string
+	seeks  []float64
+}
+
+func (f *fakeTransport) GetPaused() (bool, error) { return f.paused, nil }
+
+func (f *fakeTransport) SetPaused(paused bool) error {
+	f.paused = paused
+	f.calls = append(f.calls, "pause")
+	return nil
+}
+
+func (f *fakeTransport) SeekRelative(seconds float64) error {
+	f.seeks = append(f.seeks, seconds)
+	return nil
+}
+
+func (f *fakeTransport) PlaylistNext() error {
+	f.calls = append(f.calls, "next")
+	return nil
+}
+
+func (f *fakeTransport) Quit() error {
+	f.calls = append(f.calls, "quit")
+	return nil
+}
+
+func TestRunTransportKey(t *testing.T) {
+	f := &fakeTransport{}
+	var p keyParser
+	// space, right, left, up, down, x (ignored), n, q
+	for _, b := range []byte(" \x1b[C\x1b[D\x1bOA\x1b[Bxnq") {
+		runTransportKey(f, &p, b)
+	}
+
+	if !f.paused {
+		t.Error("space should pause")
+	}
+	if want := []float64{10, -10, 60, -60}; !slices.Equal(f.seeks, want) {
+		t.Errorf("seeks = %v, want %v", f.seeks, want)
+	}
+	if want := []string{"pause", "next", "quit"}; !slices.Equal(f.calls, want) {
+		t.Errorf("calls = %v, want %v", f.calls, want)
+	}
+}
@@ -0,0 +1,169 @@
+// Package usage keeps opt-in, local-only usage statistics: how often each
+// command is run and how long it takes. The numbers live in a small JSON file
+// next to the media cache and are only ever read by 'goplexcli stats usage';
+// nothing is transmitted anywhere.
+package usage
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/joshkerr/goplexcli/internal/config"
+	"github.com/joshkerr/goplexcli/internal/storage"
+)
+
+// maxSamples bounds how many recent runtimes are kept per command. The median
+// of the last few dozen runs is representative and keeps the file tiny.
+const maxSamples = 50
+
+// Command is the recorded usage of a single command path (e.g. "queue download").
+type Command struct {
+	Count    int     `json:"count"`
+	LastUsed int64   `json:"last_used"`  // unix seconds
+	Samples  []int64 `json:"samples_ms"` // most recent runtimes, oldest first
+}
+
+// Median returns the median of the recorded runtimes, or 0 with no samples.
+func (c *Command) Median() time.Duration {
+	if len(c.Samples) == 0 {
+		return 0
+	}
+	sorted := append([]int64(nil), c.Samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	mid := len(sorted) / 2
+	ms := sorted[mid]
+	if len(sorted)%2 == 0 {
+		ms = (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// Stats is the full usage file, keyed by command path.
+type Stats struct {
+	Since    int64               `json:"since"` // unix seconds of the first record
+	Commands map[string]*Command `json:"commands"`
+}
+
+// Entry pairs a command name with its usage, for sorted listings.
+type Entry struct {
+	Name string
+	*Command
+}
+
+// Sorted returns the commands ordered by use count, most used first.
+func (s *Stats) Sorted() []Entry {
+	entries := make([]Entry, 0, len(s.Commands))
+	for name, c := range s.Commands {
+		entries = append(entries, Entry{Name: name, Command: c})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+// Record adds one run of name taking d.
+func (s *Stats) Record(name string, d time.Duration, now time.Time) {
+	if s.Commands == nil {
+		s.Commands = map[string]*Command{}
+	}
+	if s.Since == 0 {
+		s.Since = now.Unix()
+	}
+	c := s.Commands[name]
+	if c == nil {
+		c = &Command{}
+		s.Commands[name] = c
+	}
+	c.Count++
+	c.LastUsed = now.Unix()
+	c.Samples = append(c.Samples, d.Milliseconds())
+	if len(c.Samples) > maxSamples {
+		c.Samples = c.Samples[len(c.Samples)-maxSamples:]
+	}
+}
+
+// Path returns the JSON file holding the statistics, alongside the media cache.
+func Path() (string, error) {
+	dir, err := config.GetCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "usage.json"), nil
+}
+
+// file returns the statistics stored at path.
+func file(path string) storage.File[Stats] {
+	return storage.File[Stats]{Path: path, Name: "usage stats", Indent: true}
+}
+
+// LoadFrom reads the statistics at path. A missing file yields empty stats.
+func LoadFrom(path string) (*Stats, error) {
+	s, _, err := file(path).Load()
+	if err != nil {
+		return nil, err
+	}
+	if s.Commands == nil {
+		s.Commands = map[string]*Command{}
+	}
+	return s, nil
+}
+
+// SaveTo writes the statistics to path atomically, so a concurrent reader
+// never sees a half-written file.
+func (s *Stats) SaveTo(path string) error {
+	return file(path).Save(s)
+}
+
+// Record adds one run of name to the default statistics file, under its
+// lock so concurrent commands don't lose each other's runs. A corrupt file
+// is replaced rather than failing every command forever.
+func Record(name string, d time.Duration) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	f := file(path)
+	record := func(s *Stats) error {
+		s.Record(name, d, time.Now())
+		return nil
+	}
+	if err := f.Update(record); !isCorrupt(err) {
+		return err
+	}
+	// Start over, checking again under the lock in case another command
+	// already has.
+	return f.Lock(true, func() error {
+		s, _, err := f.Load()
+		if isCorrupt(err) {
+			s, err = &Stats{}, nil
+		}
+		if err != nil {
+			return err
+		}
+		s.Record(name, d, time.Now())
+		return f.Save(s)
+	})
+}
+
+// isCorrupt reports whether err means the statistics couldn't be decoded.
+func isCorrupt(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return errors.As(err, &syntaxErr) || errors.As(err, &typeErr)
+}
+
+// Reset deletes the default statistics file.
+func Reset() error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	return file(path).Remove()
+}
@@ -0,0 +1,128 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMedian(t *testing.T) {
+	tests := []struct {
+		samples []int64
+		want    time.Duration
+	}{
+		{nil, 0},
+		{[]int64{300}, 300 * time.Millisecond},
+		{[]int64{900, 100, 500}, 500 * time.Millisecond},
+		{[]int64{400, 100, 200, 300}, 250 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		c := &Command{Samples: tt.samples}
+		if got := c.Median(); got != tt.want {
+			t.Errorf("Median(%v) = %v, want %v", tt.samples, got, tt.want)
+		}
+	}
+}
+
+func TestRecordCapsSamples(t *testing.T) {
+	s := &Stats{}
+	now := time.Unix(1700000000, 0)
+	for i := 0; i < maxSamples+10; i++ {
+		s.Record("browse", time.Duration(i)*time.Millisecond, now)
+	}
+	c := s.Commands["browse"]
+	if c.Count != maxSamples+10 {
+		t.Errorf("Count = %d, want %d", c.Count, maxSamples+10)
+	}
+	if len(c.Samples) != maxSamples {
+		t.Fatalf("kept %d samples, want %d", len(c.Samples), maxSamples)
+	}
+	if c.Samples[0] != 10 {
+		t.Errorf("oldest kept sample = %d, want 10 (oldest runs dropped first)", c.Samples[0])
+	}
+	if s.Since != now.Unix() {
+		t.Errorf("Since = %d, want %d", s.Since, now.Unix())
+	}
+}
+
+func TestSortedByCount(t *testing.T) {
+	s := &Stats{}
+	now := time.Now()
+	s.Record("cache update", time.Second, now)
+	s.Record("browse", time.Second, now)
+	s.Record("browse", time.Second, now)
+	s.Record("alpha", time.Second, now)
+
+	got := s.Sorted()
+	want := []string{"browse", "alpha", "cache update"}
+	for i, name := range want {
+		if got[i].Name != name {
+			t.Errorf("Sorted()[%d] = %q, want %q", i, got[i].Name, name)
+		}
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "usage.json")
+
+	empty, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom(missing) error = %v", err)
+	}
+	if len(empty.Commands) != 0 {
+		t.Errorf("missing file should load empty, got %v", empty.Commands)
+	}
+
+	s := &Stats{}
+	s.Record("queue download", 90*time.Second, time.Now())
+	if err := s.SaveTo(path); err != nil {
+		t.Fatalf("SaveTo() error = %v", err)
+	}
+	loaded, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom() error = %v", err)
+	}
+	c := loaded.Commands["queue download"]
+	if c == nil || c.Count != 1 || c.Median() != 90*time.Second {
+		t.Errorf("round trip lost data: %+v", c)
+	}
+}
+
+func TestRecordConcurrentAndCorrupt(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("APPDATA", tmp)
+	t.Setenv("XDG_CONFIG_HOME", tmp)
+	t.Setenv("HOME", tmp)
+	path, err := Path()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := Record("browse", time.Second); err != nil {
+				t.Errorf("Record: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	s, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+	if c := s.Commands["browse"]; c == nil || c.Count != 8 {
+		t.Errorf("browse = %+v, want 8 runs recorded", c)
+	}
+}
@@ -0,0 +1,34 @@
+#!/bin/sh
+# Compare cache benchmarks between a base git ref and the working tree, so a
+# change to the cache format or lookup code can be judged by numbers rather
+# than feel. Checks the base ref out into a throwaway worktree, runs the same
+# benchmarks in both, and prints a benchstat comparison.
+#
+# Usage: bench-compare.sh [BASE_REF] [COUNT]
+#   BASE_REF  git ref to compare against (default: HEAD)
+#   COUNT     -count passed to go test; benchstat wants >= 6 (default: 6)
+set -e
+
+BASE="${1:-HEAD}"
+COUNT="${2:-6}"
+PKGS="./internal/cache/..."
+BENCH="."
+GO="${GO:-go}"
+
+OUT="$(mktemp -d)"
+WT="$OUT/base"
+cleanup() {
+  git worktree remove --force "$WT" >/dev/null 2>&1 || true
+  rm -rf "$OUT"
+}
+trap cleanup EXIT INT TERM
+
+git worktree add --detach "$WT" "$BASE" >/dev/null
+
+echo "Benchmarking base ($BASE)..."
+(cd "$WT" && $GO test -run '^$' -bench "$BENCH" -benchmem -count "$COUNT" $PKGS) > "$OUT/old.txt"
+
+echo "Benchmarking working tree..."
+$GO test -run '^$' -bench "$BENCH" -benchmem -count "$COUNT" $PKGS > "$OUT/new.txt"
+
+$GO run golang.org/x/perf/cmd/benchstat@latest "$OUT/old.txt" "$OUT/new.txt"